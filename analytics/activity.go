@@ -0,0 +1,184 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// runActivityRollups rolls up record observations per domain and UTC day
+// into record_activity_daily, and new subdomains per apex and week into
+// subdomain_activity_weekly, reading every shard. Each run recomputes from
+// the latest day already rolled up, which may have been partial, through
+// today, or the last analytics.activity_backfill_days on the first run; the
+// weeks holding those days are recomputed too. Rows older than
+// analytics.activity_retention_days are deleted.
+//
+// Only the latest observation of a record is stored, so a day's observed
+// count is final once the job has run after the day ends; run it at least
+// daily.
+func runActivityRollups(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var latest sql.NullTime
+	if err := db.QueryRow("SELECT MAX(day) FROM record_activity_daily").Scan(&latest); err != nil {
+		return fmt.Errorf("failed to read the latest rolled-up day: %v", err)
+	}
+	from := today.AddDate(0, 0, -cfg.Analytics.ActivityBackfillDays)
+	if latest.Valid && latest.Time.After(from) {
+		from = latest.Time.UTC()
+	}
+	week := startOfWeek(from)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM record_activity_daily WHERE day >= $1", from); err != nil {
+		return fmt.Errorf("failed to clear days from %s: %v", from.Format("2006-01-02"), err)
+	}
+	if _, err := tx.Exec("DELETE FROM subdomain_activity_weekly WHERE week >= $1", week); err != nil {
+		return fmt.Errorf("failed to clear weeks from %s: %v", week.Format("2006-01-02"), err)
+	}
+	now := time.Now().UTC()
+	var days, weeks int64
+	for _, shard := range shards.Shards() {
+		n, err := copyDailyActivity(shard.DB, tx, from, now)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		days += n
+		if n, err = copyWeeklySubdomains(shard.DB, tx, week, now); err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		weeks += n
+	}
+	cutoff := today.AddDate(0, 0, -cfg.Analytics.ActivityRetentionDays)
+	if _, err := tx.Exec("DELETE FROM record_activity_daily WHERE day < $1", cutoff); err != nil {
+		return fmt.Errorf("failed to delete days before %s: %v", cutoff.Format("2006-01-02"), err)
+	}
+	if _, err := tx.Exec("DELETE FROM subdomain_activity_weekly WHERE week < $1", startOfWeek(cutoff)); err != nil {
+		return fmt.Errorf("failed to delete weeks before %s: %v", cutoff.Format("2006-01-02"), err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	fmt.Printf("Stored %d domain days since %s and %d apex weeks since %s\n", days, from.Format("2006-01-02"), weeks, week.Format("2006-01-02"))
+	return nil
+}
+
+// copyDailyActivity copies into record_activity_daily the records of a
+// shard's domains observed, first seen or replaced on each day since from:
+// a record counts as observed on the day of its latest observation, and a
+// version it replaced on the day that version was last seen.
+func copyDailyActivity(shard *sql.DB, tx *sql.Tx, from, now time.Time) (int64, error) {
+	rows, err := shard.Query(`
+		SELECT d.domain_name, a.day, SUM(a.observed), SUM(a.added), SUM(a.changed)
+		FROM (
+			SELECT domain_id, last_updated::date AS day, 1 AS observed, 0 AS added, 0 AS changed FROM dns_records WHERE last_updated >= $1
+			UNION ALL
+			SELECT domain_id, first_seen::date, 0, 1, 0 FROM dns_records WHERE first_seen >= $1
+			UNION ALL
+			SELECT domain_id, last_seen::date, 1, 0, 0 FROM record_history WHERE last_seen >= $1
+			UNION ALL
+			SELECT domain_id, replaced_at::date, 0, 0, 1 FROM record_history WHERE replaced_at >= $1
+		) a
+		JOIN domains d ON d.id = a.domain_id
+		GROUP BY d.domain_name, a.day
+	`, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count record activity: %v", err)
+	}
+	defer rows.Close()
+	stmt, err := tx.Prepare(pq.CopyIn("record_activity_daily", "domain_name", "day", "observed", "added", "changed", "computed_at"))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	var n int64
+	for rows.Next() {
+		var domain string
+		var day time.Time
+		var observed, added, changed int64
+		if err := rows.Scan(&domain, &day, &observed, &added, &changed); err != nil {
+			return 0, fmt.Errorf("failed to scan record activity: %v", err)
+		}
+		if _, err := stmt.Exec(domain, day, observed, added, changed, now); err != nil {
+			return 0, fmt.Errorf("failed to copy record activity of %s: %v", domain, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate record activity: %v", err)
+	}
+	if _, err := stmt.Exec(); err != nil {
+		return 0, fmt.Errorf("failed to copy record activity: %v", err)
+	}
+	return n, nil
+}
+
+// copyWeeklySubdomains copies into subdomain_activity_weekly the public
+// domains of a shard first seen in each week since week, counted under
+// their apex: the label left of their public suffix. Apexes themselves are
+// not counted.
+func copyWeeklySubdomains(shard *sql.DB, tx *sql.Tx, week, now time.Time) (int64, error) {
+	rows, err := shard.Query(`
+		SELECT apex, week, COUNT(*)
+		FROM (
+			SELECT domain_name, date_trunc('week', first_seen)::date AS week,
+				array_to_string((string_to_array(domain_name, '.'))[
+					cardinality(string_to_array(domain_name, '.')) - cardinality(string_to_array(public_suffix, '.')):], '.') AS apex
+			FROM domains
+			WHERE first_seen >= $1 AND restricted_to IS NULL
+		) s
+		WHERE domain_name <> apex
+		GROUP BY apex, week
+	`, week)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count new subdomains: %v", err)
+	}
+	defer rows.Close()
+	stmt, err := tx.Prepare(pq.CopyIn("subdomain_activity_weekly", "apex", "week", "new_subdomains", "computed_at"))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	var n int64
+	for rows.Next() {
+		var apex string
+		var start time.Time
+		var count int64
+		if err := rows.Scan(&apex, &start, &count); err != nil {
+			return 0, fmt.Errorf("failed to scan new subdomains: %v", err)
+		}
+		if _, err := stmt.Exec(apex, start, count, now); err != nil {
+			return 0, fmt.Errorf("failed to copy new subdomains of %s: %v", apex, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate new subdomains: %v", err)
+	}
+	if _, err := stmt.Exec(); err != nil {
+		return 0, fmt.Errorf("failed to copy new subdomains: %v", err)
+	}
+	return n, nil
+}
+
+// startOfWeek returns the Monday starting the ISO week of day, as
+// date_trunc('week') does.
+func startOfWeek(day time.Time) time.Time {
+	day = day.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
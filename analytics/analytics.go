@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/moos3/bell/config"
+)
+
+// jobs maps -job names to the aggregate jobs they run.
+var jobs = map[string]func(db *sql.DB, cfg *config.Config) error{
+	"top-n": runTopN,
+	"iana-registrars": func(db *sql.DB, cfg *config.Config) error {
+		return runIANARegistrars(db, cfg.RDAP.RegistrarRegistryURL)
+	},
+	"iana-tlds": func(db *sql.DB, cfg *config.Config) error {
+		return runIANATLDs(db, cfg.Coverage.TLDListURL)
+	},
+	"record-priorities": func(db *sql.DB, cfg *config.Config) error {
+		return runRecordPriorities(db)
+	},
+	"billing-export": func(db *sql.DB, cfg *config.Config) error {
+		return runBillingExport(db, cfg.Billing.ExportDir, cfg.Billing.Formats)
+	},
+	"keyword-trends":    runKeywordTrends,
+	"dnssec-adoption":   runDNSSECAdoption,
+	"normalize-records": runNormalizeRecords,
+	"compress-records":  runCompressRecords,
+	"create-indexes":    runCreateIndexes,
+	"domain-clusters":   runDomainClusters,
+	"activity-rollups":  runActivityRollups,
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	job := flag.String("job", "top-n", "Aggregate job to run (top-n, iana-registrars, iana-tlds, record-priorities, billing-export, keyword-trends, dnssec-adoption, normalize-records, compress-records, create-indexes, domain-clusters, activity-rollups)")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	run, ok := jobs[*job]
+	if !ok {
+		log.Fatalf("Unknown job %q", *job)
+	}
+
+	// Connect to AlloyDB
+	connStr := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode,
+	)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatal("Failed to connect to AlloyDB: ", err)
+	}
+	fmt.Println("Connected to AlloyDB successfully.")
+
+	// Run once, or on a schedule when an interval is configured
+	interval := time.Duration(config.Analytics.IntervalMinutes) * time.Minute
+	for {
+		start := time.Now()
+		if err := run(db, config); err != nil {
+			log.Printf("Error running %s job: %v", *job, err)
+		} else {
+			fmt.Printf("Completed %s job in %v\n", *job, time.Since(start).Round(time.Second))
+		}
+		if interval <= 0 {
+			break
+		}
+		time.Sleep(interval)
+	}
+}
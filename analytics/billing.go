@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/moos3/bell/logging"
+)
+
+var billingMonth = flag.String("month", "", "Month exported by billing-export (YYYY-MM); defaults to the previous month")
+
+// usageRow is one API key's requests and returned records for an RPC and
+// TLD over a month. Keys are exported as their logging.KeyID, never in full.
+type usageRow struct {
+	KeyID        string `json:"-"`
+	Description  string `json:"-"`
+	Organization string `json:"-"`
+	TLD          string `json:"tld"` // Empty for RPCs not scoped to a domain or TLD
+	RPC          string `json:"rpc"`
+	Requests     int64  `json:"requests"`
+	Errors       int64  `json:"errors"`
+	Records      int64  `json:"records"`
+}
+
+// keyUsage is one API key's usage in the JSON export.
+type keyUsage struct {
+	KeyID        string     `json:"key_id"` // logging.KeyID of the key, as in the logs
+	Description  string     `json:"description"`
+	Organization string     `json:"organization"` // Empty for keys outside any organization
+	Requests     int64      `json:"requests"`
+	Records      int64      `json:"records"`
+	Usage        []usageRow `json:"usage"`
+}
+
+// runBillingExport writes one month of usage_counts, per API key broken down
+// by TLD and RPC, to <dir>/<YYYY-MM>/usage.<format>. Re-running
+// for a month replaces its files, so the job can run on a schedule.
+func runBillingExport(db *sql.DB, dir string, formats []string) error {
+	if dir == "" {
+		return fmt.Errorf("billing.export_dir is not set")
+	}
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	if *billingMonth != "" {
+		var err error
+		if start, err = time.Parse("2006-01", *billingMonth); err != nil {
+			return fmt.Errorf("invalid -month %q; must be YYYY-MM", *billingMonth)
+		}
+	}
+	label := start.Format("2006-01")
+
+	rows, err := db.Query(`
+		SELECT u.api_key, COALESCE(k.description, ''), COALESCE(o.name, ''), u.tld, u.rpc, SUM(u.requests), SUM(u.errors), SUM(u.records)
+		FROM usage_counts u
+		LEFT JOIN api_keys k ON k.api_key::text = u.api_key
+		LEFT JOIN organizations o ON o.id = k.organization_id
+		WHERE u.day >= $1 AND u.day < $2
+		GROUP BY u.api_key, k.description, o.name, u.tld, u.rpc
+		ORDER BY u.api_key, u.tld, u.rpc
+	`, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return fmt.Errorf("failed to query usage: %v", err)
+	}
+	defer rows.Close()
+	var usage []usageRow
+	for rows.Next() {
+		var r usageRow
+		var apiKey string
+		if err := rows.Scan(&apiKey, &r.Description, &r.Organization, &r.TLD, &r.RPC, &r.Requests, &r.Errors, &r.Records); err != nil {
+			return fmt.Errorf("failed to scan usage: %v", err)
+		}
+		r.KeyID = logging.KeyID(apiKey)
+		usage = append(usage, r)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate usage: %v", err)
+	}
+
+	outDir := filepath.Join(dir, label)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory %s: %v", outDir, err)
+	}
+	for _, format := range formats {
+		var body []byte
+		switch format {
+		case "csv":
+			body, err = usageCSV(label, usage)
+		case "json":
+			body, err = usageJSON(label, usage)
+		default:
+			return fmt.Errorf("unsupported billing export format %q", format)
+		}
+		if err != nil {
+			return err
+		}
+		// Write then rename so readers of the bucket never see a partial file
+		name := filepath.Join(outDir, "usage."+format)
+		if err := os.WriteFile(name+".tmp", body, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+		if err := os.Rename(name+".tmp", name); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+	fmt.Printf("Exported %d usage rows for %s to %s\n", len(usage), label, outDir)
+	return nil
+}
+
+func usageCSV(month string, usage []usageRow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"month", "key_id", "description", "organization", "tld", "rpc", "requests", "errors", "records"})
+	for _, r := range usage {
+		w.Write([]string{month, r.KeyID, r.Description, r.Organization, r.TLD, r.RPC,
+			strconv.FormatInt(r.Requests, 10), strconv.FormatInt(r.Errors, 10), strconv.FormatInt(r.Records, 10)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode usage CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func usageJSON(month string, usage []usageRow) ([]byte, error) {
+	export := struct {
+		Month       string      `json:"month"`
+		GeneratedAt string      `json:"generated_at"`
+		Keys        []*keyUsage `json:"keys"`
+	}{Month: month, GeneratedAt: time.Now().UTC().Format(time.RFC3339), Keys: []*keyUsage{}}
+	var key *keyUsage
+	for _, r := range usage {
+		if key == nil || key.KeyID != r.KeyID {
+			key = &keyUsage{KeyID: r.KeyID, Description: r.Description, Organization: r.Organization}
+			export.Keys = append(export.Keys, key)
+		}
+		key.Requests += r.Requests
+		key.Records += r.Records
+		key.Usage = append(key.Usage, r)
+	}
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode usage JSON: %v", err)
+	}
+	return body, nil
+}
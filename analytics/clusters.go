@@ -0,0 +1,191 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// clusterDomain is a domain read by runDomainClusters with its
+// infrastructure.
+type clusterDomain struct {
+	name        string
+	firstSeen   time.Time
+	nameservers []string // Sorted
+	addresses   []string // Sorted
+}
+
+// domainCluster is a group of domains sharing infrastructure, ordered by
+// first_seen.
+type domainCluster struct {
+	id          string
+	nameservers []string
+	addresses   []string
+	domains     []clusterDomain
+}
+
+// runDomainClusters groups the public domains first seen in the last
+// analytics.cluster_lookback_days on every shard by nameserver set and A
+// records, splits each group wherever consecutive domains were first seen
+// more than analytics.cluster_window_hours apart, and replaces
+// domain_clusters with the groups of between cluster_min_size and
+// cluster_max_size domains. Domains restricted to organizations are left
+// out, so clusters never name them.
+func runDomainClusters(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	since := time.Now().UTC().AddDate(0, 0, -cfg.Analytics.ClusterLookbackDays)
+	var domains []clusterDomain
+	for _, shard := range shards.Shards() {
+		read, err := readClusterDomains(shard.DB, since)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		domains = append(domains, read...)
+	}
+	window := time.Duration(cfg.Analytics.ClusterWindowHours) * time.Hour
+	clusters := clusterDomains(domains, window, cfg.Analytics.ClusterMinSize, cfg.Analytics.ClusterMaxSize)
+	if err := storeDomainClusters(db, clusters); err != nil {
+		return fmt.Errorf("failed to store clusters: %v", err)
+	}
+	clustered := 0
+	for _, c := range clusters {
+		clustered += len(c.domains)
+	}
+	fmt.Printf("Stored %d clusters of %d domains out of %d first seen since %s\n", len(clusters), clustered, len(domains), since.Format("2006-01-02"))
+	return nil
+}
+
+// readClusterDomains returns the public domains of a shard first seen since
+// since that have both nameservers and A records.
+func readClusterDomains(db *sql.DB, since time.Time) ([]clusterDomain, error) {
+	rows, err := db.Query(`
+		SELECT d.domain_name, d.first_seen, d.nameservers, array_agg(DISTINCT r.record_data)
+		FROM domains d
+		JOIN dns_records r ON r.domain_id = d.id AND r.record_type = 'A'
+		WHERE d.first_seen >= $1 AND d.restricted_to IS NULL AND cardinality(d.nameservers) > 0
+		GROUP BY d.id
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var domains []clusterDomain
+	for rows.Next() {
+		var d clusterDomain
+		if err := rows.Scan(&d.name, &d.firstSeen, pq.Array(&d.nameservers), pq.Array(&d.addresses)); err != nil {
+			return nil, fmt.Errorf("failed to scan domain: %v", err)
+		}
+		for i, ns := range d.nameservers {
+			d.nameservers[i] = strings.ToLower(strings.TrimSuffix(ns, "."))
+		}
+		slices.Sort(d.nameservers)
+		d.nameservers = slices.Compact(d.nameservers)
+		slices.Sort(d.addresses)
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+// clusterDomains groups domains with the same nameservers and addresses,
+// splits each group where first_seen jumps by more than window, and
+// returns the parts of between minSize and maxSize domains, largest first.
+func clusterDomains(domains []clusterDomain, window time.Duration, minSize, maxSize int) []domainCluster {
+	groups := make(map[string][]clusterDomain)
+	for _, d := range domains {
+		key := strings.Join(d.nameservers, ",") + "|" + strings.Join(d.addresses, ",")
+		groups[key] = append(groups[key], d)
+	}
+	var clusters []domainCluster
+	for _, group := range groups {
+		if len(group) < minSize {
+			continue
+		}
+		slices.SortFunc(group, func(a, b clusterDomain) int {
+			if c := a.firstSeen.Compare(b.firstSeen); c != 0 {
+				return c
+			}
+			return strings.Compare(a.name, b.name)
+		})
+		start := 0
+		for i := 1; i <= len(group); i++ {
+			if i < len(group) && group[i].firstSeen.Sub(group[i-1].firstSeen) <= window {
+				continue
+			}
+			if part := group[start:i]; len(part) >= minSize && len(part) <= maxSize {
+				clusters = append(clusters, domainCluster{
+					id:          clusterID(part[0]),
+					nameservers: part[0].nameservers,
+					addresses:   part[0].addresses,
+					domains:     part,
+				})
+			}
+			start = i
+		}
+	}
+	slices.SortFunc(clusters, func(a, b domainCluster) int {
+		if len(a.domains) != len(b.domains) {
+			return len(b.domains) - len(a.domains)
+		}
+		return strings.Compare(a.id, b.id)
+	})
+	return clusters
+}
+
+// clusterID identifies the cluster first seen with domain, which stays its
+// first domain on later runs unless it leaves the lookback period.
+func clusterID(first clusterDomain) string {
+	sum := sha256.Sum256([]byte(strings.Join(first.nameservers, ",") + "|" + strings.Join(first.addresses, ",") + "|" + first.name))
+	return hex.EncodeToString(sum[:8])
+}
+
+// storeDomainClusters replaces the stored clusters in one transaction.
+func storeDomainClusters(db *sql.DB, clusters []domainCluster) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM domain_clusters"); err != nil {
+		return err
+	}
+	insertCluster, err := tx.Prepare(`
+		INSERT INTO domain_clusters (cluster_id, nameservers, addresses, domain_count, first_seen_from, first_seen_to, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insertCluster.Close()
+	insertMember, err := tx.Prepare("INSERT INTO domain_cluster_members (cluster_id, domain_name, first_seen) VALUES ($1, $2, $3)")
+	if err != nil {
+		return err
+	}
+	defer insertMember.Close()
+
+	now := time.Now().UTC()
+	for _, c := range clusters {
+		last := c.domains[len(c.domains)-1]
+		if _, err := insertCluster.Exec(c.id, pq.Array(c.nameservers), pq.Array(c.addresses), len(c.domains), c.domains[0].firstSeen, last.firstSeen, now); err != nil {
+			return fmt.Errorf("failed to insert cluster %s: %v", c.id, err)
+		}
+		for _, d := range c.domains {
+			if _, err := insertMember.Exec(c.id, d.name, d.firstSeen); err != nil {
+				return fmt.Errorf("failed to insert domain %s of cluster %s: %v", d.name, c.id, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// compressBatchSize is the number of records rewritten per transaction by
+// runCompressRecords.
+const compressBatchSize = 5000
+
+// runCompressRecords brings the TXT records of every shard in line with
+// record_data.compress_min_bytes: records stored plain before compression
+// was enabled, or by writers that do not compress (czds, whose zones rarely
+// hold TXT records), are compressed, records compressed with an older codec
+// are compressed again with storage.RecordDataCodec, and with compression disabled (0)
+// compressed records are stored plain again. Rows keep their last_updated,
+// so no history is recorded for them. It then prints the size of the TXT
+// records of each shard.
+func runCompressRecords(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	minBytes := cfg.RecordData.CompressMinBytes
+	for _, shard := range shards.Shards() {
+		var rewritten, before, after int64
+		if minBytes > 0 {
+			rewritten, before, after, err = compressShardRecords(shard.DB, minBytes)
+		} else {
+			rewritten, before, after, err = decompressShardRecords(shard.DB)
+		}
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		if minBytes > 0 {
+			fmt.Printf("Compressed %d TXT records on shard %s: %d bytes to %d\n", rewritten, shard.Name, before, after)
+		} else {
+			fmt.Printf("Decompressed %d TXT records on shard %s: %d bytes to %d\n", rewritten, shard.Name, before, after)
+		}
+		if err := printRecordSizes(shard); err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+	}
+	return nil
+}
+
+// storedRecord is a record rewritten by runCompressRecords.
+type storedRecord struct {
+	id   int64
+	data string
+	z    []byte
+}
+
+// compressShardRecords compresses the plain TXT records of at least
+// minBytes and recompresses those stored with an older codec, returning how
+// many it rewrote and their size before and after.
+func compressShardRecords(db *sql.DB, minBytes int) (rewritten, before, after int64, err error) {
+	var lastID int64
+	for {
+		rows, err := db.Query(`
+			SELECT id, record_data, record_data_z FROM dns_records
+			WHERE record_type = 'TXT' AND id > $1
+				AND (record_data_z IS NULL AND octet_length(record_data) >= $2
+					OR get_byte(record_data_z, 0) <> $4)
+			ORDER BY id
+			LIMIT $3
+		`, lastID, minBytes, compressBatchSize, int(storage.RecordDataCodec))
+		if err != nil {
+			return rewritten, before, after, fmt.Errorf("failed to query records: %v", err)
+		}
+		var records []storedRecord
+		scanned := 0
+		for rows.Next() {
+			var r storedRecord
+			if err := rows.Scan(&r.id, &r.data, &r.z); err != nil {
+				rows.Close()
+				return rewritten, before, after, fmt.Errorf("failed to scan record: %v", err)
+			}
+			lastID = r.id
+			scanned++
+			size := int64(len(r.data))
+			recompress := r.z != nil
+			if recompress {
+				size = int64(len(r.z))
+				if r.data, err = storage.RecordData(r.data, r.z); err != nil {
+					rows.Close()
+					return rewritten, before, after, fmt.Errorf("record %d: %v", r.id, err)
+				}
+			}
+			r.data, r.z = storage.EncodeRecordData("TXT", r.data, minBytes)
+			if r.z == nil && !recompress {
+				// Would not shrink
+				continue
+			}
+			before += size
+			after += int64(len(r.data) + len(r.z))
+			records = append(records, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rewritten, before, after, fmt.Errorf("failed to iterate records: %v", err)
+		}
+		if err := storeTXTRecords(db, records); err != nil {
+			return rewritten, before, after, err
+		}
+		rewritten += int64(len(records))
+		if scanned < compressBatchSize {
+			return rewritten, before, after, nil
+		}
+	}
+}
+
+// decompressShardRecords stores the compressed TXT records plain again,
+// returning how many it rewrote and their size before and after.
+func decompressShardRecords(db *sql.DB) (rewritten, before, after int64, err error) {
+	var lastID int64
+	for {
+		rows, err := db.Query(`
+			SELECT id, record_data, record_data_z FROM dns_records
+			WHERE record_type = 'TXT' AND id > $1 AND record_data_z IS NOT NULL
+			ORDER BY id
+			LIMIT $2
+		`, lastID, compressBatchSize)
+		if err != nil {
+			return rewritten, before, after, fmt.Errorf("failed to query records: %v", err)
+		}
+		var records []storedRecord
+		for rows.Next() {
+			var r storedRecord
+			if err := rows.Scan(&r.id, &r.data, &r.z); err != nil {
+				rows.Close()
+				return rewritten, before, after, fmt.Errorf("failed to scan record: %v", err)
+			}
+			lastID = r.id
+			before += int64(len(r.z))
+			if r.data, err = storage.RecordData(r.data, r.z); err != nil {
+				rows.Close()
+				return rewritten, before, after, fmt.Errorf("record %d: %v", r.id, err)
+			}
+			r.z = nil
+			after += int64(len(r.data))
+			records = append(records, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rewritten, before, after, fmt.Errorf("failed to iterate records: %v", err)
+		}
+		if err := storeTXTRecords(db, records); err != nil {
+			return rewritten, before, after, err
+		}
+		rewritten += int64(len(records))
+		if len(records) < compressBatchSize {
+			return rewritten, before, after, nil
+		}
+	}
+}
+
+// storeTXTRecords rewrites the record_data and record_data_z of TXT records.
+func storeTXTRecords(db *sql.DB, records []storedRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range records {
+		if _, err := tx.Exec(`
+			UPDATE dns_records SET record_data = $2, record_data_z = $3
+			WHERE id = $1 AND record_type = 'TXT'
+		`, r.id, r.data, r.z); err != nil {
+			return fmt.Errorf("failed to rewrite record %d: %v", r.id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// printRecordSizes prints the number and stored size of the plain and
+// compressed TXT records of a shard, and the size of their partition.
+func printRecordSizes(shard *storage.Shard) error {
+	var plain, compressed, plainBytes, compressedBytes, partitionBytes int64
+	if err := shard.DB.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE record_data_z IS NULL), COUNT(*) FILTER (WHERE record_data_z IS NOT NULL),
+			COALESCE(SUM(octet_length(record_data)), 0), COALESCE(SUM(octet_length(record_data_z)), 0),
+			pg_total_relation_size('dns_records_txt')
+		FROM dns_records
+		WHERE record_type = 'TXT'
+	`).Scan(&plain, &compressed, &plainBytes, &compressedBytes, &partitionBytes); err != nil {
+		return fmt.Errorf("failed to measure TXT records: %v", err)
+	}
+	fmt.Printf("Shard %s: %d plain TXT records (%d bytes), %d compressed (%d bytes); dns_records_txt is %d bytes\n",
+		shard.Name, plain, plainBytes, compressed, compressedBytes, partitionBytes)
+	return nil
+}
@@ -0,0 +1,273 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// algorithmKey identifies an algorithm inventory row. keySize is 0 for
+// algorithms only known from DS records.
+type algorithmKey struct {
+	algorithm uint8
+	keySize   int
+}
+
+// dnssecCounts holds the adoption counts of one TLD (or globalTLD).
+type dnssecCounts struct {
+	delegations int64
+	signed      int64
+	algorithms  map[algorithmKey]int64 // Domains using each algorithm and key size
+}
+
+// runDNSSECAdoption counts, per TLD and across all TLDs, the delegated
+// domains (those with nameservers), how many of them have DS records, and
+// the algorithms and key sizes in use, and replaces today's (UTC) rows in
+// dnssec_adoption and dnssec_algorithms. Earlier days are kept, so daily
+// runs build the time series GetDNSSECAdoption returns.
+//
+// Key sizes come from DNSKEY records resolved by the query worker; domains
+// with DS records but no stored DNSKEY count under their DS algorithms with
+// an unknown (0) key size.
+func runDNSSECAdoption(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+
+	counts := make(map[string]*dnssecCounts)
+	var mu sync.Mutex
+	if _, err := shards.FanOut(context.Background(), func(ctx context.Context, shard *storage.Shard) error {
+		shardCounts, err := countDNSSEC(shard.DB)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for tld, c := range shardCounts {
+			total := dnssecCountsFor(counts, tld)
+			total.delegations += c.delegations
+			total.signed += c.signed
+			for key, domains := range c.algorithms {
+				total.algorithms[key] += domains
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	if err := storeDNSSECAdoption(db, day, counts); err != nil {
+		return fmt.Errorf("failed to store DNSSEC adoption: %v", err)
+	}
+	global := dnssecCountsFor(counts, globalTLD)
+	fmt.Printf("Stored DNSSEC adoption for %d TLDs: %d of %d delegations signed\n", len(counts)-1, global.signed, global.delegations)
+	return nil
+}
+
+// dnssecCountsFor returns the counts of tld in counts, adding them if absent.
+func dnssecCountsFor(counts map[string]*dnssecCounts, tld string) *dnssecCounts {
+	c, ok := counts[tld]
+	if !ok {
+		c = &dnssecCounts{algorithms: make(map[algorithmKey]int64)}
+		counts[tld] = c
+	}
+	return c
+}
+
+// countDNSSEC returns the adoption counts of the domains stored in db.
+func countDNSSEC(db *sql.DB) (map[string]*dnssecCounts, error) {
+	counts := make(map[string]*dnssecCounts)
+	get := func(tld string) *dnssecCounts { return dnssecCountsFor(counts, tld) }
+
+	rows, err := db.Query(`
+		SELECT tld, COUNT(*) FILTER (WHERE cardinality(nameservers) > 0),
+			COUNT(*) FILTER (WHERE cardinality(nameservers) > 0 AND EXISTS (
+				SELECT 1 FROM dns_records r WHERE r.domain_id = d.id AND r.record_type = 'DS'
+			))
+		FROM domains d
+		GROUP BY tld
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count signed delegations: %v", err)
+	}
+	for rows.Next() {
+		var tld string
+		var delegations, signed int64
+		if err := rows.Scan(&tld, &delegations, &signed); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan delegation counts: %v", err)
+		}
+		for _, t := range []string{tld, globalTLD} {
+			c := get(t)
+			c.delegations += delegations
+			c.signed += signed
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate delegation counts: %v", err)
+	}
+
+	if err := countAlgorithms(db, get); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// countAlgorithms adds the algorithms and key sizes of each domain's DNSKEY
+// records, or of its DS records if it has no DNSKEY, counting every
+// combination once per domain.
+func countAlgorithms(db *sql.DB, get func(tld string) *dnssecCounts) error {
+	rows, err := db.Query(`
+		SELECT d.tld, r.domain_id, r.record_type, r.record_data
+		FROM dns_records r
+		JOIN domains d ON d.id = r.domain_id
+		WHERE r.record_type IN ('DNSKEY', 'DS')
+		ORDER BY r.domain_id
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to query DNSSEC records: %v", err)
+	}
+	defer rows.Close()
+
+	domainID, domainTLD := -1, ""
+	var fromKeys, fromDS map[algorithmKey]bool
+	finish := func() {
+		inUse := fromKeys
+		if len(inUse) == 0 {
+			inUse = fromDS
+		}
+		for key := range inUse {
+			for _, t := range []string{domainTLD, globalTLD} {
+				get(t).algorithms[key]++
+			}
+		}
+	}
+	for rows.Next() {
+		var tld, recordType, data string
+		var id int
+		if err := rows.Scan(&tld, &id, &recordType, &data); err != nil {
+			return fmt.Errorf("failed to scan DNSSEC record: %v", err)
+		}
+		if id != domainID {
+			finish()
+			domainID, domainTLD = id, tld
+			fromKeys, fromDS = make(map[algorithmKey]bool), make(map[algorithmKey]bool)
+		}
+		rr, err := dns.NewRR(data)
+		if err != nil || rr == nil {
+			continue
+		}
+		switch r := rr.(type) {
+		case *dns.DNSKEY:
+			fromKeys[algorithmKey{r.Algorithm, keySize(r)}] = true
+		case *dns.DS:
+			fromDS[algorithmKey{r.Algorithm, 0}] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate DNSSEC records: %v", err)
+	}
+	finish()
+	return nil
+}
+
+// keySize returns the size in bits of a DNSKEY's public key, or 0 if the
+// algorithm is unknown or the key does not decode.
+func keySize(k *dns.DNSKEY) int {
+	switch k.Algorithm {
+	case dns.ECDSAP256SHA256, dns.ED25519:
+		return 256
+	case dns.ECDSAP384SHA384:
+		return 384
+	case dns.ED448:
+		return 456
+	}
+	key, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil || len(key) == 0 {
+		return 0
+	}
+	switch k.Algorithm {
+	case dns.RSAMD5, dns.RSASHA1, dns.RSASHA1NSEC3SHA1, dns.RSASHA256, dns.RSASHA512:
+		// RFC 3110: exponent length, exponent, modulus
+		expLen, offset := int(key[0]), 1
+		if expLen == 0 {
+			if len(key) < 3 {
+				return 0
+			}
+			expLen, offset = int(key[1])<<8|int(key[2]), 3
+		}
+		modulus := key[min(offset+expLen, len(key)):]
+		for len(modulus) > 0 && modulus[0] == 0 {
+			modulus = modulus[1:]
+		}
+		if len(modulus) == 0 {
+			return 0
+		}
+		bits := len(modulus) * 8
+		for b := modulus[0]; b&0x80 == 0; b <<= 1 {
+			bits--
+		}
+		return bits
+	case dns.DSA, dns.DSANSEC3SHA1:
+		// RFC 2536: T parameter gives the prime size
+		return 512 + 64*int(key[0])
+	}
+	return 0
+}
+
+// storeDNSSECAdoption replaces a day's adoption and algorithm rows in one
+// transaction.
+func storeDNSSECAdoption(db *sql.DB, day time.Time, counts map[string]*dnssecCounts) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM dnssec_adoption WHERE day = $1", day); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM dnssec_algorithms WHERE day = $1", day); err != nil {
+		return err
+	}
+	adoptionStmt, err := tx.Prepare(`
+		INSERT INTO dnssec_adoption (day, tld, delegations, signed, computed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return err
+	}
+	defer adoptionStmt.Close()
+	algorithmStmt, err := tx.Prepare(`
+		INSERT INTO dnssec_algorithms (day, tld, algorithm, key_size, domain_count)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return err
+	}
+	defer algorithmStmt.Close()
+
+	now := time.Now().UTC()
+	for tld, c := range counts {
+		if _, err := adoptionStmt.Exec(day, tld, c.delegations, c.signed, now); err != nil {
+			return fmt.Errorf("failed to insert adoption for TLD %q: %v", tld, err)
+		}
+		for key, domains := range c.algorithms {
+			if _, err := algorithmStmt.Exec(day, tld, key.algorithm, key.keySize, domains); err != nil {
+				return fmt.Errorf("failed to insert algorithm %d for TLD %q: %v", key.algorithm, tld, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
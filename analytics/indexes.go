@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// domainIndex is an index of schema.sql on domains that API and worker hot
+// paths rely on (see server/indexes.go).
+type domainIndex struct {
+	name    string
+	columns string // What follows "ON domains" in its definition
+	marker  string // Text an up-to-date definition contains; empty if any definition under the name is
+}
+
+// domainIndexes are the indexes runCreateIndexes builds.
+var domainIndexes = []domainIndex{
+	{"idx_domains_domain_name", "(domain_name) INCLUDE (id)", "INCLUDE (id)"},
+	{"idx_domains_domain_name_pattern", "(domain_name text_pattern_ops)", ""},
+	{"idx_domains_query_due", "(last_updated, id) WHERE nameservers <> '{}'", ""},
+	{"idx_domains_nameservers", "USING GIN (nameservers)", ""},
+	{"idx_domains_reverse_name", `((reverse(domain_name) COLLATE "C"))`, ""},
+	{"idx_domains_domain_name_trgm", "USING GIN (domain_name gin_trgm_ops)", ""},
+	{"idx_domains_tld_domain_name", "(tld, domain_name)", ""},
+	{"idx_domains_public_suffix_domain_name", "(public_suffix, domain_name)", ""},
+}
+
+// runCreateIndexes creates the domainIndexes a shard lacks, on every shard,
+// with CREATE INDEX CONCURRENTLY so that writers are not blocked. An index
+// left invalid by an interrupted build is built again, and one with an
+// outdated definition is replaced by building the new one under another
+// name, then swapping them. Indexes already up to date are left alone, so
+// the job can be rerun; the server warns at startup about any missing.
+func runCreateIndexes(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	for _, shard := range shards.Shards() {
+		if _, err := shard.DB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm"); err != nil {
+			return fmt.Errorf("shard %s: failed to create pg_trgm extension: %v", shard.Name, err)
+		}
+		created := 0
+		for _, idx := range domainIndexes {
+			built, err := createDomainIndex(shard.DB, idx)
+			if err != nil {
+				return fmt.Errorf("shard %s: %v", shard.Name, err)
+			}
+			if built {
+				created++
+			}
+		}
+		fmt.Printf("Created %d of %d domains indexes on shard %s\n", created, len(domainIndexes), shard.Name)
+	}
+	return nil
+}
+
+// createDomainIndex builds idx unless it exists, is valid and is up to
+// date, reporting whether it did.
+func createDomainIndex(db *sql.DB, idx domainIndex) (bool, error) {
+	var valid bool
+	var definition string
+	err := db.QueryRow(`
+		SELECT i.indisvalid, pg_get_indexdef(i.indexrelid)
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE c.relname = $1
+	`, idx.name).Scan(&valid, &definition)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to look up index %s: %v", idx.name, err)
+	}
+	exists := err == nil
+	switch {
+	case exists && valid && strings.Contains(definition, idx.marker):
+		return false, nil
+	case exists && valid:
+		// Build the new definition alongside the old one, which keeps
+		// serving queries until it is dropped
+		replacement := idx.name + "_new"
+		if err := buildIndex(db, replacement, idx.columns); err != nil {
+			return false, err
+		}
+		for _, stmt := range []string{
+			"DROP INDEX CONCURRENTLY IF EXISTS " + idx.name,
+			fmt.Sprintf("ALTER INDEX %s RENAME TO %s", replacement, idx.name),
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				return false, fmt.Errorf("failed to replace index %s: %v", idx.name, err)
+			}
+		}
+		fmt.Printf("Replaced index %s: %s\n", idx.name, definition)
+		return true, nil
+	case exists:
+		fmt.Printf("Rebuilding invalid index %s\n", idx.name)
+	}
+	if err := buildIndex(db, idx.name, idx.columns); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// buildIndex creates the index name on domains, first dropping an invalid
+// index of that name left by an interrupted build.
+func buildIndex(db *sql.DB, name, columns string) error {
+	var invalid bool
+	err := db.QueryRow(`
+		SELECT NOT i.indisvalid FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid WHERE c.relname = $1
+	`, name).Scan(&invalid)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up index %s: %v", name, err)
+	}
+	if invalid {
+		if _, err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + name); err != nil {
+			return fmt.Errorf("failed to drop invalid index %s: %v", name, err)
+		}
+	}
+	fmt.Printf("Creating index %s\n", name)
+	if _, err := db.Exec(fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON domains %s", name, columns)); err != nil {
+		return fmt.Errorf("failed to create index %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// minKeywordLength drops tokens too short to mean anything (e.g. "my", "24").
+const minKeywordLength = 3
+
+// keywordTokens splits a domain label into keywords: the runs of letters
+// between hyphens and digits (login-secure-24 gives login and secure), plus
+// any watched keyword appearing inside it (paypallogin gives login).
+// Each keyword is returned once.
+func keywordTokens(label string, watched []string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	add := func(token string) {
+		if len(token) >= minKeywordLength && len(token) <= 63 && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	// Punycode labels tokenize into noise
+	if !strings.HasPrefix(label, "xn--") {
+		for _, token := range strings.FieldsFunc(label, func(r rune) bool { return !unicode.IsLetter(r) }) {
+			add(token)
+		}
+	}
+	for _, keyword := range watched {
+		if strings.Contains(label, keyword) {
+			add(keyword)
+		}
+	}
+	return tokens
+}
+
+// runKeywordTrends counts keywords in the labels of domains first seen
+// yesterday and today (UTC), per TLD and across all TLDs, and replaces those
+// days in keyword_trends. Earlier days are left as computed, so the table
+// builds up the history GetKeywordTrends compares against. TLDs whose zone
+// was first ingested on or after a day are left out of it, since their
+// domains were first seen then for being ingested, not registered.
+func runKeywordTrends(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	watched := make([]string, len(cfg.Analytics.Keywords))
+	for i, keyword := range cfg.Analytics.Keywords {
+		watched[i] = strings.ToLower(keyword)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		start := time.Now()
+		newZones, err := zonesFirstIngestedSince(db, day)
+		if err != nil {
+			return fmt.Errorf("failed to query new zones for %s: %v", day.Format("2006-01-02"), err)
+		}
+		counts, domains, err := countShardKeywords(shards, day, watched, newZones)
+		if err != nil {
+			return fmt.Errorf("failed to count keywords for %s: %v", day.Format("2006-01-02"), err)
+		}
+		stored, err := storeKeywordTrends(db, day, counts, cfg.Analytics.KeywordMinCount)
+		if err != nil {
+			return fmt.Errorf("failed to store keywords for %s: %v", day.Format("2006-01-02"), err)
+		}
+		fmt.Printf("Stored %d keywords from %d new domains for %s in %v\n", stored, domains, day.Format("2006-01-02"), time.Since(start).Round(time.Second))
+	}
+	return nil
+}
+
+// zonesFirstIngestedSince returns the TLDs whose first successful zone
+// ingest ended at or after since, or which are being ingested for the first
+// time.
+func zonesFirstIngestedSince(db *sql.DB, since time.Time) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT tld FROM processed_tlds
+		WHERE first_processed >= $1 OR last_processed IS NULL
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tlds []string
+	for rows.Next() {
+		var tld string
+		if err := rows.Scan(&tld); err != nil {
+			return nil, err
+		}
+		tlds = append(tlds, tld)
+	}
+	return tlds, rows.Err()
+}
+
+// countShardKeywords runs countKeywords on every shard and sums the counts.
+func countShardKeywords(shards *storage.Router, day time.Time, watched, excluded []string) (map[string]map[string]int, int, error) {
+	counts := make(map[string]map[string]int)
+	domains := 0
+	var mu sync.Mutex
+	_, err := shards.FanOut(context.Background(), func(ctx context.Context, shard *storage.Shard) error {
+		shardCounts, shardDomains, err := countKeywords(shard.DB, day, watched, excluded)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		domains += shardDomains
+		for tld, keywords := range shardCounts {
+			if counts[tld] == nil {
+				counts[tld] = make(map[string]int)
+			}
+			for keyword, count := range keywords {
+				counts[tld][keyword] += count
+			}
+		}
+		return nil
+	})
+	return counts, domains, err
+}
+
+// countKeywords returns domain counts per TLD and keyword (globalTLD for all
+// TLDs) for domains first seen on day outside the excluded TLDs, and the
+// number of domains read.
+func countKeywords(db *sql.DB, day time.Time, watched, excluded []string) (map[string]map[string]int, int, error) {
+	rows, err := db.Query(`
+		SELECT domain_name, tld
+		FROM domains
+		WHERE first_seen >= $1 AND first_seen < $2 AND tld <> ALL($3)
+	`, day, day.AddDate(0, 0, 1), pq.Array(excluded))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]map[string]int)
+	domains := 0
+	for rows.Next() {
+		var domain, tld string
+		if err := rows.Scan(&domain, &tld); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan domain: %v", err)
+		}
+		domains++
+		label := strings.ToLower(strings.TrimSuffix(domain, "."+tld))
+		for _, keyword := range keywordTokens(label, watched) {
+			for _, t := range []string{tld, globalTLD} {
+				if counts[t] == nil {
+					counts[t] = make(map[string]int)
+				}
+				counts[t][keyword]++
+			}
+		}
+	}
+	return counts, domains, rows.Err()
+}
+
+// storeKeywordTrends replaces a day's rows in one transaction, keeping
+// keywords seen in at least minCount domains.
+func storeKeywordTrends(db *sql.DB, day time.Time, counts map[string]map[string]int, minCount int) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM keyword_trends WHERE day = $1", day); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO keyword_trends (day, tld, keyword, domain_count, computed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	stored := 0
+	for tld, keywords := range counts {
+		for keyword, count := range keywords {
+			if count < minCount {
+				continue
+			}
+			if _, err := stmt.Exec(day, tld, keyword, count, now); err != nil {
+				tx.Rollback()
+				return 0, fmt.Errorf("failed to insert keyword %s: %v", keyword, err)
+			}
+			stored++
+		}
+	}
+	return stored, tx.Commit()
+}
@@ -0,0 +1,215 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+// normalizeBatchSize is the number of records read, or domains merged, per
+// batch by runNormalizeRecords.
+const normalizeBatchSize = 10000
+
+// runNormalizeRecords migrates dns_records to its natural key on every
+// shard that does not have dns_records_natural_key yet. It adds the columns of
+// the key (see addNaturalKeyColumns), normalizes the record_data of records
+// written before (see recordset.Normalize), fills canonical_hash, dates
+// first_seen from last_updated, merges the rows of each domain that share a
+// key into the latest one, summing their observations, and then enforces
+// the key (see enforceNaturalKey). Records are visited in id order, then
+// domains in id order, and the job can be stopped and rerun. Writers should
+// be stopped while it runs, or the key may not be enforceable until it is
+// rerun. Checksums of sets that change are refreshed when the sets are next
+// ingested.
+func runNormalizeRecords(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	for _, shard := range shards.Shards() {
+		var migrated bool
+		if err := shard.DB.QueryRow("SELECT to_regclass('dns_records_natural_key') IS NOT NULL").Scan(&migrated); err != nil {
+			return fmt.Errorf("shard %s: failed to look up natural key: %v", shard.Name, err)
+		}
+		if migrated {
+			fmt.Printf("Records on shard %s already have a natural key\n", shard.Name)
+			continue
+		}
+		if err := addNaturalKeyColumns(shard.DB); err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		keyed, err := keyShardRecords(shard.DB)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		merged, deleted, err := mergeShardRecords(shard.DB)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		if err := enforceNaturalKey(shard.DB); err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		fmt.Printf("Keyed %d records, merged %d and deleted %d duplicates on shard %s\n", keyed, merged, deleted, shard.Name)
+	}
+	return nil
+}
+
+// observationIndexes are the unique indexes dns_records had per observation
+// before its natural key. Normalizing record_data can make two of a
+// record's observations collide in them, so they are dropped first.
+var observationIndexes = []string{
+	"dns_records_ns_observation", "dns_records_a_observation", "dns_records_aaaa_observation",
+	"dns_records_mx_observation", "dns_records_txt_observation", "dns_records_cname_observation",
+	"dns_records_other_observation",
+}
+
+// addNaturalKeyColumns adds canonical_hash, nullable until every record has
+// one, first_seen and observations to dns_records if it lacks them, and
+// drops the observation indexes.
+func addNaturalKeyColumns(db *sql.DB) error {
+	statements := []string{
+		"ALTER TABLE dns_records ADD COLUMN IF NOT EXISTS canonical_hash BYTEA",
+		"ALTER TABLE dns_records ADD COLUMN IF NOT EXISTS first_seen TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP",
+		"ALTER TABLE dns_records ADD COLUMN IF NOT EXISTS observations INTEGER NOT NULL DEFAULT 1",
+	}
+	for _, name := range observationIndexes {
+		statements = append(statements, "DROP INDEX IF EXISTS "+name)
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to add natural key columns (%s): %v", stmt, err)
+		}
+	}
+	return nil
+}
+
+// enforceNaturalKey makes canonical_hash and source NOT NULL and creates
+// dns_records_natural_key, which the writers' upserts rely on, once every
+// record is keyed and merged. It fails if a writer stored a record without
+// a key, or a duplicate, since the merge.
+func enforceNaturalKey(db *sql.DB) error {
+	for _, stmt := range []string{
+		"ALTER TABLE dns_records ALTER COLUMN canonical_hash SET NOT NULL, ALTER COLUMN source SET NOT NULL",
+		"CREATE UNIQUE INDEX IF NOT EXISTS dns_records_natural_key ON dns_records (domain_id, record_type, source, canonical_hash)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to enforce natural key (%s): %v; rerun the job", stmt, err)
+		}
+	}
+	return nil
+}
+
+// keyedRecord is a record without a canonical_hash.
+type keyedRecord struct {
+	id         int64
+	recordType string
+	data       string // Normalized
+	hash       []byte
+}
+
+// keyShardRecords normalizes and hashes the records that have no
+// canonical_hash yet.
+func keyShardRecords(db *sql.DB) (keyed int64, err error) {
+	var lastID int64
+	for {
+		rows, err := db.Query(`
+			SELECT id, record_type, record_data FROM dns_records
+			WHERE id > $1 AND canonical_hash IS NULL
+			ORDER BY id
+			LIMIT $2
+		`, lastID, normalizeBatchSize)
+		if err != nil {
+			return keyed, fmt.Errorf("failed to query records: %v", err)
+		}
+		var records []keyedRecord
+		for rows.Next() {
+			var r keyedRecord
+			if err := rows.Scan(&r.id, &r.recordType, &r.data); err != nil {
+				rows.Close()
+				return keyed, fmt.Errorf("failed to scan record: %v", err)
+			}
+			lastID = r.id
+			r.data = recordset.Normalize(r.data)
+			r.hash = recordset.CanonicalHash(r.data)
+			records = append(records, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return keyed, fmt.Errorf("failed to iterate records: %v", err)
+		}
+		if err := storeKeyedRecords(db, records); err != nil {
+			return keyed, err
+		}
+		keyed += int64(len(records))
+		if len(records) < normalizeBatchSize {
+			return keyed, nil
+		}
+	}
+}
+
+func storeKeyedRecords(db *sql.DB, records []keyedRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range records {
+		if _, err := tx.Exec(`
+			UPDATE dns_records
+			SET record_data = $3, canonical_hash = $4, source = COALESCE(source, 'CZDS'),
+				first_seen = COALESCE(last_updated, first_seen)
+			WHERE id = $1 AND record_type = $2
+		`, r.id, r.recordType, r.data, r.hash); err != nil {
+			return fmt.Errorf("failed to key record %d: %v", r.id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// mergeShardRecords merges rows sharing a natural key, normalizeBatchSize
+// domains at a time. The row last updated is kept, with the others'
+// observations added and the earliest first_seen; the others are deleted.
+func mergeShardRecords(db *sql.DB) (merged, deleted int64, err error) {
+	var maxID int64
+	if err := db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM domains").Scan(&maxID); err != nil {
+		return 0, 0, fmt.Errorf("failed to query last domain: %v", err)
+	}
+	for lo := int64(0); lo <= maxID; lo += normalizeBatchSize {
+		var m, d int64
+		err := db.QueryRow(`
+			WITH dup AS (
+				SELECT domain_id, record_type, source, canonical_hash,
+					(array_agg(id ORDER BY last_updated DESC NULLS LAST, id DESC))[1] AS keep_id,
+					SUM(observations) AS observations, MIN(first_seen) AS first_seen
+				FROM dns_records
+				WHERE domain_id >= $1 AND domain_id < $2 AND canonical_hash IS NOT NULL
+				GROUP BY domain_id, record_type, source, canonical_hash
+				HAVING COUNT(*) > 1
+			), deleted AS (
+				DELETE FROM dns_records r USING dup
+				WHERE r.domain_id = dup.domain_id AND r.record_type = dup.record_type AND r.source = dup.source
+					AND r.canonical_hash = dup.canonical_hash AND r.id <> dup.keep_id
+				RETURNING r.id
+			), merged AS (
+				UPDATE dns_records r SET observations = dup.observations, first_seen = dup.first_seen
+				FROM dup
+				WHERE r.id = dup.keep_id AND r.record_type = dup.record_type
+				RETURNING r.id
+			)
+			SELECT (SELECT COUNT(*) FROM merged), (SELECT COUNT(*) FROM deleted)
+		`, lo, lo+normalizeBatchSize).Scan(&m, &d)
+		if err != nil {
+			return merged, deleted, fmt.Errorf("failed to merge records of domains %d-%d: %v", lo, lo+normalizeBatchSize-1, err)
+		}
+		merged += m
+		deleted += d
+	}
+	return merged, deleted, nil
+}
@@ -0,0 +1,36 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// runRecordPriorities fills the priority and weight columns of MX and SRV
+// records written before they were parsed at write time, so those records
+// take part in semantic GetRecords ordering. record_data is in zone file
+// format with tab-separated header fields, so the rdata is the fifth field.
+func runRecordPriorities(db *sql.DB) error {
+	res, err := db.Exec(`
+		UPDATE dns_records
+		SET priority = split_part(split_part(record_data, E'\t', 5), ' ', 1)::int
+		WHERE record_type = 'MX' AND priority IS NULL
+		AND split_part(split_part(record_data, E'\t', 5), ' ', 1) ~ '^[0-9]+$'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill MX preferences: %v", err)
+	}
+	mx, _ := res.RowsAffected()
+	res, err = db.Exec(`
+		UPDATE dns_records
+		SET priority = split_part(split_part(record_data, E'\t', 5), ' ', 1)::int,
+		    weight = split_part(split_part(record_data, E'\t', 5), ' ', 2)::int
+		WHERE record_type = 'SRV' AND priority IS NULL
+		AND split_part(record_data, E'\t', 5) ~ '^[0-9]+ [0-9]+ '
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill SRV priorities: %v", err)
+	}
+	srv, _ := res.RowsAffected()
+	fmt.Printf("Backfilled %d MX and %d SRV records\n", mx, srv)
+	return nil
+}
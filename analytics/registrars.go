@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runIANARegistrars reloads the IANA registrar ID registry (ID, name, status,
+// RDAP base URL) into iana_registrars, used to resolve registrar RDAP servers
+// and names for abuse contact lookups.
+func runIANARegistrars(db *sql.DB, registryURL string) error {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(registryURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch registrar registry: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch registrar registry: HTTP %d", resp.StatusCode)
+	}
+
+	registrars, err := parseRegistrarCSV(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO iana_registrars (iana_id, name, status, rdap_url, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (iana_id) DO UPDATE
+		SET name = EXCLUDED.name, status = EXCLUDED.status, rdap_url = EXCLUDED.rdap_url, updated_at = EXCLUDED.updated_at
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	for _, r := range registrars {
+		if _, err := stmt.Exec(r.id, r.name, r.status, r.rdapURL, now); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to store registrar %d: %v", r.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	fmt.Printf("Stored %d IANA registrars\n", len(registrars))
+	return nil
+}
+
+type ianaRegistrar struct {
+	id      int
+	name    string
+	status  string
+	rdapURL string
+}
+
+// parseRegistrarCSV parses IANA's registrar-ids CSV:
+// ID, Registrar Name, Status, RDAP Base URL (header row first).
+func parseRegistrarCSV(r io.Reader) ([]ianaRegistrar, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registrar registry: %v", err)
+	}
+	var registrars []ianaRegistrar
+	for i, row := range rows {
+		if i == 0 || len(row) < 3 {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(row[0]))
+		if err != nil {
+			continue
+		}
+		reg := ianaRegistrar{id: id, name: strings.TrimSpace(row[1]), status: strings.TrimSpace(row[2])}
+		if len(row) > 3 {
+			reg.rdapURL = strings.TrimSpace(row[3])
+		}
+		registrars = append(registrars, reg)
+	}
+	return registrars, nil
+}
@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/moos3/bell/coverage"
+)
+
+// runIANATLDs reloads IANA's list of TLDs into iana_tlds, which the server
+// compares with the ingested TLDs to report coverage gaps (GetTLDCoverage).
+// TLDs retired from the root zone are removed.
+func runIANATLDs(db *sql.DB, listURL string) error {
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(listURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch TLD list: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch TLD list: HTTP %d", resp.StatusCode)
+	}
+
+	tlds, err := coverage.ParseTLDList(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`
+		INSERT INTO iana_tlds (tld, kind, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tld) DO UPDATE
+		SET kind = EXCLUDED.kind, updated_at = EXCLUDED.updated_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	for _, tld := range tlds {
+		if _, err := stmt.Exec(tld, coverage.Kind(tld), now); err != nil {
+			return fmt.Errorf("failed to store TLD %s: %v", tld, err)
+		}
+	}
+	result, err := tx.Exec("DELETE FROM iana_tlds WHERE NOT (tld = ANY($1))", pq.Array(tlds))
+	if err != nil {
+		return fmt.Errorf("failed to remove retired TLDs: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	retired, _ := result.RowsAffected()
+	fmt.Printf("Stored %d IANA TLDs (%d retired)\n", len(tlds), retired)
+	return nil
+}
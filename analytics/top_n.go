@@ -0,0 +1,329 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/moos3/bell/asndb"
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+// Metric names stored in analytics_top.metric.
+const (
+	metricNameservers = "NAMESERVERS"
+	metricMXProviders = "MX_PROVIDERS"
+	metricASNs        = "ASNS"
+	globalTLD         = "" // analytics_top.tld value for global aggregates
+)
+
+// topCounter counts distinct domains per key, both per TLD and globally.
+//
+// Rows must be fed grouped by domain ID so a domain with several records
+// mapping to the same key (e.g. multiple Google MX hosts) is counted once.
+type topCounter struct {
+	counts    map[string]map[string]int64 // tld -> key -> domain count
+	labels    map[string]string           // key -> human-readable label
+	domainID  int
+	seenInDom map[string]bool
+}
+
+func newTopCounter() *topCounter {
+	return &topCounter{
+		counts:    make(map[string]map[string]int64),
+		labels:    make(map[string]string),
+		domainID:  -1,
+		seenInDom: make(map[string]bool),
+	}
+}
+
+func (c *topCounter) add(tld string, domainID int, key, label string) {
+	if domainID != c.domainID {
+		c.domainID = domainID
+		c.seenInDom = make(map[string]bool)
+	}
+	if c.seenInDom[key] {
+		return
+	}
+	c.seenInDom[key] = true
+	for _, t := range []string{tld, globalTLD} {
+		if c.counts[t] == nil {
+			c.counts[t] = make(map[string]int64)
+		}
+		c.counts[t][key]++
+	}
+	if label != "" {
+		c.labels[key] = label
+	}
+}
+
+// merge adds the counts of other, counted on another shard. Domains live on
+// exactly one shard, so no domain is counted twice.
+func (c *topCounter) merge(other *topCounter) {
+	for tld, keys := range other.counts {
+		if c.counts[tld] == nil {
+			c.counts[tld] = make(map[string]int64)
+		}
+		for key, count := range keys {
+			c.counts[tld][key] += count
+		}
+	}
+	for key, label := range other.labels {
+		c.labels[key] = label
+	}
+}
+
+// tlds returns the number of TLDs counted, not including globalTLD.
+func (c *topCounter) tlds() int {
+	n := len(c.counts)
+	if _, ok := c.counts[globalTLD]; ok {
+		n--
+	}
+	return n
+}
+
+// topEntry is a single ranked row of analytics_top.
+type topEntry struct {
+	tld   string
+	key   string
+	label string
+	count int64
+	rank  int
+}
+
+// top returns the n highest-count keys for each TLD and globally.
+func (c *topCounter) top(n int) []topEntry {
+	var entries []topEntry
+	for tld, keys := range c.counts {
+		ranked := make([]topEntry, 0, len(keys))
+		for key, count := range keys {
+			ranked = append(ranked, topEntry{tld: tld, key: key, label: c.labels[key], count: count})
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].count != ranked[j].count {
+				return ranked[i].count > ranked[j].count
+			}
+			return ranked[i].key < ranked[j].key
+		})
+		if len(ranked) > n {
+			ranked = ranked[:n]
+		}
+		for i := range ranked {
+			ranked[i].rank = i + 1
+		}
+		entries = append(entries, ranked...)
+	}
+	return entries
+}
+
+// countNameservers counts delegated domains per nameserver host.
+func countNameservers(db *sql.DB) (*topCounter, error) {
+	rows, err := db.Query(`
+		SELECT d.tld, d.id, lower(rtrim(ns, '.'))
+		FROM domains d, unnest(d.nameservers) AS ns
+		ORDER BY d.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counter := newTopCounter()
+	for rows.Next() {
+		var tld, ns string
+		var domainID int
+		if err := rows.Scan(&tld, &domainID, &ns); err != nil {
+			return nil, fmt.Errorf("failed to scan nameserver: %v", err)
+		}
+		counter.add(tld, domainID, ns, "")
+	}
+	return counter, rows.Err()
+}
+
+// countMXProviders counts domains per MX provider, where the provider is the
+// registrable domain of the MX target (e.g. google.com for aspmx.l.google.com).
+func countMXProviders(db *sql.DB) (*topCounter, error) {
+	rows, err := db.Query(`
+		SELECT d.tld, r.domain_id, r.record_data
+		FROM dns_records r
+		JOIN domains d ON d.id = r.domain_id
+		WHERE r.record_type = 'MX'
+		ORDER BY r.domain_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counter := newTopCounter()
+	for rows.Next() {
+		var tld, data string
+		var domainID int
+		if err := rows.Scan(&tld, &domainID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan MX record: %v", err)
+		}
+		rr, err := dns.NewRR(data)
+		if err != nil || rr == nil {
+			continue
+		}
+		mx, ok := rr.(*dns.MX)
+		if !ok {
+			continue
+		}
+		target := strings.ToLower(strings.TrimSuffix(mx.Mx, "."))
+		if target == "" {
+			// Null MX (RFC 7505): domain accepts no mail
+			continue
+		}
+		provider, err := publicsuffix.EffectiveTLDPlusOne(target)
+		if err != nil {
+			provider = target
+		}
+		counter.add(tld, domainID, provider, "")
+	}
+	return counter, rows.Err()
+}
+
+// countASNs counts domains per autonomous system announcing their A/AAAA addresses.
+func countASNs(db *sql.DB, asns *asndb.DB) (*topCounter, error) {
+	rows, err := db.Query(`
+		SELECT d.tld, r.domain_id, r.record_data
+		FROM dns_records r
+		JOIN domains d ON d.id = r.domain_id
+		WHERE r.record_type IN ('A', 'AAAA')
+		ORDER BY r.domain_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counter := newTopCounter()
+	for rows.Next() {
+		var tld, data string
+		var domainID int
+		if err := rows.Scan(&tld, &domainID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan address record: %v", err)
+		}
+		rr, err := dns.NewRR(data)
+		if err != nil || rr == nil {
+			continue
+		}
+		var ip net.IP
+		switch a := rr.(type) {
+		case *dns.A:
+			ip = a.A
+		case *dns.AAAA:
+			ip = a.AAAA
+		default:
+			continue
+		}
+		asn, name, ok := asns.Lookup(ip)
+		if !ok {
+			continue
+		}
+		counter.add(tld, domainID, asn, name)
+	}
+	return counter, rows.Err()
+}
+
+// storeTopN replaces the aggregate rows for a metric in a single transaction,
+// so readers never observe a partially written ranking.
+func storeTopN(db *sql.DB, metric string, entries []topEntry) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM analytics_top WHERE metric = $1", metric); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO analytics_top (metric, tld, key, label, domain_count, rank, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, e := range entries {
+		if _, err := stmt.Exec(metric, e.tld, e.key, e.label, e.count, e.rank, now); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert %s entry %s: %v", metric, e.key, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// topNJob computes the counter for a single metric on one shard.
+type topNJob struct {
+	metric string
+	count  func(shard *sql.DB) (*topCounter, error)
+}
+
+// countAllShards runs count on every shard and merges the counters.
+func countAllShards(shards *storage.Router, count func(shard *sql.DB) (*topCounter, error)) (*topCounter, error) {
+	total := newTopCounter()
+	var mu sync.Mutex
+	_, err := shards.FanOut(context.Background(), func(ctx context.Context, shard *storage.Shard) error {
+		counter, err := count(shard.DB)
+		if err != nil {
+			return fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		mu.Lock()
+		total.merge(counter)
+		mu.Unlock()
+		return nil
+	})
+	return total, err
+}
+
+// runTopN recomputes all top-N aggregates from every shard. ASN aggregates
+// are skipped when no ASN database is configured.
+func runTopN(db *sql.DB, cfg *config.Config) error {
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+	metrics := []topNJob{
+		{metricNameservers, countNameservers},
+		{metricMXProviders, countMXProviders},
+	}
+	if cfg.Analytics.ASNDatabase != "" {
+		asns, err := asndb.Load(cfg.Analytics.ASNDatabase)
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, topNJob{metricASNs, func(shard *sql.DB) (*topCounter, error) { return countASNs(shard, asns) }})
+	} else {
+		fmt.Println("No analytics.asn_database configured; skipping ASN aggregates.")
+	}
+
+	topN := cfg.Analytics.TopN
+	for _, job := range metrics {
+		start := time.Now()
+		counter, err := countAllShards(shards, job.count)
+		if err != nil {
+			return fmt.Errorf("failed to count %s: %v", job.metric, err)
+		}
+		entries := counter.top(topN)
+		if err := storeTopN(db, job.metric, entries); err != nil {
+			return fmt.Errorf("failed to store %s: %v", job.metric, err)
+		}
+		fmt.Printf("Stored %d %s entries across %d TLDs in %v\n", len(entries), job.metric, counter.tlds(), time.Since(start).Round(time.Second))
+	}
+	return nil
+}
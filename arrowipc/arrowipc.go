@@ -0,0 +1,251 @@
+// Package arrowipc writes tables in the Arrow IPC streaming format
+// (https://arrow.apache.org/docs/format/Columnar.html#ipc-streaming-format)
+// with version 5 metadata, for the column types bell serves: UTF-8 strings,
+// 32-bit integers and UTC timestamps in microseconds. It encodes the
+// flatbuffers metadata itself rather than depending on the Arrow libraries.
+//
+// A stream is the message of SchemaMessage, a Batch message per batch of
+// rows, then EndOfStream; Arrow readers (pyarrow.ipc.open_stream, Arrow
+// JS's RecordBatchReader, ...) read their concatenation.
+package arrowipc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Type is the type of a column.
+type Type int
+
+const (
+	Utf8      Type = iota
+	Int32          // Signed
+	Timestamp      // Microseconds since the epoch, in UTC
+)
+
+// Field is a column of a schema.
+type Field struct {
+	Name     string
+	Type     Type
+	Nullable bool
+}
+
+const (
+	metadataV5 = 4 // MetadataVersion.V5
+
+	headerSchema      = 1 // MessageHeader union
+	headerRecordBatch = 3
+
+	typeInt       = 2 // Type union
+	typeUtf8      = 5
+	typeTimestamp = 10
+
+	unitMicrosecond = 2 // TimeUnit
+)
+
+// fieldType returns the Type union type and table of t.
+func fieldType(t Type) (uint8, fbTable) {
+	switch t {
+	case Int32:
+		return typeInt, fbTable{fbInt32(32), fbBool(true)}
+	case Timestamp:
+		return typeTimestamp, fbTable{fbInt16(unitMicrosecond), fbOffset(fbString("UTC"))}
+	default:
+		return typeUtf8, fbTable{}
+	}
+}
+
+// SchemaMessage returns the message that starts a stream of rows of fields.
+func SchemaMessage(fields []Field) []byte {
+	var fieldTables fbTables
+	for _, f := range fields {
+		typeType, typeTable := fieldType(f.Type)
+		fieldTables = append(fieldTables, fbTable{
+			fbOffset(fbString(f.Name)),
+			fbBool(f.Nullable),
+			fbInt8(typeType),
+			fbOffset(typeTable),
+			nil,                  // dictionary
+			fbOffset(fbTables{}), // children, which readers require even when empty
+		})
+	}
+	schema := fbTable{fbInt16(0), fbOffset(fieldTables)} // Little-endian
+	return message(headerSchema, schema, nil)
+}
+
+// EndOfStream returns the marker that ends a stream.
+func EndOfStream() []byte {
+	return []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}
+}
+
+// message encapsulates a message: a continuation marker, the length of the
+// metadata padded to 8 bytes, the metadata and the body.
+func message(headerType uint8, header fbTable, body []byte) []byte {
+	metadata := finish(fbTable{
+		fbInt16(metadataV5),
+		fbInt8(headerType),
+		fbOffset(header),
+		fbInt64(int64(len(body))),
+	})
+	for len(metadata)%8 != 0 {
+		metadata = append(metadata, 0)
+	}
+	msg := make([]byte, 8, 8+len(metadata)+len(body))
+	binary.LittleEndian.PutUint32(msg, 0xffffffff)
+	binary.LittleEndian.PutUint32(msg[4:], uint32(len(metadata)))
+	msg = append(msg, metadata...)
+	return append(msg, body...)
+}
+
+// column holds the values of a column of a batch.
+type column struct {
+	valid   []bool
+	nulls   int
+	offsets []int32 // Utf8: start of each value in data, and the end
+	data    []byte  // Utf8
+	ints    []int32
+	times   []int64
+}
+
+// Batch accumulates rows column by column. Each row appends one value to
+// every column, in schema order.
+type Batch struct {
+	fields  []Field
+	columns []column
+	rows    int
+}
+
+// NewBatch returns an empty batch of fields.
+func NewBatch(fields []Field) *Batch {
+	b := &Batch{fields: fields, columns: make([]column, len(fields))}
+	b.Reset()
+	return b
+}
+
+// Len returns the number of rows appended since the last Reset.
+func (b *Batch) Len() int {
+	return b.rows
+}
+
+// Size returns about how many bytes the batch's message body takes.
+func (b *Batch) Size() int {
+	size := 0
+	for i := range b.columns {
+		c := &b.columns[i]
+		size += len(c.valid)/8 + 4*len(c.offsets) + len(c.data) + 4*len(c.ints) + 8*len(c.times)
+	}
+	return size
+}
+
+// Reset empties the batch, keeping its memory.
+func (b *Batch) Reset() {
+	for i := range b.columns {
+		c := &b.columns[i]
+		c.valid, c.nulls, c.data, c.ints, c.times = c.valid[:0], 0, c.data[:0], c.ints[:0], c.times[:0]
+		c.offsets = append(c.offsets[:0], 0)
+	}
+	b.rows = 0
+}
+
+// AppendString appends s to Utf8 column col.
+func (b *Batch) AppendString(col int, s string) {
+	c := &b.columns[col]
+	c.valid = append(c.valid, true)
+	c.data = append(c.data, s...)
+	c.offsets = append(c.offsets, int32(len(c.data)))
+	b.advance(col)
+}
+
+// AppendInt32 appends v, or a null if !valid, to Int32 column col.
+func (b *Batch) AppendInt32(col int, v int32, valid bool) {
+	c := &b.columns[col]
+	c.append(valid)
+	if !valid {
+		v = 0
+	}
+	c.ints = append(c.ints, v)
+	b.advance(col)
+}
+
+// AppendTimestamp appends t, or a null if t is zero, to Timestamp column
+// col.
+func (b *Batch) AppendTimestamp(col int, t time.Time) {
+	c := &b.columns[col]
+	c.append(!t.IsZero())
+	var us int64
+	if !t.IsZero() {
+		us = t.UnixMicro()
+	}
+	c.times = append(c.times, us)
+	b.advance(col)
+}
+
+func (c *column) append(valid bool) {
+	c.valid = append(c.valid, valid)
+	if !valid {
+		c.nulls++
+	}
+}
+
+// advance counts a row once its last column is appended.
+func (b *Batch) advance(col int) {
+	if col == len(b.columns)-1 {
+		b.rows++
+	}
+}
+
+// Message returns the batch as a record batch message. Every column must
+// hold Len values.
+func (b *Batch) Message() ([]byte, error) {
+	var nodes, buffers fbStructs
+	var body []byte
+	addBuffer := func(data []byte) {
+		buffers = append(buffers, [2]int64{int64(len(body)), int64(len(data))})
+		body = append(body, data...)
+		for len(body)%8 != 0 {
+			body = append(body, 0)
+		}
+	}
+	for i, f := range b.fields {
+		c := &b.columns[i]
+		if len(c.valid) != b.rows {
+			return nil, fmt.Errorf("column %s has %d values for %d rows", f.Name, len(c.valid), b.rows)
+		}
+		nodes = append(nodes, [2]int64{int64(b.rows), int64(c.nulls)})
+		if c.nulls > 0 {
+			bitmap := make([]byte, (b.rows+7)/8)
+			for row, valid := range c.valid {
+				if valid {
+					bitmap[row/8] |= 1 << (row % 8)
+				}
+			}
+			addBuffer(bitmap)
+		} else {
+			addBuffer(nil)
+		}
+		switch f.Type {
+		case Utf8:
+			offsets := make([]byte, 0, 4*len(c.offsets))
+			for _, off := range c.offsets {
+				offsets = binary.LittleEndian.AppendUint32(offsets, uint32(off))
+			}
+			addBuffer(offsets)
+			addBuffer(c.data)
+		case Int32:
+			values := make([]byte, 0, 4*len(c.ints))
+			for _, v := range c.ints {
+				values = binary.LittleEndian.AppendUint32(values, uint32(v))
+			}
+			addBuffer(values)
+		case Timestamp:
+			values := make([]byte, 0, 8*len(c.times))
+			for _, v := range c.times {
+				values = binary.LittleEndian.AppendUint64(values, uint64(v))
+			}
+			addBuffer(values)
+		}
+	}
+	recordBatch := fbTable{fbInt64(int64(b.rows)), fbOffset(nodes), fbOffset(buffers)}
+	return message(headerRecordBatch, recordBatch, body), nil
+}
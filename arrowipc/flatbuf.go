@@ -0,0 +1,159 @@
+package arrowipc
+
+import "encoding/binary"
+
+// The Arrow IPC metadata are flatbuffers. builder lays them out front to
+// back: a table's vtable and inline fields come first and the objects its
+// offset fields point to (tables, strings, vectors) follow, since
+// flatbuffers offsets only point forward. Every scalar is aligned to its
+// size from the start of the buffer, as flatbuffers verifiers require.
+
+// fbObject is a table, string or vector written by builder.
+type fbObject interface {
+	write(b *builder) int // Returns the position offsets to the object point at
+}
+
+// fbField is a field of a table: a scalar of size bytes or, if child is
+// set, an offset to child. Absent fields take their schema default.
+type fbField struct {
+	size   int
+	scalar uint64
+	child  fbObject
+}
+
+func fbInt8(v uint8) *fbField      { return &fbField{size: 1, scalar: uint64(v)} }
+func fbInt16(v int16) *fbField     { return &fbField{size: 2, scalar: uint64(uint16(v))} }
+func fbInt32(v int32) *fbField     { return &fbField{size: 4, scalar: uint64(uint32(v))} }
+func fbInt64(v int64) *fbField     { return &fbField{size: 8, scalar: uint64(v)} }
+func fbOffset(o fbObject) *fbField { return &fbField{size: 4, child: o} }
+
+func fbBool(v bool) *fbField {
+	if v {
+		return fbInt8(1)
+	}
+	return fbInt8(0)
+}
+
+// fbTable lists its fields by id; nil fields are absent.
+type fbTable []*fbField
+
+func (t fbTable) write(b *builder) int {
+	// Inline layout: the vtable offset, then fields by descending size so
+	// each is aligned once the table is aligned to 8
+	offsets := make([]int, len(t))
+	size := 4
+	for _, width := range []int{8, 4, 2, 1} {
+		for id, f := range t {
+			if f != nil && f.size == width {
+				size = (size + width - 1) / width * width
+				offsets[id] = size
+				size += width
+			}
+		}
+	}
+
+	b.align(2)
+	vtable := len(b.buf)
+	b.uint16(uint16(4 + 2*len(t)))
+	b.uint16(uint16(size))
+	for _, off := range offsets {
+		b.uint16(uint16(off))
+	}
+	b.align(8)
+	table := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(int32(table-vtable)))
+	for id, f := range t {
+		if f == nil || f.child != nil {
+			continue
+		}
+		at := b.buf[table+offsets[id]:]
+		switch f.size {
+		case 1:
+			at[0] = uint8(f.scalar)
+		case 2:
+			binary.LittleEndian.PutUint16(at, uint16(f.scalar))
+		case 4:
+			binary.LittleEndian.PutUint32(at, uint32(f.scalar))
+		case 8:
+			binary.LittleEndian.PutUint64(at, f.scalar)
+		}
+	}
+	for id, f := range t {
+		if f != nil && f.child != nil {
+			b.patch(table+offsets[id], f.child.write(b))
+		}
+	}
+	return table
+}
+
+// fbString is a flatbuffers string: its length, bytes and a NUL.
+type fbString string
+
+func (s fbString) write(b *builder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.uint32(uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// fbStructs is a vector of structs of two longs (Arrow's FieldNode and
+// Buffer).
+type fbStructs [][2]int64
+
+func (v fbStructs) write(b *builder) int {
+	// The elements follow the length and must be aligned to 8
+	for (len(b.buf)+4)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	pos := len(b.buf)
+	b.uint32(uint32(len(v)))
+	for _, s := range v {
+		b.uint64(uint64(s[0]))
+		b.uint64(uint64(s[1]))
+	}
+	return pos
+}
+
+// fbTables is a vector of tables.
+type fbTables []fbTable
+
+func (v fbTables) write(b *builder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.uint32(uint32(len(v)))
+	slots := len(b.buf)
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		b.patch(slots+4*i, t.write(b))
+	}
+	return pos
+}
+
+type builder struct {
+	buf []byte
+}
+
+// finish returns a flatbuffer whose root is table.
+func finish(table fbTable) []byte {
+	b := &builder{buf: make([]byte, 4, 512)}
+	b.patch(0, table.write(b))
+	return b.buf
+}
+
+// patch points the offset at pos to target.
+func (b *builder) patch(pos, target int) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+func (b *builder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *builder) uint16(v uint16) { b.buf = binary.LittleEndian.AppendUint16(b.buf, v) }
+func (b *builder) uint32(v uint32) { b.buf = binary.LittleEndian.AppendUint32(b.buf, v) }
+func (b *builder) uint64(v uint64) { b.buf = binary.LittleEndian.AppendUint64(b.buf, v) }
@@ -0,0 +1,78 @@
+// Package asndb maps IP addresses to the autonomous systems announcing
+// them, from an iptoasn.com table loaded into memory.
+package asndb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// asnRange maps an inclusive IP range to an autonomous system.
+type asnRange struct {
+	start, end net.IP // 16-byte form for comparison
+	asn        string
+	name       string
+}
+
+// DB is an in-memory IP to ASN lookup table sorted by range start.
+type DB struct {
+	ranges []asnRange
+}
+
+// Load reads a tab-separated IP-to-ASN file in the iptoasn.com format:
+// range_start, range_end, AS number, country code, AS description.
+// Ranges with AS number 0 (not routed) are skipped.
+func Load(path string) (*DB, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ASN database %s: %v", path, err)
+	}
+	defer file.Close()
+
+	db := &DB{}
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid ASN database line %d in %s", lineNo, path)
+		}
+		if fields[2] == "0" {
+			continue
+		}
+		start, end := net.ParseIP(fields[0]), net.ParseIP(fields[1])
+		if start == nil || end == nil {
+			return nil, fmt.Errorf("invalid IP range on line %d in %s", lineNo, path)
+		}
+		db.ranges = append(db.ranges, asnRange{start: start.To16(), end: end.To16(), asn: "AS" + fields[2], name: fields[4]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ASN database %s: %v", path, err)
+	}
+	sort.Slice(db.ranges, func(i, j int) bool { return bytes.Compare(db.ranges[i].start, db.ranges[j].start) < 0 })
+	return db, nil
+}
+
+// Lookup returns the ASN and AS name announcing ip, or ok=false if unknown.
+func (db *DB) Lookup(ip net.IP) (asn, name string, ok bool) {
+	ip = ip.To16()
+	if ip == nil {
+		return "", "", false
+	}
+	// First range starting after ip; the candidate is the one before it
+	i := sort.Search(len(db.ranges), func(i int) bool { return bytes.Compare(db.ranges[i].start, ip) > 0 })
+	if i == 0 {
+		return "", "", false
+	}
+	r := db.ranges[i-1]
+	if bytes.Compare(ip, r.end) > 0 {
+		return "", "", false
+	}
+	return r.asn, r.name, true
+}
@@ -0,0 +1,191 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moos3/bell/czds"
+	"github.com/moos3/bell/fixtures"
+)
+
+// autotuneZones is how many zones the calibration passes ingest, enough for
+// the concurrency passes to keep eight writers busy.
+const autotuneZones = 8
+
+// autotuneTolerance is how much slower than the fastest setting a smaller
+// one may be and still be recommended: fewer connections and shorter
+// transactions are worth a few percent.
+const autotuneTolerance = 0.05
+
+var (
+	autotuneBatchSizes  = []int{250, 500, 1000, 2500, 5000}
+	autotuneConcurrency = []int{1, 2, 4, 8}
+)
+
+// ingestSettings are the zones settings autotune recommends.
+type ingestSettings struct {
+	writeMethod   string
+	batchSize     int
+	maxConcurrent int
+}
+
+func (s ingestSettings) String() string {
+	return fmt.Sprintf("write_method=%s batch_size=%d max_concurrent=%d", s.writeMethod, s.batchSize, s.maxConcurrent)
+}
+
+// calibration is the throughput measured with a setting.
+type calibration struct {
+	settings ingestSettings
+	rate     float64
+}
+
+// autotune runs calibration passes storing generated zones in db, the
+// scratch database, with each write method and batch size and then with
+// increasing concurrency, and returns the settings to ingest with. The
+// domains are stored once before the passes, so each pass measures
+// re-ingesting zones as the daily runs do.
+func autotune(ctx context.Context, db *sql.DB, domains int, seed int64) (ingestSettings, error) {
+	tlds := make([]string, autotuneZones)
+	for i := range tlds {
+		tlds[i] = fmt.Sprintf("example%d", i)
+	}
+	perZone := domains / autotuneZones
+	if perZone < 1 {
+		perZone = 1
+	}
+	ds := fixtures.Generate(fixtures.Options{Seed: seed, TLDs: tlds, DomainsPerTLD: perZone})
+	zones := make([][]byte, len(ds.Zones))
+	for i, z := range ds.Zones {
+		var buf bytes.Buffer
+		if err := z.WriteZoneFile(&buf); err != nil {
+			return ingestSettings{}, fmt.Errorf("failed to write zone file: %v", err)
+		}
+		zones[i] = buf.Bytes()
+		tlds[i] = z.TLD
+	}
+	fmt.Printf("Calibrating zone ingestion with %d zones of %d domains (seed %d)\n", len(zones), perZone, seed)
+
+	if _, err := czds.CalibrateWrites(ctx, db, zones, tlds, 1000, false, 1); err != nil {
+		return ingestSettings{}, fmt.Errorf("failed to load zones: %v", err)
+	}
+	measure := func(s ingestSettings) (calibration, error) {
+		rate, err := czds.CalibrateWrites(ctx, db, zones, tlds, s.batchSize, s.writeMethod == "copy", s.maxConcurrent)
+		if err != nil {
+			return calibration{}, fmt.Errorf("calibration with %s failed: %v", s, err)
+		}
+		fmt.Printf("%-55s %12.0f records/s\n", s, rate)
+		return calibration{s, rate}, nil
+	}
+
+	// Batch size and write method interact, so try every pairing with a
+	// single writer, then scale the best one out
+	var passes []calibration
+	for _, method := range []string{"insert", "copy"} {
+		for _, size := range autotuneBatchSizes {
+			c, err := measure(ingestSettings{method, size, 1})
+			if err != nil {
+				return ingestSettings{}, err
+			}
+			passes = append(passes, c)
+		}
+	}
+	best := pick(passes)
+	passes = nil
+	for _, n := range autotuneConcurrency {
+		c, err := measure(ingestSettings{best.writeMethod, best.batchSize, n})
+		if err != nil {
+			return ingestSettings{}, err
+		}
+		passes = append(passes, c)
+	}
+	return pick(passes), nil
+}
+
+// pick returns the first settings of passes within autotuneTolerance of the
+// fastest. Passes are ordered from the cheapest setting up, so a larger
+// batch or more writers must earn their cost.
+func pick(passes []calibration) ingestSettings {
+	var fastest float64
+	for _, c := range passes {
+		fastest = max(fastest, c.rate)
+	}
+	for _, c := range passes {
+		if c.rate >= fastest*(1-autotuneTolerance) {
+			return c.settings
+		}
+	}
+	return passes[0].settings
+}
+
+// writeSettings sets zones.write_method, zones.batch_size and
+// zones.max_concurrent in the configuration file at path, keeping its other
+// settings. Comments are kept, though the file is re-indented.
+func writeSettings(path string, s ingestSettings) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a YAML mapping", path)
+	}
+	zones := mappingValue(root, "zones")
+	if zones.Kind != yaml.MappingNode {
+		*zones = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	setScalar(zones, "batch_size", "!!int", strconv.Itoa(s.batchSize))
+	setScalar(zones, "max_concurrent", "!!int", strconv.Itoa(s.maxConcurrent))
+	setScalar(zones, "write_method", "!!str", s.writeMethod)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Bytes(), info.Mode().Perm())
+}
+
+// mappingValue returns the value of key in mapping, adding an empty one if
+// the key is missing.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	value := &yaml.Node{}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// setScalar sets key in mapping to a scalar, keeping the comments of an
+// existing value.
+func setScalar(mapping *yaml.Node, key, tag, value string) {
+	n := mappingValue(mapping, key)
+	n.Kind, n.Tag, n.Value, n.Style = yaml.ScalarNode, tag, value, 0
+	n.Content = nil
+	if tag == "!!str" {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+}
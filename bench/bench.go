@@ -0,0 +1,185 @@
+// Package bench runs the performance benchmarks of the ingest path and the
+// API against a generated dataset (see the fixtures package), printing
+// results in the go test -bench format so runs can be compared with
+// benchstat.
+//
+// The zone parser is always benchmarked. The batch writer and the
+// GetRecords handler need a scratch database on the alloydb host with
+// schema.sql applied, named with -database; its DNS data is replaced on
+// every run. Benchmark flags such as -test.benchtime are accepted. The
+// same benchmarks run under go test -bench in the czds and server packages
+// (see fixtures.DatabaseEnv for the database ones).
+//
+// With -autotune, the tool instead calibrates zone ingestion against the
+// scratch database: it times re-ingesting generated zones with each write
+// method and batch size, then with more concurrent writers, and writes the
+// fastest settings (preferring smaller ones within 5%) into the zones
+// section of -config, or only prints them with -dry-run. Run it against a
+// database on the same AlloyDB tier as production.
+//
+// The load-test harness in loadtest/ complements this by measuring a
+// running server against latency and error-rate targets.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/czds"
+	"github.com/moos3/bell/fixtures"
+	"github.com/moos3/bell/server"
+	"github.com/moos3/bell/storage"
+)
+
+// benchAPIKey is the key GetRecords is benchmarked with, created in the
+// scratch database.
+const benchAPIKey = "00000000-0000-4000-8000-00000000be1c"
+
+// benchmarkGetRecords measures getRecords (see server.NewGetRecordsBench),
+// cycling through domains.
+func benchmarkGetRecords(b *testing.B, getRecords func(domain string, merged bool) (int, error), domains []string, merged bool) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	records := 0
+	for i := 0; i < b.N; i++ {
+		n, err := getRecords(domains[i%len(domains)], merged)
+		if err != nil {
+			b.Fatal(err)
+		}
+		records += n
+	}
+	b.ReportMetric(float64(records)/float64(b.N), "records/op")
+}
+
+// benchmark is a named benchmark; run reports false if it failed.
+type benchmark struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+// run runs a benchmark count times and prints each result.
+func (bm benchmark) run(count int) bool {
+	for i := 0; i < count; i++ {
+		r := testing.Benchmark(bm.fn)
+		if r.N == 0 {
+			fmt.Printf("--- FAIL: %s\n", bm.name)
+			return false
+		}
+		fmt.Printf("%s-%d\t%s\t%s\n", bm.name, runtime.GOMAXPROCS(0), r.String(), r.MemString())
+	}
+	return true
+}
+
+// openScratch connects to the scratch database name on the alloydb host and
+// clears its DNS data.
+func openScratch(cfg *config.Config, name string) *sql.DB {
+	if name == cfg.AlloyDB.Database {
+		log.Fatal("-database must not be the production database")
+	}
+	db, err := sql.Open("postgres", storage.ConnString(cfg.AlloyDB.Host, cfg.AlloyDB.Port, cfg.AlloyDB.User, cfg.AlloyDB.Password, name, cfg.AlloyDB.SSLMode))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := db.Ping(); err != nil {
+		log.Fatal("Failed to connect to scratch database: ", err)
+	}
+	if _, err := db.Exec("TRUNCATE dns_records, domains RESTART IDENTITY CASCADE"); err != nil {
+		log.Fatalf("Failed to clear scratch database: %v", err)
+	}
+	return db
+}
+
+func main() {
+	testing.Init()
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	database := flag.String("database", "", "Scratch database on the alloydb host for the batch writer and GetRecords benchmarks; skipped if empty")
+	domains := flag.Int("domains", 10000, "Generated domains in the benchmark zone")
+	seed := flag.Int64("seed", 1, "Seed of the generated zone")
+	tld := flag.String("tld", "example", "TLD of the generated zone")
+	count := flag.Int("count", 1, "Run each benchmark this many times")
+	autotuneFlag := flag.Bool("autotune", false, "Calibrate zone ingestion against -database and write the fastest zones settings into -config instead of benchmarking")
+	dryRun := flag.Bool("dry-run", false, "With -autotune, print the recommended settings without writing them")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *autotuneFlag {
+		if *database == "" {
+			log.Fatal("-autotune needs a scratch database; set -database")
+		}
+		db := openScratch(config, *database)
+		defer db.Close()
+		settings, err := autotune(context.Background(), db, *domains, *seed)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Recommended: %s\n", settings)
+		if *dryRun {
+			return
+		}
+		if err := writeSettings(*configFile, settings); err != nil {
+			log.Fatalf("Failed to write settings to %s: %v", *configFile, err)
+		}
+		fmt.Printf("Wrote zones settings to %s\n", *configFile)
+		return
+	}
+
+	ds := fixtures.Generate(fixtures.Options{Seed: *seed, TLDs: []string{*tld}, DomainsPerTLD: *domains})
+	zone := ds.Zones[0]
+	var zoneFile bytes.Buffer
+	if err := zone.WriteZoneFile(&zoneFile); err != nil {
+		log.Fatalf("Failed to write zone file: %v", err)
+	}
+	fmt.Printf("Generated %s zone with %d domains and %d records (seed %d)\n", zone.TLD, ds.Domains(), ds.Records(), *seed)
+	batchSize := config.Zones.BatchSize
+	size := fmt.Sprintf("domains=%d", *domains)
+
+	ok := benchmark{"BenchmarkParseZone/" + size, func(b *testing.B) {
+		czds.ParseZoneBench(b, zoneFile.Bytes(), zone.TLD, batchSize)
+	}}.run(*count)
+
+	if *database == "" {
+		fmt.Println("Skipping database benchmarks; set -database to run them")
+	} else {
+		db := openScratch(config, *database)
+		defer db.Close()
+		if _, err := db.Exec("INSERT INTO api_keys (api_key, description) VALUES ($1, 'bench') ON CONFLICT DO NOTHING", benchAPIKey); err != nil {
+			log.Fatalf("Failed to create benchmark API key: %v", err)
+		}
+
+		ok = benchmark{"BenchmarkStoreRecords/" + size, func(b *testing.B) {
+			czds.StoreRecordsBench(b, db, zoneFile.Bytes(), zone.TLD, batchSize)
+		}}.run(*count) && ok
+		// What the query worker would have resolved for the stored domains
+		if err := zone.Store(db, "QUERY"); err != nil {
+			log.Fatalf("Failed to load resolved records: %v", err)
+		}
+		names := make([]string, len(zone.Domains))
+		for i, d := range zone.Domains {
+			names[i] = d.Name
+		}
+		getRecords, restore := server.NewGetRecordsBench(config, db, benchAPIKey)
+		for _, merged := range []bool{false, true} {
+			ok = benchmark{fmt.Sprintf("BenchmarkGetRecords/%s/merged=%t", size, merged), func(b *testing.B) {
+				benchmarkGetRecords(b, getRecords, names, merged)
+			}}.run(*count) && ok
+		}
+		restore()
+	}
+	if !ok {
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+}
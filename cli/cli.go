@@ -0,0 +1,481 @@
+// Package cli implements bell-cli, a command-line tool for analysts to query
+// the DNS service without writing Go. It is built on the client package.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moos3/bell/client"
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/logging"
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// logger logs bell-cli's diagnostics (see the logging package); results and
+// usage go to standard output and error as they are.
+var logger = logging.New("cli")
+
+// errUsage is returned by a subcommand when its arguments are invalid.
+var errUsage = errors.New("invalid arguments")
+
+// command is a bell-cli subcommand.
+type command struct {
+	usage string
+	run   func(g *globalFlags, args []string) error
+}
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]command{
+	"lookup":           {"lookup [-type A,MX] [-merged] <domain>...", runLookup},
+	"watch":            {"watch [-type A,MX] [-interval 1m] <domain>", runWatch},
+	"export":           {"export [-type A,MX] [-file domains.txt] [-out records.csv] [domain...]", runExport},
+	"import-watchlist": {"import-watchlist [-format csv|stix] [-replace] [-dry-run] <file>", runImportWatchlist},
+	"search":           {"search [-records] [-type A,MX] [-tld com] [-i] [-limit 100] <pattern>", runSearch},
+	"completion":       {"completion bash|zsh", runCompletion},
+}
+
+// globalFlags are accepted by every subcommand.
+type globalFlags struct {
+	server  string        // gRPC server address
+	apiKey  string        // API key; falls back to BELL_API_KEY and the OS keyring
+	output  string        // Output format: table, json, or csv
+	timeout time.Duration // Per-request timeout
+
+	tls      bool   // Connect over TLS
+	caFile   string // CA bundle verifying the server; system roots if empty
+	certFile string // Client certificate for mutual TLS
+	keyFile  string
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	server := os.Getenv("BELL_SERVER")
+	if server == "" {
+		server = "localhost:50051"
+	}
+	fs.StringVar(&g.server, "server", server, "gRPC server address (env BELL_SERVER)")
+	fs.StringVar(&g.apiKey, "api-key", "", "API key (default: env BELL_API_KEY, then the OS keyring)")
+	fs.StringVar(&g.output, "o", "table", "Output format: table, json, or csv")
+	fs.DurationVar(&g.timeout, "timeout", 30*time.Second, "Request timeout")
+	fs.BoolVar(&g.tls, "tls", os.Getenv("BELL_TLS") != "", "Connect over TLS (env BELL_TLS); implied by -ca, -cert and -key")
+	fs.StringVar(&g.caFile, "ca", "", "PEM CA bundle verifying the server (default: system roots)")
+	fs.StringVar(&g.certFile, "cert", "", "PEM client certificate for servers requiring mutual TLS")
+	fs.StringVar(&g.keyFile, "key", "", "PEM private key of -cert")
+}
+
+// resolveAPIKey returns the API key from the flag, the BELL_API_KEY
+// environment variable, or the OS keyring (service "bell"), in that order.
+func (g *globalFlags) resolveAPIKey() (string, error) {
+	if g.apiKey != "" {
+		return g.apiKey, nil
+	}
+	if key := os.Getenv("BELL_API_KEY"); key != "" {
+		return key, nil
+	}
+	key, err := keyringAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("no API key: set -api-key, BELL_API_KEY, or store one in the OS keyring under service \"bell\" (%v)", err)
+	}
+	return key, nil
+}
+
+// keyringAPIKey reads the API key from the OS keyring using the platform's
+// command-line tool: security(1) on macOS and secret-tool(1) elsewhere.
+func keyringAPIKey() (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", "bell", "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", "bell")
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("keyring lookup failed: %v", err)
+	}
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("keyring entry is empty")
+	}
+	return key, nil
+}
+
+// connect parses the subcommand flags and returns a connected client and API key.
+func (g *globalFlags) connect() (*client.Client, string, error) {
+	if _, ok := formatters[g.output]; !ok {
+		return nil, "", fmt.Errorf("invalid output format %s; must be table, json, or csv", g.output)
+	}
+	apiKey, err := g.resolveAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	var c *client.Client
+	if g.tls || g.caFile != "" || g.certFile != "" || g.keyFile != "" {
+		c, err = client.NewTLSClient(g.server, g.caFile, g.certFile, g.keyFile)
+	} else {
+		c, err = client.NewClient(g.server)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return c, apiKey, nil
+}
+
+// splitTypes parses a comma-separated record type list.
+func splitTypes(types string) []string {
+	var out []string
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func runLookup(g *globalFlags, args []string) error {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	g.register(fs)
+	types := fs.String("type", "", "Comma-separated record types to return (default all)")
+	merged := fs.Bool("merged", false, "Return one authoritative source per record type")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	c, apiKey, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var rows []recordRow
+	for _, domain := range fs.Args() {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		var records []*pb.DNSRecord
+		if *merged {
+			records, _, err = c.GetMergedRecords(ctx, apiKey, domain, splitTypes(*types))
+		} else {
+			records, err = c.GetRecords(ctx, apiKey, domain, splitTypes(*types))
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+		rows = append(rows, toRows(domain, records)...)
+	}
+	return formatters[g.output](os.Stdout, rows)
+}
+
+func runWatch(g *globalFlags, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	g.register(fs)
+	types := fs.String("type", "", "Comma-separated record types to watch (default all)")
+	interval := fs.Duration("interval", time.Minute, "Polling interval")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	domain := fs.Arg(0)
+	c, apiKey, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var previous map[string]recordRow
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		records, err := c.GetRecords(ctx, apiKey, domain, splitTypes(*types))
+		cancel()
+		if err != nil {
+			logger.Errorf("Failed to get records of %s: %v", domain, err)
+		} else {
+			current := make(map[string]recordRow)
+			for _, row := range toRows(domain, records) {
+				current[row.key()] = row
+			}
+			if previous == nil {
+				formatters[g.output](os.Stdout, toRows(domain, records))
+			} else {
+				printChanges(previous, current)
+			}
+			previous = current
+		}
+		time.Sleep(*interval)
+	}
+}
+
+// printChanges prints added (+) and removed (-) records between two polls.
+func printChanges(previous, current map[string]recordRow) {
+	now := time.Now().Format(time.RFC3339)
+	var lines []string
+	for k, row := range current {
+		if _, ok := previous[k]; !ok {
+			lines = append(lines, fmt.Sprintf("%s + %s %s %s", now, row.Domain, row.Type, row.Data))
+		}
+	}
+	for k, row := range previous {
+		if _, ok := current[k]; !ok {
+			lines = append(lines, fmt.Sprintf("%s - %s %s %s", now, row.Domain, row.Type, row.Data))
+		}
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+// exportBatchSize is the number of domains export fetches per call, the
+// most GetRecordsBatch accepts.
+const exportBatchSize = 500
+
+func runExport(g *globalFlags, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	g.register(fs)
+	types := fs.String("type", "", "Comma-separated record types to export (default all)")
+	file := fs.String("file", "", "File with one domain per line (- for stdin)")
+	out := fs.String("out", "", "Output file (default stdout)")
+	fs.Parse(args)
+
+	domains := fs.Args()
+	if *file != "" {
+		fileDomains, err := readDomains(*file)
+		if err != nil {
+			return err
+		}
+		domains = append(domains, fileDomains...)
+	}
+	if len(domains) == 0 {
+		return errUsage
+	}
+	c, apiKey, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	// All domains are read from the same snapshot for a consistent export
+	var rows []recordRow
+	var snapshot string
+	for start := 0; start < len(domains); start += exportBatchSize {
+		batch := domains[start:min(start+exportBatchSize, len(domains))]
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		var records map[string][]*pb.DNSRecord
+		records, snapshot, err = c.GetRecordsBatch(ctx, apiKey, batch, splitTypes(*types), snapshot)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, domain := range batch {
+			rows = append(rows, toRows(domain, records[domain])...)
+		}
+		if *out != "" {
+			fmt.Fprintf(os.Stderr, "\rExported %d/%d domains", start+len(batch), len(domains))
+		}
+	}
+	if *out != "" {
+		fmt.Fprintln(os.Stderr)
+	}
+	return formatters[g.output](w, rows)
+}
+
+// searchPageSize is the number of domains search fetches per
+// SearchDomains call, the most the server returns.
+const searchPageSize = 1000
+
+func runSearch(g *globalFlags, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	g.register(fs)
+	records := fs.Bool("records", false, "Match the pattern, a POSIX regular expression, against record data instead of domain names, a glob such as paypal*.com")
+	types := fs.String("type", "", "Comma-separated record types to search with -records (default the key's, else all)")
+	tld := fs.String("tld", "", "Search only this TLD's domains with -records")
+	insensitive := fs.Bool("i", false, "Match case-insensitively with -records")
+	limit := fs.Int("limit", 0, "Maximum results (default the key's max_rows, else the server's cap)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	pattern := fs.Arg(0)
+	c, apiKey, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if *records {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		defer cancel()
+		resp, err := c.SearchRecords(ctx, apiKey, pattern, *tld, splitTypes(*types), *insensitive, int32(*limit))
+		if err != nil {
+			return err
+		}
+		rows := make([]recordRow, 0, len(resp.Records))
+		for _, r := range resp.Records {
+			rows = append(rows, toRows(r.Domain, []*pb.DNSRecord{r.Record})...)
+		}
+		if resp.Truncated {
+			fmt.Fprintf(os.Stderr, "Results stopped at the limit of %d records\n", len(rows))
+		}
+		if resp.TimedOut {
+			fmt.Fprintln(os.Stderr, "The search timed out; results are partial")
+		}
+		if len(resp.SkippedShards) > 0 {
+			fmt.Fprintf(os.Stderr, "Unhealthy shards left out: %s\n", strings.Join(resp.SkippedShards, ", "))
+		}
+		return formatters[g.output](os.Stdout, rows)
+	}
+
+	var matches []*pb.DomainMatch
+	var token string
+	for {
+		pageSize := int32(searchPageSize)
+		if *limit > 0 {
+			pageSize = int32(min(*limit-len(matches), searchPageSize))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		page, next, err := c.SearchDomains(ctx, apiKey, pattern, pageSize, token)
+		cancel()
+		if err != nil {
+			return err
+		}
+		matches = append(matches, page...)
+		if next == "" || (*limit > 0 && len(matches) >= *limit) {
+			break
+		}
+		token = next
+	}
+	return domainFormatters[g.output](os.Stdout, toDomainRows(matches))
+}
+
+func runImportWatchlist(g *globalFlags, args []string) error {
+	fs := flag.NewFlagSet("import-watchlist", flag.ExitOnError)
+	g.register(fs)
+	format := fs.String("format", "", "File format: csv or stix (default stix for .json files, else csv)")
+	replace := fs.Bool("replace", false, "Replace the organization watchlist instead of adding to it")
+	dryRun := fs.Bool("dry-run", false, "Validate the file without changing the watchlist")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = "csv"
+		if strings.HasSuffix(strings.ToLower(path), ".json") {
+			*format = "stix"
+		}
+	}
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	c, apiKey, err := g.connect()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	resp, err := c.ImportWatchlist(ctx, apiKey, strings.ToUpper(*format), string(content), *replace, *dryRun)
+	if err != nil {
+		return err
+	}
+	for _, r := range resp.Rejected {
+		fmt.Fprintf(os.Stderr, "%s: %q rejected: %s\n", r.Location, r.Value, r.Reason)
+	}
+	if int(resp.RejectedCount) > len(resp.Rejected) {
+		fmt.Fprintf(os.Stderr, "... and %d more rejected entries\n", int(resp.RejectedCount)-len(resp.Rejected))
+	}
+	verb := "Added"
+	if *dryRun {
+		verb = "Would add"
+	}
+	fmt.Printf("%s %d terms (%d duplicates, %d rejected); the watchlist has %d terms\n",
+		verb, resp.Added, resp.Duplicates, resp.RejectedCount, len(resp.Organization.GetWatchlist()))
+	return nil
+}
+
+// readDomains reads one domain per line from path, skipping blanks and # comments.
+func readDomains(path string) ([]string, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", path, err)
+		}
+		defer f.Close()
+	}
+	var domains []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			domains = append(domains, line)
+		}
+	}
+	return domains, scanner.Err()
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: bell-cli <command> [flags]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun bell-cli <command> -h for command flags.")
+}
+
+func main() {
+	// bell-cli has no configuration file; API keys in its diagnostics are
+	// hashed as in the service's logs
+	var cfg config.Config
+	cfg.Logging.Level, cfg.Logging.APIKeys = "info", "hash"
+	if err := logging.Setup(&cfg); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	err := cmd.run(&globalFlags{}, os.Args[2:])
+	if err == errUsage {
+		fmt.Fprintf(os.Stderr, "Usage: bell-cli %s\n", cmd.usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bell-cli: %s\n", logging.Redact(err.Error()))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+)
+
+const bashCompletion = `# bash completion for bell-cli
+# Install: bell-cli completion bash > /etc/bash_completion.d/bell-cli
+_bell_cli() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    local prev=${COMP_WORDS[COMP_CWORD-1]}
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "lookup watch export import-watchlist search completion" -- "$cur"))
+        return
+    fi
+    case "$prev" in
+        -o) COMPREPLY=($(compgen -W "table json csv" -- "$cur")); return ;;
+        -format) COMPREPLY=($(compgen -W "csv stix" -- "$cur")); return ;;
+        -file|-out) COMPREPLY=($(compgen -f -- "$cur")); return ;;
+        completion) COMPREPLY=($(compgen -W "bash zsh" -- "$cur")); return ;;
+    esac
+    case "${COMP_WORDS[1]}" in
+        lookup) COMPREPLY=($(compgen -W "-server -api-key -o -timeout -tls -ca -cert -key -type -merged" -- "$cur")) ;;
+        watch) COMPREPLY=($(compgen -W "-server -api-key -o -timeout -tls -ca -cert -key -type -interval" -- "$cur")) ;;
+        export) COMPREPLY=($(compgen -W "-server -api-key -o -timeout -tls -ca -cert -key -type -file -out" -- "$cur")) ;;
+        import-watchlist) COMPREPLY=($(compgen -W "-server -api-key -o -timeout -tls -ca -cert -key -format -replace -dry-run" -- "$cur") $(compgen -f -- "$cur")) ;;
+        search) COMPREPLY=($(compgen -W "-server -api-key -o -timeout -tls -ca -cert -key -records -type -tld -i -limit" -- "$cur")) ;;
+    esac
+}
+complete -F _bell_cli bell-cli
+`
+
+const zshCompletion = `#compdef bell-cli
+# Install: bell-cli completion zsh > "${fpath[1]}/_bell-cli"
+_bell_cli() {
+    local -a common
+    common=(
+        '-server[gRPC server address]:address:'
+        '-api-key[API key]:key:'
+        '-o[output format]:format:(table json csv)'
+        '-timeout[request timeout]:duration:'
+        '-tls[connect over TLS]'
+        '-ca[CA bundle]:file:_files'
+        '-cert[client certificate]:file:_files'
+        '-key[client key]:file:_files'
+        '-type[comma-separated record types]:types:'
+    )
+    if (( CURRENT == 2 )); then
+        _values 'command' lookup watch export import-watchlist search completion
+        return
+    fi
+    case "$words[2]" in
+        lookup) _arguments $common '-merged[one source per record type]' '*:domain:' ;;
+        watch) _arguments $common '-interval[polling interval]:duration:' ':domain:' ;;
+        export) _arguments $common '-file[domain list]:file:_files' '-out[output file]:file:_files' '*:domain:' ;;
+        import-watchlist) _arguments $common '-format[file format]:format:(csv stix)' '-replace[replace the watchlist]' '-dry-run[validate only]' ':file:_files' ;;
+        search) _arguments $common '-records[match record data]' '-tld[TLD to search]:tld:' '-i[case-insensitive]' '-limit[maximum results]:count:' ':pattern:' ;;
+        completion) _values 'shell' bash zsh ;;
+    esac
+}
+_bell_cli "$@"
+`
+
+func runCompletion(g *globalFlags, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	default:
+		return fmt.Errorf("unsupported shell %s; must be bash or zsh", args[0])
+	}
+	return nil
+}
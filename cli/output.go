@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// recordRow is a flattened DNS record as printed by bell-cli.
+type recordRow struct {
+	Domain      string `json:"domain"`
+	Type        string `json:"type"`
+	Data        string `json:"data"`
+	TTL         int32  `json:"ttl"`
+	Source      string `json:"source"`
+	LastUpdated string `json:"last_updated"`
+	FirstSeen   string `json:"first_seen"` // By any source
+	LastSeen    string `json:"last_seen"`  // By any source
+}
+
+// key identifies a record independent of TTL and observation time.
+func (r recordRow) key() string {
+	return r.Domain + "\x00" + r.Type + "\x00" + r.Data + "\x00" + r.Source
+}
+
+func toRows(domain string, records []*pb.DNSRecord) []recordRow {
+	rows := make([]recordRow, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, recordRow{
+			Domain:      domain,
+			Type:        r.RecordType,
+			Data:        r.RecordData,
+			TTL:         r.Ttl,
+			Source:      r.Source,
+			LastUpdated: r.LastUpdated,
+			FirstSeen:   r.FirstSeen,
+			LastSeen:    r.LastSeen,
+		})
+	}
+	return rows
+}
+
+// domainRow is a domain matched by search, as printed by bell-cli.
+type domainRow struct {
+	Domain      string `json:"domain"`
+	TLD         string `json:"tld"`
+	FirstSeen   string `json:"first_seen"`
+	LastUpdated string `json:"last_updated"`
+}
+
+func toDomainRows(matches []*pb.DomainMatch) []domainRow {
+	rows := make([]domainRow, 0, len(matches))
+	for _, m := range matches {
+		rows = append(rows, domainRow{Domain: m.Domain, TLD: m.Tld, FirstSeen: m.FirstSeen, LastUpdated: m.LastUpdated})
+	}
+	return rows
+}
+
+// formatters write rows in each supported output format.
+var formatters = map[string]func(io.Writer, []recordRow) error{
+	"table": writeTable,
+	"json":  writeJSON,
+	"csv":   writeCSV,
+}
+
+var header = []string{"DOMAIN", "TYPE", "DATA", "TTL", "SOURCE", "LAST_UPDATED", "FIRST_SEEN", "LAST_SEEN"}
+
+func writeTable(w io.Writer, rows []recordRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, h := range header {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, h)
+	}
+	fmt.Fprintln(tw)
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", r.Domain, r.Type, r.Data, r.TTL, r.Source, r.LastUpdated, r.FirstSeen, r.LastSeen)
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, rows []recordRow) error {
+	if rows == nil {
+		rows = []recordRow{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func writeCSV(w io.Writer, rows []recordRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"domain", "type", "data", "ttl", "source", "last_updated", "first_seen", "last_seen"})
+	for _, r := range rows {
+		cw.Write([]string{r.Domain, r.Type, r.Data, strconv.Itoa(int(r.TTL)), r.Source, r.LastUpdated, r.FirstSeen, r.LastSeen})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// domainFormatters write domain rows in each output format of formatters.
+var domainFormatters = map[string]func(io.Writer, []domainRow) error{
+	"table": writeDomainTable,
+	"json":  writeDomainJSON,
+	"csv":   writeDomainCSV,
+}
+
+func writeDomainTable(w io.Writer, rows []domainRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DOMAIN\tTLD\tFIRST_SEEN\tLAST_UPDATED")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Domain, r.TLD, r.FirstSeen, r.LastUpdated)
+	}
+	return tw.Flush()
+}
+
+func writeDomainJSON(w io.Writer, rows []domainRow) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func writeDomainCSV(w io.Writer, rows []domainRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"domain", "tld", "first_seen", "last_updated"})
+	for _, r := range rows {
+		cw.Write([]string{r.Domain, r.TLD, r.FirstSeen, r.LastUpdated})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -4,11 +4,16 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 
 	pb "github.com/moos3/bell/pb/bell/v1"
@@ -16,20 +21,53 @@ import (
 
 // Client encapsulates a gRPC client for the DNS service.
 type Client struct {
-	conn   *grpc.ClientConn    // gRPC connection to the server
-	client pb.DNSServiceClient // DNS service client interface
+	conn   *grpc.ClientConn      // gRPC connection to the server
+	client pb.DNSServiceClient   // DNS service client interface
+	admin  pb.AdminServiceClient // API key management; admin keys only
 }
 
 // NewClient initializes a new DNS service client connected to the specified server address.
+// Options such as WithHooks adjust its calls.
 //
 // It returns a Client instance or an error if the connection fails.
-func NewClient(serverAddr string) (*Client, error) {
-	conn, err := grpc.Dial(serverAddr, grpc.WithInsecure())
+func NewClient(serverAddr string, opts ...Option) (*Client, error) {
+	conn, err := grpc.Dial(serverAddr, append(dialOptions(opts), grpc.WithInsecure())...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to server at %s: %v", serverAddr, err)
+		return nil, fmt.Errorf("failed to connect to server at %s: %w", serverAddr, err)
 	}
 	client := pb.NewDNSServiceClient(conn)
-	return &Client{conn: conn, client: client}, nil
+	return &Client{conn: conn, client: client, admin: pb.NewAdminServiceClient(conn)}, nil
+}
+
+// NewTLSClient is NewClient over TLS. The server's certificate is verified
+// against the PEM bundle caFile, or the system roots if caFile is empty. If
+// certFile and keyFile are set, their certificate is presented to servers
+// requiring mutual TLS.
+func NewTLSClient(serverAddr, caFile, certFile, keyFile string, opts ...Option) (*Client, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %s: %w", caFile, err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA bundle %s", caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %s: %w", certFile, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	conn, err := grpc.Dial(serverAddr, append(dialOptions(opts), grpc.WithTransportCredentials(credentials.NewTLS(cfg)))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server at %s: %w", serverAddr, err)
+	}
+	client := pb.NewDNSServiceClient(conn)
+	return &Client{conn: conn, client: client, admin: pb.NewAdminServiceClient(conn)}, nil
 }
 
 // Close closes the gRPC client connection.
@@ -46,7 +84,7 @@ func (c *Client) Authenticate(ctx context.Context, apiKey string) (bool, string,
 	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
 	resp, err := c.client.Authenticate(ctx, &pb.AuthenticateRequest{ApiKey: apiKey})
 	if err != nil {
-		return false, "", fmt.Errorf("authentication failed: %v", err)
+		return false, "", fmt.Errorf("authentication failed: %w", err)
 	}
 	return resp.Valid, resp.Message, nil
 }
@@ -64,11 +102,891 @@ func (c *Client) GetRecords(ctx context.Context, apiKey, domain string, recordTy
 		RecordType: recordTypes,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch records for %s: %v", domain, err)
+		return nil, fmt.Errorf("failed to fetch records for %s: %w", domain, err)
 	}
 	return resp.Records, nil
 }
 
+// GetRecordsBatch fetches the DNS records of up to 500 domains in one call,
+// keyed by domain; every requested domain has an entry, empty if it has no
+// records. Like GetRecordsAt, it reads the snapshot of snapshotToken, or
+// starts a new one if it is empty, and returns the snapshot's token.
+func (c *Client) GetRecordsBatch(ctx context.Context, apiKey string, domains, recordTypes []string, snapshotToken string) (map[string][]*pb.DNSRecord, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecordsBatch(ctx, &pb.GetRecordsBatchRequest{
+		Domains:       domains,
+		RecordType:    recordTypes,
+		SnapshotToken: snapshotToken,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch records for %d domains: %w", len(domains), err)
+	}
+	records := make(map[string][]*pb.DNSRecord, len(resp.Results))
+	for domain, result := range resp.Results {
+		records[domain] = result.Records
+	}
+	return records, resp.SnapshotToken, nil
+}
+
+// GetRecordsMatching fetches the DNS records of the domains matching
+// patterns such as *.example.com or login.*.bank, at most maxExpansion per
+// pattern (0 for the server's limit), keyed by domain, and what each
+// pattern expanded to. Patterns matching too much fail with reason
+// PATTERN_TOO_BROAD.
+func (c *Client) GetRecordsMatching(ctx context.Context, apiKey string, patterns, recordTypes []string, maxExpansion int32) (map[string][]*pb.DNSRecord, []*pb.PatternExpansion, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecordsBatch(ctx, &pb.GetRecordsBatchRequest{
+		Domains:      patterns,
+		RecordType:   recordTypes,
+		MaxExpansion: maxExpansion,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch records matching %v: %w", patterns, err)
+	}
+	records := make(map[string][]*pb.DNSRecord, len(resp.Results))
+	for domain, result := range resp.Results {
+		records[domain] = result.Records
+	}
+	return records, resp.Expansions, nil
+}
+
+// GetRecordsAt fetches DNS records for a domain as of a snapshot. Pass an
+// empty token to start a new snapshot, then pass the returned token to later
+// calls so they see the same view of the data while ingestion continues.
+func (c *Client) GetRecordsAt(ctx context.Context, apiKey, domain string, recordTypes []string, snapshotToken string) ([]*pb.DNSRecord, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecords(ctx, &pb.GetRecordsRequest{
+		Domain:        domain,
+		RecordType:    recordTypes,
+		SnapshotToken: snapshotToken,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch records for %s: %w", domain, err)
+	}
+	return resp.Records, resp.SnapshotToken, nil
+}
+
+// GetRecordHistory fetches the timeline of the values a record type of a
+// domain has had, oldest first, optionally from one source.
+func (c *Client) GetRecordHistory(ctx context.Context, apiKey, domain, recordType, source string) ([]*pb.RecordHistoryEntry, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecordHistory(ctx, &pb.GetRecordHistoryRequest{Domain: domain, RecordType: recordType, Source: source})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s history for %s: %w", recordType, domain, err)
+	}
+	return resp.Entries, nil
+}
+
+// GetRecordsIfChanged fetches DNS records for a domain unless its record
+// sets are still at knownVersion, the version returned by an earlier call
+// (0 to always fetch). It returns the records and their current version, or
+// nil records and modified false when nothing changed.
+func (c *Client) GetRecordsIfChanged(ctx context.Context, apiKey, domain string, recordTypes []string, knownVersion int64) (records []*pb.DNSRecord, version int64, modified bool, err error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecords(ctx, &pb.GetRecordsRequest{
+		Domain:       domain,
+		RecordType:   recordTypes,
+		KnownVersion: knownVersion,
+	})
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to fetch records for %s: %w", domain, err)
+	}
+	return resp.Records, resp.Version, !resp.NotModified, nil
+}
+
+// GetMergedRecords fetches DNS records for a domain with source conflicts
+// resolved by the server's merge policy.
+//
+// It returns one authoritative source per record type along with provenance
+// describing which source was chosen and why.
+func (c *Client) GetMergedRecords(ctx context.Context, apiKey, domain string, recordTypes []string) ([]*pb.DNSRecord, []*pb.MergeProvenance, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecords(ctx, &pb.GetRecordsRequest{
+		Domain:     domain,
+		RecordType: recordTypes,
+		Merged:     true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch merged records for %s: %w", domain, err)
+	}
+	return resp.Records, resp.Provenance, nil
+}
+
+// ListTLDs fetches the ingestion status of every TLD loaded by the DNS service.
+func (c *Client) ListTLDs(ctx context.Context, apiKey string) ([]*pb.TLDStatus, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListTLDs(ctx, &pb.ListTLDsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TLDs: %w", err)
+	}
+	return resp.Tlds, nil
+}
+
+// GetTLDStatus fetches the ingestion status of a single TLD.
+func (c *Client) GetTLDStatus(ctx context.Context, apiKey, tld string) (*pb.TLDStatus, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetTLDStatus(ctx, &pb.GetTLDStatusRequest{Tld: tld})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status for TLD %s: %w", tld, err)
+	}
+	return resp.Status, nil
+}
+
+// GetTopN fetches a precomputed ranking for metric, for a TLD or globally if
+// tld is empty. A limit of 0 returns all stored entries.
+func (c *Client) GetTopN(ctx context.Context, apiKey string, metric pb.TopNMetric, tld string, limit int32) ([]*pb.TopNEntry, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetTopN(ctx, &pb.GetTopNRequest{Metric: metric, Tld: tld, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top %s: %w", metric, err)
+	}
+	return resp.Entries, nil
+}
+
+// GetTTLStats fetches the TTL distribution and recent TTL anomalies for a
+// domain or a TLD (exactly one must be set), optionally filtered by record type.
+func (c *Client) GetTTLStats(ctx context.Context, apiKey, domain, tld, recordType string) (*pb.GetTTLStatsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetTTLStats(ctx, &pb.GetTTLStatsRequest{Domain: domain, Tld: tld, RecordType: recordType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TTL stats: %w", err)
+	}
+	return resp, nil
+}
+
+// CheckDomains reports which of the given domains (at most 1000) exist in
+// the DNS service, in request order.
+func (c *Client) CheckDomains(ctx context.Context, apiKey string, domains []string) ([]*pb.DomainPresence, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.CheckDomains(ctx, &pb.CheckDomainsRequest{Domains: domains})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check domains: %w", err)
+	}
+	return resp.Results, nil
+}
+
+// CountDomains counts the domains matching req. The count is an estimate
+// when the response's Estimated is set.
+func (c *Client) CountDomains(ctx context.Context, apiKey string, req *pb.CountDomainsRequest) (*pb.CountResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.CountDomains(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count domains: %w", err)
+	}
+	return resp, nil
+}
+
+// CountRecords counts the DNS records matching req. The count is an
+// estimate when the response's Estimated is set.
+func (c *Client) CountRecords(ctx context.Context, apiKey string, req *pb.CountRecordsRequest) (*pb.CountResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.CountRecords(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count records: %w", err)
+	}
+	return resp, nil
+}
+
+// StartExport queues an export of the domains or records matching req.
+// Poll GetExport until its status is SUCCEEDED or FAILED.
+func (c *Client) StartExport(ctx context.Context, apiKey string, req *pb.StartExportRequest) (*pb.ExportJob, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	job, err := c.client.StartExport(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start export: %w", err)
+	}
+	return job, nil
+}
+
+// GetExport fetches an export of apiKey, with a fresh signed download URL
+// once it has succeeded.
+func (c *Client) GetExport(ctx context.Context, apiKey string, id int32) (*pb.ExportJob, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	job, err := c.client.GetExport(ctx, &pb.GetExportRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export %d: %w", id, err)
+	}
+	return job, nil
+}
+
+// ListExports fetches the recent exports of apiKey, newest first.
+func (c *Client) ListExports(ctx context.Context, apiKey string) ([]*pb.ExportJob, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListExports(ctx, &pb.ListExportsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exports: %w", err)
+	}
+	return resp.Exports, nil
+}
+
+// GetAbuseContacts fetches the registrar and abuse contacts for a domain.
+// Set refresh to query RDAP even if the server has fresh cached contacts.
+func (c *Client) GetAbuseContacts(ctx context.Context, apiKey, domain string, refresh bool) (*pb.GetAbuseContactsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetAbuseContacts(ctx, &pb.GetAbuseContactsRequest{Domain: domain, Refresh: refresh})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get abuse contacts for %s: %w", domain, err)
+	}
+	return resp, nil
+}
+
+// GetDomainLifecycle fetches when a domain was first seen, its nameserver
+// and record-set changes and its detected registrar transfers, oldest
+// first. significances optionally limits the changes (e.g. NS_REPLACED,
+// ASN_CHANGED); nil returns them all.
+func (c *Client) GetDomainLifecycle(ctx context.Context, apiKey, domain string, significances []string) (*pb.GetDomainLifecycleResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetDomainLifecycle(ctx, &pb.GetDomainLifecycleRequest{Domain: domain, Significance: significances})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lifecycle of %s: %w", domain, err)
+	}
+	return resp, nil
+}
+
+// SetLogLevel changes the server log level at runtime (debug, info, warn, or
+// error), or the level of one module such as "server" if module is set, and
+// returns the new and previous levels. An empty level only reports the
+// current level. It requires an admin API key.
+func (c *Client) SetLogLevel(ctx context.Context, apiKey, module, level string) (string, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.SetLogLevel(ctx, &pb.SetLogLevelRequest{Level: level, Module: module})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to set log level: %w", err)
+	}
+	return resp.Level, resp.PreviousLevel, nil
+}
+
+// RefreshDomain resolves domain at its authoritative nameservers and stores
+// the answers, sending clientSubnet (an address or CIDR block) as EDNS
+// Client Subnet unless it is empty. Answers that failed are in errors.
+func (c *Client) RefreshDomain(ctx context.Context, apiKey, domain string, recordTypes []string, clientSubnet string) (answers []*pb.GeoAnswer, errors []string, err error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.RefreshDomain(ctx, &pb.RefreshDomainRequest{Domain: domain, RecordType: recordTypes, ClientSubnet: clientSubnet})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to refresh %s: %w", domain, err)
+	}
+	return resp.Answers, resp.Errors, nil
+}
+
+// GetGeoAnswers returns the answers RefreshDomain stored for domain, by
+// record type and client subnet.
+func (c *Client) GetGeoAnswers(ctx context.Context, apiKey, domain string, recordTypes []string) ([]*pb.GeoAnswer, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecords(ctx, &pb.GetRecordsRequest{Domain: domain, RecordType: recordTypes, IncludeGeoAnswers: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch geo answers for %s: %w", domain, err)
+	}
+	return resp.GeoAnswers, nil
+}
+
+// GetDomainsByNameserver returns a page of the domains delegated to a
+// nameserver host and the token of the next page, empty on the last one.
+// pageSize 0 uses the key's default.
+func (c *Client) GetDomainsByNameserver(ctx context.Context, apiKey, nameserver string, pageSize int32, pageToken string) ([]*pb.DelegatedDomain, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetDomainsByNameserver(ctx, &pb.GetDomainsByNameserverRequest{Nameserver: nameserver, PageSize: pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get domains of nameserver %s: %w", nameserver, err)
+	}
+	return resp.Domains, resp.NextPageToken, nil
+}
+
+// ListDomainsByTLD returns a page of the domains of a TLD or public suffix,
+// optionally first seen within a range; pass the response's next_page_token
+// in the next request until it is empty.
+func (c *Client) ListDomainsByTLD(ctx context.Context, apiKey string, req *pb.ListDomainsByTLDRequest) (*pb.ListDomainsByTLDResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListDomainsByTLD(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains of %s: %w", req.Tld, err)
+	}
+	return resp, nil
+}
+
+// ListSubdomains returns a page of the domains under apex and the token of
+// the next page, empty on the last one. pageSize 0 uses the key's default.
+func (c *Client) ListSubdomains(ctx context.Context, apiKey, apex string, pageSize int32, pageToken string) ([]*pb.Subdomain, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListSubdomains(ctx, &pb.ListSubdomainsRequest{Apex: apex, PageSize: pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list subdomains of %s: %w", apex, err)
+	}
+	return resp.Subdomains, resp.NextPageToken, nil
+}
+
+// SearchDomains returns a page of the domains matching pattern, a glob such
+// as *bank* or paypal*.com, and the token of the next page, empty on the last
+// one. pageSize 0 uses the key's default.
+func (c *Client) SearchDomains(ctx context.Context, apiKey, pattern string, pageSize int32, pageToken string) ([]*pb.DomainMatch, string, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.SearchDomains(ctx, &pb.SearchDomainsRequest{Pattern: pattern, PageSize: pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search domains: %w", err)
+	}
+	return resp.Domains, resp.NextPageToken, nil
+}
+
+// SearchRecords returns the records whose data matches pattern, a POSIX
+// extended regular expression, optionally within one TLD and some record
+// types. The response says whether the server cut the search short.
+func (c *Client) SearchRecords(ctx context.Context, apiKey, pattern, tld string, recordTypes []string, caseInsensitive bool, limit int32) (*pb.SearchRecordsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.SearchRecords(ctx, &pb.SearchRecordsRequest{Pattern: pattern, Tld: tld, RecordType: recordTypes, CaseInsensitive: caseInsensitive, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search records: %w", err)
+	}
+	return resp, nil
+}
+
+// CreateAPIKey issues a new API key for owner; ttl 0 never expires. It
+// requires an admin API key.
+func (c *Client) CreateAPIKey(ctx context.Context, apiKey, owner, description, role string, ttl time.Duration) (*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	key, err := c.admin.CreateAPIKey(ctx, &pb.CreateAPIKeyRequest{Owner: owner, Description: description, Role: role, TtlSeconds: int64(ttl.Seconds())})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key for %s: %w", owner, err)
+	}
+	return key, nil
+}
+
+// RotateAPIKey replaces key with a new one, keeping the old one working
+// for grace. It requires an admin API key.
+func (c *Client) RotateAPIKey(ctx context.Context, apiKey, key string, grace time.Duration) (*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	rotated, err := c.admin.RotateAPIKey(ctx, &pb.RotateAPIKeyRequest{ApiKey: key, GraceSeconds: int64(grace.Seconds())})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate API key %s: %w", key, err)
+	}
+	return rotated, nil
+}
+
+// RevokeAPIKey deactivates key. It requires an admin API key.
+func (c *Client) RevokeAPIKey(ctx context.Context, apiKey, key string) (*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	revoked, err := c.admin.RevokeAPIKey(ctx, &pb.RevokeAPIKeyRequest{ApiKey: key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke API key %s: %w", key, err)
+	}
+	return revoked, nil
+}
+
+// SetAPIKeyQuota sets the monthly request and record quotas of key; 0
+// removes a quota. It requires an admin API key.
+func (c *Client) SetAPIKeyQuota(ctx context.Context, apiKey, key string, monthlyRequestQuota, monthlyRecordQuota int64) (*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	updated, err := c.admin.SetAPIKeyQuota(ctx, &pb.SetAPIKeyQuotaRequest{ApiKey: key, MonthlyRequestQuota: monthlyRequestQuota, MonthlyRecordQuota: monthlyRecordQuota})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set quota of API key %s: %w", key, err)
+	}
+	return updated, nil
+}
+
+// SetAPIKeyScopes replaces the scopes granted to key, such as read:records;
+// none leaves it with those of its role. It requires an admin API key.
+func (c *Client) SetAPIKeyScopes(ctx context.Context, apiKey, key string, scopes []string) (*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	updated, err := c.admin.SetAPIKeyScopes(ctx, &pb.SetAPIKeyScopesRequest{ApiKey: key, Scopes: scopes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set scopes of API key %s: %w", key, err)
+	}
+	return updated, nil
+}
+
+// ListAPIKeys returns the usable API keys, or all with includeInactive,
+// optionally only owner's. It requires an admin API key.
+func (c *Client) ListAPIKeys(ctx context.Context, apiKey, owner string, includeInactive bool) ([]*pb.APIKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.admin.ListAPIKeys(ctx, &pb.ListAPIKeysRequest{Owner: owner, IncludeInactive: includeInactive})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return resp.Keys, nil
+}
+
+// ListDNSServers returns the server's recursive upstreams and their health.
+// It requires an admin API key.
+func (c *Client) ListDNSServers(ctx context.Context, apiKey string) ([]*pb.DNSServer, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListDNSServers(ctx, &pb.ListDNSServersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DNS servers: %w", err)
+	}
+	return resp.Servers, nil
+}
+
+// UpdateDNSServers adds and removes recursive upstreams of the server and
+// query workers, written as in dns_query.dns_servers, and returns the
+// resulting set. It requires an admin API key.
+func (c *Client) UpdateDNSServers(ctx context.Context, apiKey string, add, remove []string) ([]*pb.DNSServer, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.UpdateDNSServers(ctx, &pb.UpdateDNSServersRequest{Add: add, Remove: remove})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update DNS servers: %w", err)
+	}
+	return resp.Servers, nil
+}
+
+// VerifyDomain compares a domain's record sets in the zone, the database and
+// live DNS. With no record types, NS, A, AAAA, MX, TXT and CNAME are checked.
+func (c *Client) VerifyDomain(ctx context.Context, apiKey, domain string, recordTypes []string) (*pb.VerifyDomainResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.VerifyDomain(ctx, &pb.VerifyDomainRequest{Domain: domain, RecordType: recordTypes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify domain: %w", err)
+	}
+	return resp, nil
+}
+
+// LookupLive resolves a domain live through the server's resolver without
+// storing the answers. With no record types, A and AAAA are looked up; opts
+// may be nil for a recursive lookup over the server's default transport.
+func (c *Client) LookupLive(ctx context.Context, apiKey, domain string, recordTypes []string, opts *pb.LiveLookupOptions) (*pb.LookupLiveResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.LookupLive(ctx, &pb.LookupLiveRequest{Domain: domain, RecordType: recordTypes, Options: opts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up domain: %w", err)
+	}
+	return resp, nil
+}
+
+// TraceResolution resolves a domain iteratively from the root and returns the
+// delegation chain. With no record type, A is traced.
+func (c *Client) TraceResolution(ctx context.Context, apiKey, domain, recordType string) (*pb.TraceResolutionResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.TraceResolution(ctx, &pb.TraceResolutionRequest{Domain: domain, RecordType: recordType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace domain: %w", err)
+	}
+	return resp, nil
+}
+
+// GetServiceRecords fetches the parsed SRV and NAPTR records of a name such
+// as _sip._tls.example.com. With no record types, both are returned.
+func (c *Client) GetServiceRecords(ctx context.Context, apiKey, name string, recordTypes []string) ([]*pb.ServiceRecord, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetServiceRecords(ctx, &pb.GetServiceRecordsRequest{Name: name, RecordType: recordTypes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service records: %w", err)
+	}
+	return resp.Records, nil
+}
+
+// ValidateDANE checks the live certificate chain of domain:port against its
+// stored TLSA records. A port of 0 means 443.
+func (c *Client) ValidateDANE(ctx context.Context, apiKey, domain string, port int32) (*pb.ValidateDANEResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ValidateDANE(ctx, &pb.ValidateDANERequest{Domain: domain, Port: port})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate DANE: %w", err)
+	}
+	return resp, nil
+}
+
+// GetPTR fetches the PTR records of an IPv4 or IPv6 address from ingested
+// reverse zones, or resolves them live if live is set.
+func (c *Client) GetPTR(ctx context.Context, apiKey, ip string, live bool) ([]*pb.PTRRecord, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetPTR(ctx, &pb.GetPTRRequest{Ip: ip, Live: live})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PTR records: %w", err)
+	}
+	return resp.Records, nil
+}
+
+// GetPTRRange fetches the PTR records of a CIDR block in address order. The
+// returned bool reports whether more than limit records exist.
+func (c *Client) GetPTRRange(ctx context.Context, apiKey, cidr string, limit int32) ([]*pb.PTRRecord, bool, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetPTRRange(ctx, &pb.GetPTRRangeRequest{Cidr: cidr, Limit: limit})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get PTR range: %w", err)
+	}
+	return resp.Records, resp.Truncated, nil
+}
+
+// GetKeywordTrends fetches the trending keywords in newly seen domain labels
+// for day (YYYY-MM-DD, or "" for the latest computed day), globally or for
+// one TLD. Zero baselineDays and limit use the server defaults.
+func (c *Client) GetKeywordTrends(ctx context.Context, apiKey, tld, day string, baselineDays, limit int32) (*pb.GetKeywordTrendsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetKeywordTrends(ctx, &pb.GetKeywordTrendsRequest{Tld: tld, Day: day, BaselineDays: baselineDays, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword trends: %w", err)
+	}
+	return resp, nil
+}
+
+// GetDNSSECAdoption fetches the daily share of signed delegations over the
+// last days computed days (0 for the server default) and the algorithms in
+// use, globally or for one TLD.
+func (c *Client) GetDNSSECAdoption(ctx context.Context, apiKey, tld string, days int32) (*pb.GetDNSSECAdoptionResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetDNSSECAdoption(ctx, &pb.GetDNSSECAdoptionRequest{Tld: tld, Days: days})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DNSSEC adoption: %w", err)
+	}
+	return resp, nil
+}
+
+// ListDomainClusters fetches the clusters of domains sharing infrastructure
+// with at least minSize domains (0 for any) and a domain first seen since
+// activeSince (RFC 3339, empty for any), most recently active first.
+func (c *Client) ListDomainClusters(ctx context.Context, apiKey string, minSize int32, activeSince string, limit int32) (*pb.ListDomainClustersResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListDomainClusters(ctx, &pb.ListDomainClustersRequest{MinSize: minSize, ActiveSince: activeSince, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domain clusters: %w", err)
+	}
+	return resp, nil
+}
+
+// GetDomainCluster fetches a cluster and up to limit of its domains (0 for
+// the server default), by cluster ID or, if clusterID is empty, by domain.
+func (c *Client) GetDomainCluster(ctx context.Context, apiKey, clusterID, domain string, limit int32) (*pb.DomainCluster, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetDomainCluster(ctx, &pb.GetDomainClusterRequest{ClusterId: clusterID, Domain: domain, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain cluster: %w", err)
+	}
+	return resp, nil
+}
+
+// GetRecordActivity fetches the records of domain observed, first seen and
+// changed per day over the last days rolled-up days (0 for the default).
+func (c *Client) GetRecordActivity(ctx context.Context, apiKey, domain string, days int32) (*pb.GetRecordActivityResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetRecordActivity(ctx, &pb.GetRecordActivityRequest{Domain: domain, Days: days})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record activity: %w", err)
+	}
+	return resp, nil
+}
+
+// GetSubdomainActivity fetches the subdomains first seen under apex per
+// week over the last weeks rolled-up weeks (0 for the default).
+func (c *Client) GetSubdomainActivity(ctx context.Context, apiKey, apex string, weeks int32) (*pb.GetSubdomainActivityResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetSubdomainActivity(ctx, &pb.GetSubdomainActivityRequest{Apex: apex, Weeks: weeks})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subdomain activity: %w", err)
+	}
+	return resp, nil
+}
+
+// GetSigningKeys fetches the public keys that signed responses and export
+// files verify against (see the signing package). It needs no API key.
+func (c *Client) GetSigningKeys(ctx context.Context) (*pb.GetSigningKeysResponse, error) {
+	resp, err := c.client.GetSigningKeys(ctx, &pb.GetSigningKeysRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+	return resp, nil
+}
+
+// GetKeyPreferences fetches the request defaults stored for apiKey.
+func (c *Client) GetKeyPreferences(ctx context.Context, apiKey string) (*pb.KeyPreferences, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	prefs, err := c.client.GetKeyPreferences(ctx, &pb.GetKeyPreferencesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// SetKeyPreferences replaces the request defaults stored for apiKey and
+// returns them as stored (record types and sources uppercased).
+func (c *Client) SetKeyPreferences(ctx context.Context, apiKey string, prefs *pb.KeyPreferences) (*pb.KeyPreferences, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	stored, err := c.client.SetKeyPreferences(ctx, &pb.SetKeyPreferencesRequest{Preferences: prefs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set key preferences: %w", err)
+	}
+	return stored, nil
+}
+
+// CreateReportSchedule schedules a recurring report for apiKey and returns
+// it as stored, with its id.
+func (c *Client) CreateReportSchedule(ctx context.Context, apiKey string, schedule *pb.ReportSchedule) (*pb.ReportSchedule, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	stored, err := c.client.CreateReportSchedule(ctx, &pb.CreateReportScheduleRequest{Schedule: schedule})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report schedule: %w", err)
+	}
+	return stored, nil
+}
+
+// ListReportSchedules fetches the report schedules of apiKey with the
+// outcome of their last delivery.
+func (c *Client) ListReportSchedules(ctx context.Context, apiKey string) ([]*pb.ReportSchedule, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListReportSchedules(ctx, &pb.ListReportSchedulesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	return resp.Schedules, nil
+}
+
+// DeleteReportSchedule stops a report schedule of apiKey.
+func (c *Client) DeleteReportSchedule(ctx context.Context, apiKey string, id int32) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	if _, err := c.client.DeleteReportSchedule(ctx, &pb.DeleteReportScheduleRequest{Id: id}); err != nil {
+		return fmt.Errorf("failed to delete report schedule %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetOrganization fetches the organization of apiKey and its usage today,
+// with its keys if apiKey is an org admin.
+func (c *Client) GetOrganization(ctx context.Context, apiKey string) (*pb.Organization, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	org, err := c.client.GetOrganization(ctx, &pb.GetOrganizationRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org, nil
+}
+
+// CreateOrganizationKey issues a new key in the organization of apiKey,
+// which must be an org admin.
+func (c *Client) CreateOrganizationKey(ctx context.Context, apiKey, description string, orgAdmin bool) (*pb.OrganizationKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	key, err := c.client.CreateOrganizationKey(ctx, &pb.CreateOrganizationKeyRequest{Description: description, OrgAdmin: orgAdmin})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization key: %w", err)
+	}
+	return key, nil
+}
+
+// UpdateOrganizationKey replaces the description and flags of a key in the
+// organization of apiKey, which must be an org admin.
+func (c *Client) UpdateOrganizationKey(ctx context.Context, apiKey string, key *pb.OrganizationKey) (*pb.OrganizationKey, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	updated, err := c.client.UpdateOrganizationKey(ctx, &pb.UpdateOrganizationKeyRequest{
+		ApiKey:      key.ApiKey,
+		Description: key.Description,
+		Active:      key.Active,
+		OrgAdmin:    key.OrgAdmin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update organization key %s: %w", key.ApiKey, err)
+	}
+	return updated, nil
+}
+
+// SetOrganizationWatchlist replaces the watchlist shared by the
+// organization of apiKey, which must be an org admin.
+func (c *Client) SetOrganizationWatchlist(ctx context.Context, apiKey string, watchlist []string) (*pb.Organization, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	org, err := c.client.SetOrganizationWatchlist(ctx, &pb.SetOrganizationWatchlistRequest{Watchlist: watchlist})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set organization watchlist: %w", err)
+	}
+	return org, nil
+}
+
+// ImportWatchlist adds the domains and patterns of a CSV file or STIX 2.1
+// bundle (format CSV or STIX) to the watchlist of the organization of
+// apiKey, which must be an org admin. With replace the file replaces the
+// watchlist; with dryRun it is only validated.
+func (c *Client) ImportWatchlist(ctx context.Context, apiKey, format, content string, replace, dryRun bool) (*pb.ImportWatchlistResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ImportWatchlist(ctx, &pb.ImportWatchlistRequest{Format: format, Content: content, Replace: replace, DryRun: dryRun})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import watchlist: %w", err)
+	}
+	return resp, nil
+}
+
+// ImportDomains adds domains to those the organization of apiKey, which must
+// be an org admin, tracks; the query worker resolves them ahead of others.
+// Private domains new to bell are visible only to the organization's keys.
+func (c *Client) ImportDomains(ctx context.Context, apiKey string, domains []string, private bool) (*pb.ImportDomainsResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ImportDomains(ctx, &pb.ImportDomainsRequest{Domains: domains, Private: private})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import domains: %w", err)
+	}
+	return resp, nil
+}
+
+// GetUsage fetches the requests and returned records of apiKey in month
+// (YYYY-MM, or "" for the current month) with its monthly quotas.
+func (c *Client) GetUsage(ctx context.Context, apiKey, month string) (*pb.GetUsageResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetUsage(ctx, &pb.GetUsageRequest{Month: month})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	return resp, nil
+}
+
+// GetOrganizationUsage fetches daily request counts per key over the last
+// days (0 for the server default) for the organization of apiKey, which
+// must be an org admin.
+func (c *Client) GetOrganizationUsage(ctx context.Context, apiKey string, days int32) (*pb.GetOrganizationUsageResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetOrganizationUsage(ctx, &pb.GetOrganizationUsageRequest{Days: days})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization usage: %w", err)
+	}
+	return resp, nil
+}
+
+// CreateOrganization creates an organization with adminAPIKey as its first
+// org admin. It requires an admin API key.
+func (c *Client) CreateOrganization(ctx context.Context, apiKey, name string, dailyRequestQuota int64, adminAPIKey string) (*pb.Organization, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	org, err := c.client.CreateOrganization(ctx, &pb.CreateOrganizationRequest{Name: name, DailyRequestQuota: dailyRequestQuota, AdminApiKey: adminAPIKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization %s: %w", name, err)
+	}
+	return org, nil
+}
+
+// SetOrganizationQuota changes the daily request quota shared by an
+// organization's keys; 0 removes it. It requires an admin API key.
+func (c *Client) SetOrganizationQuota(ctx context.Context, apiKey string, organizationID int32, dailyRequestQuota int64) (*pb.Organization, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	org, err := c.client.SetOrganizationQuota(ctx, &pb.SetOrganizationQuotaRequest{OrganizationId: organizationID, DailyRequestQuota: dailyRequestQuota})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set quota of organization %d: %w", organizationID, err)
+	}
+	return org, nil
+}
+
+// SetDomainRestriction restricts a domain and its records to the keys of
+// the given organizations; none makes it public again. It requires an admin
+// API key.
+func (c *Client) SetDomainRestriction(ctx context.Context, apiKey, domain string, organizationIDs []int32) (*pb.DomainRestriction, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	restriction, err := c.client.SetDomainRestriction(ctx, &pb.SetDomainRestrictionRequest{Domain: domain, OrganizationIds: organizationIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set restriction of domain %s: %w", domain, err)
+	}
+	return restriction, nil
+}
+
+// ListNameserverReputation fetches the query worker's reliability stats per
+// nameserver host, least reliable first. host optionally restricts results
+// to hosts under a domain. It requires an admin API key.
+func (c *Client) ListNameserverReputation(ctx context.Context, apiKey, host string, skippedOnly bool, limit int32) ([]*pb.NameserverReputation, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.ListNameserverReputation(ctx, &pb.ListNameserverReputationRequest{Host: host, SkippedOnly: skippedOnly, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nameserver reputation: %w", err)
+	}
+	return resp.Nameservers, nil
+}
+
+// GetTLDCoverage reports the IANA TLDs whose zones bell has not ingested,
+// optionally only those of kind (CCTLD or GTLD). It requires an admin API
+// key.
+func (c *Client) GetTLDCoverage(ctx context.Context, apiKey, kind string) (*pb.GetTLDCoverageResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	resp, err := c.client.GetTLDCoverage(ctx, &pb.GetTLDCoverageRequest{Kind: kind})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLD coverage: %w", err)
+	}
+	return resp, nil
+}
+
+// TailEvents streams ingestion and worker events to fn until ctx is cancelled
+// or the stream fails. sources, kinds and tld optionally filter the events;
+// significances keeps only record-set changes of those significances.
+// It requires an admin API key.
+func (c *Client) TailEvents(ctx context.Context, apiKey string, sources, kinds []string, tld string, significances []string, fn func(*pb.IngestEvent)) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	stream, err := c.client.TailEvents(ctx, &pb.TailEventsRequest{Sources: sources, Kinds: kinds, Tld: tld, Significances: significances})
+	if err != nil {
+		return fmt.Errorf("failed to tail events: %w", err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to receive event: %w", err)
+		}
+		fn(event)
+	}
+}
+
+// StreamRecords calls fn with each record of domain, or of every domain of
+// tld if domain is empty, as the server reads them, optionally filtered by
+// record type. Records arrive in storage order. It stops at the first error
+// returned by fn.
+func (c *Client) StreamRecords(ctx context.Context, apiKey, domain, tld string, recordTypes []string, fn func(domain string, record *pb.DNSRecord) error) error {
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey))
+	defer cancel()
+	stream, err := c.client.StreamRecords(ctx, &pb.StreamRecordsRequest{Domain: domain, Tld: tld, RecordType: recordTypes})
+	if err != nil {
+		return fmt.Errorf("failed to stream records: %w", err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive record: %w", err)
+		}
+		if err := fn(msg.Domain, msg.Record); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamRecordBatches writes the records of domain, or of every domain of tld
+// if domain is empty, to w as an Arrow IPC stream, which Arrow readers such
+// as pyarrow.ipc.open_stream read. recordTypes and source optionally filter
+// the records; batchRows caps the rows of each record batch (0 for the
+// server's default). It returns the number of records written.
+func (c *Client) StreamRecordBatches(ctx context.Context, apiKey, domain, tld string, recordTypes []string, source string, batchRows int32, w io.Writer) (int64, error) {
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey))
+	defer cancel()
+	stream, err := c.client.StreamRecordBatches(ctx, &pb.StreamRecordBatchesRequest{Domain: domain, Tld: tld, RecordType: recordTypes, Source: source, BatchRows: batchRows})
+	if err != nil {
+		return 0, fmt.Errorf("failed to stream record batches: %w", err)
+	}
+	var rows int64
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, fmt.Errorf("failed to receive record batch: %w", err)
+		}
+		if _, err := w.Write(msg.Data); err != nil {
+			return rows, err
+		}
+		rows += msg.Rows
+	}
+}
+
+// SubscribeRecordChanges calls fn with each change the query worker observes
+// to the record sets of domains, or of every domain of tld if domains is
+// empty, until ctx is cancelled or the stream fails. recordTypes and
+// significances optionally filter the changes.
+func (c *Client) SubscribeRecordChanges(ctx context.Context, apiKey string, domains []string, tld string, recordTypes, significances []string, fn func(*pb.RecordChange)) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	stream, err := c.client.SubscribeRecordChanges(ctx, &pb.SubscribeRecordChangesRequest{Domains: domains, Tld: tld, RecordTypes: recordTypes, Significances: significances})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to record changes: %w", err)
+	}
+	for {
+		change, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to receive record change: %w", err)
+		}
+		fn(change)
+	}
+}
+
 // Example demonstrates usage of the Client to authenticate and fetch DNS records.
 func Example() {
 	// Initialize client
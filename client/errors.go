@@ -0,0 +1,45 @@
+package client
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Reasons in the ErrorInfo attached to service errors. Errors without a more
+// specific reason carry their status code in upper snake case, such as
+// INVALID_ARGUMENT or INTERNAL.
+const (
+	ReasonMissingKey         = "MISSING_KEY"        // No API key was sent
+	ReasonInvalidKey         = "INVALID_KEY"        // The API key does not exist
+	ReasonKeyInactive        = "KEY_INACTIVE"       // The API key has been deactivated
+	ReasonAdminKeyRequired   = "ADMIN_KEY_REQUIRED" // The RPC needs an admin API key
+	ReasonTooManyDomains     = "TOO_MANY_DOMAINS"   // Batch over its limit; see metadata quota_limit
+	ReasonInvalidSnapshot    = "INVALID_SNAPSHOT_TOKEN"
+	ReasonDomainNotFound     = "DOMAIN_NOT_FOUND"
+	ReasonTLDNotIngested     = "TLD_NOT_INGESTED"
+	ReasonUpstreamFailed     = "UPSTREAM_FAILED" // RDAP, DNS or TLS lookup failed; see metadata retry_after
+	ReasonResolverDisabled   = "RESOLVER_NOT_CONFIGURED"
+	ReasonUnknownRecordType  = "UNKNOWN_RECORD_TYPE"
+	ReasonTLSANotFound       = "TLSA_NOT_FOUND"      // ValidateDANE found no TLSA records; see metadata name
+	ReasonSandboxUnavailable = "SANDBOX_UNAVAILABLE" // Sandbox key used where the sandbox is not configured
+	ReasonNoOrganization     = "NO_ORGANIZATION"     // The API key does not belong to an organization
+	ReasonOrgAdminRequired   = "ORG_ADMIN_REQUIRED"  // The RPC needs an org admin API key
+	ReasonQuotaExceeded      = "QUOTA_EXCEEDED"      // Organization's daily quota used up; see metadata quota_limit and retry_after
+	ReasonExportsDisabled    = "EXPORTS_NOT_CONFIGURED"
+	ReasonPatternTooBroad    = "PATTERN_TOO_BROAD" // A GetRecordsBatch pattern would match too much; see metadata pattern
+)
+
+// ErrorReason returns the ErrorInfo reason and metadata of an error returned
+// by a Client method, or "" and nil if the error carries none.
+func ErrorReason(err error) (string, map[string]string) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", nil
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason, info.Metadata
+		}
+	}
+	return "", nil
+}
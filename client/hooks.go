@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Call describes a call to the service as its hooks see it.
+type Call struct {
+	Method   string      // Full gRPC method, e.g. /bell.v1.DNSService/GetRecords
+	Stream   bool        // A streaming call; OnResponse runs when the stream ends
+	Request  interface{} // The request message; nil for streams
+	Response interface{} // The response message of a unary call that succeeded; nil otherwise
+	Start    time.Time   // When OnRequest ran
+}
+
+// Hook observes and adjusts the calls of a Client, so embedders can add
+// logging, metrics or headers without wrapping every method.
+//
+// OnRequest runs before each call is sent. The context it returns is the
+// call's: add outgoing headers to it with metadata.AppendToOutgoingContext,
+// or values for OnResponse. OnResponse runs with that context once the call
+// has finished: when a unary call returns, or when a stream ends or its
+// context is cancelled. err is the call's error, nil on success.
+//
+// Hooks run in the order given to WithHooks, OnResponse in reverse, and must
+// be safe for concurrent use.
+type Hook interface {
+	OnRequest(ctx context.Context, call *Call) context.Context
+	OnResponse(ctx context.Context, call *Call, err error)
+}
+
+// HookFuncs is a Hook of functions, either of which may be nil.
+type HookFuncs struct {
+	Request  func(ctx context.Context, call *Call) context.Context
+	Response func(ctx context.Context, call *Call, err error)
+}
+
+func (h HookFuncs) OnRequest(ctx context.Context, call *Call) context.Context {
+	if h.Request == nil {
+		return ctx
+	}
+	return h.Request(ctx, call)
+}
+
+func (h HookFuncs) OnResponse(ctx context.Context, call *Call, err error) {
+	if h.Response != nil {
+		h.Response(ctx, call, err)
+	}
+}
+
+// Option configures a Client made by NewClient or NewTLSClient.
+type Option func(*options)
+
+type options struct {
+	hooks []Hook
+}
+
+// WithHooks runs hooks around every call of the client.
+func WithHooks(hooks ...Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// dialOptions returns the dial options installing the hooks of opts.
+func dialOptions(opts []Option) []grpc.DialOption {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var dialOpts []grpc.DialOption
+	for _, hook := range o.hooks {
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(unaryHook(hook)),
+			grpc.WithChainStreamInterceptor(streamHook(hook)))
+	}
+	return dialOpts
+}
+
+// unaryHook runs hook around unary calls.
+func unaryHook(hook Hook) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		call := &Call{Method: method, Request: req, Start: time.Now()}
+		ctx = hook.OnRequest(ctx, call)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			call.Response = reply
+		}
+		hook.OnResponse(ctx, call, err)
+		return err
+	}
+}
+
+// streamHook runs hook around streaming calls.
+func streamHook(hook Hook) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		call := &Call{Method: method, Stream: true, Start: time.Now()}
+		ctx = hook.OnRequest(ctx, call)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			hook.OnResponse(ctx, call, err)
+			return nil, err
+		}
+		s := &hookedStream{ClientStream: stream}
+		s.finish = func(err error) {
+			s.once.Do(func() { hook.OnResponse(ctx, call, err) })
+		}
+		// The stream's context ends with the stream, also when the caller
+		// stops reading and cancels it
+		go func() {
+			<-stream.Context().Done()
+			s.finish(stream.Context().Err())
+		}()
+		return s, nil
+	}
+}
+
+// hookedStream reports the end of a stream to its hook.
+type hookedStream struct {
+	grpc.ClientStream
+	once   sync.Once
+	finish func(err error)
+}
+
+func (s *hookedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		s.finish(nil)
+	} else if err != nil {
+		s.finish(err)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// otelHook traces calls; see OpenTelemetryHook.
+type otelHook struct {
+	tracer trace.Tracer
+}
+
+// OpenTelemetryHook returns a Hook tracing each call as a client span of
+// provider, or of the global tracer provider if nil, and passing the trace
+// context to the server in the call's metadata with the global propagator,
+// so the server's spans join the caller's trace when it traces too.
+func OpenTelemetryHook(provider trace.TracerProvider) Hook {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &otelHook{tracer: provider.Tracer("github.com/moos3/bell/client")}
+}
+
+func (h *otelHook) OnRequest(ctx context.Context, call *Call) context.Context {
+	service, method := path.Split(strings.TrimPrefix(call.Method, "/"))
+	ctx, _ = h.tracer.Start(ctx, strings.TrimPrefix(call.Method, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", strings.TrimSuffix(service, "/")),
+			attribute.String("rpc.method", method),
+		))
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (h *otelHook) OnResponse(ctx context.Context, call *Call, err error) {
+	span := trace.SpanFromContext(ctx)
+	st := status.Convert(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(st.Code())))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+	span.End()
+}
+
+// metadataCarrier lets propagators write gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
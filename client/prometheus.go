@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// prometheusBuckets are the upper bounds, in seconds, of the call duration
+// histogram: Prometheus's default buckets.
+var prometheusBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusHook is a Hook counting calls by method and gRPC status code,
+// timing them, and tracking those in flight. It serves the metrics in the
+// Prometheus text format, so an embedder can expose them on its own
+// /metrics or append them to its registry's output:
+//
+//	bell_client_calls_total{service,method,code}
+//	bell_client_call_duration_seconds{service,method} (histogram)
+//	bell_client_calls_in_flight{service,method}
+//
+// Streams are timed until they end.
+type PrometheusHook struct {
+	mu        sync.Mutex
+	calls     map[[3]string]int64          // {service, method, code} -> calls
+	durations map[[2]string]*callHistogram // {service, method} -> durations
+	inFlight  map[[2]string]int64          // {service, method} -> calls in flight
+}
+
+// callHistogram holds one method's call durations.
+type callHistogram struct {
+	buckets []int64 // Calls no longer than each of prometheusBuckets
+	count   int64
+	sum     float64
+}
+
+// NewPrometheusHook returns a PrometheusHook with no calls counted.
+func NewPrometheusHook() *PrometheusHook {
+	return &PrometheusHook{
+		calls:     make(map[[3]string]int64),
+		durations: make(map[[2]string]*callHistogram),
+		inFlight:  make(map[[2]string]int64),
+	}
+}
+
+// methodLabels splits a full gRPC method into its service and method.
+func methodLabels(fullMethod string) [2]string {
+	service, method := path.Split(strings.TrimPrefix(fullMethod, "/"))
+	return [2]string{strings.TrimSuffix(service, "/"), method}
+}
+
+func (h *PrometheusHook) OnRequest(ctx context.Context, call *Call) context.Context {
+	h.mu.Lock()
+	h.inFlight[methodLabels(call.Method)]++
+	h.mu.Unlock()
+	return ctx
+}
+
+func (h *PrometheusHook) OnResponse(ctx context.Context, call *Call, err error) {
+	labels := methodLabels(call.Method)
+	seconds := time.Since(call.Start).Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inFlight[labels]--
+	h.calls[[3]string{labels[0], labels[1], status.Code(err).String()}]++
+	hist, ok := h.durations[labels]
+	if !ok {
+		hist = &callHistogram{buckets: make([]int64, len(prometheusBuckets))}
+		h.durations[labels] = hist
+	}
+	for i, bound := range prometheusBuckets {
+		if seconds <= bound {
+			hist.buckets[i]++
+		}
+	}
+	hist.count++
+	hist.sum += seconds
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (h *PrometheusHook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	h.WriteMetrics(w)
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes the metrics to w in the Prometheus text format.
+func (h *PrometheusHook) WriteMetrics(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b := bufio.NewWriter(w)
+	labels := func(service, method string) string {
+		return fmt.Sprintf(`service="%s",method="%s"`, labelEscaper.Replace(service), labelEscaper.Replace(method))
+	}
+
+	fmt.Fprintln(b, "# HELP bell_client_calls_total Calls made by the bell client, by gRPC status code.")
+	fmt.Fprintln(b, "# TYPE bell_client_calls_total counter")
+	calls := make([][3]string, 0, len(h.calls))
+	for key := range h.calls {
+		calls = append(calls, key)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return strings.Join(calls[i][:], "\x00") < strings.Join(calls[j][:], "\x00")
+	})
+	for _, key := range calls {
+		fmt.Fprintf(b, "bell_client_calls_total{%s,code=\"%s\"} %d\n", labels(key[0], key[1]), key[2], h.calls[key])
+	}
+
+	fmt.Fprintln(b, "# HELP bell_client_call_duration_seconds Duration of the bell client's calls.")
+	fmt.Fprintln(b, "# TYPE bell_client_call_duration_seconds histogram")
+	for _, key := range sortedMethods(h.durations) {
+		hist := h.durations[key]
+		for i, bound := range prometheusBuckets {
+			fmt.Fprintf(b, "bell_client_call_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels(key[0], key[1]), strconv.FormatFloat(bound, 'g', -1, 64), hist.buckets[i])
+		}
+		fmt.Fprintf(b, "bell_client_call_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels(key[0], key[1]), hist.count)
+		fmt.Fprintf(b, "bell_client_call_duration_seconds_sum{%s} %s\n", labels(key[0], key[1]), strconv.FormatFloat(hist.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "bell_client_call_duration_seconds_count{%s} %d\n", labels(key[0], key[1]), hist.count)
+	}
+
+	fmt.Fprintln(b, "# HELP bell_client_calls_in_flight Calls of the bell client not yet finished.")
+	fmt.Fprintln(b, "# TYPE bell_client_calls_in_flight gauge")
+	for _, key := range sortedMethods(h.inFlight) {
+		fmt.Fprintf(b, "bell_client_calls_in_flight{%s} %d\n", labels(key[0], key[1]), h.inFlight[key])
+	}
+	return b.Flush()
+}
+
+// sortedMethods returns the keys of m in order.
+func sortedMethods[V any](m map[[2]string]V) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
@@ -3,7 +3,10 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,25 +14,305 @@ import (
 // Config holds the configuration for AlloyDB and DNS-related settings.
 type Config struct {
 	AlloyDB struct {
-		Host     string `yaml:"host"`     // Database host (e.g., private IP)
-		Port     string `yaml:"port"`     // Database port (e.g., 5432)
-		User     string `yaml:"user"`     // Database user
-		Password string `yaml:"password"` // Database password
-		Database string `yaml:"database"` // Database name
-		SSLMode  string `yaml:"sslmode"`  // SSL mode (disable, require, verify-ca, verify-full)
+		Host     string   `yaml:"host"`     // Database host (e.g., private IP)
+		Port     string   `yaml:"port"`     // Database port (e.g., 5432)
+		User     string   `yaml:"user"`     // Database user
+		Password string   `yaml:"password"` // Database password
+		Database string   `yaml:"database"` // Database name
+		SSLMode  string   `yaml:"sslmode"`  // SSL mode (disable, require, verify-ca, verify-full)
+		Replicas []string `yaml:"replicas"` // Read replica addresses ("host" or "host:port"); same credentials and database
 	} `yaml:"alloydb"`
+	Sharding struct {
+		HealthCheckSeconds int `yaml:"health_check_seconds"` // Interval between shard health checks
+		Shards             []struct {
+			Name     string   `yaml:"name"`     // Shard name used in logs and health reports
+			Host     string   `yaml:"host"`     // Database host
+			Port     string   `yaml:"port"`     // Database port
+			User     string   `yaml:"user"`     // Database user
+			Password string   `yaml:"password"` // Database password
+			Database string   `yaml:"database"` // Database name
+			SSLMode  string   `yaml:"sslmode"`  // SSL mode (disable, require, verify-ca, verify-full)
+			TLDs     []string `yaml:"tlds"`     // TLDs whose domains and records live on this shard
+			Replicas []string `yaml:"replicas"` // Read replica addresses ("host" or "host:port"); same credentials and database
+		} `yaml:"shards"`
+	} `yaml:"sharding"`
+	RecordData struct {
+		CompressMinBytes int `yaml:"compress_min_bytes"` // Store TXT record data at least this long compressed; 0 stores all data plain
+	} `yaml:"record_data"`
 	Zones struct {
 		Directory               string `yaml:"directory"`                 // Directory containing zone files
 		ReprocessThresholdHours int    `yaml:"reprocess_threshold_hours"` // Hours before reprocessing TLDs
 		MaxConcurrent           int    `yaml:"max_concurrent"`            // Maximum concurrent TLD processing
 		BatchSize               int    `yaml:"batch_size"`                // Batch size for record processing
+		WriteMethod             string `yaml:"write_method"`              // How batches are written: insert (a statement per record) or copy (COPY into a staging table, then one upsert)
 	} `yaml:"zones"`
+	CZDS struct {
+		Username          string   `yaml:"username"`            // ICANN account username
+		Password          string   `yaml:"password"`            // ICANN account password
+		AuthURL           string   `yaml:"auth_url"`            // ICANN account authentication endpoint
+		APIURL            string   `yaml:"api_url"`             // CZDS API base URL
+		TLDs              []string `yaml:"tlds"`                // Optional subset of approved zones to download
+		MaxConcurrent     int      `yaml:"max_concurrent"`      // Maximum concurrent requests for the account
+		RequestsPerMinute int      `yaml:"requests_per_minute"` // Maximum request starts per minute for the account
+		MaxRetries        int      `yaml:"max_retries"`         // Retries per request on 429/503 or network errors
+		TimeoutMinutes    int      `yaml:"timeout_minutes"`     // Per-request timeout, including the download (minutes)
+	} `yaml:"czds"`
 	DNSQuery struct {
-		MaxConcurrent     int      `yaml:"max_concurrent"`      // Maximum concurrent DNS queries
-		RetryDelaySeconds int      `yaml:"retry_delay_seconds"` // Delay between retries (seconds)
-		BatchSize         int      `yaml:"batch_size"`          // Batch size for domain queries
-		DNSServers        []string `yaml:"dns_servers"`         // List of DNS servers
+		MaxConcurrent       int      `yaml:"max_concurrent"`         // Maximum concurrent DNS queries
+		RetryDelaySeconds   int      `yaml:"retry_delay_seconds"`    // Delay between retries (seconds)
+		BatchSize           int      `yaml:"batch_size"`             // Batch size for domain queries
+		DNSServers          []string `yaml:"dns_servers"`            // Recursive upstreams: [udp|tcp|tls|https://]host[:port]; more can be added at runtime
+		TTLAnomalyRatio     float64  `yaml:"ttl_anomaly_ratio"`      // Flag TTL changes by at least this factor (e.g. 10 = 3600 -> 360)
+		IngestAddress       string   `yaml:"ingest_address"`         // Send records to the ingest service at this address instead of writing directly
+		DomainMaxQueries    int      `yaml:"domain_max_queries"`     // DNS exchanges per domain per pass, including retries and NS discovery
+		DomainMaxSeconds    int      `yaml:"domain_max_seconds"`     // Wall time per domain per pass (seconds)
+		TLDMaxQueries       int      `yaml:"tld_max_queries"`        // DNS exchanges per TLD per batch; further domains are deferred
+		TLDMaxSeconds       int      `yaml:"tld_max_seconds"`        // Summed domain wall time per TLD per batch (seconds)
+		NSSkipAfterTimeouts int      `yaml:"ns_skip_after_timeouts"` // Skip a nameserver after this many consecutive timeouts
+		NSSkipMinutes       int      `yaml:"ns_skip_minutes"`        // How long a timed-out nameserver is skipped (minutes)
+		ServiceNames        []string `yaml:"service_names"`          // Service prefixes resolved for SRV under each domain (e.g. _sip._tls)
+		TLSANames           []string `yaml:"tlsa_names"`             // Port prefixes resolved for TLSA under each domain (e.g. _443._tcp)
 	} `yaml:"dns_query"`
+	Resolver struct {
+		Transport                 string   `yaml:"transport"`                     // Transport to recursive upstreams: udp (TCP on truncation), tcp or tcp-tls to dns_query.dns_servers, or https to doh_urls
+		DoHURLs                   []string `yaml:"doh_urls"`                      // DNS over HTTPS endpoints, e.g. https://dns.google/dns-query
+		TimeoutSeconds            int      `yaml:"timeout_seconds"`               // Timeout per DNS exchange (seconds)
+		QueriesPerSecond          int      `yaml:"queries_per_second"`            // Outbound queries per second across all servers; 0 is unlimited
+		PerServerQueriesPerSecond int      `yaml:"per_server_queries_per_second"` // Outbound queries per second to any one server; 0 is unlimited
+		CacheSize                 int      `yaml:"cache_size"`                    // Maximum cached answers
+		CacheMaxTTLSeconds        int      `yaml:"cache_max_ttl_seconds"`         // Upper bound on how long an answer is cached (seconds)
+		NegativeTTLSeconds        int      `yaml:"negative_ttl_seconds"`          // How long NXDOMAIN and empty answers are cached (seconds)
+		MetricsAddress            string   `yaml:"metrics_address"`               // Serve resolver metrics at /debug/vars on this address; disabled if empty
+		HealthCheckSeconds        int      `yaml:"health_check_seconds"`          // How often recursive upstreams are probed (seconds)
+		UnhealthyAfter            int      `yaml:"unhealthy_after"`               // Failed probes in a row before an upstream is taken out of rotation
+		HealthyAfter              int      `yaml:"healthy_after"`                 // Successful probes in a row before it is put back
+		ProbeName                 string   `yaml:"probe_name"`                    // Name whose NS records probes ask for
+		ServersRefreshSeconds     int      `yaml:"servers_refresh_seconds"`       // How often upstream edits made through the API are picked up (seconds)
+	} `yaml:"resolver"`
+	Ingest struct {
+		ListenAddress    string `yaml:"listen_address"`      // gRPC address the ingest service listens on
+		FlushSize        int    `yaml:"flush_size"`          // Records per COPY batch; reaching it triggers a flush
+		FlushIntervalMs  int    `yaml:"flush_interval_ms"`   // Maximum time records wait in the buffer (milliseconds)
+		MaxBuffered      int    `yaml:"max_buffered"`        // Reject writes with ResourceExhausted above this many buffered records
+		MaxRowsPerSecond int    `yaml:"max_rows_per_second"` // Database write throttle; 0 is unlimited
+		MaxWriteAttempts int    `yaml:"max_write_attempts"`  // Drop a record after this many failed writes of it on its own
+		Token            string `yaml:"token"`               // Shared secret writers send as ingest-token metadata; required unless tls.client_ca_file is set
+		Pressure         struct {
+			CheckIntervalMs          int     `yaml:"check_interval_ms"`           // How often database load is sampled while commits are held
+			MaxReplicationLagSeconds float64 `yaml:"max_replication_lag_seconds"` // Hold commits while a replica replays this far behind; 0 ignores lag
+			MaxActiveConnections     int     `yaml:"max_active_connections"`      // Hold commits while this many client connections run a statement; 0 ignores them
+			MaxLockWaits             int     `yaml:"max_lock_waits"`              // Hold commits while this many connections wait on a lock; 0 ignores lock waits
+			MaxPauseSeconds          int     `yaml:"max_pause_seconds"`           // Commit anyway after holding a batch this long, so ingestion slows but never stalls
+		} `yaml:"pressure"`
+		TLS struct {
+			CertFile       string `yaml:"cert_file"`        // PEM certificate chain of the ingest service; it serves plaintext if empty
+			KeyFile        string `yaml:"key_file"`         // PEM private key of cert_file
+			ClientCAFile   string `yaml:"client_ca_file"`   // PEM CA bundle; if set, writers must present a certificate it signed (mutual TLS)
+			CAFile         string `yaml:"ca_file"`          // PEM CA bundle writers verify the service's certificate against; system roots if empty
+			ClientCertFile string `yaml:"client_cert_file"` // PEM certificate writers present under mutual TLS
+			ClientKeyFile  string `yaml:"client_key_file"`  // PEM private key of client_cert_file
+		} `yaml:"tls"`
+	} `yaml:"ingest"`
+	Leases struct {
+		TTLSeconds          int    `yaml:"ttl_seconds"`           // A lease on a TLD ingest or domain batch expires this long after its last heartbeat
+		ReapIntervalSeconds int    `yaml:"reap_interval_seconds"` // How often the server re-queues work whose lease expired
+		AlertAfterFailures  int    `yaml:"alert_after_failures"`  // Alert when the same item fails or expires this many times in a row
+		AlertWebhookURL     string `yaml:"alert_webhook_url"`     // Optional endpoint receiving alerts as JSON; alerts are always logged and published as events
+	} `yaml:"leases"`
+	Shadow struct {
+		Percent        float64 `yaml:"percent"`         // Percentage (0-100) of GetRecords and CheckDomains reads mirrored to the secondary; 0 disables
+		Host           string  `yaml:"host"`            // Secondary database host (e.g. the new storage layer or a replica)
+		Port           string  `yaml:"port"`            // Secondary database port
+		User           string  `yaml:"user"`            // Secondary database user
+		Password       string  `yaml:"password"`        // Secondary database password
+		Database       string  `yaml:"database"`        // Secondary database name
+		SSLMode        string  `yaml:"sslmode"`         // SSL mode (disable, require, verify-ca, verify-full)
+		TimeoutSeconds int     `yaml:"timeout_seconds"` // Timeout per mirrored read (seconds)
+		MaxInFlight    int     `yaml:"max_in_flight"`   // Mirrored reads running at once; further samples are dropped
+		MetricsAddress string  `yaml:"metrics_address"` // Serve shadow metrics at /debug/vars on this address; disabled if empty
+	} `yaml:"shadow"`
+	Hedging struct {
+		DelayMs         int      `yaml:"delay_ms"`          // Send a second attempt to another replica if the first has not answered after this long
+		MaxExtraPercent float64  `yaml:"max_extra_percent"` // Hedged attempts as a percentage of hedgeable requests; 0 disables hedging
+		Methods         []string `yaml:"methods"`           // Read RPCs that may be hedged
+	} `yaml:"hedging"`
+	Canary struct {
+		IntervalSeconds  int      `yaml:"interval_seconds"`  // Run the self-checks this often; 0 disables them
+		TimeoutSeconds   int      `yaml:"timeout_seconds"`   // Per check
+		APIKey           string   `yaml:"api_key"`           // Key the checks call the API with; use a dedicated read-only key, as the calls are metered like any other
+		Domains          []string `yaml:"domains"`           // Control domains known to be in the corpus, checked with GetRecords
+		LiveDomains      []string `yaml:"live_domains"`      // Control domains checked with LookupLive if live resolution is configured; defaults to domains
+		FailureThreshold int      `yaml:"failure_threshold"` // Rounds in a row a check must fail before the server reports not ready
+		MetricsAddress   string   `yaml:"metrics_address"`   // Serve canary metrics at /debug/vars on this address; disabled if empty
+	} `yaml:"canary"`
+	Health struct {
+		IntervalSeconds      int `yaml:"interval_seconds"`        // Ping the databases and read the ingestion lag this often
+		TimeoutSeconds       int `yaml:"timeout_seconds"`         // Per ping and query
+		MaxIngestionLagHours int `yaml:"max_ingestion_lag_hours"` // Not ready once no zone has been ingested for this long; 0 only reports the lag
+	} `yaml:"health"`
+	Freshness struct {
+		IntervalSeconds int `yaml:"interval_seconds"` // Measure compliance with the SLOs this often
+		TimeoutSeconds  int `yaml:"timeout_seconds"`  // Per measurement of one SLO on one shard
+		SLOs            []struct {
+			Name          string  `yaml:"name"`           // Names the SLO in metrics and logs
+			Source        string  `yaml:"source"`         // Record source whose age is measured, e.g. QUERY
+			Scope         string  `yaml:"scope"`          // "watched": domains organizations track (ImportDomains); "all": every domain with records from source
+			MaxAgeHours   float64 `yaml:"max_age_hours"`  // A domain is fresh while its oldest record from source was observed within this long
+			TargetPercent float64 `yaml:"target_percent"` // The SLO is met while at least this share of its domains is fresh
+		} `yaml:"slos"`
+	} `yaml:"freshness"`
+	Shutdown struct {
+		DelaySeconds   int `yaml:"delay_seconds"`   // After SIGTERM or SIGINT, keep serving this long while reporting not ready, so load balancers stop routing here first
+		TimeoutSeconds int `yaml:"timeout_seconds"` // Then wait this long for requests in flight before cutting them off
+	} `yaml:"shutdown"`
+	Sandbox struct {
+		Database string `yaml:"database"` // Database on the alloydb host holding the synthetic dataset for sandbox keys; sandbox keys are refused if empty
+		Fixture  string `yaml:"fixture"`  // Fixture loaded into it by the sandbox tool; the built-in dataset if empty
+	} `yaml:"sandbox"`
+	DGA struct {
+		Threshold  float64 `yaml:"threshold"`   // Score at or above which a domain is flagged as likely DGA; 0.65 if unset
+		NGramModel string  `yaml:"ngram_model"` // Optional bigram frequency file; built-in English model if empty
+		BatchSize  int     `yaml:"batch_size"`  // Number of domains scored per batch
+	} `yaml:"dga"`
+	Delta struct {
+		WebhookURL     string `yaml:"webhook_url"`     // Endpoint receiving zone deltas after each ingest
+		WebhookSecret  string `yaml:"webhook_secret"`  // Optional HMAC-SHA256 key for the X-Bell-Signature header
+		OutputDir      string `yaml:"output_dir"`      // Optional directory (e.g. object storage mount) for delta files
+		TimeoutSeconds int    `yaml:"timeout_seconds"` // Webhook request timeout (seconds)
+	} `yaml:"delta"`
+	Merge struct {
+		Precedence    []string `yaml:"precedence"`     // Record sources in order of preference (e.g. QUERY, CZDS)
+		FreshnessWins bool     `yaml:"freshness_wins"` // Prefer the most recently updated source over precedence
+	} `yaml:"merge"`
+	Analytics struct {
+		TopN                  int      `yaml:"top_n"`                   // Entries kept per TLD for top-N aggregates
+		ASNDatabase           string   `yaml:"asn_database"`            // Optional iptoasn.com TSV used for hosting ASN aggregates and to classify address changes
+		IntervalMinutes       int      `yaml:"interval_minutes"`        // Re-run aggregate jobs on this interval; 0 runs once
+		KeywordMinCount       int      `yaml:"keyword_min_count"`       // Domains a keyword needs on a day to be stored by keyword-trends
+		Keywords              []string `yaml:"keywords"`                // Watched keywords also counted inside unseparated labels (e.g. paypallogin)
+		ClusterLookbackDays   int      `yaml:"cluster_lookback_days"`   // domain-clusters groups the domains first seen within this many days
+		ClusterWindowHours    int      `yaml:"cluster_window_hours"`    // Largest first_seen gap between consecutive domains of a cluster
+		ClusterMinSize        int      `yaml:"cluster_min_size"`        // Domains a cluster needs to be stored
+		ClusterMaxSize        int      `yaml:"cluster_max_size"`        // Larger groups are shared hosting or parking rather than a campaign and are not stored
+		ActivityBackfillDays  int      `yaml:"activity_backfill_days"`  // Days activity-rollups computes on its first run
+		ActivityRetentionDays int      `yaml:"activity_retention_days"` // activity-rollups deletes days and weeks older than this
+	} `yaml:"analytics"`
+	Coverage struct {
+		TLDListURL     string            `yaml:"tld_list_url"`    // IANA TLD list loaded by the analytics iana-tlds job
+		EstimatedSizes map[string]int64  `yaml:"estimated_sizes"` // TLD -> registered domains, e.g. from registry statistics; GetTLDCoverage falls back on the domains observed
+		Sources        map[string]string `yaml:"sources"`         // TLD -> where its zone could be obtained, suggested ahead of the built-in sources
+	} `yaml:"coverage"`
+	Billing struct {
+		ExportDir string   `yaml:"export_dir"` // Directory (e.g. object storage mount) for <YYYY-MM>/usage.<format> exports
+		Formats   []string `yaml:"formats"`    // Export formats: csv, json
+	} `yaml:"billing"`
+	Reports struct {
+		IntervalMinutes int    `yaml:"interval_minutes"` // Check for due report schedules on this interval; 0 checks once
+		OutputDir       string `yaml:"output_dir"`       // Directory (e.g. object storage mount) for STORAGE deliveries: <schedule id>/<report>-<period end>.<format>
+		MaxRows         int    `yaml:"max_rows"`         // Rows per report; longer reports are truncated with a note
+		SMTP            struct {
+			Host     string `yaml:"host"` // Mail server for EMAIL deliveries; EMAIL schedules fail if empty
+			Port     string `yaml:"port"`
+			Username string `yaml:"username"` // PLAIN auth if set
+			Password string `yaml:"password"`
+			From     string `yaml:"from"` // Sender address
+		} `yaml:"smtp"`
+	} `yaml:"reports"`
+	Gateway struct {
+		GRPCWeb        bool     `yaml:"grpc_web"`        // Serve gRPC-Web on the HTTP listener for browser clients
+		AllowedOrigins []string `yaml:"allowed_origins"` // CORS origins allowed to call the HTTP listener
+		SwaggerUI      bool     `yaml:"swagger_ui"`      // Serve a Swagger UI for the REST gateway at /docs
+		SwaggerAssets  string   `yaml:"swagger_assets"`  // Base URL of the swagger-ui-dist files the Swagger UI loads
+		HTTP3          bool     `yaml:"http3"`           // Also serve the HTTP listener over HTTP/3, on the same port over UDP; requires tls.cert_file
+	} `yaml:"gateway"`
+	GRPC struct {
+		Reflection bool `yaml:"reflection"` // Register the reflection service, callable without an API key, so grpcurl and grpcui can discover the services
+	} `yaml:"grpc"`
+	TLS struct {
+		CertFile      string `yaml:"cert_file"`      // PEM certificate chain of the gRPC and HTTP listeners; both serve plaintext if empty
+		KeyFile       string `yaml:"key_file"`       // PEM private key of cert_file
+		ClientCAFile  string `yaml:"client_ca_file"` // PEM CA bundle; if set, clients must present a certificate it signed (mutual TLS)
+		ReloadSeconds int    `yaml:"reload_seconds"` // How often the files are checked for rotated certificates
+	} `yaml:"tls"`
+	RateLimit struct {
+		RequestsPerSecond float64 `yaml:"requests_per_second"` // Sustained requests per second per API key; 0 disables rate limiting
+		Burst             int     `yaml:"burst"`               // Requests a key may make at once before being held to the sustained rate
+	} `yaml:"rate_limit"`
+	Authorization struct {
+		DefaultScope string              `yaml:"default_scope"`  // Scope required by RPCs without one of their own in the server or methods
+		Methods      map[string]string   `yaml:"methods"`        // RPC name (e.g. GetRecords) -> required scope: read, write or admin, or one narrowed to an area (write:ingest)
+		Roles        map[string][]string `yaml:"roles"`          // api_keys.role -> granted scopes; unlisted roles get read and write. api_keys.scopes overrides these per key
+		AdminAPIKeys []string            `yaml:"admin_api_keys"` // API keys with every scope, whatever api_keys stores for them
+	} `yaml:"authorization"`
+	RDAP struct {
+		BootstrapURL         string `yaml:"bootstrap_url"`          // IANA RDAP bootstrap file for domain registries
+		RegistrarRegistryURL string `yaml:"registrar_registry_url"` // IANA registrar ID CSV loaded by the iana-registrars job
+		TimeoutSeconds       int    `yaml:"timeout_seconds"`        // Timeout per RDAP request (seconds)
+		CacheHours           int    `yaml:"cache_hours"`            // Reuse stored registrar and abuse data for this long
+	} `yaml:"rdap"`
+	DomainFilter struct {
+		Enabled           bool    `yaml:"enabled"`             // Keep a Bloom filter of domain names in the server
+		ExpectedDomains   int     `yaml:"expected_domains"`    // Minimum filter capacity in domains
+		FalsePositiveRate float64 `yaml:"false_positive_rate"` // Target false positive rate (e.g. 0.01)
+		RefreshSeconds    int     `yaml:"refresh_seconds"`     // Interval for adding recently updated domains
+		RebuildHours      int     `yaml:"rebuild_hours"`       // Interval for full rebuilds, which drop removed domains
+	} `yaml:"domain_filter"`
+	Counts struct {
+		ExactThreshold int64 `yaml:"exact_threshold"` // CountDomains and CountRecords count exactly when the planner expects at most this many rows on a shard, else estimate
+		TimeoutSeconds int   `yaml:"timeout_seconds"` // Exact counts running longer fall back to the planner's estimate
+	} `yaml:"counts"`
+	RecordSearch struct {
+		TimeoutSeconds int `yaml:"timeout_seconds"` // SearchRecords scans stop after this long and return the matches found so far
+		MaxResults     int `yaml:"max_results"`     // Most records one SearchRecords call returns
+	} `yaml:"record_search"`
+	Wildcards struct {
+		MaxExpansion int     `yaml:"max_expansion"` // Most domains one GetRecordsBatch pattern expands to
+		MaxCost      float64 `yaml:"max_cost"`      // Planner cost of listing every match above which a pattern is rejected
+	} `yaml:"wildcards"`
+	Tracing struct {
+		Endpoint       string  `yaml:"endpoint"`        // OTLP/HTTP traces URL of a collector, e.g. http://otel-collector:4318/v1/traces; tracing is disabled if empty
+		SampleRate     float64 `yaml:"sample_rate"`     // Fraction (0-1) of traces the server starts that are sampled; calls carrying a trace context follow its decision
+		MetricsAddress string  `yaml:"metrics_address"` // Serve gRPC and database latency histograms with trace exemplars at /metrics on this address (OpenMetrics); disabled if empty or tracing is
+	} `yaml:"tracing"`
+	Exports struct {
+		OutputDir      string `yaml:"output_dir"`      // Directory (e.g. object storage mount) shared by the export worker and the server: <job id>/<kind>.<format>
+		SharedStorage  bool   `yaml:"shared_storage"`  // Confirms output_dir is storage every server replica and the export worker mount; required to enable exports
+		SigningKey     string `yaml:"signing_key"`     // HMAC-SHA256 key for download URLs; StartExport is disabled if this or output_dir is empty
+		URLBase        string `yaml:"url_base"`        // Public base URL of the HTTP listener for download URLs, e.g. https://bell.example.com
+		URLTTLMinutes  int    `yaml:"url_ttl_minutes"` // How long a download URL from GetExport stays valid
+		RetentionHours int    `yaml:"retention_hours"` // Export files are deleted this long after they finish
+		PollSeconds    int    `yaml:"poll_seconds"`    // The export worker checks for queued jobs on this interval; 0 checks once
+		MaxRows        int64  `yaml:"max_rows"`        // Exports stop and fail past this many rows
+		Scrub          struct {
+			Key    string   `yaml:"key"`    // HMAC-SHA256 key for pseudonyms in scrubbed exports; scrubbing is disabled if empty
+			Hash   []string `yaml:"hash"`   // Fields whose registrable domains are replaced with pseudonyms: domain, nameservers, record_data
+			Redact []string `yaml:"redact"` // Export columns cleared in scrubbed exports
+		} `yaml:"scrub"`
+	} `yaml:"exports"`
+	Signing struct {
+		KeyFiles []string `yaml:"key_files"` // PEM Ed25519 keys: the first, a private key, signs responses on request and export files; the others are retired keys GetSigningKeys still publishes. Signing is disabled if empty
+	} `yaml:"signing"`
+	Lifecycle struct {
+		TransferCheckSeconds int    `yaml:"transfer_check_seconds"` // How often the server looks up domains whose nameserver provider changed over RDAP for registrar transfers
+		TransferLookups      int    `yaml:"transfer_lookups"`       // RDAP lookups per check, bounding the load on registries
+		LikelyAfterHours     int    `yaml:"likely_after_hours"`     // Provider changes RDAP could not confirm within this long are reported as LIKELY transfers
+		WebhookURL           string `yaml:"webhook_url"`            // Endpoint receiving detected transfers
+		WebhookSecret        string `yaml:"webhook_secret"`         // Optional HMAC-SHA256 key for the X-Bell-Signature header
+		TimeoutSeconds       int    `yaml:"timeout_seconds"`        // Webhook request timeout (seconds)
+	} `yaml:"lifecycle"`
+	Redaction struct {
+		Roles map[string][]string `yaml:"roles"` // api_keys.role -> response fields cleared for that role (e.g. source, which also clears sources, chosen_source and discarded_sources)
+	} `yaml:"redaction"`
+	Logging struct {
+		Level         string            `yaml:"level"`          // Initial log level (debug, info, warn, error)
+		Modules       map[string]string `yaml:"modules"`        // Module (server, czds, query) -> initial log level, overriding level
+		Format        string            `yaml:"format"`         // Output format: text (key=value) or json, one object per line
+		APIKeys       string            `yaml:"api_keys"`       // How API keys appear in logs: hash (a short SHA-256 prefix), prefix (first 8 characters), hidden, or full
+		RedactHeaders []string          `yaml:"redact_headers"` // Request headers logged as [REDACTED] besides Authorization, Cookie and Set-Cookie
+		SampleRate    float64           `yaml:"sample_rate"`    // Fraction of HTTP requests logged (0-1)
+		Routes        map[string]string `yaml:"routes"`         // Path prefix -> verbosity (none, basic, headers)
+		AdminAPIKeys  []string          `yaml:"admin_api_keys"` // API keys allowed to call SetLogLevel whatever their scopes
+	} `yaml:"logging"`
 }
 
 // LoadConfig reads and parses the YAML configuration file.
@@ -39,6 +322,11 @@ func LoadConfig(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %v", filePath, err)
 	}
 	var config Config
+	// Defaults that 0 cannot stand for are set before parsing, so that an
+	// explicit 0 is seen and rejected below
+	config.DGA.Threshold = 0.65
+	config.DGA.BatchSize = 1000
+	config.Zones.BatchSize = 1000
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %v\nEnsure YAML syntax is correct and all required fields are present", filePath, err)
 	}
@@ -60,5 +348,504 @@ func LoadConfig(filePath string) (*Config, error) {
 	if !validSSLModes[config.AlloyDB.SSLMode] {
 		return nil, fmt.Errorf("invalid alloydb.sslmode %s in %s; must be disable, require, verify-ca, or verify-full", config.AlloyDB.SSLMode, filePath)
 	}
+	shardNames := map[string]bool{"default": true}
+	shardTLDs := make(map[string]string)
+	for _, shard := range config.Sharding.Shards {
+		if shard.Name == "" || shardNames[shard.Name] {
+			return nil, fmt.Errorf("invalid shard name %q in %s; names must be unique and not \"default\"", shard.Name, filePath)
+		}
+		shardNames[shard.Name] = true
+		if !validSSLModes[shard.SSLMode] {
+			return nil, fmt.Errorf("invalid sslmode %s for shard %s in %s", shard.SSLMode, shard.Name, filePath)
+		}
+		for _, tld := range shard.TLDs {
+			tld = strings.ToLower(strings.Trim(tld, "."))
+			if owner, ok := shardTLDs[tld]; ok {
+				return nil, fmt.Errorf("TLD %s is mapped to both shard %s and %s in %s", tld, owner, shard.Name, filePath)
+			}
+			shardTLDs[tld] = shard.Name
+		}
+	}
+	if config.Sharding.HealthCheckSeconds == 0 {
+		config.Sharding.HealthCheckSeconds = 30
+	}
+	if config.RecordData.CompressMinBytes < 0 {
+		return nil, fmt.Errorf("record_data.compress_min_bytes must not be negative in %s", filePath)
+	}
+	if config.CZDS.AuthURL == "" {
+		config.CZDS.AuthURL = "https://account-api.icann.org/api/authenticate"
+	}
+	if config.CZDS.APIURL == "" {
+		config.CZDS.APIURL = "https://czds-api.icann.org"
+	}
+	if config.CZDS.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("invalid czds.max_concurrent %d in %s; must not be negative", config.CZDS.MaxConcurrent, filePath)
+	}
+	if config.CZDS.MaxConcurrent == 0 {
+		config.CZDS.MaxConcurrent = 2
+	}
+	if config.CZDS.RequestsPerMinute < 0 {
+		return nil, fmt.Errorf("invalid czds.requests_per_minute %d in %s; must not be negative", config.CZDS.RequestsPerMinute, filePath)
+	}
+	if config.CZDS.RequestsPerMinute == 0 {
+		config.CZDS.RequestsPerMinute = 10
+	}
+	if config.CZDS.MaxRetries == 0 {
+		config.CZDS.MaxRetries = 5
+	}
+	if config.CZDS.TimeoutMinutes == 0 {
+		config.CZDS.TimeoutMinutes = 60
+	}
+	if config.DNSQuery.TTLAnomalyRatio == 0 {
+		config.DNSQuery.TTLAnomalyRatio = 10
+	}
+	if config.DNSQuery.TTLAnomalyRatio < 1 {
+		return nil, fmt.Errorf("invalid dns_query.ttl_anomaly_ratio %v in %s; must be at least 1", config.DNSQuery.TTLAnomalyRatio, filePath)
+	}
+	if config.DNSQuery.DomainMaxQueries == 0 {
+		config.DNSQuery.DomainMaxQueries = 50
+	}
+	if config.DNSQuery.DomainMaxSeconds == 0 {
+		config.DNSQuery.DomainMaxSeconds = 120
+	}
+	if config.DNSQuery.TLDMaxQueries == 0 {
+		config.DNSQuery.TLDMaxQueries = 2000
+	}
+	if config.DNSQuery.TLDMaxSeconds == 0 {
+		config.DNSQuery.TLDMaxSeconds = 1800
+	}
+	if config.DNSQuery.NSSkipAfterTimeouts == 0 {
+		config.DNSQuery.NSSkipAfterTimeouts = 5
+	}
+	if config.DNSQuery.NSSkipMinutes == 0 {
+		config.DNSQuery.NSSkipMinutes = 30
+	}
+	if config.DNSQuery.ServiceNames == nil {
+		config.DNSQuery.ServiceNames = []string{"_sip._tls", "_sip._tcp", "_sip._udp", "_sips._tcp"}
+	}
+	if config.DNSQuery.TLSANames == nil {
+		config.DNSQuery.TLSANames = []string{"_443._tcp", "_25._tcp"}
+	}
+	if config.Resolver.Transport == "" {
+		config.Resolver.Transport = "udp"
+	}
+	if config.Resolver.Transport != "udp" && config.Resolver.Transport != "tcp" && config.Resolver.Transport != "tcp-tls" && config.Resolver.Transport != "https" {
+		return nil, fmt.Errorf("invalid resolver.transport %q in %s; must be udp, tcp, tcp-tls or https", config.Resolver.Transport, filePath)
+	}
+	if config.Resolver.TimeoutSeconds == 0 {
+		config.Resolver.TimeoutSeconds = 10
+	}
+	if config.Resolver.CacheSize == 0 {
+		config.Resolver.CacheSize = 10000
+	}
+	if config.Resolver.CacheMaxTTLSeconds == 0 {
+		config.Resolver.CacheMaxTTLSeconds = 3600
+	}
+	if config.Resolver.NegativeTTLSeconds == 0 {
+		config.Resolver.NegativeTTLSeconds = 300
+	}
+	if config.Resolver.HealthCheckSeconds == 0 {
+		config.Resolver.HealthCheckSeconds = 30
+	}
+	if config.Resolver.UnhealthyAfter == 0 {
+		config.Resolver.UnhealthyAfter = 3
+	}
+	if config.Resolver.HealthyAfter == 0 {
+		config.Resolver.HealthyAfter = 2
+	}
+	if config.Resolver.ProbeName == "" {
+		config.Resolver.ProbeName = "."
+	}
+	if config.Resolver.ServersRefreshSeconds == 0 {
+		config.Resolver.ServersRefreshSeconds = 60
+	}
+	if config.Ingest.ListenAddress == "" {
+		config.Ingest.ListenAddress = "127.0.0.1:50052"
+	}
+	if t := config.Ingest.TLS; (t.CertFile == "") != (t.KeyFile == "") || (t.ClientCertFile == "") != (t.ClientKeyFile == "") {
+		return nil, fmt.Errorf("ingest.tls.cert_file and key_file, and client_cert_file and client_key_file, must be set together in %s", filePath)
+	}
+	if config.Ingest.TLS.ClientCAFile != "" && config.Ingest.TLS.CertFile == "" {
+		return nil, fmt.Errorf("ingest.tls.client_ca_file requires ingest.tls.cert_file and key_file in %s", filePath)
+	}
+	if config.Ingest.FlushSize == 0 {
+		config.Ingest.FlushSize = 5000
+	}
+	if config.Ingest.FlushIntervalMs == 0 {
+		config.Ingest.FlushIntervalMs = 1000
+	}
+	if config.Ingest.MaxBuffered == 0 {
+		config.Ingest.MaxBuffered = 200000
+	}
+	if config.Ingest.MaxBuffered < config.Ingest.FlushSize {
+		return nil, fmt.Errorf("invalid ingest.max_buffered %d in %s; must be at least ingest.flush_size", config.Ingest.MaxBuffered, filePath)
+	}
+	if config.Ingest.MaxWriteAttempts == 0 {
+		config.Ingest.MaxWriteAttempts = 5
+	}
+	if config.Ingest.MaxWriteAttempts < 0 {
+		return nil, fmt.Errorf("invalid ingest.max_write_attempts %d in %s; must not be negative", config.Ingest.MaxWriteAttempts, filePath)
+	}
+	if config.Ingest.Pressure.CheckIntervalMs == 0 {
+		config.Ingest.Pressure.CheckIntervalMs = 1000
+	}
+	if config.Ingest.Pressure.MaxPauseSeconds == 0 {
+		config.Ingest.Pressure.MaxPauseSeconds = 300
+	}
+	if p := config.Ingest.Pressure; p.CheckIntervalMs < 0 || p.MaxReplicationLagSeconds < 0 || p.MaxActiveConnections < 0 || p.MaxLockWaits < 0 || p.MaxPauseSeconds < 0 {
+		return nil, fmt.Errorf("invalid ingest.pressure in %s; values must not be negative", filePath)
+	}
+	if config.Leases.TTLSeconds == 0 {
+		config.Leases.TTLSeconds = 120
+	}
+	if config.Leases.ReapIntervalSeconds == 0 {
+		config.Leases.ReapIntervalSeconds = 60
+	}
+	if config.Leases.AlertAfterFailures == 0 {
+		config.Leases.AlertAfterFailures = 3
+	}
+	if config.Shadow.Percent < 0 || config.Shadow.Percent > 100 {
+		return nil, fmt.Errorf("invalid shadow.percent %v in %s; must be between 0 and 100", config.Shadow.Percent, filePath)
+	}
+	if config.Shadow.Percent > 0 {
+		if config.Shadow.Host == "" {
+			return nil, fmt.Errorf("missing shadow.host in %s", filePath)
+		}
+		if !validSSLModes[config.Shadow.SSLMode] {
+			return nil, fmt.Errorf("invalid shadow.sslmode %s in %s", config.Shadow.SSLMode, filePath)
+		}
+	}
+	if config.Shadow.TimeoutSeconds == 0 {
+		config.Shadow.TimeoutSeconds = 5
+	}
+	if config.Shadow.MaxInFlight == 0 {
+		config.Shadow.MaxInFlight = 32
+	}
+	if config.Hedging.DelayMs == 0 {
+		config.Hedging.DelayMs = 25
+	}
+	if len(config.Hedging.Methods) == 0 {
+		config.Hedging.Methods = []string{"GetRecords", "CheckDomains", "GetServiceRecords", "GetTTLStats"}
+	}
+	if config.DGA.Threshold <= 0 || config.DGA.Threshold > 1 {
+		return nil, fmt.Errorf("invalid dga.threshold %v in %s; must be greater than 0 and at most 1", config.DGA.Threshold, filePath)
+	}
+	if config.DGA.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid dga.batch_size %d in %s; must be positive", config.DGA.BatchSize, filePath)
+	}
+	if config.Zones.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid zones.batch_size %d in %s; must be positive", config.Zones.BatchSize, filePath)
+	}
+	if config.Zones.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("invalid zones.max_concurrent %d in %s; must not be negative", config.Zones.MaxConcurrent, filePath)
+	}
+	if config.Zones.MaxConcurrent == 0 {
+		config.Zones.MaxConcurrent = 1
+	}
+	if config.Zones.WriteMethod == "" {
+		config.Zones.WriteMethod = "insert"
+	}
+	if config.Zones.WriteMethod != "insert" && config.Zones.WriteMethod != "copy" {
+		return nil, fmt.Errorf("invalid zones.write_method %q in %s; must be insert or copy", config.Zones.WriteMethod, filePath)
+	}
+	if len(config.Merge.Precedence) == 0 {
+		config.Merge.Precedence = []string{"QUERY", "CZDS"}
+	}
+	for i, source := range config.Merge.Precedence {
+		config.Merge.Precedence[i] = strings.ToUpper(source)
+	}
+	if config.Analytics.TopN == 0 {
+		config.Analytics.TopN = 100
+	}
+	if config.Analytics.KeywordMinCount == 0 {
+		config.Analytics.KeywordMinCount = 3
+	}
+	if config.Analytics.ClusterLookbackDays == 0 {
+		config.Analytics.ClusterLookbackDays = 30
+	}
+	if config.Analytics.ClusterWindowHours == 0 {
+		config.Analytics.ClusterWindowHours = 24
+	}
+	if config.Analytics.ClusterMinSize == 0 {
+		config.Analytics.ClusterMinSize = 3
+	}
+	if config.Analytics.ClusterMaxSize == 0 {
+		config.Analytics.ClusterMaxSize = 5000
+	}
+	if config.Analytics.ActivityBackfillDays == 0 {
+		config.Analytics.ActivityBackfillDays = 30
+	}
+	if config.Analytics.ActivityRetentionDays == 0 {
+		config.Analytics.ActivityRetentionDays = 400
+	}
+	if config.Analytics.ActivityBackfillDays < 0 || config.Analytics.ActivityRetentionDays < config.Analytics.ActivityBackfillDays {
+		return nil, fmt.Errorf("invalid analytics.activity_backfill_days %d and activity_retention_days %d in %s; need 0 <= backfill <= retention", config.Analytics.ActivityBackfillDays, config.Analytics.ActivityRetentionDays, filePath)
+	}
+	if config.Analytics.ClusterMinSize < 2 || config.Analytics.ClusterMaxSize < config.Analytics.ClusterMinSize {
+		return nil, fmt.Errorf("invalid analytics.cluster_min_size %d and cluster_max_size %d in %s; need 2 <= min <= max", config.Analytics.ClusterMinSize, config.Analytics.ClusterMaxSize, filePath)
+	}
+	if config.Analytics.Keywords == nil {
+		config.Analytics.Keywords = []string{"login", "signin", "verify", "secure", "account", "support", "update", "wallet", "bank", "pay"}
+	}
+	if len(config.Billing.Formats) == 0 {
+		config.Billing.Formats = []string{"csv", "json"}
+	}
+	for _, format := range config.Billing.Formats {
+		if format != "csv" && format != "json" {
+			return nil, fmt.Errorf("invalid billing.formats entry %q in %s; must be csv or json", format, filePath)
+		}
+	}
+	if config.Reports.MaxRows == 0 {
+		config.Reports.MaxRows = 10000
+	}
+	if config.Reports.SMTP.Port == "" {
+		config.Reports.SMTP.Port = "587"
+	}
+	if len(config.Gateway.AllowedOrigins) == 0 {
+		config.Gateway.AllowedOrigins = []string{"http://localhost:3000"}
+	}
+	if config.Gateway.SwaggerAssets == "" {
+		config.Gateway.SwaggerAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+	}
+	config.Gateway.SwaggerAssets = strings.TrimSuffix(config.Gateway.SwaggerAssets, "/")
+	if (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return nil, fmt.Errorf("tls.cert_file and tls.key_file must be set together in %s", filePath)
+	}
+	if config.TLS.ClientCAFile != "" && config.TLS.CertFile == "" {
+		return nil, fmt.Errorf("tls.client_ca_file requires tls.cert_file and tls.key_file in %s", filePath)
+	}
+	if config.Gateway.HTTP3 && config.TLS.CertFile == "" {
+		return nil, fmt.Errorf("gateway.http3 requires tls.cert_file and tls.key_file in %s", filePath)
+	}
+	if config.TLS.ReloadSeconds == 0 {
+		config.TLS.ReloadSeconds = 60
+	}
+	if config.RateLimit.RequestsPerSecond < 0 || config.RateLimit.Burst < 0 {
+		return nil, fmt.Errorf("invalid rate_limit in %s; requests_per_second and burst must not be negative", filePath)
+	}
+	if config.RateLimit.RequestsPerSecond > 0 && config.RateLimit.Burst == 0 {
+		config.RateLimit.Burst = max(1, int(math.Ceil(config.RateLimit.RequestsPerSecond)))
+	}
+	if config.Authorization.DefaultScope == "" {
+		config.Authorization.DefaultScope = "read"
+	}
+	if !validScope(config.Authorization.DefaultScope) {
+		return nil, fmt.Errorf("invalid authorization.default_scope %q in %s; must be read, write or admin, optionally with an area as in read:records", config.Authorization.DefaultScope, filePath)
+	}
+	for method, scope := range config.Authorization.Methods {
+		if !validScope(scope) {
+			return nil, fmt.Errorf("invalid authorization.methods scope %q for %s in %s; must be read, write or admin, optionally with an area as in read:records", scope, method, filePath)
+		}
+	}
+	for role, scopes := range config.Authorization.Roles {
+		for _, scope := range scopes {
+			if !validScope(scope) {
+				return nil, fmt.Errorf("invalid authorization.roles scope %q for role %q in %s; must be read, write or admin, optionally with an area as in read:records", scope, role, filePath)
+			}
+		}
+	}
+	if config.RDAP.BootstrapURL == "" {
+		config.RDAP.BootstrapURL = "https://data.iana.org/rdap/dns.json"
+	}
+	if config.RDAP.RegistrarRegistryURL == "" {
+		config.RDAP.RegistrarRegistryURL = "https://www.iana.org/assignments/registrar-ids/registrar-ids-1.csv"
+	}
+	if config.Canary.IntervalSeconds > 0 {
+		if config.Canary.APIKey == "" || len(config.Canary.Domains) == 0 {
+			return nil, fmt.Errorf("invalid canary in %s; api_key and domains are required when interval_seconds is set", filePath)
+		}
+		if config.Canary.TimeoutSeconds == 0 {
+			config.Canary.TimeoutSeconds = 10
+		}
+		if config.Canary.FailureThreshold == 0 {
+			config.Canary.FailureThreshold = 3
+		}
+		if len(config.Canary.LiveDomains) == 0 {
+			config.Canary.LiveDomains = config.Canary.Domains
+		}
+	}
+	if config.Health.IntervalSeconds == 0 {
+		config.Health.IntervalSeconds = 10
+	}
+	if config.Health.TimeoutSeconds == 0 {
+		config.Health.TimeoutSeconds = 2
+	}
+	if config.Health.IntervalSeconds < 0 || config.Health.TimeoutSeconds < 0 || config.Health.MaxIngestionLagHours < 0 {
+		return nil, fmt.Errorf("invalid health in %s; interval_seconds, timeout_seconds and max_ingestion_lag_hours must not be negative", filePath)
+	}
+	if config.Freshness.IntervalSeconds == 0 {
+		config.Freshness.IntervalSeconds = 300
+	}
+	if config.Freshness.TimeoutSeconds == 0 {
+		config.Freshness.TimeoutSeconds = 60
+	}
+	if config.Freshness.IntervalSeconds < 0 || config.Freshness.TimeoutSeconds < 0 {
+		return nil, fmt.Errorf("invalid freshness in %s; interval_seconds and timeout_seconds must not be negative", filePath)
+	}
+	sloNames := make(map[string]bool)
+	for i := range config.Freshness.SLOs {
+		slo := &config.Freshness.SLOs[i]
+		slo.Source = strings.ToUpper(slo.Source)
+		if slo.Scope == "" {
+			slo.Scope = "all"
+		}
+		if slo.Name == "" {
+			slo.Name = strings.ToLower(slo.Source) + "-" + slo.Scope
+		}
+		if slo.TargetPercent == 0 {
+			slo.TargetPercent = 99
+		}
+		switch {
+		case slo.Source == "":
+			return nil, fmt.Errorf("invalid freshness SLO %s in %s; source is required", slo.Name, filePath)
+		case slo.Scope != "all" && slo.Scope != "watched":
+			return nil, fmt.Errorf("invalid freshness SLO %s in %s; scope must be all or watched", slo.Name, filePath)
+		case slo.MaxAgeHours <= 0:
+			return nil, fmt.Errorf("invalid freshness SLO %s in %s; max_age_hours must be positive", slo.Name, filePath)
+		case slo.TargetPercent < 0 || slo.TargetPercent > 100:
+			return nil, fmt.Errorf("invalid freshness SLO %s in %s; target_percent must be between 0 and 100", slo.Name, filePath)
+		case sloNames[slo.Name]:
+			return nil, fmt.Errorf("invalid freshness SLOs in %s; %s is defined twice", filePath, slo.Name)
+		}
+		sloNames[slo.Name] = true
+	}
+	if config.Shutdown.TimeoutSeconds == 0 {
+		config.Shutdown.TimeoutSeconds = 30
+	}
+	if config.Shutdown.DelaySeconds < 0 || config.Shutdown.TimeoutSeconds < 0 {
+		return nil, fmt.Errorf("invalid shutdown in %s; delay_seconds and timeout_seconds must not be negative", filePath)
+	}
+	if config.Coverage.TLDListURL == "" {
+		config.Coverage.TLDListURL = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+	}
+	if config.RDAP.TimeoutSeconds == 0 {
+		config.RDAP.TimeoutSeconds = 10
+	}
+	if config.RDAP.CacheHours == 0 {
+		config.RDAP.CacheHours = 24
+	}
+	if config.DomainFilter.ExpectedDomains == 0 {
+		config.DomainFilter.ExpectedDomains = 10000000
+	}
+	if config.DomainFilter.FalsePositiveRate == 0 {
+		config.DomainFilter.FalsePositiveRate = 0.01
+	}
+	if config.DomainFilter.FalsePositiveRate <= 0 || config.DomainFilter.FalsePositiveRate >= 1 {
+		return nil, fmt.Errorf("invalid domain_filter.false_positive_rate %v in %s; must be between 0 and 1", config.DomainFilter.FalsePositiveRate, filePath)
+	}
+	if config.DomainFilter.RefreshSeconds == 0 {
+		config.DomainFilter.RefreshSeconds = 60
+	}
+	if config.DomainFilter.RebuildHours == 0 {
+		config.DomainFilter.RebuildHours = 24
+	}
+	if config.Counts.ExactThreshold == 0 {
+		config.Counts.ExactThreshold = 1000000
+	}
+	if config.Counts.TimeoutSeconds == 0 {
+		config.Counts.TimeoutSeconds = 5
+	}
+	if config.RecordSearch.TimeoutSeconds == 0 {
+		config.RecordSearch.TimeoutSeconds = 10
+	}
+	if config.RecordSearch.MaxResults == 0 {
+		config.RecordSearch.MaxResults = 1000
+	}
+	if config.Wildcards.MaxExpansion == 0 {
+		config.Wildcards.MaxExpansion = 100
+	}
+	if config.Wildcards.MaxCost == 0 {
+		config.Wildcards.MaxCost = 50000
+	}
+	if config.Wildcards.MaxExpansion < 0 || config.Wildcards.MaxCost < 0 {
+		return nil, fmt.Errorf("invalid wildcards.max_expansion %d or max_cost %v in %s; must not be negative", config.Wildcards.MaxExpansion, config.Wildcards.MaxCost, filePath)
+	}
+	if config.Tracing.SampleRate == 0 {
+		config.Tracing.SampleRate = 1
+	}
+	if config.Tracing.SampleRate < 0 || config.Tracing.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid tracing.sample_rate %v in %s; must be between 0 and 1", config.Tracing.SampleRate, filePath)
+	}
+	// The server serves export files from output_dir, so with a directory
+	// local to one host, downloads from other replicas would fail
+	if config.Exports.OutputDir != "" && config.Exports.SigningKey != "" && !config.Exports.SharedStorage {
+		return nil, fmt.Errorf("exports.output_dir in %s must be shared storage mounted by the export worker and every server replica; set exports.shared_storage: true once it is", filePath)
+	}
+	if config.Exports.URLTTLMinutes == 0 {
+		config.Exports.URLTTLMinutes = 60
+	}
+	if config.Exports.RetentionHours == 0 {
+		config.Exports.RetentionHours = 72
+	}
+	if config.Exports.PollSeconds == 0 {
+		config.Exports.PollSeconds = 30
+	}
+	if config.Exports.MaxRows == 0 {
+		config.Exports.MaxRows = 100000000
+	}
+	if config.Exports.Scrub.Hash == nil {
+		config.Exports.Scrub.Hash = []string{"domain", "nameservers", "record_data"}
+	}
+	if config.Lifecycle.TransferCheckSeconds == 0 {
+		config.Lifecycle.TransferCheckSeconds = 300
+	}
+	if config.Lifecycle.TransferLookups == 0 {
+		config.Lifecycle.TransferLookups = 100
+	}
+	if config.Lifecycle.LikelyAfterHours == 0 {
+		config.Lifecycle.LikelyAfterHours = 24
+	}
+	if config.Lifecycle.TimeoutSeconds == 0 {
+		config.Lifecycle.TimeoutSeconds = 30
+	}
+	if config.Logging.Level == "" {
+		config.Logging.Level = "info"
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.Logging.Level)); err != nil {
+		return nil, fmt.Errorf("invalid logging.level %s in %s; must be debug, info, warn, or error", config.Logging.Level, filePath)
+	}
+	if config.Logging.SampleRate == 0 {
+		config.Logging.SampleRate = 1
+	}
+	if config.Logging.Format == "" {
+		config.Logging.Format = "text"
+	}
+	if config.Logging.Format != "text" && config.Logging.Format != "json" {
+		return nil, fmt.Errorf("invalid logging.format %q in %s; must be text or json", config.Logging.Format, filePath)
+	}
+	if config.Logging.APIKeys == "" {
+		config.Logging.APIKeys = "hash"
+	}
+	if config.Logging.APIKeys != "hash" && config.Logging.APIKeys != "prefix" && config.Logging.APIKeys != "hidden" && config.Logging.APIKeys != "full" {
+		return nil, fmt.Errorf("invalid logging.api_keys %q in %s; must be hash, prefix, hidden, or full", config.Logging.APIKeys, filePath)
+	}
+	for module, name := range config.Logging.Modules {
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("invalid logging.modules level %s for %s in %s; must be debug, info, warn, or error", name, module, filePath)
+		}
+	}
+	if config.Logging.SampleRate < 0 || config.Logging.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid logging.sample_rate %v in %s; must be between 0 and 1", config.Logging.SampleRate, filePath)
+	}
+	for prefix, verbosity := range config.Logging.Routes {
+		if verbosity != "none" && verbosity != "basic" && verbosity != "headers" {
+			return nil, fmt.Errorf("invalid logging.routes verbosity %s for %s in %s; must be none, basic, or headers", verbosity, prefix, filePath)
+		}
+	}
+	if config.Delta.TimeoutSeconds == 0 {
+		config.Delta.TimeoutSeconds = 30
+	}
 	return &config, nil
 }
+
+// validScope reports whether scope is one of the authorization scopes, read,
+// write or admin, optionally narrowed to an area of RPCs (read:records). The
+// server checks the area names.
+func validScope(scope string) bool {
+	level, area, narrowed := strings.Cut(scope, ":")
+	if narrowed && (area == "" || strings.Trim(area, "abcdefghijklmnopqrstuvwxyz") != "") {
+		return false
+	}
+	return level == "read" || level == "write" || level == "admin"
+}
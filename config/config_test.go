@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadYAML loads a configuration with the required alloydb settings and
+// extra appended.
+func loadYAML(t *testing.T, extra string) (*Config, error) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	data := "alloydb:\n  host: localhost\n  user: bell\n  database: bell\n  sslmode: disable\n" + extra
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadConfig(file)
+}
+
+func TestBatchSizes(t *testing.T) {
+	cfg, err := loadYAML(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DGA.BatchSize != 1000 || cfg.Zones.BatchSize != 1000 {
+		t.Errorf("default batch sizes %d and %d, want 1000", cfg.DGA.BatchSize, cfg.Zones.BatchSize)
+	}
+	cfg, err = loadYAML(t, "dga:\n  batch_size: 50\nzones:\n  batch_size: 200\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DGA.BatchSize != 50 || cfg.Zones.BatchSize != 200 {
+		t.Errorf("batch sizes %d and %d, want 50 and 200", cfg.DGA.BatchSize, cfg.Zones.BatchSize)
+	}
+	for _, extra := range []string{
+		"dga:\n  batch_size: 0\n",
+		"dga:\n  batch_size: -1\n",
+		"zones:\n  batch_size: 0\n",
+		"zones:\n  batch_size: -100\n",
+	} {
+		if _, err := loadYAML(t, extra); err == nil {
+			t.Errorf("accepted %q", extra)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Kinds of TLD. IANA's list does not say which TLDs are country codes;
+// two-letter TLDs are, and internationalized country-code TLDs (xn--p1ai)
+// count as generic.
+const (
+	KindCountryCode = "CCTLD"
+	KindGeneric     = "GTLD"
+)
+
+// Data sources suggested for TLDs missing from the corpus.
+const (
+	SourceCZDS     = "ICANN CZDS: request zone file access for the TLD"
+	SourcePDNS     = "Passive DNS: import observations with pdns"
+	SourceCustomer = "Customer inventories: organizations add their domains with ImportDomains"
+)
+
+// openZones are ccTLD registries that publish their zone files.
+var openZones = map[string]string{
+	"se": "Zone transfer (AXFR) from zonedata.iis.se, published by the Swedish Internet Foundation",
+	"nu": "Zone transfer (AXFR) from zonedata.iis.se, published by the Swedish Internet Foundation",
+	"ch": "Zone transfer (AXFR) from zonedata.switch.ch, published by SWITCH as open data",
+	"li": "Zone transfer (AXFR) from zonedata.switch.ch, published by SWITCH as open data",
+	"ee": "Zone transfer (AXFR) from zone.internet.ee, published by the Estonian Internet Foundation",
+}
+
+// Kind returns KindCountryCode for two-letter TLDs and KindGeneric for the
+// others.
+func Kind(tld string) string {
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	if len(tld) == 2 && strings.Trim(tld, "abcdefghijklmnopqrstuvwxyz") == "" {
+		return KindCountryCode
+	}
+	return KindGeneric
+}
+
+// Sources returns where domains of a TLD missing from the corpus could be
+// obtained, best first: the configured source if any, the registry's own
+// zone file service, CZDS for generic TLDs (whose registry agreements
+// require it), then passive DNS and customer imports, which cover any TLD
+// partially.
+func Sources(tld string, configured map[string]string) []string {
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	var sources []string
+	if source, ok := configured[tld]; ok && source != "" {
+		sources = append(sources, source)
+	}
+	if source, ok := openZones[tld]; ok {
+		sources = append(sources, source)
+	}
+	if Kind(tld) == KindGeneric {
+		sources = append(sources, SourceCZDS)
+	}
+	return append(sources, SourcePDNS, SourceCustomer)
+}
+
+// ParseTLDList parses IANA's list of TLDs (tlds-alpha-by-domain.txt): one
+// uppercase TLD per line after a # comment with the list's version. It
+// returns the TLDs lowercased and sorted.
+func ParseTLDList(r io.Reader) ([]string, error) {
+	var tlds []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tld := strings.ToLower(line)
+		if strings.Trim(tld, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return nil, fmt.Errorf("invalid TLD %q in TLD list", line)
+		}
+		tlds = append(tlds, tld)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read TLD list: %v", err)
+	}
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("TLD list is empty")
+	}
+	sort.Strings(tlds)
+	return tlds, nil
+}
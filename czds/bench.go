@@ -0,0 +1,126 @@
+package czds
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Benchmarks of zone ingestion against generated zones (see the fixtures
+// package), run by go test -bench and by the bench tool with
+// testing.Benchmark, so they live outside _test files.
+
+// ParseZoneBench measures parsing zone, a zone file of tld, into
+// batches of batchSize records, without storing them.
+func ParseZoneBench(b *testing.B, zone []byte, tld string, batchSize int) {
+	// The parser logs the records it skips, such as the zone's own SOA and NS
+	defer logger.Override(slog.LevelError)()
+
+	b.SetBytes(int64(len(zone)))
+	b.ReportAllocs()
+	var records int
+	for i := 0; i < b.N; i++ {
+		records = 0
+		err := parseZoneFile(context.Background(), bytes.NewReader(zone), tld, batchSize, func(batch []zoneRecord, nameservers map[string][]string) error {
+			records += len(batch)
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(records)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+}
+
+// StoreRecordsBench measures the batch writer: upserting zone, already
+// parsed into batches of batchSize records, into db. The first iteration
+// inserts the domains and later ones update them, as re-ingesting a zone
+// does.
+func StoreRecordsBench(b *testing.B, db *sql.DB, zone []byte, tld string, batchSize int) {
+	defer logger.Override(slog.LevelError)()
+
+	type batch struct {
+		records     []zoneRecord
+		nameservers map[string][]string
+	}
+	var batches []batch
+	records := 0
+	err := parseZoneFile(context.Background(), bytes.NewReader(zone), tld, batchSize, func(r []zoneRecord, ns map[string][]string) error {
+		batches = append(batches, batch{r, ns})
+		records += len(r)
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		delta := newDeltaCollector()
+		for _, batch := range batches {
+			if err := storeRecords(context.Background(), db, batch.records, batch.nameservers, delta, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportMetric(float64(records)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+}
+
+// CalibrateWrites stores zones, zone files of the TLDs of the same index,
+// writing concurrency zones at once in batches of batchSize records, copied
+// through a staging table if useCopy is set, and returns the records stored
+// per second. Parsing is not timed. The bench tool's autotune mode compares
+// ingestion settings with it.
+func CalibrateWrites(ctx context.Context, db *sql.DB, zones [][]byte, tlds []string, batchSize int, useCopy bool, concurrency int) (float64, error) {
+	defer logger.Override(slog.LevelError)()
+
+	type batch struct {
+		records     []zoneRecord
+		nameservers map[string][]string
+	}
+	parsed := make(chan []batch, len(zones))
+	records := 0
+	for i, zone := range zones {
+		var batches []batch
+		err := parseZoneFile(ctx, bytes.NewReader(zone), tlds[i], batchSize, func(r []zoneRecord, ns map[string][]string) error {
+			batches = append(batches, batch{r, ns})
+			records += len(r)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		parsed <- batches
+	}
+	close(parsed)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	start := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delta := newDeltaCollector()
+			for zone := range parsed {
+				for _, b := range zone {
+					if err := storeRecords(ctx, db, b.records, b.nameservers, delta, useCopy); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(errs)
+	if err := <-errs; err != nil {
+		return 0, err
+	}
+	return float64(records) / elapsed.Seconds(), nil
+}
@@ -0,0 +1,29 @@
+package czds
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moos3/bell/fixtures"
+)
+
+// benchZone writes a generated zone of 10,000 domains as a zone file.
+func benchZone(b *testing.B) (*fixtures.Zone, []byte) {
+	zone := fixtures.Generate(fixtures.Options{Seed: 1, DomainsPerTLD: 10000}).Zones[0]
+	var zoneFile bytes.Buffer
+	if err := zone.WriteZoneFile(&zoneFile); err != nil {
+		b.Fatal(err)
+	}
+	return zone, zoneFile.Bytes()
+}
+
+func BenchmarkParseZone(b *testing.B) {
+	zone, zoneFile := benchZone(b)
+	ParseZoneBench(b, zoneFile, zone.TLD, 1000)
+}
+
+func BenchmarkStoreRecords(b *testing.B) {
+	db := fixtures.OpenDatabase(b)
+	zone, zoneFile := benchZone(b)
+	StoreRecordsBench(b, db, zoneFile, zone.TLD, 1000)
+}
@@ -1,24 +1,36 @@
 package czds
 
 import (
+	"bufio"
 	"compress/gzip"
+	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/lib/pq"
 	"github.com/miekg/dns"
 	"github.com/moos3/bell/config"
-	_ "golang.org/x/net/publicsuffix"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/lease"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/pressure"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
 )
 
+// logger logs the czds tool's messages (see the logging package).
+var logger = logging.New("czds")
+
 var validRecordTypes = map[string]bool{
 	"NS":     true,
 	"A":      true,
@@ -29,64 +41,86 @@ var validRecordTypes = map[string]bool{
 	"SOA":    true,
 	"PTR":    true,
 	"SRV":    true,
+	"NAPTR":  true,
+	"TLSA":   true,
 	"CAA":    true,
 	"DNSKEY": true,
 	"DS":     true,
 }
 
-func parseZoneFile(reader io.Reader, tld string, batchSize int, processBatch func(records []map[string]interface{}, nameservers map[string][]string) error) error {
+// parseZoneFile parses a zone and hands it to processBatch in batches of
+// about batchSize records. It stops with ctx's error between batches once ctx
+// is cancelled, so every batch handed over is complete.
+//
+// The zone need not be a TLD: second-level registries such as co.uk have
+// zones of their own. Owner names that are public suffixes, such as the
+// zone's apex or co.uk delegated from the uk zone, are apexes of a registry
+// rather than domains and are skipped. Each record carries the public suffix
+// of its domain (see recordset.ZoneSuffix).
+//
+// A zone lists each owner's records together, so the owner's domain, TLD
+// and suffix are worked out once for its first record and shared by the
+// rest; nameserver hosts, TLDs and suffixes are interned for the whole zone
+// (see stringTable). Batches are slices of zoneRecord values, allocated
+// once per batch rather than once per record.
+func parseZoneFile(ctx context.Context, reader io.Reader, tld string, batchSize int, processBatch func(records []zoneRecord, nameservers map[string][]string) error) error {
 	zp := dns.NewZoneParser(reader, tld+".", "")
-	records := make([]map[string]interface{}, 0, batchSize)
+	records := make([]zoneRecord, 0, batchSize)
 	nameservers := make(map[string][]string)
+	names := newStringTable()
+	var owner zoneOwner
 
 	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if rr == nil {
 			continue
 		}
-		domain := rr.Header().Name
-		// Skip root TLD (e.g., aero.)
-		if domain == tld+"." {
-			log.Printf("Skipping root TLD domain %s in TLD %s", domain, tld)
-			continue
+		if rr.Header().Name != owner.name || owner.name == "" {
+			owner = newZoneOwner(rr.Header().Name, tld, names)
 		}
-		// Remove trailing dot from domain
-		domain = strings.TrimSuffix(domain, ".")
-		if domain == "" {
-			log.Printf("Skipping empty domain after trimming in TLD %s", tld)
+		if owner.skip != "" {
+			logger.Debugf("%s", owner.skip)
 			continue
 		}
+		domain := owner.domain
 		recordType := dns.TypeToString[rr.Header().Rrtype]
 		if !validRecordTypes[recordType] {
-			log.Printf("Skipping unsupported record type %s for domain %s in TLD %s", recordType, domain, tld)
+			logger.Debugf("Skipping unsupported record type %s for domain %s in TLD %s", recordType, domain, tld)
 			continue
 		}
-		records = append(records, map[string]interface{}{
-			"domain_name": domain,
-			"record_type": recordType,
-			"record_data": rr.String(),
-			"ttl":         int(rr.Header().Ttl),
-			"tld":         tld,
-			"source":      "CZDS",
+		// Process batch when full, but never split a domain's records across
+		// batches so each batch holds complete record sets for checksums
+		if len(records) >= batchSize && records[len(records)-1].domain != domain {
+			if err := processBatch(records, nameservers); err != nil {
+				return err
+			}
+			// Clear memory
+			records = make([]zoneRecord, 0, batchSize)
+			nameservers = make(map[string][]string)
+		}
+		priority, weight := recordset.SortKeys(rr)
+		records = append(records, zoneRecord{
+			domain:     domain,
+			recordType: recordType,
+			data:       recordset.NormalizeRR(rr),
+			ttl:        int(rr.Header().Ttl),
+			tld:        owner.tld,
+			suffix:     owner.suffix,
+			priority:   priority,
+			weight:     weight,
 		})
 		if recordType == "NS" {
 			if ns, ok := rr.(*dns.NS); ok {
 				nsName := strings.TrimSuffix(ns.Ns, ".")
 				if nsName == "" {
-					log.Printf("Skipping empty nameserver for domain %s in TLD %s", domain, tld)
+					logger.Debugf("Skipping empty nameserver for domain %s in TLD %s", domain, tld)
 					continue
 				}
-				nameservers[domain] = append(nameservers[domain], nsName)
+				nameservers[domain] = append(nameservers[domain], names.intern(nsName))
 			}
 		}
-		// Process batch when full
-		if len(records) >= batchSize {
-			if err := processBatch(records, nameservers); err != nil {
-				return err
-			}
-			// Clear memory
-			records = make([]map[string]interface{}, 0, batchSize)
-			nameservers = make(map[string][]string)
-		}
 	}
 	if err := zp.Err(); err != nil {
 		return fmt.Errorf("error parsing zone file: %v", err)
@@ -100,76 +134,256 @@ func parseZoneFile(reader io.Reader, tld string, batchSize int, processBatch fun
 	return nil
 }
 
-func storeRecords(db *sql.DB, records []map[string]interface{}, nameservers map[string][]string, tld string) error {
-	tx, err := db.Begin()
-	if err != nil {
-		return err
+// zoneRecord is a record parsed from a zone file, always from source CZDS.
+type zoneRecord struct {
+	domain     string
+	recordType string
+	data       string // recordset.NormalizeRR of the record
+	ttl        int
+	tld        string // The domain's own TLD
+	suffix     string // recordset.ZoneSuffix of the domain
+	priority   sql.NullInt32
+	weight     sql.NullInt32
+}
+
+// zoneOwner is what parseZoneFile derives from an owner name once for all of
+// its records.
+type zoneOwner struct {
+	name   string // As in the zone file, with its trailing dot
+	domain string
+	tld    string
+	suffix string
+	skip   string // Why the owner's records are skipped, logged for each; empty to store them
+}
+
+func newZoneOwner(name, tld string, names *stringTable) zoneOwner {
+	owner := zoneOwner{name: name}
+	// Skip root TLD (e.g., aero.)
+	if name == tld+"." {
+		owner.skip = fmt.Sprintf("Skipping root TLD domain %s in TLD %s", name, tld)
+		return owner
+	}
+	// Remove trailing dot from domain
+	owner.domain = strings.TrimSuffix(name, ".")
+	if owner.domain == "" {
+		owner.skip = fmt.Sprintf("Skipping empty domain after trimming in TLD %s", tld)
+		return owner
+	}
+	if recordset.IsPublicSuffix(owner.domain) {
+		owner.skip = fmt.Sprintf("Skipping registry apex %s in zone %s", owner.domain, tld)
+		return owner
 	}
+	owner.tld = names.intern(recordset.TLD(owner.domain))
+	owner.suffix = names.intern(recordset.ZoneSuffix(owner.domain, tld))
+	return owner
+}
 
-	domainStmt, err := tx.Prepare(`
-		INSERT INTO domains (domain_name, tld, nameservers, last_updated)
-		VALUES ($1, $2, $3, $4)
-		ON CONFLICT (domain_name, tld) DO UPDATE
-		SET nameservers = EXCLUDED.nameservers, last_updated = EXCLUDED.last_updated
-		RETURNING id
-	`)
+// batchOptions are how ingestZone writes a zone: records per batch, whether
+// each batch's records are copied into a staging table and upserted from it
+// in one statement (zones.write_method copy) or upserted row by row, and
+// what holds batches while the shard is under load.
+type batchOptions struct {
+	size     int
+	copy     bool
+	pressure *pressure.Monitor // nil never holds batches
+}
+
+func newBatchOptions(cfg *config.Config) batchOptions {
+	return batchOptions{size: cfg.Zones.BatchSize, copy: cfg.Zones.WriteMethod == "copy"}
+}
+
+// storeRecords upserts a batch's domains, records and record set checksums
+// in one transaction, which is rolled back if ctx is cancelled, and then
+// publishes the batch's record set changes on db. Domains are stored under
+// their own TLD and public suffix rather than the zone's name. Records are
+// copied through a staging table if useCopy is set.
+func storeRecords(ctx context.Context, db *sql.DB, records []zoneRecord, nameservers map[string][]string, delta *deltaCollector, useCopy bool) error {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
-	defer domainStmt.Close()
 
-	recordStmt, err := tx.Prepare(`
-		INSERT INTO dns_records (domain_id, record_type, record_data, ttl, source, last_updated)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT DO NOTHING
+	// prev captures the nameservers before the upsert for zone delta reporting
+	domainStmt, err := tx.PrepareContext(ctx, `
+		WITH prev AS (
+			SELECT nameservers FROM domains WHERE domain_name = $1 AND tld = $2
+		)
+		INSERT INTO domains (domain_name, tld, public_suffix, nameservers, last_updated)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (domain_name, tld) DO UPDATE
+		SET public_suffix = EXCLUDED.public_suffix, nameservers = EXCLUDED.nameservers, last_updated = EXCLUDED.last_updated
+		RETURNING id, (xmax = 0) AS inserted, (SELECT nameservers FROM prev)
 	`)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	defer recordStmt.Close()
+	defer domainStmt.Close()
 
 	domainIDs := make(map[string]int)
 	for _, r := range records {
-		domain := r["domain_name"].(string)
+		domain := r.domain
 		if _, exists := domainIDs[domain]; !exists {
 			var domainID int
+			var inserted bool
+			var prevNS pq.StringArray
 			ns := nameservers[domain]
 			if len(ns) == 0 {
 				ns = []string{}
 			}
-			err := domainStmt.QueryRow(domain, tld, pq.StringArray(ns), time.Now().UTC()).Scan(&domainID)
+			err := domainStmt.QueryRowContext(ctx, domain, r.tld, r.suffix, pq.StringArray(ns), time.Now().UTC()).Scan(&domainID, &inserted, &prevNS)
 			if err != nil {
 				tx.Rollback()
 				return fmt.Errorf("failed to insert domain %s: %v", domain, err)
 			}
 			domainIDs[domain] = domainID
+			delta.recordUpsert(domain, inserted, prevNS, ns)
 		}
 	}
 
+	if useCopy {
+		err = copyRecords(ctx, tx, records, domainIDs)
+	} else {
+		err = insertRecords(ctx, tx, records, domainIDs)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	changes, err := storeChecksums(ctx, tx, records, domainIDs, delta)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to store record set checksums: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, e := range changes {
+		events.Publish(db, e)
+	}
+	return nil
+}
+
+// insertRecords upserts records one statement each.
+func insertRecords(ctx context.Context, tx *sql.Tx, records []zoneRecord, domainIDs map[string]int) error {
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO dns_records (domain_id, record_type, record_data, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8, $9)
+	`+recordset.OnRecordConflict)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
 	for _, r := range records {
-		domain := r["domain_name"].(string)
-		domainID := domainIDs[domain]
-		_, err := recordStmt.Exec(
-			domainID,
-			r["record_type"],
-			r["record_data"],
-			r["ttl"],
-			r["source"],
+		_, err := stmt.ExecContext(ctx,
+			domainIDs[r.domain],
+			r.recordType,
+			r.data,
+			recordset.CanonicalHash(r.data),
+			r.ttl,
+			"CZDS",
 			time.Now().UTC(),
+			r.priority,
+			r.weight,
 		)
 		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to insert record for %s: %v", domain, err)
+			return fmt.Errorf("failed to insert record for %s: %v", r.domain, err)
 		}
 	}
+	return nil
+}
 
-	return tx.Commit()
+// copyRecords copies records into a staging table and upserts them from it
+// in one statement, as the ingest service writes its buffer. A record
+// listed twice in the zone, which one upsert cannot touch twice, is stored
+// once.
+func copyRecords(ctx context.Context, tx *sql.Tx, records []zoneRecord, domainIDs map[string]int) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TEMP TABLE zone_staging (
+			domain_id INTEGER, record_type VARCHAR(20), record_data TEXT, canonical_hash BYTEA,
+			ttl INTEGER, last_updated TIMESTAMP, priority INTEGER, weight INTEGER
+		) ON COMMIT DROP
+	`); err != nil {
+		return fmt.Errorf("failed to create staging table: %v", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("zone_staging", "domain_id", "record_type", "record_data", "canonical_hash", "ttl", "last_updated", "priority", "weight"))
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, r := range records {
+		if _, err := stmt.ExecContext(ctx, domainIDs[r.domain], r.recordType, r.data, recordset.CanonicalHash(r.data), r.ttl, now, r.priority, r.weight); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy record for %s: %v", r.domain, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to copy records: %v", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dns_records (domain_id, record_type, record_data, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+		SELECT DISTINCT ON (domain_id, record_type, canonical_hash)
+			domain_id, record_type, record_data, canonical_hash, ttl, 'CZDS', last_updated, last_updated, priority, weight
+		FROM zone_staging
+	`+recordset.OnRecordConflict); err != nil {
+		return fmt.Errorf("failed to insert records: %v", err)
+	}
+	return nil
+}
+
+// storeChecksums records the checksum of each domain's record sets in the
+// batch, and new or changed sets in delta. parseZoneFile keeps a domain's
+// records in one batch, so every set is complete. It returns the
+// record_set_changed events of those sets, to publish once the batch is
+// committed; like deltas, they are only meaningful against a previous
+// ingest of the TLD, so there are none without delta.
+func storeChecksums(ctx context.Context, tx *sql.Tx, records []zoneRecord, domainIDs map[string]int, delta *deltaCollector) ([]events.Event, error) {
+	type setKey struct {
+		domain     string
+		recordType string
+	}
+	sets := make(map[setKey][]string)
+	tlds := make(map[string]string)
+	for _, r := range records {
+		k := setKey{r.domain, r.recordType}
+		sets[k] = append(sets[k], r.data)
+		tlds[r.domain] = r.tld
+	}
+	stmt, err := tx.PrepareContext(ctx, recordset.UpsertChecksum)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	var changes []events.Event
+	for k, data := range sets {
+		var version int64
+		var changed, inserted bool
+		err := stmt.QueryRowContext(ctx, domainIDs[k.domain], k.recordType, "CZDS", recordset.Checksum(data), len(recordset.Set(data)), now).
+			Scan(&version, &changed, &inserted)
+		if err != nil {
+			return nil, err
+		}
+		if changed && delta != nil {
+			delta.recordSetChanged(k.domain, k.recordType, version)
+			// Zone checksums do not keep the old records to classify changes by
+			significance := recordset.SignificanceUnclassified
+			if inserted {
+				significance = recordset.SignificanceNew
+			}
+			changes = append(changes, events.Event{Source: events.SourceCZDS, Kind: events.RecordSetChanged, TLD: tlds[k.domain], Domain: k.domain,
+				RecordType: k.recordType, Version: version, Significance: significance})
+		}
+	}
+	return changes, nil
 }
 
 func getProcessedTLDs(db *sql.DB) (map[string]time.Time, error) {
-	rows, err := db.Query("SELECT tld, last_processed FROM processed_tlds")
+	rows, err := db.Query("SELECT tld, last_processed FROM processed_tlds WHERE last_processed IS NOT NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -187,16 +401,48 @@ func getProcessedTLDs(db *sql.DB) (map[string]time.Time, error) {
 	return processed, rows.Err()
 }
 
-func markTLDProcessed(db *sql.DB, tld string) error {
+// markTLDProcessed records a successful ingest in tx, after which the TLD
+// is not ingested again until reprocess_threshold_hours have passed.
+func markTLDProcessed(ctx context.Context, tx *sql.Tx, tld string, recordCount int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO processed_tlds (tld, first_processed, last_processed, last_attempted, last_status, last_error, domain_count, record_count)
+		VALUES ($1, $2, $2, $2, 'SUCCESS', NULL, (SELECT COUNT(*) FROM domains WHERE public_suffix = $1), $3)
+		ON CONFLICT (tld) DO UPDATE
+		SET first_processed = CASE WHEN processed_tlds.last_processed IS NULL
+				THEN EXCLUDED.first_processed ELSE processed_tlds.first_processed END,
+		    last_processed = EXCLUDED.last_processed, last_attempted = EXCLUDED.last_attempted,
+		    last_status = EXCLUDED.last_status, last_error = NULL,
+		    domain_count = EXCLUDED.domain_count, record_count = EXCLUDED.record_count
+	`, tld, time.Now().UTC(), recordCount)
+	return err
+}
+
+// markTLDFailed records a failed ingest attempt without touching last_processed,
+// so the TLD is retried on the next run.
+func markTLDFailed(db *sql.DB, tld string, ingestErr error) error {
+	_, err := db.Exec(`
+		INSERT INTO processed_tlds (tld, last_attempted, last_status, last_error)
+		VALUES ($1, $2, 'FAILED', $3)
+		ON CONFLICT (tld) DO UPDATE
+		SET last_attempted = EXCLUDED.last_attempted, last_status = EXCLUDED.last_status, last_error = EXCLUDED.last_error
+	`, tld, time.Now().UTC(), ingestErr.Error())
+	return err
+}
+
+// markTLDInterrupted records an ingest stopped by shutdown. The batches
+// stored before it are complete; last_processed is left alone so the TLD is
+// ingested again on the next run.
+func markTLDInterrupted(db *sql.DB, tld string, recordCount int64) error {
 	_, err := db.Exec(`
-		INSERT INTO processed_tlds (tld, last_processed)
-		VALUES ($1, $2)
-		ON CONFLICT (tld) DO UPDATE SET last_processed = $2
-	`, tld, time.Now().UTC())
+		INSERT INTO processed_tlds (tld, last_attempted, last_status, last_error)
+		VALUES ($1, $2, 'INTERRUPTED', $3)
+		ON CONFLICT (tld) DO UPDATE
+		SET last_attempted = EXCLUDED.last_attempted, last_status = EXCLUDED.last_status, last_error = EXCLUDED.last_error
+	`, tld, time.Now().UTC(), fmt.Sprintf("interrupted by shutdown after %d records", recordCount))
 	return err
 }
 
-func processZoneFile(db *sql.DB, entry os.DirEntry, force bool, processedTLDs map[string]time.Time, reprocessThreshold time.Duration, batchSize int, zonesDir string) error {
+func processZoneFile(ctx context.Context, leases *lease.Manager, db *sql.DB, shards *storage.Router, cfg *config.Config, entry os.DirEntry, force bool, processedTLDs map[string]time.Time, reprocessThreshold time.Duration, batch batchOptions, zonesDir string) error {
 	if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt.gz") {
 		return nil
 	}
@@ -208,52 +454,201 @@ func processZoneFile(db *sql.DB, entry os.DirEntry, force bool, processedTLDs ma
 
 	if !force {
 		if lastProcessed, exists := processedTLDs[tld]; exists && time.Since(lastProcessed) < reprocessThreshold {
-			fmt.Printf("Skipping TLD %s: processed recently at %v\n", tld, lastProcessed)
+			logger.Infof("Skipping TLD %s: processed recently at %v", tld, lastProcessed)
 			return nil
 		}
 	}
 
-	fmt.Printf("Processing TLD: %s\n", tld)
+	logger.Infof("Processing TLD: %s", tld)
 	filePath := filepath.Join(zonesDir, entry.Name())
+	// ForDomain rather than ForTLD so reverse zones such as 10.in-addr.arpa
+	// land on the shard owning arpa
+	shard := shards.ForDomain(tld)
+	return ingestTLD(ctx, leases, db, shard.DB, cfg, tld, processedTLDs, func(ctx context.Context, delta *deltaCollector, progress func(int)) (int64, error) {
+		return ingestZoneFile(ctx, shard, filePath, tld, batch, delta, progress)
+	})
+}
+
+// ingestTLD runs ingest for a TLD, records the outcome in processed_tlds, and
+// publishes a zone delta if configured. dataDB is the shard owning the TLD.
+// Progress is published as events on db for TailEvents. An ingest cancelled
+// by shutdown is recorded as INTERRUPTED rather than FAILED.
+//
+// The TLD is leased for the duration of the ingest. A TLD leased by another
+// worker is skipped, and ingest's context is cancelled if the lease is lost.
+func ingestTLD(ctx context.Context, leases *lease.Manager, db, dataDB *sql.DB, cfg *config.Config, tld string, processedTLDs map[string]time.Time, ingest func(ctx context.Context, delta *deltaCollector, progress func(records int)) (int64, error)) (err error) {
+	held, leaseCtx, err := leases.Acquire(ctx, lease.KindTLD, tld)
+	if errors.Is(err, lease.ErrHeld) {
+		logger.Infof("Skipping TLD %s: being ingested by another worker", tld)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { held.Release(err) }()
+
+	started := time.Now()
+	events.Publish(db, events.Event{Source: events.SourceCZDS, Kind: events.ZoneStarted, TLD: tld})
+	defer func() {
+		if err != nil {
+			events.Publish(db, events.Event{Source: events.SourceCZDS, Kind: events.ZoneFailed, TLD: tld, Message: err.Error()})
+		}
+	}()
+	progress := func(records int) {
+		events.Publish(db, events.Event{Source: events.SourceCZDS, Kind: events.BatchCommitted, TLD: tld, Count: int64(records)})
+	}
+	// Deltas and nameserver change events are only meaningful against a
+	// previous ingest of the same TLD
+	var delta *deltaCollector
+	previous, processedBefore := processedTLDs[tld]
+	if processedBefore {
+		delta = newDeltaCollector()
+	}
+	recordCount, err := ingest(leaseCtx, delta, progress)
+	if errors.Is(err, context.Canceled) {
+		// A lost lease has already been re-queued by the reaper
+		if ctx.Err() != nil {
+			if markErr := markTLDInterrupted(db, tld, recordCount); markErr != nil {
+				logger.Errorf("Error marking %s as interrupted: %v", tld, markErr)
+			}
+		}
+		return err
+	}
+	if err != nil {
+		if markErr := markTLDFailed(db, tld, err); markErr != nil {
+			logger.Errorf("Error marking %s as failed: %v", tld, markErr)
+		}
+		return err
+	}
+
+	// Removals and nameserver changes are recorded before the TLD is marked
+	// processed, so that a failure in between has the next run ingest it
+	// again rather than lose them. Removals are recorded whether or not
+	// deltas are published, so that a delta only lists its own ingest's.
+	if delta != nil {
+		if err := delta.recordRemovals(ctx, dataDB, tld, started, previous); err != nil {
+			return err
+		}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stored := 0
+	if delta != nil {
+		if stored, err = delta.storeLifecycleEvents(ctx, tx); err != nil {
+			return fmt.Errorf("failed to store lifecycle events for %s: %v", tld, err)
+		}
+	}
+	if err := markTLDProcessed(ctx, tx, tld, recordCount); err != nil {
+		return fmt.Errorf("error marking %s as processed: %v", tld, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error marking %s as processed: %v", tld, err)
+	}
+	if delta != nil {
+		logger.Infof("Recorded %d nameserver changes for %s", stored, tld)
+	}
+	if delta != nil && (cfg.Delta.WebhookURL != "" || cfg.Delta.OutputDir != "") {
+		zoneDelta := delta.build(tld, started, previous)
+		if err := publishDelta(cfg, zoneDelta); err != nil {
+			return err
+		}
+		logger.Infof("Published delta for %s: %d added, %d removed, %d changed, %d record sets", tld,
+			len(zoneDelta.Added), len(zoneDelta.Removed), len(zoneDelta.Changed), len(zoneDelta.RecordSets))
+	}
+	logger.Infof("Completed processing %s", tld)
+	events.Publish(db, events.Event{Source: events.SourceCZDS, Kind: events.ZoneCompleted, TLD: tld, Count: recordCount})
+	return nil
+}
+
+func ingestZoneFile(ctx context.Context, shard *storage.Shard, filePath, tld string, batch batchOptions, delta *deltaCollector, progress func(records int)) (int64, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return fmt.Errorf("error opening zone file for %s: %v", tld, err)
+		return 0, fmt.Errorf("error opening zone file for %s: %v", tld, err)
 	}
 	defer file.Close()
 
 	gzReader, err := gzip.NewReader(file)
 	if err != nil {
-		return fmt.Errorf("error decompressing zone file for %s: %v", tld, err)
+		return 0, fmt.Errorf("error decompressing zone file for %s: %v", tld, err)
 	}
 	defer gzReader.Close()
+	return ingestZone(ctx, shard, gzReader, tld, batch, delta, progress)
+}
 
-	err = parseZoneFile(gzReader, tld, batchSize, func(records []map[string]interface{}, nameservers map[string][]string) error {
-		if err := storeRecords(db, records, nameservers, tld); err != nil {
-			return fmt.Errorf("error storing records for %s: %v", tld, err)
+// ingestStream ingests zone data piped on r (e.g. dig AXFR output or a
+// decompression pipeline). Gzip-compressed input is detected and decompressed.
+func ingestStream(ctx context.Context, shard *storage.Shard, r io.Reader, tld string, batch batchOptions, delta *deltaCollector, progress func(records int)) (int64, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzReader, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, fmt.Errorf("error decompressing zone data for %s: %v", tld, err)
 		}
-		fmt.Printf("Stored %d records for %s\n", len(records), tld)
-		return nil
-	})
-	if err != nil {
-		return err
+		defer gzReader.Close()
+		return ingestZone(ctx, shard, gzReader, tld, batch, delta, progress)
 	}
+	return ingestZone(ctx, shard, br, tld, batch, delta, progress)
+}
 
-	if err := markTLDProcessed(db, tld); err != nil {
-		return fmt.Errorf("error marking %s as processed: %v", tld, err)
+// ingestZone stores the zone read from r on shard batch by batch, holding
+// each batch while the shard is under pressure, and returns the number of
+// records stored, which on error counts only committed batches.
+func ingestZone(ctx context.Context, shard *storage.Shard, r io.Reader, tld string, batch batchOptions, delta *deltaCollector, progress func(records int)) (int64, error) {
+	var recordCount int64
+	reverse := recordset.IsReverseZone(tld)
+	err := parseZoneFile(ctx, r, tld, batch.size, func(records []zoneRecord, nameservers map[string][]string) error {
+		slow := batch.pressure.Wait(shard, len(records), ctx.Done())
+		began := time.Now()
+		domainRecords := records
+		if reverse {
+			var ptrs []ptrRecord
+			ptrs, domainRecords = splitPTRRecords(records)
+			if err := storePTRRecords(ctx, shard.DB, ptrs, tld); err != nil {
+				return fmt.Errorf("error storing PTR records for %s: %v", tld, err)
+			}
+		}
+		if len(domainRecords) > 0 {
+			if err := storeRecords(ctx, shard.DB, domainRecords, nameservers, delta, batch.copy); err != nil {
+				return fmt.Errorf("error storing records for %s: %v", tld, err)
+			}
+		}
+		if slow {
+			time.Sleep(time.Since(began))
+		}
+		recordCount += int64(len(records))
+		logger.Infof("Stored %d records for %s", len(records), tld)
+		progress(len(records))
+		return nil
+	})
+	// A statement cut short by shutdown fails with a driver error rather than
+	// ctx's; the transaction it was in is rolled back either way
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
 	}
-	fmt.Printf("Completed processing %s\n", tld)
-	return nil
+	return recordCount, err
 }
 
 func main() {
 	force := flag.Bool("force", false, "Force reprocessing of all TLDs")
 	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	download := flag.Bool("download", false, "Download approved zones from CZDS into the zones directory before processing")
+	stdin := flag.Bool("stdin", false, "Ingest zone data from stdin instead of the zones directory (requires -tld)")
+	stdinTLD := flag.String("tld", "", "TLD of the zone data read from stdin, or a reverse zone such as 2.0.192.in-addr.arpa")
 	flag.Parse()
+	if *stdin && *stdinTLD == "" {
+		logger.Fatalf("-stdin requires -tld")
+	}
 
 	// Load configuration
 	config, err := config.LoadConfig(*configFile)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
 	}
 
 	// Connect to AlloyDB
@@ -263,27 +658,70 @@ func main() {
 	)
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
 	}
 	defer db.Close()
 
 	if err := db.Ping(); err != nil {
-		log.Fatal("Failed to connect to AlloyDB via private IP: ", err)
+		logger.Fatalf("Failed to connect to AlloyDB via private IP: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	// Stop between batches on shutdown; TLDs in progress are marked INTERRUPTED
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	leases := lease.NewManager(shards, config)
+	batch := newBatchOptions(config)
+	batch.pressure = pressure.New(shards, events.SourceCZDS, config)
+
+	if *stdin {
+		tld := strings.ToLower(strings.Trim(*stdinTLD, "."))
+		processedTLDs, err := getProcessedTLDs(db)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		logger.Infof("Processing TLD from stdin: %s", tld)
+		shard := shards.ForDomain(tld)
+		err = ingestTLD(ctx, leases, db, shard.DB, config, tld, processedTLDs, func(ctx context.Context, delta *deltaCollector, progress func(int)) (int64, error) {
+			return ingestStream(ctx, shard, os.Stdin, tld, batch, delta, progress)
+		})
+		if errors.Is(err, context.Canceled) {
+			logger.Warnf("Interrupted; stopped processing %s from stdin", tld)
+			return
+		}
+		if err != nil {
+			logger.Fatalf("Error processing %s from stdin: %v", tld, err)
+		}
+		return
 	}
-	fmt.Println("Connected to AlloyDB successfully.")
 
 	if _, err := os.Stat(config.Zones.Directory); os.IsNotExist(err) {
-		log.Fatal("Zones directory does not exist: ", config.Zones.Directory)
+		logger.Fatalf("Zones directory does not exist: %s", config.Zones.Directory)
+	}
+
+	if *download {
+		if config.CZDS.Username == "" || config.CZDS.Password == "" {
+			logger.Fatalf("-download requires czds.username and czds.password")
+		}
+		if err := downloadZones(config, config.Zones.Directory); err != nil {
+			logger.Fatalf("Failed to download zones: %v", err)
+		}
 	}
 
 	entries, err := os.ReadDir(config.Zones.Directory)
 	if err != nil {
-		log.Fatal("Failed to read zones directory: ", err)
+		logger.Fatalf("Failed to read zones directory: %v", err)
 	}
 
 	processedTLDs, err := getProcessedTLDs(db)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
 	}
 
 	var wg sync.WaitGroup
@@ -294,12 +732,22 @@ func main() {
 		wg.Add(1)
 		go func(entry os.DirEntry) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
-			if err := processZoneFile(db, entry, *force, processedTLDs, reprocessThreshold, config.Zones.BatchSize, config.Zones.Directory); err != nil {
-				log.Printf("Error processing %s: %v", entry.Name(), err)
+			if ctx.Err() != nil {
+				return
+			}
+			if err := processZoneFile(ctx, leases, db, shards, config, entry, *force, processedTLDs, reprocessThreshold, batch, config.Zones.Directory); err != nil {
+				logger.Errorf("Error processing %s: %v", entry.Name(), err)
 			}
 		}(entry)
 	}
 	wg.Wait()
+	if ctx.Err() != nil {
+		logger.Warnf("Interrupted; remaining TLDs will be processed on the next run")
+	}
 }
@@ -0,0 +1,334 @@
+package czds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/moos3/bell/config"
+)
+
+// czdsClient talks to the ICANN CZDS API on behalf of a single account.
+//
+// All requests go through the scheduler, which enforces the account's
+// concurrency and rate limits and pauses every worker when CZDS answers 429.
+type czdsClient struct {
+	httpClient *http.Client
+	cfg        *config.Config
+	sched      *downloadScheduler
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// downloadScheduler limits CZDS requests to a fixed number in flight and a
+// minimum spacing between request starts, and holds all requests back while
+// the account is throttled.
+type downloadScheduler struct {
+	slots    chan struct{}
+	interval time.Duration // Minimum time between request starts
+
+	mu          sync.Mutex
+	next        time.Time // Earliest start time for the next request
+	pausedUntil time.Time // Set from Retry-After on 429/503 responses
+}
+
+func newDownloadScheduler(maxConcurrent, requestsPerMinute int) *downloadScheduler {
+	return &downloadScheduler{
+		slots:    make(chan struct{}, maxConcurrent),
+		interval: time.Minute / time.Duration(requestsPerMinute),
+	}
+}
+
+// acquire blocks until a request may start and returns a func releasing its slot.
+func (s *downloadScheduler) acquire() func() {
+	s.slots <- struct{}{}
+	s.mu.Lock()
+	start := time.Now()
+	if s.next.After(start) {
+		start = s.next
+	}
+	if s.pausedUntil.After(start) {
+		start = s.pausedUntil
+	}
+	s.next = start.Add(s.interval)
+	s.mu.Unlock()
+	time.Sleep(time.Until(start))
+	return func() { <-s.slots }
+}
+
+// pause holds back all requests until d from now.
+func (s *downloadScheduler) pause(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until := time.Now().Add(d); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+}
+
+// throttledError is returned for 429 and 503 responses.
+type throttledError struct {
+	status     int
+	retryAfter time.Duration // Zero if the response had no usable Retry-After
+}
+
+func (e *throttledError) Error() string {
+	return fmt.Sprintf("throttled by CZDS (HTTP %d, retry after %v)", e.status, e.retryAfter)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func newCZDSClient(cfg *config.Config) *czdsClient {
+	return &czdsClient{
+		httpClient: &http.Client{Timeout: time.Duration(cfg.CZDS.TimeoutMinutes) * time.Minute},
+		cfg:        cfg,
+		sched:      newDownloadScheduler(cfg.CZDS.MaxConcurrent, cfg.CZDS.RequestsPerMinute),
+	}
+}
+
+// accessToken returns a cached bearer token, authenticating if it has expired.
+// CZDS tokens are valid for 24 hours; they are refreshed an hour early.
+func (c *czdsClient) accessToken(refresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !refresh && c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+	body, err := json.Marshal(map[string]string{"username": c.cfg.CZDS.Username, "password": c.cfg.CZDS.Password})
+	if err != nil {
+		return "", err
+	}
+	release := c.sched.acquire()
+	defer release()
+	resp, err := c.httpClient.Post(c.cfg.CZDS.AuthURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to authenticate with CZDS: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to authenticate with CZDS: HTTP %d", resp.StatusCode)
+	}
+	var auth struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return "", fmt.Errorf("failed to decode CZDS authentication response: %v", err)
+	}
+	c.token = auth.AccessToken
+	c.tokenExpiry = time.Now().Add(23 * time.Hour)
+	return c.token, nil
+}
+
+// do sends an authenticated request through the scheduler, re-authenticating
+// once on 401 and retrying throttled (429/503) and transient failures with
+// exponential backoff, honouring Retry-After. The caller must close the body,
+// which frees the request's scheduler slot.
+func (c *czdsClient) do(newRequest func() (*http.Request, error)) (*http.Response, error) {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 5 * time.Second
+	b.MaxInterval = 10 * time.Minute
+	b.MaxElapsedTime = 0
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		token, err := c.accessToken(false)
+		if err != nil {
+			return nil, err
+		}
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		release := c.sched.acquire()
+		resp, err := c.httpClient.Do(req)
+
+		var retryErr error
+		switch {
+		case err != nil:
+			release()
+			retryErr = err
+		case resp.StatusCode == http.StatusUnauthorized && !refreshed:
+			resp.Body.Close()
+			release()
+			refreshed = true
+			if _, err := c.accessToken(true); err != nil {
+				return nil, err
+			}
+			continue
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+			resp.Body.Close()
+			release()
+			retryErr = &throttledError{status: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		default:
+			// The slot stays held until the body has been read and closed
+			resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+
+		if attempt >= c.cfg.CZDS.MaxRetries {
+			return nil, fmt.Errorf("giving up on %s after %d attempts: %v", req.URL, attempt+1, retryErr)
+		}
+		wait := b.NextBackOff()
+		if throttled, ok := retryErr.(*throttledError); ok {
+			if throttled.retryAfter > wait {
+				wait = throttled.retryAfter
+			}
+			// Throttling applies to the whole account, not just this download
+			c.sched.pause(wait)
+		}
+		logger.Warnf("CZDS request to %s failed (%v); retrying in %v", req.URL, retryErr, wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// releasingBody releases a scheduler slot when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// zoneLinks returns the download URLs of every zone the account is approved for.
+func (c *czdsClient) zoneLinks() ([]string, error) {
+	url := strings.TrimSuffix(c.cfg.CZDS.APIURL, "/") + "/czds/downloads/links"
+	resp, err := c.do(func() (*http.Request, error) { return http.NewRequest(http.MethodGet, url, nil) })
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list CZDS zones: HTTP %d", resp.StatusCode)
+	}
+	var links []string
+	if err := json.NewDecoder(resp.Body).Decode(&links); err != nil {
+		return nil, fmt.Errorf("failed to decode CZDS zone links: %v", err)
+	}
+	return links, nil
+}
+
+// downloadZone downloads a zone to <dir>/<tld>.txt.gz, the name processZoneFile
+// expects. Data is written to a .part file first; an existing .part file is
+// resumed with a Range request.
+func (c *czdsClient) downloadZone(link, dir string) (string, error) {
+	tld := strings.TrimSuffix(path.Base(link), ".zone")
+	dest := filepath.Join(dir, tld+".txt.gz")
+	part := dest + ".part"
+
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+	resp, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, link, nil)
+		if err != nil {
+			return nil, err
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return tld, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Server ignored the range or there was nothing to resume
+		flags |= os.O_TRUNC
+		offset = 0
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The .part file already holds the whole zone
+		return tld, os.Rename(part, dest)
+	default:
+		return tld, fmt.Errorf("failed to download zone %s: HTTP %d", tld, resp.StatusCode)
+	}
+
+	file, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return tld, fmt.Errorf("failed to open %s: %v", part, err)
+	}
+	written, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return tld, fmt.Errorf("download of zone %s interrupted after %d bytes (will resume): %v", tld, offset+written, err)
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return tld, fmt.Errorf("failed to move %s into place: %v", part, err)
+	}
+	logger.Infof("Downloaded zone %s (%d bytes)", tld, offset+written)
+	return tld, nil
+}
+
+// downloadZones downloads all approved zones (or only czds.tlds if set) into
+// dir, skipping zones whose file is newer than the reprocess threshold.
+// Per-zone failures are logged and do not stop other downloads.
+func downloadZones(cfg *config.Config, dir string) error {
+	c := newCZDSClient(cfg)
+	links, err := c.zoneLinks()
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool)
+	for _, tld := range cfg.CZDS.TLDs {
+		wanted[strings.ToLower(tld)] = true
+	}
+	reprocessThreshold := time.Duration(cfg.Zones.ReprocessThresholdHours) * time.Hour
+
+	var wg sync.WaitGroup
+	for _, link := range links {
+		tld := strings.TrimSuffix(path.Base(link), ".zone")
+		if len(wanted) > 0 && !wanted[tld] {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, tld+".txt.gz")); err == nil && time.Since(info.ModTime()) < reprocessThreshold {
+			logger.Infof("Skipping download of %s: downloaded at %v", tld, info.ModTime())
+			continue
+		}
+		wg.Add(1)
+		go func(link string) {
+			defer wg.Done()
+			if tld, err := c.downloadZone(link, dir); err != nil {
+				logger.Errorf("Error downloading zone %s: %v", tld, err)
+			}
+		}(link)
+	}
+	wg.Wait()
+	return nil
+}
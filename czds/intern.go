@@ -0,0 +1,30 @@
+package czds
+
+// maxInternedStrings bounds the strings a zone's stringTable keeps. The
+// strings worth sharing, such as a registrar's nameservers, recur from the
+// start of a zone, so a full table stops adding rather than evicting.
+const maxInternedStrings = 1 << 16
+
+// stringTable interns the strings that recur across a zone's records, so
+// the batches parseZoneFile hands over share one copy of each nameserver
+// host, TLD and public suffix instead of holding one per record. Strings
+// unique to a record, such as record data, which includes its owner name,
+// are not worth interning.
+type stringTable struct {
+	strings map[string]string
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{strings: make(map[string]string)}
+}
+
+// intern returns the table's copy of s, adding s if the table has room.
+func (t *stringTable) intern(s string) string {
+	if shared, ok := t.strings[s]; ok {
+		return shared
+	}
+	if len(t.strings) < maxInternedStrings {
+		t.strings[s] = s
+	}
+	return s
+}
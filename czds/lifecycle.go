@@ -0,0 +1,76 @@
+package czds
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/moos3/bell/recordset"
+)
+
+// storeLifecycleEvents records the nameserver changes of an ingest as
+// NAMESERVERS_CHANGED events in domain_lifecycle_events (see
+// GetDomainLifecycle). Changes to a new DNS provider are flagged for the
+// server's registrar transfer check, since transfers usually move the
+// delegation too, and each change is classified by significance (see
+// recordset.ClassifyNameservers). It returns the number of events stored in
+// tx.
+func (c *deltaCollector) storeLifecycleEvents(ctx context.Context, tx *sql.Tx) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.changed) == 0 {
+		return 0, nil
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO domain_lifecycle_events (domain_name, event_type, old_nameservers, nameservers, provider_changed, significance)
+		VALUES ($1, 'NAMESERVERS_CHANGED', $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for _, change := range c.changed {
+		old, ns := change.OldNameservers, change.Nameservers
+		if old == nil {
+			old = []string{}
+		}
+		if _, err := stmt.ExecContext(ctx, change.Domain, pq.StringArray(old), pq.StringArray(ns), providerChanged(old, ns),
+			recordset.ClassifyNameservers(old, ns)); err != nil {
+			return 0, fmt.Errorf("failed to store lifecycle event for %s: %v", change.Domain, err)
+		}
+	}
+	return len(c.changed), nil
+}
+
+// providerChanged reports whether a delegation moved between DNS providers:
+// both nameserver sets are non-empty and share no registrable domain (so
+// ns1.a.net to ns2.a.net is not a move, but ns1.a.net to ns1.b.org is).
+func providerChanged(old, current []string) bool {
+	if len(old) == 0 || len(current) == 0 {
+		return false
+	}
+	providers := make(map[string]bool)
+	for _, ns := range old {
+		providers[nameserverProvider(ns)] = true
+	}
+	for _, ns := range current {
+		if providers[nameserverProvider(ns)] {
+			return false
+		}
+	}
+	return true
+}
+
+// nameserverProvider returns the registrable domain of a nameserver host,
+// or the host itself if it has none.
+func nameserverProvider(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if etld1, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return etld1
+	}
+	return host
+}
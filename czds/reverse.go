@@ -0,0 +1,84 @@
+package czds
+
+import (
+	"context"
+	"database/sql"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/moos3/bell/recordset"
+)
+
+// ptrRecord is a PTR record of a reverse zone, keyed by address.
+type ptrRecord struct {
+	ip      netip.Addr
+	ptrName string
+	ttl     int
+}
+
+// splitPTRRecords separates the PTR records of full reverse names from the
+// rest of a reverse zone batch. Everything else, such as delegations to
+// smaller reverse zones and RFC 2317 classless names, is stored as domains
+// like any other zone.
+func splitPTRRecords(records []zoneRecord) (ptrs []ptrRecord, rest []zoneRecord) {
+	for _, r := range records {
+		if r.recordType != "PTR" {
+			rest = append(rest, r)
+			continue
+		}
+		ip, ok := recordset.ReverseAddr(r.domain)
+		if !ok {
+			rest = append(rest, r)
+			continue
+		}
+		rr, err := dns.NewRR(r.data)
+		if err != nil || rr == nil {
+			rest = append(rest, r)
+			continue
+		}
+		ptr, ok := rr.(*dns.PTR)
+		if !ok {
+			rest = append(rest, r)
+			continue
+		}
+		ptrs = append(ptrs, ptrRecord{
+			ip:      ip,
+			ptrName: strings.TrimSuffix(dns.CanonicalName(ptr.Ptr), "."),
+			ttl:     r.ttl,
+		})
+	}
+	return ptrs, rest
+}
+
+// storePTRRecords upserts the PTR records of a reverse zone into ptr_records.
+func storePTRRecords(ctx context.Context, db *sql.DB, ptrs []ptrRecord, zone string) error {
+	if len(ptrs) == 0 {
+		return nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO ptr_records (ip, ptr_name, zone, ttl, source, last_updated)
+		VALUES ($1, $2, $3, $4, 'CZDS', $5)
+		ON CONFLICT (ip, ptr_name, source) DO UPDATE
+		SET zone = EXCLUDED.zone, ttl = EXCLUDED.ttl, last_updated = EXCLUDED.last_updated
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	for _, p := range ptrs {
+		if _, err := stmt.ExecContext(ctx, p.ip.String(), p.ptrName, zone, p.ttl, now); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
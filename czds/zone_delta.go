@@ -0,0 +1,295 @@
+package czds
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/moos3/bell/config"
+)
+
+// ZoneDelta is the document published to registry partners after each TLD
+// ingest. It is serialized as JSON with the following schema:
+//
+//	{
+//	  "schema_version": 1,
+//	  "tld": "example",
+//	  "ingest_started": "2025-01-01T00:00:00Z",   // RFC 3339, UTC
+//	  "ingest_completed": "2025-01-01T00:10:00Z", // RFC 3339, UTC
+//	  "previous_ingest": "2024-12-31T00:00:00Z",  // RFC 3339, UTC
+//	  "added":   [{"domain": "new.example", "nameservers": ["ns1.host.net"]}],
+//	  "removed": [{"domain": "gone.example", "nameservers": ["ns1.host.net"]}],
+//	  "changed": [{"domain": "moved.example", "old_nameservers": ["ns1.a.net"], "nameservers": ["ns1.b.net"]}],
+//	  "record_sets": [{"domain": "moved.example", "record_type": "NS", "version": 1042}]
+//	}
+//
+// removed lists the domains dropped from the zone since its previous
+// ingest. record_sets lists every record set the ingest added, changed or
+// dropped with its new version (see GetRecords), so downstream caches can
+// invalidate exactly those sets. Domains in each list are sorted by name.
+// Webhook deliveries carry an X-Bell-Signature header ("sha256=<hex HMAC of
+// the body>") when delta.webhook_secret is configured.
+type ZoneDelta struct {
+	SchemaVersion   int                `json:"schema_version"`
+	TLD             string             `json:"tld"`
+	IngestStarted   string             `json:"ingest_started"`
+	IngestCompleted string             `json:"ingest_completed"`
+	PreviousIngest  string             `json:"previous_ingest"`
+	Added           []DomainChange     `json:"added"`
+	Removed         []DomainChange     `json:"removed"`
+	Changed         []DomainChange     `json:"changed"`
+	RecordSets      []RecordSetVersion `json:"record_sets"`
+}
+
+// DomainChange describes a single domain in a ZoneDelta.
+type DomainChange struct {
+	Domain         string   `json:"domain"`
+	OldNameservers []string `json:"old_nameservers,omitempty"` // Only set for changed domains
+	Nameservers    []string `json:"nameservers"`
+}
+
+// RecordSetVersion is a record set added or changed by an ingest, in a
+// ZoneDelta.
+type RecordSetVersion struct {
+	Domain     string `json:"domain"`
+	RecordType string `json:"record_type"`
+	Version    int64  `json:"version"`
+}
+
+// zoneDeltaSchemaVersion is bumped whenever the ZoneDelta document changes incompatibly.
+const zoneDeltaSchemaVersion = 1
+
+// deltaCollector accumulates domain changes across batches of a single TLD ingest.
+type deltaCollector struct {
+	mu         sync.Mutex
+	added      map[string][]string
+	changed    map[string]DomainChange
+	removed    []DomainChange // Set by recordRemovals
+	recordSets []RecordSetVersion
+}
+
+func newDeltaCollector() *deltaCollector {
+	return &deltaCollector{
+		added:   make(map[string][]string),
+		changed: make(map[string]DomainChange),
+	}
+}
+
+// recordSetChanged records a record set stored with a new version.
+func (c *deltaCollector) recordSetChanged(domain, recordType string, version int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordSets = append(c.recordSets, RecordSetVersion{Domain: domain, RecordType: recordType, Version: version})
+}
+
+// recordUpsert records the outcome of a domain upsert. prev is the nameserver
+// set before the upsert and is ignored for inserted domains.
+func (c *deltaCollector) recordUpsert(domain string, inserted bool, prev, current []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inserted {
+		c.added[domain] = current
+		return
+	}
+	if _, ok := c.added[domain]; ok {
+		// Inserted by an earlier batch of this ingest
+		c.added[domain] = current
+		return
+	}
+	if existing, ok := c.changed[domain]; ok {
+		prev = existing.OldNameservers
+	}
+	if sameNameservers(prev, current) {
+		delete(c.changed, domain)
+		return
+	}
+	c.changed[domain] = DomainChange{Domain: domain, OldNameservers: prev, Nameservers: current}
+}
+
+func sameNameservers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := append([]string(nil), a...)
+	bs := append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// build assembles the final delta, with the removals of recordRemovals.
+func (c *deltaCollector) build(tld string, started, previous time.Time) *ZoneDelta {
+	delta := &ZoneDelta{
+		SchemaVersion:   zoneDeltaSchemaVersion,
+		TLD:             tld,
+		IngestStarted:   started.UTC().Format(time.RFC3339),
+		IngestCompleted: time.Now().UTC().Format(time.RFC3339),
+		PreviousIngest:  previous.UTC().Format(time.RFC3339),
+		Added:           []DomainChange{},
+		Removed:         append([]DomainChange{}, c.removed...),
+		Changed:         []DomainChange{},
+		RecordSets:      append([]RecordSetVersion{}, c.recordSets...),
+	}
+	for domain, ns := range c.added {
+		delta.Added = append(delta.Added, DomainChange{Domain: domain, Nameservers: ns})
+	}
+	for _, change := range c.changed {
+		delta.Changed = append(delta.Changed, change)
+	}
+
+	for _, list := range [][]DomainChange{delta.Added, delta.Removed, delta.Changed} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Domain < list[j].Domain })
+	}
+	sort.Slice(delta.RecordSets, func(i, j int) bool {
+		a, b := delta.RecordSets[i], delta.RecordSets[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		return a.RecordType < b.RecordType
+	})
+	return delta
+}
+
+// recordRemovals tombstones the zone's CZDS record sets that the ingest
+// started at started did not store, and records as removed the domains that
+// lost their last live CZDS set since previous. It must only run after the
+// ingest has stored the whole zone.
+func (c *deltaCollector) recordRemovals(ctx context.Context, db *sql.DB, tld string, started, previous time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE record_set_checksums c
+		SET removed_at = $3, version = nextval('record_set_version_seq')
+		FROM domains d
+		WHERE c.domain_id = d.id AND d.public_suffix = $1 AND c.source = 'CZDS'
+			AND c.removed_at IS NULL AND c.computed_at < $2
+		RETURNING d.domain_name, c.record_type, c.version
+	`, tld, started.UTC(), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to tombstone dropped record sets for %s: %v", tld, err)
+	}
+	var tombstoned []RecordSetVersion
+	for rows.Next() {
+		var set RecordSetVersion
+		if err := rows.Scan(&set.Domain, &set.RecordType, &set.Version); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan dropped record set: %v", err)
+		}
+		tombstoned = append(tombstoned, set)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows, err = tx.QueryContext(ctx, `
+		SELECT d.domain_name, d.nameservers
+		FROM domains d
+		WHERE d.public_suffix = $1
+			AND EXISTS (
+				SELECT 1 FROM record_set_checksums c
+				WHERE c.domain_id = d.id AND c.source = 'CZDS' AND c.removed_at > $2
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM record_set_checksums c
+				WHERE c.domain_id = d.id AND c.source = 'CZDS' AND c.removed_at IS NULL
+			)
+	`, tld, previous.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to query removed domains for %s: %v", tld, err)
+	}
+	defer rows.Close()
+	var removed []DomainChange
+	for rows.Next() {
+		var change DomainChange
+		var ns pq.StringArray
+		if err := rows.Scan(&change.Domain, &ns); err != nil {
+			return fmt.Errorf("failed to scan removed domain: %v", err)
+		}
+		change.Nameservers = ns
+		if change.Nameservers == nil {
+			change.Nameservers = []string{}
+		}
+		removed = append(removed, change)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.removed = removed
+	c.recordSets = append(c.recordSets, tombstoned...)
+	c.mu.Unlock()
+	return nil
+}
+
+// publishDelta delivers a delta to the configured webhook and/or output directory.
+func publishDelta(cfg *config.Config, delta *ZoneDelta) error {
+	body, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("failed to encode delta for %s: %v", delta.TLD, err)
+	}
+
+	if cfg.Delta.OutputDir != "" {
+		dir := filepath.Join(cfg.Delta.OutputDir, delta.TLD)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create delta directory %s: %v", dir, err)
+		}
+		name := fmt.Sprintf("%s.json", time.Now().UTC().Format("20060102T150405Z"))
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0o644); err != nil {
+			return fmt.Errorf("failed to write delta for %s: %v", delta.TLD, err)
+		}
+	}
+
+	if cfg.Delta.WebhookURL != "" {
+		timeout := time.Duration(cfg.Delta.TimeoutSeconds) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Delta.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create webhook request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if cfg.Delta.WebhookSecret != "" {
+			mac := hmac.New(sha256.New, []byte(cfg.Delta.WebhookSecret))
+			mac.Write(body)
+			req.Header.Set("X-Bell-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to deliver delta for %s: %v", delta.TLD, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook rejected delta for %s: %s", delta.TLD, resp.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package czds
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/moos3/bell/fixtures"
+)
+
+// parseZone parses a generated zone in batches of batchSize.
+func parseZone(t *testing.T, zone *fixtures.Zone, batchSize int) (records []zoneRecord, nameservers map[string][]string, batches int) {
+	var zoneFile bytes.Buffer
+	if err := zone.WriteZoneFile(&zoneFile); err != nil {
+		t.Fatal(err)
+	}
+	nameservers = make(map[string][]string)
+	err := parseZoneFile(context.Background(), &zoneFile, zone.TLD, batchSize, func(batch []zoneRecord, ns map[string][]string) error {
+		records = append(records, batch...)
+		for domain, hosts := range ns {
+			nameservers[domain] = hosts
+		}
+		batches++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records, nameservers, batches
+}
+
+func TestParseGeneratedZone(t *testing.T) {
+	zone := fixtures.Generate(fixtures.Options{Seed: 7, DomainsPerTLD: 300}).Zones[0]
+	records, nameservers, batches := parseZone(t, zone, 100)
+
+	want := 0
+	for _, d := range zone.Domains {
+		want += len(d.Records["CZDS"])
+		if len(nameservers[d.Name]) != len(d.Nameservers) {
+			t.Errorf("%s: parsed nameservers %v, want %v", d.Name, nameservers[d.Name], d.Nameservers)
+		}
+	}
+	// The zone's own SOA and NS records are skipped
+	if len(records) != want {
+		t.Errorf("parsed %d records, want %d", len(records), want)
+	}
+	if batches < want/100 {
+		t.Errorf("parsed %d records in %d batches of at most 100", len(records), batches)
+	}
+	for _, r := range records {
+		if r.tld != "example" || r.suffix != "example" {
+			t.Fatalf("record of %s has TLD %q and suffix %q", r.domain, r.tld, r.suffix)
+		}
+	}
+}
+
+func TestStoreGeneratedZone(t *testing.T) {
+	db := fixtures.OpenDatabase(t)
+	zone := fixtures.Generate(fixtures.Options{Seed: 7, DomainsPerTLD: 300}).Zones[0]
+	var zoneFile bytes.Buffer
+	if err := zone.WriteZoneFile(&zoneFile); err != nil {
+		t.Fatal(err)
+	}
+	delta := newDeltaCollector()
+	err := parseZoneFile(context.Background(), &zoneFile, zone.TLD, 100, func(batch []zoneRecord, ns map[string][]string) error {
+		return storeRecords(context.Background(), db, batch, ns, delta, true)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 0
+	for _, d := range zone.Domains {
+		want += len(d.Records["CZDS"])
+	}
+	var domains, records int
+	if err := db.QueryRow("SELECT COUNT(*) FROM domains WHERE tld = 'example'").Scan(&domains); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM dns_records WHERE source = 'CZDS'").Scan(&records); err != nil {
+		t.Fatal(err)
+	}
+	if domains != len(zone.Domains) || records != want {
+		t.Errorf("stored %d domains and %d records, want %d and %d", domains, records, len(zone.Domains), want)
+	}
+}
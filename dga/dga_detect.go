@@ -0,0 +1,327 @@
+package dga
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	_ "github.com/lib/pq"
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/storage"
+	"golang.org/x/net/publicsuffix"
+)
+
+// logger logs the dga tool's messages (see the logging package).
+var logger = logging.New("dga")
+
+// builtinBigrams holds approximate English bigram frequencies (percent of all
+// bigrams), used when no ngram_model file is configured.
+var builtinBigrams = map[string]float64{
+	"th": 3.56, "he": 3.07, "in": 2.43, "er": 2.05, "an": 1.99, "re": 1.85, "on": 1.76, "at": 1.49,
+	"en": 1.45, "nd": 1.35, "ti": 1.34, "es": 1.34, "or": 1.28, "te": 1.20, "of": 1.17, "ed": 1.17,
+	"is": 1.13, "it": 1.12, "al": 1.09, "ar": 1.07, "st": 1.05, "to": 1.04, "nt": 1.04, "ng": 0.95,
+	"se": 0.93, "ha": 0.93, "as": 0.87, "ou": 0.87, "io": 0.83, "le": 0.83, "ve": 0.83, "co": 0.79,
+	"me": 0.79, "de": 0.76, "hi": 0.76, "ri": 0.73, "ro": 0.73, "ic": 0.70, "ne": 0.69, "ea": 0.69,
+	"ra": 0.69, "ce": 0.65, "li": 0.62, "ch": 0.60, "ll": 0.58, "be": 0.58, "ma": 0.57, "si": 0.55,
+	"om": 0.55, "ur": 0.54, "ca": 0.54, "el": 0.53, "ta": 0.53, "la": 0.52, "ns": 0.51, "di": 0.48,
+	"fo": 0.48, "ho": 0.47, "pe": 0.46, "ec": 0.46, "pr": 0.45, "no": 0.45, "ct": 0.45, "us": 0.44,
+	"ac": 0.43, "ot": 0.43, "il": 0.43, "tr": 0.42, "ly": 0.42, "nc": 0.42, "et": 0.41, "ut": 0.41,
+	"ss": 0.41, "so": 0.40, "rs": 0.40, "un": 0.39, "lo": 0.39, "wa": 0.38, "ge": 0.38, "ie": 0.38,
+	"wh": 0.38, "ee": 0.38, "wi": 0.37, "em": 0.37, "ad": 0.37, "ol": 0.37, "rt": 0.36, "po": 0.36,
+	"we": 0.36, "na": 0.35, "ul": 0.35, "ni": 0.35, "ts": 0.35, "mo": 0.35, "ow": 0.35, "pa": 0.34,
+	"im": 0.34, "mi": 0.34, "ai": 0.33, "sh": 0.33, "ir": 0.32, "su": 0.32, "id": 0.32, "os": 0.32,
+	"iv": 0.31, "ia": 0.31, "am": 0.31, "fi": 0.30, "ci": 0.30, "vi": 0.30, "pl": 0.29, "ig": 0.29,
+	"tu": 0.29, "ev": 0.29, "ld": 0.28, "ry": 0.28, "mp": 0.27, "fe": 0.27, "bl": 0.27, "ab": 0.26,
+	"gh": 0.26, "ty": 0.26, "op": 0.26, "wo": 0.26, "sa": 0.26, "ay": 0.25, "ex": 0.25, "ke": 0.25,
+	"fr": 0.25, "oo": 0.25, "av": 0.24, "ag": 0.24, "if": 0.24, "ap": 0.23, "gr": 0.23, "od": 0.23,
+	"bo": 0.23, "sp": 0.23, "rd": 0.23, "do": 0.23, "uc": 0.23, "bu": 0.22, "ei": 0.22, "ov": 0.22,
+	"by": 0.21, "rm": 0.21, "ep": 0.21, "tt": 0.21, "oc": 0.21, "fa": 0.21, "ef": 0.21, "cu": 0.20,
+}
+
+// Scorer computes DGA likelihood scores for domain labels.
+type Scorer struct {
+	bigrams   map[string]float64 // Bigram frequencies (percent)
+	threshold float64            // Score at or above which a label is flagged
+	model     string             // Model name stored alongside scores
+}
+
+// Score is the result of scoring a single domain.
+type Score struct {
+	Entropy    float64 // Shannon entropy of the label in bits per character
+	NGramScore float64 // Fraction of label bigrams present in the model (0-1)
+	Score      float64 // Combined DGA score (0-1), higher is more suspicious
+	LikelyDGA  bool    // Score is at or above the configured threshold
+}
+
+// NewScorer creates a Scorer using the bigram model at modelPath, or the
+// built-in English model if modelPath is empty.
+func NewScorer(modelPath string, threshold float64) (*Scorer, error) {
+	if modelPath == "" {
+		return &Scorer{bigrams: builtinBigrams, threshold: threshold, model: "builtin-en"}, nil
+	}
+	bigrams, err := loadBigramModel(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Scorer{bigrams: bigrams, threshold: threshold, model: modelPath}, nil
+}
+
+// loadBigramModel reads a bigram model file with one "bigram frequency" pair
+// per line. Blank lines and lines starting with # are ignored.
+func loadBigramModel(path string) (map[string]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ngram model %s: %v", path, err)
+	}
+	defer file.Close()
+
+	bigrams := make(map[string]float64)
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != 2 {
+			return nil, fmt.Errorf("invalid ngram model line %d in %s: %q", lineNo, path, line)
+		}
+		freq, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frequency on line %d in %s: %v", lineNo, path, err)
+		}
+		bigrams[strings.ToLower(fields[0])] = freq
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ngram model %s: %v", path, err)
+	}
+	if len(bigrams) == 0 {
+		return nil, fmt.Errorf("ngram model %s contains no bigrams", path)
+	}
+	return bigrams, nil
+}
+
+// registrableLabel returns the label directly left of the public suffix
+// (e.g. "example" for "www.example.co.uk").
+func registrableLabel(domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	etld1, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		etld1 = domain
+	}
+	if i := strings.IndexByte(etld1, '.'); i >= 0 {
+		return etld1[:i]
+	}
+	return etld1
+}
+
+// entropy returns the Shannon entropy of s in bits per character, counting
+// runes rather than bytes so that IDN labels are not skewed.
+func entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+	var h float64
+	n := float64(utf8.RuneCountInString(s))
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// ScoreDomain scores the registrable label of domain.
+//
+// The combined score weighs normalized entropy (50%), the share of bigrams
+// absent from the model (30%), and the share of digits in the label (20%).
+func (s *Scorer) ScoreDomain(domain string) Score {
+	label := registrableLabel(domain)
+	// Hyphens separate words in legitimate names and would skew the bigram score
+	letters := strings.ReplaceAll(label, "-", "")
+
+	var sc Score
+	sc.Entropy = entropy(letters)
+
+	bigramCount, known, digits := 0, 0, 0
+	for i := 0; i < len(letters); i++ {
+		if letters[i] >= '0' && letters[i] <= '9' {
+			digits++
+		}
+		if i+1 < len(letters) {
+			bigramCount++
+			if _, ok := s.bigrams[letters[i:i+2]]; ok {
+				known++
+			}
+		}
+	}
+	if bigramCount > 0 {
+		sc.NGramScore = float64(known) / float64(bigramCount)
+	} else {
+		sc.NGramScore = 1
+	}
+	digitRatio := 0.0
+	if len(letters) > 0 {
+		digitRatio = float64(digits) / float64(len(letters))
+	}
+
+	sc.Score = 0.5*math.Min(sc.Entropy/4.0, 1) + 0.3*(1-sc.NGramScore) + 0.2*digitRatio
+	// Very short labels don't carry enough signal to be flagged
+	sc.LikelyDGA = len(letters) >= 6 && sc.Score >= s.threshold
+	return sc
+}
+
+type unscoredDomain struct {
+	ID     int
+	Domain string
+}
+
+func getUnscoredDomains(db *sql.DB, batchSize int) ([]unscoredDomain, error) {
+	rows, err := db.Query(`
+		SELECT d.id, d.domain_name
+		FROM domains d
+		LEFT JOIN domain_scores s ON s.domain_id = d.id
+		WHERE s.domain_id IS NULL
+		ORDER BY d.id
+		LIMIT $1
+	`, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []unscoredDomain
+	for rows.Next() {
+		var d unscoredDomain
+		if err := rows.Scan(&d.ID, &d.Domain); err != nil {
+			return nil, fmt.Errorf("failed to scan domain: %v", err)
+		}
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+func storeScores(db *sql.DB, scorer *Scorer, domains []unscoredDomain) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO domain_scores (domain_id, entropy, ngram_score, dga_score, likely_dga, model, scored_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (domain_id) DO UPDATE
+		SET entropy = EXCLUDED.entropy, ngram_score = EXCLUDED.ngram_score, dga_score = EXCLUDED.dga_score,
+		    likely_dga = EXCLUDED.likely_dga, model = EXCLUDED.model, scored_at = EXCLUDED.scored_at
+	`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+
+	flagged := 0
+	for _, d := range domains {
+		sc := scorer.ScoreDomain(d.Domain)
+		if sc.LikelyDGA {
+			flagged++
+		}
+		if _, err := stmt.Exec(d.ID, sc.Entropy, sc.NGramScore, sc.Score, sc.LikelyDGA, scorer.model, time.Now().UTC()); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to store score for %s: %v", d.Domain, err)
+		}
+	}
+	return flagged, tx.Commit()
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	rescore := flag.Bool("rescore", false, "Clear existing scores and rescore all domains")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	scorer, err := NewScorer(config.DGA.NGramModel, config.DGA.Threshold)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Connect to AlloyDB
+	connStr := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode,
+	)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	if *rescore {
+		if _, err := shards.FanOut(context.Background(), func(ctx context.Context, shard *storage.Shard) error {
+			if _, err := shard.DB.ExecContext(ctx, "DELETE FROM domain_scores"); err != nil {
+				return fmt.Errorf("shard %s: %v", shard.Name, err)
+			}
+			return nil
+		}); err != nil {
+			logger.Fatalf("Failed to clear domain scores: %v", err)
+		}
+		logger.Infof("Cleared existing domain scores")
+	}
+
+	// Score new domains on each shard in batches until none are left
+	var total, totalFlagged atomic.Int64
+	if _, err := shards.FanOut(context.Background(), func(ctx context.Context, shard *storage.Shard) error {
+		for {
+			domains, err := getUnscoredDomains(shard.DB, config.DGA.BatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to fetch unscored domains on shard %s: %v", shard.Name, err)
+			}
+			if len(domains) == 0 {
+				return nil
+			}
+			flagged, err := storeScores(shard.DB, scorer, domains)
+			if err != nil {
+				return fmt.Errorf("failed to store scores on shard %s: %v", shard.Name, err)
+			}
+			total.Add(int64(len(domains)))
+			totalFlagged.Add(int64(flagged))
+			logger.Infof("Scored %d domains on shard %s (%d flagged as likely DGA)", len(domains), shard.Name, flagged)
+		}
+	}); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	logger.Infof("Completed scoring %d domains, %d flagged as likely DGA (threshold %.2f, model %s)", total.Load(), totalFlagged.Load(), config.DGA.Threshold, scorer.model)
+}
@@ -0,0 +1,96 @@
+// Package events publishes ingestion and worker progress over Postgres
+// NOTIFY so the API server can stream it to operators (TailEvents) and
+// record-set changes to subscribers (SubscribeRecordChanges) without anyone
+// reading the workers' stdout or running a message broker. API servers also
+// tell each other over NOTIFY which cached API keys have changed.
+//
+// Events are fire-and-forget: nothing is stored, and events published while
+// no server is listening are lost.
+package events
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/moos3/bell/logging"
+)
+
+// Channel is the Postgres NOTIFY channel events are published on.
+const Channel = "bell_events"
+
+// KeyChannel is the Postgres NOTIFY channel API key changes are published
+// on. Payloads are the bare API key; they are kept off Channel so that
+// TailEvents never shows keys.
+const KeyChannel = "bell_key_changes"
+
+// Event sources.
+const (
+	SourceCZDS   = "czds"   // Zone file ingester
+	SourceQuery  = "query"  // DNS query worker
+	SourceIngest = "ingest" // Write-behind ingest service
+	SourcePDNS   = "pdns"   // Passive DNS importer
+)
+
+// Event kinds.
+const (
+	ZoneStarted    = "zone_started"
+	ZoneCompleted  = "zone_completed"
+	ZoneFailed     = "zone_failed"
+	BatchCommitted = "batch_committed"
+	BatchFailed    = "batch_failed"
+	Error          = "error"
+	LeaseExpired   = "lease_expired" // A worker stopped heartbeating an item; it was re-queued
+	Alert          = "alert"         // An item failed or expired leases.alert_after_failures times in a row
+	Throttled      = "throttled"     // The ingest writer started holding commits because the database is under pressure; the message says why
+	Unthrottled    = "unthrottled"   // The ingest writer resumed commits; count is the seconds it held them
+
+	RecordSetChanged = "record_set_changed" // A stored record set is new or differs from before, if only in TTL; carries its version, if the source keeps checksums, and the change's significance. Published on the shard holding the set
+)
+
+// maxMessage keeps payloads well under the 8000 byte NOTIFY limit.
+const maxMessage = 4000
+
+// Event is one progress event.
+type Event struct {
+	Time    string `json:"time"` // RFC 3339, set by Publish
+	Source  string `json:"source"`
+	Kind    string `json:"kind"`
+	TLD     string `json:"tld,omitempty"`
+	Domain  string `json:"domain,omitempty"`
+	Count   int64  `json:"count,omitempty"` // Records or domains, depending on kind
+	Message string `json:"message,omitempty"`
+
+	RecordType   string `json:"record_type,omitempty"`  // Set on record_set_changed
+	Version      int64  `json:"version,omitempty"`      // Record-set version on record_set_changed
+	Significance string `json:"significance,omitempty"` // Of the change on record_set_changed (see recordset.Classify)
+}
+
+// Publish sends e on Channel. Failures are logged rather than returned so
+// that progress reporting never interrupts the work it reports on.
+func Publish(db *sql.DB, e Event) {
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	if len(e.Message) > maxMessage {
+		e.Message = e.Message[:maxMessage]
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", e.Kind, err)
+		return
+	}
+	if _, err := db.Exec("SELECT pg_notify($1, $2)", Channel, string(payload)); err != nil {
+		log.Printf("Failed to publish %s event: %v", e.Kind, err)
+	}
+}
+
+// KeyChanged tells every API server listening on KeyChannel to drop what it
+// caches about apiKey (role, status, sandbox flag, organization, quotas and
+// preferences) because it was created, changed or revoked. Like Publish,
+// failures are logged, naming the key by its logging.KeyID: servers still
+// pick the change up when their caches expire.
+func KeyChanged(db *sql.DB, apiKey string) {
+	if _, err := db.Exec("SELECT pg_notify($1, $2)", KeyChannel, apiKey); err != nil {
+		log.Printf("Failed to publish change of API key %s: %v", logging.KeyID(apiKey), err)
+	}
+}
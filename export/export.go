@@ -0,0 +1,278 @@
+// Package export runs the exports API keys queue with StartExport, writing
+// the matching domains or DNS records to exports.output_dir for the server
+// to hand out as signed download URLs. Rows leave out the fields the
+// requesting key's role may not see (redaction.roles).
+//
+// The server serves downloads from the same directory, so it must be
+// storage every server replica and the export worker mount, such as an
+// object storage bucket mount (see exports.shared_storage).
+//
+// Each run claims queued jobs one at a time, streams the rows from every
+// shard into a temporary file and renames it into place once complete, so a
+// download never sees a partial export. While a job runs the worker records
+// its progress and a heartbeat; jobs whose heartbeat stops, because their
+// worker died, are claimed again. Each run also deletes exports older than
+// exports.retention_hours.
+package export
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/signing"
+	"github.com/moos3/bell/storage"
+)
+
+// logger logs the export worker's messages (see the logging package).
+var logger = logging.New("export")
+
+const (
+	heartbeatInterval = 30 * time.Second // How often a running job's progress is stored
+	staleAfter        = 5 * time.Minute  // Running jobs without a heartbeat this long are claimed again
+)
+
+// job is a claimed row of export_jobs.
+type job struct {
+	id              int
+	kind            string // DOMAINS or RECORDS
+	format          string // CSV or JSONL
+	tld             string
+	pattern         string
+	recordType      string
+	source          string
+	dataPattern     string
+	firstSeenAfter  sql.NullTime
+	firstSeenBefore sql.NullTime
+	scrub           bool            // Pseudonymize and redact fields as configured in exports.scrub
+	orgID           int             // Organization of the requesting key, whose restricted domains are exported; 0 if none
+	role            string          // Role of the requesting key
+	redact          map[string]bool // Fields the role may not see (redaction.roles), cleared in every row
+	startedAt       time.Time       // Identifies this claim; a job claimed again gets a new one
+}
+
+// runQueued runs queued jobs until none are left, then deletes expired
+// exports.
+func runQueued(ctx context.Context, db *sql.DB, shards *storage.Router, cfg *config.Config, scrub *scrubber, signer *signing.Signer) error {
+	if cfg.Exports.OutputDir == "" {
+		return fmt.Errorf("exports.output_dir is not set")
+	}
+	ran := 0
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		j, err := claim(ctx, db)
+		if err != nil {
+			return err
+		}
+		if j == nil {
+			break
+		}
+		j.redact = make(map[string]bool)
+		for _, field := range cfg.Redaction.Roles[j.role] {
+			j.redact[field] = true
+		}
+		result, runErr := run(ctx, db, shards, cfg, scrub, signer, j)
+		if err := finish(db, j, result, runErr); err != nil {
+			return err
+		}
+		if runErr != nil {
+			logger.Errorf("Error running %s export %d: %v", j.kind, j.id, runErr)
+			continue
+		}
+		logger.Infof("Exported %d rows (%d bytes) for export %d", result.rows, result.bytes, j.id)
+		ran++
+	}
+	if ran > 0 {
+		logger.Infof("Ran %d exports", ran)
+	}
+	return expire(ctx, db, cfg)
+}
+
+// claim marks the oldest queued job, or a running job whose worker stopped
+// sending heartbeats, as running here, recording the requesting key's
+// current role. It returns nil if there is none.
+func claim(ctx context.Context, db *sql.DB) (*job, error) {
+	var j job
+	err := db.QueryRowContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'RUNNING', started_at = NOW(), heartbeat_at = NOW(),
+			row_count = 0, byte_count = 0, skipped_shards = '{}', error = NULL,
+			role = (SELECT k.role FROM api_keys k WHERE k.api_key = export_jobs.api_key)
+		WHERE id = (
+			SELECT id FROM export_jobs
+			WHERE status = 'PENDING' OR (status = 'RUNNING' AND heartbeat_at < NOW() - $1 * INTERVAL '1 second')
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, kind, format, tld, pattern, record_type, source, data_pattern, first_seen_after, first_seen_before, scrub, started_at,
+			(SELECT COALESCE(k.organization_id, 0) FROM api_keys k WHERE k.api_key = export_jobs.api_key), COALESCE(role, '')
+	`, int(staleAfter.Seconds())).Scan(&j.id, &j.kind, &j.format, &j.tld, &j.pattern, &j.recordType, &j.source, &j.dataPattern,
+		&j.firstSeenAfter, &j.firstSeenBefore, &j.scrub, &j.startedAt, &j.orgID, &j.role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim export job: %v", err)
+	}
+	return &j, nil
+}
+
+// heartbeat stores a running job's progress. It fails if the job has been
+// claimed again by another worker, which then owns it.
+func heartbeat(ctx context.Context, db *sql.DB, j *job, rows, bytes int64) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET heartbeat_at = NOW(), row_count = $3, byte_count = $4
+		WHERE id = $1 AND started_at = $2 AND status = 'RUNNING'
+	`, j.id, j.startedAt, rows, bytes)
+	if err != nil {
+		return fmt.Errorf("failed to store progress: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("export was claimed by another worker")
+	}
+	return nil
+}
+
+// finish records the outcome of a job, unless another worker has claimed it
+// since.
+func finish(db *sql.DB, j *job, result *output, runErr error) error {
+	var err error
+	if runErr == nil {
+		_, err = db.Exec(`
+			UPDATE export_jobs
+			SET status = 'SUCCEEDED', file_name = $3, row_count = $4, byte_count = $5, skipped_shards = $6, signature = NULLIF($7, ''), finished_at = NOW()
+			WHERE id = $1 AND started_at = $2
+		`, j.id, j.startedAt, result.fileName, result.rows, result.bytes, pq.Array(result.skipped), result.signature)
+	} else {
+		_, err = db.Exec(`
+			UPDATE export_jobs
+			SET status = 'FAILED', error = $3, finished_at = NOW()
+			WHERE id = $1 AND started_at = $2
+		`, j.id, j.startedAt, runErr.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record outcome of export %d: %v", j.id, err)
+	}
+	return nil
+}
+
+// expire deletes the files of exports that finished more than
+// exports.retention_hours ago and marks them EXPIRED.
+func expire(ctx context.Context, db *sql.DB, cfg *config.Config) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id FROM export_jobs
+		WHERE status = 'SUCCEEDED' AND finished_at < NOW() - $1 * INTERVAL '1 hour'
+		ORDER BY id
+	`, cfg.Exports.RetentionHours)
+	if err != nil {
+		return fmt.Errorf("failed to query expired exports: %v", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan expired export: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate expired exports: %v", err)
+	}
+
+	for _, id := range ids {
+		if err := os.RemoveAll(filepath.Join(cfg.Exports.OutputDir, strconv.Itoa(id))); err != nil {
+			logger.Errorf("Error deleting expired export %d: %v", id, err)
+			continue
+		}
+		if _, err := db.ExecContext(ctx, "UPDATE export_jobs SET status = 'EXPIRED' WHERE id = $1", id); err != nil {
+			return fmt.Errorf("failed to expire export %d: %v", id, err)
+		}
+	}
+	if len(ids) > 0 {
+		logger.Infof("Deleted %d expired exports", len(ids))
+	}
+	return nil
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Connect to AlloyDB
+	db, err := sql.Open("postgres", storage.ConnString(config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode))
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	scrub, err := newScrubber(config)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Export files are signed if response signing is configured
+	var signer *signing.Signer
+	if len(config.Signing.KeyFiles) > 0 {
+		if signer, err = signing.Load(config.Signing.KeyFiles); err != nil {
+			logger.Fatalf("%v", err)
+		}
+	}
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Run once, or on a schedule when an interval is configured
+	interval := time.Duration(config.Exports.PollSeconds) * time.Second
+	if interval > 0 {
+		shards.StartHealthChecks(ctx, time.Duration(config.Sharding.HealthCheckSeconds)*time.Second)
+	}
+	for {
+		if err := runQueued(ctx, db, shards, config, scrub, signer); err != nil && ctx.Err() == nil {
+			logger.Errorf("Error running exports: %v", err)
+		}
+		if interval <= 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/moos3/bell/config"
+)
+
+// hashableFields can be pseudonymized with exports.scrub.hash.
+var hashableFields = map[string]bool{"domain": true, "nameservers": true, "record_data": true}
+
+// scrubber pseudonymizes and redacts the fields of scrubbed exports so they
+// can be shared under privacy constraints. Registrable domains are replaced
+// with an HMAC of themselves, keeping subdomain labels and the public
+// suffix, so the same domain maps to the same pseudonym in every row and
+// every export made with the same key.
+type scrubber struct {
+	key    []byte
+	hash   map[string]bool
+	redact map[string]bool
+}
+
+// newScrubber returns the scrubber configured by exports.scrub, or nil if no
+// key is set.
+func newScrubber(cfg *config.Config) (*scrubber, error) {
+	if cfg.Exports.Scrub.Key == "" {
+		return nil, nil
+	}
+	columns := make(map[string]bool)
+	for _, c := range append(append([]string{}, domainColumns...), recordColumns...) {
+		columns[c] = true
+	}
+	s := &scrubber{key: []byte(cfg.Exports.Scrub.Key), hash: make(map[string]bool), redact: make(map[string]bool)}
+	for _, f := range cfg.Exports.Scrub.Hash {
+		if !hashableFields[f] {
+			return nil, fmt.Errorf("invalid exports.scrub.hash field %q; must be domain, nameservers or record_data", f)
+		}
+		s.hash[f] = true
+	}
+	for _, f := range cfg.Exports.Scrub.Redact {
+		if !columns[f] {
+			return nil, fmt.Errorf("invalid exports.scrub.redact field %q; must be an export column", f)
+		}
+		s.redact[f] = true
+	}
+	return s, nil
+}
+
+// token returns the HMAC of s as a DNS label.
+func (s *scrubber) token(v string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(v))
+	return hex.EncodeToString(mac.Sum(nil))[:20]
+}
+
+// name replaces the registrable domain of a domain name with its HMAC, e.g.
+// www.example.co.uk. becomes www.<hmac>.co.uk. Public suffixes themselves
+// are returned unchanged.
+func (s *scrubber) name(name string) string {
+	fqdn := strings.HasSuffix(name, ".")
+	lower := strings.ToLower(strings.TrimSuffix(name, "."))
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(lower)
+	if err != nil {
+		return name
+	}
+	suffix := registrable[strings.Index(registrable, ".")+1:]
+	scrubbed := strings.TrimSuffix(lower, registrable) + s.token(registrable) + "." + suffix
+	if fqdn {
+		scrubbed += "."
+	}
+	return scrubbed
+}
+
+// recordData scrubs the owner and target names of a record in zone file
+// format and replaces TXT strings with their HMACs. Data that does not parse
+// is replaced with its HMAC entirely.
+func (s *scrubber) recordData(data string) string {
+	rr, err := dns.NewRR(data)
+	if err != nil || rr == nil {
+		return s.token(data)
+	}
+	rr.Header().Name = s.name(rr.Header().Name)
+	switch r := rr.(type) {
+	case *dns.NS:
+		r.Ns = s.name(r.Ns)
+	case *dns.CNAME:
+		r.Target = s.name(r.Target)
+	case *dns.DNAME:
+		r.Target = s.name(r.Target)
+	case *dns.MX:
+		r.Mx = s.name(r.Mx)
+	case *dns.PTR:
+		r.Ptr = s.name(r.Ptr)
+	case *dns.SRV:
+		r.Target = s.name(r.Target)
+	case *dns.SOA:
+		r.Ns, r.Mbox = s.name(r.Ns), s.name(r.Mbox)
+	case *dns.TXT:
+		for i, txt := range r.Txt {
+			r.Txt[i] = s.token(txt)
+		}
+	}
+	return rr.String()
+}
+
+func (s *scrubber) domainRow(d *domainRow) {
+	if s.hash["domain"] {
+		d.Domain = s.name(d.Domain)
+	}
+	if s.hash["nameservers"] {
+		for i, ns := range d.Nameservers {
+			d.Nameservers[i] = s.name(ns)
+		}
+	}
+	redactDomainRow(d, s.redact)
+}
+
+// redactDomainRow clears the fields of d named in fields.
+func redactDomainRow(d *domainRow, fields map[string]bool) {
+	for field := range fields {
+		switch field {
+		case "domain":
+			d.Domain = ""
+		case "tld":
+			d.TLD = ""
+		case "first_seen":
+			d.FirstSeen = ""
+		case "last_updated":
+			d.LastUpdated = ""
+		case "nameservers":
+			d.Nameservers = []string{}
+		}
+	}
+}
+
+func (s *scrubber) recordRow(r *recordRow) {
+	if s.hash["domain"] {
+		r.Domain = s.name(r.Domain)
+	}
+	if s.hash["record_data"] {
+		r.RecordData = s.recordData(r.RecordData)
+	}
+	redactRecordRow(r, s.redact)
+}
+
+// redactRecordRow clears the fields of r named in fields.
+func redactRecordRow(r *recordRow, fields map[string]bool) {
+	for field := range fields {
+		switch field {
+		case "domain":
+			r.Domain = ""
+		case "tld":
+			r.TLD = ""
+		case "record_type":
+			r.RecordType = ""
+		case "ttl":
+			r.TTL = nil
+		case "source":
+			r.Source = ""
+		case "record_data":
+			r.RecordData = ""
+		case "first_seen":
+			r.FirstSeen = ""
+		case "last_updated":
+			r.LastUpdated = ""
+		}
+	}
+}
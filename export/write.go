@@ -0,0 +1,290 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/signing"
+	"github.com/moos3/bell/storage"
+)
+
+// Columns of each export kind, in CSV order.
+var (
+	domainColumns = []string{"domain", "tld", "first_seen", "last_updated", "nameservers"}
+	recordColumns = []string{"domain", "tld", "record_type", "ttl", "source", "record_data", "first_seen", "last_updated"}
+)
+
+// domainRow and recordRow are the rows of DOMAINS and RECORDS exports. JSONL
+// exports write them as objects; CSV exports write their fields.
+type domainRow struct {
+	Domain      string   `json:"domain"`
+	TLD         string   `json:"tld"`
+	FirstSeen   string   `json:"first_seen"`
+	LastUpdated string   `json:"last_updated,omitempty"`
+	Nameservers []string `json:"nameservers"`
+}
+
+func (d *domainRow) fields() []string {
+	return []string{d.Domain, d.TLD, d.FirstSeen, d.LastUpdated, strings.Join(d.Nameservers, " ")}
+}
+
+type recordRow struct {
+	Domain      string `json:"domain"`
+	TLD         string `json:"tld"`
+	RecordType  string `json:"record_type"`
+	TTL         *int64 `json:"ttl,omitempty"`
+	Source      string `json:"source"`
+	RecordData  string `json:"record_data"`
+	FirstSeen   string `json:"first_seen,omitempty"`
+	LastUpdated string `json:"last_updated,omitempty"`
+}
+
+func (r *recordRow) fields() []string {
+	ttl := ""
+	if r.TTL != nil {
+		ttl = strconv.FormatInt(*r.TTL, 10)
+	}
+	return []string{r.Domain, r.TLD, r.RecordType, ttl, r.Source, r.RecordData, r.FirstSeen, r.LastUpdated}
+}
+
+// output is an export file being written.
+type output struct {
+	fileName  string // Relative to exports.output_dir
+	format    string
+	rows      int64
+	bytes     int64
+	skipped   []string
+	signature string // Of the whole file; empty if signing is not configured
+
+	buf  *bufio.Writer
+	csv  *csv.Writer
+	json *json.Encoder
+	hash hash.Hash // SHA-256 of the bytes written, for the signature
+}
+
+// Write counts the bytes written to the file.
+func (o *output) Write(p []byte) (int, error) {
+	n, err := o.buf.Write(p)
+	o.bytes += int64(n)
+	return n, err
+}
+
+func (o *output) write(row interface{ fields() []string }) error {
+	o.rows++
+	if o.format == "JSONL" {
+		return o.json.Encode(row)
+	}
+	return o.csv.Write(row.fields())
+}
+
+// run writes the rows matching a job's filter to <job id>/<kind>.<format>
+// under exports.output_dir, one shard after another, and signs the file if
+// signer is set.
+func run(ctx context.Context, db *sql.DB, shards *storage.Router, cfg *config.Config, scrub *scrubber, signer *signing.Signer, j *job) (*output, error) {
+	if !j.scrub {
+		scrub = nil
+	} else if scrub == nil {
+		return nil, fmt.Errorf("scrubbed export requested but exports.scrub.key is not set on the export worker")
+	}
+	ext := strings.ToLower(j.format)
+	o := &output{fileName: path.Join(strconv.Itoa(j.id), strings.ToLower(j.kind)+"."+ext), format: j.format}
+	name := filepath.Join(cfg.Exports.OutputDir, filepath.FromSlash(o.fileName))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %v", err)
+	}
+	f, err := os.Create(name + ".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %v", err)
+	}
+	// Remove the partial file unless it is renamed into place below
+	defer os.Remove(name + ".tmp")
+	defer f.Close()
+
+	o.hash = sha256.New()
+	o.buf = bufio.NewWriterSize(io.MultiWriter(f, o.hash), 1<<20)
+	o.csv = csv.NewWriter(o)
+	o.json = json.NewEncoder(o)
+	if j.format == "CSV" {
+		columns := domainColumns
+		if j.kind == "RECORDS" {
+			columns = recordColumns
+		}
+		o.csv.Write(columns)
+	}
+
+	// Write shards in turn rather than with FanOut, since rows go to one file
+	targets := shards.Shards()
+	if j.tld != "" {
+		targets = []*storage.Shard{shards.ForTLD(j.tld)}
+	}
+	lastHeartbeat := time.Now()
+	for _, shard := range targets {
+		if !shard.Healthy() {
+			o.skipped = append(o.skipped, shard.Name)
+			continue
+		}
+		err := exportShard(ctx, shard.DB, j, o, scrub, func() error {
+			if o.rows > cfg.Exports.MaxRows {
+				return fmt.Errorf("export has more than exports.max_rows (%d) rows; narrow the filter", cfg.Exports.MaxRows)
+			}
+			if time.Since(lastHeartbeat) < heartbeatInterval {
+				return nil
+			}
+			lastHeartbeat = time.Now()
+			return heartbeat(ctx, db, j, o.rows, o.bytes)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+	}
+
+	o.csv.Flush()
+	if err := o.csv.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write export file: %v", err)
+	}
+	if err := o.buf.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write export file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write export file: %v", err)
+	}
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return nil, fmt.Errorf("failed to write export file: %v", err)
+	}
+	if signer != nil {
+		o.signature = signer.Sign(o.hash.Sum(nil), time.Now())
+	}
+	return o, nil
+}
+
+// exportShard writes the rows of one shard matching a job's filter and
+// visible to its key's organization, scrubbed if scrub is set and without
+// the fields its key's role may not see, calling progress after each row.
+func exportShard(ctx context.Context, db *sql.DB, j *job, o *output, scrub *scrubber, progress func() error) error {
+	var q *storage.Query
+	if j.kind == "DOMAINS" {
+		q = storage.NewQuery("SELECT d.domain_name, d.tld, d.first_seen, d.last_updated, d.nameservers FROM domains d")
+	} else {
+		q = storage.NewQuery(`
+			SELECT d.domain_name, d.tld, r.record_type, r.ttl, COALESCE(r.source, ''), r.record_data, r.record_data_z, r.first_seen, r.last_updated
+			FROM dns_records r
+			JOIN domains d ON d.id = r.domain_id
+		`)
+	}
+	q.WhereVisible("d", j.orgID)
+	if j.tld != "" {
+		q.WhereTLD("d", j.tld)
+	}
+	if j.pattern != "" {
+		q.Where("d.domain_name LIKE ?", globPattern(j.pattern))
+	}
+	if j.firstSeenAfter.Valid {
+		q.Where("d.first_seen > ?", j.firstSeenAfter.Time)
+	}
+	if j.firstSeenBefore.Valid {
+		q.Where("d.first_seen < ?", j.firstSeenBefore.Time)
+	}
+	if j.recordType != "" {
+		q.Where("r.record_type = ?", j.recordType)
+	}
+	if j.source != "" {
+		q.Where("r.source = ?", j.source)
+	}
+	if j.dataPattern != "" {
+		q.Where("r.record_data LIKE ?", globPattern(j.dataPattern))
+	}
+
+	rows, err := db.QueryContext(ctx, q.SQL(), q.Args()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lastUpdated sql.NullTime
+		if j.kind == "DOMAINS" {
+			var d domainRow
+			var firstSeen time.Time
+			if err := rows.Scan(&d.Domain, &d.TLD, &firstSeen, &lastUpdated, pq.Array(&d.Nameservers)); err != nil {
+				return err
+			}
+			d.FirstSeen = firstSeen.UTC().Format(time.RFC3339)
+			if lastUpdated.Valid {
+				d.LastUpdated = lastUpdated.Time.UTC().Format(time.RFC3339)
+			}
+			if d.Nameservers == nil {
+				d.Nameservers = []string{}
+			}
+			if scrub != nil {
+				scrub.domainRow(&d)
+			}
+			redactDomainRow(&d, j.redact)
+			err = o.write(&d)
+		} else {
+			var r recordRow
+			var ttl sql.NullInt64
+			var z []byte
+			var firstSeen time.Time
+			if err := rows.Scan(&r.Domain, &r.TLD, &r.RecordType, &ttl, &r.Source, &r.RecordData, &z, &firstSeen, &lastUpdated); err != nil {
+				return err
+			}
+			if r.RecordData, err = storage.RecordData(r.RecordData, z); err != nil {
+				return err
+			}
+			if ttl.Valid {
+				r.TTL = &ttl.Int64
+			}
+			r.FirstSeen = firstSeen.UTC().Format(time.RFC3339)
+			if lastUpdated.Valid {
+				r.LastUpdated = lastUpdated.Time.UTC().Format(time.RFC3339)
+			}
+			if scrub != nil {
+				scrub.recordRow(&r)
+			}
+			redactRecordRow(&r, j.redact)
+			err = o.write(&r)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write export file: %v", err)
+		}
+		if err := progress(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// globPattern converts a glob, where * matches any run of characters and ?
+// any one, to a LIKE pattern, as the server does for the same filters.
+func globPattern(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
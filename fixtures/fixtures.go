@@ -0,0 +1,316 @@
+// Package fixtures generates synthetic zones, domains and DNS records at a
+// configurable scale, for integration tests, benchmarks and the sandbox.
+//
+// Generation is deterministic: the same Options always produce the same
+// dataset, so a failing test or a benchmark can be reproduced from its seed.
+// Names default to the reserved .example TLD, nameservers and mail hosts
+// live under .example, and every address is in a documentation range
+// (192.0.2.0/24, 198.51.100.0/24, 203.0.113.0/24, 2001:db8::/32), so nothing
+// generated resolves or belongs to anyone.
+//
+// Each domain carries the records the ingest pipeline would store for it: a
+// delegation (NS, some with DS) from the zone file as source CZDS, and for
+// most domains the apex records the query worker would resolve as source
+// QUERY. Zone.WriteZoneFile writes the CZDS side as a zone file, for tests
+// and benchmarks of zone ingestion.
+package fixtures
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Options control the size and shape of a generated dataset. Zero fields
+// take the defaults noted.
+type Options struct {
+	Seed          int64     // Seeds the generator; datasets with equal Options are identical
+	TLDs          []string  // Zones to generate; ["example"] if empty
+	DomainsPerTLD int       // 100 if zero
+	ResolvedRatio float64   // Fraction of domains with QUERY records; 0.8 if zero
+	DGARatio      float64   // Fraction of algorithmically generated names; 0.05 if zero
+	Now           time.Time // Latest first_seen; 2024-01-01 UTC if zero
+	FirstSeenDays int       // first_seen is spread over this many days before Now; 90 if zero
+}
+
+// Dataset is a generated set of zones, with PTR records for some of the
+// addresses their domains resolve to.
+type Dataset struct {
+	Zones []*Zone
+	PTR   []*dns.PTR
+}
+
+// Zone is a generated TLD zone.
+type Zone struct {
+	TLD     string
+	Serial  uint32
+	Domains []*Domain // Sorted by name
+}
+
+// Domain is a generated domain and its records.
+type Domain struct {
+	Name        string // Without a trailing dot
+	TLD         string
+	Nameservers []string // Without trailing dots
+	FirstSeen   time.Time
+	Records     map[string][]dns.RR // Source (CZDS or QUERY) -> records
+	LikelyDGA   bool                // The name was generated to look like DGA output
+}
+
+// Records returns the number of records in the dataset, PTR records
+// excluded.
+func (d *Dataset) Records() int {
+	n := 0
+	for _, z := range d.Zones {
+		for _, domain := range z.Domains {
+			for _, rrs := range domain.Records {
+				n += len(rrs)
+			}
+		}
+	}
+	return n
+}
+
+// Domains returns the number of domains in the dataset.
+func (d *Dataset) Domains() int {
+	n := 0
+	for _, z := range d.Zones {
+		n += len(z.Domains)
+	}
+	return n
+}
+
+// Word lists names are built from. Changing them changes every dataset, so
+// add to them only with the understanding that seeds stop reproducing.
+var (
+	nameWords = []string{
+		"acme", "alpine", "amber", "apex", "atlas", "beacon", "birch", "blue", "bright", "cedar",
+		"cobalt", "coral", "crest", "delta", "ember", "falcon", "fern", "forge", "granite", "harbor",
+		"iris", "juniper", "lumen", "maple", "meadow", "nimbus", "north", "oak", "orbit", "pine",
+		"quartz", "river", "sage", "summit", "tidal", "vertex", "willow", "zenith",
+	}
+	businessWords = []string{
+		"analytics", "bakery", "cloud", "consulting", "design", "dental", "foods", "games", "health", "labs",
+		"law", "media", "motors", "outdoors", "pay", "realty", "shop", "software", "studio", "travel", "widgets",
+	}
+	dnsProviders  = []string{"dns-host-a", "dns-host-b", "cloud-dns", "registrar-parking", "edge-dns"}
+	mailProviders = []string{"mail-relay", "mx-cloud", "inbox-hosting"}
+	cas           = []string{"ca.example", "letsencrypt.example", "trusted-ca.example"}
+	ipv4Ranges    = []net.IP{net.IPv4(192, 0, 2, 0), net.IPv4(198, 51, 100, 0), net.IPv4(203, 0, 113, 0)}
+)
+
+// Generate builds a dataset from opts.
+func Generate(opts Options) *Dataset {
+	if len(opts.TLDs) == 0 {
+		opts.TLDs = []string{"example"}
+	}
+	if opts.DomainsPerTLD == 0 {
+		opts.DomainsPerTLD = 100
+	}
+	if opts.ResolvedRatio == 0 {
+		opts.ResolvedRatio = 0.8
+	}
+	if opts.DGARatio == 0 {
+		opts.DGARatio = 0.05
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if opts.FirstSeenDays == 0 {
+		opts.FirstSeenDays = 90
+	}
+
+	g := &generator{opts: opts, rng: rand.New(rand.NewSource(opts.Seed)), ptrs: make(map[string]bool)}
+	ds := &Dataset{}
+	for _, tld := range opts.TLDs {
+		tld = strings.ToLower(strings.Trim(tld, ". "))
+		z := &Zone{TLD: tld, Serial: uint32(opts.Now.Unix())}
+		seen := make(map[string]bool)
+		for len(z.Domains) < opts.DomainsPerTLD {
+			dga := g.rng.Float64() < opts.DGARatio
+			label := g.label(dga)
+			if seen[label] {
+				// Generated labels never end in -<digits>, so this is unique
+				label += "-" + strconv.Itoa(len(z.Domains))
+			}
+			seen[label] = true
+			z.Domains = append(z.Domains, g.domain(label+"."+tld, tld, dga, ds))
+		}
+		sort.Slice(z.Domains, func(i, j int) bool { return z.Domains[i].Name < z.Domains[j].Name })
+		ds.Zones = append(ds.Zones, z)
+	}
+	return ds
+}
+
+type generator struct {
+	opts Options
+	rng  *rand.Rand
+	ptrs map[string]bool // Addresses that already have a PTR record
+}
+
+func (g *generator) pick(words []string) string {
+	return words[g.rng.Intn(len(words))]
+}
+
+// label returns a second-level label: a word-based business name, or a run
+// of random letters and digits for DGA names. Labels may repeat; the caller
+// makes them unique.
+func (g *generator) label(dga bool) string {
+	if dga {
+		const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+		b := make([]byte, 10+g.rng.Intn(8))
+		for i := range b {
+			b[i] = chars[g.rng.Intn(len(chars))]
+		}
+		return string(b)
+	}
+	switch g.rng.Intn(4) {
+	case 0:
+		return g.pick(nameWords) + g.pick(businessWords)
+	case 1:
+		return g.pick(nameWords) + "-" + g.pick(businessWords)
+	case 2:
+		return fmt.Sprintf("%s%s%d", g.pick(nameWords), g.pick(businessWords), 1+g.rng.Intn(99))
+	default:
+		return g.pick(nameWords) + "-" + g.pick(nameWords) + "-" + g.pick(businessWords)
+	}
+}
+
+func (g *generator) domain(name, tld string, dga bool, ds *Dataset) *Domain {
+	d := &Domain{
+		Name:      name,
+		TLD:       tld,
+		FirstSeen: g.opts.Now.Add(-time.Duration(g.rng.Int63n(int64(g.opts.FirstSeenDays) * int64(24*time.Hour)))).Truncate(time.Second),
+		Records:   make(map[string][]dns.RR),
+		LikelyDGA: dga,
+	}
+	owner := dns.Fqdn(name)
+
+	// Delegation, as published in the TLD zone
+	if dga {
+		d.Nameservers = []string{"ns1.fastflux-host.example"}
+	} else {
+		provider := g.pick(dnsProviders)
+		d.Nameservers = []string{"ns1." + provider + ".example", "ns2." + provider + ".example"}
+	}
+	for _, ns := range d.Nameservers {
+		d.Records["CZDS"] = append(d.Records["CZDS"], &dns.NS{Hdr: header(owner, dns.TypeNS, 86400), Ns: dns.Fqdn(ns)})
+	}
+	if !dga && g.rng.Float64() < 0.2 {
+		digest := make([]byte, 32) // SHA-256
+		g.rng.Read(digest)
+		d.Records["CZDS"] = append(d.Records["CZDS"], &dns.DS{Hdr: header(owner, dns.TypeDS, 86400),
+			KeyTag: uint16(g.rng.Intn(65536)), Algorithm: dns.ECDSAP256SHA256, DigestType: dns.SHA256, Digest: strings.ToUpper(hex.EncodeToString(digest))})
+	}
+
+	if g.rng.Float64() >= g.opts.ResolvedRatio {
+		return d
+	}
+
+	// Apex records, as resolved by the query worker
+	var query []dns.RR
+	add := func(rr dns.RR) { query = append(query, rr) }
+	add(&dns.SOA{Hdr: header(owner, dns.TypeSOA, 3600), Ns: dns.Fqdn(d.Nameservers[0]), Mbox: "hostmaster." + owner,
+		Serial: uint32(d.FirstSeen.Unix()), Refresh: 7200, Retry: 3600, Expire: 1209600, Minttl: 300})
+	if dga {
+		// Fast flux: several short-lived addresses, no mail
+		for i := 0; i < 2+g.rng.Intn(3); i++ {
+			add(&dns.A{Hdr: header(owner, dns.TypeA, 30), A: g.ipv4()})
+		}
+		d.Records["QUERY"] = query
+		return d
+	}
+
+	if g.rng.Float64() < 0.1 {
+		// Hosted behind a CDN
+		add(&dns.CNAME{Hdr: header("www."+owner, dns.TypeCNAME, 60), Target: "edge-lb.cdn-provider.example."})
+	}
+	ttl := []uint32{60, 300, 600, 3600}[g.rng.Intn(4)]
+	for i := 0; i < 1+g.rng.Intn(2); i++ {
+		ip := g.ipv4()
+		add(&dns.A{Hdr: header(owner, dns.TypeA, ttl), A: ip})
+		if !g.ptrs[ip.String()] && g.rng.Float64() < 0.5 {
+			g.ptrs[ip.String()] = true
+			reverse, _ := dns.ReverseAddr(ip.String())
+			ds.PTR = append(ds.PTR, &dns.PTR{Hdr: header(reverse, dns.TypePTR, 3600), Ptr: owner})
+		}
+	}
+	if g.rng.Float64() < 0.5 {
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, net.ParseIP("2001:db8::"))
+		ip[5], ip[14], ip[15] = byte(g.rng.Intn(256)), byte(g.rng.Intn(256)), byte(1+g.rng.Intn(254))
+		add(&dns.AAAA{Hdr: header(owner, dns.TypeAAAA, ttl), AAAA: ip})
+	}
+
+	switch r := g.rng.Float64(); {
+	case r < 0.05:
+		// Null MX (RFC 7505): the domain accepts no mail
+		add(&dns.MX{Hdr: header(owner, dns.TypeMX, 3600), Preference: 0, Mx: "."})
+		add(txt(owner, "v=spf1 -all"))
+	case r < 0.75:
+		provider := g.pick(mailProviders)
+		add(&dns.MX{Hdr: header(owner, dns.TypeMX, 3600), Preference: 10, Mx: "mx1." + provider + ".example."})
+		if g.rng.Float64() < 0.6 {
+			add(&dns.MX{Hdr: header(owner, dns.TypeMX, 3600), Preference: 20, Mx: "mx2." + provider + ".example."})
+		}
+		if g.rng.Float64() < 0.8 {
+			policy := []string{"-all", "-all", "~all", "?all"}[g.rng.Intn(4)]
+			add(txt(owner, "v=spf1 include:_spf."+provider+".example "+policy))
+		}
+	}
+	if g.rng.Float64() < 0.3 {
+		add(txt(owner, fmt.Sprintf("site-verification=%06x", g.rng.Intn(1<<24))))
+	}
+	if g.rng.Float64() < 0.2 {
+		add(&dns.CAA{Hdr: header(owner, dns.TypeCAA, 3600), Flag: 0, Tag: "issue", Value: g.pick(cas)})
+	}
+	d.Records["QUERY"] = query
+	return d
+}
+
+// ipv4 returns an address in one of the IPv4 documentation ranges.
+func (g *generator) ipv4() net.IP {
+	base := ipv4Ranges[g.rng.Intn(len(ipv4Ranges))].To4()
+	return net.IPv4(base[0], base[1], base[2], byte(1+g.rng.Intn(254)))
+}
+
+func header(name string, rrtype uint16, ttl uint32) dns.RR_Header {
+	return dns.RR_Header{Name: name, Rrtype: rrtype, Class: dns.ClassINET, Ttl: ttl}
+}
+
+func txt(owner, text string) *dns.TXT {
+	return &dns.TXT{Hdr: header(owner, dns.TypeTXT, 3600), Txt: []string{text}}
+}
+
+// WriteZoneFile writes the zone as its registry would publish it: the TLD's
+// SOA and NS records followed by each domain's CZDS records.
+func (z *Zone) WriteZoneFile(w io.Writer) error {
+	origin := dns.Fqdn(z.TLD)
+	soa := &dns.SOA{Hdr: header(origin, dns.TypeSOA, 900), Ns: "a.nic." + origin, Mbox: "hostmaster.nic." + origin,
+		Serial: z.Serial, Refresh: 1800, Retry: 900, Expire: 604800, Minttl: 86400}
+	if _, err := fmt.Fprintln(w, soa.String()); err != nil {
+		return err
+	}
+	for _, ns := range []string{"a.nic.", "b.nic."} {
+		rr := &dns.NS{Hdr: header(origin, dns.TypeNS, 172800), Ns: ns + origin}
+		if _, err := fmt.Fprintln(w, rr.String()); err != nil {
+			return err
+		}
+	}
+	for _, d := range z.Domains {
+		for _, rr := range d.Records["CZDS"] {
+			if _, err := fmt.Fprintln(w, rr.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
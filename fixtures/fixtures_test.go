@@ -0,0 +1,49 @@
+package fixtures
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func zoneFile(t *testing.T, z *Zone) []byte {
+	var b bytes.Buffer
+	if err := z.WriteZoneFile(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	opts := Options{Seed: 42, TLDs: []string{"example", "test"}, DomainsPerTLD: 200}
+	a, b := Generate(opts), Generate(opts)
+	if a.Domains() != 400 || a.Records() != b.Records() || len(a.PTR) != len(b.PTR) {
+		t.Fatalf("datasets differ: %d/%d domains, %d/%d records", a.Domains(), b.Domains(), a.Records(), b.Records())
+	}
+	for i := range a.Zones {
+		if !bytes.Equal(zoneFile(t, a.Zones[i]), zoneFile(t, b.Zones[i])) {
+			t.Errorf("zone %s differs between runs with seed %d", a.Zones[i].TLD, opts.Seed)
+		}
+	}
+	opts.Seed++
+	if c := Generate(opts); bytes.Equal(zoneFile(t, a.Zones[0]), zoneFile(t, c.Zones[0])) {
+		t.Error("seeds 42 and 43 generated the same zone")
+	}
+}
+
+func TestGeneratedNamesAreReserved(t *testing.T) {
+	ds := Generate(Options{Seed: 1, DomainsPerTLD: 500})
+	for _, d := range ds.Zones[0].Domains {
+		if !strings.HasSuffix(d.Name, ".example") {
+			t.Errorf("domain %s is outside .example", d.Name)
+		}
+		for _, ns := range d.Nameservers {
+			if !strings.HasSuffix(ns, ".example") {
+				t.Errorf("nameserver %s of %s is outside .example", ns, d.Name)
+			}
+		}
+		if len(d.Records["CZDS"]) == 0 {
+			t.Errorf("domain %s has no delegation", d.Name)
+		}
+	}
+}
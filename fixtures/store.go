@@ -0,0 +1,90 @@
+package fixtures
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+
+	"github.com/moos3/bell/recordset"
+)
+
+// DatabaseEnv names the environment variable holding the connection string
+// of the scratch database integration tests and benchmarks run against. It
+// must have schema.sql applied; its DNS data is cleared by OpenDatabase.
+const DatabaseEnv = "BELL_TEST_DATABASE"
+
+// OpenDatabase connects to the scratch database named by DatabaseEnv and
+// clears its domains and records, skipping tb if the variable is unset. The
+// connection is closed when tb ends.
+func OpenDatabase(tb testing.TB) *sql.DB {
+	tb.Helper()
+	dsn := os.Getenv(DatabaseEnv)
+	if dsn == "" {
+		tb.Skipf("set %s to run against a scratch database", DatabaseEnv)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		tb.Fatalf("Failed to connect to %s: %v", DatabaseEnv, err)
+	}
+	if _, err := db.Exec("TRUNCATE dns_records, domains RESTART IDENTITY CASCADE"); err != nil {
+		tb.Fatalf("Failed to clear scratch database: %v", err)
+	}
+	return db
+}
+
+// Store writes the zone's domains, unless already stored, and their records
+// of sources (every source if none) into db, as the ingest pipeline would
+// have stored them.
+func (z *Zone) Store(db *sql.DB, sources ...string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	domainStmt, err := tx.Prepare(`
+		INSERT INTO domains (domain_name, tld, public_suffix, nameservers, first_seen)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (domain_name, tld) DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer domainStmt.Close()
+	recordStmt, err := tx.Prepare(`
+		INSERT INTO dns_records (domain_id, record_type, record_data, canonical_hash, ttl, source, first_seen, priority, weight)
+		SELECT id, $3, $4, $5, $6, $7, $8, $9, $10 FROM domains WHERE domain_name = $1 AND tld = $2
+		ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer recordStmt.Close()
+	if len(sources) == 0 {
+		sources = []string{"CZDS", "QUERY"}
+	}
+	tld := recordset.TLD(z.TLD)
+	for _, d := range z.Domains {
+		if _, err := domainStmt.Exec(d.Name, tld, recordset.PublicSuffix(d.Name), pq.Array(d.Nameservers), d.FirstSeen); err != nil {
+			return fmt.Errorf("failed to insert domain %s: %v", d.Name, err)
+		}
+		for _, source := range sources {
+			for _, rr := range d.Records[source] {
+				priority, weight := recordset.SortKeys(rr)
+				data := recordset.NormalizeRR(rr)
+				if _, err := recordStmt.Exec(d.Name, tld, dns.TypeToString[rr.Header().Rrtype], data, recordset.CanonicalHash(data),
+					int(rr.Header().Ttl), source, d.FirstSeen, priority, weight); err != nil {
+					return fmt.Errorf("failed to insert record for %s: %v", d.Name, err)
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,107 @@
+package ingest
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/moos3/bell/config"
+)
+
+// TokenMetadata is the metadata key writers send ingest.token under.
+const TokenMetadata = "ingest-token"
+
+// serverOptions returns the options that secure the ingest service: TLS,
+// requiring client certificates under ingest.tls.client_ca_file, and the
+// ingest.token check. It fails if writers would be neither authenticated by
+// certificate nor by token, since any record under any source would then be
+// accepted from whoever reaches the port.
+func serverOptions(cfg *config.Config) ([]grpc.ServerOption, error) {
+	t := cfg.Ingest.TLS
+	if cfg.Ingest.Token == "" && t.ClientCAFile == "" {
+		return nil, fmt.Errorf("the ingest service requires ingest.token or ingest.tls.client_ca_file")
+	}
+	var opts []grpc.ServerOption
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificate %s: %v", t.CertFile, err)
+		}
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+		if t.ClientCAFile != "" {
+			if tlsConfig.ClientCAs, err = loadCertPool(t.ClientCAFile); err != nil {
+				return nil, err
+			}
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if cfg.Ingest.Token != "" {
+		opts = append(opts, grpc.UnaryInterceptor(tokenInterceptor(cfg.Ingest.Token)))
+	}
+	return opts, nil
+}
+
+// tokenInterceptor refuses calls that do not carry token as TokenMetadata.
+func tokenInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(TokenMetadata)
+		if len(values) != 1 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(token)) != 1 {
+			return nil, statusError(codes.Unauthenticated, "INVALID_TOKEN", nil, "missing or invalid %s", TokenMetadata)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// DialOptions returns the options writers dial the ingest service with:
+// TLS when it serves TLS, verified against ingest.tls.ca_file and presenting
+// ingest.tls.client_cert_file, and ingest.token on every call.
+func DialOptions(cfg *config.Config) ([]grpc.DialOption, error) {
+	t := cfg.Ingest.TLS
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if t.CertFile != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if t.CAFile != "" {
+			var err error
+			if tlsConfig.RootCAs, err = loadCertPool(t.CAFile); err != nil {
+				return nil, err
+			}
+		}
+		if t.ClientCertFile != "" {
+			cert, err := tls.LoadX509KeyPair(t.ClientCertFile, t.ClientKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate %s: %v", t.ClientCertFile, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+	if token := cfg.Ingest.Token; token != "" {
+		opts = append(opts, grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, TokenMetadata, token), method, req, reply, cc, callOpts...)
+		}))
+	}
+	return opts, nil
+}
+
+// loadCertPool reads a PEM CA bundle.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle %s: %v", file, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in CA bundle %s", file)
+	}
+	return pool, nil
+}
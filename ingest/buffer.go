@@ -0,0 +1,352 @@
+package ingest
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/pressure"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+// bufferedRecord is a record waiting to be written to its shard.
+type bufferedRecord struct {
+	shard      *storage.Shard
+	domainID   int32
+	recordType string
+	recordData string
+	ttl        int32
+	source     string
+	observedAt time.Time
+	attempts   int // Failed writes of the record on its own
+}
+
+// recordKey identifies a record the way dns_records does; later observations
+// of the same record replace earlier ones in the buffer, so a batch never
+// upserts a row twice. Domain IDs are only unique within a shard.
+type recordKey struct {
+	shard      string
+	domainID   int32
+	recordType string
+	canonical  string // recordset.Canonical of the record data
+	source     string
+}
+
+// writeBuffer coalesces records from all workers and writes them in large
+// COPY batches from a single writer, so crawl speed is decoupled from
+// database write latency and write load is throttled in one place.
+type writeBuffer struct {
+	shards           *storage.Router
+	db               *sql.DB // Default database, which events go to
+	flushSize        int
+	maxBuffered      int
+	maxRowsPerSecond int
+	maxAttempts      int // Failed writes of a record on its own before it is dropped
+	ttlAnomalyRatio  float64
+	compressMinBytes int               // TXT data at least this long is stored compressed (see storage.EncodeRecordData)
+	pressure         *pressure.Monitor // Holds commits while a shard is under pressure; nil never holds them
+
+	mu      sync.Mutex
+	pending map[recordKey]bufferedRecord
+	flushCh chan struct{}
+}
+
+func newWriteBuffer(shards *storage.Router, flushSize, maxBuffered, maxRowsPerSecond, maxAttempts int, ttlAnomalyRatio float64, compressMinBytes int) *writeBuffer {
+	return &writeBuffer{
+		shards:           shards,
+		db:               shards.Default(),
+		flushSize:        flushSize,
+		maxBuffered:      maxBuffered,
+		maxRowsPerSecond: maxRowsPerSecond,
+		maxAttempts:      maxAttempts,
+		ttlAnomalyRatio:  ttlAnomalyRatio,
+		compressMinBytes: compressMinBytes,
+		pending:          make(map[recordKey]bufferedRecord),
+		flushCh:          make(chan struct{}, 1),
+	}
+}
+
+// add buffers records, each replacing a buffered duplicate unless the
+// duplicate was observed later, so that records put back after a failed
+// write never overwrite newer observations. It returns the number of
+// buffered records, or ok=false without buffering anything if the records
+// not yet buffered would exceed maxBuffered.
+func (b *writeBuffer) add(records []bufferedRecord) (buffered int, ok bool) {
+	return b.put(records, true)
+}
+
+// put is add; signal says whether reaching flushSize triggers a flush. It
+// does not for records put back by a flush, which would otherwise retry
+// them at once.
+func (b *writeBuffer) put(records []bufferedRecord, signal bool) (buffered int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	keys := make([]recordKey, len(records))
+	added := 0
+	for i, r := range records {
+		keys[i] = recordKey{r.shard.Name, r.domainID, r.recordType, recordset.Canonical(r.recordData), r.source}
+		if _, ok := b.pending[keys[i]]; !ok {
+			added++
+		}
+	}
+	if len(b.pending)+added > b.maxBuffered {
+		return len(b.pending), false
+	}
+	for i, r := range records {
+		if existing, ok := b.pending[keys[i]]; ok && existing.observedAt.After(r.observedAt) {
+			continue
+		}
+		b.pending[keys[i]] = r
+	}
+	if signal && len(b.pending) >= b.flushSize {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(b.pending), true
+}
+
+// take removes and returns all buffered records, grouped by shard.
+func (b *writeBuffer) take() []bufferedRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records := make([]bufferedRecord, 0, len(b.pending))
+	for _, r := range b.pending {
+		records = append(records, r)
+	}
+	b.pending = make(map[recordKey]bufferedRecord)
+	sort.Slice(records, func(i, j int) bool { return records[i].shard.Name < records[j].shard.Name })
+	return records
+}
+
+// run flushes the buffer every interval or when it reaches flushSize, until
+// stop is closed; it then flushes whatever is left.
+func (b *writeBuffer) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			b.flushAll(stop)
+			return
+		case <-ticker.C:
+		case <-b.flushCh:
+		}
+		b.flushAll(stop)
+	}
+}
+
+// flushAll writes buffered records shard by shard, and puts back the
+// records left unwritten for the next flush.
+func (b *writeBuffer) flushAll(stop <-chan struct{}) {
+	records := b.take()
+	var retry []bufferedRecord
+	for start := 0; start < len(records); {
+		end := start + 1
+		for end < len(records) && records[end].shard == records[start].shard {
+			end++
+		}
+		retry = append(retry, b.flushShard(records[start].shard, records[start:end], stop)...)
+		start = end
+	}
+	if len(retry) == 0 {
+		return
+	}
+	if _, ok := b.put(retry, false); !ok {
+		logger.Errorf("Buffer full; dropped %d unwritten records", len(retry))
+		events.Publish(b.db, events.Event{Source: events.SourceIngest, Kind: events.Error, Count: int64(len(retry)),
+			Message: "buffer full; dropped unwritten records"})
+	}
+}
+
+// flushShard writes the records of shard in chunks of at most flushSize,
+// pacing writes to maxRowsPerSecond and holding or slowing them while the
+// shard is under pressure, except once stop is closed. It returns the
+// records to write again: all that are left if the shard cannot be reached,
+// and otherwise those of a failed chunk that fail on their own (see
+// isolate).
+func (b *writeBuffer) flushShard(shard *storage.Shard, records []bufferedRecord, stop <-chan struct{}) []bufferedRecord {
+	var retry []bufferedRecord
+	for start := 0; start < len(records); start += b.flushSize {
+		end := start + b.flushSize
+		if end > len(records) {
+			end = len(records)
+		}
+		slow := b.pressure.Wait(shard, end-start, stop)
+		began := time.Now()
+		if err := b.commit(shard, records[start:end]); err != nil {
+			if pingErr := shard.DB.Ping(); pingErr != nil {
+				logger.Warnf("Shard %s unreachable; keeping %d records for the next flush: %v", shard.Name, len(records)-start, pingErr)
+				return append(retry, records[start:]...)
+			}
+			retry = append(retry, b.isolate(shard, records[start:end])...)
+			continue
+		}
+		if slow {
+			time.Sleep(time.Since(began))
+		}
+		if b.maxRowsPerSecond > 0 {
+			budget := time.Duration(end-start) * time.Second / time.Duration(b.maxRowsPerSecond)
+			time.Sleep(budget - time.Since(began))
+		}
+	}
+	return retry
+}
+
+// commit writes records to shard and publishes the outcome.
+func (b *writeBuffer) commit(shard *storage.Shard, records []bufferedRecord) error {
+	began := time.Now()
+	changes, err := b.write(shard.DB, records)
+	if err != nil {
+		logger.Errorf("Error writing %d records to shard %s: %v", len(records), shard.Name, err)
+		events.Publish(b.db, events.Event{Source: events.SourceIngest, Kind: events.BatchFailed, Count: int64(len(records)), Message: err.Error()})
+		return err
+	}
+	logger.Infof("Wrote %d records to shard %s in %v", len(records), shard.Name, time.Since(began).Round(time.Millisecond))
+	events.Publish(b.db, events.Event{Source: events.SourceIngest, Kind: events.BatchCommitted, Count: int64(len(records))})
+	for _, e := range changes {
+		events.Publish(shard.DB, e)
+	}
+	return nil
+}
+
+// isolate writes the records of a failed chunk in halves, splitting the
+// halves that fail again, so that one bad record does not hold back the
+// rest. It returns the records that failed on their own, with the attempt
+// counted, to write again; those that failed maxAttempts times are dropped.
+// A failure while the shard cannot be reached is not counted.
+func (b *writeBuffer) isolate(shard *storage.Shard, records []bufferedRecord) []bufferedRecord {
+	if len(records) == 1 {
+		r := records[0]
+		if shard.DB.Ping() != nil {
+			return records
+		}
+		r.attempts++
+		if r.attempts < b.maxAttempts {
+			return []bufferedRecord{r}
+		}
+		message := fmt.Sprintf("dropped %s record of domain %d from %s on shard %s after %d failed writes",
+			r.recordType, r.domainID, r.source, shard.Name, r.attempts)
+		logger.Errorf("Ingest %s: %q", message, r.recordData)
+		events.Publish(b.db, events.Event{Source: events.SourceIngest, Kind: events.Error, Count: 1, Message: message})
+		return nil
+	}
+	var retry []bufferedRecord
+	mid := len(records) / 2
+	for _, half := range [][]bufferedRecord{records[:mid], records[mid:]} {
+		if b.commit(shard, half) != nil {
+			retry = append(retry, b.isolate(shard, half)...)
+		}
+	}
+	return retry
+}
+
+// write copies records into a staging table on db, the records' shard, flags
+// TTL anomalies against the latest stored observation, and upserts the
+// records and domain timestamps. It returns the record_set_changed events of
+// the record sets that gained a record, to publish once committed. Sets of
+// QUERY records are left out: the query worker classifies and publishes
+// those itself.
+func (b *writeBuffer) write(db *sql.DB, records []bufferedRecord) ([]events.Event, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TEMP TABLE ingest_staging (
+			domain_id INTEGER, record_type VARCHAR(20), record_data TEXT, record_data_z BYTEA, canonical_hash BYTEA,
+			ttl INTEGER, source VARCHAR(10), last_updated TIMESTAMP,
+			priority INTEGER, weight INTEGER
+		) ON COMMIT DROP
+	`); err != nil {
+		return nil, fmt.Errorf("failed to create staging table: %v", err)
+	}
+	stmt, err := tx.Prepare(pq.CopyIn("ingest_staging", "domain_id", "record_type", "record_data", "record_data_z", "canonical_hash", "ttl", "source", "last_updated", "priority", "weight"))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		priority, weight := recordset.ParseSortKeys(r.recordData)
+		data, z := storage.EncodeRecordData(r.recordType, r.recordData, b.compressMinBytes)
+		if _, err := stmt.Exec(r.domainID, r.recordType, data, z, recordset.CanonicalHash(r.recordData), r.ttl, r.source, r.observedAt, priority, weight); err != nil {
+			stmt.Close()
+			return nil, fmt.Errorf("failed to copy record for domain %d: %v", r.domainID, err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return nil, fmt.Errorf("failed to copy records: %v", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, err
+	}
+
+	// Same rule as the query worker: one TTL per RRset compared with the most
+	// recent stored observation from the same source, flagged when it changed
+	// by at least the ratio
+	if _, err := tx.Exec(`
+		INSERT INTO ttl_anomalies (domain_id, record_type, old_ttl, new_ttl, observed_at)
+		SELECT s.domain_id, s.record_type, old.ttl, s.ttl, s.last_updated
+		FROM (
+			SELECT DISTINCT ON (domain_id, record_type, source) domain_id, record_type, source, ttl, last_updated
+			FROM ingest_staging
+			WHERE source = 'QUERY'
+			ORDER BY domain_id, record_type, source, last_updated DESC
+		) s
+		JOIN LATERAL (
+			SELECT ttl FROM dns_records r
+			WHERE r.domain_id = s.domain_id AND r.record_type = s.record_type AND r.source = s.source
+			ORDER BY r.last_updated DESC
+			LIMIT 1
+		) old ON TRUE
+		WHERE LEAST(old.ttl, s.ttl) > 0
+		  AND GREATEST(old.ttl, s.ttl)::float8 / LEAST(old.ttl, s.ttl) >= $1
+	`, b.ttlAnomalyRatio); err != nil {
+		return nil, fmt.Errorf("failed to record TTL anomalies: %v", err)
+	}
+	rows, err := tx.Query(`
+		WITH upserted AS (
+			INSERT INTO dns_records (domain_id, record_type, record_data, record_data_z, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+			SELECT domain_id, record_type, record_data, record_data_z, canonical_hash, ttl, source, last_updated, last_updated, priority, weight FROM ingest_staging
+			` + recordset.OnRecordConflict + `
+			RETURNING domain_id, record_type, source, xmax = 0 AS inserted
+		)
+		SELECT DISTINCT d.domain_name, d.tld, u.record_type
+		FROM upserted u JOIN domains d ON d.id = u.domain_id
+		WHERE u.inserted AND u.source <> 'QUERY'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert records: %v", err)
+	}
+	var changes []events.Event
+	for rows.Next() {
+		e := events.Event{Source: events.SourceIngest, Kind: events.RecordSetChanged, Significance: recordset.SignificanceUnclassified}
+		if err := rows.Scan(&e.Domain, &e.TLD, &e.RecordType); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to insert records: %v", err)
+		}
+		changes = append(changes, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to insert records: %v", err)
+	}
+	if _, err := tx.Exec(`
+		UPDATE domains d
+		SET last_updated = s.last_updated
+		FROM (SELECT domain_id, MAX(last_updated) AS last_updated FROM ingest_staging GROUP BY domain_id) s
+		WHERE d.id = s.domain_id
+	`); err != nil {
+		return nil, fmt.Errorf("failed to update domains: %v", err)
+	}
+	return changes, tx.Commit()
+}
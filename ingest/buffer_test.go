@@ -0,0 +1,103 @@
+package ingest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/moos3/bell/fixtures"
+	"github.com/moos3/bell/storage"
+)
+
+func TestPutKeepsLatestObservation(t *testing.T) {
+	shard := &storage.Shard{Name: storage.DefaultShard}
+	b := &writeBuffer{maxBuffered: 10, flushSize: 10, pending: make(map[recordKey]bufferedRecord), flushCh: make(chan struct{}, 1)}
+	now := time.Now()
+	newer := bufferedRecord{shard: shard, domainID: 1, recordType: "A", recordData: "192.0.2.1", ttl: 60, source: "QUERY", observedAt: now}
+	older := newer
+	older.ttl, older.observedAt = 300, now.Add(-time.Minute)
+
+	b.add([]bufferedRecord{newer})
+	// A record put back after a failed write must not replace a later
+	// observation buffered meanwhile
+	if buffered, ok := b.put([]bufferedRecord{older}, false); !ok || buffered != 1 {
+		t.Fatalf("put = %d, %t; want 1, true", buffered, ok)
+	}
+	if got := b.take(); len(got) != 1 || got[0].ttl != 60 {
+		t.Errorf("buffered %+v, want the record observed last", got)
+	}
+}
+
+func TestAddRejectsOverflow(t *testing.T) {
+	shard := &storage.Shard{Name: storage.DefaultShard}
+	b := &writeBuffer{maxBuffered: 2, flushSize: 2, pending: make(map[recordKey]bufferedRecord), flushCh: make(chan struct{}, 1)}
+	record := func(i int) bufferedRecord {
+		return bufferedRecord{shard: shard, domainID: int32(i), recordType: "A", recordData: fmt.Sprintf("192.0.2.%d", i), source: "QUERY", observedAt: time.Now()}
+	}
+	if _, ok := b.add([]bufferedRecord{record(1), record(2)}); !ok {
+		t.Fatal("add rejected records within maxBuffered")
+	}
+	select {
+	case <-b.flushCh:
+	default:
+		t.Error("reaching flushSize did not signal a flush")
+	}
+	if buffered, ok := b.add([]bufferedRecord{record(2), record(3)}); ok || buffered != 2 {
+		t.Errorf("add = %d, %t; want 2, false", buffered, ok)
+	}
+	// A duplicate replaces what is buffered without taking room
+	if _, ok := b.add([]bufferedRecord{record(1)}); !ok {
+		t.Error("add rejected a buffered duplicate")
+	}
+}
+
+func TestFlushIsolatesFailingRecord(t *testing.T) {
+	db := fixtures.OpenDatabase(t)
+	zone := fixtures.Generate(fixtures.Options{Seed: 3, DomainsPerTLD: 20}).Zones[0]
+	if err := zone.Store(db, "CZDS"); err != nil {
+		t.Fatal(err)
+	}
+	router := storage.NewSingleRouter(db)
+	shard := router.Shards()[0]
+	b := newWriteBuffer(router, 8, 1000, 0, 2, 2, 0)
+
+	var records []bufferedRecord
+	rows, err := db.Query("SELECT id FROM domains ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, bufferedRecord{shard: shard, domainID: id, recordType: "A", recordData: "192.0.2.1",
+			ttl: 300, source: "QUERY", observedAt: time.Now()})
+	}
+	rows.Close()
+	// No such domain, so the foreign key fails the batch it is written in
+	bad := bufferedRecord{shard: shard, domainID: 1 << 30, recordType: "A", recordData: "192.0.2.2", ttl: 300, source: "QUERY", observedAt: time.Now()}
+	if _, ok := b.add(append(records, bad)); !ok {
+		t.Fatal("add rejected records")
+	}
+
+	b.flushAll(nil)
+	var stored int
+	if err := db.QueryRow("SELECT COUNT(*) FROM dns_records WHERE source = 'QUERY'").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != len(records) {
+		t.Errorf("stored %d records next to the failing one, want %d", stored, len(records))
+	}
+	if pending := b.take(); len(pending) != 1 || pending[0].domainID != bad.domainID || pending[0].attempts != 1 {
+		t.Fatalf("kept %+v for the next flush, want the failing record after one attempt", pending)
+	} else {
+		b.put(pending, false)
+	}
+
+	// maxAttempts is 2, so the second failure drops it
+	b.flushAll(nil)
+	if pending := b.take(); len(pending) != 0 {
+		t.Errorf("kept %+v after maxAttempts failures", pending)
+	}
+}
@@ -0,0 +1,202 @@
+// Package ingest implements the internal write-behind ingestion service.
+//
+// Workers send record batches over gRPC (IngestService.WriteRecords),
+// authenticated by ingest.token or a client certificate (see serverOptions); the
+// service coalesces and deduplicates them in memory and writes them to the
+// shard of each record's TLD with COPY from a single writer, throttled to a
+// configurable rate and held back while the shard shows load (see package
+// pressure).
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/logging"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/pressure"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+// logger logs the ingest service's messages (see the logging package).
+var logger = logging.New("ingest")
+
+// server implements IngestService on top of a writeBuffer.
+type server struct {
+	pb.UnimplementedIngestServiceServer
+	buffer     *writeBuffer
+	retryAfter time.Duration // Suggested backoff when the buffer is full: one flush interval
+}
+
+// statusError returns a status error carrying an ErrorInfo with reason and
+// metadata, matching the errors of the public API server.
+func statusError(code codes.Code, reason string, metadata map[string]string, format string, args ...interface{}) error {
+	st := status.Newf(code, format, args...)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: "bell", Metadata: metadata}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+// WriteRecords buffers a batch of records. It returns ResourceExhausted
+// (reason BUFFER_FULL, with retry_after and quota_limit metadata) when the
+// buffer is full so workers back off instead of growing memory unbounded.
+func (s *server) WriteRecords(ctx context.Context, req *pb.WriteRecordsRequest) (*pb.WriteRecordsResponse, error) {
+	received := time.Now().UTC()
+	records := make([]bufferedRecord, 0, len(req.Records))
+	for _, r := range req.Records {
+		if r.DomainId <= 0 || r.RecordType == "" || r.Source == "" {
+			return nil, statusError(codes.InvalidArgument, "INVALID_RECORD", nil, "records require domain_id, record_type, and source")
+		}
+		observedAt := received
+		if r.ObservedAt != "" {
+			t, err := time.Parse(time.RFC3339, r.ObservedAt)
+			if err != nil {
+				return nil, statusError(codes.InvalidArgument, "INVALID_RECORD", map[string]string{"field": "observed_at"}, "invalid observed_at %q: %v", r.ObservedAt, err)
+			}
+			observedAt = t.UTC()
+		}
+		records = append(records, bufferedRecord{
+			shard:      s.buffer.shards.ForTLD(r.Tld),
+			domainID:   r.DomainId,
+			recordType: r.RecordType,
+			recordData: recordset.Normalize(r.RecordData),
+			ttl:        r.Ttl,
+			source:     r.Source,
+			observedAt: observedAt,
+		})
+	}
+	missing, err := unknownDomain(ctx, records)
+	if err != nil {
+		return nil, statusError(codes.Unavailable, "SHARD_UNAVAILABLE", nil, "failed to check domain IDs: %v", err)
+	}
+	if missing != 0 {
+		return nil, statusError(codes.InvalidArgument, "UNKNOWN_DOMAIN", map[string]string{"domain_id": strconv.Itoa(int(missing))},
+			"no domain %d on the shard of its TLD", missing)
+	}
+	buffered, ok := s.buffer.add(records)
+	if !ok {
+		return nil, statusError(codes.ResourceExhausted, "BUFFER_FULL", map[string]string{
+			"retry_after": s.retryAfter.String(),
+			"quota_limit": strconv.Itoa(s.buffer.maxBuffered),
+		}, "ingest buffer full (%d records); retry later", buffered)
+	}
+	return &pb.WriteRecordsResponse{Accepted: int32(len(records)), Buffered: int32(buffered)}, nil
+}
+
+// unknownDomain returns the first domain ID of records missing from the
+// domains table of its record's shard, or 0 if all exist, so that a record
+// the foreign key would reject is refused here instead of failing its batch.
+func unknownDomain(ctx context.Context, records []bufferedRecord) (int32, error) {
+	byShard := make(map[*storage.Shard][]int64)
+	for _, r := range records {
+		byShard[r.shard] = append(byShard[r.shard], int64(r.domainID))
+	}
+	for shard, ids := range byShard {
+		var missing sql.NullInt64
+		err := shard.DB.QueryRowContext(ctx, `
+			SELECT MIN(u.id) FROM unnest($1::bigint[]) AS u(id)
+			WHERE NOT EXISTS (SELECT 1 FROM domains d WHERE d.id = u.id)
+		`, pq.Array(ids)).Scan(&missing)
+		if err != nil {
+			return 0, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		if missing.Valid {
+			return int32(missing.Int64), nil
+		}
+	}
+	return 0, nil
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Connect to AlloyDB
+	connStr := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode,
+	)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	buffer := newWriteBuffer(shards, config.Ingest.FlushSize, config.Ingest.MaxBuffered, config.Ingest.MaxRowsPerSecond, config.Ingest.MaxWriteAttempts, config.DNSQuery.TTLAnomalyRatio, config.RecordData.CompressMinBytes)
+	buffer.pressure = pressure.New(shards, events.SourceIngest, config)
+	if buffer.pressure.Enabled() {
+		logger.Infof("Holding commits while the database is under pressure (checked every %dms, at most %ds)",
+			config.Ingest.Pressure.CheckIntervalMs, config.Ingest.Pressure.MaxPauseSeconds)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		buffer.run(time.Duration(config.Ingest.FlushIntervalMs)*time.Millisecond, stop)
+		close(done)
+	}()
+
+	opts, err := serverOptions(config)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterIngestServiceServer(grpcServer, &server{buffer: buffer, retryAfter: time.Duration(config.Ingest.FlushIntervalMs) * time.Millisecond})
+	lis, err := net.Listen("tcp", config.Ingest.ListenAddress)
+	if err != nil {
+		logger.Fatalf("Failed to listen on %s: %v", config.Ingest.ListenAddress, err)
+	}
+
+	// Flush buffered records before exiting
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		logger.Infof("Shutting down; flushing buffered records")
+		grpcServer.GracefulStop()
+	}()
+
+	logger.Infof("Ingest service listening on %s (TLS: %t, client certificates required: %t, token required: %t)", config.Ingest.ListenAddress,
+		config.Ingest.TLS.CertFile != "", config.Ingest.TLS.ClientCAFile != "", config.Ingest.Token != "")
+	if err := grpcServer.Serve(lis); err != nil {
+		logger.Fatalf("Failed to serve gRPC: %v", err)
+	}
+	close(stop)
+	<-done
+}
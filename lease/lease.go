@@ -0,0 +1,420 @@
+// Package lease tracks the work items held by workers in work_leases: TLD
+// ingests by the CZDS importer and domain batches by the query worker.
+//
+// A worker acquires a lease before starting an item and heartbeats it while
+// it works. If the worker crashes or hangs, the lease expires and the reaper
+// (run by the API server) re-queues the item. Items that fail or expire
+// alert_after_failures times in a row are alerted on. Counters are published
+// through expvar as "leases".
+package lease
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/storage"
+)
+
+// Work item kinds.
+const (
+	KindTLD         = "tld"          // Item is the TLD being ingested
+	KindDomainBatch = "domain_batch" // Item is "<shard>:<first>-<last>" domain IDs of a query batch (see DomainBatch)
+)
+
+// DomainBatch returns the item of a query batch of the domains first to last
+// on shard.
+func DomainBatch(shard string, first, last int) string {
+	return fmt.Sprintf("%s:%d-%d", shard, first, last)
+}
+
+// ErrHeld is returned by Acquire when another worker holds a live lease on
+// the item.
+var ErrHeld = errors.New("lease held by another worker")
+
+// metrics counts lease activity in this process.
+var metrics = expvar.NewMap("leases")
+
+// owner identifies this worker process in work_leases.
+var owner = func() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return host + ":" + strconv.Itoa(os.Getpid())
+}()
+
+// Manager acquires leases and reaps expired ones.
+type Manager struct {
+	db         *sql.DB // Default database holding work_leases
+	shards     *storage.Router
+	ttl        time.Duration
+	alertAfter int
+	webhookURL string
+}
+
+// NewManager returns a Manager using the leases config block. Leases are
+// kept on the default database; domain batches are re-queued on their shard.
+func NewManager(shards *storage.Router, cfg *config.Config) *Manager {
+	return &Manager{
+		db:         shards.Default(),
+		shards:     shards,
+		ttl:        time.Duration(cfg.Leases.TTLSeconds) * time.Second,
+		alertAfter: cfg.Leases.AlertAfterFailures,
+		webhookURL: cfg.Leases.AlertWebhookURL,
+	}
+}
+
+// Lease is a held lease on one work item.
+type Lease struct {
+	m          *Manager
+	kind, item string
+	cancel     context.CancelFunc // Cancels the context returned by Acquire
+	stop       context.CancelFunc // Stops heartbeats
+	done       chan struct{}      // Closed once heartbeats have stopped
+}
+
+// Acquire takes the lease on a work item and heartbeats it until Release.
+// An expired lease is taken over and counted as a failure of the item. The
+// returned context is cancelled with ctx or when the lease is lost, i.e.
+// reaped after missed heartbeats; the caller should then stop working on the
+// item, which has been re-queued.
+func (m *Manager) Acquire(ctx context.Context, kind, item string) (*Lease, context.Context, error) {
+	now := time.Now().UTC()
+	res, err := m.db.ExecContext(ctx, `
+		INSERT INTO work_leases (kind, item, owner, state, acquired_at, heartbeat_at, expires_at)
+		VALUES ($1, $2, $3, 'RUNNING', $4, $4, $5)
+		ON CONFLICT (kind, item) DO UPDATE
+		SET owner = EXCLUDED.owner, state = 'RUNNING', acquired_at = EXCLUDED.acquired_at,
+		    heartbeat_at = EXCLUDED.heartbeat_at, expires_at = EXCLUDED.expires_at,
+		    failures = work_leases.failures + CASE WHEN work_leases.state = 'RUNNING' THEN 1 ELSE 0 END
+		WHERE work_leases.state <> 'RUNNING' OR work_leases.expires_at < EXCLUDED.acquired_at
+	`, kind, item, owner, now, now.Add(m.ttl))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire lease on %s %s: %v", kind, item, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, nil, fmt.Errorf("%s %s: %w", kind, item, ErrHeld)
+	}
+	metrics.Add("acquired", 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	hbCtx, stop := context.WithCancel(context.Background())
+	l := &Lease{m: m, kind: kind, item: item, cancel: cancel, stop: stop, done: make(chan struct{})}
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(m.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hbCtx.Done():
+				return
+			case <-ticker.C:
+				if held, err := l.heartbeat(hbCtx); err != nil {
+					log.Printf("Failed to heartbeat lease on %s %s: %v", kind, item, err)
+				} else if !held {
+					log.Printf("Lost lease on %s %s; stopping work on it", kind, item)
+					metrics.Add("lost", 1)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return l, ctx, nil
+}
+
+// heartbeat extends the lease, reporting false if it is no longer held.
+func (l *Lease) heartbeat(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	res, err := l.m.db.ExecContext(ctx, `
+		UPDATE work_leases
+		SET heartbeat_at = $4, expires_at = $5
+		WHERE kind = $1 AND item = $2 AND owner = $3 AND state = 'RUNNING'
+	`, l.kind, l.item, owner, now, now.Add(l.m.ttl))
+	if err != nil {
+		return true, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return true, err
+	}
+	metrics.Add("heartbeats", 1)
+	return n > 0, nil
+}
+
+// Release stops heartbeats and records the outcome of the item: DONE if
+// workErr is nil, INTERRUPTED if it is a context cancellation (shutdown or a
+// lost lease), and FAILED otherwise. Failures count towards alerting; success
+// resets the count. A lease lost to the reaper is left as the reaper set it.
+func (l *Lease) Release(workErr error) {
+	l.stop()
+	<-l.done
+	defer l.cancel()
+	now := time.Now().UTC()
+	var err error
+	switch {
+	case workErr == nil:
+		_, err = l.m.db.Exec(`
+			UPDATE work_leases
+			SET state = 'DONE', heartbeat_at = $4, failures = 0, last_error = NULL
+			WHERE kind = $1 AND item = $2 AND owner = $3 AND state = 'RUNNING'
+		`, l.kind, l.item, owner, now)
+		metrics.Add("released", 1)
+	case errors.Is(workErr, context.Canceled):
+		_, err = l.m.db.Exec(`
+			UPDATE work_leases
+			SET state = 'INTERRUPTED', heartbeat_at = $4
+			WHERE kind = $1 AND item = $2 AND owner = $3 AND state = 'RUNNING'
+		`, l.kind, l.item, owner, now)
+		metrics.Add("interrupted", 1)
+	default:
+		var failures int
+		err = l.m.db.QueryRow(`
+			UPDATE work_leases
+			SET state = 'FAILED', heartbeat_at = $4, failures = failures + 1, last_error = $5
+			WHERE kind = $1 AND item = $2 AND owner = $3 AND state = 'RUNNING'
+			RETURNING failures
+		`, l.kind, l.item, owner, now, workErr.Error()).Scan(&failures)
+		if err == sql.ErrNoRows {
+			err = nil
+		} else if err == nil {
+			metrics.Add("failed", 1)
+			l.m.alertIfRepeated(l.kind, l.item, failures, workErr.Error())
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to release lease on %s %s: %v", l.kind, l.item, err)
+	}
+}
+
+// Reap marks every RUNNING lease past its expiry as EXPIRED and re-queues
+// its item, one transaction per lease. It returns the number of leases
+// reaped.
+func (m *Manager) Reap(ctx context.Context) (int, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT kind, item FROM work_leases
+		WHERE state = 'RUNNING' AND expires_at < $1
+	`, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to query expired leases: %v", err)
+	}
+	type expired struct{ kind, item string }
+	var leases []expired
+	for rows.Next() {
+		var e expired
+		if err := rows.Scan(&e.kind, &e.item); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan expired lease: %v", err)
+		}
+		leases = append(leases, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate expired leases: %v", err)
+	}
+
+	reaped := 0
+	for _, e := range leases {
+		ok, err := m.reap(ctx, e.kind, e.item)
+		if err != nil {
+			return reaped, fmt.Errorf("failed to reap lease on %s %s: %v", e.kind, e.item, err)
+		}
+		if ok {
+			reaped++
+		}
+	}
+	return reaped, nil
+}
+
+// reap expires one lease and re-queues its item, reporting false if the
+// lease was heartbeated or released in the meantime.
+func (m *Manager) reap(ctx context.Context, kind, item string) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	var holder string
+	var heartbeat time.Time
+	var failures int
+	err = tx.QueryRowContext(ctx, `
+		SELECT owner, heartbeat_at, failures + 1 FROM work_leases
+		WHERE kind = $1 AND item = $2 AND state = 'RUNNING' AND expires_at < $3
+		FOR UPDATE
+	`, kind, item, now).Scan(&holder, &heartbeat, &failures)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	reason := fmt.Sprintf("lease expired: no heartbeat from %s since %s", holder, heartbeat.Format(time.RFC3339))
+	_, err = tx.ExecContext(ctx, `
+		UPDATE work_leases
+		SET state = 'EXPIRED', failures = $3, last_error = $4
+		WHERE kind = $1 AND item = $2
+	`, kind, item, failures, reason)
+	if err != nil {
+		return false, err
+	}
+	if err := m.requeue(ctx, tx, kind, item, reason); err != nil {
+		return false, fmt.Errorf("failed to re-queue: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	metrics.Add("expired", 1)
+	log.Printf("Re-queued %s %s: %s", kind, item, reason)
+	events.Publish(m.db, events.Event{Source: source(kind), Kind: events.LeaseExpired, TLD: tldOf(kind, item), Message: kind + " " + item + ": " + reason})
+	m.alertIfRepeated(kind, item, failures, reason)
+	return true, nil
+}
+
+// requeue makes an item's worker pick it up again. A TLD is marked FAILED
+// without touching last_processed, so the next CZDS run ingests it; the query
+// worker's progress on the batch's shard is rewound to just before a domain
+// batch. Progress on another shard is rewound outside tx; rewinding twice is
+// harmless if tx then fails.
+func (m *Manager) requeue(ctx context.Context, tx *sql.Tx, kind, item, reason string) error {
+	switch kind {
+	case KindTLD:
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO processed_tlds (tld, last_attempted, last_status, last_error)
+			VALUES ($1, $2, 'FAILED', $3)
+			ON CONFLICT (tld) DO UPDATE
+			SET last_status = EXCLUDED.last_status, last_error = EXCLUDED.last_error
+		`, item, time.Now().UTC(), reason)
+		return err
+	case KindDomainBatch:
+		// Items from before sharding have no shard and are on the default one
+		shardName, ids, ok := strings.Cut(item, ":")
+		if !ok {
+			shardName, ids = storage.DefaultShard, item
+		}
+		first, _, ok := strings.Cut(ids, "-")
+		firstID, err := strconv.Atoi(first)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid domain batch %q", item)
+		}
+		// last_domain_id references domains, so rewind to the last existing
+		// domain before the batch (NULL to start over)
+		const rewind = `
+			UPDATE query_progress
+			SET last_domain_id = (SELECT MAX(id) FROM domains WHERE id < $1), updated_at = $2
+			WHERE id = 1 AND last_domain_id >= $1
+		`
+		if shardName == storage.DefaultShard {
+			_, err = tx.ExecContext(ctx, rewind, firstID, time.Now().UTC())
+			return err
+		}
+		shard := m.shard(shardName)
+		if shard == nil {
+			return fmt.Errorf("domain batch %q is on unknown shard %s", item, shardName)
+		}
+		_, err = shard.DB.ExecContext(ctx, rewind, firstID, time.Now().UTC())
+		return err
+	}
+	return fmt.Errorf("unknown work item kind %q", kind)
+}
+
+// shard returns the shard called name, or nil if there is none.
+func (m *Manager) shard(name string) *storage.Shard {
+	for _, shard := range m.shards.Shards() {
+		if shard.Name == name {
+			return shard
+		}
+	}
+	return nil
+}
+
+// RunReaper reaps expired leases on interval until ctx is cancelled.
+func (m *Manager) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Reap(ctx); err != nil {
+				log.Printf("Lease reaper: %v", err)
+			}
+		}
+	}
+}
+
+// Alert is the webhook payload for an item failing repeatedly.
+type Alert struct {
+	Time      string `json:"time"` // RFC 3339
+	Kind      string `json:"kind"`
+	Item      string `json:"item"`
+	Failures  int    `json:"failures"` // Consecutive failed or expired runs
+	LastError string `json:"last_error"`
+}
+
+// alertIfRepeated logs, publishes and delivers an alert once an item has
+// failed alertAfter times in a row, and on every failure after that.
+func (m *Manager) alertIfRepeated(kind, item string, failures int, lastError string) {
+	if failures < m.alertAfter {
+		return
+	}
+	metrics.Add("alerts", 1)
+	log.Printf("ALERT: %s %s failed %d times in a row: %s", kind, item, failures, lastError)
+	events.Publish(m.db, events.Event{Source: source(kind), Kind: events.Alert, TLD: tldOf(kind, item), Count: int64(failures),
+		Message: fmt.Sprintf("%s %s failed %d times in a row: %s", kind, item, failures, lastError)})
+	if m.webhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(Alert{Time: time.Now().UTC().Format(time.RFC3339), Kind: kind, Item: item, Failures: failures, LastError: lastError})
+	if err != nil {
+		log.Printf("Failed to encode alert for %s %s: %v", kind, item, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to create alert request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("Failed to deliver alert for %s %s: %v", kind, item, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Alert webhook rejected alert for %s %s: %s", kind, item, resp.Status)
+	}
+}
+
+// source returns the events source of the worker owning kind.
+func source(kind string) string {
+	if kind == KindTLD {
+		return events.SourceCZDS
+	}
+	return events.SourceQuery
+}
+
+// tldOf returns the TLD an item belongs to, if it is a TLD.
+func tldOf(kind, item string) string {
+	if kind == KindTLD {
+		return item
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+#!/usr/bin/env bash
+# Load test for GetRecords over gRPC (ghz) and the REST gateway (vegeta),
+# checked against the objectives in slo.env. Exits non-zero if any is
+# missed, so it can gate a release.
+#
+# Run it against a staging server or the sandbox, never production: with a
+# sandbox key the default domain list is the built-in sandbox dataset, and
+# a larger one loaded with "sandbox -generate" can be listed with
+#   psql -At -c "SELECT domain_name FROM domains ORDER BY random() LIMIT 1000" > domains.txt
+#
+# Environment:
+#   API_KEY       key to call with (required)
+#   GRPC_TARGET   gRPC address (default localhost:50051)
+#   HTTP_TARGET   REST gateway URL (default http://localhost:8080)
+#   DOMAINS_FILE  domains to request, one per line (default: sandbox dataset)
+#   RATE          requests per second per protocol (default 200)
+#   DURATION      length of each run, e.g. 60s (default 60s)
+#   CONCURRENCY   ghz workers (default 50)
+#
+# Requires ghz, vegeta, jq and buf on PATH.
+set -euo pipefail
+
+cd "$(dirname "$0")/.."
+source loadtest/slo.env
+
+: "${API_KEY:?API_KEY is required}"
+GRPC_TARGET=${GRPC_TARGET:-localhost:50051}
+HTTP_TARGET=${HTTP_TARGET:-http://localhost:8080}
+RATE=${RATE:-200}
+DURATION=${DURATION:-60s}
+CONCURRENCY=${CONCURRENCY:-50}
+
+for tool in ghz vegeta jq buf; do
+  command -v "$tool" >/dev/null || { echo "$tool is required" >&2; exit 2; }
+done
+
+work=$(mktemp -d)
+trap 'rm -rf "$work"' EXIT
+
+if [[ -n "${DOMAINS_FILE:-}" ]]; then
+  grep -v '^\s*$' "$DOMAINS_FILE" > "$work/domains.txt"
+else
+  sed -n 's/^  - name: //p' sandbox/fixture.yaml > "$work/domains.txt"
+fi
+echo "Load testing GetRecords with $(wc -l < "$work/domains.txt") domains at $RATE req/s for $DURATION"
+
+# gRPC: ghz sends the request messages round-robin
+buf build -o "$work/bell.protoset"
+jq -R '{domain: .}' "$work/domains.txt" | jq -s . > "$work/requests.json"
+ghz --insecure \
+  --protoset "$work/bell.protoset" \
+  --call bell.v1.DNSService.GetRecords \
+  --metadata "{\"x-api-key\": \"$API_KEY\"}" \
+  --data-file "$work/requests.json" \
+  --rps "$RATE" --duration "$DURATION" --concurrency "$CONCURRENCY" \
+  --format json --output "$work/grpc.json" \
+  "$GRPC_TARGET"
+
+# REST: vegeta cycles through the targets
+while read -r domain; do
+  printf 'GET %s/v1/records/%s\nX-API-Key: %s\n\n' "$HTTP_TARGET" "$domain" "$API_KEY"
+done < "$work/domains.txt" > "$work/targets.txt"
+vegeta attack -targets "$work/targets.txt" -rate "$RATE" -duration "$DURATION" \
+  | vegeta report -type json > "$work/http.json"
+
+# Latencies are reported in nanoseconds by both tools
+grpc_p50=$(jq '[.latencyDistribution[] | select(.percentage == 50)][0].latency / 1e6' "$work/grpc.json")
+grpc_p99=$(jq '[.latencyDistribution[] | select(.percentage == 99)][0].latency / 1e6' "$work/grpc.json")
+grpc_errors=$(jq '(.count - (.statusCodeDistribution.OK // 0)) / ([.count, 1] | max)' "$work/grpc.json")
+http_p50=$(jq '.latencies["50th"] / 1e6' "$work/http.json")
+http_p99=$(jq '.latencies["99th"] / 1e6' "$work/http.json")
+http_errors=$(jq '1 - .success' "$work/http.json")
+
+failed=0
+check() { # name value limit unit
+  if awk -v v="$2" -v l="$3" 'BEGIN { exit !(v <= l) }'; then
+    printf '%-16s %10.3f %-3s (limit %s)  ok\n' "$1" "$2" "$4" "$3"
+  else
+    printf '%-16s %10.3f %-3s (limit %s)  MISSED\n' "$1" "$2" "$4" "$3"
+    failed=1
+  fi
+}
+check "gRPC p50" "$grpc_p50" "$GRPC_P50_MS" ms
+check "gRPC p99" "$grpc_p99" "$GRPC_P99_MS" ms
+check "gRPC errors" "$grpc_errors" "$MAX_ERROR_RATE" ""
+check "REST p50" "$http_p50" "$HTTP_P50_MS" ms
+check "REST p99" "$http_p99" "$HTTP_P99_MS" ms
+check "REST errors" "$http_errors" "$MAX_ERROR_RATE" ""
+
+if [[ $failed -ne 0 ]]; then
+  echo "Load test missed its objectives" >&2
+  exit 1
+fi
+echo "Load test met its objectives"
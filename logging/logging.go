@@ -0,0 +1,276 @@
+// Package logging writes bell's logs as leveled, structured records through
+// log/slog, as key=value text or one JSON object per line.
+//
+// Each package logs through a Logger named for its module ("server",
+// "czds", "query"), which records the module with every message and has
+// its own level: logging.modules sets it, falling back to logging.level.
+// Setup also routes the standard log package through the same handler at
+// the info level, so packages that still use it share the format.
+//
+// API keys are UUIDs. Every message and string attribute is scanned for
+// them and, unless logging.api_keys is full, they are replaced with a short
+// hash of the key (hash, the default), cut to their first 8 characters
+// (prefix), or hidden. Hashes and prefixes are the same on every line that
+// names a key, so its requests can still be followed through the log; a
+// key's hash is printed by APIKey.
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/moos3/bell/config"
+)
+
+// level is the level of modules without one of their own.
+var level = new(slog.LevelVar)
+
+// handler writes the records. Until Setup runs it is the default slog
+// handler, which writes through the standard log package.
+var handler atomic.Pointer[slog.Handler]
+
+// apiKeyPattern matches the UUIDs API keys are.
+var apiKeyPattern = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+
+// apiKeys is logging.api_keys: hash, prefix, hidden or full.
+var apiKeys atomic.Value
+
+var (
+	mu      sync.Mutex
+	modules = make(map[string]*Logger)
+)
+
+func init() {
+	apiKeys.Store("hash")
+}
+
+// Setup configures logging from cfg: the output format, the API key
+// redaction, and the levels of all modules and of each module in
+// logging.modules. Logs go to standard error.
+func Setup(cfg *config.Config) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(cfg.Logging.Level)); err != nil {
+		return fmt.Errorf("invalid logging.level %s: %v", cfg.Logging.Level, err)
+	}
+	level.Set(l)
+	for module, name := range cfg.Logging.Modules {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(name)); err != nil {
+			return fmt.Errorf("invalid logging.modules level %s for %s: %v", name, module, err)
+		}
+		New(module).SetLevel(l)
+	}
+	apiKeys.Store(cfg.Logging.APIKeys)
+
+	// Loggers filter by their own level, so the handler passes everything
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: redactAttr}
+	var h slog.Handler
+	if cfg.Logging.Format == "json" {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		h = slog.NewTextHandler(os.Stderr, opts)
+	}
+	handler.Store(&h)
+	slog.SetDefault(slog.New(stdHandler{h}))
+	return nil
+}
+
+// Level returns the level of modules without one of their own.
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SetLevel sets the level of modules without one of their own.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Redact applies logging.api_keys to the API keys in s.
+func Redact(s string) string {
+	if apiKeys.Load() == "full" {
+		return s
+	}
+	return apiKeyPattern.ReplaceAllStringFunc(s, APIKey)
+}
+
+// APIKey returns key as logging.api_keys has it logged. Unlike Redact, it
+// takes key to be a key whatever it looks like, so it suits values such as
+// the x-api-key header that may hold malformed keys.
+func APIKey(key string) string {
+	switch apiKeys.Load() {
+	case "full":
+		return key
+	case "hidden":
+		return "[REDACTED]"
+	case "prefix":
+		if len(key) > 8 {
+			return key[:8] + "..."
+		}
+		return "[REDACTED]"
+	}
+	return KeyID(key)
+}
+
+// KeyID returns the short hash identifying key in logs under the default
+// logging.api_keys setting, whatever the setting is. It suits exports that
+// must tell keys apart without exposing them.
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:6])
+}
+
+// redactAttr applies Redact to string attributes.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindString {
+		a.Value = slog.StringValue(Redact(a.Value.String()))
+	}
+	return a
+}
+
+// stdHandler is the handler of the standard log package and of slog's
+// top-level functions once Setup has run. It applies the level of modules
+// without one of their own and redacts API keys in messages.
+type stdHandler struct {
+	slog.Handler
+}
+
+func (h stdHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+func (h stdHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Message = Redact(r.Message)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h stdHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return stdHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h stdHandler) WithGroup(name string) slog.Handler {
+	return stdHandler{h.Handler.WithGroup(name)}
+}
+
+// Logger logs the messages of a module.
+type Logger struct {
+	module string
+	level  atomic.Pointer[slog.Level] // nil follows the level of all modules
+}
+
+// New returns the Logger of module, creating it the first time.
+func New(module string) *Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	l, ok := modules[module]
+	if !ok {
+		l = &Logger{module: module}
+		modules[module] = l
+	}
+	return l
+}
+
+// Module returns the Logger of module, or nil if no package logs as module.
+func Module(module string) *Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return modules[module]
+}
+
+// Level returns the module's level.
+func (l *Logger) Level() slog.Level {
+	if v := l.level.Load(); v != nil {
+		return *v
+	}
+	return level.Level()
+}
+
+// SetLevel gives the module a level of its own.
+func (l *Logger) SetLevel(v slog.Level) {
+	l.level.Store(&v)
+}
+
+// ResetLevel makes the module follow the level of all modules again.
+func (l *Logger) ResetLevel() {
+	l.level.Store(nil)
+}
+
+// Override sets the module's level until the returned function restores
+// the previous one, for benchmarks that would otherwise log every
+// iteration.
+func (l *Logger) Override(v slog.Level) (restore func()) {
+	previous := l.level.Swap(&v)
+	return func() { l.level.Store(previous) }
+}
+
+// Enabled reports whether the module logs messages of level v.
+func (l *Logger) Enabled(v slog.Level) bool {
+	return v >= l.Level()
+}
+
+// Log logs msg at level v with attributes given as alternating keys and
+// values, as slog.Logger.Log takes them.
+func (l *Logger) Log(v slog.Level, msg string, args ...any) {
+	if !l.Enabled(v) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), v, Redact(msg), 0)
+	r.Add("module", l.module)
+	r.Add(args...)
+	l.handle(r)
+}
+
+// Debugf logs a formatted message at the debug level.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(slog.LevelDebug, format, args)
+}
+
+// Infof logs a formatted message at the info level.
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(slog.LevelInfo, format, args)
+}
+
+// Warnf logs a formatted message at the warn level.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.logf(slog.LevelWarn, format, args)
+}
+
+// Errorf logs a formatted message at the error level.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.logf(slog.LevelError, format, args)
+}
+
+// Fatalf logs a formatted message at the error level and exits with
+// status 1, whatever the module's level.
+func (l *Logger) Fatalf(format string, args ...any) {
+	r := slog.NewRecord(time.Now(), slog.LevelError, Redact(fmt.Sprintf(format, args...)), 0)
+	r.Add("module", l.module)
+	l.handle(r)
+	os.Exit(1)
+}
+
+func (l *Logger) logf(v slog.Level, format string, args []any) {
+	if !l.Enabled(v) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), v, Redact(fmt.Sprintf(format, args...)), 0)
+	r.Add("module", l.module)
+	l.handle(r)
+}
+
+func (l *Logger) handle(r slog.Record) {
+	h := slog.Default().Handler()
+	if p := handler.Load(); p != nil {
+		h = *p
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		fmt.Fprintf(os.Stderr, "Logging: %v\n", err)
+	}
+}
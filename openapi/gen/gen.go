@@ -0,0 +1,30 @@
+// Package gen converts the generated Swagger 2.0 description of the REST
+// gateway to the OpenAPI 3.0 document embedded by the openapi package.
+package gen
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/moos3/bell/openapi"
+)
+
+func main() {
+	in := flag.String("in", "openapi/bell.swagger.json", "Swagger 2.0 document generated by protoc-gen-openapiv2")
+	out := flag.String("out", "openapi/bell.openapi.json", "OpenAPI 3.0 document to write")
+	flag.Parse()
+
+	swagger, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := openapi.ConvertV2(swagger)
+	if err != nil {
+		log.Fatalf("Failed to convert %s: %v", *in, err)
+	}
+	if err := os.WriteFile(*out, append(doc, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote %s", *out)
+}
@@ -0,0 +1,39 @@
+// Package openapi holds the OpenAPI description of the DNSService REST
+// gateway.
+//
+// bell.swagger.json is generated from the protos by protoc-gen-openapiv2
+// (make proto), and bell.openapi.json is converted from it to OpenAPI 3.0 by
+// openapi/gen (make openapi). Both are derived from the google.api.http and
+// openapiv2 annotations in proto/bell/v1/bell.proto, so the documented
+// contract always matches the gateway. The REST SDKs are generated from
+// bell.openapi.json (make sdk).
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+)
+
+// Document is the OpenAPI 3.0 description of the REST gateway, served by
+// the server at /openapi.json.
+//
+//go:embed bell.openapi.json
+var Document []byte
+
+//go:embed swagger.html
+var swaggerHTML string
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(swaggerHTML))
+
+// SwaggerUI returns a Swagger UI page browsing the document served at
+// spec, with the swagger-ui-dist bundle and stylesheet loaded from the
+// assets base URL. Only the page is embedded, so the assets can come from a
+// CDN or, on networks without one, a copy the deployment serves itself.
+func SwaggerUI(assets, spec string) ([]byte, error) {
+	var page bytes.Buffer
+	if err := swaggerTemplate.Execute(&page, struct{ Assets, Spec string }{assets, spec}); err != nil {
+		return nil, err
+	}
+	return page.Bytes(), nil
+}
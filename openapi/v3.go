@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// v3Document is an OpenAPI 3.0 document; fields are ordered as they are
+// conventionally written.
+type v3Document struct {
+	OpenAPI    string                 `json:"openapi"`
+	Info       interface{}            `json:"info,omitempty"`
+	Tags       interface{}            `json:"tags,omitempty"`
+	Paths      map[string]interface{} `json:"paths"`
+	Components v3Components           `json:"components"`
+	Security   interface{}            `json:"security,omitempty"`
+}
+
+type v3Components struct {
+	Schemas         map[string]interface{} `json:"schemas,omitempty"`
+	SecuritySchemes map[string]interface{} `json:"securitySchemes,omitempty"`
+}
+
+// schemaKeys are the Swagger 2.0 parameter keys that move into a
+// parameter's schema in OpenAPI 3.0.
+var schemaKeys = []string{"type", "format", "items", "enum", "default", "example", "pattern", "minimum", "maximum"}
+
+// ConvertV2 converts a Swagger 2.0 document generated by protoc-gen-openapiv2
+// to OpenAPI 3.0.
+//
+// It covers the subset of Swagger that the generator emits: path, query,
+// and body parameters, JSON request and response bodies, definitions, and
+// API key security schemes.
+func ConvertV2(swagger []byte) ([]byte, error) {
+	var v2 map[string]interface{}
+	if err := json.Unmarshal(swagger, &v2); err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger document: %v", err)
+	}
+	if v2["swagger"] != "2.0" {
+		return nil, fmt.Errorf("unsupported Swagger version %v", v2["swagger"])
+	}
+	// Rewrite every #/definitions/ reference before restructuring
+	v2 = rewriteRefs(v2).(map[string]interface{})
+
+	consumes := mediaTypes(v2["consumes"])
+	produces := mediaTypes(v2["produces"])
+	doc := v3Document{
+		OpenAPI:  "3.0.3",
+		Info:     v2["info"],
+		Tags:     v2["tags"],
+		Paths:    make(map[string]interface{}),
+		Security: v2["security"],
+	}
+
+	paths, _ := v2["paths"].(map[string]interface{})
+	for path, item := range paths {
+		ops, _ := item.(map[string]interface{})
+		out := make(map[string]interface{})
+		for method, op := range ops {
+			operation, ok := op.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s %s: operation is not an object", method, path)
+			}
+			converted, err := convertOperation(operation, consumes, produces)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %v", method, path, err)
+			}
+			out[method] = converted
+		}
+		doc.Paths[path] = out
+	}
+
+	if defs, ok := v2["definitions"].(map[string]interface{}); ok {
+		doc.Components.Schemas = defs
+	}
+	if schemes, ok := v2["securityDefinitions"].(map[string]interface{}); ok {
+		doc.Components.SecuritySchemes = make(map[string]interface{})
+		for name, s := range schemes {
+			scheme, _ := s.(map[string]interface{})
+			if scheme["type"] != "apiKey" {
+				return nil, fmt.Errorf("security scheme %s: unsupported type %v", name, scheme["type"])
+			}
+			doc.Components.SecuritySchemes[name] = scheme
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// convertOperation moves an operation's parameter types into schemas, its
+// body parameter into requestBody, and its response schemas into content.
+func convertOperation(op map[string]interface{}, consumes, produces []string) (map[string]interface{}, error) {
+	if c := mediaTypes(op["consumes"]); len(c) > 0 {
+		consumes = c
+	}
+	if p := mediaTypes(op["produces"]); len(p) > 0 {
+		produces = p
+	}
+	delete(op, "consumes")
+	delete(op, "produces")
+
+	params, _ := op["parameters"].([]interface{})
+	var converted []interface{}
+	for _, p := range params {
+		param, _ := p.(map[string]interface{})
+		switch param["in"] {
+		case "body":
+			body := map[string]interface{}{
+				"content": content(consumes, param["schema"]),
+			}
+			if param["required"] == true {
+				body["required"] = true
+			}
+			if d, ok := param["description"]; ok {
+				body["description"] = d
+			}
+			op["requestBody"] = body
+		case "path", "query", "header":
+			schema := make(map[string]interface{})
+			for _, k := range schemaKeys {
+				if v, ok := param[k]; ok {
+					schema[k] = v
+					delete(param, k)
+				}
+			}
+			if param["collectionFormat"] == "multi" {
+				param["style"] = "form"
+				param["explode"] = true
+			}
+			delete(param, "collectionFormat")
+			param["schema"] = schema
+			converted = append(converted, param)
+		default:
+			return nil, fmt.Errorf("unsupported parameter location %v", param["in"])
+		}
+	}
+	if converted != nil {
+		op["parameters"] = converted
+	} else {
+		delete(op, "parameters")
+	}
+
+	responses, _ := op["responses"].(map[string]interface{})
+	for code, r := range responses {
+		resp, _ := r.(map[string]interface{})
+		if schema, ok := resp["schema"]; ok {
+			resp["content"] = content(produces, schema)
+			delete(resp, "schema")
+		}
+		responses[code] = resp
+	}
+	return op, nil
+}
+
+// content builds an OpenAPI 3.0 content map with schema for each media type.
+func content(types []string, schema interface{}) map[string]interface{} {
+	if len(types) == 0 {
+		types = []string{"application/json"}
+	}
+	c := make(map[string]interface{}, len(types))
+	for _, t := range types {
+		c[t] = map[string]interface{}{"schema": schema}
+	}
+	return c
+}
+
+func mediaTypes(v interface{}) []string {
+	list, _ := v.([]interface{})
+	var types []string
+	for _, t := range list {
+		if s, ok := t.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types
+}
+
+// rewriteRefs points Swagger definition references at components/schemas.
+func rewriteRefs(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				v[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			v[k] = rewriteRefs(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = rewriteRefs(child)
+		}
+	}
+	return v
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.2
+// 	protoc-gen-go v1.36.6
 // 	protoc        (unknown)
 // source: bell/v1/bell.proto
 
@@ -8,11 +8,13 @@ package bellv1
 
 import (
 	_ "github.com/moos3/bell/pb/google/api"
+	_ "github.com/moos3/bell/pb/protoc-gen-openapiv2/options"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	_ "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -22,21 +24,119 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RecordOrder int32
+
+const (
+	RecordOrder_RECORD_ORDER_UNSPECIFIED RecordOrder = 0 // Same as RECORD_ORDER_SEMANTIC
+	RecordOrder_RECORD_ORDER_SEMANTIC    RecordOrder = 1 // SOA, NS, MX by preference, SRV by priority then weight (heaviest first), NAPTR by order, then other types alphabetically
+	RecordOrder_RECORD_ORDER_STORAGE     RecordOrder = 2 // Database order, cheapest for bulk readers
+)
+
+// Enum value maps for RecordOrder.
+var (
+	RecordOrder_name = map[int32]string{
+		0: "RECORD_ORDER_UNSPECIFIED",
+		1: "RECORD_ORDER_SEMANTIC",
+		2: "RECORD_ORDER_STORAGE",
+	}
+	RecordOrder_value = map[string]int32{
+		"RECORD_ORDER_UNSPECIFIED": 0,
+		"RECORD_ORDER_SEMANTIC":    1,
+		"RECORD_ORDER_STORAGE":     2,
+	}
+)
+
+func (x RecordOrder) Enum() *RecordOrder {
+	p := new(RecordOrder)
+	*p = x
+	return p
+}
+
+func (x RecordOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RecordOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_bell_v1_bell_proto_enumTypes[0].Descriptor()
+}
+
+func (RecordOrder) Type() protoreflect.EnumType {
+	return &file_bell_v1_bell_proto_enumTypes[0]
+}
+
+func (x RecordOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RecordOrder.Descriptor instead.
+func (RecordOrder) EnumDescriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{0}
+}
+
+type TopNMetric int32
+
+const (
+	TopNMetric_TOP_N_METRIC_UNSPECIFIED  TopNMetric = 0
+	TopNMetric_TOP_N_METRIC_NAMESERVERS  TopNMetric = 1 // Nameserver hosts by delegated domain count
+	TopNMetric_TOP_N_METRIC_MX_PROVIDERS TopNMetric = 2 // MX target registrable domains by domain count
+	TopNMetric_TOP_N_METRIC_ASNS         TopNMetric = 3 // Hosting autonomous systems by domain count (A/AAAA)
+)
+
+// Enum value maps for TopNMetric.
+var (
+	TopNMetric_name = map[int32]string{
+		0: "TOP_N_METRIC_UNSPECIFIED",
+		1: "TOP_N_METRIC_NAMESERVERS",
+		2: "TOP_N_METRIC_MX_PROVIDERS",
+		3: "TOP_N_METRIC_ASNS",
+	}
+	TopNMetric_value = map[string]int32{
+		"TOP_N_METRIC_UNSPECIFIED":  0,
+		"TOP_N_METRIC_NAMESERVERS":  1,
+		"TOP_N_METRIC_MX_PROVIDERS": 2,
+		"TOP_N_METRIC_ASNS":         3,
+	}
+)
+
+func (x TopNMetric) Enum() *TopNMetric {
+	p := new(TopNMetric)
+	*p = x
+	return p
+}
+
+func (x TopNMetric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TopNMetric) Descriptor() protoreflect.EnumDescriptor {
+	return file_bell_v1_bell_proto_enumTypes[1].Descriptor()
+}
+
+func (TopNMetric) Type() protoreflect.EnumType {
+	return &file_bell_v1_bell_proto_enumTypes[1]
+}
+
+func (x TopNMetric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TopNMetric.Descriptor instead.
+func (TopNMetric) EnumDescriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{1}
+}
+
 type AuthenticateRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	ApiKey string `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AuthenticateRequest) Reset() {
 	*x = AuthenticateRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_bell_v1_bell_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_bell_v1_bell_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AuthenticateRequest) String() string {
@@ -47,7 +147,7 @@ func (*AuthenticateRequest) ProtoMessage() {}
 
 func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_bell_v1_bell_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -70,21 +170,18 @@ func (x *AuthenticateRequest) GetApiKey() string {
 }
 
 type AuthenticateResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Valid   bool   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AuthenticateResponse) Reset() {
 	*x = AuthenticateResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_bell_v1_bell_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_bell_v1_bell_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AuthenticateResponse) String() string {
@@ -95,7 +192,7 @@ func (*AuthenticateResponse) ProtoMessage() {}
 
 func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_bell_v1_bell_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -124,22 +221,180 @@ func (x *AuthenticateResponse) GetMessage() string {
 	return ""
 }
 
-type GetRecordsRequest struct {
-	state         protoimpl.MessageState
+type GetSigningKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSigningKeysRequest) Reset() {
+	*x = GetSigningKeysRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSigningKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSigningKeysRequest) ProtoMessage() {}
+
+func (x *GetSigningKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSigningKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetSigningKeysRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{2}
+}
+
+type SigningKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`                        // Named in signatures: first 16 hex digits of the SHA-256 of public_key
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`                             // Ed25519
+	PublicKey     string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`            // Base64, raw
+	PublicKeyPem  string                 `protobuf:"bytes,4,opt,name=public_key_pem,json=publicKeyPem,proto3" json:"public_key_pem,omitempty"` // PKIX
+	Active        bool                   `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`                                  // Signs new payloads; the others verify earlier signatures
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	Domain     string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	RecordType []string `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional filter (e.g., ["CNAME", "A"])
+func (x *SigningKey) Reset() {
+	*x = SigningKey{}
+	mi := &file_bell_v1_bell_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRecordsRequest) Reset() {
-	*x = GetRecordsRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_bell_v1_bell_proto_msgTypes[2]
+func (x *SigningKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningKey) ProtoMessage() {}
+
+func (x *SigningKey) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningKey.ProtoReflect.Descriptor instead.
+func (*SigningKey) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SigningKey) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SigningKey) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *SigningKey) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SigningKey) GetPublicKeyPem() string {
+	if x != nil {
+		return x.PublicKeyPem
+	}
+	return ""
+}
+
+func (x *SigningKey) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type GetSigningKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*SigningKey          `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"` // The active key first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSigningKeysResponse) Reset() {
+	*x = GetSigningKeysResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSigningKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSigningKeysResponse) ProtoMessage() {}
+
+func (x *GetSigningKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[4]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSigningKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetSigningKeysResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSigningKeysResponse) GetKeys() []*SigningKey {
+	if x != nil {
+		return x.Keys
 	}
+	return nil
+}
+
+type GetRecordsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Domain            string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType        []string               `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`                         // Optional filter (e.g., ["CNAME", "A"])
+	Merged            bool                   `protobuf:"varint,3,opt,name=merged,proto3" json:"merged,omitempty"`                                                  // Return one authoritative source per record type instead of all sources
+	SnapshotToken     string                 `protobuf:"bytes,4,opt,name=snapshot_token,json=snapshotToken,proto3" json:"snapshot_token,omitempty"`                // Optional token from an earlier response for a consistent view across calls
+	Order             RecordOrder            `protobuf:"varint,5,opt,name=order,proto3,enum=bell.v1.RecordOrder" json:"order,omitempty"`                           // Defaults to semantic ordering
+	KnownVersion      int64                  `protobuf:"varint,6,opt,name=known_version,json=knownVersion,proto3" json:"known_version,omitempty"`                  // Optional version from an earlier response; if the version is unchanged, records are left out
+	IncludeGeoAnswers bool                   `protobuf:"varint,7,opt,name=include_geo_answers,json=includeGeoAnswers,proto3" json:"include_geo_answers,omitempty"` // Also return the answers RefreshDomain stored per client subnet
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetRecordsRequest) Reset() {
+	*x = GetRecordsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetRecordsRequest) String() string {
@@ -149,8 +404,8 @@ func (x *GetRecordsRequest) String() string {
 func (*GetRecordsRequest) ProtoMessage() {}
 
 func (x *GetRecordsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bell_v1_bell_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_bell_v1_bell_proto_msgTypes[5]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -162,7 +417,7 @@ func (x *GetRecordsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRecordsRequest.ProtoReflect.Descriptor instead.
 func (*GetRecordsRequest) Descriptor() ([]byte, []int) {
-	return file_bell_v1_bell_proto_rawDescGZIP(), []int{2}
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetRecordsRequest) GetDomain() string {
@@ -179,26 +434,66 @@ func (x *GetRecordsRequest) GetRecordType() []string {
 	return nil
 }
 
-type DNSRecord struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *GetRecordsRequest) GetMerged() bool {
+	if x != nil {
+		return x.Merged
+	}
+	return false
+}
+
+func (x *GetRecordsRequest) GetSnapshotToken() string {
+	if x != nil {
+		return x.SnapshotToken
+	}
+	return ""
+}
+
+func (x *GetRecordsRequest) GetOrder() RecordOrder {
+	if x != nil {
+		return x.Order
+	}
+	return RecordOrder_RECORD_ORDER_UNSPECIFIED
+}
+
+func (x *GetRecordsRequest) GetKnownVersion() int64 {
+	if x != nil {
+		return x.KnownVersion
+	}
+	return 0
+}
+
+func (x *GetRecordsRequest) GetIncludeGeoAnswers() bool {
+	if x != nil {
+		return x.IncludeGeoAnswers
+	}
+	return false
+}
 
-	DomainId    int32  `protobuf:"varint,1,opt,name=domain_id,json=domainId,proto3" json:"domain_id,omitempty"`
-	RecordType  string `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
-	RecordData  string `protobuf:"bytes,3,opt,name=record_data,json=recordData,proto3" json:"record_data,omitempty"`
-	Ttl         int32  `protobuf:"varint,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	Source      string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
-	LastUpdated string `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+type DNSRecord struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DomainId    int32                  `protobuf:"varint,1,opt,name=domain_id,json=domainId,proto3" json:"domain_id,omitempty"`
+	RecordType  string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	RecordData  string                 `protobuf:"bytes,3,opt,name=record_data,json=recordData,proto3" json:"record_data,omitempty"`
+	Ttl         int32                  `protobuf:"varint,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Source      string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	LastUpdated string                 `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	Version     int64                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"` // Version of the record set (type and source) the record belongs to; 0 if it has none yet
+	// How often and when this record data has been observed for the domain,
+	// by any source, up to the snapshot; repeated sightings from several
+	// sources make a record more trustworthy than a single old one
+	ObservationCount int32    `protobuf:"varint,8,opt,name=observation_count,json=observationCount,proto3" json:"observation_count,omitempty"`
+	FirstSeen        string   `protobuf:"bytes,9,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // Earliest observation (RFC 3339)
+	LastSeen         string   `protobuf:"bytes,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`   // Latest observation (RFC 3339)
+	Sources          []string `protobuf:"bytes,11,rep,name=sources,proto3" json:"sources,omitempty"`                     // Distinct sources that observed it, e.g. ["CZDS", "QUERY"]
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *DNSRecord) Reset() {
 	*x = DNSRecord{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_bell_v1_bell_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_bell_v1_bell_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DNSRecord) String() string {
@@ -208,8 +503,8 @@ func (x *DNSRecord) String() string {
 func (*DNSRecord) ProtoMessage() {}
 
 func (x *DNSRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_bell_v1_bell_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_bell_v1_bell_proto_msgTypes[6]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -221,7 +516,7 @@ func (x *DNSRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DNSRecord.ProtoReflect.Descriptor instead.
 func (*DNSRecord) Descriptor() ([]byte, []int) {
-	return file_bell_v1_bell_proto_rawDescGZIP(), []int{3}
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DNSRecord) GetDomainId() int32 {
@@ -266,32 +561,68 @@ func (x *DNSRecord) GetLastUpdated() string {
 	return ""
 }
 
-type GetRecordsResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *DNSRecord) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DNSRecord) GetObservationCount() int32 {
+	if x != nil {
+		return x.ObservationCount
+	}
+	return 0
+}
 
-	Records []*DNSRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+func (x *DNSRecord) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
 }
 
-func (x *GetRecordsResponse) Reset() {
-	*x = GetRecordsResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_bell_v1_bell_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *DNSRecord) GetLastSeen() string {
+	if x != nil {
+		return x.LastSeen
 	}
+	return ""
 }
 
-func (x *GetRecordsResponse) String() string {
+func (x *DNSRecord) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type DGAScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entropy       float64                `protobuf:"fixed64,1,opt,name=entropy,proto3" json:"entropy,omitempty"`                         // Shannon entropy of the registrable label
+	NgramScore    float64                `protobuf:"fixed64,2,opt,name=ngram_score,json=ngramScore,proto3" json:"ngram_score,omitempty"` // Fraction of label bigrams found in the model
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`                             // Combined score (0-1), higher is more suspicious
+	LikelyDga     bool                   `protobuf:"varint,4,opt,name=likely_dga,json=likelyDga,proto3" json:"likely_dga,omitempty"`
+	ScoredAt      string                 `protobuf:"bytes,5,opt,name=scored_at,json=scoredAt,proto3" json:"scored_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DGAScore) Reset() {
+	*x = DGAScore{}
+	mi := &file_bell_v1_bell_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DGAScore) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRecordsResponse) ProtoMessage() {}
+func (*DGAScore) ProtoMessage() {}
 
-func (x *GetRecordsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bell_v1_bell_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *DGAScore) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[7]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -301,111 +632,12082 @@ func (x *GetRecordsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRecordsResponse.ProtoReflect.Descriptor instead.
-func (*GetRecordsResponse) Descriptor() ([]byte, []int) {
-	return file_bell_v1_bell_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DGAScore.ProtoReflect.Descriptor instead.
+func (*DGAScore) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetRecordsResponse) GetRecords() []*DNSRecord {
+func (x *DGAScore) GetEntropy() float64 {
 	if x != nil {
-		return x.Records
+		return x.Entropy
 	}
-	return nil
+	return 0
 }
 
-var File_bell_v1_bell_proto protoreflect.FileDescriptor
-
-var file_bell_v1_bell_proto_rawDesc = []byte{
-	0x0a, 0x12, 0x62, 0x65, 0x6c, 0x6c, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70,
-	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e, 0x0a, 0x13, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x17, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x22, 0x46, 0x0a, 0x14, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x4c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x1f, 0x0a,
-	0x0b, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x79, 0x70, 0x65, 0x22, 0xb7,
-	0x01, 0x0a, 0x09, 0x44, 0x4e, 0x53, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09,
-	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x08, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x74,
-	0x74, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x12, 0x16, 0x0a,
-	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x61, 0x73,
-	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x42, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c,
-	0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x4e, 0x53, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x32, 0xdb, 0x01, 0x0a,
-	0x0a, 0x44, 0x4e, 0x53, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x68, 0x0a, 0x0c, 0x41,
-	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x62, 0x65,
-	0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x65, 0x6c, 0x6c,
-	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15,
-	0x3a, 0x01, 0x2a, 0x22, 0x10, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
-	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x63, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x63, 0x6f,
-	0x72, 0x64, 0x73, 0x12, 0x1a, 0x2e, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
-	0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1b, 0x2e, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x82, 0xd3,
-	0xe4, 0x93, 0x02, 0x16, 0x12, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x73, 0x2f, 0x7b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x7d, 0x42, 0x7e, 0x0a, 0x0b, 0x63, 0x6f,
-	0x6d, 0x2e, 0x62, 0x65, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x42, 0x09, 0x42, 0x65, 0x6c, 0x6c, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x6d, 0x6f, 0x6f, 0x73, 0x33, 0x2f, 0x62, 0x65, 0x6c, 0x6c, 0x2f, 0x70, 0x62,
-	0x2f, 0x62, 0x65, 0x6c, 0x6c, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x65, 0x6c, 0x6c, 0x76, 0x31, 0xa2,
-	0x02, 0x03, 0x42, 0x58, 0x58, 0xaa, 0x02, 0x07, 0x42, 0x65, 0x6c, 0x6c, 0x2e, 0x56, 0x31, 0xca,
-	0x02, 0x07, 0x42, 0x65, 0x6c, 0x6c, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x13, 0x42, 0x65, 0x6c, 0x6c,
-	0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea,
-	0x02, 0x08, 0x42, 0x65, 0x6c, 0x6c, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+func (x *DGAScore) GetNgramScore() float64 {
+	if x != nil {
+		return x.NgramScore
+	}
+	return 0
 }
 
-var (
-	file_bell_v1_bell_proto_rawDescOnce sync.Once
-	file_bell_v1_bell_proto_rawDescData = file_bell_v1_bell_proto_rawDesc
-)
+func (x *DGAScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
 
-func file_bell_v1_bell_proto_rawDescGZIP() []byte {
-	file_bell_v1_bell_proto_rawDescOnce.Do(func() {
-		file_bell_v1_bell_proto_rawDescData = protoimpl.X.CompressGZIP(file_bell_v1_bell_proto_rawDescData)
+func (x *DGAScore) GetLikelyDga() bool {
+	if x != nil {
+		return x.LikelyDga
+	}
+	return false
+}
+
+func (x *DGAScore) GetScoredAt() string {
+	if x != nil {
+		return x.ScoredAt
+	}
+	return ""
+}
+
+type GetRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*DNSRecord           `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Dga           *DGAScore              `protobuf:"bytes,2,opt,name=dga,proto3" json:"dga,omitempty"`                                          // Unset if the dga job has not scored the domain yet
+	Provenance    []*MergeProvenance     `protobuf:"bytes,3,rep,name=provenance,proto3" json:"provenance,omitempty"`                            // Set when merged is requested
+	SnapshotToken string                 `protobuf:"bytes,4,opt,name=snapshot_token,json=snapshotToken,proto3" json:"snapshot_token,omitempty"` // Pass back in later requests to see the same snapshot
+	Truncated     bool                   `protobuf:"varint,5,opt,name=truncated,proto3" json:"truncated,omitempty"`                             // Records were cut to the key's max_rows preference
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                 // Highest version of the returned record sets; versions only increase as sets change
+	NotModified   bool                   `protobuf:"varint,7,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`      // version equals known_version; records, dga and provenance are left out
+	GeoAnswers    []*GeoAnswer           `protobuf:"bytes,8,rep,name=geo_answers,json=geoAnswers,proto3" json:"geo_answers,omitempty"`          // Set when include_geo_answers is requested
+	// Seconds since the least recently observed record returned was last
+	// observed; 0 without records. The X-Data-Freshness header (gRPC:
+	// x-data-freshness) gives it per source too, e.g. "age=86400; CZDS=86400;
+	// QUERY=3600"
+	DataAgeSeconds int64 `protobuf:"varint,9,opt,name=data_age_seconds,json=dataAgeSeconds,proto3" json:"data_age_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRecordsResponse) Reset() {
+	*x = GetRecordsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordsResponse) ProtoMessage() {}
+
+func (x *GetRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordsResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRecordsResponse) GetRecords() []*DNSRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *GetRecordsResponse) GetDga() *DGAScore {
+	if x != nil {
+		return x.Dga
+	}
+	return nil
+}
+
+func (x *GetRecordsResponse) GetProvenance() []*MergeProvenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *GetRecordsResponse) GetSnapshotToken() string {
+	if x != nil {
+		return x.SnapshotToken
+	}
+	return ""
+}
+
+func (x *GetRecordsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *GetRecordsResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetRecordsResponse) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
+	}
+	return false
+}
+
+func (x *GetRecordsResponse) GetGeoAnswers() []*GeoAnswer {
+	if x != nil {
+		return x.GeoAnswers
+	}
+	return nil
+}
+
+func (x *GetRecordsResponse) GetDataAgeSeconds() int64 {
+	if x != nil {
+		return x.DataAgeSeconds
+	}
+	return 0
+}
+
+type GetRecordHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Required
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                           // Optional filter (e.g., "QUERY")
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                            // Most recent values to return; defaults to and at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordHistoryRequest) Reset() {
+	*x = GetRecordHistoryRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordHistoryRequest) ProtoMessage() {}
+
+func (x *GetRecordHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRecordHistoryRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// RecordHistoryEntry is one value a record had over a period. A record
+// whose TTL or formatting changed has an entry per version.
+type RecordHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordData    string                 `protobuf:"bytes,1,opt,name=record_data,json=recordData,proto3" json:"record_data,omitempty"`
+	Ttl           int32                  `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,4,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // First observation of this value (RFC 3339)
+	LastSeen      string                 `protobuf:"bytes,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`    // Latest observation of this value (RFC 3339)
+	Superseded    bool                   `protobuf:"varint,6,opt,name=superseded,proto3" json:"superseded,omitempty"`               // Replaced in place by a later observation with another TTL or formatting
+	Current       bool                   `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`                     // Seen in the latest observation of the record type from its source
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordHistoryEntry) Reset() {
+	*x = RecordHistoryEntry{}
+	mi := &file_bell_v1_bell_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordHistoryEntry) ProtoMessage() {}
+
+func (x *RecordHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordHistoryEntry.ProtoReflect.Descriptor instead.
+func (*RecordHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RecordHistoryEntry) GetRecordData() string {
+	if x != nil {
+		return x.RecordData
+	}
+	return ""
+}
+
+func (x *RecordHistoryEntry) GetTtl() int32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *RecordHistoryEntry) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RecordHistoryEntry) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *RecordHistoryEntry) GetLastSeen() string {
+	if x != nil {
+		return x.LastSeen
+	}
+	return ""
+}
+
+func (x *RecordHistoryEntry) GetSuperseded() bool {
+	if x != nil {
+		return x.Superseded
+	}
+	return false
+}
+
+func (x *RecordHistoryEntry) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type GetRecordHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Entries       []*RecordHistoryEntry  `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`      // Oldest first
+	Truncated     bool                   `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"` // Older values were left out by limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordHistoryResponse) Reset() {
+	*x = GetRecordHistoryResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordHistoryResponse) ProtoMessage() {}
+
+func (x *GetRecordHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetRecordHistoryResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryResponse) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryResponse) GetEntries() []*RecordHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetRecordHistoryResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type GetRecordsBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 500, counting the domains patterns expand to. Domains holding
+	// * (any run of characters) or ? (any one), such as *.example.com or
+	// login.*.bank, are patterns, expanded to the stored domains they match;
+	// like SearchDomains patterns they need 3 consecutive other characters.
+	Domains       []string    `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	RecordType    []string    `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`          // Optional filter (e.g., ["CNAME", "A"])
+	SnapshotToken string      `protobuf:"bytes,3,opt,name=snapshot_token,json=snapshotToken,proto3" json:"snapshot_token,omitempty"` // Optional token from an earlier response for a consistent view across calls
+	Order         RecordOrder `protobuf:"varint,4,opt,name=order,proto3,enum=bell.v1.RecordOrder" json:"order,omitempty"`            // Defaults to semantic ordering
+	MaxExpansion  int32       `protobuf:"varint,5,opt,name=max_expansion,json=maxExpansion,proto3" json:"max_expansion,omitempty"`   // Optional; domains each pattern expands to at most, defaulting to and capped by the server's limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordsBatchRequest) Reset() {
+	*x = GetRecordsBatchRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordsBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordsBatchRequest) ProtoMessage() {}
+
+func (x *GetRecordsBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordsBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordsBatchRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRecordsBatchRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *GetRecordsBatchRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *GetRecordsBatchRequest) GetSnapshotToken() string {
+	if x != nil {
+		return x.SnapshotToken
+	}
+	return ""
+}
+
+func (x *GetRecordsBatchRequest) GetOrder() RecordOrder {
+	if x != nil {
+		return x.Order
+	}
+	return RecordOrder_RECORD_ORDER_UNSPECIFIED
+}
+
+func (x *GetRecordsBatchRequest) GetMaxExpansion() int32 {
+	if x != nil {
+		return x.MaxExpansion
+	}
+	return 0
+}
+
+// PatternExpansion is the domains a GetRecordsBatch pattern expanded to.
+type PatternExpansion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pattern       string                 `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Domains       []string               `protobuf:"bytes,2,rep,name=domains,proto3" json:"domains,omitempty"`      // In name order
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"` // The pattern matched more domains than it could expand to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatternExpansion) Reset() {
+	*x = PatternExpansion{}
+	mi := &file_bell_v1_bell_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatternExpansion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternExpansion) ProtoMessage() {}
+
+func (x *PatternExpansion) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternExpansion.ProtoReflect.Descriptor instead.
+func (*PatternExpansion) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PatternExpansion) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *PatternExpansion) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *PatternExpansion) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type DomainRecords struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Records        []*DNSRecord           `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Truncated      bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`                                   // Records were cut to the key's max_rows preference
+	DataAgeSeconds int64                  `protobuf:"varint,3,opt,name=data_age_seconds,json=dataAgeSeconds,proto3" json:"data_age_seconds,omitempty"` // As GetRecordsResponse.data_age_seconds; the X-Data-Freshness header covers every domain
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DomainRecords) Reset() {
+	*x = DomainRecords{}
+	mi := &file_bell_v1_bell_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainRecords) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainRecords) ProtoMessage() {}
+
+func (x *DomainRecords) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainRecords.ProtoReflect.Descriptor instead.
+func (*DomainRecords) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DomainRecords) GetRecords() []*DNSRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *DomainRecords) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *DomainRecords) GetDataAgeSeconds() int64 {
+	if x != nil {
+		return x.DataAgeSeconds
+	}
+	return 0
+}
+
+type GetRecordsBatchResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Results       map[string]*DomainRecords `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Keyed by requested domain, or domain a pattern expanded to; every requested domain has an entry, empty if it has no records
+	SnapshotToken string                    `protobuf:"bytes,2,opt,name=snapshot_token,json=snapshotToken,proto3" json:"snapshot_token,omitempty"`                                          // Pass back in later requests to see the same snapshot
+	Expansions    []*PatternExpansion       `protobuf:"bytes,3,rep,name=expansions,proto3" json:"expansions,omitempty"`                                                                     // One per requested pattern, in request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordsBatchResponse) Reset() {
+	*x = GetRecordsBatchResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordsBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordsBatchResponse) ProtoMessage() {}
+
+func (x *GetRecordsBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordsBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordsBatchResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRecordsBatchResponse) GetResults() map[string]*DomainRecords {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *GetRecordsBatchResponse) GetSnapshotToken() string {
+	if x != nil {
+		return x.SnapshotToken
+	}
+	return ""
+}
+
+func (x *GetRecordsBatchResponse) GetExpansions() []*PatternExpansion {
+	if x != nil {
+		return x.Expansions
+	}
+	return nil
+}
+
+type StreamRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`                           // Stream the records of this domain, or
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`                                 // of every domain of this TLD; exactly one must be set
+	RecordType    []string               `protobuf:"bytes,3,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional filter (e.g., ["NS", "MX"])
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                           // Optional; only records from this source (e.g. "CZDS")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRecordsRequest) Reset() {
+	*x = StreamRecordsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecordsRequest) ProtoMessage() {}
+
+func (x *StreamRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecordsRequest.ProtoReflect.Descriptor instead.
+func (*StreamRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StreamRecordsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *StreamRecordsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *StreamRecordsRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *StreamRecordsRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// StreamedRecord is one record of a StreamRecords stream. Records come in
+// storage order, so the records of a domain need not be adjacent, and carry
+// their record set's version. Their observation_count, first_seen and
+// last_seen are those of their own source only.
+type StreamedRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Record        *DNSRecord             `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamedRecord) Reset() {
+	*x = StreamedRecord{}
+	mi := &file_bell_v1_bell_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamedRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamedRecord) ProtoMessage() {}
+
+func (x *StreamedRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamedRecord.ProtoReflect.Descriptor instead.
+func (*StreamedRecord) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StreamedRecord) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *StreamedRecord) GetRecord() *DNSRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type StreamRecordBatchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`                           // Stream the records of this domain, or
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`                                 // of every domain of this TLD; exactly one must be set
+	RecordType    []string               `protobuf:"bytes,3,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional filter (e.g., ["NS", "MX"])
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                           // Optional; only records from this source (e.g. "CZDS")
+	BatchRows     int32                  `protobuf:"varint,5,opt,name=batch_rows,json=batchRows,proto3" json:"batch_rows,omitempty"`   // Rows per batch; defaults to and at most 65536. Batches are also cut at about 2 MiB
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRecordBatchesRequest) Reset() {
+	*x = StreamRecordBatchesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecordBatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecordBatchesRequest) ProtoMessage() {}
+
+func (x *StreamRecordBatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecordBatchesRequest.ProtoReflect.Descriptor instead.
+func (*StreamRecordBatchesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StreamRecordBatchesRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *StreamRecordBatchesRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *StreamRecordBatchesRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *StreamRecordBatchesRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *StreamRecordBatchesRequest) GetBatchRows() int32 {
+	if x != nil {
+		return x.BatchRows
+	}
+	return 0
+}
+
+// ArrowMessage is one message of an Arrow IPC stream: the schema first,
+// then a record batch per message, then the end-of-stream marker. The data
+// of all messages concatenated is a stream Arrow readers open directly
+// (e.g. pyarrow.ipc.open_stream), with the columns domain, record_type,
+// record_data, ttl (int32, nullable), source, first_seen and last_seen
+// (timestamp[us, UTC]). first_seen and last_seen are those of the record's
+// own source, as in StreamRecords.
+type ArrowMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Rows          int64                  `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"` // Records in the batch; 0 for the schema and end-of-stream messages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArrowMessage) Reset() {
+	*x = ArrowMessage{}
+	mi := &file_bell_v1_bell_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArrowMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrowMessage) ProtoMessage() {}
+
+func (x *ArrowMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrowMessage.ProtoReflect.Descriptor instead.
+func (*ArrowMessage) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ArrowMessage) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ArrowMessage) GetRows() int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+type SubscribeRecordChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`                            // Watch these domains (at most 1000), or
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`                                    // every domain of this TLD; exactly one must be set
+	RecordTypes   []string               `protobuf:"bytes,3,rep,name=record_types,json=recordTypes,proto3" json:"record_types,omitempty"` // Optional filter (e.g., ["NS", "MX"])
+	Significances []string               `protobuf:"bytes,4,rep,name=significances,proto3" json:"significances,omitempty"`                // Optional; only changes of these significances (NEW, COSMETIC, MINOR, ASN_CHANGED, MX_PROVIDER_CHANGED, UNCLASSIFIED)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRecordChangesRequest) Reset() {
+	*x = SubscribeRecordChangesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRecordChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRecordChangesRequest) ProtoMessage() {}
+
+func (x *SubscribeRecordChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRecordChangesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRecordChangesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SubscribeRecordChangesRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *SubscribeRecordChangesRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *SubscribeRecordChangesRequest) GetRecordTypes() []string {
+	if x != nil {
+		return x.RecordTypes
+	}
+	return nil
+}
+
+func (x *SubscribeRecordChangesRequest) GetSignificances() []string {
+	if x != nil {
+		return x.Significances
+	}
+	return nil
+}
+
+// RecordChange is a record set the query worker found new or different from
+// the stored one. Fetch the records with GetRecords.
+type RecordChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          string                 `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"` // RFC 3339 with fractional seconds
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Tld           string                 `protobuf:"bytes,3,opt,name=tld,proto3" json:"tld,omitempty"`
+	RecordType    string                 `protobuf:"bytes,4,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Version       int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`          // Record-set version after the change; unchanged for COSMETIC changes
+	Significance  string                 `protobuf:"bytes,6,opt,name=significance,proto3" json:"significance,omitempty"` // NEW, COSMETIC (TTL only), MINOR, ASN_CHANGED, MX_PROVIDER_CHANGED or UNCLASSIFIED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordChange) Reset() {
+	*x = RecordChange{}
+	mi := &file_bell_v1_bell_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordChange) ProtoMessage() {}
+
+func (x *RecordChange) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordChange.ProtoReflect.Descriptor instead.
+func (*RecordChange) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RecordChange) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *RecordChange) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RecordChange) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *RecordChange) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *RecordChange) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RecordChange) GetSignificance() string {
+	if x != nil {
+		return x.Significance
+	}
+	return ""
+}
+
+// MergeProvenance explains which source was chosen for a record type when
+// GetRecords is called with merged=true.
+type MergeProvenance struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RecordType        string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	ChosenSource      string                 `protobuf:"bytes,2,opt,name=chosen_source,json=chosenSource,proto3" json:"chosen_source,omitempty"`
+	Reason            string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                                  // "precedence", "freshness", or "single_source"
+	ChosenLastUpdated string                 `protobuf:"bytes,4,opt,name=chosen_last_updated,json=chosenLastUpdated,proto3" json:"chosen_last_updated,omitempty"` // Most recent update of the chosen source (RFC 3339)
+	DiscardedSources  []string               `protobuf:"bytes,5,rep,name=discarded_sources,json=discardedSources,proto3" json:"discarded_sources,omitempty"`
+	DiscardedRecords  int32                  `protobuf:"varint,6,opt,name=discarded_records,json=discardedRecords,proto3" json:"discarded_records,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MergeProvenance) Reset() {
+	*x = MergeProvenance{}
+	mi := &file_bell_v1_bell_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeProvenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeProvenance) ProtoMessage() {}
+
+func (x *MergeProvenance) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeProvenance.ProtoReflect.Descriptor instead.
+func (*MergeProvenance) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MergeProvenance) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *MergeProvenance) GetChosenSource() string {
+	if x != nil {
+		return x.ChosenSource
+	}
+	return ""
+}
+
+func (x *MergeProvenance) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MergeProvenance) GetChosenLastUpdated() string {
+	if x != nil {
+		return x.ChosenLastUpdated
+	}
+	return ""
+}
+
+func (x *MergeProvenance) GetDiscardedSources() []string {
+	if x != nil {
+		return x.DiscardedSources
+	}
+	return nil
+}
+
+func (x *MergeProvenance) GetDiscardedRecords() int32 {
+	if x != nil {
+		return x.DiscardedRecords
+	}
+	return 0
+}
+
+type TLDStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tld           string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`
+	LastProcessed string                 `protobuf:"bytes,2,opt,name=last_processed,json=lastProcessed,proto3" json:"last_processed,omitempty"` // Last successful ingest (RFC 3339); empty if never successful
+	LastAttempted string                 `protobuf:"bytes,3,opt,name=last_attempted,json=lastAttempted,proto3" json:"last_attempted,omitempty"` // Last ingest attempt (RFC 3339)
+	LastStatus    string                 `protobuf:"bytes,4,opt,name=last_status,json=lastStatus,proto3" json:"last_status,omitempty"`          // SUCCESS, FAILED or INTERRUPTED (stopped by shutdown; retried on the next run)
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`             // Error from the last attempt if it failed
+	DomainCount   int64                  `protobuf:"varint,6,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"`      // Domains in the TLD as of the last successful ingest
+	RecordCount   int64                  `protobuf:"varint,7,opt,name=record_count,json=recordCount,proto3" json:"record_count,omitempty"`      // Records stored by the last successful ingest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TLDStatus) Reset() {
+	*x = TLDStatus{}
+	mi := &file_bell_v1_bell_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLDStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLDStatus) ProtoMessage() {}
+
+func (x *TLDStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLDStatus.ProtoReflect.Descriptor instead.
+func (*TLDStatus) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TLDStatus) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *TLDStatus) GetLastProcessed() string {
+	if x != nil {
+		return x.LastProcessed
+	}
+	return ""
+}
+
+func (x *TLDStatus) GetLastAttempted() string {
+	if x != nil {
+		return x.LastAttempted
+	}
+	return ""
+}
+
+func (x *TLDStatus) GetLastStatus() string {
+	if x != nil {
+		return x.LastStatus
+	}
+	return ""
+}
+
+func (x *TLDStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *TLDStatus) GetDomainCount() int64 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+func (x *TLDStatus) GetRecordCount() int64 {
+	if x != nil {
+		return x.RecordCount
+	}
+	return 0
+}
+
+type ListTLDsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTLDsRequest) Reset() {
+	*x = ListTLDsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTLDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTLDsRequest) ProtoMessage() {}
+
+func (x *ListTLDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTLDsRequest.ProtoReflect.Descriptor instead.
+func (*ListTLDsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{24}
+}
+
+type ListTLDsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tlds          []*TLDStatus           `protobuf:"bytes,1,rep,name=tlds,proto3" json:"tlds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTLDsResponse) Reset() {
+	*x = ListTLDsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTLDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTLDsResponse) ProtoMessage() {}
+
+func (x *ListTLDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTLDsResponse.ProtoReflect.Descriptor instead.
+func (*ListTLDsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListTLDsResponse) GetTlds() []*TLDStatus {
+	if x != nil {
+		return x.Tlds
+	}
+	return nil
+}
+
+type GetTLDStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tld           string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTLDStatusRequest) Reset() {
+	*x = GetTLDStatusRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTLDStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTLDStatusRequest) ProtoMessage() {}
+
+func (x *GetTLDStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTLDStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetTLDStatusRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetTLDStatusRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+type GetTLDStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *TLDStatus             `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTLDStatusResponse) Reset() {
+	*x = GetTLDStatusResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTLDStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTLDStatusResponse) ProtoMessage() {}
+
+func (x *GetTLDStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTLDStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetTLDStatusResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetTLDStatusResponse) GetStatus() *TLDStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type ListDomainsByTLDRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Tld       string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`                              // A TLD, or a public suffix such as co.uk
+	PageSize  int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Optional; defaults to the key's max_rows or 100, at most 1000
+	PageToken string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; empty for the first. Later pages leave out domains inserted after the first was served
+	// Optional RFC 3339 bounds on first_seen. Registration dates are not
+	// stored; a domain is first seen when a zone file or query first has it
+	FirstSeenAfter  string `protobuf:"bytes,4,opt,name=first_seen_after,json=firstSeenAfter,proto3" json:"first_seen_after,omitempty"`
+	FirstSeenBefore string `protobuf:"bytes,5,opt,name=first_seen_before,json=firstSeenBefore,proto3" json:"first_seen_before,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListDomainsByTLDRequest) Reset() {
+	*x = ListDomainsByTLDRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainsByTLDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainsByTLDRequest) ProtoMessage() {}
+
+func (x *ListDomainsByTLDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainsByTLDRequest.ProtoReflect.Descriptor instead.
+func (*ListDomainsByTLDRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListDomainsByTLDRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *ListDomainsByTLDRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDomainsByTLDRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListDomainsByTLDRequest) GetFirstSeenAfter() string {
+	if x != nil {
+		return x.FirstSeenAfter
+	}
+	return ""
+}
+
+func (x *ListDomainsByTLDRequest) GetFirstSeenBefore() string {
+	if x != nil {
+		return x.FirstSeenBefore
+	}
+	return ""
+}
+
+type TLDDomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	PublicSuffix  string                 `protobuf:"bytes,2,opt,name=public_suffix,json=publicSuffix,proto3" json:"public_suffix,omitempty"` // e.g. co.uk under the TLD uk
+	Nameservers   []string               `protobuf:"bytes,3,rep,name=nameservers,proto3" json:"nameservers,omitempty"`                       // From the zone delegation; empty for domains only found by queries
+	FirstSeen     string                 `protobuf:"bytes,4,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`          // RFC 3339
+	LastUpdated   string                 `protobuf:"bytes,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`    // RFC 3339; empty if never queried
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TLDDomain) Reset() {
+	*x = TLDDomain{}
+	mi := &file_bell_v1_bell_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLDDomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLDDomain) ProtoMessage() {}
+
+func (x *TLDDomain) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLDDomain.ProtoReflect.Descriptor instead.
+func (*TLDDomain) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TLDDomain) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *TLDDomain) GetPublicSuffix() string {
+	if x != nil {
+		return x.PublicSuffix
+	}
+	return ""
+}
+
+func (x *TLDDomain) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *TLDDomain) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *TLDDomain) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+type ListDomainsByTLDResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []*TLDDomain           `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainsByTLDResponse) Reset() {
+	*x = ListDomainsByTLDResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainsByTLDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainsByTLDResponse) ProtoMessage() {}
+
+func (x *ListDomainsByTLDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainsByTLDResponse.ProtoReflect.Descriptor instead.
+func (*ListDomainsByTLDResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListDomainsByTLDResponse) GetDomains() []*TLDDomain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *ListDomainsByTLDResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetTopNRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metric        TopNMetric             `protobuf:"varint,1,opt,name=metric,proto3,enum=bell.v1.TopNMetric" json:"metric,omitempty"`
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`      // Optional; global ranking if empty
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // Optional; defaults to all stored entries
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopNRequest) Reset() {
+	*x = GetTopNRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopNRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopNRequest) ProtoMessage() {}
+
+func (x *GetTopNRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopNRequest.ProtoReflect.Descriptor instead.
+func (*GetTopNRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetTopNRequest) GetMetric() TopNMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return TopNMetric_TOP_N_METRIC_UNSPECIFIED
+}
+
+func (x *GetTopNRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetTopNRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TopNEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`     // Nameserver host, provider domain, or ASN (e.g. AS15169)
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"` // AS name for ASNs
+	DomainCount   int64                  `protobuf:"varint,4,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopNEntry) Reset() {
+	*x = TopNEntry{}
+	mi := &file_bell_v1_bell_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopNEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopNEntry) ProtoMessage() {}
+
+func (x *TopNEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopNEntry.ProtoReflect.Descriptor instead.
+func (*TopNEntry) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TopNEntry) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *TopNEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TopNEntry) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *TopNEntry) GetDomainCount() int64 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+type GetTopNResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*TopNEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	ComputedAt    string                 `protobuf:"bytes,2,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // When the aggregate was last computed (RFC 3339)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopNResponse) Reset() {
+	*x = GetTopNResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopNResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopNResponse) ProtoMessage() {}
+
+func (x *GetTopNResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopNResponse.ProtoReflect.Descriptor instead.
+func (*GetTopNResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetTopNResponse) GetEntries() []*TopNEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetTopNResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type GetKeywordTrendsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tld           string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`                                        // Optional; all TLDs if empty
+	Day           string                 `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"`                                        // Optional YYYY-MM-DD (UTC); defaults to the latest computed day
+	BaselineDays  int32                  `protobuf:"varint,3,opt,name=baseline_days,json=baselineDays,proto3" json:"baseline_days,omitempty"` // Optional; days before day averaged as the baseline, default 7, at most 90
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                   // Optional; defaults to 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeywordTrendsRequest) Reset() {
+	*x = GetKeywordTrendsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeywordTrendsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeywordTrendsRequest) ProtoMessage() {}
+
+func (x *GetKeywordTrendsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeywordTrendsRequest.ProtoReflect.Descriptor instead.
+func (*GetKeywordTrendsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetKeywordTrendsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetKeywordTrendsRequest) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *GetKeywordTrendsRequest) GetBaselineDays() int32 {
+	if x != nil {
+		return x.BaselineDays
+	}
+	return 0
+}
+
+func (x *GetKeywordTrendsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type KeywordTrend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	DomainCount   int64                  `protobuf:"varint,2,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"` // New domains containing the keyword on day
+	Baseline      float64                `protobuf:"fixed64,3,opt,name=baseline,proto3" json:"baseline,omitempty"`                         // Average daily count over the baseline days; days below keyword_min_count count as 0
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`                               // (domain_count + 1) / (baseline + 1); higher is a sharper spike
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeywordTrend) Reset() {
+	*x = KeywordTrend{}
+	mi := &file_bell_v1_bell_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeywordTrend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeywordTrend) ProtoMessage() {}
+
+func (x *KeywordTrend) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeywordTrend.ProtoReflect.Descriptor instead.
+func (*KeywordTrend) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *KeywordTrend) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *KeywordTrend) GetDomainCount() int64 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+func (x *KeywordTrend) GetBaseline() float64 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+func (x *KeywordTrend) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type GetKeywordTrendsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`
+	Trends        []*KeywordTrend        `protobuf:"bytes,3,rep,name=trends,proto3" json:"trends,omitempty"`                           // Highest score first
+	ComputedAt    string                 `protobuf:"bytes,4,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // When day was last computed (RFC 3339)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeywordTrendsResponse) Reset() {
+	*x = GetKeywordTrendsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeywordTrendsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeywordTrendsResponse) ProtoMessage() {}
+
+func (x *GetKeywordTrendsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeywordTrendsResponse.ProtoReflect.Descriptor instead.
+func (*GetKeywordTrendsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetKeywordTrendsResponse) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *GetKeywordTrendsResponse) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetKeywordTrendsResponse) GetTrends() []*KeywordTrend {
+	if x != nil {
+		return x.Trends
+	}
+	return nil
+}
+
+func (x *GetKeywordTrendsResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type GetDNSSECAdoptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tld           string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`    // Optional; all TLDs if empty
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"` // Optional; days of history ending at the latest computed day, default 30, at most 365
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDNSSECAdoptionRequest) Reset() {
+	*x = GetDNSSECAdoptionRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDNSSECAdoptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDNSSECAdoptionRequest) ProtoMessage() {}
+
+func (x *GetDNSSECAdoptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDNSSECAdoptionRequest.ProtoReflect.Descriptor instead.
+func (*GetDNSSECAdoptionRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetDNSSECAdoptionRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetDNSSECAdoptionRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type DNSSECAdoptionPoint struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Day            string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`                                               // YYYY-MM-DD (UTC)
+	Delegations    int64                  `protobuf:"varint,2,opt,name=delegations,proto3" json:"delegations,omitempty"`                              // Domains with nameservers
+	Signed         int64                  `protobuf:"varint,3,opt,name=signed,proto3" json:"signed,omitempty"`                                        // Delegations with DS records
+	SignedFraction float64                `protobuf:"fixed64,4,opt,name=signed_fraction,json=signedFraction,proto3" json:"signed_fraction,omitempty"` // signed / delegations; 0 without delegations
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DNSSECAdoptionPoint) Reset() {
+	*x = DNSSECAdoptionPoint{}
+	mi := &file_bell_v1_bell_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSSECAdoptionPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSSECAdoptionPoint) ProtoMessage() {}
+
+func (x *DNSSECAdoptionPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSSECAdoptionPoint.ProtoReflect.Descriptor instead.
+func (*DNSSECAdoptionPoint) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DNSSECAdoptionPoint) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *DNSSECAdoptionPoint) GetDelegations() int64 {
+	if x != nil {
+		return x.Delegations
+	}
+	return 0
+}
+
+func (x *DNSSECAdoptionPoint) GetSigned() int64 {
+	if x != nil {
+		return x.Signed
+	}
+	return 0
+}
+
+func (x *DNSSECAdoptionPoint) GetSignedFraction() float64 {
+	if x != nil {
+		return x.SignedFraction
+	}
+	return 0
+}
+
+type DNSSECAlgorithmUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     int32                  `protobuf:"varint,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`                             // IANA DNSSEC algorithm number
+	AlgorithmName string                 `protobuf:"bytes,2,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"` // e.g. ECDSAP256SHA256
+	KeySize       int32                  `protobuf:"varint,3,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`                  // Bits; 0 if only known from DS records
+	DomainCount   int64                  `protobuf:"varint,4,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DNSSECAlgorithmUsage) Reset() {
+	*x = DNSSECAlgorithmUsage{}
+	mi := &file_bell_v1_bell_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSSECAlgorithmUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSSECAlgorithmUsage) ProtoMessage() {}
+
+func (x *DNSSECAlgorithmUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSSECAlgorithmUsage.ProtoReflect.Descriptor instead.
+func (*DNSSECAlgorithmUsage) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DNSSECAlgorithmUsage) GetAlgorithm() int32 {
+	if x != nil {
+		return x.Algorithm
+	}
+	return 0
+}
+
+func (x *DNSSECAlgorithmUsage) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *DNSSECAlgorithmUsage) GetKeySize() int32 {
+	if x != nil {
+		return x.KeySize
+	}
+	return 0
+}
+
+func (x *DNSSECAlgorithmUsage) GetDomainCount() int64 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+type GetDNSSECAdoptionResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Tld           string                  `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`
+	Series        []*DNSSECAdoptionPoint  `protobuf:"bytes,2,rep,name=series,proto3" json:"series,omitempty"`                           // Oldest first
+	Algorithms    []*DNSSECAlgorithmUsage `protobuf:"bytes,3,rep,name=algorithms,proto3" json:"algorithms,omitempty"`                   // On the latest day, most used first
+	ComputedAt    string                  `protobuf:"bytes,4,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // When the latest day was computed (RFC 3339)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDNSSECAdoptionResponse) Reset() {
+	*x = GetDNSSECAdoptionResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDNSSECAdoptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDNSSECAdoptionResponse) ProtoMessage() {}
+
+func (x *GetDNSSECAdoptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDNSSECAdoptionResponse.ProtoReflect.Descriptor instead.
+func (*GetDNSSECAdoptionResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetDNSSECAdoptionResponse) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetDNSSECAdoptionResponse) GetSeries() []*DNSSECAdoptionPoint {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetDNSSECAdoptionResponse) GetAlgorithms() []*DNSSECAlgorithmUsage {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+func (x *GetDNSSECAdoptionResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type ListDomainClustersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinSize       int32                  `protobuf:"varint,1,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`            // Optional; only clusters of at least this many domains
+	ActiveSince   string                 `protobuf:"bytes,2,opt,name=active_since,json=activeSince,proto3" json:"active_since,omitempty"` // Optional RFC 3339; only clusters with a domain first seen since
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                               // Optional; defaults to 50, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainClustersRequest) Reset() {
+	*x = ListDomainClustersRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainClustersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainClustersRequest) ProtoMessage() {}
+
+func (x *ListDomainClustersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainClustersRequest.ProtoReflect.Descriptor instead.
+func (*ListDomainClustersRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListDomainClustersRequest) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *ListDomainClustersRequest) GetActiveSince() string {
+	if x != nil {
+		return x.ActiveSince
+	}
+	return ""
+}
+
+func (x *ListDomainClustersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DomainCluster struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId     string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"` // Stable across runs while the cluster's first domain and infrastructure are
+	Nameservers   []string               `protobuf:"bytes,2,rep,name=nameservers,proto3" json:"nameservers,omitempty"`              // The nameserver set its domains share
+	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`                  // The A records its domains share
+	DomainCount   int32                  `protobuf:"varint,4,opt,name=domain_count,json=domainCount,proto3" json:"domain_count,omitempty"`
+	FirstSeenFrom string                 `protobuf:"bytes,5,opt,name=first_seen_from,json=firstSeenFrom,proto3" json:"first_seen_from,omitempty"` // Earliest first_seen of its domains (RFC 3339)
+	FirstSeenTo   string                 `protobuf:"bytes,6,opt,name=first_seen_to,json=firstSeenTo,proto3" json:"first_seen_to,omitempty"`       // Latest
+	ComputedAt    string                 `protobuf:"bytes,7,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"`            // When the domain-clusters job found it (RFC 3339)
+	Domains       []*ClusterDomain       `protobuf:"bytes,8,rep,name=domains,proto3" json:"domains,omitempty"`                                    // GetDomainCluster only; first seen first
+	Truncated     bool                   `protobuf:"varint,9,opt,name=truncated,proto3" json:"truncated,omitempty"`                               // More domains than the limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DomainCluster) Reset() {
+	*x = DomainCluster{}
+	mi := &file_bell_v1_bell_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainCluster) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainCluster) ProtoMessage() {}
+
+func (x *DomainCluster) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainCluster.ProtoReflect.Descriptor instead.
+func (*DomainCluster) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DomainCluster) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *DomainCluster) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *DomainCluster) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *DomainCluster) GetDomainCount() int32 {
+	if x != nil {
+		return x.DomainCount
+	}
+	return 0
+}
+
+func (x *DomainCluster) GetFirstSeenFrom() string {
+	if x != nil {
+		return x.FirstSeenFrom
+	}
+	return ""
+}
+
+func (x *DomainCluster) GetFirstSeenTo() string {
+	if x != nil {
+		return x.FirstSeenTo
+	}
+	return ""
+}
+
+func (x *DomainCluster) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+func (x *DomainCluster) GetDomains() []*ClusterDomain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *DomainCluster) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type ClusterDomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,2,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterDomain) Reset() {
+	*x = ClusterDomain{}
+	mi := &file_bell_v1_bell_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterDomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterDomain) ProtoMessage() {}
+
+func (x *ClusterDomain) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterDomain.ProtoReflect.Descriptor instead.
+func (*ClusterDomain) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ClusterDomain) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ClusterDomain) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+type GetRecordActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"` // Optional; days ending at the latest rolled-up day, default 30, at most 365
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordActivityRequest) Reset() {
+	*x = GetRecordActivityRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordActivityRequest) ProtoMessage() {}
+
+func (x *GetRecordActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordActivityRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordActivityRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetRecordActivityRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetRecordActivityRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type RecordActivityPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`            // YYYY-MM-DD (UTC)
+	Observed      int64                  `protobuf:"varint,2,opt,name=observed,proto3" json:"observed,omitempty"` // Records last observed on the day
+	Added         int64                  `protobuf:"varint,3,opt,name=added,proto3" json:"added,omitempty"`       // Records first seen on the day
+	Changed       int64                  `protobuf:"varint,4,opt,name=changed,proto3" json:"changed,omitempty"`   // Records whose data or TTL changed on the day
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordActivityPoint) Reset() {
+	*x = RecordActivityPoint{}
+	mi := &file_bell_v1_bell_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordActivityPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordActivityPoint) ProtoMessage() {}
+
+func (x *RecordActivityPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordActivityPoint.ProtoReflect.Descriptor instead.
+func (*RecordActivityPoint) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RecordActivityPoint) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *RecordActivityPoint) GetObserved() int64 {
+	if x != nil {
+		return x.Observed
+	}
+	return 0
+}
+
+func (x *RecordActivityPoint) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *RecordActivityPoint) GetChanged() int64 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+type GetRecordActivityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Series        []*RecordActivityPoint `protobuf:"bytes,2,rep,name=series,proto3" json:"series,omitempty"`                           // Oldest first, one point per day, zero on days without activity
+	ComputedAt    string                 `protobuf:"bytes,3,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // When the latest day was rolled up (RFC 3339); empty before the first rollup
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordActivityResponse) Reset() {
+	*x = GetRecordActivityResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordActivityResponse) ProtoMessage() {}
+
+func (x *GetRecordActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordActivityResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordActivityResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetRecordActivityResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetRecordActivityResponse) GetSeries() []*RecordActivityPoint {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetRecordActivityResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type GetSubdomainActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Apex          string                 `protobuf:"bytes,1,opt,name=apex,proto3" json:"apex,omitempty"`    // A leading "*." is ignored
+	Weeks         int32                  `protobuf:"varint,2,opt,name=weeks,proto3" json:"weeks,omitempty"` // Optional; weeks ending at the latest rolled-up week, default 12, at most 104
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubdomainActivityRequest) Reset() {
+	*x = GetSubdomainActivityRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubdomainActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubdomainActivityRequest) ProtoMessage() {}
+
+func (x *GetSubdomainActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubdomainActivityRequest.ProtoReflect.Descriptor instead.
+func (*GetSubdomainActivityRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetSubdomainActivityRequest) GetApex() string {
+	if x != nil {
+		return x.Apex
+	}
+	return ""
+}
+
+func (x *GetSubdomainActivityRequest) GetWeeks() int32 {
+	if x != nil {
+		return x.Weeks
+	}
+	return 0
+}
+
+type SubdomainActivityPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Week          string                 `protobuf:"bytes,1,opt,name=week,proto3" json:"week,omitempty"`                                         // YYYY-MM-DD of the week's Monday (UTC)
+	NewSubdomains int64                  `protobuf:"varint,2,opt,name=new_subdomains,json=newSubdomains,proto3" json:"new_subdomains,omitempty"` // Public subdomains first seen in the week
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubdomainActivityPoint) Reset() {
+	*x = SubdomainActivityPoint{}
+	mi := &file_bell_v1_bell_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubdomainActivityPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubdomainActivityPoint) ProtoMessage() {}
+
+func (x *SubdomainActivityPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubdomainActivityPoint.ProtoReflect.Descriptor instead.
+func (*SubdomainActivityPoint) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SubdomainActivityPoint) GetWeek() string {
+	if x != nil {
+		return x.Week
+	}
+	return ""
+}
+
+func (x *SubdomainActivityPoint) GetNewSubdomains() int64 {
+	if x != nil {
+		return x.NewSubdomains
+	}
+	return 0
+}
+
+type GetSubdomainActivityResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Apex          string                    `protobuf:"bytes,1,opt,name=apex,proto3" json:"apex,omitempty"`
+	Series        []*SubdomainActivityPoint `protobuf:"bytes,2,rep,name=series,proto3" json:"series,omitempty"`                           // Oldest first, one point per week, zero in weeks without new subdomains
+	ComputedAt    string                    `protobuf:"bytes,3,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // When the latest week was rolled up (RFC 3339); empty before the first rollup
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubdomainActivityResponse) Reset() {
+	*x = GetSubdomainActivityResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubdomainActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubdomainActivityResponse) ProtoMessage() {}
+
+func (x *GetSubdomainActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubdomainActivityResponse.ProtoReflect.Descriptor instead.
+func (*GetSubdomainActivityResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetSubdomainActivityResponse) GetApex() string {
+	if x != nil {
+		return x.Apex
+	}
+	return ""
+}
+
+func (x *GetSubdomainActivityResponse) GetSeries() []*SubdomainActivityPoint {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *GetSubdomainActivityResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type ListDomainClustersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clusters      []*DomainCluster       `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"` // Without their domains
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainClustersResponse) Reset() {
+	*x = ListDomainClustersResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainClustersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainClustersResponse) ProtoMessage() {}
+
+func (x *ListDomainClustersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainClustersResponse.ProtoReflect.Descriptor instead.
+func (*ListDomainClustersResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListDomainClustersResponse) GetClusters() []*DomainCluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+type GetDomainClusterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId     string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"` // Either cluster_id or domain is required
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // Optional; domains returned, default 100, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDomainClusterRequest) Reset() {
+	*x = GetDomainClusterRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDomainClusterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainClusterRequest) ProtoMessage() {}
+
+func (x *GetDomainClusterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainClusterRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainClusterRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetDomainClusterRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetDomainClusterRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetDomainClusterRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetTTLStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"` // Exactly one of domain or tld is required
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`
+	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`        // Optional filter (e.g., "A")
+	AnomalyLimit  int32                  `protobuf:"varint,4,opt,name=anomaly_limit,json=anomalyLimit,proto3" json:"anomaly_limit,omitempty"` // Maximum anomalies returned; defaults to 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTTLStatsRequest) Reset() {
+	*x = GetTTLStatsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTTLStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTTLStatsRequest) ProtoMessage() {}
+
+func (x *GetTTLStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTTLStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTTLStatsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetTTLStatsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetTTLStatsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *GetTTLStatsRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *GetTTLStatsRequest) GetAnomalyLimit() int32 {
+	if x != nil {
+		return x.AnomalyLimit
+	}
+	return 0
+}
+
+type TTLBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinTtl        int32                  `protobuf:"varint,1,opt,name=min_ttl,json=minTtl,proto3" json:"min_ttl,omitempty"` // Inclusive
+	MaxTtl        int32                  `protobuf:"varint,2,opt,name=max_ttl,json=maxTtl,proto3" json:"max_ttl,omitempty"` // Exclusive; 0 means unbounded
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TTLBucket) Reset() {
+	*x = TTLBucket{}
+	mi := &file_bell_v1_bell_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TTLBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TTLBucket) ProtoMessage() {}
+
+func (x *TTLBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TTLBucket.ProtoReflect.Descriptor instead.
+func (*TTLBucket) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *TTLBucket) GetMinTtl() int32 {
+	if x != nil {
+		return x.MinTtl
+	}
+	return 0
+}
+
+func (x *TTLBucket) GetMaxTtl() int32 {
+	if x != nil {
+		return x.MaxTtl
+	}
+	return 0
+}
+
+func (x *TTLBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TTLAnomaly struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	OldTtl        int32                  `protobuf:"varint,3,opt,name=old_ttl,json=oldTtl,proto3" json:"old_ttl,omitempty"`
+	NewTtl        int32                  `protobuf:"varint,4,opt,name=new_ttl,json=newTtl,proto3" json:"new_ttl,omitempty"`
+	ObservedAt    string                 `protobuf:"bytes,5,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TTLAnomaly) Reset() {
+	*x = TTLAnomaly{}
+	mi := &file_bell_v1_bell_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TTLAnomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TTLAnomaly) ProtoMessage() {}
+
+func (x *TTLAnomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TTLAnomaly.ProtoReflect.Descriptor instead.
+func (*TTLAnomaly) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *TTLAnomaly) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *TTLAnomaly) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *TTLAnomaly) GetOldTtl() int32 {
+	if x != nil {
+		return x.OldTtl
+	}
+	return 0
+}
+
+func (x *TTLAnomaly) GetNewTtl() int32 {
+	if x != nil {
+		return x.NewTtl
+	}
+	return 0
+}
+
+func (x *TTLAnomaly) GetObservedAt() string {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return ""
+}
+
+type GetTTLStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Min           int32                  `protobuf:"varint,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int32                  `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
+	Mean          float64                `protobuf:"fixed64,4,opt,name=mean,proto3" json:"mean,omitempty"`
+	P50           float64                `protobuf:"fixed64,5,opt,name=p50,proto3" json:"p50,omitempty"`
+	P90           float64                `protobuf:"fixed64,6,opt,name=p90,proto3" json:"p90,omitempty"`
+	P99           float64                `protobuf:"fixed64,7,opt,name=p99,proto3" json:"p99,omitempty"`
+	Buckets       []*TTLBucket           `protobuf:"bytes,8,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Anomalies     []*TTLAnomaly          `protobuf:"bytes,9,rep,name=anomalies,proto3" json:"anomalies,omitempty"` // Most recent first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTTLStatsResponse) Reset() {
+	*x = GetTTLStatsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTTLStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTTLStatsResponse) ProtoMessage() {}
+
+func (x *GetTTLStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTTLStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetTTLStatsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetTTLStatsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetMin() int32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetMax() int32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetP50() float64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetP90() float64 {
+	if x != nil {
+		return x.P90
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetP99() float64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+func (x *GetTTLStatsResponse) GetBuckets() []*TTLBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetTTLStatsResponse) GetAnomalies() []*TTLAnomaly {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+type GetTLDCoverageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // CCTLD or GTLD to report only those gaps; empty for both
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTLDCoverageRequest) Reset() {
+	*x = GetTLDCoverageRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTLDCoverageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTLDCoverageRequest) ProtoMessage() {}
+
+func (x *GetTLDCoverageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTLDCoverageRequest.ProtoReflect.Descriptor instead.
+func (*GetTLDCoverageRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetTLDCoverageRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type TLDCoverageGap struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Tld                string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`
+	Kind               string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                                                        // CCTLD (two letters) or GTLD
+	ObservedDomains    int64                  `protobuf:"varint,3,opt,name=observed_domains,json=observedDomains,proto3" json:"observed_domains,omitempty"`          // Domains of the TLD in the corpus from other sources, e.g. passive DNS or customer imports
+	EstimatedDomains   int64                  `protobuf:"varint,4,opt,name=estimated_domains,json=estimatedDomains,proto3" json:"estimated_domains,omitempty"`       // Registered domains per coverage.estimated_sizes, else observed_domains as a lower bound
+	EstimateConfigured bool                   `protobuf:"varint,5,opt,name=estimate_configured,json=estimateConfigured,proto3" json:"estimate_configured,omitempty"` // estimated_domains is from coverage.estimated_sizes
+	Sources            []string               `protobuf:"bytes,6,rep,name=sources,proto3" json:"sources,omitempty"`                                                  // Where the TLD's domains could be obtained, best first
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TLDCoverageGap) Reset() {
+	*x = TLDCoverageGap{}
+	mi := &file_bell_v1_bell_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLDCoverageGap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLDCoverageGap) ProtoMessage() {}
+
+func (x *TLDCoverageGap) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLDCoverageGap.ProtoReflect.Descriptor instead.
+func (*TLDCoverageGap) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *TLDCoverageGap) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *TLDCoverageGap) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TLDCoverageGap) GetObservedDomains() int64 {
+	if x != nil {
+		return x.ObservedDomains
+	}
+	return 0
+}
+
+func (x *TLDCoverageGap) GetEstimatedDomains() int64 {
+	if x != nil {
+		return x.EstimatedDomains
+	}
+	return 0
+}
+
+func (x *TLDCoverageGap) GetEstimateConfigured() bool {
+	if x != nil {
+		return x.EstimateConfigured
+	}
+	return false
+}
+
+func (x *TLDCoverageGap) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type GetTLDCoverageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IanaTlds      int32                  `protobuf:"varint,1,opt,name=iana_tlds,json=ianaTlds,proto3" json:"iana_tlds,omitempty"`
+	CoveredTlds   int32                  `protobuf:"varint,2,opt,name=covered_tlds,json=coveredTlds,proto3" json:"covered_tlds,omitempty"` // IANA TLDs whose zone has been ingested
+	CctldGaps     int32                  `protobuf:"varint,3,opt,name=cctld_gaps,json=cctldGaps,proto3" json:"cctld_gaps,omitempty"`
+	GtldGaps      int32                  `protobuf:"varint,4,opt,name=gtld_gaps,json=gtldGaps,proto3" json:"gtld_gaps,omitempty"`
+	Gaps          []*TLDCoverageGap      `protobuf:"bytes,5,rep,name=gaps,proto3" json:"gaps,omitempty"`                                          // Largest estimate first
+	IanaUpdatedAt string                 `protobuf:"bytes,6,opt,name=iana_updated_at,json=ianaUpdatedAt,proto3" json:"iana_updated_at,omitempty"` // RFC 3339; when analytics -job iana-tlds last loaded the list
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTLDCoverageResponse) Reset() {
+	*x = GetTLDCoverageResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTLDCoverageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTLDCoverageResponse) ProtoMessage() {}
+
+func (x *GetTLDCoverageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTLDCoverageResponse.ProtoReflect.Descriptor instead.
+func (*GetTLDCoverageResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetTLDCoverageResponse) GetIanaTlds() int32 {
+	if x != nil {
+		return x.IanaTlds
+	}
+	return 0
+}
+
+func (x *GetTLDCoverageResponse) GetCoveredTlds() int32 {
+	if x != nil {
+		return x.CoveredTlds
+	}
+	return 0
+}
+
+func (x *GetTLDCoverageResponse) GetCctldGaps() int32 {
+	if x != nil {
+		return x.CctldGaps
+	}
+	return 0
+}
+
+func (x *GetTLDCoverageResponse) GetGtldGaps() int32 {
+	if x != nil {
+		return x.GtldGaps
+	}
+	return 0
+}
+
+func (x *GetTLDCoverageResponse) GetGaps() []*TLDCoverageGap {
+	if x != nil {
+		return x.Gaps
+	}
+	return nil
+}
+
+func (x *GetTLDCoverageResponse) GetIanaUpdatedAt() string {
+	if x != nil {
+		return x.IanaUpdatedAt
+	}
+	return ""
+}
+
+type SetLogLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`   // debug, info, warn, or error; empty returns the current level
+	Module        string                 `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"` // Module whose level to change or report, e.g. server; empty for all modules without a level of their own
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelRequest) Reset() {
+	*x = SetLogLevelRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelRequest) ProtoMessage() {}
+
+func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SetLogLevelRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *SetLogLevelRequest) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+type SetLogLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	PreviousLevel string                 `protobuf:"bytes,2,opt,name=previous_level,json=previousLevel,proto3" json:"previous_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelResponse) Reset() {
+	*x = SetLogLevelResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelResponse) ProtoMessage() {}
+
+func (x *SetLogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelResponse.ProtoReflect.Descriptor instead.
+func (*SetLogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SetLogLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *SetLogLevelResponse) GetPreviousLevel() string {
+	if x != nil {
+		return x.PreviousLevel
+	}
+	return ""
+}
+
+type CheckDomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"` // At most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckDomainsRequest) Reset() {
+	*x = CheckDomainsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckDomainsRequest) ProtoMessage() {}
+
+func (x *CheckDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckDomainsRequest.ProtoReflect.Descriptor instead.
+func (*CheckDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CheckDomainsRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+type DomainPresence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Exists        bool                   `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DomainPresence) Reset() {
+	*x = DomainPresence{}
+	mi := &file_bell_v1_bell_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainPresence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainPresence) ProtoMessage() {}
+
+func (x *DomainPresence) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainPresence.ProtoReflect.Descriptor instead.
+func (*DomainPresence) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DomainPresence) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DomainPresence) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+type CheckDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*DomainPresence      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // In request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckDomainsResponse) Reset() {
+	*x = CheckDomainsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckDomainsResponse) ProtoMessage() {}
+
+func (x *CheckDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckDomainsResponse.ProtoReflect.Descriptor instead.
+func (*CheckDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *CheckDomainsResponse) GetResults() []*DomainPresence {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type CountDomainsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Tld             string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`                                                  // Optional; only this TLD, which also limits the count to its shard
+	Pattern         string                 `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`                                          // Optional domain name glob; * matches any run of characters and ? one (e.g. "*paypal*")
+	FirstSeenAfter  string                 `protobuf:"bytes,3,opt,name=first_seen_after,json=firstSeenAfter,proto3" json:"first_seen_after,omitempty"`    // Optional RFC 3339 time
+	FirstSeenBefore string                 `protobuf:"bytes,4,opt,name=first_seen_before,json=firstSeenBefore,proto3" json:"first_seen_before,omitempty"` // Optional RFC 3339 time
+	Exact           bool                   `protobuf:"varint,5,opt,name=exact,proto3" json:"exact,omitempty"`                                             // Count exactly even when the planner expects many rows, unless it times out
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CountDomainsRequest) Reset() {
+	*x = CountDomainsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountDomainsRequest) ProtoMessage() {}
+
+func (x *CountDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountDomainsRequest.ProtoReflect.Descriptor instead.
+func (*CountDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CountDomainsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *CountDomainsRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *CountDomainsRequest) GetFirstSeenAfter() string {
+	if x != nil {
+		return x.FirstSeenAfter
+	}
+	return ""
+}
+
+func (x *CountDomainsRequest) GetFirstSeenBefore() string {
+	if x != nil {
+		return x.FirstSeenBefore
+	}
+	return ""
+}
+
+func (x *CountDomainsRequest) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+type CountRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tld           string                 `protobuf:"bytes,1,opt,name=tld,proto3" json:"tld,omitempty"`                                          // Optional; only this TLD, which also limits the count to its shard
+	DomainPattern string                 `protobuf:"bytes,2,opt,name=domain_pattern,json=domainPattern,proto3" json:"domain_pattern,omitempty"` // Optional domain name glob, as in CountDomainsRequest.pattern
+	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`          // Optional, e.g. MX
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                                    // Optional; CZDS or QUERY
+	DataPattern   string                 `protobuf:"bytes,5,opt,name=data_pattern,json=dataPattern,proto3" json:"data_pattern,omitempty"`       // Optional record data glob, e.g. "*google.com.*"
+	Exact         bool                   `protobuf:"varint,6,opt,name=exact,proto3" json:"exact,omitempty"`                                     // Count exactly even when the planner expects many rows, unless it times out
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountRecordsRequest) Reset() {
+	*x = CountRecordsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountRecordsRequest) ProtoMessage() {}
+
+func (x *CountRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountRecordsRequest.ProtoReflect.Descriptor instead.
+func (*CountRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *CountRecordsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *CountRecordsRequest) GetDomainPattern() string {
+	if x != nil {
+		return x.DomainPattern
+	}
+	return ""
+}
+
+func (x *CountRecordsRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *CountRecordsRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CountRecordsRequest) GetDataPattern() string {
+	if x != nil {
+		return x.DataPattern
+	}
+	return ""
+}
+
+func (x *CountRecordsRequest) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+type CountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Estimated     bool                   `protobuf:"varint,2,opt,name=estimated,proto3" json:"estimated,omitempty"`                             // count is the query planner's estimate for at least one shard
+	SkippedShards []string               `protobuf:"bytes,3,rep,name=skipped_shards,json=skippedShards,proto3" json:"skipped_shards,omitempty"` // Unhealthy shards left out of count
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountResponse) Reset() {
+	*x = CountResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountResponse) ProtoMessage() {}
+
+func (x *CountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
+func (*CountResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *CountResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CountResponse) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
+func (x *CountResponse) GetSkippedShards() []string {
+	if x != nil {
+		return x.SkippedShards
+	}
+	return nil
+}
+
+type StartExportRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Kind            string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                                                // DOMAINS or RECORDS
+	Format          string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`                                            // CSV or JSONL; defaults to CSV
+	Tld             string                 `protobuf:"bytes,3,opt,name=tld,proto3" json:"tld,omitempty"`                                                  // Optional; all TLDs if empty
+	Pattern         string                 `protobuf:"bytes,4,opt,name=pattern,proto3" json:"pattern,omitempty"`                                          // Optional domain name glob, e.g. "*paypal*"
+	RecordType      string                 `protobuf:"bytes,5,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`                  // Optional; RECORDS only
+	Source          string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`                                            // Optional; RECORDS only, CZDS or QUERY
+	DataPattern     string                 `protobuf:"bytes,7,opt,name=data_pattern,json=dataPattern,proto3" json:"data_pattern,omitempty"`               // Optional record data glob; RECORDS only
+	FirstSeenAfter  string                 `protobuf:"bytes,8,opt,name=first_seen_after,json=firstSeenAfter,proto3" json:"first_seen_after,omitempty"`    // Optional RFC 3339 bound on the domain's first_seen
+	FirstSeenBefore string                 `protobuf:"bytes,9,opt,name=first_seen_before,json=firstSeenBefore,proto3" json:"first_seen_before,omitempty"` // Optional RFC 3339 bound on the domain's first_seen
+	Scrub           bool                   `protobuf:"varint,10,opt,name=scrub,proto3" json:"scrub,omitempty"`                                            // Replace registrable domains with HMAC pseudonyms and clear fields as configured, for sharing
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StartExportRequest) Reset() {
+	*x = StartExportRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartExportRequest) ProtoMessage() {}
+
+func (x *StartExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartExportRequest.ProtoReflect.Descriptor instead.
+func (*StartExportRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *StartExportRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetDataPattern() string {
+	if x != nil {
+		return x.DataPattern
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetFirstSeenAfter() string {
+	if x != nil {
+		return x.FirstSeenAfter
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetFirstSeenBefore() string {
+	if x != nil {
+		return x.FirstSeenBefore
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetScrub() bool {
+	if x != nil {
+		return x.Scrub
+	}
+	return false
+}
+
+type ExportJob struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind                 string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Format               string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Tld                  string                 `protobuf:"bytes,4,opt,name=tld,proto3" json:"tld,omitempty"`
+	Pattern              string                 `protobuf:"bytes,5,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	RecordType           string                 `protobuf:"bytes,6,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Source               string                 `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	DataPattern          string                 `protobuf:"bytes,8,opt,name=data_pattern,json=dataPattern,proto3" json:"data_pattern,omitempty"`
+	FirstSeenAfter       string                 `protobuf:"bytes,9,opt,name=first_seen_after,json=firstSeenAfter,proto3" json:"first_seen_after,omitempty"`
+	FirstSeenBefore      string                 `protobuf:"bytes,10,opt,name=first_seen_before,json=firstSeenBefore,proto3" json:"first_seen_before,omitempty"`
+	Status               string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"` // PENDING, RUNNING, SUCCEEDED, FAILED or EXPIRED (file deleted)
+	Rows                 int64                  `protobuf:"varint,12,opt,name=rows,proto3" json:"rows,omitempty"`    // Rows written so far while RUNNING
+	Bytes                int64                  `protobuf:"varint,13,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	SkippedShards        []string               `protobuf:"bytes,14,rep,name=skipped_shards,json=skippedShards,proto3" json:"skipped_shards,omitempty"`                          // Unhealthy shards left out of the export
+	Error                string                 `protobuf:"bytes,15,opt,name=error,proto3" json:"error,omitempty"`                                                               // Set when FAILED
+	DownloadUrl          string                 `protobuf:"bytes,16,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`                                // Signed URL, set when SUCCEEDED; needs no API key
+	DownloadUrlExpiresAt string                 `protobuf:"bytes,17,opt,name=download_url_expires_at,json=downloadUrlExpiresAt,proto3" json:"download_url_expires_at,omitempty"` // RFC 3339; call GetExport again for a fresh URL
+	CreatedAt            string                 `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                      // RFC 3339
+	StartedAt            string                 `protobuf:"bytes,19,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`                                      // RFC 3339
+	FinishedAt           string                 `protobuf:"bytes,20,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`                                   // RFC 3339
+	ExpiresAt            string                 `protobuf:"bytes,21,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                      // When the file is deleted (RFC 3339); set when SUCCEEDED
+	Scrub                bool                   `protobuf:"varint,22,opt,name=scrub,proto3" json:"scrub,omitempty"`
+	Signature            string                 `protobuf:"bytes,23,opt,name=signature,proto3" json:"signature,omitempty"` // Of the file, set when SUCCEEDED if signing is configured; see GetSigningKeys
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ExportJob) Reset() {
+	*x = ExportJob{}
+	mi := &file_bell_v1_bell_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJob) ProtoMessage() {}
+
+func (x *ExportJob) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJob.ProtoReflect.Descriptor instead.
+func (*ExportJob) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ExportJob) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExportJob) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ExportJob) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportJob) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *ExportJob) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *ExportJob) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *ExportJob) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ExportJob) GetDataPattern() string {
+	if x != nil {
+		return x.DataPattern
+	}
+	return ""
+}
+
+func (x *ExportJob) GetFirstSeenAfter() string {
+	if x != nil {
+		return x.FirstSeenAfter
+	}
+	return ""
+}
+
+func (x *ExportJob) GetFirstSeenBefore() string {
+	if x != nil {
+		return x.FirstSeenBefore
+	}
+	return ""
+}
+
+func (x *ExportJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportJob) GetRows() int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *ExportJob) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *ExportJob) GetSkippedShards() []string {
+	if x != nil {
+		return x.SkippedShards
+	}
+	return nil
+}
+
+func (x *ExportJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ExportJob) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *ExportJob) GetDownloadUrlExpiresAt() string {
+	if x != nil {
+		return x.DownloadUrlExpiresAt
+	}
+	return ""
+}
+
+func (x *ExportJob) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ExportJob) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *ExportJob) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *ExportJob) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ExportJob) GetScrub() bool {
+	if x != nil {
+		return x.Scrub
+	}
+	return false
+}
+
+func (x *ExportJob) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type GetExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExportRequest) Reset() {
+	*x = GetExportRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExportRequest) ProtoMessage() {}
+
+func (x *GetExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExportRequest.ProtoReflect.Descriptor instead.
+func (*GetExportRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetExportRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListExportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExportsRequest) Reset() {
+	*x = ListExportsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExportsRequest) ProtoMessage() {}
+
+func (x *ListExportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExportsRequest.ProtoReflect.Descriptor instead.
+func (*ListExportsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{70}
+}
+
+type ListExportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exports       []*ExportJob           `protobuf:"bytes,1,rep,name=exports,proto3" json:"exports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExportsResponse) Reset() {
+	*x = ListExportsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExportsResponse) ProtoMessage() {}
+
+func (x *ListExportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExportsResponse.ProtoReflect.Descriptor instead.
+func (*ListExportsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ListExportsResponse) GetExports() []*ExportJob {
+	if x != nil {
+		return x.Exports
+	}
+	return nil
+}
+
+type GetAbuseContactsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Refresh       bool                   `protobuf:"varint,2,opt,name=refresh,proto3" json:"refresh,omitempty"` // Query RDAP even if cached contacts are still fresh; requires the write:ingest scope
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAbuseContactsRequest) Reset() {
+	*x = GetAbuseContactsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAbuseContactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAbuseContactsRequest) ProtoMessage() {}
+
+func (x *GetAbuseContactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAbuseContactsRequest.ProtoReflect.Descriptor instead.
+func (*GetAbuseContactsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetAbuseContactsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetAbuseContactsRequest) GetRefresh() bool {
+	if x != nil {
+		return x.Refresh
+	}
+	return false
+}
+
+type Registrar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IanaId        int32                  `protobuf:"varint,1,opt,name=iana_id,json=ianaId,proto3" json:"iana_id,omitempty"` // 0 if RDAP did not name an IANA registrar
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                  // IANA registry status (e.g. "Accredited"); empty if not loaded
+	RdapUrl       string                 `protobuf:"bytes,4,opt,name=rdap_url,json=rdapUrl,proto3" json:"rdap_url,omitempty"` // Registrar RDAP base URL from the IANA registry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registrar) Reset() {
+	*x = Registrar{}
+	mi := &file_bell_v1_bell_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registrar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registrar) ProtoMessage() {}
+
+func (x *Registrar) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registrar.ProtoReflect.Descriptor instead.
+func (*Registrar) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *Registrar) GetIanaId() int32 {
+	if x != nil {
+		return x.IanaId
+	}
+	return 0
+}
+
+func (x *Registrar) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Registrar) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Registrar) GetRdapUrl() string {
+	if x != nil {
+		return x.RdapUrl
+	}
+	return ""
+}
+
+type AbuseContact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"` // "registrar" or "registry"
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	SourceUrl     string                 `protobuf:"bytes,4,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"` // RDAP record the contact came from
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbuseContact) Reset() {
+	*x = AbuseContact{}
+	mi := &file_bell_v1_bell_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbuseContact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbuseContact) ProtoMessage() {}
+
+func (x *AbuseContact) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbuseContact.ProtoReflect.Descriptor instead.
+func (*AbuseContact) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *AbuseContact) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AbuseContact) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *AbuseContact) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *AbuseContact) GetSourceUrl() string {
+	if x != nil {
+		return x.SourceUrl
+	}
+	return ""
+}
+
+type GetAbuseContactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Registrar     *Registrar             `protobuf:"bytes,2,opt,name=registrar,proto3" json:"registrar,omitempty"`
+	Contacts      []*AbuseContact        `protobuf:"bytes,3,rep,name=contacts,proto3" json:"contacts,omitempty"`                    // Registrar contacts first
+	FetchedAt     string                 `protobuf:"bytes,4,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"` // When RDAP was last queried (RFC 3339)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAbuseContactsResponse) Reset() {
+	*x = GetAbuseContactsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAbuseContactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAbuseContactsResponse) ProtoMessage() {}
+
+func (x *GetAbuseContactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAbuseContactsResponse.ProtoReflect.Descriptor instead.
+func (*GetAbuseContactsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetAbuseContactsResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetAbuseContactsResponse) GetRegistrar() *Registrar {
+	if x != nil {
+		return x.Registrar
+	}
+	return nil
+}
+
+func (x *GetAbuseContactsResponse) GetContacts() []*AbuseContact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *GetAbuseContactsResponse) GetFetchedAt() string {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return ""
+}
+
+type GetDomainLifecycleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Significance  []string               `protobuf:"bytes,2,rep,name=significance,proto3" json:"significance,omitempty"` // Optional; only changes of these significances (COSMETIC, MINOR, ASN_CHANGED, NS_REPLACED, MX_PROVIDER_CHANGED, UNCLASSIFIED); FIRST_SEEN and TRANSFER events are always returned
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDomainLifecycleRequest) Reset() {
+	*x = GetDomainLifecycleRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDomainLifecycleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainLifecycleRequest) ProtoMessage() {}
+
+func (x *GetDomainLifecycleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainLifecycleRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainLifecycleRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *GetDomainLifecycleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetDomainLifecycleRequest) GetSignificance() []string {
+	if x != nil {
+		return x.Significance
+	}
+	return nil
+}
+
+type LifecycleEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`                                           // FIRST_SEEN, NAMESERVERS_CHANGED, RECORD_SET_CHANGED or TRANSFER
+	ObservedAt      string                 `protobuf:"bytes,2,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`             // RFC 3339
+	OldNameservers  []string               `protobuf:"bytes,3,rep,name=old_nameservers,json=oldNameservers,proto3" json:"old_nameservers,omitempty"` // NAMESERVERS_CHANGED and TRANSFER
+	Nameservers     []string               `protobuf:"bytes,4,rep,name=nameservers,proto3" json:"nameservers,omitempty"`
+	ProviderChanged bool                   `protobuf:"varint,5,opt,name=provider_changed,json=providerChanged,proto3" json:"provider_changed,omitempty"` // NAMESERVERS_CHANGED: the old and new nameservers share no registrable domain
+	OldRegistrar    *Registrar             `protobuf:"bytes,6,opt,name=old_registrar,json=oldRegistrar,proto3" json:"old_registrar,omitempty"`           // TRANSFER; unset if the previous registrar is unknown
+	Registrar       *Registrar             `protobuf:"bytes,7,opt,name=registrar,proto3" json:"registrar,omitempty"`                                     // TRANSFER
+	Confidence      string                 `protobuf:"bytes,8,opt,name=confidence,proto3" json:"confidence,omitempty"`                                   // TRANSFER: CONFIRMED (RDAP shows a new registrar or a transfer event) or LIKELY (nameserver provider change RDAP could not confirm)
+	Evidence        []string               `protobuf:"bytes,9,rep,name=evidence,proto3" json:"evidence,omitempty"`                                       // TRANSFER: nameserver_provider_changed, rdap_registrar_changed, rdap_transfer_event
+	Significance    string                 `protobuf:"bytes,10,opt,name=significance,proto3" json:"significance,omitempty"`                              // NAMESERVERS_CHANGED: NS_REPLACED or MINOR; RECORD_SET_CHANGED: COSMETIC, MINOR, ASN_CHANGED, MX_PROVIDER_CHANGED or UNCLASSIFIED
+	RecordType      string                 `protobuf:"bytes,11,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`                // RECORD_SET_CHANGED
+	Version         int64                  `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`                                       // RECORD_SET_CHANGED: record-set version after the change
+	OldRecords      []string               `protobuf:"bytes,13,rep,name=old_records,json=oldRecords,proto3" json:"old_records,omitempty"`                // RECORD_SET_CHANGED: canonical records (TTLs omitted); empty if UNCLASSIFIED
+	Records         []string               `protobuf:"bytes,14,rep,name=records,proto3" json:"records,omitempty"`                                        // RECORD_SET_CHANGED
+	OldTtl          int32                  `protobuf:"varint,15,opt,name=old_ttl,json=oldTtl,proto3" json:"old_ttl,omitempty"`                           // RECORD_SET_CHANGED: lowest TTL of each set
+	Ttl             int32                  `protobuf:"varint,16,opt,name=ttl,proto3" json:"ttl,omitempty"`                                               // RECORD_SET_CHANGED
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LifecycleEvent) Reset() {
+	*x = LifecycleEvent{}
+	mi := &file_bell_v1_bell_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LifecycleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LifecycleEvent) ProtoMessage() {}
+
+func (x *LifecycleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LifecycleEvent.ProtoReflect.Descriptor instead.
+func (*LifecycleEvent) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *LifecycleEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetObservedAt() string {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetOldNameservers() []string {
+	if x != nil {
+		return x.OldNameservers
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetProviderChanged() bool {
+	if x != nil {
+		return x.ProviderChanged
+	}
+	return false
+}
+
+func (x *LifecycleEvent) GetOldRegistrar() *Registrar {
+	if x != nil {
+		return x.OldRegistrar
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetRegistrar() *Registrar {
+	if x != nil {
+		return x.Registrar
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetConfidence() string {
+	if x != nil {
+		return x.Confidence
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetEvidence() []string {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetSignificance() string {
+	if x != nil {
+		return x.Significance
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *LifecycleEvent) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *LifecycleEvent) GetOldRecords() []string {
+	if x != nil {
+		return x.OldRecords
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetRecords() []string {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *LifecycleEvent) GetOldTtl() int32 {
+	if x != nil {
+		return x.OldTtl
+	}
+	return 0
+}
+
+func (x *LifecycleEvent) GetTtl() int32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+type GetDomainLifecycleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,2,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // RFC 3339; empty if the domain is not in a zone
+	LastSeen      string                 `protobuf:"bytes,3,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`    // RFC 3339
+	Events        []*LifecycleEvent      `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`                        // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDomainLifecycleResponse) Reset() {
+	*x = GetDomainLifecycleResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDomainLifecycleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainLifecycleResponse) ProtoMessage() {}
+
+func (x *GetDomainLifecycleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainLifecycleResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainLifecycleResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *GetDomainLifecycleResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *GetDomainLifecycleResponse) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *GetDomainLifecycleResponse) GetLastSeen() string {
+	if x != nil {
+		return x.LastSeen
+	}
+	return ""
+}
+
+func (x *GetDomainLifecycleResponse) GetEvents() []*LifecycleEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type VerifyDomainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    []string               `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional; defaults to NS, A, AAAA, MX, TXT and CNAME
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDomainRequest) Reset() {
+	*x = VerifyDomainRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDomainRequest) ProtoMessage() {}
+
+func (x *VerifyDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDomainRequest.ProtoReflect.Descriptor instead.
+func (*VerifyDomainRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *VerifyDomainRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *VerifyDomainRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+type RecordSetChecksum struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checksum      string                 `protobuf:"bytes,1,opt,name=checksum,proto3" json:"checksum,omitempty"` // Hex SHA-256 of the canonical record set (TTLs ignored)
+	RecordCount   int32                  `protobuf:"varint,2,opt,name=record_count,json=recordCount,proto3" json:"record_count,omitempty"`
+	ComputedAt    string                 `protobuf:"bytes,3,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"` // RFC 3339
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                        // Stored record-set version; 0 for live checksums
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordSetChecksum) Reset() {
+	*x = RecordSetChecksum{}
+	mi := &file_bell_v1_bell_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordSetChecksum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordSetChecksum) ProtoMessage() {}
+
+func (x *RecordSetChecksum) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordSetChecksum.ProtoReflect.Descriptor instead.
+func (*RecordSetChecksum) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *RecordSetChecksum) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *RecordSetChecksum) GetRecordCount() int32 {
+	if x != nil {
+		return x.RecordCount
+	}
+	return 0
+}
+
+func (x *RecordSetChecksum) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+func (x *RecordSetChecksum) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RecordSetVerification struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RecordType          string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Zone                *RecordSetChecksum     `protobuf:"bytes,2,opt,name=zone,proto3" json:"zone,omitempty"`                                                            // Stored at the last CZDS ingest; unset if the zone had no such records
+	Query               *RecordSetChecksum     `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`                                                          // Stored when the query worker last resolved the set; unset if never
+	Database            *RecordSetChecksum     `protobuf:"bytes,4,opt,name=database,proto3" json:"database,omitempty"`                                                    // Computed now from each source's current records; unset if none
+	Live                *RecordSetChecksum     `protobuf:"bytes,5,opt,name=live,proto3" json:"live,omitempty"`                                                            // Computed now from the domain's nameservers; unset if empty or failed
+	LiveError           string                 `protobuf:"bytes,6,opt,name=live_error,json=liveError,proto3" json:"live_error,omitempty"`                                 // Why live resolution failed, if it did
+	Drift               bool                   `protobuf:"varint,7,opt,name=drift,proto3" json:"drift,omitempty"`                                                         // The database or zone set differs from the live set
+	MissingFromDatabase []string               `protobuf:"bytes,8,rep,name=missing_from_database,json=missingFromDatabase,proto3" json:"missing_from_database,omitempty"` // Live records the database does not have
+	StaleInDatabase     []string               `protobuf:"bytes,9,rep,name=stale_in_database,json=staleInDatabase,proto3" json:"stale_in_database,omitempty"`             // Database records no longer served live
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RecordSetVerification) Reset() {
+	*x = RecordSetVerification{}
+	mi := &file_bell_v1_bell_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordSetVerification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordSetVerification) ProtoMessage() {}
+
+func (x *RecordSetVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordSetVerification.ProtoReflect.Descriptor instead.
+func (*RecordSetVerification) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RecordSetVerification) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *RecordSetVerification) GetZone() *RecordSetChecksum {
+	if x != nil {
+		return x.Zone
+	}
+	return nil
+}
+
+func (x *RecordSetVerification) GetQuery() *RecordSetChecksum {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *RecordSetVerification) GetDatabase() *RecordSetChecksum {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+func (x *RecordSetVerification) GetLive() *RecordSetChecksum {
+	if x != nil {
+		return x.Live
+	}
+	return nil
+}
+
+func (x *RecordSetVerification) GetLiveError() string {
+	if x != nil {
+		return x.LiveError
+	}
+	return ""
+}
+
+func (x *RecordSetVerification) GetDrift() bool {
+	if x != nil {
+		return x.Drift
+	}
+	return false
+}
+
+func (x *RecordSetVerification) GetMissingFromDatabase() []string {
+	if x != nil {
+		return x.MissingFromDatabase
+	}
+	return nil
+}
+
+func (x *RecordSetVerification) GetStaleInDatabase() []string {
+	if x != nil {
+		return x.StaleInDatabase
+	}
+	return nil
+}
+
+type VerifyDomainResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Domain        string                   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Nameservers   []string                 `protobuf:"bytes,2,rep,name=nameservers,proto3" json:"nameservers,omitempty"` // Nameservers queried for the live sets
+	RecordSets    []*RecordSetVerification `protobuf:"bytes,3,rep,name=record_sets,json=recordSets,proto3" json:"record_sets,omitempty"`
+	Drift         bool                     `protobuf:"varint,4,opt,name=drift,proto3" json:"drift,omitempty"` // Any record set drifted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDomainResponse) Reset() {
+	*x = VerifyDomainResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDomainResponse) ProtoMessage() {}
+
+func (x *VerifyDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDomainResponse.ProtoReflect.Descriptor instead.
+func (*VerifyDomainResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *VerifyDomainResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *VerifyDomainResponse) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *VerifyDomainResponse) GetRecordSets() []*RecordSetVerification {
+	if x != nil {
+		return x.RecordSets
+	}
+	return nil
+}
+
+func (x *VerifyDomainResponse) GetDrift() bool {
+	if x != nil {
+		return x.Drift
+	}
+	return false
+}
+
+type LiveLookupOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transport     string                 `protobuf:"bytes,1,opt,name=transport,proto3" json:"transport,omitempty"`                           // udp, tcp, tcp-tls or https; defaults to resolver.transport (udp when authoritative)
+	Server        string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`                                 // A configured recursive upstream (host:port) or DoH URL; random if empty
+	Authoritative bool                   `protobuf:"varint,3,opt,name=authoritative,proto3" json:"authoritative,omitempty"`                  // Query the domain's nameservers instead of a recursive upstream (udp or tcp)
+	Dnssec        bool                   `protobuf:"varint,4,opt,name=dnssec,proto3" json:"dnssec,omitempty"`                                // Set the DO bit, so answers include RRSIGs and the AD bit is meaningful
+	NoCache       bool                   `protobuf:"varint,5,opt,name=no_cache,json=noCache,proto3" json:"no_cache,omitempty"`               // Bypass the resolver cache
+	ClientSubnet  string                 `protobuf:"bytes,6,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"` // Sent as EDNS Client Subnet; an address stands for its /24 (IPv4) or /56 (IPv6)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LiveLookupOptions) Reset() {
+	*x = LiveLookupOptions{}
+	mi := &file_bell_v1_bell_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveLookupOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveLookupOptions) ProtoMessage() {}
+
+func (x *LiveLookupOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveLookupOptions.ProtoReflect.Descriptor instead.
+func (*LiveLookupOptions) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *LiveLookupOptions) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *LiveLookupOptions) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *LiveLookupOptions) GetAuthoritative() bool {
+	if x != nil {
+		return x.Authoritative
+	}
+	return false
+}
+
+func (x *LiveLookupOptions) GetDnssec() bool {
+	if x != nil {
+		return x.Dnssec
+	}
+	return false
+}
+
+func (x *LiveLookupOptions) GetNoCache() bool {
+	if x != nil {
+		return x.NoCache
+	}
+	return false
+}
+
+func (x *LiveLookupOptions) GetClientSubnet() string {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return ""
+}
+
+type LookupLiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    []string               `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional; defaults to A and AAAA
+	Options       *LiveLookupOptions     `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupLiveRequest) Reset() {
+	*x = LookupLiveRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupLiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupLiveRequest) ProtoMessage() {}
+
+func (x *LookupLiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupLiveRequest.ProtoReflect.Descriptor instead.
+func (*LookupLiveRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *LookupLiveRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *LookupLiveRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *LookupLiveRequest) GetOptions() *LiveLookupOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type LiveAnswer struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RecordType          string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Server              string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"` // Server that answered: host:port, or a DoH URL
+	Transport           string                 `protobuf:"bytes,3,opt,name=transport,proto3" json:"transport,omitempty"`
+	Rcode               string                 `protobuf:"bytes,4,opt,name=rcode,proto3" json:"rcode,omitempty"`                                                          // e.g. NOERROR, NXDOMAIN
+	Answer              []string               `protobuf:"bytes,5,rep,name=answer,proto3" json:"answer,omitempty"`                                                        // Answer section in zone file format
+	Authority           []string               `protobuf:"bytes,6,rep,name=authority,proto3" json:"authority,omitempty"`                                                  // Authority section, e.g. the SOA of a negative answer
+	RttMs               float64                `protobuf:"fixed64,7,opt,name=rtt_ms,json=rttMs,proto3" json:"rtt_ms,omitempty"`                                           // Round trip time; 0 if cached
+	Cached              bool                   `protobuf:"varint,8,opt,name=cached,proto3" json:"cached,omitempty"`                                                       // Served from the resolver cache
+	AuthenticatedData   bool                   `protobuf:"varint,9,opt,name=authenticated_data,json=authenticatedData,proto3" json:"authenticated_data,omitempty"`        // AD bit: the upstream validated the answer
+	AuthoritativeAnswer bool                   `protobuf:"varint,10,opt,name=authoritative_answer,json=authoritativeAnswer,proto3" json:"authoritative_answer,omitempty"` // AA bit
+	Error               string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`                                                         // Why the query failed, if it did
+	ClientSubnetScope   string                 `protobuf:"bytes,12,opt,name=client_subnet_scope,json=clientSubnetScope,proto3" json:"client_subnet_scope,omitempty"`      // Client block the answer applies to, from the ECS scope; empty if the server ignored ECS
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *LiveAnswer) Reset() {
+	*x = LiveAnswer{}
+	mi := &file_bell_v1_bell_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveAnswer) ProtoMessage() {}
+
+func (x *LiveAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveAnswer.ProtoReflect.Descriptor instead.
+func (*LiveAnswer) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *LiveAnswer) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *LiveAnswer) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *LiveAnswer) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *LiveAnswer) GetRcode() string {
+	if x != nil {
+		return x.Rcode
+	}
+	return ""
+}
+
+func (x *LiveAnswer) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *LiveAnswer) GetAuthority() []string {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *LiveAnswer) GetRttMs() float64 {
+	if x != nil {
+		return x.RttMs
+	}
+	return 0
+}
+
+func (x *LiveAnswer) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *LiveAnswer) GetAuthenticatedData() bool {
+	if x != nil {
+		return x.AuthenticatedData
+	}
+	return false
+}
+
+func (x *LiveAnswer) GetAuthoritativeAnswer() bool {
+	if x != nil {
+		return x.AuthoritativeAnswer
+	}
+	return false
+}
+
+func (x *LiveAnswer) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *LiveAnswer) GetClientSubnetScope() string {
+	if x != nil {
+		return x.ClientSubnetScope
+	}
+	return ""
+}
+
+type LookupLiveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Nameservers   []string               `protobuf:"bytes,2,rep,name=nameservers,proto3" json:"nameservers,omitempty"`                // Nameservers queried, if authoritative
+	Answers       []*LiveAnswer          `protobuf:"bytes,3,rep,name=answers,proto3" json:"answers,omitempty"`                        // One per record type, in request order
+	ElapsedMs     float64                `protobuf:"fixed64,4,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"` // Wall time of the whole lookup
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupLiveResponse) Reset() {
+	*x = LookupLiveResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupLiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupLiveResponse) ProtoMessage() {}
+
+func (x *LookupLiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupLiveResponse.ProtoReflect.Descriptor instead.
+func (*LookupLiveResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *LookupLiveResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *LookupLiveResponse) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *LookupLiveResponse) GetAnswers() []*LiveAnswer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+func (x *LookupLiveResponse) GetElapsedMs() float64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+type RefreshDomainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    []string               `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`       // Optional; defaults to A and AAAA
+	ClientSubnet  string                 `protobuf:"bytes,3,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"` // Optional, as in LiveLookupOptions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshDomainRequest) Reset() {
+	*x = RefreshDomainRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshDomainRequest) ProtoMessage() {}
+
+func (x *RefreshDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshDomainRequest.ProtoReflect.Descriptor instead.
+func (*RefreshDomainRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *RefreshDomainRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RefreshDomainRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *RefreshDomainRequest) GetClientSubnet() string {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return ""
+}
+
+// GeoAnswer is an answer RefreshDomain stored for a domain, record type and
+// client subnet.
+type GeoAnswer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordType    string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	ClientSubnet  string                 `protobuf:"bytes,2,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"` // Subnet sent as ECS; empty if none was
+	Scope         string                 `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`                                   // Client block the answer applies to, from the ECS scope; empty if the server ignored ECS
+	Rcode         string                 `protobuf:"bytes,4,opt,name=rcode,proto3" json:"rcode,omitempty"`
+	Answer        []string               `protobuf:"bytes,5,rep,name=answer,proto3" json:"answer,omitempty"`                        // Answer section in zone file format
+	Server        string                 `protobuf:"bytes,6,opt,name=server,proto3" json:"server,omitempty"`                        // Nameserver that answered (host:port)
+	QueriedAt     string                 `protobuf:"bytes,7,opt,name=queried_at,json=queriedAt,proto3" json:"queried_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoAnswer) Reset() {
+	*x = GeoAnswer{}
+	mi := &file_bell_v1_bell_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoAnswer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoAnswer) ProtoMessage() {}
+
+func (x *GeoAnswer) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoAnswer.ProtoReflect.Descriptor instead.
+func (*GeoAnswer) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *GeoAnswer) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *GeoAnswer) GetClientSubnet() string {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return ""
+}
+
+func (x *GeoAnswer) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *GeoAnswer) GetRcode() string {
+	if x != nil {
+		return x.Rcode
+	}
+	return ""
+}
+
+func (x *GeoAnswer) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *GeoAnswer) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *GeoAnswer) GetQueriedAt() string {
+	if x != nil {
+		return x.QueriedAt
+	}
+	return ""
+}
+
+type RefreshDomainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Nameservers   []string               `protobuf:"bytes,2,rep,name=nameservers,proto3" json:"nameservers,omitempty"` // Nameservers queried
+	Answers       []*GeoAnswer           `protobuf:"bytes,3,rep,name=answers,proto3" json:"answers,omitempty"`         // One per record type, in request order; failed queries are not stored
+	Errors        []string               `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`           // Why queries failed, by record type
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshDomainResponse) Reset() {
+	*x = RefreshDomainResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshDomainResponse) ProtoMessage() {}
+
+func (x *RefreshDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshDomainResponse.ProtoReflect.Descriptor instead.
+func (*RefreshDomainResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *RefreshDomainResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RefreshDomainResponse) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *RefreshDomainResponse) GetAnswers() []*GeoAnswer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+func (x *RefreshDomainResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type TraceResolutionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional; defaults to A
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceResolutionRequest) Reset() {
+	*x = TraceResolutionRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceResolutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResolutionRequest) ProtoMessage() {}
+
+func (x *TraceResolutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResolutionRequest.ProtoReflect.Descriptor instead.
+func (*TraceResolutionRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *TraceResolutionRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *TraceResolutionRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+type TraceStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zone          string                 `protobuf:"bytes,1,opt,name=zone,proto3" json:"zone,omitempty"`       // Zone whose nameservers were queried; "." for the root
+	Server        string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`   // Nameserver that answered
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"` // Its address (ip:port)
+	RttMs         float64                `protobuf:"fixed64,4,opt,name=rtt_ms,json=rttMs,proto3" json:"rtt_ms,omitempty"`
+	Rcode         string                 `protobuf:"bytes,5,opt,name=rcode,proto3" json:"rcode,omitempty"`                                       // e.g. NOERROR, NXDOMAIN
+	Kind          string                 `protobuf:"bytes,6,opt,name=kind,proto3" json:"kind,omitempty"`                                         // REFERRAL, ANSWER, CNAME, NODATA, NXDOMAIN or ERROR
+	Delegation    string                 `protobuf:"bytes,7,opt,name=delegation,proto3" json:"delegation,omitempty"`                             // Zone delegated to, for referrals
+	Nameservers   []string               `protobuf:"bytes,8,rep,name=nameservers,proto3" json:"nameservers,omitempty"`                           // Nameservers of the delegation
+	Glue          []string               `protobuf:"bytes,9,rep,name=glue,proto3" json:"glue,omitempty"`                                         // Glue A/AAAA records from the additional section
+	Ds            []string               `protobuf:"bytes,10,rep,name=ds,proto3" json:"ds,omitempty"`                                            // DS records of the delegation
+	Dnssec        string                 `protobuf:"bytes,11,opt,name=dnssec,proto3" json:"dnssec,omitempty"`                                    // SIGNED or UNSIGNED: DS present for a referral, RRSIGs present for an answer
+	Answer        []string               `protobuf:"bytes,12,rep,name=answer,proto3" json:"answer,omitempty"`                                    // Answer section in zone file format
+	Authority     []string               `protobuf:"bytes,13,rep,name=authority,proto3" json:"authority,omitempty"`                              // SOA or NSEC/NSEC3 records of a negative answer
+	FailedServers []string               `protobuf:"bytes,14,rep,name=failed_servers,json=failedServers,proto3" json:"failed_servers,omitempty"` // Servers of the zone tried first that did not answer, with why
+	Error         string                 `protobuf:"bytes,15,opt,name=error,proto3" json:"error,omitempty"`                                      // Why the trace stopped at this step, if it failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceStep) Reset() {
+	*x = TraceStep{}
+	mi := &file_bell_v1_bell_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceStep) ProtoMessage() {}
+
+func (x *TraceStep) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceStep.ProtoReflect.Descriptor instead.
+func (*TraceStep) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *TraceStep) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *TraceStep) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *TraceStep) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *TraceStep) GetRttMs() float64 {
+	if x != nil {
+		return x.RttMs
+	}
+	return 0
+}
+
+func (x *TraceStep) GetRcode() string {
+	if x != nil {
+		return x.Rcode
+	}
+	return ""
+}
+
+func (x *TraceStep) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TraceStep) GetDelegation() string {
+	if x != nil {
+		return x.Delegation
+	}
+	return ""
+}
+
+func (x *TraceStep) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *TraceStep) GetGlue() []string {
+	if x != nil {
+		return x.Glue
+	}
+	return nil
+}
+
+func (x *TraceStep) GetDs() []string {
+	if x != nil {
+		return x.Ds
+	}
+	return nil
+}
+
+func (x *TraceStep) GetDnssec() string {
+	if x != nil {
+		return x.Dnssec
+	}
+	return ""
+}
+
+func (x *TraceStep) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *TraceStep) GetAuthority() []string {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *TraceStep) GetFailedServers() []string {
+	if x != nil {
+		return x.FailedServers
+	}
+	return nil
+}
+
+func (x *TraceStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TraceResolutionResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Domain              string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	RecordType          string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Steps               []*TraceStep           `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"`                                                          // In order, starting at the root
+	Complete            bool                   `protobuf:"varint,4,opt,name=complete,proto3" json:"complete,omitempty"`                                                   // The trace reached an authoritative ANSWER, CNAME, NODATA or NXDOMAIN
+	Answer              []string               `protobuf:"bytes,5,rep,name=answer,proto3" json:"answer,omitempty"`                                                        // Final answer, if complete
+	Stored              []string               `protobuf:"bytes,6,rep,name=stored,proto3" json:"stored,omitempty"`                                                        // Records GetRecords returns for the domain and type; empty if not in the corpus
+	MissingFromDatabase []string               `protobuf:"bytes,7,rep,name=missing_from_database,json=missingFromDatabase,proto3" json:"missing_from_database,omitempty"` // Live records the database does not have
+	StaleInDatabase     []string               `protobuf:"bytes,8,rep,name=stale_in_database,json=staleInDatabase,proto3" json:"stale_in_database,omitempty"`             // Database records no longer served live
+	Drift               bool                   `protobuf:"varint,9,opt,name=drift,proto3" json:"drift,omitempty"`                                                         // The stored set differs from the traced answer
+	ElapsedMs           float64                `protobuf:"fixed64,10,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TraceResolutionResponse) Reset() {
+	*x = TraceResolutionResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceResolutionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResolutionResponse) ProtoMessage() {}
+
+func (x *TraceResolutionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResolutionResponse.ProtoReflect.Descriptor instead.
+func (*TraceResolutionResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *TraceResolutionResponse) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *TraceResolutionResponse) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *TraceResolutionResponse) GetSteps() []*TraceStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *TraceResolutionResponse) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *TraceResolutionResponse) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *TraceResolutionResponse) GetStored() []string {
+	if x != nil {
+		return x.Stored
+	}
+	return nil
+}
+
+func (x *TraceResolutionResponse) GetMissingFromDatabase() []string {
+	if x != nil {
+		return x.MissingFromDatabase
+	}
+	return nil
+}
+
+func (x *TraceResolutionResponse) GetStaleInDatabase() []string {
+	if x != nil {
+		return x.StaleInDatabase
+	}
+	return nil
+}
+
+func (x *TraceResolutionResponse) GetDrift() bool {
+	if x != nil {
+		return x.Drift
+	}
+	return false
+}
+
+func (x *TraceResolutionResponse) GetElapsedMs() float64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+type GetServiceRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                               // Owner name, e.g. _sip._tls.example.com or example.com
+	RecordType    []string               `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional; SRV, NAPTR or both (default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceRecordsRequest) Reset() {
+	*x = GetServiceRecordsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceRecordsRequest) ProtoMessage() {}
+
+func (x *GetServiceRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceRecordsRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *GetServiceRecordsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetServiceRecordsRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+type ServiceRecord struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                               // Owner name
+	RecordType  string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // SRV or NAPTR
+	Ttl         int32                  `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Source      string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                              // CZDS or QUERY
+	LastUpdated string                 `protobuf:"bytes,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // RFC 3339
+	// SRV
+	Service  string `protobuf:"bytes,6,opt,name=service,proto3" json:"service,omitempty"` // e.g. sip, from the _sip label
+	Proto    string `protobuf:"bytes,7,opt,name=proto,proto3" json:"proto,omitempty"`     // e.g. tls, from the _tls label
+	Priority int32  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	Weight   int32  `protobuf:"varint,9,opt,name=weight,proto3" json:"weight,omitempty"`
+	Port     int32  `protobuf:"varint,10,opt,name=port,proto3" json:"port,omitempty"`
+	Target   string `protobuf:"bytes,11,opt,name=target,proto3" json:"target,omitempty"`
+	// NAPTR
+	Order         int32  `protobuf:"varint,12,opt,name=order,proto3" json:"order,omitempty"`
+	Preference    int32  `protobuf:"varint,13,opt,name=preference,proto3" json:"preference,omitempty"`
+	Flags         string `protobuf:"bytes,14,opt,name=flags,proto3" json:"flags,omitempty"`
+	Services      string `protobuf:"bytes,15,opt,name=services,proto3" json:"services,omitempty"` // e.g. SIPS+D2T
+	Regexp        string `protobuf:"bytes,16,opt,name=regexp,proto3" json:"regexp,omitempty"`
+	Replacement   string `protobuf:"bytes,17,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceRecord) Reset() {
+	*x = ServiceRecord{}
+	mi := &file_bell_v1_bell_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceRecord) ProtoMessage() {}
+
+func (x *ServiceRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceRecord.ProtoReflect.Descriptor instead.
+func (*ServiceRecord) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *ServiceRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetTtl() int32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetProto() string {
+	if x != nil {
+		return x.Proto
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetPreference() int32 {
+	if x != nil {
+		return x.Preference
+	}
+	return 0
+}
+
+func (x *ServiceRecord) GetFlags() string {
+	if x != nil {
+		return x.Flags
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetServices() string {
+	if x != nil {
+		return x.Services
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetRegexp() string {
+	if x != nil {
+		return x.Regexp
+	}
+	return ""
+}
+
+func (x *ServiceRecord) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+type GetServiceRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Records       []*ServiceRecord       `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"` // SRV by priority then weight (heaviest first), then NAPTR by order then preference
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceRecordsResponse) Reset() {
+	*x = GetServiceRecordsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceRecordsResponse) ProtoMessage() {}
+
+func (x *GetServiceRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceRecordsResponse.ProtoReflect.Descriptor instead.
+func (*GetServiceRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *GetServiceRecordsResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetServiceRecordsResponse) GetRecords() []*ServiceRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type ValidateDANERequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"` // Host serving TLS, e.g. example.com or mail.example.com
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`    // Optional; defaults to 443. Ports 25 and 587 use SMTP STARTTLS
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDANERequest) Reset() {
+	*x = ValidateDANERequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDANERequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDANERequest) ProtoMessage() {}
+
+func (x *ValidateDANERequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDANERequest.ProtoReflect.Descriptor instead.
+func (*ValidateDANERequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ValidateDANERequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ValidateDANERequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type TLSAValidation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        string                 `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`                                  // TLSA record as stored
+	Usage         int32                  `protobuf:"varint,2,opt,name=usage,proto3" json:"usage,omitempty"`                                   // 0 PKIX-TA, 1 PKIX-EE, 2 DANE-TA, 3 DANE-EE
+	Selector      int32                  `protobuf:"varint,3,opt,name=selector,proto3" json:"selector,omitempty"`                             // 0 full certificate, 1 SubjectPublicKeyInfo
+	MatchingType  int32                  `protobuf:"varint,4,opt,name=matching_type,json=matchingType,proto3" json:"matching_type,omitempty"` // 0 exact, 1 SHA-256, 2 SHA-512
+	Match         bool                   `protobuf:"varint,5,opt,name=match,proto3" json:"match,omitempty"`                                   // The record matches the live chain under its usage
+	MatchedDepth  int32                  `protobuf:"varint,6,opt,name=matched_depth,json=matchedDepth,proto3" json:"matched_depth,omitempty"` // Chain position matched, 0 being the server certificate; -1 if none
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                                  // Why the record does not match, if it does not
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TLSAValidation) Reset() {
+	*x = TLSAValidation{}
+	mi := &file_bell_v1_bell_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLSAValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLSAValidation) ProtoMessage() {}
+
+func (x *TLSAValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLSAValidation.ProtoReflect.Descriptor instead.
+func (*TLSAValidation) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *TLSAValidation) GetRecord() string {
+	if x != nil {
+		return x.Record
+	}
+	return ""
+}
+
+func (x *TLSAValidation) GetUsage() int32 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+func (x *TLSAValidation) GetSelector() int32 {
+	if x != nil {
+		return x.Selector
+	}
+	return 0
+}
+
+func (x *TLSAValidation) GetMatchingType() int32 {
+	if x != nil {
+		return x.MatchingType
+	}
+	return 0
+}
+
+func (x *TLSAValidation) GetMatch() bool {
+	if x != nil {
+		return x.Match
+	}
+	return false
+}
+
+func (x *TLSAValidation) GetMatchedDepth() int32 {
+	if x != nil {
+		return x.MatchedDepth
+	}
+	return 0
+}
+
+func (x *TLSAValidation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ValidateDANEResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // TLSA owner name, e.g. _443._tcp.example.com
+	Records       []*TLSAValidation      `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	Valid         bool                   `protobuf:"varint,3,opt,name=valid,proto3" json:"valid,omitempty"`                          // At least one record matches
+	PkixValid     bool                   `protobuf:"varint,4,opt,name=pkix_valid,json=pkixValid,proto3" json:"pkix_valid,omitempty"` // The chain verifies against system roots for domain
+	PkixError     string                 `protobuf:"bytes,5,opt,name=pkix_error,json=pkixError,proto3" json:"pkix_error,omitempty"`
+	Chain         []string               `protobuf:"bytes,6,rep,name=chain,proto3" json:"chain,omitempty"` // Certificate subjects as presented, server certificate first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDANEResponse) Reset() {
+	*x = ValidateDANEResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDANEResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDANEResponse) ProtoMessage() {}
+
+func (x *ValidateDANEResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDANEResponse.ProtoReflect.Descriptor instead.
+func (*ValidateDANEResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *ValidateDANEResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ValidateDANEResponse) GetRecords() []*TLSAValidation {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ValidateDANEResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateDANEResponse) GetPkixValid() bool {
+	if x != nil {
+		return x.PkixValid
+	}
+	return false
+}
+
+func (x *ValidateDANEResponse) GetPkixError() string {
+	if x != nil {
+		return x.PkixError
+	}
+	return ""
+}
+
+func (x *ValidateDANEResponse) GetChain() []string {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+type GetPTRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`      // IPv4 or IPv6 address
+	Live          bool                   `protobuf:"varint,2,opt,name=live,proto3" json:"live,omitempty"` // Resolve through the configured DNS servers instead of reading ingested zones
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPTRRequest) Reset() {
+	*x = GetPTRRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPTRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPTRRequest) ProtoMessage() {}
+
+func (x *GetPTRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPTRRequest.ProtoReflect.Descriptor instead.
+func (*GetPTRRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *GetPTRRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GetPTRRequest) GetLive() bool {
+	if x != nil {
+		return x.Live
+	}
+	return false
+}
+
+type PTRRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	PtrName       string                 `protobuf:"bytes,2,opt,name=ptr_name,json=ptrName,proto3" json:"ptr_name,omitempty"` // Host name the address points at
+	Ttl           int32                  `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                              // CZDS for ingested zones, LIVE for live resolution
+	Zone          string                 `protobuf:"bytes,5,opt,name=zone,proto3" json:"zone,omitempty"`                                  // Reverse zone the record was ingested from; empty when live
+	LastUpdated   string                 `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PTRRecord) Reset() {
+	*x = PTRRecord{}
+	mi := &file_bell_v1_bell_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PTRRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PTRRecord) ProtoMessage() {}
+
+func (x *PTRRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PTRRecord.ProtoReflect.Descriptor instead.
+func (*PTRRecord) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *PTRRecord) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *PTRRecord) GetPtrName() string {
+	if x != nil {
+		return x.PtrName
+	}
+	return ""
+}
+
+func (x *PTRRecord) GetTtl() int32 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *PTRRecord) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PTRRecord) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *PTRRecord) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+type GetPTRResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"` // Address in canonical form
+	Records       []*PTRRecord           `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPTRResponse) Reset() {
+	*x = GetPTRResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPTRResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPTRResponse) ProtoMessage() {}
+
+func (x *GetPTRResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPTRResponse.ProtoReflect.Descriptor instead.
+func (*GetPTRResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *GetPTRResponse) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GetPTRResponse) GetRecords() []*PTRRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type GetPTRRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cidr          string                 `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`    // e.g. 192.0.2.0/24 or 2001:db8::/64
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // Optional; defaults to 1000, at most 10000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPTRRangeRequest) Reset() {
+	*x = GetPTRRangeRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPTRRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPTRRangeRequest) ProtoMessage() {}
+
+func (x *GetPTRRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPTRRangeRequest.ProtoReflect.Descriptor instead.
+func (*GetPTRRangeRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *GetPTRRangeRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *GetPTRRangeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetPTRRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cidr          string                 `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"` // Block in canonical form
+	Records       []*PTRRecord           `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"` // More records exist past limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPTRRangeResponse) Reset() {
+	*x = GetPTRRangeResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPTRRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPTRRangeResponse) ProtoMessage() {}
+
+func (x *GetPTRRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPTRRangeResponse.ProtoReflect.Descriptor instead.
+func (*GetPTRRangeResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *GetPTRRangeResponse) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *GetPTRRangeResponse) GetRecords() []*PTRRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *GetPTRRangeResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type GetDomainsByNameserverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nameserver    string                 `protobuf:"bytes,1,opt,name=nameserver,proto3" json:"nameserver,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Optional; defaults to the key's max_rows or 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; empty for the first. Later pages leave out domains inserted after the first was served
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDomainsByNameserverRequest) Reset() {
+	*x = GetDomainsByNameserverRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDomainsByNameserverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainsByNameserverRequest) ProtoMessage() {}
+
+func (x *GetDomainsByNameserverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainsByNameserverRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainsByNameserverRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *GetDomainsByNameserverRequest) GetNameserver() string {
+	if x != nil {
+		return x.Nameserver
+	}
+	return ""
+}
+
+func (x *GetDomainsByNameserverRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetDomainsByNameserverRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type DelegatedDomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`
+	Nameservers   []string               `protobuf:"bytes,3,rep,name=nameservers,proto3" json:"nameservers,omitempty"`              // All of the domain's nameservers
+	FirstSeen     string                 `protobuf:"bytes,4,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelegatedDomain) Reset() {
+	*x = DelegatedDomain{}
+	mi := &file_bell_v1_bell_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelegatedDomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegatedDomain) ProtoMessage() {}
+
+func (x *DelegatedDomain) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegatedDomain.ProtoReflect.Descriptor instead.
+func (*DelegatedDomain) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *DelegatedDomain) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DelegatedDomain) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *DelegatedDomain) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *DelegatedDomain) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+type GetDomainsByNameserverResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []*DelegatedDomain     `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	SkippedShards []string               `protobuf:"bytes,3,rep,name=skipped_shards,json=skippedShards,proto3" json:"skipped_shards,omitempty"`   // Unhealthy shards left out of the page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDomainsByNameserverResponse) Reset() {
+	*x = GetDomainsByNameserverResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDomainsByNameserverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainsByNameserverResponse) ProtoMessage() {}
+
+func (x *GetDomainsByNameserverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainsByNameserverResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainsByNameserverResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *GetDomainsByNameserverResponse) GetDomains() []*DelegatedDomain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *GetDomainsByNameserverResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetDomainsByNameserverResponse) GetSkippedShards() []string {
+	if x != nil {
+		return x.SkippedShards
+	}
+	return nil
+}
+
+type ListSubdomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Apex          string                 `protobuf:"bytes,1,opt,name=apex,proto3" json:"apex,omitempty"`                            // A leading "*." is ignored
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Optional; defaults to the key's max_rows or 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; empty for the first. Later pages leave out domains inserted after the first was served
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubdomainsRequest) Reset() {
+	*x = ListSubdomainsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubdomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubdomainsRequest) ProtoMessage() {}
+
+func (x *ListSubdomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubdomainsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubdomainsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *ListSubdomainsRequest) GetApex() string {
+	if x != nil {
+		return x.Apex
+	}
+	return ""
+}
+
+func (x *ListSubdomainsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubdomainsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type Subdomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,3,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`       // RFC 3339
+	LastUpdated   string                 `protobuf:"bytes,4,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subdomain) Reset() {
+	*x = Subdomain{}
+	mi := &file_bell_v1_bell_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subdomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subdomain) ProtoMessage() {}
+
+func (x *Subdomain) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subdomain.ProtoReflect.Descriptor instead.
+func (*Subdomain) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *Subdomain) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Subdomain) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *Subdomain) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *Subdomain) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+type ListSubdomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subdomains    []*Subdomain           `protobuf:"bytes,1,rep,name=subdomains,proto3" json:"subdomains,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubdomainsResponse) Reset() {
+	*x = ListSubdomainsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubdomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubdomainsResponse) ProtoMessage() {}
+
+func (x *ListSubdomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubdomainsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubdomainsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *ListSubdomainsResponse) GetSubdomains() []*Subdomain {
+	if x != nil {
+		return x.Subdomains
+	}
+	return nil
+}
+
+func (x *ListSubdomainsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SearchDomainsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Glob over the whole name: * matches any run of characters and ? any
+	// one. It must hold at least 3 consecutive other characters.
+	Pattern       string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Optional; defaults to the key's max_rows or 100, at most 1000
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; empty for the first. Later pages leave out domains inserted after the first was served
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchDomainsRequest) Reset() {
+	*x = SearchDomainsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchDomainsRequest) ProtoMessage() {}
+
+func (x *SearchDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchDomainsRequest.ProtoReflect.Descriptor instead.
+func (*SearchDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *SearchDomainsRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *SearchDomainsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchDomainsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type DomainMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Tld           string                 `protobuf:"bytes,2,opt,name=tld,proto3" json:"tld,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,3,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`       // RFC 3339
+	LastUpdated   string                 `protobuf:"bytes,4,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DomainMatch) Reset() {
+	*x = DomainMatch{}
+	mi := &file_bell_v1_bell_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainMatch) ProtoMessage() {}
+
+func (x *DomainMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainMatch.ProtoReflect.Descriptor instead.
+func (*DomainMatch) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *DomainMatch) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DomainMatch) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *DomainMatch) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *DomainMatch) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+type SearchDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []*DomainMatch         `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	SkippedShards []string               `protobuf:"bytes,3,rep,name=skipped_shards,json=skippedShards,proto3" json:"skipped_shards,omitempty"`   // Unhealthy shards left out of the page
+	Capped        bool                   `protobuf:"varint,4,opt,name=capped,proto3" json:"capped,omitempty"`                                     // The search stopped at the server's cap of 10000 domains
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchDomainsResponse) Reset() {
+	*x = SearchDomainsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchDomainsResponse) ProtoMessage() {}
+
+func (x *SearchDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchDomainsResponse.ProtoReflect.Descriptor instead.
+func (*SearchDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *SearchDomainsResponse) GetDomains() []*DomainMatch {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *SearchDomainsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *SearchDomainsResponse) GetSkippedShards() []string {
+	if x != nil {
+		return x.SkippedShards
+	}
+	return nil
+}
+
+func (x *SearchDomainsResponse) GetCapped() bool {
+	if x != nil {
+		return x.Capped
+	}
+	return false
+}
+
+type SearchRecordsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// POSIX extended regular expression matched against record_data,
+	// anywhere in it unless anchored
+	Pattern         string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	RecordType      []string `protobuf:"bytes,2,rep,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Optional; defaults to the key's record types, else every type
+	Tld             string   `protobuf:"bytes,3,opt,name=tld,proto3" json:"tld,omitempty"`                                 // Optional; searches only this TLD's domains
+	CaseInsensitive bool     `protobuf:"varint,4,opt,name=case_insensitive,json=caseInsensitive,proto3" json:"case_insensitive,omitempty"`
+	Limit           int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"` // Optional; defaults to the key's max_rows or the server's cap, which it cannot exceed
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchRecordsRequest) Reset() {
+	*x = SearchRecordsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRecordsRequest) ProtoMessage() {}
+
+func (x *SearchRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRecordsRequest.ProtoReflect.Descriptor instead.
+func (*SearchRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *SearchRecordsRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *SearchRecordsRequest) GetRecordType() []string {
+	if x != nil {
+		return x.RecordType
+	}
+	return nil
+}
+
+func (x *SearchRecordsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *SearchRecordsRequest) GetCaseInsensitive() bool {
+	if x != nil {
+		return x.CaseInsensitive
+	}
+	return false
+}
+
+func (x *SearchRecordsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*StreamedRecord      `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`                                  // In domain order
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`                             // The search stopped at the limit
+	TimedOut      bool                   `protobuf:"varint,3,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`               // A shard ran out of time; records holds the matches found before
+	SkippedShards []string               `protobuf:"bytes,4,rep,name=skipped_shards,json=skippedShards,proto3" json:"skipped_shards,omitempty"` // Unhealthy shards left out of the search
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRecordsResponse) Reset() {
+	*x = SearchRecordsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRecordsResponse) ProtoMessage() {}
+
+func (x *SearchRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRecordsResponse.ProtoReflect.Descriptor instead.
+func (*SearchRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *SearchRecordsResponse) GetRecords() []*StreamedRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *SearchRecordsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *SearchRecordsResponse) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
+func (x *SearchRecordsResponse) GetSkippedShards() []string {
+	if x != nil {
+		return x.SkippedShards
+	}
+	return nil
+}
+
+// KeyPreferences are defaults applied to requests made with a key when the
+// request leaves the corresponding field unset.
+type KeyPreferences struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RecordTypes      []string               `protobuf:"bytes,1,rep,name=record_types,json=recordTypes,proto3" json:"record_types,omitempty"`                // GetRecords record types
+	SourcePrecedence []string               `protobuf:"bytes,2,rep,name=source_precedence,json=sourcePrecedence,proto3" json:"source_precedence,omitempty"` // Merged GetRecords source order, highest first
+	MaxRows          int32                  `protobuf:"varint,3,opt,name=max_rows,json=maxRows,proto3" json:"max_rows,omitempty"`                           // Caps GetRecords records and defaults list limits; 0 for none
+	Timezone         string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`                                         // IANA zone for response timestamps, e.g. Europe/Berlin; empty for UTC
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *KeyPreferences) Reset() {
+	*x = KeyPreferences{}
+	mi := &file_bell_v1_bell_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyPreferences) ProtoMessage() {}
+
+func (x *KeyPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyPreferences.ProtoReflect.Descriptor instead.
+func (*KeyPreferences) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *KeyPreferences) GetRecordTypes() []string {
+	if x != nil {
+		return x.RecordTypes
+	}
+	return nil
+}
+
+func (x *KeyPreferences) GetSourcePrecedence() []string {
+	if x != nil {
+		return x.SourcePrecedence
+	}
+	return nil
+}
+
+func (x *KeyPreferences) GetMaxRows() int32 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+func (x *KeyPreferences) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type GetKeyPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyPreferencesRequest) Reset() {
+	*x = GetKeyPreferencesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyPreferencesRequest) ProtoMessage() {}
+
+func (x *GetKeyPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{116}
+}
+
+type SetKeyPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Preferences   *KeyPreferences        `protobuf:"bytes,1,opt,name=preferences,proto3" json:"preferences,omitempty"` // Replaces all stored preferences
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetKeyPreferencesRequest) Reset() {
+	*x = SetKeyPreferencesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetKeyPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetKeyPreferencesRequest) ProtoMessage() {}
+
+func (x *SetKeyPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetKeyPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*SetKeyPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *SetKeyPreferencesRequest) GetPreferences() *KeyPreferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+// ReportSchedule is a recurring report delivered to the key's owner. Daily
+// periods end at midnight UTC, weekly periods at midnight UTC on Monday.
+type ReportSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                             // Assigned on creation
+	Report        string                 `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"`                                      // NEW_DOMAINS (needs a watchlist or an organization watchlist), EMAIL_POSTURE (needs a watchlist or TLDs) or COVERAGE
+	Frequency     string                 `protobuf:"bytes,3,opt,name=frequency,proto3" json:"frequency,omitempty"`                                // DAILY or WEEKLY
+	Format        string                 `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`                                      // CSV, HTML or PDF
+	Delivery      string                 `protobuf:"bytes,5,opt,name=delivery,proto3" json:"delivery,omitempty"`                                  // EMAIL or STORAGE (reports.output_dir)
+	Destination   string                 `protobuf:"bytes,6,opt,name=destination,proto3" json:"destination,omitempty"`                            // Email address for EMAIL; unused for STORAGE
+	Watchlist     []string               `protobuf:"bytes,7,rep,name=watchlist,proto3" json:"watchlist,omitempty"`                                // Terms matched against domain names, e.g. "paypal"; NEW_DOMAINS adds the organization watchlist
+	Tlds          []string               `protobuf:"bytes,8,rep,name=tlds,proto3" json:"tlds,omitempty"`                                          // Restrict the report to these TLDs; all if empty
+	LastPeriodEnd string                 `protobuf:"bytes,9,opt,name=last_period_end,json=lastPeriodEnd,proto3" json:"last_period_end,omitempty"` // End of the last delivered period (RFC 3339); unset if none yet
+	LastStatus    string                 `protobuf:"bytes,10,opt,name=last_status,json=lastStatus,proto3" json:"last_status,omitempty"`           // SUCCESS, FAILED or RUNNING
+	LastError     string                 `protobuf:"bytes,11,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportSchedule) Reset() {
+	*x = ReportSchedule{}
+	mi := &file_bell_v1_bell_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportSchedule) ProtoMessage() {}
+
+func (x *ReportSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportSchedule.ProtoReflect.Descriptor instead.
+func (*ReportSchedule) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *ReportSchedule) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReportSchedule) GetReport() string {
+	if x != nil {
+		return x.Report
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetWatchlist() []string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return nil
+}
+
+func (x *ReportSchedule) GetTlds() []string {
+	if x != nil {
+		return x.Tlds
+	}
+	return nil
+}
+
+func (x *ReportSchedule) GetLastPeriodEnd() string {
+	if x != nil {
+		return x.LastPeriodEnd
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetLastStatus() string {
+	if x != nil {
+		return x.LastStatus
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ReportSchedule) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateReportScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *ReportSchedule        `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"` // id and the last_* fields are ignored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReportScheduleRequest) Reset() {
+	*x = CreateReportScheduleRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReportScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReportScheduleRequest) ProtoMessage() {}
+
+func (x *CreateReportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateReportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *CreateReportScheduleRequest) GetSchedule() *ReportSchedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type ListReportSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportSchedulesRequest) Reset() {
+	*x = ListReportSchedulesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportSchedulesRequest) ProtoMessage() {}
+
+func (x *ListReportSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[120]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListReportSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{120}
+}
+
+type ListReportSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*ReportSchedule      `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportSchedulesResponse) Reset() {
+	*x = ListReportSchedulesResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[121]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportSchedulesResponse) ProtoMessage() {}
+
+func (x *ListReportSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[121]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListReportSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *ListReportSchedulesResponse) GetSchedules() []*ReportSchedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+type DeleteReportScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReportScheduleRequest) Reset() {
+	*x = DeleteReportScheduleRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[122]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReportScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReportScheduleRequest) ProtoMessage() {}
+
+func (x *DeleteReportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[122]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{122}
+}
+
+func (x *DeleteReportScheduleRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteReportScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReportScheduleResponse) Reset() {
+	*x = DeleteReportScheduleResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[123]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReportScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReportScheduleResponse) ProtoMessage() {}
+
+func (x *DeleteReportScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[123]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReportScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReportScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{123}
+}
+
+// Organization groups API keys that share a daily request quota and a
+// watchlist. Quota use is counted as in billing exports: a call counts once
+// per TLD it names.
+type Organization struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DailyRequestQuota int64                  `protobuf:"varint,3,opt,name=daily_request_quota,json=dailyRequestQuota,proto3" json:"daily_request_quota,omitempty"` // Requests per UTC day across all member keys; 0 for unlimited
+	RequestsToday     int64                  `protobuf:"varint,4,opt,name=requests_today,json=requestsToday,proto3" json:"requests_today,omitempty"`               // As of the last usage flush, about a minute behind
+	Watchlist         []string               `protobuf:"bytes,5,rep,name=watchlist,proto3" json:"watchlist,omitempty"`                                             // Added to the watchlist of member keys' NEW_DOMAINS reports
+	Keys              []*OrganizationKey     `protobuf:"bytes,6,rep,name=keys,proto3" json:"keys,omitempty"`                                                       // Only returned to org admins
+	CreatedAt         string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                            // RFC 3339
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Organization) Reset() {
+	*x = Organization{}
+	mi := &file_bell_v1_bell_proto_msgTypes[124]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Organization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Organization) ProtoMessage() {}
+
+func (x *Organization) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[124]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Organization.ProtoReflect.Descriptor instead.
+func (*Organization) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *Organization) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Organization) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Organization) GetDailyRequestQuota() int64 {
+	if x != nil {
+		return x.DailyRequestQuota
+	}
+	return 0
+}
+
+func (x *Organization) GetRequestsToday() int64 {
+	if x != nil {
+		return x.RequestsToday
+	}
+	return 0
+}
+
+func (x *Organization) GetWatchlist() []string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return nil
+}
+
+func (x *Organization) GetKeys() []*OrganizationKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Organization) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type OrganizationKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Active        bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	OrgAdmin      bool                   `protobuf:"varint,4,opt,name=org_admin,json=orgAdmin,proto3" json:"org_admin,omitempty"`   // May manage the organization's keys and watchlist
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationKey) Reset() {
+	*x = OrganizationKey{}
+	mi := &file_bell_v1_bell_proto_msgTypes[125]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationKey) ProtoMessage() {}
+
+func (x *OrganizationKey) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[125]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationKey.ProtoReflect.Descriptor instead.
+func (*OrganizationKey) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{125}
+}
+
+func (x *OrganizationKey) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *OrganizationKey) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *OrganizationKey) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *OrganizationKey) GetOrgAdmin() bool {
+	if x != nil {
+		return x.OrgAdmin
+	}
+	return false
+}
+
+func (x *OrganizationKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type APIKey struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey              string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Description         string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Owner               string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`    // Person or service responsible for the key
+	Role                string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`      // Redaction role (redaction.roles)
+	Active              bool                   `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"` // False once revoked
+	Sandbox             bool                   `protobuf:"varint,6,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	OrganizationId      int32                  `protobuf:"varint,7,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"` // 0 outside any organization
+	OrgAdmin            bool                   `protobuf:"varint,8,opt,name=org_admin,json=orgAdmin,proto3" json:"org_admin,omitempty"`
+	CreatedAt           string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                   // RFC 3339
+	ExpiresAt           string                 `protobuf:"bytes,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                  // RFC 3339; empty if the key does not expire
+	RevokedAt           string                 `protobuf:"bytes,11,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`                                  // RFC 3339; empty unless revoked
+	RotatedTo           string                 `protobuf:"bytes,12,opt,name=rotated_to,json=rotatedTo,proto3" json:"rotated_to,omitempty"`                                  // Key that replaced this one, if rotated
+	MonthlyRequestQuota int64                  `protobuf:"varint,13,opt,name=monthly_request_quota,json=monthlyRequestQuota,proto3" json:"monthly_request_quota,omitempty"` // Requests per UTC calendar month; 0 for unlimited
+	MonthlyRecordQuota  int64                  `protobuf:"varint,14,opt,name=monthly_record_quota,json=monthlyRecordQuota,proto3" json:"monthly_record_quota,omitempty"`    // DNS records returned per UTC calendar month; 0 for unlimited
+	Scopes              []string               `protobuf:"bytes,15,rep,name=scopes,proto3" json:"scopes,omitempty"`                                                         // Granted scopes, e.g. read:records; empty for those of the role (authorization.roles)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_bell_v1_bell_proto_msgTypes[126]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[126]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{126}
+}
+
+func (x *APIKey) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *APIKey) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *APIKey) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *APIKey) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *APIKey) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *APIKey) GetSandbox() bool {
+	if x != nil {
+		return x.Sandbox
+	}
+	return false
+}
+
+func (x *APIKey) GetOrganizationId() int32 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *APIKey) GetOrgAdmin() bool {
+	if x != nil {
+		return x.OrgAdmin
+	}
+	return false
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetRotatedTo() string {
+	if x != nil {
+		return x.RotatedTo
+	}
+	return ""
+}
+
+func (x *APIKey) GetMonthlyRequestQuota() int64 {
+	if x != nil {
+		return x.MonthlyRequestQuota
+	}
+	return 0
+}
+
+func (x *APIKey) GetMonthlyRecordQuota() int64 {
+	if x != nil {
+		return x.MonthlyRecordQuota
+	}
+	return 0
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type CreateAPIKeyRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Description         string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Owner               string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Role                string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`                                                             // Optional redaction role
+	Sandbox             bool                   `protobuf:"varint,4,opt,name=sandbox,proto3" json:"sandbox,omitempty"`                                                      // Serve the key from the sandbox dataset
+	TtlSeconds          int64                  `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`                              // Lifetime of the key; 0 for no expiry
+	MonthlyRequestQuota int64                  `protobuf:"varint,6,opt,name=monthly_request_quota,json=monthlyRequestQuota,proto3" json:"monthly_request_quota,omitempty"` // 0 for unlimited
+	MonthlyRecordQuota  int64                  `protobuf:"varint,7,opt,name=monthly_record_quota,json=monthlyRecordQuota,proto3" json:"monthly_record_quota,omitempty"`    // 0 for unlimited
+	Scopes              []string               `protobuf:"bytes,8,rep,name=scopes,proto3" json:"scopes,omitempty"`                                                         // read, write or admin, optionally narrowed to an area: records, exports, reports, ingest, orgs, keys or ops (write:ingest); empty for those of the role
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[127]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[127]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{127}
+}
+
+func (x *CreateAPIKeyRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetSandbox() bool {
+	if x != nil {
+		return x.Sandbox
+	}
+	return false
+}
+
+func (x *CreateAPIKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateAPIKeyRequest) GetMonthlyRequestQuota() int64 {
+	if x != nil {
+		return x.MonthlyRequestQuota
+	}
+	return 0
+}
+
+func (x *CreateAPIKeyRequest) GetMonthlyRecordQuota() int64 {
+	if x != nil {
+		return x.MonthlyRecordQuota
+	}
+	return 0
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type RotateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	GraceSeconds  int64                  `protobuf:"varint,2,opt,name=grace_seconds,json=graceSeconds,proto3" json:"grace_seconds,omitempty"` // How long the old key keeps working; 0 retires it at once
+	TtlSeconds    int64                  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`       // Lifetime of the new key; 0 keeps the old key's remaining lifetime
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[128]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[128]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{128}
+}
+
+func (x *RotateAPIKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyRequest) GetGraceSeconds() int64 {
+	if x != nil {
+		return x.GraceSeconds
+	}
+	return 0
+}
+
+func (x *RotateAPIKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[129]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[129]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{129}
+}
+
+func (x *RevokeAPIKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type SetAPIKeyQuotaRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey              string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	MonthlyRequestQuota int64                  `protobuf:"varint,2,opt,name=monthly_request_quota,json=monthlyRequestQuota,proto3" json:"monthly_request_quota,omitempty"` // 0 for unlimited
+	MonthlyRecordQuota  int64                  `protobuf:"varint,3,opt,name=monthly_record_quota,json=monthlyRecordQuota,proto3" json:"monthly_record_quota,omitempty"`    // 0 for unlimited
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetAPIKeyQuotaRequest) Reset() {
+	*x = SetAPIKeyQuotaRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[130]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAPIKeyQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAPIKeyQuotaRequest) ProtoMessage() {}
+
+func (x *SetAPIKeyQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[130]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAPIKeyQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetAPIKeyQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{130}
+}
+
+func (x *SetAPIKeyQuotaRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetAPIKeyQuotaRequest) GetMonthlyRequestQuota() int64 {
+	if x != nil {
+		return x.MonthlyRequestQuota
+	}
+	return 0
+}
+
+func (x *SetAPIKeyQuotaRequest) GetMonthlyRecordQuota() int64 {
+	if x != nil {
+		return x.MonthlyRecordQuota
+	}
+	return 0
+}
+
+type SetAPIKeyScopesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"` // As in CreateAPIKeyRequest; empty for those of the role
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAPIKeyScopesRequest) Reset() {
+	*x = SetAPIKeyScopesRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[131]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAPIKeyScopesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAPIKeyScopesRequest) ProtoMessage() {}
+
+func (x *SetAPIKeyScopesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[131]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAPIKeyScopesRequest.ProtoReflect.Descriptor instead.
+func (*SetAPIKeyScopesRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{131}
+}
+
+func (x *SetAPIKeyScopesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetAPIKeyScopesRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type ListAPIKeysRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Owner           string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`                                             // Optional filter
+	IncludeInactive bool                   `protobuf:"varint,2,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"` // Include revoked, expired and rotated-out keys
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[132]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[132]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{132}
+}
+
+func (x *ListAPIKeysRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ListAPIKeysRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*APIKey              `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[133]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[133]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{133}
+}
+
+func (x *ListAPIKeysResponse) GetKeys() []*APIKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetOrganizationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrganizationRequest) Reset() {
+	*x = GetOrganizationRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[134]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationRequest) ProtoMessage() {}
+
+func (x *GetOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[134]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*GetOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{134}
+}
+
+type CreateOrganizationKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"` // e.g. the team member or service using the key
+	OrgAdmin      bool                   `protobuf:"varint,2,opt,name=org_admin,json=orgAdmin,proto3" json:"org_admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrganizationKeyRequest) Reset() {
+	*x = CreateOrganizationKeyRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[135]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrganizationKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrganizationKeyRequest) ProtoMessage() {}
+
+func (x *CreateOrganizationKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[135]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrganizationKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrganizationKeyRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{135}
+}
+
+func (x *CreateOrganizationKeyRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateOrganizationKeyRequest) GetOrgAdmin() bool {
+	if x != nil {
+		return x.OrgAdmin
+	}
+	return false
+}
+
+type UpdateOrganizationKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // Replaces the stored description
+	Active        bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	OrgAdmin      bool                   `protobuf:"varint,4,opt,name=org_admin,json=orgAdmin,proto3" json:"org_admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrganizationKeyRequest) Reset() {
+	*x = UpdateOrganizationKeyRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[136]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrganizationKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrganizationKeyRequest) ProtoMessage() {}
+
+func (x *UpdateOrganizationKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[136]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrganizationKeyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrganizationKeyRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{136}
+}
+
+func (x *UpdateOrganizationKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *UpdateOrganizationKeyRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateOrganizationKeyRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *UpdateOrganizationKeyRequest) GetOrgAdmin() bool {
+	if x != nil {
+		return x.OrgAdmin
+	}
+	return false
+}
+
+type SetOrganizationWatchlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Watchlist     []string               `protobuf:"bytes,1,rep,name=watchlist,proto3" json:"watchlist,omitempty"` // Terms matched against domain names, e.g. "paypal"; terms with * or ? are globs matched against the whole name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOrganizationWatchlistRequest) Reset() {
+	*x = SetOrganizationWatchlistRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[137]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOrganizationWatchlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOrganizationWatchlistRequest) ProtoMessage() {}
+
+func (x *SetOrganizationWatchlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[137]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOrganizationWatchlistRequest.ProtoReflect.Descriptor instead.
+func (*SetOrganizationWatchlistRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{137}
+}
+
+func (x *SetOrganizationWatchlistRequest) GetWatchlist() []string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return nil
+}
+
+type ImportWatchlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`                // CSV (one domain or pattern in the first column per row; a header row and # comments are skipped) or STIX (2.1 bundle JSON)
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`              // The file; STIX domain-name objects and indicators comparing domain-name:value with = or LIKE are imported
+	Replace       bool                   `protobuf:"varint,3,opt,name=replace,proto3" json:"replace,omitempty"`             // Replace the watchlist instead of adding to it
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Validate and report without changing the watchlist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWatchlistRequest) Reset() {
+	*x = ImportWatchlistRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[138]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWatchlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWatchlistRequest) ProtoMessage() {}
+
+func (x *ImportWatchlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[138]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWatchlistRequest.ProtoReflect.Descriptor instead.
+func (*ImportWatchlistRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{138}
+}
+
+func (x *ImportWatchlistRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportWatchlistRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ImportWatchlistRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+func (x *ImportWatchlistRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RejectedWatchlistEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"` // CSV "line 12" or STIX object ID
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedWatchlistEntry) Reset() {
+	*x = RejectedWatchlistEntry{}
+	mi := &file_bell_v1_bell_proto_msgTypes[139]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedWatchlistEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedWatchlistEntry) ProtoMessage() {}
+
+func (x *RejectedWatchlistEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[139]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedWatchlistEntry.ProtoReflect.Descriptor instead.
+func (*RejectedWatchlistEntry) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{139}
+}
+
+func (x *RejectedWatchlistEntry) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *RejectedWatchlistEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *RejectedWatchlistEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ImportWatchlistResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Organization  *Organization             `protobuf:"bytes,1,opt,name=organization,proto3" json:"organization,omitempty"` // After the import; unchanged on a dry run
+	Added         int32                     `protobuf:"varint,2,opt,name=added,proto3" json:"added,omitempty"`              // Terms not already in the watchlist (every distinct term with replace)
+	Duplicates    int32                     `protobuf:"varint,3,opt,name=duplicates,proto3" json:"duplicates,omitempty"`    // Terms repeated in the file or already in the watchlist
+	RejectedCount int32                     `protobuf:"varint,4,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	Rejected      []*RejectedWatchlistEntry `protobuf:"bytes,5,rep,name=rejected,proto3" json:"rejected,omitempty"` // The first 100 rejected entries
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWatchlistResponse) Reset() {
+	*x = ImportWatchlistResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[140]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWatchlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWatchlistResponse) ProtoMessage() {}
+
+func (x *ImportWatchlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[140]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWatchlistResponse.ProtoReflect.Descriptor instead.
+func (*ImportWatchlistResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{140}
+}
+
+func (x *ImportWatchlistResponse) GetOrganization() *Organization {
+	if x != nil {
+		return x.Organization
+	}
+	return nil
+}
+
+func (x *ImportWatchlistResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ImportWatchlistResponse) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *ImportWatchlistResponse) GetRejectedCount() int32 {
+	if x != nil {
+		return x.RejectedCount
+	}
+	return 0
+}
+
+func (x *ImportWatchlistResponse) GetRejected() []*RejectedWatchlistEntry {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+type ImportDomainsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Domains []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"` // Registered domains or hostnames, e.g. example.co.uk; at most 10000 per call
+	// Restrict added domains bell did not know, or already restricted to other
+	// organizations, to the organization's keys. Domains of the public corpus
+	// stay public.
+	Private       bool `protobuf:"varint,2,opt,name=private,proto3" json:"private,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportDomainsRequest) Reset() {
+	*x = ImportDomainsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[141]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDomainsRequest) ProtoMessage() {}
+
+func (x *ImportDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[141]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDomainsRequest.ProtoReflect.Descriptor instead.
+func (*ImportDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{141}
+}
+
+func (x *ImportDomainsRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *ImportDomainsRequest) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+type RejectedDomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedDomain) Reset() {
+	*x = RejectedDomain{}
+	mi := &file_bell_v1_bell_proto_msgTypes[142]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedDomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedDomain) ProtoMessage() {}
+
+func (x *RejectedDomain) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[142]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedDomain.ProtoReflect.Descriptor instead.
+func (*RejectedDomain) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{142}
+}
+
+func (x *RejectedDomain) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RejectedDomain) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ImportDomainsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Added          int32                  `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`                                         // Domains the organization did not track before
+	AlreadyTracked int32                  `protobuf:"varint,2,opt,name=already_tracked,json=alreadyTracked,proto3" json:"already_tracked,omitempty"` // Domains repeated in the request or tracked before
+	NewDomains     int32                  `protobuf:"varint,3,opt,name=new_domains,json=newDomains,proto3" json:"new_domains,omitempty"`             // Added domains bell did not know, e.g. from TLDs without zone-file access
+	RejectedCount  int32                  `protobuf:"varint,4,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	Rejected       []*RejectedDomain      `protobuf:"bytes,5,rep,name=rejected,proto3" json:"rejected,omitempty"`                                 // The first 100 rejected domains
+	PublicDomains  int32                  `protobuf:"varint,6,opt,name=public_domains,json=publicDomains,proto3" json:"public_domains,omitempty"` // Added domains of a private import left public, as bell already served them to everyone
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportDomainsResponse) Reset() {
+	*x = ImportDomainsResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[143]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDomainsResponse) ProtoMessage() {}
+
+func (x *ImportDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[143]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDomainsResponse.ProtoReflect.Descriptor instead.
+func (*ImportDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{143}
+}
+
+func (x *ImportDomainsResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ImportDomainsResponse) GetAlreadyTracked() int32 {
+	if x != nil {
+		return x.AlreadyTracked
+	}
+	return 0
+}
+
+func (x *ImportDomainsResponse) GetNewDomains() int32 {
+	if x != nil {
+		return x.NewDomains
+	}
+	return 0
+}
+
+func (x *ImportDomainsResponse) GetRejectedCount() int32 {
+	if x != nil {
+		return x.RejectedCount
+	}
+	return 0
+}
+
+func (x *ImportDomainsResponse) GetRejected() []*RejectedDomain {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+func (x *ImportDomainsResponse) GetPublicDomains() int32 {
+	if x != nil {
+		return x.PublicDomains
+	}
+	return 0
+}
+
+type SetDomainRestrictionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Domain          string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	OrganizationIds []int32                `protobuf:"varint,2,rep,packed,name=organization_ids,json=organizationIds,proto3" json:"organization_ids,omitempty"` // Organizations whose keys may read the domain; empty makes it public
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetDomainRestrictionRequest) Reset() {
+	*x = SetDomainRestrictionRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[144]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDomainRestrictionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDomainRestrictionRequest) ProtoMessage() {}
+
+func (x *SetDomainRestrictionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[144]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDomainRestrictionRequest.ProtoReflect.Descriptor instead.
+func (*SetDomainRestrictionRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{144}
+}
+
+func (x *SetDomainRestrictionRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetDomainRestrictionRequest) GetOrganizationIds() []int32 {
+	if x != nil {
+		return x.OrganizationIds
+	}
+	return nil
+}
+
+type DomainRestriction struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Domain          string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	OrganizationIds []int32                `protobuf:"varint,2,rep,packed,name=organization_ids,json=organizationIds,proto3" json:"organization_ids,omitempty"` // Empty for a public domain
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DomainRestriction) Reset() {
+	*x = DomainRestriction{}
+	mi := &file_bell_v1_bell_proto_msgTypes[145]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainRestriction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainRestriction) ProtoMessage() {}
+
+func (x *DomainRestriction) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[145]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainRestriction.ProtoReflect.Descriptor instead.
+func (*DomainRestriction) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{145}
+}
+
+func (x *DomainRestriction) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DomainRestriction) GetOrganizationIds() []int32 {
+	if x != nil {
+		return x.OrganizationIds
+	}
+	return nil
+}
+
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"` // Optional; YYYY-MM (UTC), defaults to the current month
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[146]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[146]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{146}
+}
+
+func (x *GetUsageRequest) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+type DailyUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"` // UTC date, YYYY-MM-DD
+	Requests      int64                  `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors        int64                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	Records       int64                  `protobuf:"varint,4,opt,name=records,proto3" json:"records,omitempty"` // DNS records returned
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyUsage) Reset() {
+	*x = DailyUsage{}
+	mi := &file_bell_v1_bell_proto_msgTypes[147]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyUsage) ProtoMessage() {}
+
+func (x *DailyUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[147]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyUsage.ProtoReflect.Descriptor instead.
+func (*DailyUsage) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{147}
+}
+
+func (x *DailyUsage) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *DailyUsage) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *DailyUsage) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *DailyUsage) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+// GetUsageResponse counts requests as quotas and billing exports do: once
+// per TLD a call names. Counts reach the database about a minute after the
+// calls.
+type GetUsageResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Month               string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"` // YYYY-MM
+	Requests            int64                  `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors              int64                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	Records             int64                  `protobuf:"varint,4,opt,name=records,proto3" json:"records,omitempty"`
+	MonthlyRequestQuota int64                  `protobuf:"varint,5,opt,name=monthly_request_quota,json=monthlyRequestQuota,proto3" json:"monthly_request_quota,omitempty"` // 0 for unlimited
+	MonthlyRecordQuota  int64                  `protobuf:"varint,6,opt,name=monthly_record_quota,json=monthlyRecordQuota,proto3" json:"monthly_record_quota,omitempty"`    // 0 for unlimited
+	Days                []*DailyUsage          `protobuf:"bytes,7,rep,name=days,proto3" json:"days,omitempty"`                                                             // Oldest first; days without calls are left out
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[148]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[148]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{148}
+}
+
+func (x *GetUsageResponse) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *GetUsageResponse) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetMonthlyRequestQuota() int64 {
+	if x != nil {
+		return x.MonthlyRequestQuota
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetMonthlyRecordQuota() int64 {
+	if x != nil {
+		return x.MonthlyRecordQuota
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetDays() []*DailyUsage {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type GetOrganizationUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Days          int32                  `protobuf:"varint,1,opt,name=days,proto3" json:"days,omitempty"` // Optional; days back from today (UTC), defaults to 30, at most 90
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrganizationUsageRequest) Reset() {
+	*x = GetOrganizationUsageRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[149]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationUsageRequest) ProtoMessage() {}
+
+func (x *GetOrganizationUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[149]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetOrganizationUsageRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{149}
+}
+
+func (x *GetOrganizationUsageRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type OrganizationUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"` // UTC date, YYYY-MM-DD
+	ApiKey        string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Requests      int64                  `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors        int64                  `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationUsage) Reset() {
+	*x = OrganizationUsage{}
+	mi := &file_bell_v1_bell_proto_msgTypes[150]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationUsage) ProtoMessage() {}
+
+func (x *OrganizationUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[150]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationUsage.ProtoReflect.Descriptor instead.
+func (*OrganizationUsage) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{150}
+}
+
+func (x *OrganizationUsage) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *OrganizationUsage) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *OrganizationUsage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *OrganizationUsage) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *OrganizationUsage) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+type GetOrganizationUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usage         []*OrganizationUsage   `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"` // Newest day first, then by key
+	TotalRequests int64                  `protobuf:"varint,2,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrganizationUsageResponse) Reset() {
+	*x = GetOrganizationUsageResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[151]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationUsageResponse) ProtoMessage() {}
+
+func (x *GetOrganizationUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[151]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetOrganizationUsageResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{151}
+}
+
+func (x *GetOrganizationUsageResponse) GetUsage() []*OrganizationUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *GetOrganizationUsageResponse) GetTotalRequests() int64 {
+	if x != nil {
+		return x.TotalRequests
+	}
+	return 0
+}
+
+type CreateOrganizationRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DailyRequestQuota int64                  `protobuf:"varint,2,opt,name=daily_request_quota,json=dailyRequestQuota,proto3" json:"daily_request_quota,omitempty"` // 0 for unlimited
+	AdminApiKey       string                 `protobuf:"bytes,3,opt,name=admin_api_key,json=adminApiKey,proto3" json:"admin_api_key,omitempty"`                    // Existing key outside any organization
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateOrganizationRequest) Reset() {
+	*x = CreateOrganizationRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[152]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrganizationRequest) ProtoMessage() {}
+
+func (x *CreateOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[152]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{152}
+}
+
+func (x *CreateOrganizationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOrganizationRequest) GetDailyRequestQuota() int64 {
+	if x != nil {
+		return x.DailyRequestQuota
+	}
+	return 0
+}
+
+func (x *CreateOrganizationRequest) GetAdminApiKey() string {
+	if x != nil {
+		return x.AdminApiKey
+	}
+	return ""
+}
+
+type SetOrganizationQuotaRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId    int32                  `protobuf:"varint,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	DailyRequestQuota int64                  `protobuf:"varint,2,opt,name=daily_request_quota,json=dailyRequestQuota,proto3" json:"daily_request_quota,omitempty"` // 0 for unlimited
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetOrganizationQuotaRequest) Reset() {
+	*x = SetOrganizationQuotaRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[153]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOrganizationQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOrganizationQuotaRequest) ProtoMessage() {}
+
+func (x *SetOrganizationQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[153]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOrganizationQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetOrganizationQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{153}
+}
+
+func (x *SetOrganizationQuotaRequest) GetOrganizationId() int32 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *SetOrganizationQuotaRequest) GetDailyRequestQuota() int64 {
+	if x != nil {
+		return x.DailyRequestQuota
+	}
+	return 0
+}
+
+type TailEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []string               `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`             // Optional; czds, query, ingest
+	Kinds         []string               `protobuf:"bytes,2,rep,name=kinds,proto3" json:"kinds,omitempty"`                 // Optional; zone_started, zone_completed, zone_failed, batch_committed, batch_failed, error, lease_expired, alert, throttled, unthrottled, record_set_changed
+	Tld           string                 `protobuf:"bytes,3,opt,name=tld,proto3" json:"tld,omitempty"`                     // Optional; only events for this TLD
+	Significances []string               `protobuf:"bytes,4,rep,name=significances,proto3" json:"significances,omitempty"` // Optional; only record_set_changed events of these significances (NEW, COSMETIC, MINOR, ASN_CHANGED, MX_PROVIDER_CHANGED, UNCLASSIFIED)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailEventsRequest) Reset() {
+	*x = TailEventsRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[154]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailEventsRequest) ProtoMessage() {}
+
+func (x *TailEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[154]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailEventsRequest.ProtoReflect.Descriptor instead.
+func (*TailEventsRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{154}
+}
+
+func (x *TailEventsRequest) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *TailEventsRequest) GetKinds() []string {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+func (x *TailEventsRequest) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *TailEventsRequest) GetSignificances() []string {
+	if x != nil {
+		return x.Significances
+	}
+	return nil
+}
+
+type IngestEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          string                 `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`     // RFC 3339 with fractional seconds
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // czds, query or ingest
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Tld           string                 `protobuf:"bytes,4,opt,name=tld,proto3" json:"tld,omitempty"`
+	Domain        string                 `protobuf:"bytes,5,opt,name=domain,proto3" json:"domain,omitempty"`
+	Count         int64                  `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"` // Records or domains, depending on kind
+	Message       string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+	RecordType    string                 `protobuf:"bytes,8,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"` // Set on record_set_changed
+	Version       int64                  `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`                        // New record-set version on record_set_changed
+	Significance  string                 `protobuf:"bytes,10,opt,name=significance,proto3" json:"significance,omitempty"`              // Of the change on record_set_changed: NEW, COSMETIC (TTL only), MINOR, ASN_CHANGED, MX_PROVIDER_CHANGED or UNCLASSIFIED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestEvent) Reset() {
+	*x = IngestEvent{}
+	mi := &file_bell_v1_bell_proto_msgTypes[155]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestEvent) ProtoMessage() {}
+
+func (x *IngestEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[155]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestEvent.ProtoReflect.Descriptor instead.
+func (*IngestEvent) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{155}
+}
+
+func (x *IngestEvent) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *IngestEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *IngestEvent) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *IngestEvent) GetSignificance() string {
+	if x != nil {
+		return x.Significance
+	}
+	return ""
+}
+
+type ListNameserverReputationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`                                   // Optional; only hosts ending in this suffix (e.g. "example.net")
+	SkippedOnly   bool                   `protobuf:"varint,2,opt,name=skipped_only,json=skippedOnly,proto3" json:"skipped_only,omitempty"` // Only hosts the worker is currently skipping
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                // Optional; defaults to 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNameserverReputationRequest) Reset() {
+	*x = ListNameserverReputationRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[156]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNameserverReputationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNameserverReputationRequest) ProtoMessage() {}
+
+func (x *ListNameserverReputationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[156]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNameserverReputationRequest.ProtoReflect.Descriptor instead.
+func (*ListNameserverReputationRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{156}
+}
+
+func (x *ListNameserverReputationRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ListNameserverReputationRequest) GetSkippedOnly() bool {
+	if x != nil {
+		return x.SkippedOnly
+	}
+	return false
+}
+
+func (x *ListNameserverReputationRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type NameserverReputation struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Host                string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Score               float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"` // Exponentially weighted success rate (0-1)
+	Queries             int64                  `protobuf:"varint,3,opt,name=queries,proto3" json:"queries,omitempty"`
+	Timeouts            int64                  `protobuf:"varint,4,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	Failures            int64                  `protobuf:"varint,5,opt,name=failures,proto3" json:"failures,omitempty"` // Non-timeout errors plus SERVFAIL and REFUSED answers
+	ConsecutiveTimeouts int32                  `protobuf:"varint,6,opt,name=consecutive_timeouts,json=consecutiveTimeouts,proto3" json:"consecutive_timeouts,omitempty"`
+	AvgRttMs            float64                `protobuf:"fixed64,7,opt,name=avg_rtt_ms,json=avgRttMs,proto3" json:"avg_rtt_ms,omitempty"`
+	LastSuccess         string                 `protobuf:"bytes,8,opt,name=last_success,json=lastSuccess,proto3" json:"last_success,omitempty"` // RFC 3339; empty if never
+	LastFailure         string                 `protobuf:"bytes,9,opt,name=last_failure,json=lastFailure,proto3" json:"last_failure,omitempty"` // RFC 3339; empty if never
+	SkipUntil           string                 `protobuf:"bytes,10,opt,name=skip_until,json=skipUntil,proto3" json:"skip_until,omitempty"`      // RFC 3339; empty if not skipped
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NameserverReputation) Reset() {
+	*x = NameserverReputation{}
+	mi := &file_bell_v1_bell_proto_msgTypes[157]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameserverReputation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameserverReputation) ProtoMessage() {}
+
+func (x *NameserverReputation) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[157]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameserverReputation.ProtoReflect.Descriptor instead.
+func (*NameserverReputation) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{157}
+}
+
+func (x *NameserverReputation) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *NameserverReputation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetQueries() int64 {
+	if x != nil {
+		return x.Queries
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetTimeouts() int64 {
+	if x != nil {
+		return x.Timeouts
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetFailures() int64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetConsecutiveTimeouts() int32 {
+	if x != nil {
+		return x.ConsecutiveTimeouts
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetAvgRttMs() float64 {
+	if x != nil {
+		return x.AvgRttMs
+	}
+	return 0
+}
+
+func (x *NameserverReputation) GetLastSuccess() string {
+	if x != nil {
+		return x.LastSuccess
+	}
+	return ""
+}
+
+func (x *NameserverReputation) GetLastFailure() string {
+	if x != nil {
+		return x.LastFailure
+	}
+	return ""
+}
+
+func (x *NameserverReputation) GetSkipUntil() string {
+	if x != nil {
+		return x.SkipUntil
+	}
+	return ""
+}
+
+type ListNameserverReputationResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Nameservers   []*NameserverReputation `protobuf:"bytes,1,rep,name=nameservers,proto3" json:"nameservers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNameserverReputationResponse) Reset() {
+	*x = ListNameserverReputationResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[158]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNameserverReputationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNameserverReputationResponse) ProtoMessage() {}
+
+func (x *ListNameserverReputationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[158]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNameserverReputationResponse.ProtoReflect.Descriptor instead.
+func (*ListNameserverReputationResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{158}
+}
+
+func (x *ListNameserverReputationResponse) GetNameservers() []*NameserverReputation {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+type ListDNSServersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDNSServersRequest) Reset() {
+	*x = ListDNSServersRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[159]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDNSServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDNSServersRequest) ProtoMessage() {}
+
+func (x *ListDNSServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[159]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDNSServersRequest.ProtoReflect.Descriptor instead.
+func (*ListDNSServersRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{159}
+}
+
+type DNSServer struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Address             string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Transport           string                 `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`                                                 // Transport health probes use: udp, tcp, tcp-tls or https
+	Healthy             bool                   `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`                                                    // In rotation; unhealthy upstreams are used only if none is healthy
+	ConsecutiveFailures int32                  `protobuf:"varint,4,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"` // Failed health probes in a row
+	LastError           string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastChecked         string                 `protobuf:"bytes,6,opt,name=last_checked,json=lastChecked,proto3" json:"last_checked,omitempty"` // RFC 3339; empty if never probed
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DNSServer) Reset() {
+	*x = DNSServer{}
+	mi := &file_bell_v1_bell_proto_msgTypes[160]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSServer) ProtoMessage() {}
+
+func (x *DNSServer) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[160]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSServer.ProtoReflect.Descriptor instead.
+func (*DNSServer) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{160}
+}
+
+func (x *DNSServer) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *DNSServer) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *DNSServer) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *DNSServer) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *DNSServer) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DNSServer) GetLastChecked() string {
+	if x != nil {
+		return x.LastChecked
+	}
+	return ""
+}
+
+type ListDNSServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*DNSServer           `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDNSServersResponse) Reset() {
+	*x = ListDNSServersResponse{}
+	mi := &file_bell_v1_bell_proto_msgTypes[161]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDNSServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDNSServersResponse) ProtoMessage() {}
+
+func (x *ListDNSServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[161]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDNSServersResponse.ProtoReflect.Descriptor instead.
+func (*ListDNSServersResponse) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{161}
+}
+
+func (x *ListDNSServersResponse) GetServers() []*DNSServer {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type UpdateDNSServersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Upstreams to add, as in dns_query.dns_servers: [udp|tcp|tls|https://]host[:port]
+	Add           []string `protobuf:"bytes,1,rep,name=add,proto3" json:"add,omitempty"`
+	Remove        []string `protobuf:"bytes,2,rep,name=remove,proto3" json:"remove,omitempty"` // Upstreams to remove, configured or added
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDNSServersRequest) Reset() {
+	*x = UpdateDNSServersRequest{}
+	mi := &file_bell_v1_bell_proto_msgTypes[162]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDNSServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDNSServersRequest) ProtoMessage() {}
+
+func (x *UpdateDNSServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bell_v1_bell_proto_msgTypes[162]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDNSServersRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDNSServersRequest) Descriptor() ([]byte, []int) {
+	return file_bell_v1_bell_proto_rawDescGZIP(), []int{162}
+}
+
+func (x *UpdateDNSServersRequest) GetAdd() []string {
+	if x != nil {
+		return x.Add
+	}
+	return nil
+}
+
+func (x *UpdateDNSServersRequest) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+var File_bell_v1_bell_proto protoreflect.FileDescriptor
+
+const file_bell_v1_bell_proto_rawDesc = "" +
+	"\n" +
+	"\x12bell/v1/bell.proto\x12\abell.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"[\n" +
+	"\x13AuthenticateRequest\x12D\n" +
+	"\aapi_key\x18\x01 \x01(\tB+\x92A(J&\"550e8400-e29b-41d4-a716-446655440000\"R\x06apiKey\"F\n" +
+	"\x14AuthenticateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x17\n" +
+	"\x15GetSigningKeysRequest\"\x9e\x01\n" +
+	"\n" +
+	"SigningKey\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\x12$\n" +
+	"\x0epublic_key_pem\x18\x04 \x01(\tR\fpublicKeyPem\x12\x16\n" +
+	"\x06active\x18\x05 \x01(\bR\x06active\"A\n" +
+	"\x16GetSigningKeysResponse\x12'\n" +
+	"\x04keys\x18\x01 \x03(\v2\x13.bell.v1.SigningKeyR\x04keys\"\x8c\x02\n" +
+	"\x11GetRecordsRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06merged\x18\x03 \x01(\bR\x06merged\x12%\n" +
+	"\x0esnapshot_token\x18\x04 \x01(\tR\rsnapshotToken\x12*\n" +
+	"\x05order\x18\x05 \x01(\x0e2\x14.bell.v1.RecordOrderR\x05order\x12#\n" +
+	"\rknown_version\x18\x06 \x01(\x03R\fknownVersion\x12.\n" +
+	"\x13include_geo_answers\x18\a \x01(\bR\x11includeGeoAnswers\"\x95\x03\n" +
+	"\tDNSRecord\x12\x1b\n" +
+	"\tdomain_id\x18\x01 \x01(\x05R\bdomainId\x12*\n" +
+	"\vrecord_type\x18\x02 \x01(\tB\t\x92A\x06J\x04\"MX\"R\n" +
+	"recordType\x12<\n" +
+	"\vrecord_data\x18\x03 \x01(\tB\x1b\x92A\x18J\x16\"10 mail.example.com.\"R\n" +
+	"recordData\x12\x1b\n" +
+	"\x03ttl\x18\x04 \x01(\x05B\t\x92A\x06J\x043600R\x03ttl\x12$\n" +
+	"\x06source\x18\x05 \x01(\tB\f\x92A\tJ\a\"QUERY\"R\x06source\x12!\n" +
+	"\flast_updated\x18\x06 \x01(\tR\vlastUpdated\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\x12+\n" +
+	"\x11observation_count\x18\b \x01(\x05R\x10observationCount\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\t \x01(\tR\tfirstSeen\x12\x1b\n" +
+	"\tlast_seen\x18\n" +
+	" \x01(\tR\blastSeen\x12\x18\n" +
+	"\asources\x18\v \x03(\tR\asources\"\x97\x01\n" +
+	"\bDGAScore\x12\x18\n" +
+	"\aentropy\x18\x01 \x01(\x01R\aentropy\x12\x1f\n" +
+	"\vngram_score\x18\x02 \x01(\x01R\n" +
+	"ngramScore\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12\x1d\n" +
+	"\n" +
+	"likely_dga\x18\x04 \x01(\bR\tlikelyDga\x12\x1b\n" +
+	"\tscored_at\x18\x05 \x01(\tR\bscoredAt\"\x82\x03\n" +
+	"\x12GetRecordsResponse\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.bell.v1.DNSRecordR\arecords\x12#\n" +
+	"\x03dga\x18\x02 \x01(\v2\x11.bell.v1.DGAScoreR\x03dga\x128\n" +
+	"\n" +
+	"provenance\x18\x03 \x03(\v2\x18.bell.v1.MergeProvenanceR\n" +
+	"provenance\x12%\n" +
+	"\x0esnapshot_token\x18\x04 \x01(\tR\rsnapshotToken\x12\x1c\n" +
+	"\ttruncated\x18\x05 \x01(\bR\ttruncated\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12!\n" +
+	"\fnot_modified\x18\a \x01(\bR\vnotModified\x123\n" +
+	"\vgeo_answers\x18\b \x03(\v2\x12.bell.v1.GeoAnswerR\n" +
+	"geoAnswers\x12(\n" +
+	"\x10data_age_seconds\x18\t \x01(\x03R\x0edataAgeSeconds\"\x8a\x01\n" +
+	"\x17GetRecordHistoryRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12)\n" +
+	"\vrecord_type\x18\x02 \x01(\tB\b\x92A\x05J\x03\"A\"R\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xd5\x01\n" +
+	"\x12RecordHistoryEntry\x12\x1f\n" +
+	"\vrecord_data\x18\x01 \x01(\tR\n" +
+	"recordData\x12\x10\n" +
+	"\x03ttl\x18\x02 \x01(\x05R\x03ttl\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x04 \x01(\tR\tfirstSeen\x12\x1b\n" +
+	"\tlast_seen\x18\x05 \x01(\tR\blastSeen\x12\x1e\n" +
+	"\n" +
+	"superseded\x18\x06 \x01(\bR\n" +
+	"superseded\x12\x18\n" +
+	"\acurrent\x18\a \x01(\bR\acurrent\"\xa8\x01\n" +
+	"\x18GetRecordHistoryResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x125\n" +
+	"\aentries\x18\x03 \x03(\v2\x1b.bell.v1.RecordHistoryEntryR\aentries\x12\x1c\n" +
+	"\ttruncated\x18\x04 \x01(\bR\ttruncated\"\xf2\x01\n" +
+	"\x16GetRecordsBatchRequest\x12?\n" +
+	"\adomains\x18\x01 \x03(\tB%\x92A\"J [\"example.com\", \"*.example.net\"]R\adomains\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\x12%\n" +
+	"\x0esnapshot_token\x18\x03 \x01(\tR\rsnapshotToken\x12*\n" +
+	"\x05order\x18\x04 \x01(\x0e2\x14.bell.v1.RecordOrderR\x05order\x12#\n" +
+	"\rmax_expansion\x18\x05 \x01(\x05R\fmaxExpansion\"d\n" +
+	"\x10PatternExpansion\x12\x18\n" +
+	"\apattern\x18\x01 \x01(\tR\apattern\x12\x18\n" +
+	"\adomains\x18\x02 \x03(\tR\adomains\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\"\x85\x01\n" +
+	"\rDomainRecords\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.bell.v1.DNSRecordR\arecords\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12(\n" +
+	"\x10data_age_seconds\x18\x03 \x01(\x03R\x0edataAgeSeconds\"\x98\x02\n" +
+	"\x17GetRecordsBatchResponse\x12G\n" +
+	"\aresults\x18\x01 \x03(\v2-.bell.v1.GetRecordsBatchResponse.ResultsEntryR\aresults\x12%\n" +
+	"\x0esnapshot_token\x18\x02 \x01(\tR\rsnapshotToken\x129\n" +
+	"\n" +
+	"expansions\x18\x03 \x03(\v2\x19.bell.v1.PatternExpansionR\n" +
+	"expansions\x1aR\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.bell.v1.DomainRecordsR\x05value:\x028\x01\"y\n" +
+	"\x14StreamRecordsRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x03(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"T\n" +
+	"\x0eStreamedRecord\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12*\n" +
+	"\x06record\x18\x02 \x01(\v2\x12.bell.v1.DNSRecordR\x06record\"\x9e\x01\n" +
+	"\x1aStreamRecordBatchesRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x03(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"batch_rows\x18\x05 \x01(\x05R\tbatchRows\"6\n" +
+	"\fArrowMessage\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04rows\x18\x02 \x01(\x03R\x04rows\"\x94\x01\n" +
+	"\x1dSubscribeRecordChangesRequest\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12!\n" +
+	"\frecord_types\x18\x03 \x03(\tR\vrecordTypes\x12$\n" +
+	"\rsignificances\x18\x04 \x03(\tR\rsignificances\"\xab\x01\n" +
+	"\fRecordChange\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\tR\x04time\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x03 \x01(\tR\x03tld\x12\x1f\n" +
+	"\vrecord_type\x18\x04 \x01(\tR\n" +
+	"recordType\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\x12\"\n" +
+	"\fsignificance\x18\x06 \x01(\tR\fsignificance\"\xf9\x01\n" +
+	"\x0fMergeProvenance\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x12#\n" +
+	"\rchosen_source\x18\x02 \x01(\tR\fchosenSource\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12.\n" +
+	"\x13chosen_last_updated\x18\x04 \x01(\tR\x11chosenLastUpdated\x12+\n" +
+	"\x11discarded_sources\x18\x05 \x03(\tR\x10discardedSources\x12+\n" +
+	"\x11discarded_records\x18\x06 \x01(\x05R\x10discardedRecords\"\xf1\x01\n" +
+	"\tTLDStatus\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12%\n" +
+	"\x0elast_processed\x18\x02 \x01(\tR\rlastProcessed\x12%\n" +
+	"\x0elast_attempted\x18\x03 \x01(\tR\rlastAttempted\x12\x1f\n" +
+	"\vlast_status\x18\x04 \x01(\tR\n" +
+	"lastStatus\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x12!\n" +
+	"\fdomain_count\x18\x06 \x01(\x03R\vdomainCount\x12!\n" +
+	"\frecord_count\x18\a \x01(\x03R\vrecordCount\"\x11\n" +
+	"\x0fListTLDsRequest\":\n" +
+	"\x10ListTLDsResponse\x12&\n" +
+	"\x04tlds\x18\x01 \x03(\v2\x12.bell.v1.TLDStatusR\x04tlds\"'\n" +
+	"\x13GetTLDStatusRequest\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\"B\n" +
+	"\x14GetTLDStatusResponse\x12*\n" +
+	"\x06status\x18\x01 \x01(\v2\x12.bell.v1.TLDStatusR\x06status\"\xc9\x01\n" +
+	"\x17ListDomainsByTLDRequest\x12\x1c\n" +
+	"\x03tld\x18\x01 \x01(\tB\n" +
+	"\x92A\aJ\x05\"com\"R\x03tld\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12(\n" +
+	"\x10first_seen_after\x18\x04 \x01(\tR\x0efirstSeenAfter\x12*\n" +
+	"\x11first_seen_before\x18\x05 \x01(\tR\x0ffirstSeenBefore\"\xac\x01\n" +
+	"\tTLDDomain\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12#\n" +
+	"\rpublic_suffix\x18\x02 \x01(\tR\fpublicSuffix\x12 \n" +
+	"\vnameservers\x18\x03 \x03(\tR\vnameservers\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x04 \x01(\tR\tfirstSeen\x12!\n" +
+	"\flast_updated\x18\x05 \x01(\tR\vlastUpdated\"p\n" +
+	"\x18ListDomainsByTLDResponse\x12,\n" +
+	"\adomains\x18\x01 \x03(\v2\x12.bell.v1.TLDDomainR\adomains\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"e\n" +
+	"\x0eGetTopNRequest\x12+\n" +
+	"\x06metric\x18\x01 \x01(\x0e2\x13.bell.v1.TopNMetricR\x06metric\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"j\n" +
+	"\tTopNEntry\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12!\n" +
+	"\fdomain_count\x18\x04 \x01(\x03R\vdomainCount\"`\n" +
+	"\x0fGetTopNResponse\x12,\n" +
+	"\aentries\x18\x01 \x03(\v2\x12.bell.v1.TopNEntryR\aentries\x12\x1f\n" +
+	"\vcomputed_at\x18\x02 \x01(\tR\n" +
+	"computedAt\"x\n" +
+	"\x17GetKeywordTrendsRequest\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\tR\x03day\x12#\n" +
+	"\rbaseline_days\x18\x03 \x01(\x05R\fbaselineDays\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"}\n" +
+	"\fKeywordTrend\x12\x18\n" +
+	"\akeyword\x18\x01 \x01(\tR\akeyword\x12!\n" +
+	"\fdomain_count\x18\x02 \x01(\x03R\vdomainCount\x12\x1a\n" +
+	"\bbaseline\x18\x03 \x01(\x01R\bbaseline\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"\x8e\x01\n" +
+	"\x18GetKeywordTrendsResponse\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12-\n" +
+	"\x06trends\x18\x03 \x03(\v2\x15.bell.v1.KeywordTrendR\x06trends\x12\x1f\n" +
+	"\vcomputed_at\x18\x04 \x01(\tR\n" +
+	"computedAt\"@\n" +
+	"\x18GetDNSSECAdoptionRequest\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\"\x8a\x01\n" +
+	"\x13DNSSECAdoptionPoint\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12 \n" +
+	"\vdelegations\x18\x02 \x01(\x03R\vdelegations\x12\x16\n" +
+	"\x06signed\x18\x03 \x01(\x03R\x06signed\x12'\n" +
+	"\x0fsigned_fraction\x18\x04 \x01(\x01R\x0esignedFraction\"\x99\x01\n" +
+	"\x14DNSSECAlgorithmUsage\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\x05R\talgorithm\x12%\n" +
+	"\x0ealgorithm_name\x18\x02 \x01(\tR\ralgorithmName\x12\x19\n" +
+	"\bkey_size\x18\x03 \x01(\x05R\akeySize\x12!\n" +
+	"\fdomain_count\x18\x04 \x01(\x03R\vdomainCount\"\xc3\x01\n" +
+	"\x19GetDNSSECAdoptionResponse\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x124\n" +
+	"\x06series\x18\x02 \x03(\v2\x1c.bell.v1.DNSSECAdoptionPointR\x06series\x12=\n" +
+	"\n" +
+	"algorithms\x18\x03 \x03(\v2\x1d.bell.v1.DNSSECAlgorithmUsageR\n" +
+	"algorithms\x12\x1f\n" +
+	"\vcomputed_at\x18\x04 \x01(\tR\n" +
+	"computedAt\"o\n" +
+	"\x19ListDomainClustersRequest\x12\x19\n" +
+	"\bmin_size\x18\x01 \x01(\x05R\aminSize\x12!\n" +
+	"\factive_since\x18\x02 \x01(\tR\vactiveSince\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xce\x02\n" +
+	"\rDomainCluster\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12 \n" +
+	"\vnameservers\x18\x02 \x03(\tR\vnameservers\x12\x1c\n" +
+	"\taddresses\x18\x03 \x03(\tR\taddresses\x12!\n" +
+	"\fdomain_count\x18\x04 \x01(\x05R\vdomainCount\x12&\n" +
+	"\x0ffirst_seen_from\x18\x05 \x01(\tR\rfirstSeenFrom\x12\"\n" +
+	"\rfirst_seen_to\x18\x06 \x01(\tR\vfirstSeenTo\x12\x1f\n" +
+	"\vcomputed_at\x18\a \x01(\tR\n" +
+	"computedAt\x120\n" +
+	"\adomains\x18\b \x03(\v2\x16.bell.v1.ClusterDomainR\adomains\x12\x1c\n" +
+	"\ttruncated\x18\t \x01(\bR\ttruncated\"F\n" +
+	"\rClusterDomain\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x02 \x01(\tR\tfirstSeen\"Z\n" +
+	"\x18GetRecordActivityRequest\x12*\n" +
+	"\x06domain\x18\x01 \x01(\tB\x12\x92A\x0fJ\r\"example.com\"R\x06domain\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\"s\n" +
+	"\x13RecordActivityPoint\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x1a\n" +
+	"\bobserved\x18\x02 \x01(\x03R\bobserved\x12\x14\n" +
+	"\x05added\x18\x03 \x01(\x03R\x05added\x12\x18\n" +
+	"\achanged\x18\x04 \x01(\x03R\achanged\"\x8a\x01\n" +
+	"\x19GetRecordActivityResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x124\n" +
+	"\x06series\x18\x02 \x03(\v2\x1c.bell.v1.RecordActivityPointR\x06series\x12\x1f\n" +
+	"\vcomputed_at\x18\x03 \x01(\tR\n" +
+	"computedAt\"[\n" +
+	"\x1bGetSubdomainActivityRequest\x12&\n" +
+	"\x04apex\x18\x01 \x01(\tB\x12\x92A\x0fJ\r\"example.com\"R\x04apex\x12\x14\n" +
+	"\x05weeks\x18\x02 \x01(\x05R\x05weeks\"S\n" +
+	"\x16SubdomainActivityPoint\x12\x12\n" +
+	"\x04week\x18\x01 \x01(\tR\x04week\x12%\n" +
+	"\x0enew_subdomains\x18\x02 \x01(\x03R\rnewSubdomains\"\x8c\x01\n" +
+	"\x1cGetSubdomainActivityResponse\x12\x12\n" +
+	"\x04apex\x18\x01 \x01(\tR\x04apex\x127\n" +
+	"\x06series\x18\x02 \x03(\v2\x1f.bell.v1.SubdomainActivityPointR\x06series\x12\x1f\n" +
+	"\vcomputed_at\x18\x03 \x01(\tR\n" +
+	"computedAt\"P\n" +
+	"\x1aListDomainClustersResponse\x122\n" +
+	"\bclusters\x18\x01 \x03(\v2\x16.bell.v1.DomainClusterR\bclusters\"f\n" +
+	"\x17GetDomainClusterRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x84\x01\n" +
+	"\x12GetTTLStatsRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x01(\tR\n" +
+	"recordType\x12#\n" +
+	"\ranomaly_limit\x18\x04 \x01(\x05R\fanomalyLimit\"S\n" +
+	"\tTTLBucket\x12\x17\n" +
+	"\amin_ttl\x18\x01 \x01(\x05R\x06minTtl\x12\x17\n" +
+	"\amax_ttl\x18\x02 \x01(\x05R\x06maxTtl\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"\x98\x01\n" +
+	"\n" +
+	"TTLAnomaly\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12\x17\n" +
+	"\aold_ttl\x18\x03 \x01(\x05R\x06oldTtl\x12\x17\n" +
+	"\anew_ttl\x18\x04 \x01(\x05R\x06newTtl\x12\x1f\n" +
+	"\vobserved_at\x18\x05 \x01(\tR\n" +
+	"observedAt\"\xfa\x01\n" +
+	"\x13GetTTLStatsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x05R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x05R\x03max\x12\x12\n" +
+	"\x04mean\x18\x04 \x01(\x01R\x04mean\x12\x10\n" +
+	"\x03p50\x18\x05 \x01(\x01R\x03p50\x12\x10\n" +
+	"\x03p90\x18\x06 \x01(\x01R\x03p90\x12\x10\n" +
+	"\x03p99\x18\a \x01(\x01R\x03p99\x12,\n" +
+	"\abuckets\x18\b \x03(\v2\x12.bell.v1.TTLBucketR\abuckets\x121\n" +
+	"\tanomalies\x18\t \x03(\v2\x13.bell.v1.TTLAnomalyR\tanomalies\"+\n" +
+	"\x15GetTLDCoverageRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\"\xd9\x01\n" +
+	"\x0eTLDCoverageGap\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12)\n" +
+	"\x10observed_domains\x18\x03 \x01(\x03R\x0fobservedDomains\x12+\n" +
+	"\x11estimated_domains\x18\x04 \x01(\x03R\x10estimatedDomains\x12/\n" +
+	"\x13estimate_configured\x18\x05 \x01(\bR\x12estimateConfigured\x12\x18\n" +
+	"\asources\x18\x06 \x03(\tR\asources\"\xe9\x01\n" +
+	"\x16GetTLDCoverageResponse\x12\x1b\n" +
+	"\tiana_tlds\x18\x01 \x01(\x05R\bianaTlds\x12!\n" +
+	"\fcovered_tlds\x18\x02 \x01(\x05R\vcoveredTlds\x12\x1d\n" +
+	"\n" +
+	"cctld_gaps\x18\x03 \x01(\x05R\tcctldGaps\x12\x1b\n" +
+	"\tgtld_gaps\x18\x04 \x01(\x05R\bgtldGaps\x12+\n" +
+	"\x04gaps\x18\x05 \x03(\v2\x17.bell.v1.TLDCoverageGapR\x04gaps\x12&\n" +
+	"\x0fiana_updated_at\x18\x06 \x01(\tR\rianaUpdatedAt\"B\n" +
+	"\x12SetLogLevelRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x12\x16\n" +
+	"\x06module\x18\x02 \x01(\tR\x06module\"R\n" +
+	"\x13SetLogLevelResponse\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x12%\n" +
+	"\x0eprevious_level\x18\x02 \x01(\tR\rpreviousLevel\"T\n" +
+	"\x13CheckDomainsRequest\x12=\n" +
+	"\adomains\x18\x01 \x03(\tB#\x92A J\x1e[\"example.com\", \"example.net\"]R\adomains\"@\n" +
+	"\x0eDomainPresence\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x16\n" +
+	"\x06exists\x18\x02 \x01(\bR\x06exists\"I\n" +
+	"\x14CheckDomainsResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.bell.v1.DomainPresenceR\aresults\"\xad\x01\n" +
+	"\x13CountDomainsRequest\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\x12(\n" +
+	"\x10first_seen_after\x18\x03 \x01(\tR\x0efirstSeenAfter\x12*\n" +
+	"\x11first_seen_before\x18\x04 \x01(\tR\x0ffirstSeenBefore\x12\x14\n" +
+	"\x05exact\x18\x05 \x01(\bR\x05exact\"\xc0\x01\n" +
+	"\x13CountRecordsRequest\x12\x10\n" +
+	"\x03tld\x18\x01 \x01(\tR\x03tld\x12%\n" +
+	"\x0edomain_pattern\x18\x02 \x01(\tR\rdomainPattern\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x01(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12!\n" +
+	"\fdata_pattern\x18\x05 \x01(\tR\vdataPattern\x12\x14\n" +
+	"\x05exact\x18\x06 \x01(\bR\x05exact\"j\n" +
+	"\rCountResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x1c\n" +
+	"\testimated\x18\x02 \x01(\bR\testimated\x12%\n" +
+	"\x0eskipped_shards\x18\x03 \x03(\tR\rskippedShards\"\xb4\x02\n" +
+	"\x12StartExportRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x10\n" +
+	"\x03tld\x18\x03 \x01(\tR\x03tld\x12\x18\n" +
+	"\apattern\x18\x04 \x01(\tR\apattern\x12\x1f\n" +
+	"\vrecord_type\x18\x05 \x01(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x12!\n" +
+	"\fdata_pattern\x18\a \x01(\tR\vdataPattern\x12(\n" +
+	"\x10first_seen_after\x18\b \x01(\tR\x0efirstSeenAfter\x12*\n" +
+	"\x11first_seen_before\x18\t \x01(\tR\x0ffirstSeenBefore\x12\x14\n" +
+	"\x05scrub\x18\n" +
+	" \x01(\bR\x05scrub\"\xb0\x05\n" +
+	"\tExportJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x10\n" +
+	"\x03tld\x18\x04 \x01(\tR\x03tld\x12\x18\n" +
+	"\apattern\x18\x05 \x01(\tR\apattern\x12\x1f\n" +
+	"\vrecord_type\x18\x06 \x01(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06source\x18\a \x01(\tR\x06source\x12!\n" +
+	"\fdata_pattern\x18\b \x01(\tR\vdataPattern\x12(\n" +
+	"\x10first_seen_after\x18\t \x01(\tR\x0efirstSeenAfter\x12*\n" +
+	"\x11first_seen_before\x18\n" +
+	" \x01(\tR\x0ffirstSeenBefore\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12\x12\n" +
+	"\x04rows\x18\f \x01(\x03R\x04rows\x12\x14\n" +
+	"\x05bytes\x18\r \x01(\x03R\x05bytes\x12%\n" +
+	"\x0eskipped_shards\x18\x0e \x03(\tR\rskippedShards\x12\x14\n" +
+	"\x05error\x18\x0f \x01(\tR\x05error\x12!\n" +
+	"\fdownload_url\x18\x10 \x01(\tR\vdownloadUrl\x125\n" +
+	"\x17download_url_expires_at\x18\x11 \x01(\tR\x14downloadUrlExpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x13 \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x14 \x01(\tR\n" +
+	"finishedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x15 \x01(\tR\texpiresAt\x12\x14\n" +
+	"\x05scrub\x18\x16 \x01(\bR\x05scrub\x12\x1c\n" +
+	"\tsignature\x18\x17 \x01(\tR\tsignature\"\"\n" +
+	"\x10GetExportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x14\n" +
+	"\x12ListExportsRequest\"C\n" +
+	"\x13ListExportsResponse\x12,\n" +
+	"\aexports\x18\x01 \x03(\v2\x12.bell.v1.ExportJobR\aexports\"K\n" +
+	"\x17GetAbuseContactsRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x18\n" +
+	"\arefresh\x18\x02 \x01(\bR\arefresh\"k\n" +
+	"\tRegistrar\x12\x17\n" +
+	"\aiana_id\x18\x01 \x01(\x05R\x06ianaId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
+	"\brdap_url\x18\x04 \x01(\tR\ardapUrl\"m\n" +
+	"\fAbuseContact\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x03 \x01(\tR\x05phone\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x04 \x01(\tR\tsourceUrl\"\xb6\x01\n" +
+	"\x18GetAbuseContactsResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x120\n" +
+	"\tregistrar\x18\x02 \x01(\v2\x12.bell.v1.RegistrarR\tregistrar\x121\n" +
+	"\bcontacts\x18\x03 \x03(\v2\x15.bell.v1.AbuseContactR\bcontacts\x12\x1d\n" +
+	"\n" +
+	"fetched_at\x18\x04 \x01(\tR\tfetchedAt\"W\n" +
+	"\x19GetDomainLifecycleRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\"\n" +
+	"\fsignificance\x18\x02 \x03(\tR\fsignificance\"\xa7\x04\n" +
+	"\x0eLifecycleEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vobserved_at\x18\x02 \x01(\tR\n" +
+	"observedAt\x12'\n" +
+	"\x0fold_nameservers\x18\x03 \x03(\tR\x0eoldNameservers\x12 \n" +
+	"\vnameservers\x18\x04 \x03(\tR\vnameservers\x12)\n" +
+	"\x10provider_changed\x18\x05 \x01(\bR\x0fproviderChanged\x127\n" +
+	"\rold_registrar\x18\x06 \x01(\v2\x12.bell.v1.RegistrarR\foldRegistrar\x120\n" +
+	"\tregistrar\x18\a \x01(\v2\x12.bell.v1.RegistrarR\tregistrar\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\b \x01(\tR\n" +
+	"confidence\x12\x1a\n" +
+	"\bevidence\x18\t \x03(\tR\bevidence\x12\"\n" +
+	"\fsignificance\x18\n" +
+	" \x01(\tR\fsignificance\x12\x1f\n" +
+	"\vrecord_type\x18\v \x01(\tR\n" +
+	"recordType\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\x12\x1f\n" +
+	"\vold_records\x18\r \x03(\tR\n" +
+	"oldRecords\x12\x18\n" +
+	"\arecords\x18\x0e \x03(\tR\arecords\x12\x17\n" +
+	"\aold_ttl\x18\x0f \x01(\x05R\x06oldTtl\x12\x10\n" +
+	"\x03ttl\x18\x10 \x01(\x05R\x03ttl\"\xa1\x01\n" +
+	"\x1aGetDomainLifecycleResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x02 \x01(\tR\tfirstSeen\x12\x1b\n" +
+	"\tlast_seen\x18\x03 \x01(\tR\blastSeen\x12/\n" +
+	"\x06events\x18\x04 \x03(\v2\x17.bell.v1.LifecycleEventR\x06events\"N\n" +
+	"\x13VerifyDomainRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\"\x8d\x01\n" +
+	"\x11RecordSetChecksum\x12\x1a\n" +
+	"\bchecksum\x18\x01 \x01(\tR\bchecksum\x12!\n" +
+	"\frecord_count\x18\x02 \x01(\x05R\vrecordCount\x12\x1f\n" +
+	"\vcomputed_at\x18\x03 \x01(\tR\n" +
+	"computedAt\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\"\x97\x03\n" +
+	"\x15RecordSetVerification\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x12.\n" +
+	"\x04zone\x18\x02 \x01(\v2\x1a.bell.v1.RecordSetChecksumR\x04zone\x120\n" +
+	"\x05query\x18\x03 \x01(\v2\x1a.bell.v1.RecordSetChecksumR\x05query\x126\n" +
+	"\bdatabase\x18\x04 \x01(\v2\x1a.bell.v1.RecordSetChecksumR\bdatabase\x12.\n" +
+	"\x04live\x18\x05 \x01(\v2\x1a.bell.v1.RecordSetChecksumR\x04live\x12\x1d\n" +
+	"\n" +
+	"live_error\x18\x06 \x01(\tR\tliveError\x12\x14\n" +
+	"\x05drift\x18\a \x01(\bR\x05drift\x122\n" +
+	"\x15missing_from_database\x18\b \x03(\tR\x13missingFromDatabase\x12*\n" +
+	"\x11stale_in_database\x18\t \x03(\tR\x0fstaleInDatabase\"\xa7\x01\n" +
+	"\x14VerifyDomainResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12 \n" +
+	"\vnameservers\x18\x02 \x03(\tR\vnameservers\x12?\n" +
+	"\vrecord_sets\x18\x03 \x03(\v2\x1e.bell.v1.RecordSetVerificationR\n" +
+	"recordSets\x12\x14\n" +
+	"\x05drift\x18\x04 \x01(\bR\x05drift\"\xde\x01\n" +
+	"\x11LiveLookupOptions\x12\x1c\n" +
+	"\ttransport\x18\x01 \x01(\tR\ttransport\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\x12$\n" +
+	"\rauthoritative\x18\x03 \x01(\bR\rauthoritative\x12\x16\n" +
+	"\x06dnssec\x18\x04 \x01(\bR\x06dnssec\x12\x19\n" +
+	"\bno_cache\x18\x05 \x01(\bR\anoCache\x12:\n" +
+	"\rclient_subnet\x18\x06 \x01(\tB\x15\x92A\x12J\x10\"203.0.113.0/24\"R\fclientSubnet\"\x82\x01\n" +
+	"\x11LookupLiveRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\x124\n" +
+	"\aoptions\x18\x03 \x01(\v2\x1a.bell.v1.LiveLookupOptionsR\aoptions\"\x86\x03\n" +
+	"\n" +
+	"LiveAnswer\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\x12\x1c\n" +
+	"\ttransport\x18\x03 \x01(\tR\ttransport\x12\x14\n" +
+	"\x05rcode\x18\x04 \x01(\tR\x05rcode\x12\x16\n" +
+	"\x06answer\x18\x05 \x03(\tR\x06answer\x12\x1c\n" +
+	"\tauthority\x18\x06 \x03(\tR\tauthority\x12\x15\n" +
+	"\x06rtt_ms\x18\a \x01(\x01R\x05rttMs\x12\x16\n" +
+	"\x06cached\x18\b \x01(\bR\x06cached\x12-\n" +
+	"\x12authenticated_data\x18\t \x01(\bR\x11authenticatedData\x121\n" +
+	"\x14authoritative_answer\x18\n" +
+	" \x01(\bR\x13authoritativeAnswer\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12.\n" +
+	"\x13client_subnet_scope\x18\f \x01(\tR\x11clientSubnetScope\"\x9c\x01\n" +
+	"\x12LookupLiveResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12 \n" +
+	"\vnameservers\x18\x02 \x03(\tR\vnameservers\x12-\n" +
+	"\aanswers\x18\x03 \x03(\v2\x13.bell.v1.LiveAnswerR\aanswers\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\x04 \x01(\x01R\telapsedMs\"\x8b\x01\n" +
+	"\x14RefreshDomainRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\x12:\n" +
+	"\rclient_subnet\x18\x03 \x01(\tB\x15\x92A\x12J\x10\"203.0.113.0/24\"R\fclientSubnet\"\xcc\x01\n" +
+	"\tGeoAnswer\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x12#\n" +
+	"\rclient_subnet\x18\x02 \x01(\tR\fclientSubnet\x12\x14\n" +
+	"\x05scope\x18\x03 \x01(\tR\x05scope\x12\x14\n" +
+	"\x05rcode\x18\x04 \x01(\tR\x05rcode\x12\x16\n" +
+	"\x06answer\x18\x05 \x03(\tR\x06answer\x12\x16\n" +
+	"\x06server\x18\x06 \x01(\tR\x06server\x12\x1d\n" +
+	"\n" +
+	"queried_at\x18\a \x01(\tR\tqueriedAt\"\x97\x01\n" +
+	"\x15RefreshDomainResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12 \n" +
+	"\vnameservers\x18\x02 \x03(\tR\vnameservers\x12,\n" +
+	"\aanswers\x18\x03 \x03(\v2\x12.bell.v1.GeoAnswerR\aanswers\x12\x16\n" +
+	"\x06errors\x18\x04 \x03(\tR\x06errors\"Q\n" +
+	"\x16TraceResolutionRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\"\x83\x03\n" +
+	"\tTraceStep\x12\x12\n" +
+	"\x04zone\x18\x01 \x01(\tR\x04zone\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x15\n" +
+	"\x06rtt_ms\x18\x04 \x01(\x01R\x05rttMs\x12\x14\n" +
+	"\x05rcode\x18\x05 \x01(\tR\x05rcode\x12\x12\n" +
+	"\x04kind\x18\x06 \x01(\tR\x04kind\x12\x1e\n" +
+	"\n" +
+	"delegation\x18\a \x01(\tR\n" +
+	"delegation\x12 \n" +
+	"\vnameservers\x18\b \x03(\tR\vnameservers\x12\x12\n" +
+	"\x04glue\x18\t \x03(\tR\x04glue\x12\x0e\n" +
+	"\x02ds\x18\n" +
+	" \x03(\tR\x02ds\x12\x16\n" +
+	"\x06dnssec\x18\v \x01(\tR\x06dnssec\x12\x16\n" +
+	"\x06answer\x18\f \x03(\tR\x06answer\x12\x1c\n" +
+	"\tauthority\x18\r \x03(\tR\tauthority\x12%\n" +
+	"\x0efailed_servers\x18\x0e \x03(\tR\rfailedServers\x12\x14\n" +
+	"\x05error\x18\x0f \x01(\tR\x05error\"\xdd\x02\n" +
+	"\x17TraceResolutionResponse\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12(\n" +
+	"\x05steps\x18\x03 \x03(\v2\x12.bell.v1.TraceStepR\x05steps\x12\x1a\n" +
+	"\bcomplete\x18\x04 \x01(\bR\bcomplete\x12\x16\n" +
+	"\x06answer\x18\x05 \x03(\tR\x06answer\x12\x16\n" +
+	"\x06stored\x18\x06 \x03(\tR\x06stored\x122\n" +
+	"\x15missing_from_database\x18\a \x03(\tR\x13missingFromDatabase\x12*\n" +
+	"\x11stale_in_database\x18\b \x03(\tR\x0fstaleInDatabase\x12\x14\n" +
+	"\x05drift\x18\t \x01(\bR\x05drift\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\n" +
+	" \x01(\x01R\telapsedMs\"O\n" +
+	"\x18GetServiceRecordsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\"\xc3\x03\n" +
+	"\rServiceRecord\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12\x10\n" +
+	"\x03ttl\x18\x03 \x01(\x05R\x03ttl\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12!\n" +
+	"\flast_updated\x18\x05 \x01(\tR\vlastUpdated\x12\x18\n" +
+	"\aservice\x18\x06 \x01(\tR\aservice\x12\x14\n" +
+	"\x05proto\x18\a \x01(\tR\x05proto\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\x12\x16\n" +
+	"\x06weight\x18\t \x01(\x05R\x06weight\x12\x12\n" +
+	"\x04port\x18\n" +
+	" \x01(\x05R\x04port\x12\x16\n" +
+	"\x06target\x18\v \x01(\tR\x06target\x12\x14\n" +
+	"\x05order\x18\f \x01(\x05R\x05order\x12\x1e\n" +
+	"\n" +
+	"preference\x18\r \x01(\x05R\n" +
+	"preference\x12\x14\n" +
+	"\x05flags\x18\x0e \x01(\tR\x05flags\x12\x1a\n" +
+	"\bservices\x18\x0f \x01(\tR\bservices\x12\x16\n" +
+	"\x06regexp\x18\x10 \x01(\tR\x06regexp\x12 \n" +
+	"\vreplacement\x18\x11 \x01(\tR\vreplacement\"a\n" +
+	"\x19GetServiceRecordsResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x120\n" +
+	"\arecords\x18\x02 \x03(\v2\x16.bell.v1.ServiceRecordR\arecords\"A\n" +
+	"\x13ValidateDANERequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\"\xd2\x01\n" +
+	"\x0eTLSAValidation\x12\x16\n" +
+	"\x06record\x18\x01 \x01(\tR\x06record\x12\x14\n" +
+	"\x05usage\x18\x02 \x01(\x05R\x05usage\x12\x1a\n" +
+	"\bselector\x18\x03 \x01(\x05R\bselector\x12#\n" +
+	"\rmatching_type\x18\x04 \x01(\x05R\fmatchingType\x12\x14\n" +
+	"\x05match\x18\x05 \x01(\bR\x05match\x12#\n" +
+	"\rmatched_depth\x18\x06 \x01(\x05R\fmatchedDepth\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\"\xc7\x01\n" +
+	"\x14ValidateDANEResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x121\n" +
+	"\arecords\x18\x02 \x03(\v2\x17.bell.v1.TLSAValidationR\arecords\x12\x14\n" +
+	"\x05valid\x18\x03 \x01(\bR\x05valid\x12\x1d\n" +
+	"\n" +
+	"pkix_valid\x18\x04 \x01(\bR\tpkixValid\x12\x1d\n" +
+	"\n" +
+	"pkix_error\x18\x05 \x01(\tR\tpkixError\x12\x14\n" +
+	"\x05chain\x18\x06 \x03(\tR\x05chain\"3\n" +
+	"\rGetPTRRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04live\x18\x02 \x01(\bR\x04live\"\x97\x01\n" +
+	"\tPTRRecord\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x19\n" +
+	"\bptr_name\x18\x02 \x01(\tR\aptrName\x12\x10\n" +
+	"\x03ttl\x18\x03 \x01(\x05R\x03ttl\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x12\n" +
+	"\x04zone\x18\x05 \x01(\tR\x04zone\x12!\n" +
+	"\flast_updated\x18\x06 \x01(\tR\vlastUpdated\"N\n" +
+	"\x0eGetPTRResponse\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12,\n" +
+	"\arecords\x18\x02 \x03(\v2\x12.bell.v1.PTRRecordR\arecords\">\n" +
+	"\x12GetPTRRangeRequest\x12\x12\n" +
+	"\x04cidr\x18\x01 \x01(\tR\x04cidr\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"u\n" +
+	"\x13GetPTRRangeResponse\x12\x12\n" +
+	"\x04cidr\x18\x01 \x01(\tR\x04cidr\x12,\n" +
+	"\arecords\x18\x02 \x03(\v2\x12.bell.v1.PTRRecordR\arecords\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\"\x93\x01\n" +
+	"\x1dGetDomainsByNameserverRequest\x126\n" +
+	"\n" +
+	"nameserver\x18\x01 \x01(\tB\x16\x92A\x13J\x11\"ns1.example.net\"R\n" +
+	"nameserver\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"|\n" +
+	"\x0fDelegatedDomain\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12 \n" +
+	"\vnameservers\x18\x03 \x03(\tR\vnameservers\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x04 \x01(\tR\tfirstSeen\"\xa3\x01\n" +
+	"\x1eGetDomainsByNameserverResponse\x122\n" +
+	"\adomains\x18\x01 \x03(\v2\x18.bell.v1.DelegatedDomainR\adomains\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12%\n" +
+	"\x0eskipped_shards\x18\x03 \x03(\tR\rskippedShards\"{\n" +
+	"\x15ListSubdomainsRequest\x12&\n" +
+	"\x04apex\x18\x01 \x01(\tB\x12\x92A\x0fJ\r\"example.com\"R\x04apex\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"w\n" +
+	"\tSubdomain\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x03 \x01(\tR\tfirstSeen\x12!\n" +
+	"\flast_updated\x18\x04 \x01(\tR\vlastUpdated\"t\n" +
+	"\x16ListSubdomainsResponse\x122\n" +
+	"\n" +
+	"subdomains\x18\x01 \x03(\v2\x12.bell.v1.SubdomainR\n" +
+	"subdomains\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x80\x01\n" +
+	"\x14SearchDomainsRequest\x12,\n" +
+	"\apattern\x18\x01 \x01(\tB\x12\x92A\x0fJ\r\"paypal*.com\"R\apattern\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"y\n" +
+	"\vDomainMatch\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03tld\x18\x02 \x01(\tR\x03tld\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x03 \x01(\tR\tfirstSeen\x12!\n" +
+	"\flast_updated\x18\x04 \x01(\tR\vlastUpdated\"\xae\x01\n" +
+	"\x15SearchDomainsResponse\x12.\n" +
+	"\adomains\x18\x01 \x03(\v2\x14.bell.v1.DomainMatchR\adomains\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12%\n" +
+	"\x0eskipped_shards\x18\x03 \x03(\tR\rskippedShards\x12\x16\n" +
+	"\x06capped\x18\x04 \x01(\bR\x06capped\"\xcc\x01\n" +
+	"\x14SearchRecordsRequest\x12@\n" +
+	"\apattern\x18\x01 \x01(\tB&\x92A#J!\"google-site-verification=abc123\"R\apattern\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x03(\tR\n" +
+	"recordType\x12\x10\n" +
+	"\x03tld\x18\x03 \x01(\tR\x03tld\x12)\n" +
+	"\x10case_insensitive\x18\x04 \x01(\bR\x0fcaseInsensitive\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xac\x01\n" +
+	"\x15SearchRecordsResponse\x121\n" +
+	"\arecords\x18\x01 \x03(\v2\x17.bell.v1.StreamedRecordR\arecords\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x1b\n" +
+	"\ttimed_out\x18\x03 \x01(\bR\btimedOut\x12%\n" +
+	"\x0eskipped_shards\x18\x04 \x03(\tR\rskippedShards\"\x97\x01\n" +
+	"\x0eKeyPreferences\x12!\n" +
+	"\frecord_types\x18\x01 \x03(\tR\vrecordTypes\x12+\n" +
+	"\x11source_precedence\x18\x02 \x03(\tR\x10sourcePrecedence\x12\x19\n" +
+	"\bmax_rows\x18\x03 \x01(\x05R\amaxRows\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\"\x1a\n" +
+	"\x18GetKeyPreferencesRequest\"U\n" +
+	"\x18SetKeyPreferencesRequest\x129\n" +
+	"\vpreferences\x18\x01 \x01(\v2\x17.bell.v1.KeyPreferencesR\vpreferences\"\xe5\x02\n" +
+	"\x0eReportSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06report\x18\x02 \x01(\tR\x06report\x12\x1c\n" +
+	"\tfrequency\x18\x03 \x01(\tR\tfrequency\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x12\x1a\n" +
+	"\bdelivery\x18\x05 \x01(\tR\bdelivery\x12 \n" +
+	"\vdestination\x18\x06 \x01(\tR\vdestination\x12\x1c\n" +
+	"\twatchlist\x18\a \x03(\tR\twatchlist\x12\x12\n" +
+	"\x04tlds\x18\b \x03(\tR\x04tlds\x12&\n" +
+	"\x0flast_period_end\x18\t \x01(\tR\rlastPeriodEnd\x12\x1f\n" +
+	"\vlast_status\x18\n" +
+	" \x01(\tR\n" +
+	"lastStatus\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\v \x01(\tR\tlastError\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAt\"R\n" +
+	"\x1bCreateReportScheduleRequest\x123\n" +
+	"\bschedule\x18\x01 \x01(\v2\x17.bell.v1.ReportScheduleR\bschedule\"\x1c\n" +
+	"\x1aListReportSchedulesRequest\"T\n" +
+	"\x1bListReportSchedulesResponse\x125\n" +
+	"\tschedules\x18\x01 \x03(\v2\x17.bell.v1.ReportScheduleR\tschedules\"-\n" +
+	"\x1bDeleteReportScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x1e\n" +
+	"\x1cDeleteReportScheduleResponse\"\xf4\x01\n" +
+	"\fOrganization\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x13daily_request_quota\x18\x03 \x01(\x03R\x11dailyRequestQuota\x12%\n" +
+	"\x0erequests_today\x18\x04 \x01(\x03R\rrequestsToday\x12\x1c\n" +
+	"\twatchlist\x18\x05 \x03(\tR\twatchlist\x12,\n" +
+	"\x04keys\x18\x06 \x03(\v2\x18.bell.v1.OrganizationKeyR\x04keys\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\xa0\x01\n" +
+	"\x0fOrganizationKey\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12\x1b\n" +
+	"\torg_admin\x18\x04 \x01(\bR\borgAdmin\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\xdf\x03\n" +
+	"\x06APIKey\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x16\n" +
+	"\x06active\x18\x05 \x01(\bR\x06active\x12\x18\n" +
+	"\asandbox\x18\x06 \x01(\bR\asandbox\x12'\n" +
+	"\x0forganization_id\x18\a \x01(\x05R\x0eorganizationId\x12\x1b\n" +
+	"\torg_admin\x18\b \x01(\bR\borgAdmin\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\n" +
+	" \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\v \x01(\tR\trevokedAt\x12\x1d\n" +
+	"\n" +
+	"rotated_to\x18\f \x01(\tR\trotatedTo\x122\n" +
+	"\x15monthly_request_quota\x18\r \x01(\x03R\x13monthlyRequestQuota\x120\n" +
+	"\x14monthly_record_quota\x18\x0e \x01(\x03R\x12monthlyRecordQuota\x12\x16\n" +
+	"\x06scopes\x18\x0f \x03(\tR\x06scopes\"\xb5\x02\n" +
+	"\x13CreateAPIKeyRequest\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12/\n" +
+	"\x05owner\x18\x02 \x01(\tB\x19\x92A\x16J\x14\"secops@example.com\"R\x05owner\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x18\n" +
+	"\asandbox\x18\x04 \x01(\bR\asandbox\x12\x1f\n" +
+	"\vttl_seconds\x18\x05 \x01(\x03R\n" +
+	"ttlSeconds\x122\n" +
+	"\x15monthly_request_quota\x18\x06 \x01(\x03R\x13monthlyRequestQuota\x120\n" +
+	"\x14monthly_record_quota\x18\a \x01(\x03R\x12monthlyRecordQuota\x12\x16\n" +
+	"\x06scopes\x18\b \x03(\tR\x06scopes\"t\n" +
+	"\x13RotateAPIKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12#\n" +
+	"\rgrace_seconds\x18\x02 \x01(\x03R\fgraceSeconds\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\".\n" +
+	"\x13RevokeAPIKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"\x96\x01\n" +
+	"\x15SetAPIKeyQuotaRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x122\n" +
+	"\x15monthly_request_quota\x18\x02 \x01(\x03R\x13monthlyRequestQuota\x120\n" +
+	"\x14monthly_record_quota\x18\x03 \x01(\x03R\x12monthlyRecordQuota\"I\n" +
+	"\x16SetAPIKeyScopesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\"U\n" +
+	"\x12ListAPIKeysRequest\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12)\n" +
+	"\x10include_inactive\x18\x02 \x01(\bR\x0fincludeInactive\":\n" +
+	"\x13ListAPIKeysResponse\x12#\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0f.bell.v1.APIKeyR\x04keys\"\x18\n" +
+	"\x16GetOrganizationRequest\"]\n" +
+	"\x1cCreateOrganizationKeyRequest\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1b\n" +
+	"\torg_admin\x18\x02 \x01(\bR\borgAdmin\"\x8e\x01\n" +
+	"\x1cUpdateOrganizationKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12\x1b\n" +
+	"\torg_admin\x18\x04 \x01(\bR\borgAdmin\"?\n" +
+	"\x1fSetOrganizationWatchlistRequest\x12\x1c\n" +
+	"\twatchlist\x18\x01 \x03(\tR\twatchlist\"}\n" +
+	"\x16ImportWatchlistRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x18\n" +
+	"\areplace\x18\x03 \x01(\bR\areplace\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"b\n" +
+	"\x16RejectedWatchlistEntry\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xee\x01\n" +
+	"\x17ImportWatchlistResponse\x129\n" +
+	"\forganization\x18\x01 \x01(\v2\x15.bell.v1.OrganizationR\forganization\x12\x14\n" +
+	"\x05added\x18\x02 \x01(\x05R\x05added\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x03 \x01(\x05R\n" +
+	"duplicates\x12%\n" +
+	"\x0erejected_count\x18\x04 \x01(\x05R\rrejectedCount\x12;\n" +
+	"\brejected\x18\x05 \x03(\v2\x1f.bell.v1.RejectedWatchlistEntryR\brejected\"J\n" +
+	"\x14ImportDomainsRequest\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x12\x18\n" +
+	"\aprivate\x18\x02 \x01(\bR\aprivate\"@\n" +
+	"\x0eRejectedDomain\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xfa\x01\n" +
+	"\x15ImportDomainsResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x05R\x05added\x12'\n" +
+	"\x0falready_tracked\x18\x02 \x01(\x05R\x0ealreadyTracked\x12\x1f\n" +
+	"\vnew_domains\x18\x03 \x01(\x05R\n" +
+	"newDomains\x12%\n" +
+	"\x0erejected_count\x18\x04 \x01(\x05R\rrejectedCount\x123\n" +
+	"\brejected\x18\x05 \x03(\v2\x17.bell.v1.RejectedDomainR\brejected\x12%\n" +
+	"\x0epublic_domains\x18\x06 \x01(\x05R\rpublicDomains\"`\n" +
+	"\x1bSetDomainRestrictionRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12)\n" +
+	"\x10organization_ids\x18\x02 \x03(\x05R\x0forganizationIds\"V\n" +
+	"\x11DomainRestriction\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12)\n" +
+	"\x10organization_ids\x18\x02 \x03(\x05R\x0forganizationIds\"'\n" +
+	"\x0fGetUsageRequest\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\"l\n" +
+	"\n" +
+	"DailyUsage\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x1a\n" +
+	"\brequests\x18\x02 \x01(\x03R\brequests\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x03R\x06errors\x12\x18\n" +
+	"\arecords\x18\x04 \x01(\x03R\arecords\"\x85\x02\n" +
+	"\x10GetUsageResponse\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x1a\n" +
+	"\brequests\x18\x02 \x01(\x03R\brequests\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x03R\x06errors\x12\x18\n" +
+	"\arecords\x18\x04 \x01(\x03R\arecords\x122\n" +
+	"\x15monthly_request_quota\x18\x05 \x01(\x03R\x13monthlyRequestQuota\x120\n" +
+	"\x14monthly_record_quota\x18\x06 \x01(\x03R\x12monthlyRecordQuota\x12'\n" +
+	"\x04days\x18\a \x03(\v2\x13.bell.v1.DailyUsageR\x04days\"1\n" +
+	"\x1bGetOrganizationUsageRequest\x12\x12\n" +
+	"\x04days\x18\x01 \x01(\x05R\x04days\"\x94\x01\n" +
+	"\x11OrganizationUsage\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x17\n" +
+	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\brequests\x18\x04 \x01(\x03R\brequests\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\x03R\x06errors\"w\n" +
+	"\x1cGetOrganizationUsageResponse\x120\n" +
+	"\x05usage\x18\x01 \x03(\v2\x1a.bell.v1.OrganizationUsageR\x05usage\x12%\n" +
+	"\x0etotal_requests\x18\x02 \x01(\x03R\rtotalRequests\"\x83\x01\n" +
+	"\x19CreateOrganizationRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\x13daily_request_quota\x18\x02 \x01(\x03R\x11dailyRequestQuota\x12\"\n" +
+	"\radmin_api_key\x18\x03 \x01(\tR\vadminApiKey\"v\n" +
+	"\x1bSetOrganizationQuotaRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\x05R\x0eorganizationId\x12.\n" +
+	"\x13daily_request_quota\x18\x02 \x01(\x03R\x11dailyRequestQuota\"{\n" +
+	"\x11TailEventsRequest\x12\x18\n" +
+	"\asources\x18\x01 \x03(\tR\asources\x12\x14\n" +
+	"\x05kinds\x18\x02 \x03(\tR\x05kinds\x12\x10\n" +
+	"\x03tld\x18\x03 \x01(\tR\x03tld\x12$\n" +
+	"\rsignificances\x18\x04 \x03(\tR\rsignificances\"\x86\x02\n" +
+	"\vIngestEvent\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\tR\x04time\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03tld\x18\x04 \x01(\tR\x03tld\x12\x16\n" +
+	"\x06domain\x18\x05 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05count\x18\x06 \x01(\x03R\x05count\x12\x18\n" +
+	"\amessage\x18\a \x01(\tR\amessage\x12\x1f\n" +
+	"\vrecord_type\x18\b \x01(\tR\n" +
+	"recordType\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x12\"\n" +
+	"\fsignificance\x18\n" +
+	" \x01(\tR\fsignificance\"n\n" +
+	"\x1fListNameserverReputationRequest\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12!\n" +
+	"\fskipped_only\x18\x02 \x01(\bR\vskippedOnly\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xc8\x02\n" +
+	"\x14NameserverReputation\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x18\n" +
+	"\aqueries\x18\x03 \x01(\x03R\aqueries\x12\x1a\n" +
+	"\btimeouts\x18\x04 \x01(\x03R\btimeouts\x12\x1a\n" +
+	"\bfailures\x18\x05 \x01(\x03R\bfailures\x121\n" +
+	"\x14consecutive_timeouts\x18\x06 \x01(\x05R\x13consecutiveTimeouts\x12\x1c\n" +
+	"\n" +
+	"avg_rtt_ms\x18\a \x01(\x01R\bavgRttMs\x12!\n" +
+	"\flast_success\x18\b \x01(\tR\vlastSuccess\x12!\n" +
+	"\flast_failure\x18\t \x01(\tR\vlastFailure\x12\x1d\n" +
+	"\n" +
+	"skip_until\x18\n" +
+	" \x01(\tR\tskipUntil\"c\n" +
+	" ListNameserverReputationResponse\x12?\n" +
+	"\vnameservers\x18\x01 \x03(\v2\x1d.bell.v1.NameserverReputationR\vnameservers\"\x17\n" +
+	"\x15ListDNSServersRequest\"\xec\x01\n" +
+	"\tDNSServer\x122\n" +
+	"\aaddress\x18\x01 \x01(\tB\x18\x92A\x15J\x13\"tls://1.1.1.1:853\"R\aaddress\x12\x1c\n" +
+	"\ttransport\x18\x02 \x01(\tR\ttransport\x12\x18\n" +
+	"\ahealthy\x18\x03 \x01(\bR\ahealthy\x121\n" +
+	"\x14consecutive_failures\x18\x04 \x01(\x05R\x13consecutiveFailures\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x12!\n" +
+	"\flast_checked\x18\x06 \x01(\tR\vlastChecked\"F\n" +
+	"\x16ListDNSServersResponse\x12,\n" +
+	"\aservers\x18\x01 \x03(\v2\x12.bell.v1.DNSServerR\aservers\"[\n" +
+	"\x17UpdateDNSServersRequest\x12(\n" +
+	"\x03add\x18\x01 \x03(\tB\x16\x92A\x13J\x11[\"tls://9.9.9.9\"]R\x03add\x12\x16\n" +
+	"\x06remove\x18\x02 \x03(\tR\x06remove*`\n" +
+	"\vRecordOrder\x12\x1c\n" +
+	"\x18RECORD_ORDER_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15RECORD_ORDER_SEMANTIC\x10\x01\x12\x18\n" +
+	"\x14RECORD_ORDER_STORAGE\x10\x02*~\n" +
+	"\n" +
+	"TopNMetric\x12\x1c\n" +
+	"\x18TOP_N_METRIC_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18TOP_N_METRIC_NAMESERVERS\x10\x01\x12\x1d\n" +
+	"\x19TOP_N_METRIC_MX_PROVIDERS\x10\x02\x12\x15\n" +
+	"\x11TOP_N_METRIC_ASNS\x10\x032\xf84\n" +
+	"\n" +
+	"DNSService\x12m\n" +
+	"\fAuthenticate\x12\x1c.bell.v1.AuthenticateRequest\x1a\x1d.bell.v1.AuthenticateResponse\" \x92A\x02b\x00\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/authenticate\x12p\n" +
+	"\x0eGetSigningKeys\x12\x1e.bell.v1.GetSigningKeysRequest\x1a\x1f.bell.v1.GetSigningKeysResponse\"\x1d\x92A\x02b\x00\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/signing-keys\x12c\n" +
+	"\n" +
+	"GetRecords\x12\x1a.bell.v1.GetRecordsRequest\x1a\x1b.bell.v1.GetRecordsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/records/{domain}\x12}\n" +
+	"\x10GetRecordHistory\x12 .bell.v1.GetRecordHistoryRequest\x1a!.bell.v1.GetRecordHistoryResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/records/{domain}/history\x12u\n" +
+	"\x0fGetRecordsBatch\x12\x1f.bell.v1.GetRecordsBatchRequest\x1a .bell.v1.GetRecordsBatchResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/records:batchGet\x12I\n" +
+	"\rStreamRecords\x12\x1d.bell.v1.StreamRecordsRequest\x1a\x17.bell.v1.StreamedRecord0\x01\x12S\n" +
+	"\x13StreamRecordBatches\x12#.bell.v1.StreamRecordBatchesRequest\x1a\x15.bell.v1.ArrowMessage0\x01\x12Y\n" +
+	"\x16SubscribeRecordChanges\x12&.bell.v1.SubscribeRecordChangesRequest\x1a\x15.bell.v1.RecordChange0\x01\x12Q\n" +
+	"\bListTLDs\x12\x18.bell.v1.ListTLDsRequest\x1a\x19.bell.v1.ListTLDsResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/tlds\x12c\n" +
+	"\fGetTLDStatus\x12\x1c.bell.v1.GetTLDStatusRequest\x1a\x1d.bell.v1.GetTLDStatusResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/tlds/{tld}\x12w\n" +
+	"\x10ListDomainsByTLD\x12 .bell.v1.ListDomainsByTLDRequest\x1a!.bell.v1.ListDomainsByTLDResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/tlds/{tld}/domains\x12`\n" +
+	"\aGetTopN\x12\x17.bell.v1.GetTopNRequest\x1a\x18.bell.v1.GetTopNResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/analytics/top/{metric}\x12w\n" +
+	"\x10GetKeywordTrends\x12 .bell.v1.GetKeywordTrendsRequest\x1a!.bell.v1.GetKeywordTrendsResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/analytics/keywords\x12x\n" +
+	"\x11GetDNSSECAdoption\x12!.bell.v1.GetDNSSECAdoptionRequest\x1a\".bell.v1.GetDNSSECAdoptionResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/analytics/dnssec\x12}\n" +
+	"\x12ListDomainClusters\x12\".bell.v1.ListDomainClustersRequest\x1a#.bell.v1.ListDomainClustersResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/analytics/clusters\x12\x99\x01\n" +
+	"\x10GetDomainCluster\x12 .bell.v1.GetDomainClusterRequest\x1a\x16.bell.v1.DomainCluster\"K\x82\xd3\xe4\x93\x02EZ\x1e\x12\x1c/v1/domains/{domain}/cluster\x12#/v1/analytics/clusters/{cluster_id}\x12\x81\x01\n" +
+	"\x11GetRecordActivity\x12!.bell.v1.GetRecordActivityRequest\x1a\".bell.v1.GetRecordActivityResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/domains/{domain}/activity\x12\x93\x01\n" +
+	"\x14GetSubdomainActivity\x12$.bell.v1.GetSubdomainActivityRequest\x1a%.bell.v1.GetSubdomainActivityResponse\".\x82\xd3\xe4\x93\x02(\x12&/v1/domains/{apex}/subdomains/activity\x12_\n" +
+	"\vGetTTLStats\x12\x1b.bell.v1.GetTTLStatsRequest\x1a\x1c.bell.v1.GetTTLStatsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/ttl-stats\x12i\n" +
+	"\fCheckDomains\x12\x1c.bell.v1.CheckDomainsRequest\x1a\x1d.bell.v1.CheckDomainsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/domains:check\x12_\n" +
+	"\fCountDomains\x12\x1c.bell.v1.CountDomainsRequest\x1a\x16.bell.v1.CountResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/domains:count\x12_\n" +
+	"\fCountRecords\x12\x1c.bell.v1.CountRecordsRequest\x1a\x16.bell.v1.CountResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/records:count\x12V\n" +
+	"\vStartExport\x12\x1b.bell.v1.StartExportRequest\x1a\x12.bell.v1.ExportJob\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/exports\x12T\n" +
+	"\tGetExport\x12\x19.bell.v1.GetExportRequest\x1a\x12.bell.v1.ExportJob\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/exports/{id}\x12]\n" +
+	"\vListExports\x12\x1b.bell.v1.ListExportsRequest\x1a\x1c.bell.v1.ListExportsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/exports\x12\x84\x01\n" +
+	"\x10GetAbuseContacts\x12 .bell.v1.GetAbuseContactsRequest\x1a!.bell.v1.GetAbuseContactsResponse\"+\x82\xd3\xe4\x93\x02%\x12#/v1/domains/{domain}/abuse-contacts\x12\x85\x01\n" +
+	"\x12GetDomainLifecycle\x12\".bell.v1.GetDomainLifecycleRequest\x1a#.bell.v1.GetDomainLifecycleResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/domains/{domain}/lifecycle\x12p\n" +
+	"\fVerifyDomain\x12\x1c.bell.v1.VerifyDomainRequest\x1a\x1d.bell.v1.VerifyDomainResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/domains/{domain}/verify\x12b\n" +
+	"\n" +
+	"LookupLive\x12\x1a.bell.v1.LookupLiveRequest\x1a\x1b.bell.v1.LookupLiveResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/lookup/{domain}\x12w\n" +
+	"\rRefreshDomain\x12\x1d.bell.v1.RefreshDomainRequest\x1a\x1e.bell.v1.RefreshDomainResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/domains/{domain}:refresh\x12x\n" +
+	"\x0fTraceResolution\x12\x1f.bell.v1.TraceResolutionRequest\x1a .bell.v1.TraceResolutionResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/domains/{domain}/trace\x12w\n" +
+	"\x11GetServiceRecords\x12!.bell.v1.GetServiceRecordsRequest\x1a\".bell.v1.GetServiceRecordsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/services/{name}\x12n\n" +
+	"\fValidateDANE\x12\x1c.bell.v1.ValidateDANERequest\x1a\x1d.bell.v1.ValidateDANEResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/domains/{domain}/dane\x12O\n" +
+	"\x06GetPTR\x12\x16.bell.v1.GetPTRRequest\x1a\x17.bell.v1.GetPTRResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/ptr/{ip}\x12Y\n" +
+	"\vGetPTRRange\x12\x1b.bell.v1.GetPTRRangeRequest\x1a\x1c.bell.v1.GetPTRRangeResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/v1/ptr\x12\x97\x01\n" +
+	"\x16GetDomainsByNameserver\x12&.bell.v1.GetDomainsByNameserverRequest\x1a'.bell.v1.GetDomainsByNameserverResponse\",\x82\xd3\xe4\x93\x02&\x12$/v1/nameservers/{nameserver}/domains\x12x\n" +
+	"\x0eListSubdomains\x12\x1e.bell.v1.ListSubdomainsRequest\x1a\x1f.bell.v1.ListSubdomainsResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/domains/{apex}/subdomains\x12j\n" +
+	"\rSearchDomains\x12\x1d.bell.v1.SearchDomainsRequest\x1a\x1e.bell.v1.SearchDomainsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/domains:search\x12j\n" +
+	"\rSearchRecords\x12\x1d.bell.v1.SearchRecordsRequest\x1a\x1e.bell.v1.SearchRecordsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/records:search\x12r\n" +
+	"\x11GetKeyPreferences\x12!.bell.v1.GetKeyPreferencesRequest\x1a\x17.bell.v1.KeyPreferences\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/keys/self/preferences\x12u\n" +
+	"\x11SetKeyPreferences\x12!.bell.v1.SetKeyPreferencesRequest\x1a\x17.bell.v1.KeyPreferences\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/keys/self/preferences\x12~\n" +
+	"\x14CreateReportSchedule\x12$.bell.v1.CreateReportScheduleRequest\x1a\x17.bell.v1.ReportSchedule\"'\x82\xd3\xe4\x93\x02!:\bschedule\"\x15/v1/keys/self/reports\x12\x7f\n" +
+	"\x13ListReportSchedules\x12#.bell.v1.ListReportSchedulesRequest\x1a$.bell.v1.ListReportSchedulesResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/keys/self/reports\x12\x87\x01\n" +
+	"\x14DeleteReportSchedule\x12$.bell.v1.DeleteReportScheduleRequest\x1a%.bell.v1.DeleteReportScheduleResponse\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/v1/keys/self/reports/{id}\x12\\\n" +
+	"\bGetUsage\x12\x18.bell.v1.GetUsageRequest\x1a\x19.bell.v1.GetUsageResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/keys/self/usage\x12Z\n" +
+	"\x0fGetOrganization\x12\x1f.bell.v1.GetOrganizationRequest\x1a\x15.bell.v1.Organization\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/v1/org\x12q\n" +
+	"\x15CreateOrganizationKey\x12%.bell.v1.CreateOrganizationKeyRequest\x1a\x18.bell.v1.OrganizationKey\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/org/keys\x12{\n" +
+	"\x15UpdateOrganizationKey\x12%.bell.v1.UpdateOrganizationKeyRequest\x1a\x18.bell.v1.OrganizationKey\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/org/keys/{api_key}\x12y\n" +
+	"\x18SetOrganizationWatchlist\x12(.bell.v1.SetOrganizationWatchlistRequest\x1a\x15.bell.v1.Organization\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/org/watchlist\x12y\n" +
+	"\x0fImportWatchlist\x12\x1f.bell.v1.ImportWatchlistRequest\x1a .bell.v1.ImportWatchlistResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/org/watchlist:import\x12q\n" +
+	"\rImportDomains\x12\x1d.bell.v1.ImportDomainsRequest\x1a\x1e.bell.v1.ImportDomainsResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/org/domains:import\x12z\n" +
+	"\x14GetOrganizationUsage\x12$.bell.v1.GetOrganizationUsageRequest\x1a%.bell.v1.GetOrganizationUsageResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/org/usage\x12O\n" +
+	"\x12CreateOrganization\x12\".bell.v1.CreateOrganizationRequest\x1a\x15.bell.v1.Organization\x12S\n" +
+	"\x14SetOrganizationQuota\x12$.bell.v1.SetOrganizationQuotaRequest\x1a\x15.bell.v1.Organization\x12X\n" +
+	"\x14SetDomainRestriction\x12$.bell.v1.SetDomainRestrictionRequest\x1a\x1a.bell.v1.DomainRestriction\x12o\n" +
+	"\x18ListNameserverReputation\x12(.bell.v1.ListNameserverReputationRequest\x1a).bell.v1.ListNameserverReputationResponse\x12@\n" +
+	"\n" +
+	"TailEvents\x12\x1a.bell.v1.TailEventsRequest\x1a\x14.bell.v1.IngestEvent0\x01\x12p\n" +
+	"\x0eListDNSServers\x12\x1e.bell.v1.ListDNSServersRequest\x1a\x1f.bell.v1.ListDNSServersResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/admin/dns-servers\x12w\n" +
+	"\x10UpdateDNSServers\x12 .bell.v1.UpdateDNSServersRequest\x1a\x1f.bell.v1.ListDNSServersResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/admin/dns-servers\x12w\n" +
+	"\x0eGetTLDCoverage\x12\x1e.bell.v1.GetTLDCoverageRequest\x1a\x1f.bell.v1.GetTLDCoverageResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/admin/stats/tld-coverage\x12h\n" +
+	"\vSetLogLevel\x12\x1b.bell.v1.SetLogLevelRequest\x1a\x1c.bell.v1.SetLogLevelResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/admin/log-level2\x85\x05\n" +
+	"\fAdminService\x12X\n" +
+	"\fCreateAPIKey\x12\x1c.bell.v1.CreateAPIKeyRequest\x1a\x0f.bell.v1.APIKey\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/admin/keys\x12i\n" +
+	"\fRotateAPIKey\x12\x1c.bell.v1.RotateAPIKeyRequest\x1a\x0f.bell.v1.APIKey\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/admin/keys/{api_key}:rotate\x12i\n" +
+	"\fRevokeAPIKey\x12\x1c.bell.v1.RevokeAPIKeyRequest\x1a\x0f.bell.v1.APIKey\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/admin/keys/{api_key}:revoke\x12o\n" +
+	"\x0eSetAPIKeyQuota\x12\x1e.bell.v1.SetAPIKeyQuotaRequest\x1a\x0f.bell.v1.APIKey\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/admin/keys/{api_key}:setQuota\x12r\n" +
+	"\x0fSetAPIKeyScopes\x12\x1f.bell.v1.SetAPIKeyScopesRequest\x1a\x0f.bell.v1.APIKey\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/v1/admin/keys/{api_key}:setScopes\x12`\n" +
+	"\vListAPIKeys\x12\x1b.bell.v1.ListAPIKeysRequest\x1a\x1c.bell.v1.ListAPIKeysResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/admin/keysB\xe3\x02\x92A\xe1\x01\x12g\n" +
+	"\fBell DNS API\x12RDNS records and zone analytics collected from CZDS zone files and live resolution.2\x031.02\x10application/json:\x10application/jsonZ@\n" +
+	">\n" +
+	"\n" +
+	"ApiKeyAuth\x120\b\x02\x12\x1fAPI key from the api_keys table\x1a\tX-API-Key \x02b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"ApiKeyAuth\x12\x00\n" +
+	"\vcom.bell.v1B\tBellProtoP\x01Z'github.com/moos3/bell/pb/bell/v1;bellv1\xa2\x02\x03BXX\xaa\x02\aBell.V1\xca\x02\aBell\\V1\xe2\x02\x13Bell\\V1\\GPBMetadata\xea\x02\bBell::V1b\x06proto3"
+
+var (
+	file_bell_v1_bell_proto_rawDescOnce sync.Once
+	file_bell_v1_bell_proto_rawDescData []byte
+)
+
+func file_bell_v1_bell_proto_rawDescGZIP() []byte {
+	file_bell_v1_bell_proto_rawDescOnce.Do(func() {
+		file_bell_v1_bell_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bell_v1_bell_proto_rawDesc), len(file_bell_v1_bell_proto_rawDesc)))
 	})
 	return file_bell_v1_bell_proto_rawDescData
 }
 
-var file_bell_v1_bell_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_bell_v1_bell_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_bell_v1_bell_proto_msgTypes = make([]protoimpl.MessageInfo, 164)
 var file_bell_v1_bell_proto_goTypes = []any{
-	(*AuthenticateRequest)(nil),  // 0: bell.v1.AuthenticateRequest
-	(*AuthenticateResponse)(nil), // 1: bell.v1.AuthenticateResponse
-	(*GetRecordsRequest)(nil),    // 2: bell.v1.GetRecordsRequest
-	(*DNSRecord)(nil),            // 3: bell.v1.DNSRecord
-	(*GetRecordsResponse)(nil),   // 4: bell.v1.GetRecordsResponse
+	(RecordOrder)(0),                         // 0: bell.v1.RecordOrder
+	(TopNMetric)(0),                          // 1: bell.v1.TopNMetric
+	(*AuthenticateRequest)(nil),              // 2: bell.v1.AuthenticateRequest
+	(*AuthenticateResponse)(nil),             // 3: bell.v1.AuthenticateResponse
+	(*GetSigningKeysRequest)(nil),            // 4: bell.v1.GetSigningKeysRequest
+	(*SigningKey)(nil),                       // 5: bell.v1.SigningKey
+	(*GetSigningKeysResponse)(nil),           // 6: bell.v1.GetSigningKeysResponse
+	(*GetRecordsRequest)(nil),                // 7: bell.v1.GetRecordsRequest
+	(*DNSRecord)(nil),                        // 8: bell.v1.DNSRecord
+	(*DGAScore)(nil),                         // 9: bell.v1.DGAScore
+	(*GetRecordsResponse)(nil),               // 10: bell.v1.GetRecordsResponse
+	(*GetRecordHistoryRequest)(nil),          // 11: bell.v1.GetRecordHistoryRequest
+	(*RecordHistoryEntry)(nil),               // 12: bell.v1.RecordHistoryEntry
+	(*GetRecordHistoryResponse)(nil),         // 13: bell.v1.GetRecordHistoryResponse
+	(*GetRecordsBatchRequest)(nil),           // 14: bell.v1.GetRecordsBatchRequest
+	(*PatternExpansion)(nil),                 // 15: bell.v1.PatternExpansion
+	(*DomainRecords)(nil),                    // 16: bell.v1.DomainRecords
+	(*GetRecordsBatchResponse)(nil),          // 17: bell.v1.GetRecordsBatchResponse
+	(*StreamRecordsRequest)(nil),             // 18: bell.v1.StreamRecordsRequest
+	(*StreamedRecord)(nil),                   // 19: bell.v1.StreamedRecord
+	(*StreamRecordBatchesRequest)(nil),       // 20: bell.v1.StreamRecordBatchesRequest
+	(*ArrowMessage)(nil),                     // 21: bell.v1.ArrowMessage
+	(*SubscribeRecordChangesRequest)(nil),    // 22: bell.v1.SubscribeRecordChangesRequest
+	(*RecordChange)(nil),                     // 23: bell.v1.RecordChange
+	(*MergeProvenance)(nil),                  // 24: bell.v1.MergeProvenance
+	(*TLDStatus)(nil),                        // 25: bell.v1.TLDStatus
+	(*ListTLDsRequest)(nil),                  // 26: bell.v1.ListTLDsRequest
+	(*ListTLDsResponse)(nil),                 // 27: bell.v1.ListTLDsResponse
+	(*GetTLDStatusRequest)(nil),              // 28: bell.v1.GetTLDStatusRequest
+	(*GetTLDStatusResponse)(nil),             // 29: bell.v1.GetTLDStatusResponse
+	(*ListDomainsByTLDRequest)(nil),          // 30: bell.v1.ListDomainsByTLDRequest
+	(*TLDDomain)(nil),                        // 31: bell.v1.TLDDomain
+	(*ListDomainsByTLDResponse)(nil),         // 32: bell.v1.ListDomainsByTLDResponse
+	(*GetTopNRequest)(nil),                   // 33: bell.v1.GetTopNRequest
+	(*TopNEntry)(nil),                        // 34: bell.v1.TopNEntry
+	(*GetTopNResponse)(nil),                  // 35: bell.v1.GetTopNResponse
+	(*GetKeywordTrendsRequest)(nil),          // 36: bell.v1.GetKeywordTrendsRequest
+	(*KeywordTrend)(nil),                     // 37: bell.v1.KeywordTrend
+	(*GetKeywordTrendsResponse)(nil),         // 38: bell.v1.GetKeywordTrendsResponse
+	(*GetDNSSECAdoptionRequest)(nil),         // 39: bell.v1.GetDNSSECAdoptionRequest
+	(*DNSSECAdoptionPoint)(nil),              // 40: bell.v1.DNSSECAdoptionPoint
+	(*DNSSECAlgorithmUsage)(nil),             // 41: bell.v1.DNSSECAlgorithmUsage
+	(*GetDNSSECAdoptionResponse)(nil),        // 42: bell.v1.GetDNSSECAdoptionResponse
+	(*ListDomainClustersRequest)(nil),        // 43: bell.v1.ListDomainClustersRequest
+	(*DomainCluster)(nil),                    // 44: bell.v1.DomainCluster
+	(*ClusterDomain)(nil),                    // 45: bell.v1.ClusterDomain
+	(*GetRecordActivityRequest)(nil),         // 46: bell.v1.GetRecordActivityRequest
+	(*RecordActivityPoint)(nil),              // 47: bell.v1.RecordActivityPoint
+	(*GetRecordActivityResponse)(nil),        // 48: bell.v1.GetRecordActivityResponse
+	(*GetSubdomainActivityRequest)(nil),      // 49: bell.v1.GetSubdomainActivityRequest
+	(*SubdomainActivityPoint)(nil),           // 50: bell.v1.SubdomainActivityPoint
+	(*GetSubdomainActivityResponse)(nil),     // 51: bell.v1.GetSubdomainActivityResponse
+	(*ListDomainClustersResponse)(nil),       // 52: bell.v1.ListDomainClustersResponse
+	(*GetDomainClusterRequest)(nil),          // 53: bell.v1.GetDomainClusterRequest
+	(*GetTTLStatsRequest)(nil),               // 54: bell.v1.GetTTLStatsRequest
+	(*TTLBucket)(nil),                        // 55: bell.v1.TTLBucket
+	(*TTLAnomaly)(nil),                       // 56: bell.v1.TTLAnomaly
+	(*GetTTLStatsResponse)(nil),              // 57: bell.v1.GetTTLStatsResponse
+	(*GetTLDCoverageRequest)(nil),            // 58: bell.v1.GetTLDCoverageRequest
+	(*TLDCoverageGap)(nil),                   // 59: bell.v1.TLDCoverageGap
+	(*GetTLDCoverageResponse)(nil),           // 60: bell.v1.GetTLDCoverageResponse
+	(*SetLogLevelRequest)(nil),               // 61: bell.v1.SetLogLevelRequest
+	(*SetLogLevelResponse)(nil),              // 62: bell.v1.SetLogLevelResponse
+	(*CheckDomainsRequest)(nil),              // 63: bell.v1.CheckDomainsRequest
+	(*DomainPresence)(nil),                   // 64: bell.v1.DomainPresence
+	(*CheckDomainsResponse)(nil),             // 65: bell.v1.CheckDomainsResponse
+	(*CountDomainsRequest)(nil),              // 66: bell.v1.CountDomainsRequest
+	(*CountRecordsRequest)(nil),              // 67: bell.v1.CountRecordsRequest
+	(*CountResponse)(nil),                    // 68: bell.v1.CountResponse
+	(*StartExportRequest)(nil),               // 69: bell.v1.StartExportRequest
+	(*ExportJob)(nil),                        // 70: bell.v1.ExportJob
+	(*GetExportRequest)(nil),                 // 71: bell.v1.GetExportRequest
+	(*ListExportsRequest)(nil),               // 72: bell.v1.ListExportsRequest
+	(*ListExportsResponse)(nil),              // 73: bell.v1.ListExportsResponse
+	(*GetAbuseContactsRequest)(nil),          // 74: bell.v1.GetAbuseContactsRequest
+	(*Registrar)(nil),                        // 75: bell.v1.Registrar
+	(*AbuseContact)(nil),                     // 76: bell.v1.AbuseContact
+	(*GetAbuseContactsResponse)(nil),         // 77: bell.v1.GetAbuseContactsResponse
+	(*GetDomainLifecycleRequest)(nil),        // 78: bell.v1.GetDomainLifecycleRequest
+	(*LifecycleEvent)(nil),                   // 79: bell.v1.LifecycleEvent
+	(*GetDomainLifecycleResponse)(nil),       // 80: bell.v1.GetDomainLifecycleResponse
+	(*VerifyDomainRequest)(nil),              // 81: bell.v1.VerifyDomainRequest
+	(*RecordSetChecksum)(nil),                // 82: bell.v1.RecordSetChecksum
+	(*RecordSetVerification)(nil),            // 83: bell.v1.RecordSetVerification
+	(*VerifyDomainResponse)(nil),             // 84: bell.v1.VerifyDomainResponse
+	(*LiveLookupOptions)(nil),                // 85: bell.v1.LiveLookupOptions
+	(*LookupLiveRequest)(nil),                // 86: bell.v1.LookupLiveRequest
+	(*LiveAnswer)(nil),                       // 87: bell.v1.LiveAnswer
+	(*LookupLiveResponse)(nil),               // 88: bell.v1.LookupLiveResponse
+	(*RefreshDomainRequest)(nil),             // 89: bell.v1.RefreshDomainRequest
+	(*GeoAnswer)(nil),                        // 90: bell.v1.GeoAnswer
+	(*RefreshDomainResponse)(nil),            // 91: bell.v1.RefreshDomainResponse
+	(*TraceResolutionRequest)(nil),           // 92: bell.v1.TraceResolutionRequest
+	(*TraceStep)(nil),                        // 93: bell.v1.TraceStep
+	(*TraceResolutionResponse)(nil),          // 94: bell.v1.TraceResolutionResponse
+	(*GetServiceRecordsRequest)(nil),         // 95: bell.v1.GetServiceRecordsRequest
+	(*ServiceRecord)(nil),                    // 96: bell.v1.ServiceRecord
+	(*GetServiceRecordsResponse)(nil),        // 97: bell.v1.GetServiceRecordsResponse
+	(*ValidateDANERequest)(nil),              // 98: bell.v1.ValidateDANERequest
+	(*TLSAValidation)(nil),                   // 99: bell.v1.TLSAValidation
+	(*ValidateDANEResponse)(nil),             // 100: bell.v1.ValidateDANEResponse
+	(*GetPTRRequest)(nil),                    // 101: bell.v1.GetPTRRequest
+	(*PTRRecord)(nil),                        // 102: bell.v1.PTRRecord
+	(*GetPTRResponse)(nil),                   // 103: bell.v1.GetPTRResponse
+	(*GetPTRRangeRequest)(nil),               // 104: bell.v1.GetPTRRangeRequest
+	(*GetPTRRangeResponse)(nil),              // 105: bell.v1.GetPTRRangeResponse
+	(*GetDomainsByNameserverRequest)(nil),    // 106: bell.v1.GetDomainsByNameserverRequest
+	(*DelegatedDomain)(nil),                  // 107: bell.v1.DelegatedDomain
+	(*GetDomainsByNameserverResponse)(nil),   // 108: bell.v1.GetDomainsByNameserverResponse
+	(*ListSubdomainsRequest)(nil),            // 109: bell.v1.ListSubdomainsRequest
+	(*Subdomain)(nil),                        // 110: bell.v1.Subdomain
+	(*ListSubdomainsResponse)(nil),           // 111: bell.v1.ListSubdomainsResponse
+	(*SearchDomainsRequest)(nil),             // 112: bell.v1.SearchDomainsRequest
+	(*DomainMatch)(nil),                      // 113: bell.v1.DomainMatch
+	(*SearchDomainsResponse)(nil),            // 114: bell.v1.SearchDomainsResponse
+	(*SearchRecordsRequest)(nil),             // 115: bell.v1.SearchRecordsRequest
+	(*SearchRecordsResponse)(nil),            // 116: bell.v1.SearchRecordsResponse
+	(*KeyPreferences)(nil),                   // 117: bell.v1.KeyPreferences
+	(*GetKeyPreferencesRequest)(nil),         // 118: bell.v1.GetKeyPreferencesRequest
+	(*SetKeyPreferencesRequest)(nil),         // 119: bell.v1.SetKeyPreferencesRequest
+	(*ReportSchedule)(nil),                   // 120: bell.v1.ReportSchedule
+	(*CreateReportScheduleRequest)(nil),      // 121: bell.v1.CreateReportScheduleRequest
+	(*ListReportSchedulesRequest)(nil),       // 122: bell.v1.ListReportSchedulesRequest
+	(*ListReportSchedulesResponse)(nil),      // 123: bell.v1.ListReportSchedulesResponse
+	(*DeleteReportScheduleRequest)(nil),      // 124: bell.v1.DeleteReportScheduleRequest
+	(*DeleteReportScheduleResponse)(nil),     // 125: bell.v1.DeleteReportScheduleResponse
+	(*Organization)(nil),                     // 126: bell.v1.Organization
+	(*OrganizationKey)(nil),                  // 127: bell.v1.OrganizationKey
+	(*APIKey)(nil),                           // 128: bell.v1.APIKey
+	(*CreateAPIKeyRequest)(nil),              // 129: bell.v1.CreateAPIKeyRequest
+	(*RotateAPIKeyRequest)(nil),              // 130: bell.v1.RotateAPIKeyRequest
+	(*RevokeAPIKeyRequest)(nil),              // 131: bell.v1.RevokeAPIKeyRequest
+	(*SetAPIKeyQuotaRequest)(nil),            // 132: bell.v1.SetAPIKeyQuotaRequest
+	(*SetAPIKeyScopesRequest)(nil),           // 133: bell.v1.SetAPIKeyScopesRequest
+	(*ListAPIKeysRequest)(nil),               // 134: bell.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),              // 135: bell.v1.ListAPIKeysResponse
+	(*GetOrganizationRequest)(nil),           // 136: bell.v1.GetOrganizationRequest
+	(*CreateOrganizationKeyRequest)(nil),     // 137: bell.v1.CreateOrganizationKeyRequest
+	(*UpdateOrganizationKeyRequest)(nil),     // 138: bell.v1.UpdateOrganizationKeyRequest
+	(*SetOrganizationWatchlistRequest)(nil),  // 139: bell.v1.SetOrganizationWatchlistRequest
+	(*ImportWatchlistRequest)(nil),           // 140: bell.v1.ImportWatchlistRequest
+	(*RejectedWatchlistEntry)(nil),           // 141: bell.v1.RejectedWatchlistEntry
+	(*ImportWatchlistResponse)(nil),          // 142: bell.v1.ImportWatchlistResponse
+	(*ImportDomainsRequest)(nil),             // 143: bell.v1.ImportDomainsRequest
+	(*RejectedDomain)(nil),                   // 144: bell.v1.RejectedDomain
+	(*ImportDomainsResponse)(nil),            // 145: bell.v1.ImportDomainsResponse
+	(*SetDomainRestrictionRequest)(nil),      // 146: bell.v1.SetDomainRestrictionRequest
+	(*DomainRestriction)(nil),                // 147: bell.v1.DomainRestriction
+	(*GetUsageRequest)(nil),                  // 148: bell.v1.GetUsageRequest
+	(*DailyUsage)(nil),                       // 149: bell.v1.DailyUsage
+	(*GetUsageResponse)(nil),                 // 150: bell.v1.GetUsageResponse
+	(*GetOrganizationUsageRequest)(nil),      // 151: bell.v1.GetOrganizationUsageRequest
+	(*OrganizationUsage)(nil),                // 152: bell.v1.OrganizationUsage
+	(*GetOrganizationUsageResponse)(nil),     // 153: bell.v1.GetOrganizationUsageResponse
+	(*CreateOrganizationRequest)(nil),        // 154: bell.v1.CreateOrganizationRequest
+	(*SetOrganizationQuotaRequest)(nil),      // 155: bell.v1.SetOrganizationQuotaRequest
+	(*TailEventsRequest)(nil),                // 156: bell.v1.TailEventsRequest
+	(*IngestEvent)(nil),                      // 157: bell.v1.IngestEvent
+	(*ListNameserverReputationRequest)(nil),  // 158: bell.v1.ListNameserverReputationRequest
+	(*NameserverReputation)(nil),             // 159: bell.v1.NameserverReputation
+	(*ListNameserverReputationResponse)(nil), // 160: bell.v1.ListNameserverReputationResponse
+	(*ListDNSServersRequest)(nil),            // 161: bell.v1.ListDNSServersRequest
+	(*DNSServer)(nil),                        // 162: bell.v1.DNSServer
+	(*ListDNSServersResponse)(nil),           // 163: bell.v1.ListDNSServersResponse
+	(*UpdateDNSServersRequest)(nil),          // 164: bell.v1.UpdateDNSServersRequest
+	nil,                                      // 165: bell.v1.GetRecordsBatchResponse.ResultsEntry
 }
 var file_bell_v1_bell_proto_depIdxs = []int32{
-	3, // 0: bell.v1.GetRecordsResponse.records:type_name -> bell.v1.DNSRecord
-	0, // 1: bell.v1.DNSService.Authenticate:input_type -> bell.v1.AuthenticateRequest
-	2, // 2: bell.v1.DNSService.GetRecords:input_type -> bell.v1.GetRecordsRequest
-	1, // 3: bell.v1.DNSService.Authenticate:output_type -> bell.v1.AuthenticateResponse
-	4, // 4: bell.v1.DNSService.GetRecords:output_type -> bell.v1.GetRecordsResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5,   // 0: bell.v1.GetSigningKeysResponse.keys:type_name -> bell.v1.SigningKey
+	0,   // 1: bell.v1.GetRecordsRequest.order:type_name -> bell.v1.RecordOrder
+	8,   // 2: bell.v1.GetRecordsResponse.records:type_name -> bell.v1.DNSRecord
+	9,   // 3: bell.v1.GetRecordsResponse.dga:type_name -> bell.v1.DGAScore
+	24,  // 4: bell.v1.GetRecordsResponse.provenance:type_name -> bell.v1.MergeProvenance
+	90,  // 5: bell.v1.GetRecordsResponse.geo_answers:type_name -> bell.v1.GeoAnswer
+	12,  // 6: bell.v1.GetRecordHistoryResponse.entries:type_name -> bell.v1.RecordHistoryEntry
+	0,   // 7: bell.v1.GetRecordsBatchRequest.order:type_name -> bell.v1.RecordOrder
+	8,   // 8: bell.v1.DomainRecords.records:type_name -> bell.v1.DNSRecord
+	165, // 9: bell.v1.GetRecordsBatchResponse.results:type_name -> bell.v1.GetRecordsBatchResponse.ResultsEntry
+	15,  // 10: bell.v1.GetRecordsBatchResponse.expansions:type_name -> bell.v1.PatternExpansion
+	8,   // 11: bell.v1.StreamedRecord.record:type_name -> bell.v1.DNSRecord
+	25,  // 12: bell.v1.ListTLDsResponse.tlds:type_name -> bell.v1.TLDStatus
+	25,  // 13: bell.v1.GetTLDStatusResponse.status:type_name -> bell.v1.TLDStatus
+	31,  // 14: bell.v1.ListDomainsByTLDResponse.domains:type_name -> bell.v1.TLDDomain
+	1,   // 15: bell.v1.GetTopNRequest.metric:type_name -> bell.v1.TopNMetric
+	34,  // 16: bell.v1.GetTopNResponse.entries:type_name -> bell.v1.TopNEntry
+	37,  // 17: bell.v1.GetKeywordTrendsResponse.trends:type_name -> bell.v1.KeywordTrend
+	40,  // 18: bell.v1.GetDNSSECAdoptionResponse.series:type_name -> bell.v1.DNSSECAdoptionPoint
+	41,  // 19: bell.v1.GetDNSSECAdoptionResponse.algorithms:type_name -> bell.v1.DNSSECAlgorithmUsage
+	45,  // 20: bell.v1.DomainCluster.domains:type_name -> bell.v1.ClusterDomain
+	47,  // 21: bell.v1.GetRecordActivityResponse.series:type_name -> bell.v1.RecordActivityPoint
+	50,  // 22: bell.v1.GetSubdomainActivityResponse.series:type_name -> bell.v1.SubdomainActivityPoint
+	44,  // 23: bell.v1.ListDomainClustersResponse.clusters:type_name -> bell.v1.DomainCluster
+	55,  // 24: bell.v1.GetTTLStatsResponse.buckets:type_name -> bell.v1.TTLBucket
+	56,  // 25: bell.v1.GetTTLStatsResponse.anomalies:type_name -> bell.v1.TTLAnomaly
+	59,  // 26: bell.v1.GetTLDCoverageResponse.gaps:type_name -> bell.v1.TLDCoverageGap
+	64,  // 27: bell.v1.CheckDomainsResponse.results:type_name -> bell.v1.DomainPresence
+	70,  // 28: bell.v1.ListExportsResponse.exports:type_name -> bell.v1.ExportJob
+	75,  // 29: bell.v1.GetAbuseContactsResponse.registrar:type_name -> bell.v1.Registrar
+	76,  // 30: bell.v1.GetAbuseContactsResponse.contacts:type_name -> bell.v1.AbuseContact
+	75,  // 31: bell.v1.LifecycleEvent.old_registrar:type_name -> bell.v1.Registrar
+	75,  // 32: bell.v1.LifecycleEvent.registrar:type_name -> bell.v1.Registrar
+	79,  // 33: bell.v1.GetDomainLifecycleResponse.events:type_name -> bell.v1.LifecycleEvent
+	82,  // 34: bell.v1.RecordSetVerification.zone:type_name -> bell.v1.RecordSetChecksum
+	82,  // 35: bell.v1.RecordSetVerification.query:type_name -> bell.v1.RecordSetChecksum
+	82,  // 36: bell.v1.RecordSetVerification.database:type_name -> bell.v1.RecordSetChecksum
+	82,  // 37: bell.v1.RecordSetVerification.live:type_name -> bell.v1.RecordSetChecksum
+	83,  // 38: bell.v1.VerifyDomainResponse.record_sets:type_name -> bell.v1.RecordSetVerification
+	85,  // 39: bell.v1.LookupLiveRequest.options:type_name -> bell.v1.LiveLookupOptions
+	87,  // 40: bell.v1.LookupLiveResponse.answers:type_name -> bell.v1.LiveAnswer
+	90,  // 41: bell.v1.RefreshDomainResponse.answers:type_name -> bell.v1.GeoAnswer
+	93,  // 42: bell.v1.TraceResolutionResponse.steps:type_name -> bell.v1.TraceStep
+	96,  // 43: bell.v1.GetServiceRecordsResponse.records:type_name -> bell.v1.ServiceRecord
+	99,  // 44: bell.v1.ValidateDANEResponse.records:type_name -> bell.v1.TLSAValidation
+	102, // 45: bell.v1.GetPTRResponse.records:type_name -> bell.v1.PTRRecord
+	102, // 46: bell.v1.GetPTRRangeResponse.records:type_name -> bell.v1.PTRRecord
+	107, // 47: bell.v1.GetDomainsByNameserverResponse.domains:type_name -> bell.v1.DelegatedDomain
+	110, // 48: bell.v1.ListSubdomainsResponse.subdomains:type_name -> bell.v1.Subdomain
+	113, // 49: bell.v1.SearchDomainsResponse.domains:type_name -> bell.v1.DomainMatch
+	19,  // 50: bell.v1.SearchRecordsResponse.records:type_name -> bell.v1.StreamedRecord
+	117, // 51: bell.v1.SetKeyPreferencesRequest.preferences:type_name -> bell.v1.KeyPreferences
+	120, // 52: bell.v1.CreateReportScheduleRequest.schedule:type_name -> bell.v1.ReportSchedule
+	120, // 53: bell.v1.ListReportSchedulesResponse.schedules:type_name -> bell.v1.ReportSchedule
+	127, // 54: bell.v1.Organization.keys:type_name -> bell.v1.OrganizationKey
+	128, // 55: bell.v1.ListAPIKeysResponse.keys:type_name -> bell.v1.APIKey
+	126, // 56: bell.v1.ImportWatchlistResponse.organization:type_name -> bell.v1.Organization
+	141, // 57: bell.v1.ImportWatchlistResponse.rejected:type_name -> bell.v1.RejectedWatchlistEntry
+	144, // 58: bell.v1.ImportDomainsResponse.rejected:type_name -> bell.v1.RejectedDomain
+	149, // 59: bell.v1.GetUsageResponse.days:type_name -> bell.v1.DailyUsage
+	152, // 60: bell.v1.GetOrganizationUsageResponse.usage:type_name -> bell.v1.OrganizationUsage
+	159, // 61: bell.v1.ListNameserverReputationResponse.nameservers:type_name -> bell.v1.NameserverReputation
+	162, // 62: bell.v1.ListDNSServersResponse.servers:type_name -> bell.v1.DNSServer
+	16,  // 63: bell.v1.GetRecordsBatchResponse.ResultsEntry.value:type_name -> bell.v1.DomainRecords
+	2,   // 64: bell.v1.DNSService.Authenticate:input_type -> bell.v1.AuthenticateRequest
+	4,   // 65: bell.v1.DNSService.GetSigningKeys:input_type -> bell.v1.GetSigningKeysRequest
+	7,   // 66: bell.v1.DNSService.GetRecords:input_type -> bell.v1.GetRecordsRequest
+	11,  // 67: bell.v1.DNSService.GetRecordHistory:input_type -> bell.v1.GetRecordHistoryRequest
+	14,  // 68: bell.v1.DNSService.GetRecordsBatch:input_type -> bell.v1.GetRecordsBatchRequest
+	18,  // 69: bell.v1.DNSService.StreamRecords:input_type -> bell.v1.StreamRecordsRequest
+	20,  // 70: bell.v1.DNSService.StreamRecordBatches:input_type -> bell.v1.StreamRecordBatchesRequest
+	22,  // 71: bell.v1.DNSService.SubscribeRecordChanges:input_type -> bell.v1.SubscribeRecordChangesRequest
+	26,  // 72: bell.v1.DNSService.ListTLDs:input_type -> bell.v1.ListTLDsRequest
+	28,  // 73: bell.v1.DNSService.GetTLDStatus:input_type -> bell.v1.GetTLDStatusRequest
+	30,  // 74: bell.v1.DNSService.ListDomainsByTLD:input_type -> bell.v1.ListDomainsByTLDRequest
+	33,  // 75: bell.v1.DNSService.GetTopN:input_type -> bell.v1.GetTopNRequest
+	36,  // 76: bell.v1.DNSService.GetKeywordTrends:input_type -> bell.v1.GetKeywordTrendsRequest
+	39,  // 77: bell.v1.DNSService.GetDNSSECAdoption:input_type -> bell.v1.GetDNSSECAdoptionRequest
+	43,  // 78: bell.v1.DNSService.ListDomainClusters:input_type -> bell.v1.ListDomainClustersRequest
+	53,  // 79: bell.v1.DNSService.GetDomainCluster:input_type -> bell.v1.GetDomainClusterRequest
+	46,  // 80: bell.v1.DNSService.GetRecordActivity:input_type -> bell.v1.GetRecordActivityRequest
+	49,  // 81: bell.v1.DNSService.GetSubdomainActivity:input_type -> bell.v1.GetSubdomainActivityRequest
+	54,  // 82: bell.v1.DNSService.GetTTLStats:input_type -> bell.v1.GetTTLStatsRequest
+	63,  // 83: bell.v1.DNSService.CheckDomains:input_type -> bell.v1.CheckDomainsRequest
+	66,  // 84: bell.v1.DNSService.CountDomains:input_type -> bell.v1.CountDomainsRequest
+	67,  // 85: bell.v1.DNSService.CountRecords:input_type -> bell.v1.CountRecordsRequest
+	69,  // 86: bell.v1.DNSService.StartExport:input_type -> bell.v1.StartExportRequest
+	71,  // 87: bell.v1.DNSService.GetExport:input_type -> bell.v1.GetExportRequest
+	72,  // 88: bell.v1.DNSService.ListExports:input_type -> bell.v1.ListExportsRequest
+	74,  // 89: bell.v1.DNSService.GetAbuseContacts:input_type -> bell.v1.GetAbuseContactsRequest
+	78,  // 90: bell.v1.DNSService.GetDomainLifecycle:input_type -> bell.v1.GetDomainLifecycleRequest
+	81,  // 91: bell.v1.DNSService.VerifyDomain:input_type -> bell.v1.VerifyDomainRequest
+	86,  // 92: bell.v1.DNSService.LookupLive:input_type -> bell.v1.LookupLiveRequest
+	89,  // 93: bell.v1.DNSService.RefreshDomain:input_type -> bell.v1.RefreshDomainRequest
+	92,  // 94: bell.v1.DNSService.TraceResolution:input_type -> bell.v1.TraceResolutionRequest
+	95,  // 95: bell.v1.DNSService.GetServiceRecords:input_type -> bell.v1.GetServiceRecordsRequest
+	98,  // 96: bell.v1.DNSService.ValidateDANE:input_type -> bell.v1.ValidateDANERequest
+	101, // 97: bell.v1.DNSService.GetPTR:input_type -> bell.v1.GetPTRRequest
+	104, // 98: bell.v1.DNSService.GetPTRRange:input_type -> bell.v1.GetPTRRangeRequest
+	106, // 99: bell.v1.DNSService.GetDomainsByNameserver:input_type -> bell.v1.GetDomainsByNameserverRequest
+	109, // 100: bell.v1.DNSService.ListSubdomains:input_type -> bell.v1.ListSubdomainsRequest
+	112, // 101: bell.v1.DNSService.SearchDomains:input_type -> bell.v1.SearchDomainsRequest
+	115, // 102: bell.v1.DNSService.SearchRecords:input_type -> bell.v1.SearchRecordsRequest
+	118, // 103: bell.v1.DNSService.GetKeyPreferences:input_type -> bell.v1.GetKeyPreferencesRequest
+	119, // 104: bell.v1.DNSService.SetKeyPreferences:input_type -> bell.v1.SetKeyPreferencesRequest
+	121, // 105: bell.v1.DNSService.CreateReportSchedule:input_type -> bell.v1.CreateReportScheduleRequest
+	122, // 106: bell.v1.DNSService.ListReportSchedules:input_type -> bell.v1.ListReportSchedulesRequest
+	124, // 107: bell.v1.DNSService.DeleteReportSchedule:input_type -> bell.v1.DeleteReportScheduleRequest
+	148, // 108: bell.v1.DNSService.GetUsage:input_type -> bell.v1.GetUsageRequest
+	136, // 109: bell.v1.DNSService.GetOrganization:input_type -> bell.v1.GetOrganizationRequest
+	137, // 110: bell.v1.DNSService.CreateOrganizationKey:input_type -> bell.v1.CreateOrganizationKeyRequest
+	138, // 111: bell.v1.DNSService.UpdateOrganizationKey:input_type -> bell.v1.UpdateOrganizationKeyRequest
+	139, // 112: bell.v1.DNSService.SetOrganizationWatchlist:input_type -> bell.v1.SetOrganizationWatchlistRequest
+	140, // 113: bell.v1.DNSService.ImportWatchlist:input_type -> bell.v1.ImportWatchlistRequest
+	143, // 114: bell.v1.DNSService.ImportDomains:input_type -> bell.v1.ImportDomainsRequest
+	151, // 115: bell.v1.DNSService.GetOrganizationUsage:input_type -> bell.v1.GetOrganizationUsageRequest
+	154, // 116: bell.v1.DNSService.CreateOrganization:input_type -> bell.v1.CreateOrganizationRequest
+	155, // 117: bell.v1.DNSService.SetOrganizationQuota:input_type -> bell.v1.SetOrganizationQuotaRequest
+	146, // 118: bell.v1.DNSService.SetDomainRestriction:input_type -> bell.v1.SetDomainRestrictionRequest
+	158, // 119: bell.v1.DNSService.ListNameserverReputation:input_type -> bell.v1.ListNameserverReputationRequest
+	156, // 120: bell.v1.DNSService.TailEvents:input_type -> bell.v1.TailEventsRequest
+	161, // 121: bell.v1.DNSService.ListDNSServers:input_type -> bell.v1.ListDNSServersRequest
+	164, // 122: bell.v1.DNSService.UpdateDNSServers:input_type -> bell.v1.UpdateDNSServersRequest
+	58,  // 123: bell.v1.DNSService.GetTLDCoverage:input_type -> bell.v1.GetTLDCoverageRequest
+	61,  // 124: bell.v1.DNSService.SetLogLevel:input_type -> bell.v1.SetLogLevelRequest
+	129, // 125: bell.v1.AdminService.CreateAPIKey:input_type -> bell.v1.CreateAPIKeyRequest
+	130, // 126: bell.v1.AdminService.RotateAPIKey:input_type -> bell.v1.RotateAPIKeyRequest
+	131, // 127: bell.v1.AdminService.RevokeAPIKey:input_type -> bell.v1.RevokeAPIKeyRequest
+	132, // 128: bell.v1.AdminService.SetAPIKeyQuota:input_type -> bell.v1.SetAPIKeyQuotaRequest
+	133, // 129: bell.v1.AdminService.SetAPIKeyScopes:input_type -> bell.v1.SetAPIKeyScopesRequest
+	134, // 130: bell.v1.AdminService.ListAPIKeys:input_type -> bell.v1.ListAPIKeysRequest
+	3,   // 131: bell.v1.DNSService.Authenticate:output_type -> bell.v1.AuthenticateResponse
+	6,   // 132: bell.v1.DNSService.GetSigningKeys:output_type -> bell.v1.GetSigningKeysResponse
+	10,  // 133: bell.v1.DNSService.GetRecords:output_type -> bell.v1.GetRecordsResponse
+	13,  // 134: bell.v1.DNSService.GetRecordHistory:output_type -> bell.v1.GetRecordHistoryResponse
+	17,  // 135: bell.v1.DNSService.GetRecordsBatch:output_type -> bell.v1.GetRecordsBatchResponse
+	19,  // 136: bell.v1.DNSService.StreamRecords:output_type -> bell.v1.StreamedRecord
+	21,  // 137: bell.v1.DNSService.StreamRecordBatches:output_type -> bell.v1.ArrowMessage
+	23,  // 138: bell.v1.DNSService.SubscribeRecordChanges:output_type -> bell.v1.RecordChange
+	27,  // 139: bell.v1.DNSService.ListTLDs:output_type -> bell.v1.ListTLDsResponse
+	29,  // 140: bell.v1.DNSService.GetTLDStatus:output_type -> bell.v1.GetTLDStatusResponse
+	32,  // 141: bell.v1.DNSService.ListDomainsByTLD:output_type -> bell.v1.ListDomainsByTLDResponse
+	35,  // 142: bell.v1.DNSService.GetTopN:output_type -> bell.v1.GetTopNResponse
+	38,  // 143: bell.v1.DNSService.GetKeywordTrends:output_type -> bell.v1.GetKeywordTrendsResponse
+	42,  // 144: bell.v1.DNSService.GetDNSSECAdoption:output_type -> bell.v1.GetDNSSECAdoptionResponse
+	52,  // 145: bell.v1.DNSService.ListDomainClusters:output_type -> bell.v1.ListDomainClustersResponse
+	44,  // 146: bell.v1.DNSService.GetDomainCluster:output_type -> bell.v1.DomainCluster
+	48,  // 147: bell.v1.DNSService.GetRecordActivity:output_type -> bell.v1.GetRecordActivityResponse
+	51,  // 148: bell.v1.DNSService.GetSubdomainActivity:output_type -> bell.v1.GetSubdomainActivityResponse
+	57,  // 149: bell.v1.DNSService.GetTTLStats:output_type -> bell.v1.GetTTLStatsResponse
+	65,  // 150: bell.v1.DNSService.CheckDomains:output_type -> bell.v1.CheckDomainsResponse
+	68,  // 151: bell.v1.DNSService.CountDomains:output_type -> bell.v1.CountResponse
+	68,  // 152: bell.v1.DNSService.CountRecords:output_type -> bell.v1.CountResponse
+	70,  // 153: bell.v1.DNSService.StartExport:output_type -> bell.v1.ExportJob
+	70,  // 154: bell.v1.DNSService.GetExport:output_type -> bell.v1.ExportJob
+	73,  // 155: bell.v1.DNSService.ListExports:output_type -> bell.v1.ListExportsResponse
+	77,  // 156: bell.v1.DNSService.GetAbuseContacts:output_type -> bell.v1.GetAbuseContactsResponse
+	80,  // 157: bell.v1.DNSService.GetDomainLifecycle:output_type -> bell.v1.GetDomainLifecycleResponse
+	84,  // 158: bell.v1.DNSService.VerifyDomain:output_type -> bell.v1.VerifyDomainResponse
+	88,  // 159: bell.v1.DNSService.LookupLive:output_type -> bell.v1.LookupLiveResponse
+	91,  // 160: bell.v1.DNSService.RefreshDomain:output_type -> bell.v1.RefreshDomainResponse
+	94,  // 161: bell.v1.DNSService.TraceResolution:output_type -> bell.v1.TraceResolutionResponse
+	97,  // 162: bell.v1.DNSService.GetServiceRecords:output_type -> bell.v1.GetServiceRecordsResponse
+	100, // 163: bell.v1.DNSService.ValidateDANE:output_type -> bell.v1.ValidateDANEResponse
+	103, // 164: bell.v1.DNSService.GetPTR:output_type -> bell.v1.GetPTRResponse
+	105, // 165: bell.v1.DNSService.GetPTRRange:output_type -> bell.v1.GetPTRRangeResponse
+	108, // 166: bell.v1.DNSService.GetDomainsByNameserver:output_type -> bell.v1.GetDomainsByNameserverResponse
+	111, // 167: bell.v1.DNSService.ListSubdomains:output_type -> bell.v1.ListSubdomainsResponse
+	114, // 168: bell.v1.DNSService.SearchDomains:output_type -> bell.v1.SearchDomainsResponse
+	116, // 169: bell.v1.DNSService.SearchRecords:output_type -> bell.v1.SearchRecordsResponse
+	117, // 170: bell.v1.DNSService.GetKeyPreferences:output_type -> bell.v1.KeyPreferences
+	117, // 171: bell.v1.DNSService.SetKeyPreferences:output_type -> bell.v1.KeyPreferences
+	120, // 172: bell.v1.DNSService.CreateReportSchedule:output_type -> bell.v1.ReportSchedule
+	123, // 173: bell.v1.DNSService.ListReportSchedules:output_type -> bell.v1.ListReportSchedulesResponse
+	125, // 174: bell.v1.DNSService.DeleteReportSchedule:output_type -> bell.v1.DeleteReportScheduleResponse
+	150, // 175: bell.v1.DNSService.GetUsage:output_type -> bell.v1.GetUsageResponse
+	126, // 176: bell.v1.DNSService.GetOrganization:output_type -> bell.v1.Organization
+	127, // 177: bell.v1.DNSService.CreateOrganizationKey:output_type -> bell.v1.OrganizationKey
+	127, // 178: bell.v1.DNSService.UpdateOrganizationKey:output_type -> bell.v1.OrganizationKey
+	126, // 179: bell.v1.DNSService.SetOrganizationWatchlist:output_type -> bell.v1.Organization
+	142, // 180: bell.v1.DNSService.ImportWatchlist:output_type -> bell.v1.ImportWatchlistResponse
+	145, // 181: bell.v1.DNSService.ImportDomains:output_type -> bell.v1.ImportDomainsResponse
+	153, // 182: bell.v1.DNSService.GetOrganizationUsage:output_type -> bell.v1.GetOrganizationUsageResponse
+	126, // 183: bell.v1.DNSService.CreateOrganization:output_type -> bell.v1.Organization
+	126, // 184: bell.v1.DNSService.SetOrganizationQuota:output_type -> bell.v1.Organization
+	147, // 185: bell.v1.DNSService.SetDomainRestriction:output_type -> bell.v1.DomainRestriction
+	160, // 186: bell.v1.DNSService.ListNameserverReputation:output_type -> bell.v1.ListNameserverReputationResponse
+	157, // 187: bell.v1.DNSService.TailEvents:output_type -> bell.v1.IngestEvent
+	163, // 188: bell.v1.DNSService.ListDNSServers:output_type -> bell.v1.ListDNSServersResponse
+	163, // 189: bell.v1.DNSService.UpdateDNSServers:output_type -> bell.v1.ListDNSServersResponse
+	60,  // 190: bell.v1.DNSService.GetTLDCoverage:output_type -> bell.v1.GetTLDCoverageResponse
+	62,  // 191: bell.v1.DNSService.SetLogLevel:output_type -> bell.v1.SetLogLevelResponse
+	128, // 192: bell.v1.AdminService.CreateAPIKey:output_type -> bell.v1.APIKey
+	128, // 193: bell.v1.AdminService.RotateAPIKey:output_type -> bell.v1.APIKey
+	128, // 194: bell.v1.AdminService.RevokeAPIKey:output_type -> bell.v1.APIKey
+	128, // 195: bell.v1.AdminService.SetAPIKeyQuota:output_type -> bell.v1.APIKey
+	128, // 196: bell.v1.AdminService.SetAPIKeyScopes:output_type -> bell.v1.APIKey
+	135, // 197: bell.v1.AdminService.ListAPIKeys:output_type -> bell.v1.ListAPIKeysResponse
+	131, // [131:198] is the sub-list for method output_type
+	64,  // [64:131] is the sub-list for method input_type
+	64,  // [64:64] is the sub-list for extension type_name
+	64,  // [64:64] is the sub-list for extension extendee
+	0,   // [0:64] is the sub-list for field type_name
 }
 
 func init() { file_bell_v1_bell_proto_init() }
@@ -413,84 +12715,22 @@ func file_bell_v1_bell_proto_init() {
 	if File_bell_v1_bell_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_bell_v1_bell_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*AuthenticateRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bell_v1_bell_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*AuthenticateResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bell_v1_bell_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*GetRecordsRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bell_v1_bell_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*DNSRecord); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_bell_v1_bell_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*GetRecordsResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_bell_v1_bell_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bell_v1_bell_proto_rawDesc), len(file_bell_v1_bell_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   164,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_bell_v1_bell_proto_goTypes,
 		DependencyIndexes: file_bell_v1_bell_proto_depIdxs,
+		EnumInfos:         file_bell_v1_bell_proto_enumTypes,
 		MessageInfos:      file_bell_v1_bell_proto_msgTypes,
 	}.Build()
 	File_bell_v1_bell_proto = out.File
-	file_bell_v1_bell_proto_rawDesc = nil
 	file_bell_v1_bell_proto_goTypes = nil
 	file_bell_v1_bell_proto_depIdxs = nil
 }
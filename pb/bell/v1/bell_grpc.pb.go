@@ -19,8 +19,67 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	DNSService_Authenticate_FullMethodName = "/bell.v1.DNSService/Authenticate"
-	DNSService_GetRecords_FullMethodName   = "/bell.v1.DNSService/GetRecords"
+	DNSService_Authenticate_FullMethodName             = "/bell.v1.DNSService/Authenticate"
+	DNSService_GetSigningKeys_FullMethodName           = "/bell.v1.DNSService/GetSigningKeys"
+	DNSService_GetRecords_FullMethodName               = "/bell.v1.DNSService/GetRecords"
+	DNSService_GetRecordHistory_FullMethodName         = "/bell.v1.DNSService/GetRecordHistory"
+	DNSService_GetRecordsBatch_FullMethodName          = "/bell.v1.DNSService/GetRecordsBatch"
+	DNSService_StreamRecords_FullMethodName            = "/bell.v1.DNSService/StreamRecords"
+	DNSService_StreamRecordBatches_FullMethodName      = "/bell.v1.DNSService/StreamRecordBatches"
+	DNSService_SubscribeRecordChanges_FullMethodName   = "/bell.v1.DNSService/SubscribeRecordChanges"
+	DNSService_ListTLDs_FullMethodName                 = "/bell.v1.DNSService/ListTLDs"
+	DNSService_GetTLDStatus_FullMethodName             = "/bell.v1.DNSService/GetTLDStatus"
+	DNSService_ListDomainsByTLD_FullMethodName         = "/bell.v1.DNSService/ListDomainsByTLD"
+	DNSService_GetTopN_FullMethodName                  = "/bell.v1.DNSService/GetTopN"
+	DNSService_GetKeywordTrends_FullMethodName         = "/bell.v1.DNSService/GetKeywordTrends"
+	DNSService_GetDNSSECAdoption_FullMethodName        = "/bell.v1.DNSService/GetDNSSECAdoption"
+	DNSService_ListDomainClusters_FullMethodName       = "/bell.v1.DNSService/ListDomainClusters"
+	DNSService_GetDomainCluster_FullMethodName         = "/bell.v1.DNSService/GetDomainCluster"
+	DNSService_GetRecordActivity_FullMethodName        = "/bell.v1.DNSService/GetRecordActivity"
+	DNSService_GetSubdomainActivity_FullMethodName     = "/bell.v1.DNSService/GetSubdomainActivity"
+	DNSService_GetTTLStats_FullMethodName              = "/bell.v1.DNSService/GetTTLStats"
+	DNSService_CheckDomains_FullMethodName             = "/bell.v1.DNSService/CheckDomains"
+	DNSService_CountDomains_FullMethodName             = "/bell.v1.DNSService/CountDomains"
+	DNSService_CountRecords_FullMethodName             = "/bell.v1.DNSService/CountRecords"
+	DNSService_StartExport_FullMethodName              = "/bell.v1.DNSService/StartExport"
+	DNSService_GetExport_FullMethodName                = "/bell.v1.DNSService/GetExport"
+	DNSService_ListExports_FullMethodName              = "/bell.v1.DNSService/ListExports"
+	DNSService_GetAbuseContacts_FullMethodName         = "/bell.v1.DNSService/GetAbuseContacts"
+	DNSService_GetDomainLifecycle_FullMethodName       = "/bell.v1.DNSService/GetDomainLifecycle"
+	DNSService_VerifyDomain_FullMethodName             = "/bell.v1.DNSService/VerifyDomain"
+	DNSService_LookupLive_FullMethodName               = "/bell.v1.DNSService/LookupLive"
+	DNSService_RefreshDomain_FullMethodName            = "/bell.v1.DNSService/RefreshDomain"
+	DNSService_TraceResolution_FullMethodName          = "/bell.v1.DNSService/TraceResolution"
+	DNSService_GetServiceRecords_FullMethodName        = "/bell.v1.DNSService/GetServiceRecords"
+	DNSService_ValidateDANE_FullMethodName             = "/bell.v1.DNSService/ValidateDANE"
+	DNSService_GetPTR_FullMethodName                   = "/bell.v1.DNSService/GetPTR"
+	DNSService_GetPTRRange_FullMethodName              = "/bell.v1.DNSService/GetPTRRange"
+	DNSService_GetDomainsByNameserver_FullMethodName   = "/bell.v1.DNSService/GetDomainsByNameserver"
+	DNSService_ListSubdomains_FullMethodName           = "/bell.v1.DNSService/ListSubdomains"
+	DNSService_SearchDomains_FullMethodName            = "/bell.v1.DNSService/SearchDomains"
+	DNSService_SearchRecords_FullMethodName            = "/bell.v1.DNSService/SearchRecords"
+	DNSService_GetKeyPreferences_FullMethodName        = "/bell.v1.DNSService/GetKeyPreferences"
+	DNSService_SetKeyPreferences_FullMethodName        = "/bell.v1.DNSService/SetKeyPreferences"
+	DNSService_CreateReportSchedule_FullMethodName     = "/bell.v1.DNSService/CreateReportSchedule"
+	DNSService_ListReportSchedules_FullMethodName      = "/bell.v1.DNSService/ListReportSchedules"
+	DNSService_DeleteReportSchedule_FullMethodName     = "/bell.v1.DNSService/DeleteReportSchedule"
+	DNSService_GetUsage_FullMethodName                 = "/bell.v1.DNSService/GetUsage"
+	DNSService_GetOrganization_FullMethodName          = "/bell.v1.DNSService/GetOrganization"
+	DNSService_CreateOrganizationKey_FullMethodName    = "/bell.v1.DNSService/CreateOrganizationKey"
+	DNSService_UpdateOrganizationKey_FullMethodName    = "/bell.v1.DNSService/UpdateOrganizationKey"
+	DNSService_SetOrganizationWatchlist_FullMethodName = "/bell.v1.DNSService/SetOrganizationWatchlist"
+	DNSService_ImportWatchlist_FullMethodName          = "/bell.v1.DNSService/ImportWatchlist"
+	DNSService_ImportDomains_FullMethodName            = "/bell.v1.DNSService/ImportDomains"
+	DNSService_GetOrganizationUsage_FullMethodName     = "/bell.v1.DNSService/GetOrganizationUsage"
+	DNSService_CreateOrganization_FullMethodName       = "/bell.v1.DNSService/CreateOrganization"
+	DNSService_SetOrganizationQuota_FullMethodName     = "/bell.v1.DNSService/SetOrganizationQuota"
+	DNSService_SetDomainRestriction_FullMethodName     = "/bell.v1.DNSService/SetDomainRestriction"
+	DNSService_ListNameserverReputation_FullMethodName = "/bell.v1.DNSService/ListNameserverReputation"
+	DNSService_TailEvents_FullMethodName               = "/bell.v1.DNSService/TailEvents"
+	DNSService_ListDNSServers_FullMethodName           = "/bell.v1.DNSService/ListDNSServers"
+	DNSService_UpdateDNSServers_FullMethodName         = "/bell.v1.DNSService/UpdateDNSServers"
+	DNSService_GetTLDCoverage_FullMethodName           = "/bell.v1.DNSService/GetTLDCoverage"
+	DNSService_SetLogLevel_FullMethodName              = "/bell.v1.DNSService/SetLogLevel"
 )
 
 // DNSServiceClient is the client API for DNSService service.
@@ -29,8 +88,185 @@ const (
 type DNSServiceClient interface {
 	// Authenticate validates an API key
 	Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
+	// GetSigningKeys returns the public keys that signed responses (asked for
+	// with an X-Bell-Sign: true header) and export files verify against
+	GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error)
 	// GetRecords retrieves DNS records for a domain, filterable by record type
 	GetRecords(ctx context.Context, in *GetRecordsRequest, opts ...grpc.CallOption) (*GetRecordsResponse, error)
+	// GetRecordHistory returns the timeline of the values a record type of a
+	// domain has had, with when each was first and last seen, for
+	// passive-DNS-style investigations
+	GetRecordHistory(ctx context.Context, in *GetRecordHistoryRequest, opts ...grpc.CallOption) (*GetRecordHistoryResponse, error)
+	// GetRecordsBatch retrieves the DNS records of many domains at once, with
+	// one query per shard instead of a call per domain
+	GetRecordsBatch(ctx context.Context, in *GetRecordsBatchRequest, opts ...grpc.CallOption) (*GetRecordsBatchResponse, error)
+	// StreamRecords streams the records of a domain or of a whole TLD as they
+	// are read from the database, for result sets too large to buffer
+	// (gRPC only). The x-data-freshness trailer gives the age of the oldest
+	// record streamed, overall and per source, as GetRecords' header does
+	StreamRecords(ctx context.Context, in *StreamRecordsRequest, opts ...grpc.CallOption) (DNSService_StreamRecordsClient, error)
+	// StreamRecordBatches streams the records of a domain or of a whole TLD as
+	// Arrow IPC columnar batches, for bulk analytical reads (gRPC only)
+	StreamRecordBatches(ctx context.Context, in *StreamRecordBatchesRequest, opts ...grpc.CallOption) (DNSService_StreamRecordBatchesClient, error)
+	// SubscribeRecordChanges streams changes to the record sets of some
+	// domains, or of every domain of a TLD, as the query worker observes them
+	// (gRPC only)
+	SubscribeRecordChanges(ctx context.Context, in *SubscribeRecordChangesRequest, opts ...grpc.CallOption) (DNSService_SubscribeRecordChangesClient, error)
+	// ListTLDs returns the ingestion status of every loaded TLD
+	ListTLDs(ctx context.Context, in *ListTLDsRequest, opts ...grpc.CallOption) (*ListTLDsResponse, error)
+	// GetTLDStatus returns the ingestion status of a single TLD
+	GetTLDStatus(ctx context.Context, in *GetTLDStatusRequest, opts ...grpc.CallOption) (*GetTLDStatusResponse, error)
+	// ListDomainsByTLD enumerates the domains of a TLD or public suffix, in
+	// name order, a page at a time
+	ListDomainsByTLD(ctx context.Context, in *ListDomainsByTLDRequest, opts ...grpc.CallOption) (*ListDomainsByTLDResponse, error)
+	// GetTopN returns precomputed rankings (e.g. top nameservers) per TLD or globally
+	GetTopN(ctx context.Context, in *GetTopNRequest, opts ...grpc.CallOption) (*GetTopNResponse, error)
+	// GetKeywordTrends returns the keywords trending in newly seen domain
+	// labels on a day, per TLD or globally
+	GetKeywordTrends(ctx context.Context, in *GetKeywordTrendsRequest, opts ...grpc.CallOption) (*GetKeywordTrendsResponse, error)
+	// GetDNSSECAdoption returns the daily share of signed delegations and the
+	// DNSSEC algorithms and key sizes in use, per TLD or globally
+	GetDNSSECAdoption(ctx context.Context, in *GetDNSSECAdoptionRequest, opts ...grpc.CallOption) (*GetDNSSECAdoptionResponse, error)
+	// ListDomainClusters returns the clusters of domains sharing nameservers,
+	// A records and a registration window, most recently active first
+	ListDomainClusters(ctx context.Context, in *ListDomainClustersRequest, opts ...grpc.CallOption) (*ListDomainClustersResponse, error)
+	// GetDomainCluster returns a cluster and its domains, by cluster ID or by
+	// one of its domains
+	GetDomainCluster(ctx context.Context, in *GetDomainClusterRequest, opts ...grpc.CallOption) (*DomainCluster, error)
+	// GetRecordActivity returns a domain's records observed, first seen and
+	// changed per day, from the rollups of the activity-rollups job
+	GetRecordActivity(ctx context.Context, in *GetRecordActivityRequest, opts ...grpc.CallOption) (*GetRecordActivityResponse, error)
+	// GetSubdomainActivity returns the subdomains first seen under an apex per
+	// week, from the rollups of the activity-rollups job
+	GetSubdomainActivity(ctx context.Context, in *GetSubdomainActivityRequest, opts ...grpc.CallOption) (*GetSubdomainActivityResponse, error)
+	// GetTTLStats returns the TTL distribution and recent TTL anomalies for a domain or TLD
+	GetTTLStats(ctx context.Context, in *GetTTLStatsRequest, opts ...grpc.CallOption) (*GetTTLStatsResponse, error)
+	// CheckDomains reports which of the given domains exist in the database
+	CheckDomains(ctx context.Context, in *CheckDomainsRequest, opts ...grpc.CallOption) (*CheckDomainsResponse, error)
+	// CountDomains counts the domains matching a filter, estimating counts too
+	// large to compute quickly
+	CountDomains(ctx context.Context, in *CountDomainsRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	// CountRecords counts the DNS records matching a filter, estimating counts
+	// too large to compute quickly
+	CountRecords(ctx context.Context, in *CountRecordsRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	// StartExport queues an export of the domains or DNS records matching a
+	// filter, written to object storage by the export worker, for result sets
+	// too large to page through
+	StartExport(ctx context.Context, in *StartExportRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	// GetExport returns an export of the calling key, with a signed, expiring
+	// download URL once it has succeeded
+	GetExport(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	// ListExports returns the exports of the calling key, newest first
+	ListExports(ctx context.Context, in *ListExportsRequest, opts ...grpc.CallOption) (*ListExportsResponse, error)
+	// GetAbuseContacts returns the registrar and abuse contacts for a domain from RDAP
+	GetAbuseContacts(ctx context.Context, in *GetAbuseContactsRequest, opts ...grpc.CallOption) (*GetAbuseContactsResponse, error)
+	// GetDomainLifecycle returns when a domain was first seen, its nameserver
+	// and record-set changes, classified by significance, and the registrar
+	// transfers detected for it
+	GetDomainLifecycle(ctx context.Context, in *GetDomainLifecycleRequest, opts ...grpc.CallOption) (*GetDomainLifecycleResponse, error)
+	// VerifyDomain compares a domain's record sets across the zone, the
+	// database and live DNS, reporting drift between them
+	VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error)
+	// LookupLive resolves any domain live through the server's resolver and
+	// returns the answers with their timing; nothing is stored and the domain
+	// need not be in the corpus
+	LookupLive(ctx context.Context, in *LookupLiveRequest, opts ...grpc.CallOption) (*LookupLiveResponse, error)
+	// RefreshDomain resolves a domain in the corpus at its authoritative
+	// nameservers, optionally on behalf of a client subnet, and stores the
+	// answers with their EDNS Client Subnet scope for GetRecords
+	RefreshDomain(ctx context.Context, in *RefreshDomainRequest, opts ...grpc.CallOption) (*RefreshDomainResponse, error)
+	// TraceResolution resolves a domain iteratively from the root, like
+	// dig +trace, and returns every referral on the way with its glue, DS
+	// records and timing, compared against the stored record set
+	TraceResolution(ctx context.Context, in *TraceResolutionRequest, opts ...grpc.CallOption) (*TraceResolutionResponse, error)
+	// GetServiceRecords returns the parsed SRV and NAPTR records of a service
+	// name such as _sip._tls.example.com, or of a domain for NAPTR
+	GetServiceRecords(ctx context.Context, in *GetServiceRecordsRequest, opts ...grpc.CallOption) (*GetServiceRecordsResponse, error)
+	// ValidateDANE fetches the live TLS certificate chain of a service and
+	// checks it against the stored TLSA records of its _port._tcp name
+	ValidateDANE(ctx context.Context, in *ValidateDANERequest, opts ...grpc.CallOption) (*ValidateDANEResponse, error)
+	// GetPTR returns the PTR records of an IPv4 or IPv6 address from ingested
+	// reverse zones, or resolved live
+	GetPTR(ctx context.Context, in *GetPTRRequest, opts ...grpc.CallOption) (*GetPTRResponse, error)
+	// GetPTRRange returns the PTR records of every address in a CIDR block,
+	// in address order
+	GetPTRRange(ctx context.Context, in *GetPTRRangeRequest, opts ...grpc.CallOption) (*GetPTRRangeResponse, error)
+	// GetDomainsByNameserver returns the domains delegated to a nameserver
+	// host, in name order, a page at a time
+	GetDomainsByNameserver(ctx context.Context, in *GetDomainsByNameserverRequest, opts ...grpc.CallOption) (*GetDomainsByNameserverResponse, error)
+	// ListSubdomains returns the domains under an apex, grouped by parent
+	// name, a page at a time
+	ListSubdomains(ctx context.Context, in *ListSubdomainsRequest, opts ...grpc.CallOption) (*ListSubdomainsResponse, error)
+	// SearchDomains returns the domains whose names match a wildcard pattern
+	// such as *bank* or paypal*.com, in name order, a page at a time
+	SearchDomains(ctx context.Context, in *SearchDomainsRequest, opts ...grpc.CallOption) (*SearchDomainsResponse, error)
+	// SearchRecords returns records whose data matches a POSIX regular
+	// expression, such as a domain verification token in TXT records. The
+	// scan is bounded by the server's timeout and result cap.
+	SearchRecords(ctx context.Context, in *SearchRecordsRequest, opts ...grpc.CallOption) (*SearchRecordsResponse, error)
+	// GetKeyPreferences returns the request defaults stored for the calling key
+	GetKeyPreferences(ctx context.Context, in *GetKeyPreferencesRequest, opts ...grpc.CallOption) (*KeyPreferences, error)
+	// SetKeyPreferences replaces the request defaults of the calling key
+	SetKeyPreferences(ctx context.Context, in *SetKeyPreferencesRequest, opts ...grpc.CallOption) (*KeyPreferences, error)
+	// CreateReportSchedule schedules a recurring report for the calling key,
+	// rendered and delivered by the report worker
+	CreateReportSchedule(ctx context.Context, in *CreateReportScheduleRequest, opts ...grpc.CallOption) (*ReportSchedule, error)
+	// ListReportSchedules returns the report schedules of the calling key
+	ListReportSchedules(ctx context.Context, in *ListReportSchedulesRequest, opts ...grpc.CallOption) (*ListReportSchedulesResponse, error)
+	// DeleteReportSchedule stops a report schedule of the calling key
+	DeleteReportSchedule(ctx context.Context, in *DeleteReportScheduleRequest, opts ...grpc.CallOption) (*DeleteReportScheduleResponse, error)
+	// GetUsage returns the calling key's requests and returned records for a
+	// month, with its monthly quotas
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	// GetOrganization returns the organization of the calling key, with its
+	// member keys when the caller is an org admin
+	GetOrganization(ctx context.Context, in *GetOrganizationRequest, opts ...grpc.CallOption) (*Organization, error)
+	// CreateOrganizationKey issues a new API key in the caller's organization
+	// (org admins only)
+	CreateOrganizationKey(ctx context.Context, in *CreateOrganizationKeyRequest, opts ...grpc.CallOption) (*OrganizationKey, error)
+	// UpdateOrganizationKey changes the description, active flag and org admin
+	// flag of a key in the caller's organization (org admins only)
+	UpdateOrganizationKey(ctx context.Context, in *UpdateOrganizationKeyRequest, opts ...grpc.CallOption) (*OrganizationKey, error)
+	// SetOrganizationWatchlist replaces the watchlist shared by the caller's
+	// organization (org admins only)
+	SetOrganizationWatchlist(ctx context.Context, in *SetOrganizationWatchlistRequest, opts ...grpc.CallOption) (*Organization, error)
+	// ImportWatchlist adds the domains and patterns of a CSV file or STIX 2.1
+	// bundle to the caller's organization watchlist (org admins only)
+	ImportWatchlist(ctx context.Context, in *ImportWatchlistRequest, opts ...grpc.CallOption) (*ImportWatchlistResponse, error)
+	// ImportDomains adds domains to those tracked for the caller's
+	// organization, which the query worker resolves ahead of the zone-file
+	// sweep (org admins only)
+	ImportDomains(ctx context.Context, in *ImportDomainsRequest, opts ...grpc.CallOption) (*ImportDomainsResponse, error)
+	// GetOrganizationUsage returns daily request counts per key of the
+	// caller's organization (org admins only)
+	GetOrganizationUsage(ctx context.Context, in *GetOrganizationUsageRequest, opts ...grpc.CallOption) (*GetOrganizationUsageResponse, error)
+	// CreateOrganization creates an organization around an existing key, which
+	// becomes its first org admin (admin only, gRPC only)
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*Organization, error)
+	// SetOrganizationQuota changes an organization's shared daily request
+	// quota (admin only, gRPC only)
+	SetOrganizationQuota(ctx context.Context, in *SetOrganizationQuotaRequest, opts ...grpc.CallOption) (*Organization, error)
+	// SetDomainRestriction restricts a domain and its records to the keys of
+	// some organizations, or makes it public again (admin only, gRPC only)
+	SetDomainRestriction(ctx context.Context, in *SetDomainRestrictionRequest, opts ...grpc.CallOption) (*DomainRestriction, error)
+	// ListNameserverReputation returns query worker reliability stats per
+	// nameserver host, least reliable first (admin only, gRPC only)
+	ListNameserverReputation(ctx context.Context, in *ListNameserverReputationRequest, opts ...grpc.CallOption) (*ListNameserverReputationResponse, error)
+	// TailEvents streams ingestion and worker progress events as they happen
+	// (admin only, gRPC only)
+	TailEvents(ctx context.Context, in *TailEventsRequest, opts ...grpc.CallOption) (DNSService_TailEventsClient, error)
+	// ListDNSServers returns the recursive upstreams of the server's resolver
+	// and their health (admin only)
+	ListDNSServers(ctx context.Context, in *ListDNSServersRequest, opts ...grpc.CallOption) (*ListDNSServersResponse, error)
+	// UpdateDNSServers adds and removes recursive upstreams of the server and
+	// the query workers at runtime (admin only)
+	UpdateDNSServers(ctx context.Context, in *UpdateDNSServersRequest, opts ...grpc.CallOption) (*ListDNSServersResponse, error)
+	// GetTLDCoverage compares IANA's list of TLDs with the TLDs whose zones
+	// are ingested and reports the missing ones, their estimated sizes and
+	// where their domains could be obtained (admin only)
+	GetTLDCoverage(ctx context.Context, in *GetTLDCoverageRequest, opts ...grpc.CallOption) (*GetTLDCoverageResponse, error)
+	// SetLogLevel changes the server log level, or one module's, at runtime (admin only)
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
 }
 
 type dNSServiceClient struct {
@@ -51,100 +287,2754 @@ func (c *dNSServiceClient) Authenticate(ctx context.Context, in *AuthenticateReq
 	return out, nil
 }
 
+func (c *dNSServiceClient) GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSigningKeysResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetSigningKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dNSServiceClient) GetRecords(ctx context.Context, in *GetRecordsRequest, opts ...grpc.CallOption) (*GetRecordsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetRecordsResponse)
-	err := c.cc.Invoke(ctx, DNSService_GetRecords_FullMethodName, in, out, cOpts...)
+	out := new(GetRecordsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetRecordHistory(ctx context.Context, in *GetRecordHistoryRequest, opts ...grpc.CallOption) (*GetRecordHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecordHistoryResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetRecordHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetRecordsBatch(ctx context.Context, in *GetRecordsBatchRequest, opts ...grpc.CallOption) (*GetRecordsBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecordsBatchResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetRecordsBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) StreamRecords(ctx context.Context, in *StreamRecordsRequest, opts ...grpc.CallOption) (DNSService_StreamRecordsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DNSService_ServiceDesc.Streams[0], DNSService_StreamRecords_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dNSServiceStreamRecordsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DNSService_StreamRecordsClient interface {
+	Recv() (*StreamedRecord, error)
+	grpc.ClientStream
+}
+
+type dNSServiceStreamRecordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *dNSServiceStreamRecordsClient) Recv() (*StreamedRecord, error) {
+	m := new(StreamedRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dNSServiceClient) StreamRecordBatches(ctx context.Context, in *StreamRecordBatchesRequest, opts ...grpc.CallOption) (DNSService_StreamRecordBatchesClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DNSService_ServiceDesc.Streams[1], DNSService_StreamRecordBatches_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dNSServiceStreamRecordBatchesClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DNSService_StreamRecordBatchesClient interface {
+	Recv() (*ArrowMessage, error)
+	grpc.ClientStream
+}
+
+type dNSServiceStreamRecordBatchesClient struct {
+	grpc.ClientStream
+}
+
+func (x *dNSServiceStreamRecordBatchesClient) Recv() (*ArrowMessage, error) {
+	m := new(ArrowMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dNSServiceClient) SubscribeRecordChanges(ctx context.Context, in *SubscribeRecordChangesRequest, opts ...grpc.CallOption) (DNSService_SubscribeRecordChangesClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DNSService_ServiceDesc.Streams[2], DNSService_SubscribeRecordChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dNSServiceSubscribeRecordChangesClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DNSService_SubscribeRecordChangesClient interface {
+	Recv() (*RecordChange, error)
+	grpc.ClientStream
+}
+
+type dNSServiceSubscribeRecordChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *dNSServiceSubscribeRecordChangesClient) Recv() (*RecordChange, error) {
+	m := new(RecordChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dNSServiceClient) ListTLDs(ctx context.Context, in *ListTLDsRequest, opts ...grpc.CallOption) (*ListTLDsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTLDsResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListTLDs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetTLDStatus(ctx context.Context, in *GetTLDStatusRequest, opts ...grpc.CallOption) (*GetTLDStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTLDStatusResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetTLDStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListDomainsByTLD(ctx context.Context, in *ListDomainsByTLDRequest, opts ...grpc.CallOption) (*ListDomainsByTLDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDomainsByTLDResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListDomainsByTLD_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetTopN(ctx context.Context, in *GetTopNRequest, opts ...grpc.CallOption) (*GetTopNResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopNResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetTopN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetKeywordTrends(ctx context.Context, in *GetKeywordTrendsRequest, opts ...grpc.CallOption) (*GetKeywordTrendsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetKeywordTrendsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetKeywordTrends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetDNSSECAdoption(ctx context.Context, in *GetDNSSECAdoptionRequest, opts ...grpc.CallOption) (*GetDNSSECAdoptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDNSSECAdoptionResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetDNSSECAdoption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListDomainClusters(ctx context.Context, in *ListDomainClustersRequest, opts ...grpc.CallOption) (*ListDomainClustersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDomainClustersResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListDomainClusters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetDomainCluster(ctx context.Context, in *GetDomainClusterRequest, opts ...grpc.CallOption) (*DomainCluster, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DomainCluster)
+	err := c.cc.Invoke(ctx, DNSService_GetDomainCluster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetRecordActivity(ctx context.Context, in *GetRecordActivityRequest, opts ...grpc.CallOption) (*GetRecordActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecordActivityResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetRecordActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetSubdomainActivity(ctx context.Context, in *GetSubdomainActivityRequest, opts ...grpc.CallOption) (*GetSubdomainActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubdomainActivityResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetSubdomainActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetTTLStats(ctx context.Context, in *GetTTLStatsRequest, opts ...grpc.CallOption) (*GetTTLStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTTLStatsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetTTLStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CheckDomains(ctx context.Context, in *CheckDomainsRequest, opts ...grpc.CallOption) (*CheckDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckDomainsResponse)
+	err := c.cc.Invoke(ctx, DNSService_CheckDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CountDomains(ctx context.Context, in *CountDomainsRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, DNSService_CountDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CountRecords(ctx context.Context, in *CountRecordsRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, DNSService_CountRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) StartExport(ctx context.Context, in *StartExportRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, DNSService_StartExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetExport(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, DNSService_GetExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListExports(ctx context.Context, in *ListExportsRequest, opts ...grpc.CallOption) (*ListExportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExportsResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListExports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetAbuseContacts(ctx context.Context, in *GetAbuseContactsRequest, opts ...grpc.CallOption) (*GetAbuseContactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAbuseContactsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetAbuseContacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetDomainLifecycle(ctx context.Context, in *GetDomainLifecycleRequest, opts ...grpc.CallOption) (*GetDomainLifecycleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDomainLifecycleResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetDomainLifecycle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyDomainResponse)
+	err := c.cc.Invoke(ctx, DNSService_VerifyDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) LookupLive(ctx context.Context, in *LookupLiveRequest, opts ...grpc.CallOption) (*LookupLiveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupLiveResponse)
+	err := c.cc.Invoke(ctx, DNSService_LookupLive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) RefreshDomain(ctx context.Context, in *RefreshDomainRequest, opts ...grpc.CallOption) (*RefreshDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshDomainResponse)
+	err := c.cc.Invoke(ctx, DNSService_RefreshDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) TraceResolution(ctx context.Context, in *TraceResolutionRequest, opts ...grpc.CallOption) (*TraceResolutionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TraceResolutionResponse)
+	err := c.cc.Invoke(ctx, DNSService_TraceResolution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetServiceRecords(ctx context.Context, in *GetServiceRecordsRequest, opts ...grpc.CallOption) (*GetServiceRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceRecordsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetServiceRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ValidateDANE(ctx context.Context, in *ValidateDANERequest, opts ...grpc.CallOption) (*ValidateDANEResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateDANEResponse)
+	err := c.cc.Invoke(ctx, DNSService_ValidateDANE_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetPTR(ctx context.Context, in *GetPTRRequest, opts ...grpc.CallOption) (*GetPTRResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPTRResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetPTR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetPTRRange(ctx context.Context, in *GetPTRRangeRequest, opts ...grpc.CallOption) (*GetPTRRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPTRRangeResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetPTRRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetDomainsByNameserver(ctx context.Context, in *GetDomainsByNameserverRequest, opts ...grpc.CallOption) (*GetDomainsByNameserverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDomainsByNameserverResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetDomainsByNameserver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListSubdomains(ctx context.Context, in *ListSubdomainsRequest, opts ...grpc.CallOption) (*ListSubdomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubdomainsResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListSubdomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SearchDomains(ctx context.Context, in *SearchDomainsRequest, opts ...grpc.CallOption) (*SearchDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchDomainsResponse)
+	err := c.cc.Invoke(ctx, DNSService_SearchDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SearchRecords(ctx context.Context, in *SearchRecordsRequest, opts ...grpc.CallOption) (*SearchRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchRecordsResponse)
+	err := c.cc.Invoke(ctx, DNSService_SearchRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetKeyPreferences(ctx context.Context, in *GetKeyPreferencesRequest, opts ...grpc.CallOption) (*KeyPreferences, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyPreferences)
+	err := c.cc.Invoke(ctx, DNSService_GetKeyPreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SetKeyPreferences(ctx context.Context, in *SetKeyPreferencesRequest, opts ...grpc.CallOption) (*KeyPreferences, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyPreferences)
+	err := c.cc.Invoke(ctx, DNSService_SetKeyPreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CreateReportSchedule(ctx context.Context, in *CreateReportScheduleRequest, opts ...grpc.CallOption) (*ReportSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportSchedule)
+	err := c.cc.Invoke(ctx, DNSService_CreateReportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListReportSchedules(ctx context.Context, in *ListReportSchedulesRequest, opts ...grpc.CallOption) (*ListReportSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReportSchedulesResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListReportSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) DeleteReportSchedule(ctx context.Context, in *DeleteReportScheduleRequest, opts ...grpc.CallOption) (*DeleteReportScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteReportScheduleResponse)
+	err := c.cc.Invoke(ctx, DNSService_DeleteReportSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetOrganization(ctx context.Context, in *GetOrganizationRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, DNSService_GetOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CreateOrganizationKey(ctx context.Context, in *CreateOrganizationKeyRequest, opts ...grpc.CallOption) (*OrganizationKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationKey)
+	err := c.cc.Invoke(ctx, DNSService_CreateOrganizationKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) UpdateOrganizationKey(ctx context.Context, in *UpdateOrganizationKeyRequest, opts ...grpc.CallOption) (*OrganizationKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationKey)
+	err := c.cc.Invoke(ctx, DNSService_UpdateOrganizationKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SetOrganizationWatchlist(ctx context.Context, in *SetOrganizationWatchlistRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, DNSService_SetOrganizationWatchlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ImportWatchlist(ctx context.Context, in *ImportWatchlistRequest, opts ...grpc.CallOption) (*ImportWatchlistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportWatchlistResponse)
+	err := c.cc.Invoke(ctx, DNSService_ImportWatchlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ImportDomains(ctx context.Context, in *ImportDomainsRequest, opts ...grpc.CallOption) (*ImportDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportDomainsResponse)
+	err := c.cc.Invoke(ctx, DNSService_ImportDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetOrganizationUsage(ctx context.Context, in *GetOrganizationUsageRequest, opts ...grpc.CallOption) (*GetOrganizationUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrganizationUsageResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetOrganizationUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, DNSService_CreateOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SetOrganizationQuota(ctx context.Context, in *SetOrganizationQuotaRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, DNSService_SetOrganizationQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SetDomainRestriction(ctx context.Context, in *SetDomainRestrictionRequest, opts ...grpc.CallOption) (*DomainRestriction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DomainRestriction)
+	err := c.cc.Invoke(ctx, DNSService_SetDomainRestriction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListNameserverReputation(ctx context.Context, in *ListNameserverReputationRequest, opts ...grpc.CallOption) (*ListNameserverReputationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNameserverReputationResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListNameserverReputation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) TailEvents(ctx context.Context, in *TailEventsRequest, opts ...grpc.CallOption) (DNSService_TailEventsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DNSService_ServiceDesc.Streams[3], DNSService_TailEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dNSServiceTailEventsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DNSService_TailEventsClient interface {
+	Recv() (*IngestEvent, error)
+	grpc.ClientStream
+}
+
+type dNSServiceTailEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *dNSServiceTailEventsClient) Recv() (*IngestEvent, error) {
+	m := new(IngestEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dNSServiceClient) ListDNSServers(ctx context.Context, in *ListDNSServersRequest, opts ...grpc.CallOption) (*ListDNSServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDNSServersResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListDNSServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) UpdateDNSServers(ctx context.Context, in *UpdateDNSServersRequest, opts ...grpc.CallOption) (*ListDNSServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDNSServersResponse)
+	err := c.cc.Invoke(ctx, DNSService_UpdateDNSServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) GetTLDCoverage(ctx context.Context, in *GetTLDCoverageRequest, opts ...grpc.CallOption) (*GetTLDCoverageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTLDCoverageResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetTLDCoverage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, DNSService_SetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSServiceServer is the server API for DNSService service.
+// All implementations must embed UnimplementedDNSServiceServer
+// for forward compatibility
+type DNSServiceServer interface {
+	// Authenticate validates an API key
+	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
+	// GetSigningKeys returns the public keys that signed responses (asked for
+	// with an X-Bell-Sign: true header) and export files verify against
+	GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error)
+	// GetRecords retrieves DNS records for a domain, filterable by record type
+	GetRecords(context.Context, *GetRecordsRequest) (*GetRecordsResponse, error)
+	// GetRecordHistory returns the timeline of the values a record type of a
+	// domain has had, with when each was first and last seen, for
+	// passive-DNS-style investigations
+	GetRecordHistory(context.Context, *GetRecordHistoryRequest) (*GetRecordHistoryResponse, error)
+	// GetRecordsBatch retrieves the DNS records of many domains at once, with
+	// one query per shard instead of a call per domain
+	GetRecordsBatch(context.Context, *GetRecordsBatchRequest) (*GetRecordsBatchResponse, error)
+	// StreamRecords streams the records of a domain or of a whole TLD as they
+	// are read from the database, for result sets too large to buffer
+	// (gRPC only). The x-data-freshness trailer gives the age of the oldest
+	// record streamed, overall and per source, as GetRecords' header does
+	StreamRecords(*StreamRecordsRequest, DNSService_StreamRecordsServer) error
+	// StreamRecordBatches streams the records of a domain or of a whole TLD as
+	// Arrow IPC columnar batches, for bulk analytical reads (gRPC only)
+	StreamRecordBatches(*StreamRecordBatchesRequest, DNSService_StreamRecordBatchesServer) error
+	// SubscribeRecordChanges streams changes to the record sets of some
+	// domains, or of every domain of a TLD, as the query worker observes them
+	// (gRPC only)
+	SubscribeRecordChanges(*SubscribeRecordChangesRequest, DNSService_SubscribeRecordChangesServer) error
+	// ListTLDs returns the ingestion status of every loaded TLD
+	ListTLDs(context.Context, *ListTLDsRequest) (*ListTLDsResponse, error)
+	// GetTLDStatus returns the ingestion status of a single TLD
+	GetTLDStatus(context.Context, *GetTLDStatusRequest) (*GetTLDStatusResponse, error)
+	// ListDomainsByTLD enumerates the domains of a TLD or public suffix, in
+	// name order, a page at a time
+	ListDomainsByTLD(context.Context, *ListDomainsByTLDRequest) (*ListDomainsByTLDResponse, error)
+	// GetTopN returns precomputed rankings (e.g. top nameservers) per TLD or globally
+	GetTopN(context.Context, *GetTopNRequest) (*GetTopNResponse, error)
+	// GetKeywordTrends returns the keywords trending in newly seen domain
+	// labels on a day, per TLD or globally
+	GetKeywordTrends(context.Context, *GetKeywordTrendsRequest) (*GetKeywordTrendsResponse, error)
+	// GetDNSSECAdoption returns the daily share of signed delegations and the
+	// DNSSEC algorithms and key sizes in use, per TLD or globally
+	GetDNSSECAdoption(context.Context, *GetDNSSECAdoptionRequest) (*GetDNSSECAdoptionResponse, error)
+	// ListDomainClusters returns the clusters of domains sharing nameservers,
+	// A records and a registration window, most recently active first
+	ListDomainClusters(context.Context, *ListDomainClustersRequest) (*ListDomainClustersResponse, error)
+	// GetDomainCluster returns a cluster and its domains, by cluster ID or by
+	// one of its domains
+	GetDomainCluster(context.Context, *GetDomainClusterRequest) (*DomainCluster, error)
+	// GetRecordActivity returns a domain's records observed, first seen and
+	// changed per day, from the rollups of the activity-rollups job
+	GetRecordActivity(context.Context, *GetRecordActivityRequest) (*GetRecordActivityResponse, error)
+	// GetSubdomainActivity returns the subdomains first seen under an apex per
+	// week, from the rollups of the activity-rollups job
+	GetSubdomainActivity(context.Context, *GetSubdomainActivityRequest) (*GetSubdomainActivityResponse, error)
+	// GetTTLStats returns the TTL distribution and recent TTL anomalies for a domain or TLD
+	GetTTLStats(context.Context, *GetTTLStatsRequest) (*GetTTLStatsResponse, error)
+	// CheckDomains reports which of the given domains exist in the database
+	CheckDomains(context.Context, *CheckDomainsRequest) (*CheckDomainsResponse, error)
+	// CountDomains counts the domains matching a filter, estimating counts too
+	// large to compute quickly
+	CountDomains(context.Context, *CountDomainsRequest) (*CountResponse, error)
+	// CountRecords counts the DNS records matching a filter, estimating counts
+	// too large to compute quickly
+	CountRecords(context.Context, *CountRecordsRequest) (*CountResponse, error)
+	// StartExport queues an export of the domains or DNS records matching a
+	// filter, written to object storage by the export worker, for result sets
+	// too large to page through
+	StartExport(context.Context, *StartExportRequest) (*ExportJob, error)
+	// GetExport returns an export of the calling key, with a signed, expiring
+	// download URL once it has succeeded
+	GetExport(context.Context, *GetExportRequest) (*ExportJob, error)
+	// ListExports returns the exports of the calling key, newest first
+	ListExports(context.Context, *ListExportsRequest) (*ListExportsResponse, error)
+	// GetAbuseContacts returns the registrar and abuse contacts for a domain from RDAP
+	GetAbuseContacts(context.Context, *GetAbuseContactsRequest) (*GetAbuseContactsResponse, error)
+	// GetDomainLifecycle returns when a domain was first seen, its nameserver
+	// and record-set changes, classified by significance, and the registrar
+	// transfers detected for it
+	GetDomainLifecycle(context.Context, *GetDomainLifecycleRequest) (*GetDomainLifecycleResponse, error)
+	// VerifyDomain compares a domain's record sets across the zone, the
+	// database and live DNS, reporting drift between them
+	VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error)
+	// LookupLive resolves any domain live through the server's resolver and
+	// returns the answers with their timing; nothing is stored and the domain
+	// need not be in the corpus
+	LookupLive(context.Context, *LookupLiveRequest) (*LookupLiveResponse, error)
+	// RefreshDomain resolves a domain in the corpus at its authoritative
+	// nameservers, optionally on behalf of a client subnet, and stores the
+	// answers with their EDNS Client Subnet scope for GetRecords
+	RefreshDomain(context.Context, *RefreshDomainRequest) (*RefreshDomainResponse, error)
+	// TraceResolution resolves a domain iteratively from the root, like
+	// dig +trace, and returns every referral on the way with its glue, DS
+	// records and timing, compared against the stored record set
+	TraceResolution(context.Context, *TraceResolutionRequest) (*TraceResolutionResponse, error)
+	// GetServiceRecords returns the parsed SRV and NAPTR records of a service
+	// name such as _sip._tls.example.com, or of a domain for NAPTR
+	GetServiceRecords(context.Context, *GetServiceRecordsRequest) (*GetServiceRecordsResponse, error)
+	// ValidateDANE fetches the live TLS certificate chain of a service and
+	// checks it against the stored TLSA records of its _port._tcp name
+	ValidateDANE(context.Context, *ValidateDANERequest) (*ValidateDANEResponse, error)
+	// GetPTR returns the PTR records of an IPv4 or IPv6 address from ingested
+	// reverse zones, or resolved live
+	GetPTR(context.Context, *GetPTRRequest) (*GetPTRResponse, error)
+	// GetPTRRange returns the PTR records of every address in a CIDR block,
+	// in address order
+	GetPTRRange(context.Context, *GetPTRRangeRequest) (*GetPTRRangeResponse, error)
+	// GetDomainsByNameserver returns the domains delegated to a nameserver
+	// host, in name order, a page at a time
+	GetDomainsByNameserver(context.Context, *GetDomainsByNameserverRequest) (*GetDomainsByNameserverResponse, error)
+	// ListSubdomains returns the domains under an apex, grouped by parent
+	// name, a page at a time
+	ListSubdomains(context.Context, *ListSubdomainsRequest) (*ListSubdomainsResponse, error)
+	// SearchDomains returns the domains whose names match a wildcard pattern
+	// such as *bank* or paypal*.com, in name order, a page at a time
+	SearchDomains(context.Context, *SearchDomainsRequest) (*SearchDomainsResponse, error)
+	// SearchRecords returns records whose data matches a POSIX regular
+	// expression, such as a domain verification token in TXT records. The
+	// scan is bounded by the server's timeout and result cap.
+	SearchRecords(context.Context, *SearchRecordsRequest) (*SearchRecordsResponse, error)
+	// GetKeyPreferences returns the request defaults stored for the calling key
+	GetKeyPreferences(context.Context, *GetKeyPreferencesRequest) (*KeyPreferences, error)
+	// SetKeyPreferences replaces the request defaults of the calling key
+	SetKeyPreferences(context.Context, *SetKeyPreferencesRequest) (*KeyPreferences, error)
+	// CreateReportSchedule schedules a recurring report for the calling key,
+	// rendered and delivered by the report worker
+	CreateReportSchedule(context.Context, *CreateReportScheduleRequest) (*ReportSchedule, error)
+	// ListReportSchedules returns the report schedules of the calling key
+	ListReportSchedules(context.Context, *ListReportSchedulesRequest) (*ListReportSchedulesResponse, error)
+	// DeleteReportSchedule stops a report schedule of the calling key
+	DeleteReportSchedule(context.Context, *DeleteReportScheduleRequest) (*DeleteReportScheduleResponse, error)
+	// GetUsage returns the calling key's requests and returned records for a
+	// month, with its monthly quotas
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	// GetOrganization returns the organization of the calling key, with its
+	// member keys when the caller is an org admin
+	GetOrganization(context.Context, *GetOrganizationRequest) (*Organization, error)
+	// CreateOrganizationKey issues a new API key in the caller's organization
+	// (org admins only)
+	CreateOrganizationKey(context.Context, *CreateOrganizationKeyRequest) (*OrganizationKey, error)
+	// UpdateOrganizationKey changes the description, active flag and org admin
+	// flag of a key in the caller's organization (org admins only)
+	UpdateOrganizationKey(context.Context, *UpdateOrganizationKeyRequest) (*OrganizationKey, error)
+	// SetOrganizationWatchlist replaces the watchlist shared by the caller's
+	// organization (org admins only)
+	SetOrganizationWatchlist(context.Context, *SetOrganizationWatchlistRequest) (*Organization, error)
+	// ImportWatchlist adds the domains and patterns of a CSV file or STIX 2.1
+	// bundle to the caller's organization watchlist (org admins only)
+	ImportWatchlist(context.Context, *ImportWatchlistRequest) (*ImportWatchlistResponse, error)
+	// ImportDomains adds domains to those tracked for the caller's
+	// organization, which the query worker resolves ahead of the zone-file
+	// sweep (org admins only)
+	ImportDomains(context.Context, *ImportDomainsRequest) (*ImportDomainsResponse, error)
+	// GetOrganizationUsage returns daily request counts per key of the
+	// caller's organization (org admins only)
+	GetOrganizationUsage(context.Context, *GetOrganizationUsageRequest) (*GetOrganizationUsageResponse, error)
+	// CreateOrganization creates an organization around an existing key, which
+	// becomes its first org admin (admin only, gRPC only)
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*Organization, error)
+	// SetOrganizationQuota changes an organization's shared daily request
+	// quota (admin only, gRPC only)
+	SetOrganizationQuota(context.Context, *SetOrganizationQuotaRequest) (*Organization, error)
+	// SetDomainRestriction restricts a domain and its records to the keys of
+	// some organizations, or makes it public again (admin only, gRPC only)
+	SetDomainRestriction(context.Context, *SetDomainRestrictionRequest) (*DomainRestriction, error)
+	// ListNameserverReputation returns query worker reliability stats per
+	// nameserver host, least reliable first (admin only, gRPC only)
+	ListNameserverReputation(context.Context, *ListNameserverReputationRequest) (*ListNameserverReputationResponse, error)
+	// TailEvents streams ingestion and worker progress events as they happen
+	// (admin only, gRPC only)
+	TailEvents(*TailEventsRequest, DNSService_TailEventsServer) error
+	// ListDNSServers returns the recursive upstreams of the server's resolver
+	// and their health (admin only)
+	ListDNSServers(context.Context, *ListDNSServersRequest) (*ListDNSServersResponse, error)
+	// UpdateDNSServers adds and removes recursive upstreams of the server and
+	// the query workers at runtime (admin only)
+	UpdateDNSServers(context.Context, *UpdateDNSServersRequest) (*ListDNSServersResponse, error)
+	// GetTLDCoverage compares IANA's list of TLDs with the TLDs whose zones
+	// are ingested and reports the missing ones, their estimated sizes and
+	// where their domains could be obtained (admin only)
+	GetTLDCoverage(context.Context, *GetTLDCoverageRequest) (*GetTLDCoverageResponse, error)
+	// SetLogLevel changes the server log level, or one module's, at runtime (admin only)
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+// UnimplementedDNSServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDNSServiceServer struct {
+}
+
+func (UnimplementedDNSServiceServer) Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
+func (UnimplementedDNSServiceServer) GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSigningKeys not implemented")
+}
+func (UnimplementedDNSServiceServer) GetRecords(context.Context, *GetRecordsRequest) (*GetRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecords not implemented")
+}
+func (UnimplementedDNSServiceServer) GetRecordHistory(context.Context, *GetRecordHistoryRequest) (*GetRecordHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordHistory not implemented")
+}
+func (UnimplementedDNSServiceServer) GetRecordsBatch(context.Context, *GetRecordsBatchRequest) (*GetRecordsBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordsBatch not implemented")
+}
+func (UnimplementedDNSServiceServer) StreamRecords(*StreamRecordsRequest, DNSService_StreamRecordsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRecords not implemented")
+}
+func (UnimplementedDNSServiceServer) StreamRecordBatches(*StreamRecordBatchesRequest, DNSService_StreamRecordBatchesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRecordBatches not implemented")
+}
+func (UnimplementedDNSServiceServer) SubscribeRecordChanges(*SubscribeRecordChangesRequest, DNSService_SubscribeRecordChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeRecordChanges not implemented")
+}
+func (UnimplementedDNSServiceServer) ListTLDs(context.Context, *ListTLDsRequest) (*ListTLDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTLDs not implemented")
+}
+func (UnimplementedDNSServiceServer) GetTLDStatus(context.Context, *GetTLDStatusRequest) (*GetTLDStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTLDStatus not implemented")
+}
+func (UnimplementedDNSServiceServer) ListDomainsByTLD(context.Context, *ListDomainsByTLDRequest) (*ListDomainsByTLDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomainsByTLD not implemented")
+}
+func (UnimplementedDNSServiceServer) GetTopN(context.Context, *GetTopNRequest) (*GetTopNResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopN not implemented")
+}
+func (UnimplementedDNSServiceServer) GetKeywordTrends(context.Context, *GetKeywordTrendsRequest) (*GetKeywordTrendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeywordTrends not implemented")
+}
+func (UnimplementedDNSServiceServer) GetDNSSECAdoption(context.Context, *GetDNSSECAdoptionRequest) (*GetDNSSECAdoptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDNSSECAdoption not implemented")
+}
+func (UnimplementedDNSServiceServer) ListDomainClusters(context.Context, *ListDomainClustersRequest) (*ListDomainClustersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomainClusters not implemented")
+}
+func (UnimplementedDNSServiceServer) GetDomainCluster(context.Context, *GetDomainClusterRequest) (*DomainCluster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainCluster not implemented")
+}
+func (UnimplementedDNSServiceServer) GetRecordActivity(context.Context, *GetRecordActivityRequest) (*GetRecordActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordActivity not implemented")
+}
+func (UnimplementedDNSServiceServer) GetSubdomainActivity(context.Context, *GetSubdomainActivityRequest) (*GetSubdomainActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubdomainActivity not implemented")
+}
+func (UnimplementedDNSServiceServer) GetTTLStats(context.Context, *GetTTLStatsRequest) (*GetTTLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTTLStats not implemented")
+}
+func (UnimplementedDNSServiceServer) CheckDomains(context.Context, *CheckDomainsRequest) (*CheckDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDomains not implemented")
+}
+func (UnimplementedDNSServiceServer) CountDomains(context.Context, *CountDomainsRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountDomains not implemented")
+}
+func (UnimplementedDNSServiceServer) CountRecords(context.Context, *CountRecordsRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountRecords not implemented")
+}
+func (UnimplementedDNSServiceServer) StartExport(context.Context, *StartExportRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartExport not implemented")
+}
+func (UnimplementedDNSServiceServer) GetExport(context.Context, *GetExportRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExport not implemented")
+}
+func (UnimplementedDNSServiceServer) ListExports(context.Context, *ListExportsRequest) (*ListExportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExports not implemented")
+}
+func (UnimplementedDNSServiceServer) GetAbuseContacts(context.Context, *GetAbuseContactsRequest) (*GetAbuseContactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAbuseContacts not implemented")
+}
+func (UnimplementedDNSServiceServer) GetDomainLifecycle(context.Context, *GetDomainLifecycleRequest) (*GetDomainLifecycleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainLifecycle not implemented")
+}
+func (UnimplementedDNSServiceServer) VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyDomain not implemented")
+}
+func (UnimplementedDNSServiceServer) LookupLive(context.Context, *LookupLiveRequest) (*LookupLiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupLive not implemented")
+}
+func (UnimplementedDNSServiceServer) RefreshDomain(context.Context, *RefreshDomainRequest) (*RefreshDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshDomain not implemented")
+}
+func (UnimplementedDNSServiceServer) TraceResolution(context.Context, *TraceResolutionRequest) (*TraceResolutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceResolution not implemented")
+}
+func (UnimplementedDNSServiceServer) GetServiceRecords(context.Context, *GetServiceRecordsRequest) (*GetServiceRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceRecords not implemented")
+}
+func (UnimplementedDNSServiceServer) ValidateDANE(context.Context, *ValidateDANERequest) (*ValidateDANEResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateDANE not implemented")
+}
+func (UnimplementedDNSServiceServer) GetPTR(context.Context, *GetPTRRequest) (*GetPTRResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPTR not implemented")
+}
+func (UnimplementedDNSServiceServer) GetPTRRange(context.Context, *GetPTRRangeRequest) (*GetPTRRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPTRRange not implemented")
+}
+func (UnimplementedDNSServiceServer) GetDomainsByNameserver(context.Context, *GetDomainsByNameserverRequest) (*GetDomainsByNameserverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainsByNameserver not implemented")
+}
+func (UnimplementedDNSServiceServer) ListSubdomains(context.Context, *ListSubdomainsRequest) (*ListSubdomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubdomains not implemented")
+}
+func (UnimplementedDNSServiceServer) SearchDomains(context.Context, *SearchDomainsRequest) (*SearchDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchDomains not implemented")
+}
+func (UnimplementedDNSServiceServer) SearchRecords(context.Context, *SearchRecordsRequest) (*SearchRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRecords not implemented")
+}
+func (UnimplementedDNSServiceServer) GetKeyPreferences(context.Context, *GetKeyPreferencesRequest) (*KeyPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyPreferences not implemented")
+}
+func (UnimplementedDNSServiceServer) SetKeyPreferences(context.Context, *SetKeyPreferencesRequest) (*KeyPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyPreferences not implemented")
+}
+func (UnimplementedDNSServiceServer) CreateReportSchedule(context.Context, *CreateReportScheduleRequest) (*ReportSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReportSchedule not implemented")
+}
+func (UnimplementedDNSServiceServer) ListReportSchedules(context.Context, *ListReportSchedulesRequest) (*ListReportSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReportSchedules not implemented")
+}
+func (UnimplementedDNSServiceServer) DeleteReportSchedule(context.Context, *DeleteReportScheduleRequest) (*DeleteReportScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReportSchedule not implemented")
+}
+func (UnimplementedDNSServiceServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedDNSServiceServer) GetOrganization(context.Context, *GetOrganizationRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrganization not implemented")
+}
+func (UnimplementedDNSServiceServer) CreateOrganizationKey(context.Context, *CreateOrganizationKeyRequest) (*OrganizationKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganizationKey not implemented")
+}
+func (UnimplementedDNSServiceServer) UpdateOrganizationKey(context.Context, *UpdateOrganizationKeyRequest) (*OrganizationKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrganizationKey not implemented")
+}
+func (UnimplementedDNSServiceServer) SetOrganizationWatchlist(context.Context, *SetOrganizationWatchlistRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOrganizationWatchlist not implemented")
+}
+func (UnimplementedDNSServiceServer) ImportWatchlist(context.Context, *ImportWatchlistRequest) (*ImportWatchlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportWatchlist not implemented")
+}
+func (UnimplementedDNSServiceServer) ImportDomains(context.Context, *ImportDomainsRequest) (*ImportDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportDomains not implemented")
+}
+func (UnimplementedDNSServiceServer) GetOrganizationUsage(context.Context, *GetOrganizationUsageRequest) (*GetOrganizationUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrganizationUsage not implemented")
+}
+func (UnimplementedDNSServiceServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedDNSServiceServer) SetOrganizationQuota(context.Context, *SetOrganizationQuotaRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOrganizationQuota not implemented")
+}
+func (UnimplementedDNSServiceServer) SetDomainRestriction(context.Context, *SetDomainRestrictionRequest) (*DomainRestriction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDomainRestriction not implemented")
+}
+func (UnimplementedDNSServiceServer) ListNameserverReputation(context.Context, *ListNameserverReputationRequest) (*ListNameserverReputationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNameserverReputation not implemented")
+}
+func (UnimplementedDNSServiceServer) TailEvents(*TailEventsRequest, DNSService_TailEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TailEvents not implemented")
+}
+func (UnimplementedDNSServiceServer) ListDNSServers(context.Context, *ListDNSServersRequest) (*ListDNSServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDNSServers not implemented")
+}
+func (UnimplementedDNSServiceServer) UpdateDNSServers(context.Context, *UpdateDNSServersRequest) (*ListDNSServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDNSServers not implemented")
+}
+func (UnimplementedDNSServiceServer) GetTLDCoverage(context.Context, *GetTLDCoverageRequest) (*GetTLDCoverageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTLDCoverage not implemented")
+}
+func (UnimplementedDNSServiceServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedDNSServiceServer) mustEmbedUnimplementedDNSServiceServer() {}
+
+// UnsafeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DNSServiceServer will
+// result in compilation errors.
+type UnsafeDNSServiceServer interface {
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+func RegisterDNSServiceServer(s grpc.ServiceRegistrar, srv DNSServiceServer) {
+	s.RegisterService(&DNSService_ServiceDesc, srv)
+}
+
+func _DNSService_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).Authenticate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_Authenticate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).Authenticate(ctx, req.(*AuthenticateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetSigningKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSigningKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetSigningKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetSigningKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetSigningKeys(ctx, req.(*GetSigningKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetRecords(ctx, req.(*GetRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetRecordHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetRecordHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetRecordHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetRecordHistory(ctx, req.(*GetRecordHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetRecordsBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordsBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetRecordsBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetRecordsBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetRecordsBatch(ctx, req.(*GetRecordsBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_StreamRecords_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRecordsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServiceServer).StreamRecords(m, &dNSServiceStreamRecordsServer{ServerStream: stream})
+}
+
+type DNSService_StreamRecordsServer interface {
+	Send(*StreamedRecord) error
+	grpc.ServerStream
+}
+
+type dNSServiceStreamRecordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *dNSServiceStreamRecordsServer) Send(m *StreamedRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DNSService_StreamRecordBatches_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRecordBatchesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServiceServer).StreamRecordBatches(m, &dNSServiceStreamRecordBatchesServer{ServerStream: stream})
+}
+
+type DNSService_StreamRecordBatchesServer interface {
+	Send(*ArrowMessage) error
+	grpc.ServerStream
+}
+
+type dNSServiceStreamRecordBatchesServer struct {
+	grpc.ServerStream
+}
+
+func (x *dNSServiceStreamRecordBatchesServer) Send(m *ArrowMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DNSService_SubscribeRecordChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRecordChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServiceServer).SubscribeRecordChanges(m, &dNSServiceSubscribeRecordChangesServer{ServerStream: stream})
+}
+
+type DNSService_SubscribeRecordChangesServer interface {
+	Send(*RecordChange) error
+	grpc.ServerStream
+}
+
+type dNSServiceSubscribeRecordChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *dNSServiceSubscribeRecordChangesServer) Send(m *RecordChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DNSService_ListTLDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTLDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListTLDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListTLDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListTLDs(ctx, req.(*ListTLDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetTLDStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTLDStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetTLDStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetTLDStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetTLDStatus(ctx, req.(*GetTLDStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListDomainsByTLD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainsByTLDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListDomainsByTLD(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListDomainsByTLD_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListDomainsByTLD(ctx, req.(*ListDomainsByTLDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetTopN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetTopN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetTopN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetTopN(ctx, req.(*GetTopNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetKeywordTrends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeywordTrendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetKeywordTrends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetKeywordTrends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetKeywordTrends(ctx, req.(*GetKeywordTrendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetDNSSECAdoption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSSECAdoptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetDNSSECAdoption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetDNSSECAdoption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetDNSSECAdoption(ctx, req.(*GetDNSSECAdoptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListDomainClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListDomainClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListDomainClusters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListDomainClusters(ctx, req.(*ListDomainClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetDomainCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetDomainCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetDomainCluster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetDomainCluster(ctx, req.(*GetDomainClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetRecordActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetRecordActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetRecordActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetRecordActivity(ctx, req.(*GetRecordActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetSubdomainActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubdomainActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetSubdomainActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetSubdomainActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetSubdomainActivity(ctx, req.(*GetSubdomainActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetTTLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTTLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetTTLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetTTLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetTTLStats(ctx, req.(*GetTTLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CheckDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CheckDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CheckDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CheckDomains(ctx, req.(*CheckDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CountDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CountDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CountDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CountDomains(ctx, req.(*CountDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CountRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CountRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CountRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CountRecords(ctx, req.(*CountRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_StartExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).StartExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_StartExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).StartExport(ctx, req.(*StartExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetExport(ctx, req.(*GetExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListExports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListExports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListExports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListExports(ctx, req.(*ListExportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetAbuseContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAbuseContactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetAbuseContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetAbuseContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetAbuseContacts(ctx, req.(*GetAbuseContactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetDomainLifecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainLifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetDomainLifecycle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetDomainLifecycle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetDomainLifecycle(ctx, req.(*GetDomainLifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_VerifyDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).VerifyDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_VerifyDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).VerifyDomain(ctx, req.(*VerifyDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_LookupLive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupLiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).LookupLive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_LookupLive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).LookupLive(ctx, req.(*LookupLiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_RefreshDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).RefreshDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_RefreshDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).RefreshDomain(ctx, req.(*RefreshDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_TraceResolution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceResolutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).TraceResolution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_TraceResolution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).TraceResolution(ctx, req.(*TraceResolutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetServiceRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetServiceRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetServiceRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetServiceRecords(ctx, req.(*GetServiceRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ValidateDANE_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateDANERequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ValidateDANE(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ValidateDANE_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ValidateDANE(ctx, req.(*ValidateDANERequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetPTR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPTRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetPTR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetPTR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetPTR(ctx, req.(*GetPTRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetPTRRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPTRRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetPTRRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetPTRRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetPTRRange(ctx, req.(*GetPTRRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetDomainsByNameserver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainsByNameserverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetDomainsByNameserver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetDomainsByNameserver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetDomainsByNameserver(ctx, req.(*GetDomainsByNameserverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListSubdomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubdomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListSubdomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListSubdomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListSubdomains(ctx, req.(*ListSubdomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SearchDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SearchDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SearchDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SearchDomains(ctx, req.(*SearchDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SearchRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SearchRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SearchRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SearchRecords(ctx, req.(*SearchRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetKeyPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetKeyPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetKeyPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetKeyPreferences(ctx, req.(*GetKeyPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SetKeyPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SetKeyPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SetKeyPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SetKeyPreferences(ctx, req.(*SetKeyPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CreateReportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CreateReportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CreateReportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CreateReportSchedule(ctx, req.(*CreateReportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListReportSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListReportSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListReportSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListReportSchedules(ctx, req.(*ListReportSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_DeleteReportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).DeleteReportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_DeleteReportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).DeleteReportSchedule(ctx, req.(*DeleteReportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetOrganization(ctx, req.(*GetOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CreateOrganizationKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CreateOrganizationKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CreateOrganizationKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CreateOrganizationKey(ctx, req.(*CreateOrganizationKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_UpdateOrganizationKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrganizationKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).UpdateOrganizationKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_UpdateOrganizationKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).UpdateOrganizationKey(ctx, req.(*UpdateOrganizationKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SetOrganizationWatchlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOrganizationWatchlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SetOrganizationWatchlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SetOrganizationWatchlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SetOrganizationWatchlist(ctx, req.(*SetOrganizationWatchlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ImportWatchlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportWatchlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ImportWatchlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ImportWatchlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ImportWatchlist(ctx, req.(*ImportWatchlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ImportDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ImportDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ImportDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ImportDomains(ctx, req.(*ImportDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetOrganizationUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrganizationUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetOrganizationUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetOrganizationUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetOrganizationUsage(ctx, req.(*GetOrganizationUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_CreateOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SetOrganizationQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOrganizationQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SetOrganizationQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SetOrganizationQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SetOrganizationQuota(ctx, req.(*SetOrganizationQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SetDomainRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDomainRestrictionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SetDomainRestriction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SetDomainRestriction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SetDomainRestriction(ctx, req.(*SetDomainRestrictionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListNameserverReputation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNameserverReputationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListNameserverReputation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListNameserverReputation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListNameserverReputation(ctx, req.(*ListNameserverReputationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_TailEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServiceServer).TailEvents(m, &dNSServiceTailEventsServer{ServerStream: stream})
+}
+
+type DNSService_TailEventsServer interface {
+	Send(*IngestEvent) error
+	grpc.ServerStream
+}
+
+type dNSServiceTailEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *dNSServiceTailEventsServer) Send(m *IngestEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DNSService_ListDNSServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDNSServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListDNSServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListDNSServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListDNSServers(ctx, req.(*ListDNSServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_UpdateDNSServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDNSServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).UpdateDNSServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_UpdateDNSServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).UpdateDNSServers(ctx, req.(*UpdateDNSServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_GetTLDCoverage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTLDCoverageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetTLDCoverage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetTLDCoverage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetTLDCoverage(ctx, req.(*GetTLDCoverageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_SetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DNSService_ServiceDesc is the grpc.ServiceDesc for DNSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DNSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bell.v1.DNSService",
+	HandlerType: (*DNSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Authenticate",
+			Handler:    _DNSService_Authenticate_Handler,
+		},
+		{
+			MethodName: "GetSigningKeys",
+			Handler:    _DNSService_GetSigningKeys_Handler,
+		},
+		{
+			MethodName: "GetRecords",
+			Handler:    _DNSService_GetRecords_Handler,
+		},
+		{
+			MethodName: "GetRecordHistory",
+			Handler:    _DNSService_GetRecordHistory_Handler,
+		},
+		{
+			MethodName: "GetRecordsBatch",
+			Handler:    _DNSService_GetRecordsBatch_Handler,
+		},
+		{
+			MethodName: "ListTLDs",
+			Handler:    _DNSService_ListTLDs_Handler,
+		},
+		{
+			MethodName: "GetTLDStatus",
+			Handler:    _DNSService_GetTLDStatus_Handler,
+		},
+		{
+			MethodName: "ListDomainsByTLD",
+			Handler:    _DNSService_ListDomainsByTLD_Handler,
+		},
+		{
+			MethodName: "GetTopN",
+			Handler:    _DNSService_GetTopN_Handler,
+		},
+		{
+			MethodName: "GetKeywordTrends",
+			Handler:    _DNSService_GetKeywordTrends_Handler,
+		},
+		{
+			MethodName: "GetDNSSECAdoption",
+			Handler:    _DNSService_GetDNSSECAdoption_Handler,
+		},
+		{
+			MethodName: "ListDomainClusters",
+			Handler:    _DNSService_ListDomainClusters_Handler,
+		},
+		{
+			MethodName: "GetDomainCluster",
+			Handler:    _DNSService_GetDomainCluster_Handler,
+		},
+		{
+			MethodName: "GetRecordActivity",
+			Handler:    _DNSService_GetRecordActivity_Handler,
+		},
+		{
+			MethodName: "GetSubdomainActivity",
+			Handler:    _DNSService_GetSubdomainActivity_Handler,
+		},
+		{
+			MethodName: "GetTTLStats",
+			Handler:    _DNSService_GetTTLStats_Handler,
+		},
+		{
+			MethodName: "CheckDomains",
+			Handler:    _DNSService_CheckDomains_Handler,
+		},
+		{
+			MethodName: "CountDomains",
+			Handler:    _DNSService_CountDomains_Handler,
+		},
+		{
+			MethodName: "CountRecords",
+			Handler:    _DNSService_CountRecords_Handler,
+		},
+		{
+			MethodName: "StartExport",
+			Handler:    _DNSService_StartExport_Handler,
+		},
+		{
+			MethodName: "GetExport",
+			Handler:    _DNSService_GetExport_Handler,
+		},
+		{
+			MethodName: "ListExports",
+			Handler:    _DNSService_ListExports_Handler,
+		},
+		{
+			MethodName: "GetAbuseContacts",
+			Handler:    _DNSService_GetAbuseContacts_Handler,
+		},
+		{
+			MethodName: "GetDomainLifecycle",
+			Handler:    _DNSService_GetDomainLifecycle_Handler,
+		},
+		{
+			MethodName: "VerifyDomain",
+			Handler:    _DNSService_VerifyDomain_Handler,
+		},
+		{
+			MethodName: "LookupLive",
+			Handler:    _DNSService_LookupLive_Handler,
+		},
+		{
+			MethodName: "RefreshDomain",
+			Handler:    _DNSService_RefreshDomain_Handler,
+		},
+		{
+			MethodName: "TraceResolution",
+			Handler:    _DNSService_TraceResolution_Handler,
+		},
+		{
+			MethodName: "GetServiceRecords",
+			Handler:    _DNSService_GetServiceRecords_Handler,
+		},
+		{
+			MethodName: "ValidateDANE",
+			Handler:    _DNSService_ValidateDANE_Handler,
+		},
+		{
+			MethodName: "GetPTR",
+			Handler:    _DNSService_GetPTR_Handler,
+		},
+		{
+			MethodName: "GetPTRRange",
+			Handler:    _DNSService_GetPTRRange_Handler,
+		},
+		{
+			MethodName: "GetDomainsByNameserver",
+			Handler:    _DNSService_GetDomainsByNameserver_Handler,
+		},
+		{
+			MethodName: "ListSubdomains",
+			Handler:    _DNSService_ListSubdomains_Handler,
+		},
+		{
+			MethodName: "SearchDomains",
+			Handler:    _DNSService_SearchDomains_Handler,
+		},
+		{
+			MethodName: "SearchRecords",
+			Handler:    _DNSService_SearchRecords_Handler,
+		},
+		{
+			MethodName: "GetKeyPreferences",
+			Handler:    _DNSService_GetKeyPreferences_Handler,
+		},
+		{
+			MethodName: "SetKeyPreferences",
+			Handler:    _DNSService_SetKeyPreferences_Handler,
+		},
+		{
+			MethodName: "CreateReportSchedule",
+			Handler:    _DNSService_CreateReportSchedule_Handler,
+		},
+		{
+			MethodName: "ListReportSchedules",
+			Handler:    _DNSService_ListReportSchedules_Handler,
+		},
+		{
+			MethodName: "DeleteReportSchedule",
+			Handler:    _DNSService_DeleteReportSchedule_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _DNSService_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetOrganization",
+			Handler:    _DNSService_GetOrganization_Handler,
+		},
+		{
+			MethodName: "CreateOrganizationKey",
+			Handler:    _DNSService_CreateOrganizationKey_Handler,
+		},
+		{
+			MethodName: "UpdateOrganizationKey",
+			Handler:    _DNSService_UpdateOrganizationKey_Handler,
+		},
+		{
+			MethodName: "SetOrganizationWatchlist",
+			Handler:    _DNSService_SetOrganizationWatchlist_Handler,
+		},
+		{
+			MethodName: "ImportWatchlist",
+			Handler:    _DNSService_ImportWatchlist_Handler,
+		},
+		{
+			MethodName: "ImportDomains",
+			Handler:    _DNSService_ImportDomains_Handler,
+		},
+		{
+			MethodName: "GetOrganizationUsage",
+			Handler:    _DNSService_GetOrganizationUsage_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _DNSService_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "SetOrganizationQuota",
+			Handler:    _DNSService_SetOrganizationQuota_Handler,
+		},
+		{
+			MethodName: "SetDomainRestriction",
+			Handler:    _DNSService_SetDomainRestriction_Handler,
+		},
+		{
+			MethodName: "ListNameserverReputation",
+			Handler:    _DNSService_ListNameserverReputation_Handler,
+		},
+		{
+			MethodName: "ListDNSServers",
+			Handler:    _DNSService_ListDNSServers_Handler,
+		},
+		{
+			MethodName: "UpdateDNSServers",
+			Handler:    _DNSService_UpdateDNSServers_Handler,
+		},
+		{
+			MethodName: "GetTLDCoverage",
+			Handler:    _DNSService_GetTLDCoverage_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _DNSService_SetLogLevel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRecords",
+			Handler:       _DNSService_StreamRecords_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamRecordBatches",
+			Handler:       _DNSService_StreamRecordBatches_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeRecordChanges",
+			Handler:       _DNSService_SubscribeRecordChanges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailEvents",
+			Handler:       _DNSService_TailEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bell/v1/bell.proto",
+}
+
+const (
+	AdminService_CreateAPIKey_FullMethodName    = "/bell.v1.AdminService/CreateAPIKey"
+	AdminService_RotateAPIKey_FullMethodName    = "/bell.v1.AdminService/RotateAPIKey"
+	AdminService_RevokeAPIKey_FullMethodName    = "/bell.v1.AdminService/RevokeAPIKey"
+	AdminService_SetAPIKeyQuota_FullMethodName  = "/bell.v1.AdminService/SetAPIKeyQuota"
+	AdminService_SetAPIKeyScopes_FullMethodName = "/bell.v1.AdminService/SetAPIKeyScopes"
+	AdminService_ListAPIKeys_FullMethodName     = "/bell.v1.AdminService/ListAPIKeys"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService manages API keys. Every RPC requires an API key with the
+// admin scope (see authorization in config.yaml).
+type AdminServiceClient interface {
+	// CreateAPIKey issues a new key, generated server-side
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// RotateAPIKey issues a replacement for a key with the same attributes,
+	// moving its preferences and report schedules, and retires the old key
+	// after a grace period
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// RevokeAPIKey deactivates a key at once
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// SetAPIKeyQuota changes the monthly request and record quotas of a key
+	SetAPIKeyQuota(ctx context.Context, in *SetAPIKeyQuotaRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// SetAPIKeyScopes replaces the scopes granted to a key
+	SetAPIKeyScopes(ctx context.Context, in *SetAPIKeyScopesRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// ListAPIKeys returns keys, newest first
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_CreateAPIKey_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// DNSServiceServer is the server API for DNSService service.
-// All implementations must embed UnimplementedDNSServiceServer
+func (c *adminServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_RotateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetAPIKeyQuota(ctx context.Context, in *SetAPIKeyQuotaRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_SetAPIKeyQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetAPIKeyScopes(ctx context.Context, in *SetAPIKeyScopesRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_SetAPIKeyScopes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
 // for forward compatibility
-type DNSServiceServer interface {
-	// Authenticate validates an API key
-	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
-	// GetRecords retrieves DNS records for a domain, filterable by record type
-	GetRecords(context.Context, *GetRecordsRequest) (*GetRecordsResponse, error)
-	mustEmbedUnimplementedDNSServiceServer()
+//
+// AdminService manages API keys. Every RPC requires an API key with the
+// admin scope (see authorization in config.yaml).
+type AdminServiceServer interface {
+	// CreateAPIKey issues a new key, generated server-side
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*APIKey, error)
+	// RotateAPIKey issues a replacement for a key with the same attributes,
+	// moving its preferences and report schedules, and retires the old key
+	// after a grace period
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*APIKey, error)
+	// RevokeAPIKey deactivates a key at once
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error)
+	// SetAPIKeyQuota changes the monthly request and record quotas of a key
+	SetAPIKeyQuota(context.Context, *SetAPIKeyQuotaRequest) (*APIKey, error)
+	// SetAPIKeyScopes replaces the scopes granted to a key
+	SetAPIKeyScopes(context.Context, *SetAPIKeyScopesRequest) (*APIKey, error)
+	// ListAPIKeys returns keys, newest first
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
 }
 
-// UnimplementedDNSServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedDNSServiceServer struct {
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
 }
 
-func (UnimplementedDNSServiceServer) Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+func (UnimplementedAdminServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
 }
-func (UnimplementedDNSServiceServer) GetRecords(context.Context, *GetRecordsRequest) (*GetRecordsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRecords not implemented")
+func (UnimplementedAdminServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAPIKey not implemented")
 }
-func (UnimplementedDNSServiceServer) mustEmbedUnimplementedDNSServiceServer() {}
+func (UnimplementedAdminServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedAdminServiceServer) SetAPIKeyQuota(context.Context, *SetAPIKeyQuotaRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAPIKeyQuota not implemented")
+}
+func (UnimplementedAdminServiceServer) SetAPIKeyScopes(context.Context, *SetAPIKeyScopesRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAPIKeyScopes not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
 
-// UnsafeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to DNSServiceServer will
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
 // result in compilation errors.
-type UnsafeDNSServiceServer interface {
-	mustEmbedUnimplementedDNSServiceServer()
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
 }
 
-func RegisterDNSServiceServer(s grpc.ServiceRegistrar, srv DNSServiceServer) {
-	s.RegisterService(&DNSService_ServiceDesc, srv)
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
 }
 
-func _DNSService_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthenticateRequest)
+func _AdminService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DNSServiceServer).Authenticate(ctx, in)
+		return srv.(AdminServiceServer).CreateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DNSService_Authenticate_FullMethodName,
+		FullMethod: AdminService_CreateAPIKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DNSServiceServer).Authenticate(ctx, req.(*AuthenticateRequest))
+		return srv.(AdminServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DNSService_GetRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRecordsRequest)
+func _AdminService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DNSServiceServer).GetRecords(ctx, in)
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DNSService_GetRecords_FullMethodName,
+		FullMethod: AdminService_RotateAPIKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DNSServiceServer).GetRecords(ctx, req.(*GetRecordsRequest))
+		return srv.(AdminServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// DNSService_ServiceDesc is the grpc.ServiceDesc for DNSService service.
+func _AdminService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetAPIKeyQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAPIKeyQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetAPIKeyQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetAPIKeyQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetAPIKeyQuota(ctx, req.(*SetAPIKeyQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetAPIKeyScopes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAPIKeyScopesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetAPIKeyScopes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetAPIKeyScopes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetAPIKeyScopes(ctx, req.(*SetAPIKeyScopesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var DNSService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "bell.v1.DNSService",
-	HandlerType: (*DNSServiceServer)(nil),
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bell.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Authenticate",
-			Handler:    _DNSService_Authenticate_Handler,
+			MethodName: "CreateAPIKey",
+			Handler:    _AdminService_CreateAPIKey_Handler,
 		},
 		{
-			MethodName: "GetRecords",
-			Handler:    _DNSService_GetRecords_Handler,
+			MethodName: "RotateAPIKey",
+			Handler:    _AdminService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _AdminService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "SetAPIKeyQuota",
+			Handler:    _AdminService_SetAPIKeyQuota_Handler,
+		},
+		{
+			MethodName: "SetAPIKeyScopes",
+			Handler:    _AdminService_SetAPIKeyScopes_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _AdminService_ListAPIKeys_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
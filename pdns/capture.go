@@ -0,0 +1,411 @@
+package pdns
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// dnstapContentType is the Frame Streams content type of dnstap payloads.
+const dnstapContentType = "protobuf:dnstap.Dnstap"
+
+// Frame Streams control frame types and fields.
+const (
+	fstrmControlAccept      = 1
+	fstrmControlStart       = 2
+	fstrmControlStop        = 3
+	fstrmControlReady       = 4
+	fstrmControlFinish      = 5
+	fstrmFieldContentType   = 1
+	fstrmMaxControlFrameLen = 512
+	fstrmMaxDataFrameLen    = 1 << 20
+)
+
+// readDNSTAP reads a dnstap file: a unidirectional Frame Streams stream as
+// written by dnstap-enabled resolvers (dnstap { ... file } or dnstap -w).
+func readDNSTAP(r io.Reader, emit func(observation) error) error {
+	return readFrameStream(bufio.NewReader(r), nil, func(frame []byte) error {
+		return emitDNSTAP(frame, emit)
+	})
+}
+
+// readFrameStream reads Frame Streams data frames from r and passes each to
+// onFrame until the sender stops. With w set, the bidirectional handshake
+// used on sockets is performed: READY is answered with ACCEPT and STOP with
+// FINISH.
+func readFrameStream(r io.Reader, w io.Writer, onFrame func([]byte) error) error {
+	started := false
+	for {
+		var length uint32
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			if errors.Is(err, io.EOF) && !started {
+				return nil
+			}
+			return err
+		}
+		if length > 0 {
+			if !started {
+				return fmt.Errorf("data frame before START")
+			}
+			if length > fstrmMaxDataFrameLen {
+				return fmt.Errorf("data frame of %d bytes exceeds %d", length, fstrmMaxDataFrameLen)
+			}
+			frame := make([]byte, length)
+			if _, err := io.ReadFull(r, frame); err != nil {
+				return err
+			}
+			if err := onFrame(frame); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// A zero length escapes a control frame
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return err
+		}
+		if length < 4 || length > fstrmMaxControlFrameLen {
+			return fmt.Errorf("invalid control frame length %d", length)
+		}
+		control := make([]byte, length)
+		if _, err := io.ReadFull(r, control); err != nil {
+			return err
+		}
+		switch binary.BigEndian.Uint32(control) {
+		case fstrmControlReady:
+			if w == nil {
+				return fmt.Errorf("unexpected READY in a unidirectional stream")
+			}
+			if !hasContentType(control[4:], dnstapContentType) {
+				return fmt.Errorf("sender does not offer content type %s", dnstapContentType)
+			}
+			if err := writeControlFrame(w, fstrmControlAccept); err != nil {
+				return err
+			}
+		case fstrmControlStart:
+			if fields := control[4:]; len(fields) > 0 && !hasContentType(fields, dnstapContentType) {
+				return fmt.Errorf("stream content type is not %s", dnstapContentType)
+			}
+			started = true
+		case fstrmControlStop:
+			if w != nil {
+				return writeControlFrame(w, fstrmControlFinish)
+			}
+			return nil
+		default:
+			return fmt.Errorf("unexpected control frame type %d", binary.BigEndian.Uint32(control))
+		}
+	}
+}
+
+// hasContentType reports whether control frame fields include contentType.
+func hasContentType(fields []byte, contentType string) bool {
+	for len(fields) >= 8 {
+		field, length := binary.BigEndian.Uint32(fields), binary.BigEndian.Uint32(fields[4:])
+		fields = fields[8:]
+		if uint32(len(fields)) < length {
+			return false
+		}
+		if field == fstrmFieldContentType && string(fields[:length]) == contentType {
+			return true
+		}
+		fields = fields[length:]
+	}
+	return false
+}
+
+// writeControlFrame writes a control frame of controlType, with the dnstap
+// content type unless it is FINISH.
+func writeControlFrame(w io.Writer, controlType uint32) error {
+	frame := binary.BigEndian.AppendUint32(nil, controlType)
+	if controlType != fstrmControlFinish {
+		frame = binary.BigEndian.AppendUint32(frame, fstrmFieldContentType)
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(dnstapContentType)))
+		frame = append(frame, dnstapContentType...)
+	}
+	header := binary.BigEndian.AppendUint32(make([]byte, 4), uint32(len(frame)))
+	_, err := w.Write(append(header, frame...))
+	return err
+}
+
+// Field numbers of the dnstap protobuf schema (dnstap.proto) that are read.
+const (
+	dnstapFieldMessage          = 14 // Dnstap.message
+	messageFieldResponseTimeSec = 12 // Message.response_time_sec
+	messageFieldResponseMessage = 14 // Message.response_message
+)
+
+// emitDNSTAP decodes a dnstap payload and emits the answers of its DNS
+// response, if it carries one. Queries and other payloads are ignored.
+func emitDNSTAP(frame []byte, emit func(observation) error) error {
+	message, ok := protoBytesField(frame, dnstapFieldMessage)
+	if !ok {
+		return nil
+	}
+	response, ok := protoBytesField(message, messageFieldResponseMessage)
+	if !ok {
+		return nil
+	}
+	seen := time.Now().UTC()
+	if secs, ok := protoVarintField(message, messageFieldResponseTimeSec); ok {
+		seen = time.Unix(int64(secs), 0).UTC()
+	}
+	return emitResponse(response, seen, emit)
+}
+
+// protoBytesField returns the last occurrence of a length-delimited field
+// in an encoded protobuf message.
+func protoBytesField(b []byte, field protowire.Number) ([]byte, bool) {
+	var value []byte
+	found := false
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, false
+		}
+		b = b[n:]
+		if num == field && typ == protowire.BytesType {
+			v, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				return nil, false
+			}
+			value, found = v, true
+			b = b[m:]
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return nil, false
+		}
+		b = b[m:]
+	}
+	return value, found
+}
+
+// protoVarintField returns the last occurrence of a varint field in an
+// encoded protobuf message.
+func protoVarintField(b []byte, field protowire.Number) (uint64, bool) {
+	var value uint64
+	found := false
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return 0, false
+		}
+		b = b[n:]
+		if num == field && typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(b)
+			if m < 0 {
+				return 0, false
+			}
+			value, found = v, true
+			b = b[m:]
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return 0, false
+		}
+		b = b[m:]
+	}
+	return value, found
+}
+
+// emitResponse emits the answer records of a successful DNS response.
+// Messages that do not unpack, queries and error responses are ignored.
+func emitResponse(wire []byte, seen time.Time, emit func(observation) error) error {
+	var m dns.Msg
+	if err := m.Unpack(wire); err != nil || !m.Response || m.Rcode != dns.RcodeSuccess {
+		return nil
+	}
+	for _, rr := range m.Answer {
+		header := rr.Header()
+		rdata := strings.TrimPrefix(rr.String(), header.String())
+		err := emit(observation{
+			name:      header.Name,
+			rrtype:    dns.TypeToString[header.Rrtype],
+			rdata:     rdata,
+			ttl:       int(header.Ttl),
+			firstSeen: seen,
+			lastSeen:  seen,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listenDNSTAP accepts dnstap connections on addr (unix:/path or
+// tcp:host:port) and emits the answers of every stream until the listener
+// fails. Each connection is read on its own goroutine, so emit must be safe
+// for concurrent use.
+func listenDNSTAP(addr string, emit func(observation) error) error {
+	network, address, ok := strings.Cut(addr, ":")
+	if !ok || (network != "unix" && network != "tcp") {
+		return fmt.Errorf("invalid listen address %q; expected unix:/path or tcp:host:port", addr)
+	}
+	lis, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	defer lis.Close()
+	logger.Infof("Listening for dnstap on %s", addr)
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			err := readFrameStream(bufio.NewReader(conn), conn, func(frame []byte) error {
+				return emitDNSTAP(frame, emit)
+			})
+			if err != nil {
+				logger.Errorf("Error reading dnstap from %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// pcap link types whose frames are decoded.
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+	linkTypeLoop     = 108
+	linkTypeLinuxSL2 = 276
+)
+
+// readPCAP reads a pcap capture, such as tcpdump -w - port 53 piped to
+// stdin, and emits the answers of DNS responses sent over UDP from port 53.
+// TCP responses are not reassembled and pcapng is not supported.
+func readPCAP(r io.Reader, emit func(observation) error) error {
+	br := bufio.NewReader(r)
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("failed to read pcap header: %v", err)
+	}
+	var order binary.ByteOrder
+	var nanos bool
+	switch binary.LittleEndian.Uint32(header) {
+	case 0xa1b2c3d4:
+		order = binary.LittleEndian
+	case 0xa1b23c4d:
+		order, nanos = binary.LittleEndian, true
+	case 0xd4c3b2a1:
+		order = binary.BigEndian
+	case 0x4d3cb2a1:
+		order, nanos = binary.BigEndian, true
+	default:
+		return fmt.Errorf("not a pcap capture (pcapng is not supported)")
+	}
+	linkType := order.Uint32(header[20:]) & 0xffff
+
+	record := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(br, record); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		secs, frac, length := order.Uint32(record), order.Uint32(record[4:]), order.Uint32(record[8:])
+		if length > fstrmMaxDataFrameLen {
+			return fmt.Errorf("packet of %d bytes exceeds %d", length, fstrmMaxDataFrameLen)
+		}
+		packet := make([]byte, length)
+		if _, err := io.ReadFull(br, packet); err != nil {
+			return err
+		}
+		if !nanos {
+			frac *= 1000
+		}
+		payload, ok := dnsPayload(packet, linkType)
+		if !ok {
+			continue
+		}
+		if err := emitResponse(payload, time.Unix(int64(secs), int64(frac)).UTC(), emit); err != nil {
+			return err
+		}
+	}
+}
+
+// dnsPayload returns the UDP payload of a packet sent from port 53.
+func dnsPayload(packet []byte, linkType uint32) ([]byte, bool) {
+	var etherType uint16
+	switch linkType {
+	case linkTypeEthernet:
+		if len(packet) < 14 {
+			return nil, false
+		}
+		etherType, packet = binary.BigEndian.Uint16(packet[12:]), packet[14:]
+		for etherType == 0x8100 || etherType == 0x88a8 { // 802.1Q/802.1ad VLAN tags
+			if len(packet) < 4 {
+				return nil, false
+			}
+			etherType, packet = binary.BigEndian.Uint16(packet[2:]), packet[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(packet) < 16 {
+			return nil, false
+		}
+		etherType, packet = binary.BigEndian.Uint16(packet[14:]), packet[16:]
+	case linkTypeLinuxSL2:
+		if len(packet) < 20 {
+			return nil, false
+		}
+		etherType, packet = binary.BigEndian.Uint16(packet), packet[20:]
+	case linkTypeNull, linkTypeLoop, linkTypeRaw:
+		if linkType != linkTypeRaw {
+			if len(packet) < 4 {
+				return nil, false
+			}
+			packet = packet[4:]
+		}
+		if len(packet) == 0 {
+			return nil, false
+		}
+		etherType = 0x0800
+		if packet[0]>>4 == 6 {
+			etherType = 0x86dd
+		}
+	default:
+		return nil, false
+	}
+
+	var udp []byte
+	switch etherType {
+	case 0x0800:
+		if len(packet) < 20 || packet[0]>>4 != 4 {
+			return nil, false
+		}
+		headerLen := int(packet[0]&0x0f) * 4
+		// Fragmented responses are skipped
+		if packet[9] != 17 || binary.BigEndian.Uint16(packet[6:])&0x3fff != 0 || len(packet) < headerLen {
+			return nil, false
+		}
+		udp = packet[headerLen:]
+	case 0x86dd:
+		// Extension headers are not followed
+		if len(packet) < 40 || packet[6] != 17 {
+			return nil, false
+		}
+		udp = packet[40:]
+	default:
+		return nil, false
+	}
+	if len(udp) < 8 || binary.BigEndian.Uint16(udp) != 53 {
+		return nil, false
+	}
+	return udp[8:], true
+}
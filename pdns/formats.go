@@ -0,0 +1,198 @@
+package pdns
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// observation is one record seen by a passive DNS sensor between firstSeen
+// and lastSeen. ttl is -1 when the export does not carry TTLs.
+type observation struct {
+	name      string // Owner name as exported, e.g. www.example.com.
+	rrtype    string
+	rdata     string // Presentation format, e.g. "10 mail.example.com." for MX
+	ttl       int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// readers maps -format names to the readers of their exports. A reader
+// calls emit for every observation and stops at the first error.
+var readers = map[string]func(r io.Reader, emit func(observation) error) error{
+	"dnsdb":  readDNSDB,
+	"csv":    readCSV,
+	"dnstap": readDNSTAP,
+	"pcap":   readPCAP,
+}
+
+// defaultSources maps -format names to the source the records are tagged
+// with unless -source is given.
+var defaultSources = map[string]string{
+	"dnsdb":  "DNSDB",
+	"csv":    "PCAP",
+	"dnstap": "DNSTAP",
+	"pcap":   "PCAP",
+}
+
+// dnsdbRecord is a Farsight DNSDB RRset result. rdata is a string or a list
+// of strings depending on the export; zone_time_* replace time_* for
+// records seen in zone files rather than by sensors.
+type dnsdbRecord struct {
+	RRName        string          `json:"rrname"`
+	RRType        string          `json:"rrtype"`
+	RData         json.RawMessage `json:"rdata"`
+	TimeFirst     int64           `json:"time_first"`
+	TimeLast      int64           `json:"time_last"`
+	ZoneTimeFirst int64           `json:"zone_time_first"`
+	ZoneTimeLast  int64           `json:"zone_time_last"`
+}
+
+// readDNSDB reads DNSDB NDJSON exports, both the plain API v1 form (one
+// record per line) and the v2 streaming form, where records are wrapped in
+// {"obj": ...} between {"cond": ...} control lines.
+func readDNSDB(r io.Reader, emit func(observation) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var wrapped struct {
+			Obj  *dnsdbRecord `json:"obj"`
+			Cond string       `json:"cond"`
+		}
+		if err := json.Unmarshal([]byte(text), &wrapped); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		rec := wrapped.Obj
+		if rec == nil {
+			if wrapped.Cond != "" {
+				continue
+			}
+			rec = &dnsdbRecord{}
+			if err := json.Unmarshal([]byte(text), rec); err != nil {
+				return fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		var rdata []string
+		if err := json.Unmarshal(rec.RData, &rdata); err != nil {
+			var single string
+			if err := json.Unmarshal(rec.RData, &single); err != nil {
+				return fmt.Errorf("line %d: rdata is neither a string nor a list of strings", line)
+			}
+			rdata = []string{single}
+		}
+		first, last := rec.TimeFirst, rec.TimeLast
+		if first == 0 && last == 0 {
+			first, last = rec.ZoneTimeFirst, rec.ZoneTimeLast
+		}
+		for _, data := range rdata {
+			err := emit(observation{
+				name:      rec.RRName,
+				rrtype:    rec.RRType,
+				rdata:     data,
+				ttl:       -1,
+				firstSeen: time.Unix(first, 0).UTC(),
+				lastSeen:  time.Unix(last, 0).UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// csvColumns lists the header names accepted for each CSV field, as used by
+// common pcap-to-CSV tools (tshark field exports, passivedns, zeek dns.log
+// conversions).
+var csvColumns = map[string][]string{
+	"time":  {"timestamp", "ts", "time", "frame.time_epoch"},
+	"name":  {"query", "qname", "rrname", "name", "dns.qry.name", "dns.resp.name"},
+	"type":  {"type", "qtype", "rrtype", "qtype_name"},
+	"rdata": {"answer", "answers", "rdata", "data"},
+	"ttl":   {"ttl", "ttls", "dns.resp.ttl"},
+}
+
+// readCSV reads pcap-derived CSV with a header row naming its columns (see
+// csvColumns). Each row is one answer; rows without an answer (queries,
+// NXDOMAIN) are skipped. Timestamps are Unix seconds, possibly fractional,
+// or RFC 3339.
+func readCSV(r io.Reader, emit func(observation) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %v", err)
+	}
+	index := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		for field, names := range csvColumns {
+			for _, name := range names {
+				if _, seen := index[field]; !seen && column == name {
+					index[field] = i
+				}
+			}
+		}
+	}
+	for _, field := range []string{"time", "name", "type", "rdata"} {
+		if _, ok := index[field]; !ok {
+			return fmt.Errorf("header has no %s column (one of %s)", field, strings.Join(csvColumns[field], ", "))
+		}
+	}
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		get := func(field string) string {
+			if i, ok := index[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if get("rdata") == "" {
+			continue
+		}
+		seen, err := parseCSVTime(get("time"))
+		if err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		ttl := -1
+		if v := get("ttl"); v != "" {
+			if ttl, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("line %d: invalid ttl %q", line, v)
+			}
+		}
+		err = emit(observation{name: get("name"), rrtype: get("type"), rdata: get("rdata"), ttl: ttl, firstSeen: seen, lastSeen: seen})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVTime(v string) (time.Time, error) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Unix(0, int64(secs*float64(time.Second))).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,384 @@
+// Package pdns imports existing passive DNS datasets (Farsight DNSDB NDJSON
+// exports, pcap-derived CSV) into domains and dns_records, so that history
+// collected elsewhere can seed the database. It also collects live: the
+// answers of dnstap streams from resolvers we operate (-listen) or of a pcap
+// capture piped from tcpdump are written as they arrive.
+//
+// Each distinct record becomes one dns_records row tagged with the import
+// source and last_updated set to when it was last seen. Records are kept
+// only for registrable domains and their underscore service names (such as
+// _sip._tcp.example.com), the names the rest of the pipeline stores; the
+// domain's first_seen is moved back to the earliest observation.
+package pdns
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/pressure"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+// logger logs the pdns tool's messages (see the logging package).
+var logger = logging.New("pdns")
+
+// importRecordTypes are the types with a dns_records partition, except PTR:
+// reverse zones are stored in ptr_records by czds.
+var importRecordTypes = map[string]bool{
+	"NS":     true,
+	"A":      true,
+	"AAAA":   true,
+	"MX":     true,
+	"TXT":    true,
+	"CNAME":  true,
+	"SOA":    true,
+	"SRV":    true,
+	"NAPTR":  true,
+	"TLSA":   true,
+	"CAA":    true,
+	"DNSKEY": true,
+	"DS":     true,
+}
+
+// importedRecord is a distinct record of a batch with its observations
+// combined.
+type importedRecord struct {
+	domain     string
+	tld        string
+	suffix     string // Public suffix of domain
+	recordType string
+	recordData string // Zone file format, as stored by czds and the query worker
+	ttl        sql.NullInt32
+	firstSeen  time.Time
+	lastSeen   time.Time
+	priority   sql.NullInt32
+	weight     sql.NullInt32
+}
+
+// importStats counts what an import did with the observations it read.
+type importStats struct {
+	read, skipped, stored int64
+}
+
+// importer batches observations and writes them to the shard owning each
+// domain's TLD. It is safe for concurrent use.
+type importer struct {
+	shards           *storage.Router
+	source           string
+	batchSize        int
+	compressMinBytes int               // TXT data at least this long is stored compressed (see storage.EncodeRecordData)
+	pressure         *pressure.Monitor // Holds writes while a shard is under pressure; nil never holds them
+
+	mu    sync.Mutex
+	batch map[string]*importedRecord // Domain, type and canonical data -> record
+	stats importStats
+}
+
+// add converts an observation to a record and merges it into the batch,
+// flushing once the batch is full. Observations of names outside the stored
+// namespace, of other types, or with data that does not parse are skipped.
+func (im *importer) add(o observation) error {
+	rec, ok := im.convert(o)
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.stats.read++
+	if !ok {
+		im.stats.skipped++
+		return nil
+	}
+	key := rec.domain + " " + rec.recordType + " " + recordset.Canonical(rec.recordData)
+	if existing, ok := im.batch[key]; ok {
+		if rec.firstSeen.Before(existing.firstSeen) {
+			existing.firstSeen = rec.firstSeen
+		}
+		if rec.lastSeen.After(existing.lastSeen) {
+			existing.lastSeen = rec.lastSeen
+			if rec.ttl.Valid {
+				existing.ttl = rec.ttl
+			}
+		}
+		return nil
+	}
+	im.batch[key] = rec
+	if len(im.batch) >= im.batchSize {
+		return im.flushLocked()
+	}
+	return nil
+}
+
+// flush writes the batch.
+func (im *importer) flush() error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	return im.flushLocked()
+}
+
+// flushEvery flushes the batch every interval so that live captures are
+// written without waiting for a full batch. Write failures are logged and
+// the batch is kept for the next attempt.
+func (im *importer) flushEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := im.flush(); err != nil {
+			logger.Errorf("Error flushing captured records: %v", err)
+		}
+	}
+}
+
+func (im *importer) convert(o observation) (*importedRecord, bool) {
+	recordType := strings.ToUpper(o.rrtype)
+	if !importRecordTypes[recordType] {
+		return nil, false
+	}
+	name := strings.ToLower(strings.TrimSuffix(o.name, "."))
+	domain := recordset.OwnerDomain(name)
+	if registrable, err := publicsuffix.EffectiveTLDPlusOne(domain); err != nil || registrable != domain {
+		return nil, false
+	}
+	ttl := o.ttl
+	if ttl < 0 {
+		ttl = 0
+	}
+	rr, err := dns.NewRR(fmt.Sprintf("%s. %d IN %s %s", name, ttl, recordType, o.rdata))
+	if err != nil || rr == nil {
+		return nil, false
+	}
+	rec := &importedRecord{
+		domain:     domain,
+		tld:        recordset.TLD(domain),
+		suffix:     recordset.PublicSuffix(domain),
+		recordType: recordType,
+		recordData: recordset.NormalizeRR(rr),
+		ttl:        sql.NullInt32{Int32: int32(o.ttl), Valid: o.ttl >= 0},
+		firstSeen:  o.firstSeen,
+		lastSeen:   o.lastSeen,
+	}
+	rec.priority, rec.weight = recordset.SortKeys(rr)
+	return rec, true
+}
+
+// flushLocked writes the batch, one transaction per shard, holding each
+// shard's write while it is under pressure. The caller holds mu.
+func (im *importer) flushLocked() error {
+	if len(im.batch) == 0 {
+		return nil
+	}
+	byShard := make(map[*storage.Shard][]*importedRecord)
+	for _, rec := range im.batch {
+		shard := im.shards.ForDomain(rec.domain)
+		byShard[shard] = append(byShard[shard], rec)
+	}
+	for shard, records := range byShard {
+		slow := im.pressure.Wait(shard, len(records), nil)
+		began := time.Now()
+		if err := storeImported(shard.DB, records, im.source, im.compressMinBytes); err != nil {
+			return fmt.Errorf("failed to store %d records on shard %s: %v", len(records), shard.Name, err)
+		}
+		im.stats.stored += int64(len(records))
+		if slow {
+			time.Sleep(time.Since(began))
+		}
+	}
+	logger.Infof("Imported %d records (%d observations read, %d skipped)", im.stats.stored, im.stats.read, im.stats.skipped)
+	im.batch = make(map[string]*importedRecord)
+	return nil
+}
+
+// storeImported upserts the domains of records, moving first_seen back to
+// the earliest observation, and inserts the records, compressing TXT data of
+// at least compressMinBytes. Once committed, it publishes record_set_changed
+// on db for each record set that gained a record. Imports keep no record
+// set checksums, so these changes carry no version and are unclassified.
+func storeImported(db *sql.DB, records []*importedRecord, source string, compressMinBytes int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	domainStmt, err := tx.Prepare(`
+		INSERT INTO domains (domain_name, tld, public_suffix, last_updated, first_seen)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (domain_name, tld) DO UPDATE
+		SET public_suffix = EXCLUDED.public_suffix,
+			last_updated = GREATEST(domains.last_updated, EXCLUDED.last_updated),
+			first_seen = LEAST(domains.first_seen, EXCLUDED.first_seen)
+		RETURNING id
+	`)
+	if err != nil {
+		return err
+	}
+	defer domainStmt.Close()
+	recordStmt, err := tx.Prepare(`
+		INSERT INTO dns_records (domain_id, record_type, record_data, record_data_z, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	` + recordset.OnRecordConflict + `
+		RETURNING xmax = 0
+	`)
+	if err != nil {
+		return err
+	}
+	defer recordStmt.Close()
+
+	type seen struct{ first, last time.Time }
+	domains := make(map[string]seen)
+	for _, rec := range records {
+		s, ok := domains[rec.domain]
+		if !ok || rec.firstSeen.Before(s.first) {
+			s.first = rec.firstSeen
+		}
+		if !ok || rec.lastSeen.After(s.last) {
+			s.last = rec.lastSeen
+		}
+		domains[rec.domain] = s
+	}
+	domainIDs := make(map[string]int)
+	for _, rec := range records {
+		if _, ok := domainIDs[rec.domain]; ok {
+			continue
+		}
+		s := domains[rec.domain]
+		var id int
+		if err := domainStmt.QueryRow(rec.domain, rec.tld, rec.suffix, s.last, s.first).Scan(&id); err != nil {
+			return fmt.Errorf("failed to upsert domain %s: %v", rec.domain, err)
+		}
+		domainIDs[rec.domain] = id
+	}
+	type setKey struct{ domain, recordType string }
+	changed := make(map[setKey]*importedRecord)
+	for _, rec := range records {
+		data, z := storage.EncodeRecordData(rec.recordType, rec.recordData, compressMinBytes)
+		var inserted bool
+		err := recordStmt.QueryRow(domainIDs[rec.domain], rec.recordType, data, z, recordset.CanonicalHash(rec.recordData), rec.ttl, source, rec.lastSeen, rec.firstSeen, rec.priority, rec.weight).
+			Scan(&inserted)
+		if err != nil {
+			return fmt.Errorf("failed to insert record for %s: %v", rec.domain, err)
+		}
+		if inserted {
+			changed[setKey{rec.domain, rec.recordType}] = rec
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, rec := range changed {
+		events.Publish(db, events.Event{Source: events.SourcePDNS, Kind: events.RecordSetChanged, TLD: rec.tld, Domain: rec.domain,
+			RecordType: rec.recordType, Significance: recordset.SignificanceUnclassified})
+	}
+	return nil
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	format := flag.String("format", "dnsdb", "Input format: dnsdb (Farsight DNSDB NDJSON), csv (pcap-derived CSV with a header row), dnstap (Frame Streams) or pcap")
+	file := flag.String("file", "-", "Export to import, gzip-compressed if it ends in .gz; - reads stdin (e.g. tcpdump -U -w - udp port 53)")
+	listen := flag.String("listen", "", "Collect dnstap streams from resolvers on unix:/path or tcp:host:port instead of reading -file (requires -format dnstap)")
+	source := flag.String("source", "", "Source tag for imported records (default DNSDB, PCAP or DNSTAP by format)")
+	batchSize := flag.Int("batch-size", 5000, "Distinct records written per transaction")
+	flushInterval := flag.Duration("flush-interval", 5*time.Second, "How often records are written while collecting live (-listen or -format pcap)")
+	flag.Parse()
+
+	read, ok := readers[*format]
+	if !ok {
+		logger.Fatalf("Unknown format %q", *format)
+	}
+	tag := strings.ToUpper(strings.TrimSpace(*source))
+	if tag == "" {
+		tag = defaultSources[*format]
+	}
+	if len(tag) > 20 {
+		logger.Fatalf("Source %q is longer than 20 characters", tag)
+	}
+	if *batchSize <= 0 {
+		logger.Fatalf("-batch-size must be positive")
+	}
+	if *listen != "" && *format != "dnstap" {
+		logger.Fatalf("-listen requires -format dnstap")
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" && *listen == "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		defer f.Close()
+		in = f
+		if strings.HasSuffix(*file, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				logger.Fatalf("Failed to open %s: %v", *file, err)
+			}
+			defer gz.Close()
+			in = gz
+		}
+	}
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Connect to AlloyDB
+	connStr := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode,
+	)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	im := &importer{
+		shards:           shards,
+		source:           tag,
+		batchSize:        *batchSize,
+		compressMinBytes: config.RecordData.CompressMinBytes,
+		pressure:         pressure.New(shards, events.SourcePDNS, config),
+		batch:            make(map[string]*importedRecord),
+	}
+	if *listen != "" || *format == "pcap" {
+		go im.flushEvery(*flushInterval)
+	}
+	if *listen != "" {
+		logger.Fatalf("%v", listenDNSTAP(*listen, im.add))
+	}
+	start := time.Now()
+	if err := read(in, im.add); err != nil {
+		logger.Fatalf("Failed to read %s export: %v", *format, err)
+	}
+	if err := im.flush(); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	logger.Infof("Imported %d records as %s in %v (%d of %d observations skipped)",
+		im.stats.stored, tag, time.Since(start).Round(time.Second), im.stats.skipped, im.stats.read)
+}
@@ -0,0 +1,173 @@
+// Package pressure holds database commits while a shard is under load, so
+// that bulk writers (the ingest service, czds and pdns imports) back off
+// before replicas fall behind or lock queues build up. It is configured by
+// ingest.pressure.
+package pressure
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/storage"
+)
+
+// loadSignals are the database load readings writers throttle on.
+type loadSignals struct {
+	replicationLag    float64 // Seconds the most lagging replica is behind in replay
+	activeConnections int     // Client connections running a statement, the monitor's own check included
+	lockWaits         int     // Connections waiting on a lock
+}
+
+// Monitor decides whether a shard can take another commit, from its
+// replication lag, active connections and lock waits. A limit of 0 ignores
+// its signal; with every limit 0 the monitor is disabled. A nil Monitor
+// never holds commits. It is safe for concurrent use.
+type Monitor struct {
+	events       *sql.DB // Where throttling events are published
+	source       string  // events.Source* of the writer
+	interval     time.Duration
+	maxLag       float64
+	maxActive    int
+	maxLockWaits int
+	maxPause     time.Duration
+
+	mu     sync.Mutex
+	shards map[string]*shardState // Shard name -> hold state
+}
+
+// shardState is the hold state of one shard. mu is held for the whole of a
+// wait, so concurrent writers to a shard share one sampling loop.
+type shardState struct {
+	mu            sync.Mutex
+	heldSince     time.Time // Zero unless commits are held
+	lastReadError string    // Logged once until a read succeeds again
+}
+
+// New returns a monitor for the shards of shards, publishing throttling
+// events from source to the default database.
+func New(shards *storage.Router, source string, cfg *config.Config) *Monitor {
+	p := cfg.Ingest.Pressure
+	return &Monitor{
+		events:       shards.Default(),
+		source:       source,
+		interval:     time.Duration(p.CheckIntervalMs) * time.Millisecond,
+		maxLag:       p.MaxReplicationLagSeconds,
+		maxActive:    p.MaxActiveConnections,
+		maxLockWaits: p.MaxLockWaits,
+		maxPause:     time.Duration(p.MaxPauseSeconds) * time.Second,
+		shards:       make(map[string]*shardState),
+	}
+}
+
+// Enabled reports whether the monitor ever holds commits.
+func (m *Monitor) Enabled() bool {
+	return m != nil && (m.maxLag > 0 || m.maxActive > 0 || m.maxLockWaits > 0)
+}
+
+func (m *Monitor) state(shard *storage.Shard) *shardState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.shards[shard.Name]
+	if !ok {
+		s = &shardState{}
+		m.shards[shard.Name] = s
+	}
+	return s
+}
+
+// read samples the load signals of db. Replication lag is only visible on
+// the primary; replicas of a database without any report 0.
+func read(db *sql.DB) (loadSignals, error) {
+	var s loadSignals
+	err := db.QueryRow(`
+		SELECT
+			(SELECT COALESCE(MAX(EXTRACT(EPOCH FROM replay_lag)), 0) FROM pg_stat_replication),
+			COUNT(*) FILTER (WHERE state = 'active'),
+			COUNT(*) FILTER (WHERE wait_event_type = 'Lock')
+		FROM pg_stat_activity
+		WHERE backend_type = 'client backend'
+	`).Scan(&s.replicationLag, &s.activeConnections, &s.lockWaits)
+	return s, err
+}
+
+// over returns why signals are at or over their limits times fraction, or
+// nothing if none is.
+func (m *Monitor) over(s loadSignals, fraction float64) []string {
+	var reasons []string
+	if m.maxLag > 0 && s.replicationLag >= m.maxLag*fraction {
+		reasons = append(reasons, fmt.Sprintf("replication lag %.1fs (limit %gs)", s.replicationLag, m.maxLag))
+	}
+	if m.maxActive > 0 && float64(s.activeConnections) >= float64(m.maxActive)*fraction {
+		reasons = append(reasons, fmt.Sprintf("%d active connections (limit %d)", s.activeConnections, m.maxActive))
+	}
+	if m.maxLockWaits > 0 && float64(s.lockWaits) >= float64(m.maxLockWaits)*fraction {
+		reasons = append(reasons, fmt.Sprintf("%d lock waits (limit %d)", s.lockWaits, m.maxLockWaits))
+	}
+	return reasons
+}
+
+// Wait blocks while shard is over a limit, re-reading its signals every
+// interval, for at most maxPause or until stop is closed. waiting is the
+// number of records about to be committed. It reports whether the next
+// commit should be followed by a pause, the shard being past half a limit.
+// Signals that cannot be read do not hold commits, so a monitoring failure
+// never stops a writer.
+func (m *Monitor) Wait(shard *storage.Shard, waiting int, stop <-chan struct{}) (slow bool) {
+	if !m.Enabled() {
+		return false
+	}
+	st := m.state(shard)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	start := time.Now()
+	for {
+		s, err := read(shard.DB)
+		if err != nil {
+			if msg := err.Error(); msg != st.lastReadError {
+				log.Printf("Failed to read load of shard %s; not throttling: %v", shard.Name, err)
+				st.lastReadError = msg
+			}
+			m.release(shard, st, waiting)
+			return false
+		}
+		st.lastReadError = ""
+		reasons := m.over(s, 1)
+		if len(reasons) == 0 {
+			m.release(shard, st, waiting)
+			return len(m.over(s, 0.5)) > 0
+		}
+		if st.heldSince.IsZero() {
+			st.heldSince = time.Now()
+			message := fmt.Sprintf("shard %s under pressure: %s", shard.Name, strings.Join(reasons, ", "))
+			log.Printf("Holding commits of %d records; %s", waiting, message)
+			events.Publish(m.events, events.Event{Source: m.source, Kind: events.Throttled, Count: int64(waiting), Message: message})
+		}
+		if time.Since(start) >= m.maxPause {
+			log.Printf("Committing %d records to shard %s after holding them %v; %s", waiting, shard.Name, m.maxPause, strings.Join(reasons, ", "))
+			return true
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(m.interval):
+		}
+	}
+}
+
+// release ends a hold of commits to shard, if any. The caller holds st.mu.
+func (m *Monitor) release(shard *storage.Shard, st *shardState, waiting int) {
+	if st.heldSince.IsZero() {
+		return
+	}
+	held := time.Since(st.heldSince).Round(time.Second)
+	st.heldSince = time.Time{}
+	log.Printf("Resuming commits of %d records to shard %s after holding them %v", waiting, shard.Name, held)
+	events.Publish(m.events, events.Event{Source: m.source, Kind: events.Unthrottled, Count: int64(held / time.Second),
+		Message: "shard " + shard.Name})
+}
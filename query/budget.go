@@ -0,0 +1,139 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errBudgetExhausted is returned when a domain or its TLD has used up its
+// crawl budget; the domain's remaining record types are skipped.
+var errBudgetExhausted = errors.New("crawl budget exhausted")
+
+// tldBudget tracks the DNS queries and wall time spent on one TLD's domains
+// during a batch.
+type tldBudget struct {
+	mu         sync.Mutex
+	queries    int
+	elapsed    time.Duration
+	maxQueries int
+	maxElapsed time.Duration
+}
+
+func (t *tldBudget) exhausted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.queries >= t.maxQueries || t.elapsed >= t.maxElapsed
+}
+
+// crawlBudget limits the DNS queries and wall time spent on one domain and
+// charges them to the domain's TLD.
+type crawlBudget struct {
+	deadline   time.Time // End of the domain's wall time
+	queries    int
+	maxQueries int
+	tld        *tldBudget
+}
+
+// spend accounts for one DNS exchange (including retries and NS discovery)
+// and returns errBudgetExhausted if the domain or TLD budget is already used
+// up or the deadline has passed. If ctx was cancelled for shutdown, it
+// returns ctx's error instead.
+func (b *crawlBudget) spend(ctx context.Context) error {
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if !time.Now().Before(b.deadline) {
+		return fmt.Errorf("%w: wall time limit reached", errBudgetExhausted)
+	}
+	if b.queries >= b.maxQueries {
+		return fmt.Errorf("%w: %d queries used", errBudgetExhausted, b.queries)
+	}
+	b.tld.mu.Lock()
+	defer b.tld.mu.Unlock()
+	if b.tld.queries >= b.tld.maxQueries {
+		return fmt.Errorf("%w: TLD query limit of %d reached", errBudgetExhausted, b.tld.maxQueries)
+	}
+	b.queries++
+	b.tld.queries++
+	return nil
+}
+
+// scheduler orders each batch of domains fairly across TLDs and enforces
+// per-domain and per-TLD crawl budgets, so a zone full of broken
+// nameservers cannot starve the rest of the backlog. TLD budgets reset with
+// every batch.
+type scheduler struct {
+	domainMaxQueries int
+	domainMaxTime    time.Duration
+	tldMaxQueries    int
+	tldMaxTime       time.Duration
+
+	mu   sync.Mutex
+	tlds map[string]*tldBudget
+}
+
+func newScheduler(domainMaxQueries int, domainMaxTime time.Duration, tldMaxQueries int, tldMaxTime time.Duration) *scheduler {
+	return &scheduler{
+		domainMaxQueries: domainMaxQueries,
+		domainMaxTime:    domainMaxTime,
+		tldMaxQueries:    tldMaxQueries,
+		tldMaxTime:       tldMaxTime,
+		tlds:             make(map[string]*tldBudget),
+	}
+}
+
+// order resets the TLD budgets and returns domains interleaved round-robin
+// by TLD, keeping each TLD's domains in their original order.
+func (s *scheduler) order(domains []DomainInfo) []DomainInfo {
+	s.mu.Lock()
+	s.tlds = make(map[string]*tldBudget)
+	s.mu.Unlock()
+
+	byTLD := make(map[string][]DomainInfo)
+	var tlds []string
+	for _, d := range domains {
+		if _, ok := byTLD[d.TLD]; !ok {
+			tlds = append(tlds, d.TLD)
+		}
+		byTLD[d.TLD] = append(byTLD[d.TLD], d)
+	}
+	sort.Strings(tlds)
+	ordered := make([]DomainInfo, 0, len(domains))
+	for len(ordered) < len(domains) {
+		for _, tld := range tlds {
+			if queue := byTLD[tld]; len(queue) > 0 {
+				ordered = append(ordered, queue[0])
+				byTLD[tld] = queue[1:]
+			}
+		}
+	}
+	return ordered
+}
+
+// admit returns a budget for crawling d, or false if d's TLD has used up its
+// budget for this batch and d should be deferred. The caller must call the
+// returned done function when it finishes with the domain.
+func (s *scheduler) admit(d DomainInfo) (*crawlBudget, func(), bool) {
+	s.mu.Lock()
+	tld, ok := s.tlds[d.TLD]
+	if !ok {
+		tld = &tldBudget{maxQueries: s.tldMaxQueries, maxElapsed: s.tldMaxTime}
+		s.tlds[d.TLD] = tld
+	}
+	s.mu.Unlock()
+	if tld.exhausted() {
+		return nil, nil, false
+	}
+
+	start := time.Now()
+	done := func() {
+		tld.mu.Lock()
+		tld.elapsed += time.Since(start)
+		tld.mu.Unlock()
+	}
+	return &crawlBudget{deadline: start.Add(s.domainMaxTime), maxQueries: s.domainMaxQueries, tld: tld}, done, true
+}
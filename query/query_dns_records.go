@@ -1,44 +1,65 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"math/rand"
+	"net"
+	"net/http"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/lib/pq"
 	"github.com/miekg/dns"
+	"github.com/moos3/bell/asndb"
 	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/events"
+	"github.com/moos3/bell/ingest"
+	"github.com/moos3/bell/lease"
+	"github.com/moos3/bell/logging"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/resolver"
+	"github.com/moos3/bell/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var recordTypes = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeMX, dns.TypeTXT, dns.TypeCNAME}
+// recordTypes are resolved for every domain. SRV and TLSA are resolved under
+// each configured prefix (service names, TLSA ports) rather than at the
+// domain itself. DNSKEY feeds the dnssec-adoption key size inventory.
+// logger logs the query tool's messages (see the logging package).
+var logger = logging.New("query")
+
+var recordTypes = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeMX, dns.TypeTXT, dns.TypeCNAME, dns.TypeNAPTR, dns.TypeSRV, dns.TypeTLSA, dns.TypeDNSKEY}
 
 type DomainInfo struct {
+	Shard       *storage.Shard // Shard holding the domain; IDs are only unique within a shard
 	ID          int
 	Domain      string
 	TLD         string
 	Nameservers pq.StringArray
+	Customer    bool // Tracked by an organization; due by customer_domains.last_queried rather than the progress cursor
 }
 
-func getDomainsAndNameservers(db *sql.DB, lastDomainID *int, batchSize int) ([]DomainInfo, error) {
-	query := `
+func getDomainsAndNameservers(ctx context.Context, shard *storage.Shard, lastDomainID *int, batchSize int) ([]DomainInfo, error) {
+	query := storage.NewQuery(`
 		SELECT id, domain_name, tld, nameservers
 		FROM domains
-		WHERE nameservers != '{}'
-		AND (last_updated IS NULL OR last_updated < NOW() - INTERVAL '12 hours')
-	`
-	args := []interface{}{}
+	`).Where("nameservers != '{}'").Where("last_updated IS NULL OR last_updated < NOW() - INTERVAL '12 hours'")
 	if lastDomainID != nil {
-		query += " AND id > $1"
-		args = append(args, *lastDomainID)
+		query.Where("id > ?", *lastDomainID)
 	}
-	query += fmt.Sprintf(" ORDER BY id LIMIT %d", batchSize)
-	rows, err := db.Query(query, args...)
+	query.Append("ORDER BY id LIMIT ?", batchSize)
+	rows, err := shard.DB.QueryContext(ctx, query.SQL(), query.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +67,7 @@ func getDomainsAndNameservers(db *sql.DB, lastDomainID *int, batchSize int) ([]D
 
 	var domains []DomainInfo
 	for rows.Next() {
-		var d DomainInfo
+		d := DomainInfo{Shard: shard}
 		if err := rows.Scan(&d.ID, &d.Domain, &d.TLD, &d.Nameservers); err != nil {
 			return nil, fmt.Errorf("failed to scan domain: %v", err)
 		}
@@ -55,6 +76,94 @@ func getDomainsAndNameservers(db *sql.DB, lastDomainID *int, batchSize int) ([]D
 	return domains, rows.Err()
 }
 
+// getCustomerDomains returns up to batchSize domains organizations imported
+// with ImportDomains that have not been queried in 12 hours, least recently
+// queried first. Unlike the sweep they need no known nameservers.
+// customer_domains is on db; each domain is read from the shard of its TLD.
+func getCustomerDomains(ctx context.Context, db *sql.DB, shards *storage.Router, batchSize int) ([]DomainInfo, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT domain_name, MIN(tld)
+		FROM customer_domains
+		GROUP BY domain_name
+		HAVING MAX(last_queried) IS NULL OR MAX(last_queried) < NOW() - INTERVAL '12 hours'
+		ORDER BY MAX(last_queried) NULLS FIRST, domain_name
+		LIMIT $1
+	`, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	due := make(map[*storage.Shard][]string)
+	order := make(map[string]int)
+	for rows.Next() {
+		var domain, tld string
+		if err := rows.Scan(&domain, &tld); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan customer domain: %v", err)
+		}
+		shard := shards.ForTLD(tld)
+		due[shard] = append(due[shard], domain)
+		order[domain] = len(order)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	domains := make([]DomainInfo, len(order))
+	found := make([]bool, len(order))
+	for shard, names := range due {
+		rows, err := shard.DB.QueryContext(ctx, `
+			SELECT id, domain_name, tld, nameservers
+			FROM domains
+			WHERE domain_name = ANY($1)
+		`, pq.Array(names))
+		if err != nil {
+			return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		for rows.Next() {
+			d := DomainInfo{Shard: shard, Customer: true}
+			if err := rows.Scan(&d.ID, &d.Domain, &d.TLD, &d.Nameservers); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan customer domain: %v", err)
+			}
+			domains[order[d.Domain]], found[order[d.Domain]] = d, true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+	}
+	// Domains missing from their shard have nothing to resolve against
+	present := domains[:0]
+	for i, d := range domains {
+		if found[i] {
+			present = append(present, d)
+		}
+	}
+	return present, nil
+}
+
+// markCustomerQueried records that a customer domain was queried, so it is
+// not due again for 12 hours.
+func markCustomerQueried(db *sql.DB, domain string) error {
+	_, err := db.Exec("UPDATE customer_domains SET last_queried = NOW() WHERE domain_name = $1", domain)
+	return err
+}
+
+// getProgress returns the last domain the sweep of a shard finished, or nil
+// to start from the beginning.
+func getProgress(db *sql.DB) (*int, error) {
+	var lastDomainID sql.NullInt32
+	if err := db.QueryRow("SELECT last_domain_id FROM query_progress WHERE id = 1").Scan(&lastDomainID); err != nil {
+		return nil, err
+	}
+	if !lastDomainID.Valid {
+		return nil, nil
+	}
+	id := int(lastDomainID.Int32)
+	return &id, nil
+}
+
 func updateProgress(db *sql.DB, domainID int) error {
 	_, err := db.Exec(`
 		UPDATE query_progress
@@ -64,8 +173,27 @@ func updateProgress(db *sql.DB, domainID int) error {
 	return err
 }
 
-func queryDNSRecords(domain string, domainID int, nameservers []string, recordType uint16, dnsServers []string) ([]map[string]interface{}, error) {
-	client := &dns.Client{Timeout: 10 * time.Second}
+// rewindProgress sets the progress to the last domain before domainID, so the
+// next run starts at domainID. last_domain_id references domains, so it is
+// the highest existing ID below domainID, or NULL to start over.
+func rewindProgress(db *sql.DB, domainID int) error {
+	_, err := db.Exec(`
+		UPDATE query_progress
+		SET last_domain_id = (SELECT MAX(id) FROM domains WHERE id < $1), updated_at = $2
+		WHERE id = 1
+	`, domainID, time.Now().UTC())
+	return err
+}
+
+// queryDNSRecords queries the domain's nameservers (discovering them first
+// through the recursive upstreams if none are known) for one record type.
+// Every exchange, including retries, is charged to budget; once it is
+// exhausted the records found so far are returned with an error wrapping
+// errBudgetExhausted. Nameservers are tried in order of reputation,
+// and each exchange with them updates it. If ctx is cancelled, the records
+// found so far are returned with ctx's error.
+func queryDNSRecords(ctx context.Context, res *resolver.Resolver, budget *crawlBudget, rep *reputation, domain string, domainID int, nameservers []string, recordType uint16) ([]map[string]interface{}, error) {
+	retry := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3), ctx)
 	var records []map[string]interface{}
 
 	// Remove trailing dot from domain
@@ -74,13 +202,19 @@ func queryDNSRecords(domain string, domainID int, nameservers []string, recordTy
 	if len(nameservers) == 0 {
 		m := new(dns.Msg)
 		m.SetQuestion(dns.Fqdn(domain), dns.TypeNS)
-		dnsServer := dnsServers[rand.Intn(len(dnsServers))]
 		var r *dns.Msg
+		var dnsServer string
 		err := backoff.Retry(func() error {
+			if err := budget.spend(ctx); err != nil {
+				return backoff.Permanent(err)
+			}
 			var err error
-			r, _, err = client.Exchange(m, dnsServer)
+			r, dnsServer, err = res.Recursive(ctx, m)
 			return err
-		}, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3))
+		}, retry)
+		if errors.Is(err, errBudgetExhausted) || errors.Is(err, context.Canceled) {
+			return nil, err
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to query NS for %s using %s after retries: %v", domain, dnsServer, err)
 		}
@@ -95,27 +229,40 @@ func queryDNSRecords(domain string, domainID int, nameservers []string, recordTy
 		}
 	}
 
-	for _, ns := range nameservers {
+	for _, ns := range rep.rank(nameservers) {
 		nsAddr := ns + ":53"
 		m := new(dns.Msg)
 		m.SetQuestion(dns.Fqdn(domain), recordType)
 		var r *dns.Msg
 		err := backoff.Retry(func() error {
-			var err error
-			r, _, err = client.Exchange(m, nsAddr)
+			if err := budget.spend(ctx); err != nil {
+				return backoff.Permanent(err)
+			}
+			resp, rtt, cached, err := res.Exchange(ctx, m, nsAddr)
+			// Running out of wall time is not the nameserver's fault
+			if !cached && ctx.Err() == nil {
+				rep.observe(ns, resp, rtt, err)
+			}
+			r = resp
 			return err
-		}, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3))
+		}, retry)
+		if errors.Is(err, errBudgetExhausted) || errors.Is(err, context.Canceled) {
+			return records, err
+		}
 		if err != nil {
-			log.Printf("Error querying %s for %s using %s after retries: %v", dns.TypeToString[recordType], domain, nsAddr, err)
+			logger.Errorf("Error querying %s for %s using %s after retries: %v", dns.TypeToString[recordType], domain, nsAddr, err)
 			continue
 		}
 		for _, ans := range r.Answer {
+			priority, weight := recordset.SortKeys(ans)
 			records = append(records, map[string]interface{}{
 				"domain_id":   domainID,
 				"record_type": dns.TypeToString[recordType],
-				"record_data": ans.String(),
+				"record_data": recordset.NormalizeRR(ans),
 				"ttl":         int(ans.Header().Ttl),
 				"source":      "QUERY",
+				"priority":    priority,
+				"weight":      weight,
 			})
 		}
 		if len(records) > 0 {
@@ -125,56 +272,211 @@ func queryDNSRecords(domain string, domainID int, nameservers []string, recordTy
 	return records, nil
 }
 
-func processDomain(db *sql.DB, domainInfo DomainInfo, dnsServers []string) error {
-	fmt.Printf("Processing domain: %s\n", domainInfo.Domain)
-	for i, rt := range recordTypes {
-		records, err := queryDNSRecords(domainInfo.Domain, domainInfo.ID, domainInfo.Nameservers, rt, dnsServers)
+// recordWriter stores the records of one RRset of a domain, either directly
+// on the domain's shard or through the ingest service.
+type recordWriter func(ctx context.Context, domainInfo DomainInfo, records []map[string]interface{}) error
+
+// ingestWriter returns a recordWriter that sends records to the ingest
+// service, retrying with backoff while its buffer is full.
+func ingestWriter(client pb.IngestServiceClient) recordWriter {
+	return func(ctx context.Context, domainInfo DomainInfo, records []map[string]interface{}) error {
+		req := &pb.WriteRecordsRequest{}
+		for _, r := range records {
+			req.Records = append(req.Records, &pb.IngestRecord{
+				DomainId:   int32(r["domain_id"].(int)),
+				RecordType: r["record_type"].(string),
+				RecordData: r["record_data"].(string),
+				Ttl:        int32(r["ttl"].(int)),
+				Source:     r["source"].(string),
+				ObservedAt: time.Now().UTC().Format(time.RFC3339),
+				Tld:        domainInfo.TLD,
+			})
+		}
+		return backoff.Retry(func() error {
+			_, err := client.WriteRecords(ctx, req)
+			if status.Code(err) == codes.InvalidArgument {
+				return backoff.Permanent(err)
+			}
+			return err
+		}, backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 5), ctx))
+	}
+}
+
+// queryPrefixedRecords resolves recordType under each prefix of a domain
+// (e.g. _sip._tls for SRV, _443._tcp for TLSA). The records of all names are
+// returned together so that they are stored, checksummed and TTL-checked as
+// the domain's one set of that type.
+func queryPrefixedRecords(ctx context.Context, res *resolver.Resolver, budget *crawlBudget, rep *reputation, domainInfo DomainInfo, prefixes []string, recordType uint16) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+	for _, prefix := range prefixes {
+		found, err := queryDNSRecords(ctx, res, budget, rep, strings.Trim(prefix, ".")+"."+domainInfo.Domain, domainInfo.ID, domainInfo.Nameservers, recordType)
+		records = append(records, found...)
 		if err != nil {
-			log.Printf("Error querying %s for %s: %v", dns.TypeToString[rt], domainInfo.Domain, err)
+			return records, err
+		}
+	}
+	return records, nil
+}
+
+// processDomain queries and stores each record type for a domain within
+// its crawl budget, skipping the remaining types once the budget runs out.
+// Types in prefixes are resolved under the listed prefixes instead of at
+// the domain. If ctx is cancelled it stops without storing the record type
+// in progress and returns ctx's error, leaving the domain unfinished. Events
+// go to db, except record_set_changed, which goes with the checksums to the
+// domain's shard.
+func processDomain(ctx context.Context, db *sql.DB, res *resolver.Resolver, domainInfo DomainInfo, write recordWriter, budget *crawlBudget, rep *reputation, prefixes map[uint16][]string, asns recordset.ASNLookup) error {
+	logger.Infof("Processing domain: %s", domainInfo.Domain)
+	crawlCtx, cancel := context.WithDeadline(ctx, budget.deadline)
+	defer cancel()
+	for i, rt := range recordTypes {
+		var records []map[string]interface{}
+		var err error
+		if names, ok := prefixes[rt]; ok {
+			records, err = queryPrefixedRecords(crawlCtx, res, budget, rep, domainInfo, names, rt)
+		} else {
+			records, err = queryDNSRecords(crawlCtx, res, budget, rep, domainInfo.Domain, domainInfo.ID, domainInfo.Nameservers, rt)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		exhausted := errors.Is(err, errBudgetExhausted)
+		if err != nil && !exhausted {
+			logger.Errorf("Error querying %s for %s: %v", dns.TypeToString[rt], domainInfo.Domain, err)
 			continue
 		}
 		if len(records) > 0 {
-			if err := storeRecords(db, records); err != nil {
-				log.Printf("Error storing records for %s: %v", domainInfo.Domain, err)
+			if err := write(ctx, domainInfo, records); err != nil {
+				logger.Errorf("Error storing records for %s: %v", domainInfo.Domain, err)
+				events.Publish(db, events.Event{Source: events.SourceQuery, Kind: events.Error, TLD: domainInfo.TLD, Domain: domainInfo.Domain,
+					Message: fmt.Sprintf("storing %s records: %v", dns.TypeToString[rt], err)})
 			} else {
-				fmt.Printf("Stored %d %s records for %s\n", len(records), dns.TypeToString[rt], domainInfo.Domain)
+				logger.Infof("Stored %d %s records for %s", len(records), dns.TypeToString[rt], domainInfo.Domain)
+				version, significance, err := storeChecksum(ctx, domainInfo.Shard.DB, records, asns)
+				if err != nil {
+					logger.Errorf("Error storing %s record set checksum for %s: %v", dns.TypeToString[rt], domainInfo.Domain, err)
+				} else if significance != "" {
+					events.Publish(domainInfo.Shard.DB, events.Event{Source: events.SourceQuery, Kind: events.RecordSetChanged, TLD: domainInfo.TLD, Domain: domainInfo.Domain,
+						RecordType: dns.TypeToString[rt], Version: version, Significance: significance})
+				}
 			}
 		}
+		if exhausted {
+			logger.Warnf("Skipping remaining record types for %s after %s: %v", domainInfo.Domain, dns.TypeToString[rt], err)
+			break
+		}
 		// Add 5-second delay between record types, except for the last one
 		if i < len(recordTypes)-1 {
-			time.Sleep(5 * time.Second)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-crawlCtx.Done():
+			}
 		}
 	}
-	// Update progress
-	if err := updateProgress(db, domainInfo.ID); err != nil {
-		log.Printf("Error updating progress for domain %s: %v", domainInfo.Domain, err)
-	}
 	return nil
 }
 
-func storeRecords(db *sql.DB, records []map[string]interface{}) error {
-	tx, err := db.Begin()
+// storeChecksum records the checksum of a resolved record set and returns
+// the set's version and the significance of its change (see
+// recordset.Classify), which is empty if neither the set nor its TTL changed.
+// Changes of sets seen before are stored in record_set_changes; asns, if not
+// nil, tells address changes that moved to another AS.
+func storeChecksum(ctx context.Context, db *sql.DB, records []map[string]interface{}, asns recordset.ASNLookup) (version int64, significance string, err error) {
+	data := make([]string, len(records))
+	ttl := 0
+	for i, r := range records {
+		data[i] = r["record_data"].(string)
+		if t := r["ttl"].(int); i == 0 || t < ttl {
+			ttl = t
+		}
+	}
+	set := recordset.Set(data)
+	domainID, recordType := records[0]["domain_id"], records[0]["record_type"].(string)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	defer tx.Rollback()
+	var old []string
+	var oldTTL sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT records, ttl FROM record_set_checksums
+		WHERE domain_id = $1 AND record_type = $2 AND source = 'QUERY'
+		FOR UPDATE
+	`, domainID, recordType).Scan(pq.Array(&old), &oldTTL)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, "", err
+	}
+	var changed, inserted bool
+	if err := tx.QueryRowContext(ctx, recordset.UpsertChecksum, domainID, recordType, "QUERY",
+		recordset.Checksum(data), len(set), time.Now().UTC()).Scan(&version, &changed, &inserted); err != nil {
+		return 0, "", err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE record_set_checksums SET records = $1, ttl = $2
+		WHERE domain_id = $3 AND record_type = $4 AND source = 'QUERY'
+	`, pq.Array(set), ttl, domainID, recordType); err != nil {
+		return 0, "", err
+	}
+
+	switch {
+	case inserted:
+		significance = recordset.SignificanceNew
+	case changed && old == nil:
+		significance = recordset.SignificanceUnclassified
+	case changed:
+		significance = recordset.Classify(recordType, old, set, asns)
+	case oldTTL.Valid && int(oldTTL.Int64) != ttl:
+		significance = recordset.SignificanceCosmetic
+	}
+	// First observations are not changes worth a history entry
+	if significance != "" && significance != recordset.SignificanceNew {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO record_set_changes (domain_id, record_type, version, significance, old_records, records, old_ttl, ttl)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, domainID, recordType, version, significance, pq.Array(old), pq.Array(set), oldTTL, ttl); err != nil {
+			return 0, "", err
+		}
+	}
+	return version, significance, tx.Commit()
+}
+
+// storeRecords stores a resolved RRset in one transaction, which is rolled
+// back if ctx is cancelled. TXT data of at least compressMinBytes is stored
+// compressed (see storage.EncodeRecordData).
+func storeRecords(ctx context.Context, db *sql.DB, records []map[string]interface{}, ttlAnomalyRatio float64, compressMinBytes int) error {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare(`
-		INSERT INTO dns_records (domain_id, record_type, record_data, ttl, source, last_updated)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT DO NOTHING
-	`)
+	if err := recordTTLAnomaly(ctx, tx, records, ttlAnomalyRatio); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO dns_records (domain_id, record_type, record_data, record_data_z, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8, $9, $10)
+	`+recordset.OnRecordConflict)
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 	defer stmt.Close()
 
 	for _, r := range records {
-		_, err := stmt.Exec(
+		data, z := storage.EncodeRecordData(r["record_type"].(string), r["record_data"].(string), compressMinBytes)
+		_, err := stmt.ExecContext(ctx,
 			r["domain_id"],
 			r["record_type"],
-			r["record_data"],
+			data,
+			z,
+			recordset.CanonicalHash(r["record_data"].(string)),
 			r["ttl"],
 			r["source"],
 			time.Now().UTC(),
+			r["priority"],
+			r["weight"],
 		)
 		if err != nil {
 			tx.Rollback()
@@ -182,7 +484,7 @@ func storeRecords(db *sql.DB, records []map[string]interface{}) error {
 		}
 	}
 	// Update domains.last_updated
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		UPDATE domains
 		SET last_updated = $1
 		WHERE id = $2
@@ -194,6 +496,42 @@ func storeRecords(db *sql.DB, records []map[string]interface{}) error {
 	return tx.Commit()
 }
 
+// recordTTLAnomaly compares the TTL of a freshly queried RRset against the most
+// recent stored observation from the same source and records changes by at
+// least ratio in either direction.
+func recordTTLAnomaly(ctx context.Context, tx *sql.Tx, records []map[string]interface{}, ratio float64) error {
+	domainID, recordType, source := records[0]["domain_id"], records[0]["record_type"], records[0]["source"]
+	newTTL := records[0]["ttl"].(int)
+	var oldTTL sql.NullInt64
+	err := tx.QueryRowContext(ctx, `
+		SELECT ttl FROM dns_records
+		WHERE domain_id = $1 AND record_type = $2 AND source = $3
+		ORDER BY last_updated DESC
+		LIMIT 1
+	`, domainID, recordType, source).Scan(&oldTTL)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !oldTTL.Valid {
+		return nil
+	}
+	lo, hi := float64(oldTTL.Int64), float64(newTTL)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if lo <= 0 || hi/lo < ratio {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ttl_anomalies (domain_id, record_type, old_ttl, new_ttl, observed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, domainID, recordType, oldTTL.Int64, newTTL, time.Now().UTC())
+	return err
+}
+
 func main() {
 	configFile := flag.String("config", "config.yaml", "Path to configuration file")
 	flag.Parse()
@@ -204,7 +542,10 @@ func main() {
 	// Load configuration
 	config, err := config.LoadConfig(*configFile)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
 	}
 
 	// Connect to AlloyDB
@@ -214,60 +555,298 @@ func main() {
 	)
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
 	}
 	defer db.Close()
 
 	if err := db.Ping(); err != nil {
-		log.Fatal("Failed to connect to AlloyDB: ", err)
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
 	}
-	fmt.Println("Connected to AlloyDB successfully.")
+	logger.Infof("Connected to AlloyDB successfully")
 
-	// Get last processed domain_id
-	var lastDomainID sql.NullInt32
-	err = db.QueryRow("SELECT last_domain_id FROM query_progress WHERE id = 1").Scan(&lastDomainID)
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	write := recordWriter(func(ctx context.Context, domainInfo DomainInfo, records []map[string]interface{}) error {
+		return storeRecords(ctx, domainInfo.Shard.DB, records, config.DNSQuery.TTLAnomalyRatio, config.RecordData.CompressMinBytes)
+	})
+	if config.DNSQuery.IngestAddress != "" {
+		opts, err := ingest.DialOptions(config)
+		if err != nil {
+			logger.Fatalf("Failed to configure ingest service connection: %v", err)
+		}
+		conn, err := grpc.Dial(config.DNSQuery.IngestAddress, opts...)
+		if err != nil {
+			logger.Fatalf("Failed to connect to ingest service at %s: %v", config.DNSQuery.IngestAddress, err)
+		}
+		defer conn.Close()
+		write = ingestWriter(pb.NewIngestServiceClient(conn))
+		logger.Infof("Writing records through ingest service at %s", config.DNSQuery.IngestAddress)
+	}
+
+	// All outbound DNS goes through the shared resolver
+	res, err := resolver.New(config)
 	if err != nil {
-		log.Fatal("Failed to get last domain ID: ", err)
+		logger.Fatalf("Failed to create resolver: %v", err)
 	}
-	var lastDomainIDPtr *int
-	if lastDomainID.Valid {
-		lastDomainIDVal := int(lastDomainID.Int32)
-		lastDomainIDPtr = &lastDomainIDVal
+	res.WatchServers(context.Background(), db, time.Duration(config.Resolver.ServersRefreshSeconds)*time.Second)
+	res.StartHealthChecks(context.Background(), time.Duration(config.Resolver.HealthCheckSeconds)*time.Second)
+	if config.Resolver.MetricsAddress != "" {
+		go func() {
+			logger.Infof("Serving resolver metrics at http://%s/debug/vars", config.Resolver.MetricsAddress)
+			if err := http.ListenAndServe(config.Resolver.MetricsAddress, nil); err != nil {
+				logger.Errorf("Resolver metrics server failed: %v", err)
+			}
+		}()
 	}
+	defer func() {
+		s := res.Stats()
+		logger.Infof("Resolver: %d queries, %d cache hits, %d errors (%d timeouts), %d TCP fallbacks, %.1fms average latency",
+			s.Queries, s.CacheHits, s.Errors, s.Timeouts, s.TCPFallbacks, s.AvgLatencyMs)
+	}()
 
-	// Process domains in batches
-	batchSize := config.DNSQuery.BatchSize
-	for {
-		domains, err := getDomainsAndNameservers(db, lastDomainIDPtr, batchSize)
+	// Load nameserver reputation and flush it periodically and at exit
+	rep, err := loadReputation(db, config.DNSQuery.NSSkipAfterTimeouts, time.Duration(config.DNSQuery.NSSkipMinutes)*time.Minute)
+	if err != nil {
+		logger.Fatalf("Failed to load nameserver reputation: %v", err)
+	}
+	go func() {
+		for range time.Tick(time.Minute) {
+			if err := rep.flush(db); err != nil {
+				logger.Errorf("Error flushing nameserver reputation: %v", err)
+			}
+		}
+	}()
+	defer func() {
+		if err := rep.flush(db); err != nil {
+			logger.Errorf("Error flushing nameserver reputation: %v", err)
+		}
+	}()
+
+	// Address changes are classified by AS when an ASN table is configured
+	var asns recordset.ASNLookup
+	if config.Analytics.ASNDatabase != "" {
+		table, err := asndb.Load(config.Analytics.ASNDatabase)
 		if err != nil {
-			log.Fatal("Failed to fetch domains: ", err)
+			logger.Fatalf("%v", err)
 		}
-		if len(domains) == 0 {
-			fmt.Println("No more domains to process.")
-			break
+		asns = func(ip net.IP) (string, bool) {
+			asn, _, ok := table.Lookup(ip)
+			return asn, ok
+		}
+	}
+
+	// Process domains in batches, interleaved across TLDs within crawl budgets.
+	// Domains deferred because their TLD ran out of budget are retried first
+	// in the next batch.
+	batchSize := config.DNSQuery.BatchSize
+	sched := newScheduler(
+		config.DNSQuery.DomainMaxQueries, time.Duration(config.DNSQuery.DomainMaxSeconds)*time.Second,
+		config.DNSQuery.TLDMaxQueries, time.Duration(config.DNSQuery.TLDMaxSeconds)*time.Second,
+	)
+	prefixes := map[uint16][]string{
+		dns.TypeSRV:  config.DNSQuery.ServiceNames,
+		dns.TypeTLSA: config.DNSQuery.TLSANames,
+	}
+	// Stop on shutdown; domains left unfinished are queried again after restart
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	leases := lease.NewManager(shards, config)
+
+	// Sweep each shard in turn from its own progress cursor. Deferred and
+	// customer domains carry their shard and may be from any of them.
+	var deferred []DomainInfo
+sweep:
+	for _, shard := range shards.Shards() {
+		lastDomainIDPtr, err := getProgress(shard.DB)
+		if err != nil {
+			logger.Fatalf("Failed to get last domain ID on shard %s: %v", shard.Name, err)
 		}
+		for ctx.Err() == nil {
+			// Domains organizations track go ahead of the sweep
+			customer, err := getCustomerDomains(ctx, db, shards, batchSize)
+			if ctx.Err() != nil {
+				break sweep
+			}
+			if err != nil {
+				logger.Fatalf("Failed to fetch customer domains: %v", err)
+			}
+			domains, err := getDomainsAndNameservers(ctx, shard, lastDomainIDPtr, batchSize)
+			if ctx.Err() != nil {
+				break sweep
+			}
+			if err != nil {
+				logger.Fatalf("Failed to fetch domains on shard %s: %v", shard.Name, err)
+			}
+			if len(customer) == 0 && len(domains) == 0 && len(deferred) == 0 {
+				logger.Infof("No more domains to process on shard %s", shard.Name)
+				break
+			}
+			if len(domains) > 0 {
+				lastDomainIDPtr = &domains[len(domains)-1].ID
+			}
+			domains = sched.order(uniqueDomains(append(append(deferred, customer...), domains...)))
+			deferred = nil
+
+			// Lease the swept domains of the batch so the server re-queues them
+			// if this worker dies; the batch context is cancelled on shutdown or
+			// if the lease is lost. Customer domains need no lease, as they stay
+			// due until queried.
+			var held *lease.Lease
+			batchCtx := ctx
+			swept := sweptDomains(domains, shard)
+			var first, last int
+			if len(swept) > 0 {
+				first, last = domainIDRange(swept)
+				held, batchCtx, err = leases.Acquire(ctx, lease.KindDomainBatch, lease.DomainBatch(shard.Name, first, last))
+				if ctx.Err() != nil {
+					break sweep
+				}
+				if errors.Is(err, lease.ErrHeld) {
+					logger.Infof("Stopping: %v", err)
+					break sweep
+				}
+				if err != nil {
+					logger.Fatalf("Failed to lease domain batch: %v", err)
+				}
+			}
+			// unfinished holds domains stopped or never started because of shutdown
+			var unfinished []DomainInfo
+			var mu sync.Mutex
+
+			var wg sync.WaitGroup
+			sem := make(chan struct{}, config.DNSQuery.MaxConcurrent)
 
-		var wg sync.WaitGroup
-		sem := make(chan struct{}, config.DNSQuery.MaxConcurrent)
+			for _, d := range domains {
+				wg.Add(1)
+				go func(domainInfo DomainInfo) {
+					defer wg.Done()
+					defer func() {
+						if r := recover(); r != nil {
+							logger.Errorf("Recovered from panic while processing %s: %v", domainInfo.Domain, r)
+						}
+					}()
+					select {
+					case sem <- struct{}{}:
+					case <-batchCtx.Done():
+						mu.Lock()
+						unfinished = append(unfinished, domainInfo)
+						mu.Unlock()
+						return
+					}
+					defer func() { <-sem }()
+					budget, done, ok := sched.admit(domainInfo)
+					if !ok {
+						mu.Lock()
+						deferred = append(deferred, domainInfo)
+						mu.Unlock()
+						return
+					}
+					defer done()
+					if err := processDomain(batchCtx, db, res, domainInfo, write, budget, rep, prefixes, asns); err != nil {
+						mu.Lock()
+						unfinished = append(unfinished, domainInfo)
+						mu.Unlock()
+						return
+					}
+					if domainInfo.Customer {
+						if err := markCustomerQueried(db, domainInfo.Domain); err != nil {
+							logger.Errorf("Error marking customer domain %s queried: %v", domainInfo.Domain, err)
+						}
+					}
+				}(d)
+			}
+			wg.Wait()
+			if held != nil {
+				held.Release(batchCtx.Err())
+			}
+			batch := events.Event{Source: events.SourceQuery, Kind: events.BatchCommitted, Count: int64(len(domains) - len(deferred) - len(unfinished))}
+			if len(deferred) > 0 {
+				batch.Message = fmt.Sprintf("%d domains deferred by crawl budget", len(deferred))
+			}
+			events.Publish(db, batch)
 
-		for _, d := range domains {
-			wg.Add(1)
-			go func(domainInfo DomainInfo) {
-				defer wg.Done()
-				defer func() {
-					if r := recover(); r != nil {
-						log.Printf("Recovered from panic while processing %s: %v", domainInfo.Domain, r)
+			if batchCtx.Err() != nil {
+				if ctx.Err() == nil {
+					logger.Warnf("Stopping: lost the lease on domains %d-%d of shard %s", first, last, shard.Name)
+				}
+				// Checkpoint below every swept domain this run has not finished, so
+				// none is skipped after restart
+				pending := sweptDomains(append(unfinished, deferred...), shard)
+				switch {
+				case len(pending) > 0:
+					first, _ := domainIDRange(pending)
+					if err := rewindProgress(shard.DB, first); err != nil {
+						logger.Errorf("Error saving progress at shutdown: %v", err)
+					}
+					logger.Warnf("Interrupted; the next run resumes at domain %d of shard %s with %d domains unfinished", first, shard.Name, len(pending))
+				case lastDomainIDPtr != nil:
+					if err := updateProgress(shard.DB, *lastDomainIDPtr); err != nil {
+						logger.Errorf("Error saving progress at shutdown: %v", err)
 					}
-				}()
-				sem <- struct{}{}
-				defer func() { <-sem }()
-				if err := processDomain(db, domainInfo, config.DNSQuery.DNSServers); err != nil {
-					log.Printf("Error processing domain %s: %v", domainInfo.Domain, err)
+					logger.Warnf("Interrupted; saved progress at domain %d of shard %s", *lastDomainIDPtr, shard.Name)
 				}
-				// Update lastDomainIDPtr for the next batch
-				lastDomainIDPtr = &domainInfo.ID
-			}(d)
+				break sweep
+			}
+			if lastDomainIDPtr != nil {
+				if err := updateProgress(shard.DB, *lastDomainIDPtr); err != nil {
+					logger.Errorf("Error updating progress: %v", err)
+				}
+			}
+
+			// Carry at most one batch of deferred domains; the rest stay stale and
+			// are picked up on the next run
+			if len(deferred) > batchSize {
+				logger.Infof("Dropping %d deferred domains over crawl budget until the next run", len(deferred)-batchSize)
+				deferred = deferred[:batchSize]
+			}
+			if len(deferred) > 0 {
+				logger.Infof("Deferred %d domains whose TLD crawl budget was exhausted", len(deferred))
+			}
+		}
+	}
+}
+
+// uniqueDomains drops the repeats of domains, which the customer domains and
+// the sweep can share, keeping the first.
+func uniqueDomains(domains []DomainInfo) []DomainInfo {
+	type key struct {
+		shard string
+		id    int
+	}
+	seen := make(map[key]bool, len(domains))
+	unique := domains[:0]
+	for _, d := range domains {
+		if k := (key{d.Shard.Name, d.ID}); !seen[k] {
+			seen[k] = true
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+// sweptDomains returns the domains of the sweep of shard among domains,
+// leaving out customer domains, which the progress cursor and batch leases
+// do not cover, and domains of other shards.
+func sweptDomains(domains []DomainInfo, shard *storage.Shard) []DomainInfo {
+	var swept []DomainInfo
+	for _, d := range domains {
+		if !d.Customer && d.Shard == shard {
+			swept = append(swept, d)
 		}
-		wg.Wait()
 	}
+	return swept
+}
+
+// domainIDRange returns the lowest and highest ID among domains, which must
+// not be empty.
+func domainIDRange(domains []DomainInfo) (lowest, highest int) {
+	lowest, highest = domains[0].ID, domains[0].ID
+	for _, d := range domains[1:] {
+		lowest, highest = min(lowest, d.ID), max(highest, d.ID)
+	}
+	return lowest, highest
 }
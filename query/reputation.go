@@ -0,0 +1,191 @@
+package query
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// reputationAlpha is the weight of the newest observation in a nameserver's
+// score and average RTT.
+const reputationAlpha = 0.1
+
+// nsStats holds rolling reliability stats for one nameserver host.
+type nsStats struct {
+	score               float64 // Exponentially weighted success rate (0-1)
+	queries             int64
+	timeouts            int64
+	failures            int64
+	consecutiveTimeouts int
+	avgRTTMs            float64
+	lastSuccess         sql.NullTime
+	lastFailure         sql.NullTime
+	skipUntil           sql.NullTime
+	dirty               bool // Changed since the last flush
+}
+
+// reputation tracks nameserver reliability across the worker's queries so
+// that unreliable nameservers are tried last and hosts that keep timing out
+// are skipped for a while. Stats are loaded from and flushed to the
+// nameserver_reputation table.
+type reputation struct {
+	mu        sync.Mutex
+	hosts     map[string]*nsStats
+	skipAfter int           // Consecutive timeouts before a host is skipped
+	skipFor   time.Duration // How long a host is skipped
+}
+
+// loadReputation reads stored nameserver stats.
+func loadReputation(db *sql.DB, skipAfter int, skipFor time.Duration) (*reputation, error) {
+	rows, err := db.Query(`
+		SELECT host, score, queries, timeouts, failures, consecutive_timeouts, avg_rtt_ms, last_success, last_failure, skip_until
+		FROM nameserver_reputation
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := &reputation{hosts: make(map[string]*nsStats), skipAfter: skipAfter, skipFor: skipFor}
+	for rows.Next() {
+		var host string
+		var s nsStats
+		if err := rows.Scan(&host, &s.score, &s.queries, &s.timeouts, &s.failures, &s.consecutiveTimeouts,
+			&s.avgRTTMs, &s.lastSuccess, &s.lastFailure, &s.skipUntil); err != nil {
+			return nil, err
+		}
+		r.hosts[host] = &s
+	}
+	return r, rows.Err()
+}
+
+// observe records the outcome of one exchange with host.
+func (r *reputation) observe(host string, resp *dns.Msg, rtt time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.hosts[host]
+	if !ok {
+		s = &nsStats{score: 1}
+		r.hosts[host] = s
+	}
+	now := time.Now().UTC()
+	s.queries++
+	s.dirty = true
+
+	var netErr net.Error
+	timedOut := errors.As(err, &netErr) && netErr.Timeout()
+	failed := err != nil || resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused
+	if !failed {
+		s.score += reputationAlpha * (1 - s.score)
+		s.avgRTTMs += reputationAlpha * (float64(rtt.Milliseconds()) - s.avgRTTMs)
+		s.consecutiveTimeouts = 0
+		s.lastSuccess = sql.NullTime{Time: now, Valid: true}
+		return
+	}
+	s.score -= reputationAlpha * s.score
+	s.lastFailure = sql.NullTime{Time: now, Valid: true}
+	if !timedOut {
+		s.failures++
+		return
+	}
+	s.timeouts++
+	s.consecutiveTimeouts++
+	if s.consecutiveTimeouts >= r.skipAfter {
+		s.skipUntil = sql.NullTime{Time: now.Add(r.skipFor), Valid: true}
+		s.consecutiveTimeouts = 0
+	}
+}
+
+// rank returns nameservers ordered most reliable first, without hosts that
+// are currently skipped. If every host is skipped, the most reliable one is
+// still returned so the domain is not left unqueried.
+func (r *reputation) rank(nameservers []string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	score := func(host string) float64 {
+		if s, ok := r.hosts[host]; ok {
+			return s.score
+		}
+		return 1
+	}
+	ordered := append([]string(nil), nameservers...)
+	sort.SliceStable(ordered, func(i, j int) bool { return score(ordered[i]) > score(ordered[j]) })
+
+	now := time.Now()
+	var ranked []string
+	for _, host := range ordered {
+		if s, ok := r.hosts[host]; ok && s.skipUntil.Valid && now.Before(s.skipUntil.Time) {
+			continue
+		}
+		ranked = append(ranked, host)
+	}
+	if len(ranked) == 0 && len(ordered) > 0 {
+		ranked = ordered[:1]
+	}
+	return ranked
+}
+
+// flush writes stats changed since the last flush. On failure the stats
+// stay marked for the next flush.
+func (r *reputation) flush(db *sql.DB) (err error) {
+	r.mu.Lock()
+	type row struct {
+		host string
+		s    nsStats
+	}
+	var rows []row
+	for host, s := range r.hosts {
+		if s.dirty {
+			rows = append(rows, row{host, *s})
+			s.dirty = false
+		}
+	}
+	r.mu.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			r.mu.Lock()
+			for _, row := range rows {
+				r.hosts[row.host].dirty = true
+			}
+			r.mu.Unlock()
+		}
+	}()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO nameserver_reputation
+			(host, score, queries, timeouts, failures, consecutive_timeouts, avg_rtt_ms, last_success, last_failure, skip_until, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (host) DO UPDATE
+		SET score = EXCLUDED.score, queries = EXCLUDED.queries, timeouts = EXCLUDED.timeouts, failures = EXCLUDED.failures,
+		    consecutive_timeouts = EXCLUDED.consecutive_timeouts, avg_rtt_ms = EXCLUDED.avg_rtt_ms,
+		    last_success = EXCLUDED.last_success, last_failure = EXCLUDED.last_failure,
+		    skip_until = EXCLUDED.skip_until, updated_at = EXCLUDED.updated_at
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	for _, row := range rows {
+		s := row.s
+		if _, err := stmt.Exec(row.host, s.score, s.queries, s.timeouts, s.failures, s.consecutiveTimeouts,
+			s.avgRTTMs, s.lastSuccess, s.lastFailure, s.skipUntil, now); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,217 @@
+// Package recordset computes checksums of DNS record sets so that the same
+// set seen in a zone file, stored in the database and resolved live can be
+// compared cheaply.
+//
+// Records are stored in normalized form (see Normalize) and compared in
+// canonical form, which also ignores TTLs, so a set only differs when its
+// data does. The package also extracts the fields records are ordered by.
+package recordset
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// maxTXTString is the longest character-string a TXT record can hold.
+const maxTXTString = 255
+
+// Normalize returns record data (an RR in zone file format) in the form
+// stored in dns_records.record_data, whatever its source: owner and target
+// names lowercased and fully qualified, escaping as produced by the DNS
+// library, and TXT strings rechunked (see NormalizeRR). Data that does not
+// parse is returned trimmed and unchanged.
+func Normalize(data string) string {
+	rr, err := dns.NewRR(data)
+	if err != nil || rr == nil {
+		return strings.TrimSpace(data)
+	}
+	return NormalizeRR(rr)
+}
+
+// NormalizeRR returns rr in the form stored in dns_records.record_data.
+// The strings of TXT and SPF records are joined and split again at 255
+// bytes, since sources differ in how they chunk long values such as DKIM
+// keys and consumers read them concatenated. rr is modified.
+func NormalizeRR(rr dns.RR) string {
+	return normalize(rr).String()
+}
+
+// normalize lowercases rr's names and rechunks its TXT strings.
+func normalize(rr dns.RR) dns.RR {
+	rr.Header().Name = dns.CanonicalName(rr.Header().Name)
+	switch r := rr.(type) {
+	case *dns.NS:
+		r.Ns = dns.CanonicalName(r.Ns)
+	case *dns.CNAME:
+		r.Target = dns.CanonicalName(r.Target)
+	case *dns.DNAME:
+		r.Target = dns.CanonicalName(r.Target)
+	case *dns.MX:
+		r.Mx = dns.CanonicalName(r.Mx)
+	case *dns.PTR:
+		r.Ptr = dns.CanonicalName(r.Ptr)
+	case *dns.SRV:
+		r.Target = dns.CanonicalName(r.Target)
+	case *dns.NAPTR:
+		r.Replacement = dns.CanonicalName(r.Replacement)
+	case *dns.SOA:
+		r.Ns, r.Mbox = dns.CanonicalName(r.Ns), dns.CanonicalName(r.Mbox)
+	case *dns.TXT, *dns.SPF:
+		if rechunked, err := rechunkTXT(r); err == nil {
+			return rechunked
+		}
+	}
+	return rr
+}
+
+// rechunkTXT joins the character-strings of a TXT or SPF record and splits
+// them again into strings of at most maxTXTString bytes. It works on the
+// wire form so escaped bytes count once and are never split.
+func rechunkTXT(rr dns.RR) (dns.RR, error) {
+	// With the root as owner the header is always 11 bytes on the wire
+	c := dns.Copy(rr)
+	c.Header().Name = "."
+	buf := make([]byte, dns.Len(c))
+	off, err := dns.PackRR(c, buf, 0, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	var joined []byte
+	for rdata := buf[11:off]; len(rdata) > 0; {
+		n := int(rdata[0])
+		if n+1 > len(rdata) {
+			return nil, fmt.Errorf("truncated character-string")
+		}
+		joined = append(joined, rdata[1:n+1]...)
+		rdata = rdata[n+1:]
+	}
+	var rdata []byte
+	for {
+		n := min(len(joined), maxTXTString)
+		rdata = append(append(rdata, byte(n)), joined[:n]...)
+		joined = joined[n:]
+		if len(joined) == 0 {
+			break
+		}
+	}
+	header := *rr.Header()
+	header.Rdlength = uint16(len(rdata))
+	rechunked, _, err := dns.UnpackRRWithHeader(header, rdata, 0)
+	return rechunked, err
+}
+
+// Canonical returns the canonical presentation of record data as stored in
+// dns_records.record_data (an RR in zone file format): its normalized form
+// (see Normalize) without the TTL. Data that does not parse is returned
+// trimmed and unchanged.
+func Canonical(data string) string {
+	rr, err := dns.NewRR(data)
+	if err != nil || rr == nil {
+		return strings.TrimSpace(data)
+	}
+	rr = normalize(rr)
+	rr.Header().Ttl = 0
+	return rr.String()
+}
+
+// CanonicalHash returns the SHA-256 of the canonical form of record data,
+// stored in dns_records.canonical_hash as part of the record's natural key.
+func CanonicalHash(data string) []byte {
+	sum := sha256.Sum256([]byte(Canonical(data)))
+	return sum[:]
+}
+
+// OnRecordConflict is the conflict clause of dns_records inserts, which
+// must set canonical_hash and first_seen, and record_data_z if the data is
+// stored compressed. A record already stored from the
+// same source is updated instead of added again: its observations are
+// counted, a later observation replaces its data, TTL and last_updated, and
+// an earlier one, such as from a passive DNS import, moves first_seen back.
+// A trigger keeps the data and TTL it replaces in record_history.
+const OnRecordConflict = `
+	ON CONFLICT (domain_id, record_type, source, canonical_hash) DO UPDATE
+	SET record_data = CASE WHEN EXCLUDED.last_updated >= dns_records.last_updated
+			THEN EXCLUDED.record_data ELSE dns_records.record_data END,
+		record_data_z = CASE WHEN EXCLUDED.last_updated >= dns_records.last_updated
+			THEN EXCLUDED.record_data_z ELSE dns_records.record_data_z END,
+		ttl = CASE WHEN EXCLUDED.last_updated >= dns_records.last_updated
+			THEN EXCLUDED.ttl ELSE dns_records.ttl END,
+		last_updated = GREATEST(dns_records.last_updated, EXCLUDED.last_updated),
+		first_seen = LEAST(dns_records.first_seen, EXCLUDED.first_seen),
+		observations = dns_records.observations + EXCLUDED.observations
+`
+
+// Set returns the distinct canonical records in data, sorted.
+func Set(data []string) []string {
+	seen := make(map[string]bool, len(data))
+	var set []string
+	for _, d := range data {
+		c := Canonical(d)
+		if !seen[c] {
+			seen[c] = true
+			set = append(set, c)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
+
+// Checksum returns the hex SHA-256 of the canonical set of data. The empty
+// set has an empty checksum.
+func Checksum(data []string) string {
+	set := Set(data)
+	if len(set) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(set, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// UpsertChecksum stores a record set's checksum in record_set_checksums,
+// with the parameters domain_id, record_type, source, checksum, record_count
+// and computed_at. A set keeps its version while its checksum is unchanged
+// and takes the next value of record_set_version_seq when it changes or
+// returns after being dropped from its zone, so a set's versions only
+// increase. It returns the set's version, whether the set is new or
+// changed, and whether it is new, stored for the first time.
+const UpsertChecksum = `
+	INSERT INTO record_set_checksums (domain_id, record_type, source, checksum, record_count, computed_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (domain_id, record_type, source) DO UPDATE
+	SET checksum = EXCLUDED.checksum, record_count = EXCLUDED.record_count, computed_at = EXCLUDED.computed_at, removed_at = NULL,
+		version = CASE WHEN record_set_checksums.checksum = EXCLUDED.checksum AND record_set_checksums.removed_at IS NULL
+			THEN record_set_checksums.version ELSE EXCLUDED.version END
+	RETURNING version, version = currval('record_set_version_seq'), xmax = 0
+`
+
+// SortKeys returns the fields an RR is ordered by, for the priority and
+// weight columns of dns_records: the MX preference, the SRV priority and
+// weight, or the NAPTR order. Other types have neither.
+func SortKeys(rr dns.RR) (priority, weight sql.NullInt32) {
+	switch r := rr.(type) {
+	case *dns.MX:
+		priority = sql.NullInt32{Int32: int32(r.Preference), Valid: true}
+	case *dns.SRV:
+		priority = sql.NullInt32{Int32: int32(r.Priority), Valid: true}
+		weight = sql.NullInt32{Int32: int32(r.Weight), Valid: true}
+	case *dns.NAPTR:
+		priority = sql.NullInt32{Int32: int32(r.Order), Valid: true}
+	}
+	return priority, weight
+}
+
+// ParseSortKeys is SortKeys for record data in zone file format. Data that
+// does not parse has neither field.
+func ParseSortKeys(data string) (priority, weight sql.NullInt32) {
+	rr, err := dns.NewRR(data)
+	if err != nil || rr == nil {
+		return priority, weight
+	}
+	return SortKeys(rr)
+}
@@ -0,0 +1,66 @@
+package recordset
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Reverse zone suffixes for IPv4 and IPv6.
+const (
+	reverseV4 = "in-addr.arpa"
+	reverseV6 = "ip6.arpa"
+)
+
+// IsReverseZone reports whether zone is, or is under, in-addr.arpa or
+// ip6.arpa.
+func IsReverseZone(zone string) bool {
+	zone = strings.ToLower(strings.Trim(zone, "."))
+	return zone == reverseV4 || zone == reverseV6 ||
+		strings.HasSuffix(zone, "."+reverseV4) || strings.HasSuffix(zone, "."+reverseV6)
+}
+
+// ReverseAddr returns the address a full reverse name stands for, such as
+// 4.3.2.1.in-addr.arpa for 1.2.3.4 or 32 nibbles under ip6.arpa for an IPv6
+// address. It reports false for names that are not a complete address,
+// including classless (RFC 2317) delegation names.
+func ReverseAddr(name string) (netip.Addr, bool) {
+	name = strings.ToLower(strings.Trim(name, "."))
+	switch {
+	case strings.HasSuffix(name, "."+reverseV4):
+		labels := strings.Split(strings.TrimSuffix(name, "."+reverseV4), ".")
+		if len(labels) != 4 {
+			return netip.Addr{}, false
+		}
+		var ip [4]byte
+		for i, label := range labels {
+			n, err := strconv.ParseUint(label, 10, 8)
+			if err != nil || (len(label) > 1 && label[0] == '0') {
+				return netip.Addr{}, false
+			}
+			ip[3-i] = byte(n)
+		}
+		return netip.AddrFrom4(ip), true
+	case strings.HasSuffix(name, "."+reverseV6):
+		labels := strings.Split(strings.TrimSuffix(name, "."+reverseV6), ".")
+		if len(labels) != 32 {
+			return netip.Addr{}, false
+		}
+		var ip [16]byte
+		for i, label := range labels {
+			n, err := strconv.ParseUint(label, 16, 4)
+			if err != nil || len(label) != 1 {
+				return netip.Addr{}, false
+			}
+			// labels[0] is the low nibble of the last byte
+			pos := 31 - i
+			if pos%2 == 0 {
+				ip[pos/2] |= byte(n) << 4
+			} else {
+				ip[pos/2] |= byte(n)
+			}
+		}
+		return netip.AddrFrom16(ip), true
+	}
+	return netip.Addr{}, false
+}
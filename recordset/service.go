@@ -0,0 +1,81 @@
+package recordset
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Service holds the parsed fields of an SRV or NAPTR record.
+type Service struct {
+	Name       string // Owner name, canonical without the trailing dot (e.g. _sip._tls.example.com)
+	RecordType string // SRV or NAPTR
+	TTL        uint32
+
+	// SRV
+	Service  string // First owner label without its underscore (e.g. sip); empty if not underscored
+	Proto    string // Second owner label without its underscore (e.g. tls); empty if not underscored
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+
+	// NAPTR
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Services    string // e.g. SIPS+D2T
+	Regexp      string
+	Replacement string
+}
+
+// ParseService parses record data in zone file format into a Service. It
+// reports false for data that does not parse or is not SRV or NAPTR.
+func ParseService(data string) (Service, bool) {
+	rr, err := dns.NewRR(data)
+	if err != nil || rr == nil {
+		return Service{}, false
+	}
+	s := Service{
+		Name: strings.TrimSuffix(dns.CanonicalName(rr.Header().Name), "."),
+		TTL:  rr.Header().Ttl,
+	}
+	switch r := rr.(type) {
+	case *dns.SRV:
+		s.RecordType = "SRV"
+		labels := dns.SplitDomainName(s.Name)
+		if len(labels) >= 2 && strings.HasPrefix(labels[0], "_") && strings.HasPrefix(labels[1], "_") {
+			s.Service = labels[0][1:]
+			s.Proto = labels[1][1:]
+		}
+		s.Priority = r.Priority
+		s.Weight = r.Weight
+		s.Port = r.Port
+		s.Target = strings.TrimSuffix(dns.CanonicalName(r.Target), ".")
+	case *dns.NAPTR:
+		s.RecordType = "NAPTR"
+		s.Order = r.Order
+		s.Preference = r.Preference
+		s.Flags = r.Flags
+		s.Services = r.Service
+		s.Regexp = r.Regexp
+		s.Replacement = strings.TrimSuffix(dns.CanonicalName(r.Replacement), ".")
+	default:
+		return Service{}, false
+	}
+	return s, true
+}
+
+// OwnerDomain strips the leading underscore labels of a service name, giving
+// the domain its records are stored under: _sip._tls.example.com becomes
+// example.com. Other names are returned unchanged.
+func OwnerDomain(name string) string {
+	for strings.HasPrefix(name, "_") {
+		i := strings.Index(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,144 @@
+package recordset
+
+import (
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Significance of a record-set change, stored in
+// record_set_changes.significance and carried by record_set_changed events
+// so consumers can tell routine churn from moves worth an alert.
+const (
+	SignificanceNew               = "NEW"                 // First observation of the set
+	SignificanceCosmetic          = "COSMETIC"            // Same records with another TTL
+	SignificanceMinor             = "MINOR"               // Records changed without any of the moves below
+	SignificanceASNChanged        = "ASN_CHANGED"         // A/AAAA: an address is announced by an AS none of the old addresses were
+	SignificanceNSReplaced        = "NS_REPLACED"         // NS: no nameserver in common with the old set
+	SignificanceMXProviderChanged = "MX_PROVIDER_CHANGED" // MX: no mail provider in common with the old set
+	SignificanceUnclassified      = "UNCLASSIFIED"        // Records changed, but the old set was not recorded
+)
+
+// ASNLookup returns the autonomous system announcing ip, or ok=false if it
+// is unknown.
+type ASNLookup func(ip net.IP) (asn string, ok bool)
+
+// Classify returns the significance of a change between two different
+// canonical record sets (see Set) of one record type. Address changes are
+// only ASN_CHANGED with an asn lookup; without one, or when no address of a
+// set has a known AS, they are MINOR.
+func Classify(recordType string, old, current []string, asn ASNLookup) string {
+	switch strings.ToUpper(recordType) {
+	case "NS":
+		return ClassifyNameservers(targets(old), targets(current))
+	case "MX":
+		if disjoint(mxProviders(old), mxProviders(current)) {
+			return SignificanceMXProviderChanged
+		}
+	case "A", "AAAA":
+		if asn == nil {
+			break
+		}
+		known := asns(old, asn)
+		if len(known) == 0 {
+			break
+		}
+		for a := range asns(current, asn) {
+			if !known[a] {
+				return SignificanceASNChanged
+			}
+		}
+	}
+	return SignificanceMinor
+}
+
+// ClassifyNameservers returns the significance of a change between two
+// different sets of nameserver hosts: NS_REPLACED if they share none.
+func ClassifyNameservers(old, current []string) string {
+	hosts := make(map[string]bool, len(old))
+	for _, ns := range old {
+		hosts[dns.CanonicalName(ns)] = true
+	}
+	for _, ns := range current {
+		if hosts[dns.CanonicalName(ns)] {
+			return SignificanceMinor
+		}
+	}
+	if len(old) == 0 || len(current) == 0 {
+		return SignificanceMinor
+	}
+	return SignificanceNSReplaced
+}
+
+// targets returns the hosts NS or MX records point to.
+func targets(set []string) []string {
+	var hosts []string
+	for _, data := range set {
+		switch rr := parse(data).(type) {
+		case *dns.NS:
+			hosts = append(hosts, rr.Ns)
+		case *dns.MX:
+			hosts = append(hosts, rr.Mx)
+		}
+	}
+	return hosts
+}
+
+// mxProviders returns the registrable domains of the mail exchanges of an MX
+// set, or the exchange itself if it has none, as for the null MX (RFC 7505).
+func mxProviders(set []string) map[string]bool {
+	providers := make(map[string]bool)
+	for _, host := range targets(set) {
+		host = strings.TrimSuffix(host, ".")
+		if etld1, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+			host = etld1
+		}
+		providers[host] = true
+	}
+	return providers
+}
+
+// asns returns the autonomous systems announcing the addresses of an A or
+// AAAA set, as far as asn knows them.
+func asns(set []string, asn ASNLookup) map[string]bool {
+	known := make(map[string]bool)
+	for _, data := range set {
+		var ip net.IP
+		switch rr := parse(data).(type) {
+		case *dns.A:
+			ip = rr.A
+		case *dns.AAAA:
+			ip = rr.AAAA
+		default:
+			continue
+		}
+		if a, ok := asn(ip); ok {
+			known[a] = true
+		}
+	}
+	return known
+}
+
+// disjoint reports whether two non-empty sets have no member in common.
+func disjoint(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	for k := range b {
+		if a[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// parse returns the RR of record data, or nil if it does not parse.
+func parse(data string) dns.RR {
+	rr, err := dns.NewRR(data)
+	if err != nil {
+		return nil
+	}
+	return rr
+}
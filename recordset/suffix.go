@@ -0,0 +1,50 @@
+package recordset
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// TLD returns the last label of a domain: uk for example.co.uk.
+func TLD(domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	return domain[strings.LastIndex(domain, ".")+1:]
+}
+
+// PublicSuffix returns the ICANN public suffix of a domain from the Public
+// Suffix List: co.uk for example.co.uk. Suffixes from the list's private
+// section, such as blogspot.com, are registered domains to a registry and
+// are skipped; names under no listed suffix fall back to their TLD.
+func PublicSuffix(domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	suffix, icann := publicsuffix.PublicSuffix(domain)
+	for !icann {
+		i := strings.Index(suffix, ".")
+		if i < 0 {
+			return suffix
+		}
+		suffix, icann = publicsuffix.PublicSuffix(suffix[i+1:])
+	}
+	return suffix
+}
+
+// IsPublicSuffix reports whether name is itself an ICANN public suffix, such
+// as the apex of a zone run by another registry (co.uk in the uk zone)
+// rather than a registered domain.
+func IsPublicSuffix(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	return PublicSuffix(name) == name
+}
+
+// ZoneSuffix returns the public suffix of a domain read from zone: its
+// PublicSuffix, or zone itself if that is longer, as for a zone transferred
+// from a private registry whose apex the list does not know.
+func ZoneSuffix(domain, zone string) string {
+	suffix := PublicSuffix(domain)
+	zone = strings.ToLower(strings.Trim(zone, "."))
+	if strings.HasSuffix(zone, "."+suffix) {
+		return zone
+	}
+	return suffix
+}
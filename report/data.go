@@ -0,0 +1,303 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/storage"
+)
+
+// reportTitles and reportSlugs name each report in documents and file names.
+var (
+	reportTitles = map[string]string{
+		"NEW_DOMAINS":   "New domains matching watchlist",
+		"EMAIL_POSTURE": "Email posture summary",
+		"COVERAGE":      "Data coverage",
+	}
+	reportSlugs = map[string]string{
+		"NEW_DOMAINS":   "new-domains",
+		"EMAIL_POSTURE": "email-posture",
+		"COVERAGE":      "coverage",
+	}
+)
+
+// table is the content of a report: rows under a header, with notes such as
+// truncation or shards left out.
+type table struct {
+	Title   string
+	Period  string
+	Notes   []string
+	Columns []string
+	Rows    [][]string
+}
+
+func newTable(s *schedule, columns ...string) *table {
+	t := &table{Title: reportTitles[s.report], Columns: columns}
+	if len(s.tlds) > 0 {
+		t.Notes = append(t.Notes, "TLDs: "+strings.Join(s.tlds, ", "))
+	}
+	if len(s.watchlist) > 0 {
+		t.Notes = append(t.Notes, "Watchlist: "+strings.Join(s.watchlist, ", "))
+	}
+	return t
+}
+
+// noteSkipped records shards left out of a report because they were
+// unhealthy.
+func (t *table) noteSkipped(skipped []string) {
+	if len(skipped) > 0 {
+		t.Notes = append(t.Notes, "Partial results: shards "+strings.Join(skipped, ", ")+" were unavailable")
+	}
+}
+
+// likePatterns returns LIKE patterns matching names that contain any of the
+// watchlist terms, or that match a term with * or ? as a glob.
+func likePatterns(watchlist []string) []string {
+	escape := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	glob := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`, `?`, `_`)
+	patterns := make([]string, len(watchlist))
+	for i, term := range watchlist {
+		if isGlob(term) {
+			patterns[i] = glob.Replace(term)
+		} else {
+			patterns[i] = "%" + escape.Replace(term) + "%"
+		}
+	}
+	return patterns
+}
+
+// isGlob reports whether a watchlist term is a glob matched against whole
+// domain names rather than a fragment matched anywhere in them.
+func isGlob(term string) bool {
+	return strings.ContainsAny(term, "*?")
+}
+
+// watchlistMatch reports whether a domain name matches a watchlist term.
+func watchlistMatch(term, name string) bool {
+	if isGlob(term) {
+		matched, _ := path.Match(term, name)
+		return matched
+	}
+	return strings.Contains(name, term)
+}
+
+// newDomains lists domains first seen in [start, end) whose names contain a
+// watchlist term and that the schedule's key may read, oldest first.
+func newDomains(ctx context.Context, shards *storage.Router, s *schedule, start, end time.Time, maxRows int) (*table, error) {
+	type newDomain struct {
+		name, tld, nameservers string
+		firstSeen              time.Time
+	}
+	var mu sync.Mutex
+	var found []newDomain
+	skipped, err := shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		q := storage.NewQuery("SELECT d.domain_name, d.tld, d.first_seen, array_to_string(d.nameservers, ' ') FROM domains d").
+			WhereVisible("d", s.orgID).
+			Where("first_seen >= ? AND first_seen < ?", start, end).
+			Where("domain_name LIKE ANY(?)", pq.Array(likePatterns(s.watchlist)))
+		if len(s.tlds) > 0 {
+			q.WhereIn("tld", s.tlds)
+		}
+		q.Append("ORDER BY first_seen, domain_name LIMIT ?", maxRows+1)
+		rows, err := shard.DB.QueryContext(ctx, q.SQL(), q.Args()...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var d newDomain
+			if err := rows.Scan(&d.name, &d.tld, &d.firstSeen, &d.nameservers); err != nil {
+				return err
+			}
+			mu.Lock()
+			found = append(found, d)
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query new domains: %v", err)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].firstSeen.Equal(found[j].firstSeen) {
+			return found[i].firstSeen.Before(found[j].firstSeen)
+		}
+		return found[i].name < found[j].name
+	})
+	t := newTable(s, "Domain", "TLD", "First seen", "Matched", "Nameservers")
+	t.noteSkipped(skipped)
+	if len(found) > maxRows {
+		t.Notes = append(t.Notes, fmt.Sprintf("Truncated to the first %d domains", maxRows))
+		found = found[:maxRows]
+	}
+	for _, d := range found {
+		var matched []string
+		for _, term := range s.watchlist {
+			if watchlistMatch(term, d.name) {
+				matched = append(matched, term)
+			}
+		}
+		t.Rows = append(t.Rows, []string{d.name, d.tld, d.firstSeen.UTC().Format(time.RFC3339), strings.Join(matched, " "), d.nameservers})
+	}
+	return t, nil
+}
+
+// emailPosture summarizes, per TLD, how many of the selected domains accept
+// mail (MX), explicitly refuse it (null MX, RFC 7505) and publish SPF, and
+// how strict their SPF policies are, from the stored apex records of the
+// domains the schedule's key may read.
+func emailPosture(ctx context.Context, shards *storage.Router, s *schedule) (*table, error) {
+	type posture struct {
+		tld                                        string
+		domains, mx, nullMX, spf, spfFail, spfSoft int64
+	}
+	var mu sync.Mutex
+	var tlds []posture
+	skipped, err := shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		q := storage.NewQuery(`
+			SELECT tld, COUNT(*),
+				COUNT(*) FILTER (WHERE mx), COUNT(*) FILTER (WHERE null_mx), COUNT(*) FILTER (WHERE spf),
+				COUNT(*) FILTER (WHERE spf_fail), COUNT(*) FILTER (WHERE spf_soft)
+			FROM (
+				SELECT d.tld,
+					bool_or(r.record_type = 'MX') AS mx,
+					bool_or(r.record_type = 'MX' AND r.record_data ~ '\sMX\s+0\s+\.$') AS null_mx,
+					bool_or(r.record_type = 'TXT' AND r.record_data LIKE '%v=spf1%') AS spf,
+					bool_or(r.record_type = 'TXT' AND r.record_data ~ 'v=spf1.*\s-all') AS spf_fail,
+					bool_or(r.record_type = 'TXT' AND r.record_data ~ 'v=spf1.*\s~all') AS spf_soft
+				FROM domains d
+				LEFT JOIN dns_records r ON r.domain_id = d.id AND r.record_type IN ('MX', 'TXT')
+		`).WhereVisible("d", s.orgID)
+		if len(s.watchlist) > 0 {
+			q.Where("d.domain_name LIKE ANY(?)", pq.Array(likePatterns(s.watchlist)))
+		}
+		if len(s.tlds) > 0 {
+			q.WhereIn("d.tld", s.tlds)
+		}
+		q.Append("GROUP BY d.id, d.tld) posture GROUP BY tld")
+		rows, err := shard.DB.QueryContext(ctx, q.SQL(), q.Args()...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var p posture
+			if err := rows.Scan(&p.tld, &p.domains, &p.mx, &p.nullMX, &p.spf, &p.spfFail, &p.spfSoft); err != nil {
+				return err
+			}
+			mu.Lock()
+			tlds = append(tlds, p)
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email posture: %v", err)
+	}
+
+	sort.Slice(tlds, func(i, j int) bool { return tlds[i].tld < tlds[j].tld })
+	t := newTable(s, "TLD", "Domains", "MX", "Null MX", "SPF", "SPF -all", "SPF ~all", "SPF %")
+	t.noteSkipped(skipped)
+	for _, p := range tlds {
+		t.Rows = append(t.Rows, []string{p.tld, count(p.domains), count(p.mx), count(p.nullMX), count(p.spf),
+			count(p.spfFail), count(p.spfSoft), percent(p.spf, p.domains)})
+	}
+	return t, nil
+}
+
+// coverage reports, per TLD, the last zone ingest and how many of its
+// domains the query worker has resolved.
+func coverage(ctx context.Context, db *sql.DB, shards *storage.Router, s *schedule) (*table, error) {
+	q := storage.NewQuery("SELECT tld, last_processed, COALESCE(last_status, ''), domain_count, record_count FROM processed_tlds")
+	if len(s.tlds) > 0 {
+		q.WhereIn("tld", s.tlds)
+	}
+	q.Append("ORDER BY tld")
+	rows, err := db.QueryContext(ctx, q.SQL(), q.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processed TLDs: %v", err)
+	}
+	defer rows.Close()
+	type tldCoverage struct {
+		tld, status      string
+		lastProcessed    sql.NullTime
+		domains, records int64
+	}
+	var tlds []*tldCoverage
+	for rows.Next() {
+		var c tldCoverage
+		if err := rows.Scan(&c.tld, &c.lastProcessed, &c.status, &c.domains, &c.records); err != nil {
+			return nil, fmt.Errorf("failed to scan processed TLD: %v", err)
+		}
+		tlds = append(tlds, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate processed TLDs: %v", err)
+	}
+
+	var mu sync.Mutex
+	resolved := make(map[string]int64)
+	skipped, err := shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		q := storage.NewQuery(`
+			SELECT d.tld, COUNT(DISTINCT r.domain_id)
+			FROM dns_records r
+			JOIN domains d ON d.id = r.domain_id
+		`).Where("r.source = 'QUERY'")
+		if len(s.tlds) > 0 {
+			q.WhereIn("d.tld", s.tlds)
+		}
+		q.Append("GROUP BY d.tld")
+		rows, err := shard.DB.QueryContext(ctx, q.SQL(), q.Args()...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var tld string
+			var n int64
+			if err := rows.Scan(&tld, &n); err != nil {
+				return err
+			}
+			mu.Lock()
+			resolved[tld] += n
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resolved domains: %v", err)
+	}
+
+	t := newTable(s, "TLD", "Last ingest", "Status", "Domains", "Records", "Resolved", "Resolved %")
+	t.noteSkipped(skipped)
+	for _, c := range tlds {
+		lastProcessed := "never"
+		if c.lastProcessed.Valid {
+			lastProcessed = c.lastProcessed.Time.UTC().Format(time.RFC3339)
+		}
+		t.Rows = append(t.Rows, []string{c.tld, lastProcessed, c.status, count(c.domains), count(c.records),
+			count(resolved[c.tld]), percent(resolved[c.tld], c.domains)})
+	}
+	return t, nil
+}
+
+func count(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func percent(n, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(100*float64(n)/float64(total), 'f', 1, 64)
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moos3/bell/config"
+)
+
+// store writes a report to <dir>/<schedule id>/<name>. Re-running a period
+// replaces its file.
+func store(dir string, scheduleID int, name string, body []byte) error {
+	if dir == "" {
+		return fmt.Errorf("reports.output_dir is not set")
+	}
+	outDir := filepath.Join(dir, strconv.Itoa(scheduleID))
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory %s: %v", outDir, err)
+	}
+	// Write then rename so readers of the bucket never see a partial file
+	path := filepath.Join(outDir, name)
+	if err := os.WriteFile(path+".tmp", body, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	logger.Infof("Stored %s", path)
+	return nil
+}
+
+// email sends a report to to as an attachment, with its text layout (cut
+// to the first rows) as the message body.
+func email(cfg *config.Config, to string, t *table, name, contentType string, body []byte) error {
+	smtpCfg := cfg.Reports.SMTP
+	if smtpCfg.Host == "" {
+		return fmt.Errorf("reports.smtp.host is not set")
+	}
+
+	var msg bytes.Buffer
+	mw := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "From: %s\r\n", smtpCfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", t.Title+", "+t.Period))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	preview := t.text()
+	const previewLines = 40
+	if len(preview) > previewLines {
+		preview = append(preview[:previewLines], "", fmt.Sprintf("... %d rows in total; see the attached %s", len(t.Rows), name))
+	}
+	text, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return err
+	}
+	text.Write([]byte(strings.Join(preview, "\r\n") + "\r\n"))
+
+	attachment, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+	})
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(body)
+	for len(encoded) > 76 {
+		attachment.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	attachment.Write([]byte(encoded + "\r\n"))
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if smtpCfg.Username != "" {
+		auth = smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password, smtpCfg.Host)
+	}
+	if err := smtp.SendMail(net.JoinHostPort(smtpCfg.Host, smtpCfg.Port), auth, smtpCfg.From, []string{to}, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send report to %s: %v", to, err)
+	}
+	logger.Infof("Emailed %s to %s", name, to)
+	return nil
+}
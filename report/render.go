@@ -0,0 +1,108 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// csv renders the header and rows; notes and the title are left to the file
+// name and delivery.
+func (t *table) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(t.Columns)
+	w.WriteAll(t.Rows)
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode report CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.notes { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Period}}</p>
+{{range .Notes}}<p class="notes">{{.}}</p>
+{{end}}{{if .Rows}}<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p>Nothing to report for this period.</p>{{end}}
+<p class="notes">Generated {{.Generated}}</p>
+</body>
+</html>
+`))
+
+// html renders the report as a standalone HTML page.
+func (t *table) html() ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		*table
+		Generated string
+	}{t, time.Now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report HTML: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// maxColumnWidth bounds a column in text layouts; longer values are cut.
+const maxColumnWidth = 40
+
+// text lays the report out as fixed-width lines, for the PDF renderer and
+// email bodies.
+func (t *table) text() []string {
+	lines := []string{t.Title, t.Period}
+	lines = append(lines, t.Notes...)
+	lines = append(lines, "")
+	if len(t.Rows) == 0 {
+		return append(lines, "Nothing to report for this period.")
+	}
+
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = len(c)
+	}
+	for _, row := range t.Rows {
+		for i, v := range row {
+			if len(v) > widths[i] {
+				widths[i] = min(len(v), maxColumnWidth)
+			}
+		}
+	}
+	format := func(values []string) string {
+		var b strings.Builder
+		for i, v := range values {
+			if len(v) > widths[i] {
+				v = v[:widths[i]-1] + "~"
+			}
+			fmt.Fprintf(&b, "%-*s  ", widths[i], v)
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+	lines = append(lines, format(t.Columns))
+	rule := make([]string, len(widths))
+	for i, w := range widths {
+		rule[i] = strings.Repeat("-", w)
+	}
+	lines = append(lines, format(rule))
+	for _, row := range t.Rows {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
@@ -0,0 +1,259 @@
+// Package report renders the report schedules API keys create with
+// CreateReportSchedule and delivers them by email or to object storage.
+//
+// Each run finds the schedules whose latest period (the previous UTC day, or
+// the previous Monday-to-Monday week) has not been delivered, claims them so
+// concurrent workers do not send duplicates, and renders them as CSV, HTML
+// or PDF. A failed delivery releases the claim and is retried on the next
+// run.
+package report
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/storage"
+)
+
+// logger logs the report tool's messages (see the logging package).
+var logger = logging.New("report")
+
+// schedule is a row of report_schedules.
+type schedule struct {
+	id            int
+	apiKey        string
+	report        string // NEW_DOMAINS, EMAIL_POSTURE or COVERAGE
+	frequency     string // DAILY or WEEKLY
+	format        string // CSV, HTML or PDF
+	delivery      string // EMAIL or STORAGE
+	destination   string
+	watchlist     []string
+	tlds          []string
+	lastPeriodEnd sql.NullTime
+	orgID         int // Organization of the key, whose restricted domains are reported; 0 if none
+}
+
+// period returns the most recent complete period of frequency before now.
+func period(frequency string, now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	end = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if frequency == "WEEKLY" {
+		end = end.AddDate(0, 0, -(int(end.Weekday())+6)%7)
+		return end.AddDate(0, 0, -7), end
+	}
+	return end.AddDate(0, 0, -1), end
+}
+
+// runDue renders and delivers every schedule whose latest period is due.
+func runDue(ctx context.Context, db *sql.DB, shards *storage.Router, cfg *config.Config) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT s.id, s.api_key, s.report, s.frequency, s.format, s.delivery, s.destination, s.watchlist, s.tlds,
+			s.last_period_end, COALESCE(o.watchlist, '{}'), COALESCE(o.id, 0)
+		FROM report_schedules s
+		JOIN api_keys k ON k.api_key = s.api_key
+		LEFT JOIN organizations o ON o.id = k.organization_id
+		ORDER BY s.id
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to query report schedules: %v", err)
+	}
+	var schedules []*schedule
+	for rows.Next() {
+		var s schedule
+		var orgWatchlist []string
+		if err := rows.Scan(&s.id, &s.apiKey, &s.report, &s.frequency, &s.format, &s.delivery, &s.destination,
+			pq.Array(&s.watchlist), pq.Array(&s.tlds), &s.lastPeriodEnd, pq.Array(&orgWatchlist), &s.orgID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan report schedule: %v", err)
+		}
+		// New domain reports also watch the terms shared by the key's organization
+		if s.report == "NEW_DOMAINS" {
+			for _, term := range orgWatchlist {
+				if !slices.Contains(s.watchlist, term) {
+					s.watchlist = append(s.watchlist, term)
+				}
+			}
+		}
+		schedules = append(schedules, &s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate report schedules: %v", err)
+	}
+
+	delivered := 0
+	for _, s := range schedules {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		start, end := period(s.frequency, time.Now())
+		if s.lastPeriodEnd.Valid && !s.lastPeriodEnd.Time.Before(end) {
+			continue
+		}
+		claimed, err := claim(ctx, db, s, end)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		renderErr := render(ctx, db, shards, cfg, s, start, end)
+		if err := finish(db, s, end, renderErr); err != nil {
+			return err
+		}
+		if renderErr != nil {
+			logger.Errorf("Error delivering %s report %d for %s: %v", s.report, s.id, end.Format("2006-01-02"), renderErr)
+			continue
+		}
+		delivered++
+	}
+	logger.Infof("Delivered %d of %d report schedules", delivered, len(schedules))
+	return nil
+}
+
+// claim marks the period ending at end as taken by this worker. It reports
+// false if another worker already claimed or delivered it.
+func claim(ctx context.Context, db *sql.DB, s *schedule, end time.Time) (bool, error) {
+	result, err := db.ExecContext(ctx, `
+		UPDATE report_schedules
+		SET last_period_end = $2, last_status = 'RUNNING', last_error = NULL
+		WHERE id = $1 AND (last_period_end IS NULL OR last_period_end < $2)
+	`, s.id, end)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim report schedule %d: %v", s.id, err)
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// finish records the outcome of a claimed period. On failure the claim is
+// released so the next run retries the period.
+func finish(db *sql.DB, s *schedule, end time.Time, renderErr error) error {
+	var err error
+	if renderErr == nil {
+		_, err = db.Exec("UPDATE report_schedules SET last_status = 'SUCCESS' WHERE id = $1", s.id)
+	} else {
+		_, err = db.Exec(`
+			UPDATE report_schedules
+			SET last_period_end = $2, last_status = 'FAILED', last_error = $3
+			WHERE id = $1 AND last_period_end = $4
+		`, s.id, s.lastPeriodEnd, renderErr.Error(), end)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule %d: %v", s.id, err)
+	}
+	return nil
+}
+
+// render builds the report of s for [start, end), renders it in the
+// schedule's format and delivers it.
+func render(ctx context.Context, db *sql.DB, shards *storage.Router, cfg *config.Config, s *schedule, start, end time.Time) error {
+	var t *table
+	var err error
+	switch s.report {
+	case "NEW_DOMAINS":
+		if len(s.watchlist) == 0 {
+			return fmt.Errorf("no watchlist terms; set a watchlist on the schedule or its organization")
+		}
+		t, err = newDomains(ctx, shards, s, start, end, cfg.Reports.MaxRows)
+	case "EMAIL_POSTURE":
+		t, err = emailPosture(ctx, shards, s)
+	case "COVERAGE":
+		t, err = coverage(ctx, db, shards, s)
+	default:
+		return fmt.Errorf("unsupported report %q", s.report)
+	}
+	if err != nil {
+		return err
+	}
+	t.Period = fmt.Sprintf("%s to %s (UTC)", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	var body []byte
+	var ext, contentType string
+	switch s.format {
+	case "CSV":
+		body, err = t.csv()
+		ext, contentType = "csv", "text/csv; charset=utf-8"
+	case "HTML":
+		body, err = t.html()
+		ext, contentType = "html", "text/html; charset=utf-8"
+	case "PDF":
+		body = t.pdf()
+		ext, contentType = "pdf", "application/pdf"
+	default:
+		return fmt.Errorf("unsupported report format %q", s.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.%s", reportSlugs[s.report], end.Format("2006-01-02"), ext)
+	switch s.delivery {
+	case "STORAGE":
+		return store(cfg.Reports.OutputDir, s.id, name, body)
+	case "EMAIL":
+		return email(cfg, s.destination, t, name, contentType, body)
+	default:
+		return fmt.Errorf("unsupported report delivery %q", s.delivery)
+	}
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Connect to AlloyDB
+	db, err := sql.Open("postgres", storage.ConnString(config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode))
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	logger.Infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shards.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Run once, or on a schedule when an interval is configured
+	interval := time.Duration(config.Reports.IntervalMinutes) * time.Minute
+	for {
+		if err := runDue(ctx, db, shards, config); err != nil && ctx.Err() == nil {
+			logger.Errorf("Error running report schedules: %v", err)
+		}
+		if interval <= 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// cache holds answers keyed by question and server until the smallest TTL
+// in the answer expires. Negative answers (NXDOMAIN and empty NOERROR) are
+// kept for the negative TTL.
+type cache struct {
+	mu          sync.Mutex
+	entries     map[string]cacheEntry
+	maxEntries  int
+	maxTTL      time.Duration
+	negativeTTL time.Duration
+}
+
+type cacheEntry struct {
+	resp    *dns.Msg
+	expires time.Time
+}
+
+func newCache(maxEntries int, maxTTL, negativeTTL time.Duration) *cache {
+	return &cache{entries: make(map[string]cacheEntry), maxEntries: maxEntries, maxTTL: maxTTL, negativeTTL: negativeTTL}
+}
+
+func cacheKey(m *dns.Msg, server string) string {
+	if len(m.Question) != 1 {
+		return ""
+	}
+	q := m.Question[0]
+	// Answers to DNSSEC queries carry signatures the others lack, and
+	// GeoDNS answers differ by client subnet
+	do := false
+	if opt := m.IsEdns0(); opt != nil {
+		do = opt.Do()
+	}
+	subnet := ""
+	if ecs := clientSubnet(m); ecs != nil {
+		subnet = ecs.String()
+	}
+	return fmt.Sprintf("%s|%d|%d|%t|%s|%s", dns.CanonicalName(q.Name), q.Qtype, q.Qclass, do, subnet, server)
+}
+
+// get returns a copy of the cached answer to m from server, or nil.
+func (c *cache) get(m *dns.Msg, server string) *dns.Msg {
+	key := cacheKey(m, server)
+	if key == "" || c.maxEntries <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	resp := e.resp.Copy()
+	resp.Id = m.Id
+	return resp
+}
+
+// put caches resp for the lifetime of its shortest TTL, capped at maxTTL.
+// Failures (SERVFAIL, REFUSED, ...) are never cached.
+func (c *cache) put(m *dns.Msg, server string, resp *dns.Msg) {
+	key := cacheKey(m, server)
+	if key == "" || c.maxEntries <= 0 {
+		return
+	}
+	var ttl time.Duration
+	switch {
+	case resp.Rcode == dns.RcodeNameError || (resp.Rcode == dns.RcodeSuccess && len(resp.Answer) == 0):
+		ttl = c.negativeTTL
+	case resp.Rcode == dns.RcodeSuccess:
+		ttl = c.maxTTL
+		for _, rr := range resp.Answer {
+			if t := time.Duration(rr.Header().Ttl) * time.Second; t < ttl {
+				ttl = t
+			}
+		}
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = cacheEntry{resp: resp.Copy(), expires: time.Now().Add(ttl)}
+}
+
+// forget drops the cached answers to questions about name of type qtype, or
+// of every type if qtype is 0, from any server.
+func (c *cache) forget(name string, qtype uint16) {
+	prefix := dns.CanonicalName(name) + "|"
+	if qtype != 0 {
+		prefix += fmt.Sprintf("%d|", qtype)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evict drops expired entries, or an arbitrary entry if none have expired.
+// The caller holds c.mu.
+func (c *cache) evict() {
+	now := time.Now()
+	for key, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
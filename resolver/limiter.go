@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter spaces query starts to a global rate and a per-server rate.
+// A rate of zero disables that limit.
+type limiter struct {
+	global    time.Duration // Minimum time between any two queries
+	perServer time.Duration // Minimum time between queries to one server
+
+	mu         sync.Mutex
+	nextGlobal time.Time
+	nextServer map[string]time.Time
+}
+
+func newLimiter(queriesPerSecond, perServerQueriesPerSecond int) *limiter {
+	l := &limiter{nextServer: make(map[string]time.Time)}
+	if queriesPerSecond > 0 {
+		l.global = time.Second / time.Duration(queriesPerSecond)
+	}
+	if perServerQueriesPerSecond > 0 {
+		l.perServer = time.Second / time.Duration(perServerQueriesPerSecond)
+	}
+	return l
+}
+
+// wait blocks until a query to server may start and returns how long it
+// waited. It returns early with ctx's error if ctx ends first.
+func (l *limiter) wait(ctx context.Context, server string) (time.Duration, error) {
+	if l.global == 0 && l.perServer == 0 {
+		return 0, nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if l.nextGlobal.After(start) {
+		start = l.nextGlobal
+	}
+	if next := l.nextServer[server]; next.After(start) {
+		start = next
+	}
+	l.nextGlobal = start.Add(l.global)
+	if l.perServer > 0 {
+		l.nextServer[server] = start.Add(l.perServer)
+		// Forget servers whose spacing has long passed
+		if len(l.nextServer) > 10000 {
+			for s, next := range l.nextServer {
+				if next.Before(now) {
+					delete(l.nextServer, s)
+				}
+			}
+		}
+	}
+	l.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+		return time.Since(now), ctx.Err()
+	}
+}
@@ -0,0 +1,367 @@
+// Package resolver is the single outbound DNS stub used by bell's workers.
+//
+// Every DNS query goes through a Resolver, which applies a shared response
+// cache, global and per-server rate limits, and transport selection
+// (UDP with TCP fallback on truncation, TCP, DNS over TLS or DNS over HTTPS
+// for the recursive upstreams), and counts what it does in Stats. The counters are
+// published through expvar as "resolver".
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/moos3/bell/config"
+)
+
+// Transports accepted by resolver.transport for recursive upstreams.
+const (
+	TransportUDP   = "udp"     // UDP, retried over TCP when the answer is truncated
+	TransportTCP   = "tcp"     // TCP only
+	TransportTLS   = "tcp-tls" // DNS over TLS (upstreams on port 853)
+	TransportHTTPS = "https"   // DNS over HTTPS (RFC 8484) to resolver.doh_urls
+)
+
+var (
+	// ErrUnsupportedTransport is returned by Lookup for an unknown transport,
+	// or one without configured upstreams.
+	ErrUnsupportedTransport = errors.New("unsupported transport")
+	// ErrServerNotAllowed is returned by Lookup for a recursive server that
+	// is not one of the configured upstreams, so callers cannot turn the
+	// resolver into a proxy to arbitrary hosts.
+	ErrServerNotAllowed = errors.New("server is not a configured upstream")
+)
+
+// Resolver sends DNS queries to authoritative nameservers and to the
+// configured recursive upstreams, which can be probed for health and
+// changed at runtime (see SetUpstreams).
+type Resolver struct {
+	udp        *dns.Client
+	tcp        *dns.Client
+	tls        *dns.Client
+	https      *http.Client
+	transport  string   // Default transport to recursive upstreams
+	configured []string // dns_query.dns_servers and resolver.doh_urls
+
+	mu             sync.RWMutex
+	upstreams      []*upstream
+	unhealthyAfter int // Failed probes in a row before an upstream leaves rotation
+	healthyAfter   int // Successful probes in a row before it returns
+	probeName      string
+
+	cache   *cache
+	limiter *limiter
+	stats   counters
+}
+
+// counters are the live metrics behind Stats.
+type counters struct {
+	queries        atomic.Int64
+	cacheHits      atomic.Int64
+	errors         atomic.Int64
+	timeouts       atomic.Int64
+	tcpFallbacks   atomic.Int64
+	rateLimitWaits atomic.Int64 // Nanoseconds spent waiting for the rate limiter
+	latency        atomic.Int64 // Nanoseconds spent in exchanges that completed
+}
+
+// Stats is a snapshot of a Resolver's metrics.
+type Stats struct {
+	Queries         int64   `json:"queries"`    // Queries sent on the wire
+	CacheHits       int64   `json:"cache_hits"` // Queries answered from the cache
+	CacheEntries    int     `json:"cache_entries"`
+	Errors          int64   `json:"errors"` // Failed exchanges, including timeouts
+	Timeouts        int64   `json:"timeouts"`
+	TCPFallbacks    int64   `json:"tcp_fallbacks"` // Truncated UDP answers retried over TCP
+	RateLimitWaitMs int64   `json:"rate_limit_wait_ms"`
+	AvgLatencyMs    float64 `json:"avg_latency_ms"` // Mean latency of completed exchanges
+}
+
+// LookupOptions choose how Lookup sends a query. The zero value sends it to
+// a random recursive upstream over resolver.transport, through the cache.
+type LookupOptions struct {
+	Transport  string // udp, tcp, tcp-tls or https; resolver.transport if empty
+	Server     string // Recursive upstream (host:port, or a DoH URL for https); random if empty
+	Nameserver string // Authoritative server (host:port) to query instead of an upstream; udp or tcp only
+	NoCache    bool   // Neither answer from nor store into the cache
+
+	// ClientSubnet is sent as an EDNS Client Subnet option (RFC 7871), so
+	// GeoDNS answers for clients in it; none if nil
+	ClientSubnet *net.IPNet
+}
+
+// LookupResult is the answer to a Lookup and how it was obtained.
+type LookupResult struct {
+	Response  *dns.Msg
+	Server    string // Server that answered: host:port, or a DoH URL
+	Transport string
+	RTT       time.Duration // Zero if Cached
+	Cached    bool
+	Scope     string // Client block the answer applies to (see SubnetScope); empty without ECS
+}
+
+// New builds a Resolver from the resolver config block, using
+// dns_query.dns_servers as the recursive upstreams and resolver.doh_urls as
+// the DNS over HTTPS upstreams. Health checks and reloads from the
+// dns_servers table are started separately (StartHealthChecks,
+// WatchServers).
+func New(cfg *config.Config) (*Resolver, error) {
+	timeout := time.Duration(cfg.Resolver.TimeoutSeconds) * time.Second
+	r := &Resolver{
+		udp:            &dns.Client{Net: "udp", Timeout: timeout},
+		tcp:            &dns.Client{Net: "tcp", Timeout: timeout},
+		tls:            &dns.Client{Net: "tcp-tls", Timeout: timeout},
+		https:          &http.Client{Timeout: timeout},
+		transport:      cfg.Resolver.Transport,
+		configured:     append(append([]string(nil), cfg.DNSQuery.DNSServers...), cfg.Resolver.DoHURLs...),
+		unhealthyAfter: cfg.Resolver.UnhealthyAfter,
+		healthyAfter:   cfg.Resolver.HealthyAfter,
+		probeName:      cfg.Resolver.ProbeName,
+		cache: newCache(cfg.Resolver.CacheSize,
+			time.Duration(cfg.Resolver.CacheMaxTTLSeconds)*time.Second,
+			time.Duration(cfg.Resolver.NegativeTTLSeconds)*time.Second),
+		limiter: newLimiter(cfg.Resolver.QueriesPerSecond, cfg.Resolver.PerServerQueriesPerSecond),
+	}
+	if _, err := r.sender(r.transport); err != nil {
+		return nil, fmt.Errorf("unsupported resolver transport %q", r.transport)
+	}
+	if err := r.SetUpstreams(r.configured); errors.Is(err, ErrNoUpstreams) {
+		if r.transport == TransportHTTPS {
+			return nil, fmt.Errorf("no DNS over HTTPS upstreams configured in resolver.doh_urls")
+		}
+		return nil, fmt.Errorf("no recursive upstreams configured in dns_query.dns_servers")
+	} else if err != nil {
+		return nil, err
+	}
+	if expvar.Get("resolver") == nil {
+		expvar.Publish("resolver", expvar.Func(func() interface{} { return r.Stats() }))
+	}
+	return r, nil
+}
+
+// Exchange sends m to an authoritative server (host:port) over UDP,
+// retrying over TCP if the answer is truncated. cached reports whether the
+// answer came from the cache, in which case rtt is zero.
+func (r *Resolver) Exchange(ctx context.Context, m *dns.Msg, server string) (resp *dns.Msg, rtt time.Duration, cached bool, err error) {
+	return r.exchange(ctx, m, server, true, r.sendDNS(r.udp))
+}
+
+// Recursive sends m to a randomly chosen recursive upstream over the
+// configured transport and returns the upstream it used.
+func (r *Resolver) Recursive(ctx context.Context, m *dns.Msg) (resp *dns.Msg, server string, err error) {
+	res, err := r.Lookup(ctx, m, LookupOptions{})
+	if err != nil {
+		return nil, res.Server, err
+	}
+	return res.Response, res.Server, nil
+}
+
+// Lookup sends m as opts describe. A recursive upstream named in opts must be
+// one of the configured ones; when the transport differs from
+// resolver.transport, dns_servers are queried on the transport's standard
+// port (853 for tcp-tls, 53 otherwise). The result names the server even
+// when the exchange failed.
+func (r *Resolver) Lookup(ctx context.Context, m *dns.Msg, opts LookupOptions) (*LookupResult, error) {
+	res, send, err := r.route(opts)
+	if err != nil {
+		return res, err
+	}
+	if opts.ClientSubnet != nil {
+		m = withClientSubnet(m, opts.ClientSubnet)
+	}
+	res.Response, res.RTT, res.Cached, err = r.exchange(ctx, m, res.Server, !opts.NoCache, send)
+	if err == nil {
+		res.Scope = SubnetScope(res.Response)
+	}
+	return res, err
+}
+
+// CheckLookup returns the error Lookup would return for opts before sending
+// anything: ErrUnsupportedTransport or ErrServerNotAllowed.
+func (r *Resolver) CheckLookup(opts LookupOptions) error {
+	_, _, err := r.route(opts)
+	return err
+}
+
+// route picks the transport, server and sender for opts.
+func (r *Resolver) route(opts LookupOptions) (*LookupResult, func(context.Context, *dns.Msg, string) (*dns.Msg, time.Duration, error), error) {
+	res := &LookupResult{Transport: opts.Transport, Server: opts.Server}
+	if res.Transport == "" {
+		res.Transport = r.transport
+		if opts.Nameserver != "" {
+			res.Transport = TransportUDP
+		}
+	}
+
+	send, err := r.sender(res.Transport)
+	if err != nil {
+		return res, nil, err
+	}
+	if opts.Nameserver != "" {
+		if res.Transport != TransportUDP && res.Transport != TransportTCP {
+			return res, nil, fmt.Errorf("%w %q for authoritative servers", ErrUnsupportedTransport, res.Transport)
+		}
+		res.Server = opts.Nameserver
+		return res, send, nil
+	}
+	if res.Server, err = r.pickUpstream(res.Transport, opts.Server); err != nil {
+		res.Server = opts.Server
+		return res, nil, err
+	}
+	return res, send, nil
+}
+
+// sender returns the sender for transport.
+func (r *Resolver) sender(transport string) (func(context.Context, *dns.Msg, string) (*dns.Msg, time.Duration, error), error) {
+	switch transport {
+	case TransportUDP:
+		return r.sendDNS(r.udp), nil
+	case TransportTCP:
+		return r.sendDNS(r.tcp), nil
+	case TransportTLS:
+		return r.sendDNS(r.tls), nil
+	case TransportHTTPS:
+		return r.sendHTTPS, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedTransport, transport)
+}
+
+// upstreamAddr moves an upstream without an explicit transport to the
+// standard port of transport if it differs in kind (TLS or plain) from
+// resolver.transport.
+func (r *Resolver) upstreamAddr(u Upstream, transport string) string {
+	if u.Transport != "" || (transport == TransportTLS) == (r.transport == TransportTLS) {
+		return u.Addr
+	}
+	host, _, err := net.SplitHostPort(u.Addr)
+	if err != nil {
+		return u.Addr
+	}
+	if transport == TransportTLS {
+		return net.JoinHostPort(host, "853")
+	}
+	return net.JoinHostPort(host, "53")
+}
+
+// sendDNS returns a sender over client. UDP answers that are truncated are
+// retried over TCP.
+func (r *Resolver) sendDNS(client *dns.Client) func(context.Context, *dns.Msg, string) (*dns.Msg, time.Duration, error) {
+	return func(ctx context.Context, m *dns.Msg, server string) (*dns.Msg, time.Duration, error) {
+		resp, rtt, err := client.ExchangeContext(ctx, m, server)
+		if err == nil && resp.Truncated && client.Net == "udp" {
+			r.stats.tcpFallbacks.Add(1)
+			resp, rtt, err = r.tcp.ExchangeContext(ctx, m, server)
+		}
+		return resp, rtt, err
+	}
+}
+
+// sendHTTPS posts m to a DNS over HTTPS endpoint in wire format (RFC 8484).
+// The message ID is sent as zero, as the RFC recommends for caching, and
+// restored in the answer.
+func (r *Resolver) sendHTTPS(ctx context.Context, m *dns.Msg, url string) (*dns.Msg, time.Duration, error) {
+	q := m.Copy()
+	q.Id = 0
+	packed, err := q.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	start := time.Now()
+	resp, err := r.https.Do(req)
+	if err != nil {
+		return nil, time.Since(start), err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	rtt := time.Since(start)
+	if err != nil {
+		return nil, rtt, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, rtt, fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	answer := new(dns.Msg)
+	if err := answer.Unpack(body); err != nil {
+		return nil, rtt, fmt.Errorf("invalid answer from %s: %v", url, err)
+	}
+	answer.Id = m.Id
+	return answer, rtt, nil
+}
+
+// exchange answers m from the cache, unless useCache is false, or sends it to
+// server with send, applying the rate limits and counting the outcome.
+func (r *Resolver) exchange(ctx context.Context, m *dns.Msg, server string, useCache bool, send func(context.Context, *dns.Msg, string) (*dns.Msg, time.Duration, error)) (resp *dns.Msg, rtt time.Duration, cached bool, err error) {
+	ctx, span := startSpan(ctx, m, server)
+	defer func() { endSpan(span, resp, cached, err) }()
+	if useCache {
+		if resp := r.cache.get(m, server); resp != nil {
+			r.stats.cacheHits.Add(1)
+			return resp, 0, true, nil
+		}
+	}
+
+	waited, err := r.limiter.wait(ctx, server)
+	r.stats.rateLimitWaits.Add(int64(waited))
+	if err != nil {
+		return nil, 0, false, err
+	}
+	r.stats.queries.Add(1)
+	resp, rtt, err = send(ctx, m, server)
+	if err != nil {
+		r.stats.errors.Add(1)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			r.stats.timeouts.Add(1)
+		}
+		return nil, rtt, false, err
+	}
+	r.stats.latency.Add(int64(rtt))
+	if useCache {
+		r.cache.put(m, server, resp)
+	}
+	return resp, rtt, false, nil
+}
+
+// Forget drops cached answers about name of recordType (e.g. "MX"), or of
+// every type if recordType is empty or unknown, so the next lookup asks an
+// upstream. The API server calls it when the query worker sees the records
+// change.
+func (r *Resolver) Forget(name, recordType string) {
+	r.cache.forget(name, dns.StringToType[strings.ToUpper(recordType)])
+}
+
+// Stats returns a snapshot of the resolver's metrics.
+func (r *Resolver) Stats() Stats {
+	s := Stats{
+		Queries:         r.stats.queries.Load(),
+		CacheHits:       r.stats.cacheHits.Load(),
+		CacheEntries:    r.cache.len(),
+		Errors:          r.stats.errors.Load(),
+		Timeouts:        r.stats.timeouts.Load(),
+		TCPFallbacks:    r.stats.tcpFallbacks.Load(),
+		RateLimitWaitMs: time.Duration(r.stats.rateLimitWaits.Load()).Milliseconds(),
+	}
+	if completed := s.Queries - s.Errors; completed > 0 {
+		s.AvgLatencyMs = float64(time.Duration(r.stats.latency.Load()).Milliseconds()) / float64(completed)
+	}
+	return s
+}
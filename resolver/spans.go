@@ -0,0 +1,46 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/miekg/dns"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records exchanges made within traced requests; it records nothing
+// until the process sets up tracing (see package tracing).
+var tracer = otel.Tracer("github.com/moos3/bell/resolver")
+
+// startSpan starts the span of an exchange of m with server, if ctx is part
+// of a trace; otherwise the span it returns records nothing, so health
+// probes and the query worker's sweeps do not start traces of their own.
+func startSpan(ctx context.Context, m *dns.Msg, server string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	attrs := []attribute.KeyValue{attribute.String("server.address", server)}
+	if len(m.Question) > 0 {
+		attrs = append(attrs,
+			attribute.String("dns.question.name", m.Question[0].Name),
+			attribute.String("dns.question.type", dns.TypeToString[m.Question[0].Qtype]))
+	}
+	return tracer.Start(ctx, "dns.exchange", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an exchange on span and ends it.
+func endSpan(span trace.Span, resp *dns.Msg, cached bool, err error) {
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.Bool("dns.cached", cached))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if resp != nil {
+		span.SetAttributes(attribute.String("dns.response.rcode", dns.RcodeToString[resp.Rcode]))
+	}
+	span.End()
+}
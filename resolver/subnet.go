@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Source prefix lengths for client subnets given as a bare address, as RFC
+// 7871 recommends for privacy.
+const (
+	defaultSubnetBitsIPv4 = 24
+	defaultSubnetBitsIPv6 = 56
+)
+
+// ParseClientSubnet parses an EDNS Client Subnet (RFC 7871) source: a CIDR
+// block, or an address, which stands for its /24 (IPv4) or /56 (IPv6). Host
+// bits are cleared.
+func ParseClientSubnet(s string) (*net.IPNet, error) {
+	s = strings.TrimSpace(s)
+	if _, subnet, err := net.ParseCIDR(s); err == nil {
+		if ip4 := subnet.IP.To4(); ip4 != nil {
+			subnet.IP = ip4
+		}
+		return subnet, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid client subnet %q: want an address or CIDR block", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(defaultSubnetBitsIPv4, 32)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}, nil
+	}
+	mask := net.CIDRMask(defaultSubnetBitsIPv6, 128)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// withClientSubnet returns a copy of m carrying subnet in an ECS option,
+// adding an OPT record if m has none.
+func withClientSubnet(m *dns.Msg, subnet *net.IPNet) *dns.Msg {
+	m = m.Copy()
+	opt := m.IsEdns0()
+	if opt == nil {
+		m.SetEdns0(4096, false)
+		opt = m.IsEdns0()
+	}
+	bits, _ := subnet.Mask.Size()
+	ecs := &dns.EDNS0_SUBNET{Code: dns.EDNS0SUBNET, Family: 1, SourceNetmask: uint8(bits), Address: subnet.IP}
+	if subnet.IP.To4() == nil {
+		ecs.Family = 2
+	}
+	opt.Option = append(opt.Option, ecs)
+	return m
+}
+
+// clientSubnet returns the ECS option of m, or nil.
+func clientSubnet(m *dns.Msg) *dns.EDNS0_SUBNET {
+	opt := m.IsEdns0()
+	if opt == nil {
+		return nil
+	}
+	for _, o := range opt.Option {
+		if ecs, ok := o.(*dns.EDNS0_SUBNET); ok {
+			return ecs
+		}
+	}
+	return nil
+}
+
+// SubnetScope returns the block of clients an answer applies to: the
+// address of its ECS option cut to the scope prefix length the server
+// returned, in CIDR notation. It is empty if the answer has no ECS option,
+// as when the server ignored it, and a /0 when the answer is the same for
+// every client.
+func SubnetScope(resp *dns.Msg) string {
+	ecs := clientSubnet(resp)
+	if ecs == nil {
+		return ""
+	}
+	size := 32
+	if ecs.Family == 2 {
+		size = 128
+	}
+	mask := net.CIDRMask(int(ecs.SourceScope), size)
+	if mask == nil {
+		return ""
+	}
+	return (&net.IPNet{IP: ecs.Address.Mask(mask), Mask: mask}).String()
+}
@@ -0,0 +1,395 @@
+package resolver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ErrNoUpstreams is returned by SetUpstreams for a set without an upstream
+// for resolver.transport.
+var ErrNoUpstreams = errors.New("no recursive upstreams")
+
+// Upstream is a recursive upstream as written in dns_query.dns_servers:
+// [scheme://]host[:port], where scheme is udp, tcp, tls (or tcp-tls) or
+// https. Without a scheme the upstream is queried over resolver.transport,
+// or any transport a lookup asks for; with one, only over that transport.
+// The port defaults to 853 for TLS and 53 otherwise. https upstreams are
+// DNS over HTTPS URLs, like resolver.doh_urls.
+type Upstream struct {
+	Transport string // Explicit transport; empty for resolver.transport
+	Addr      string // host:port, or the URL for https
+}
+
+// ParseUpstream parses an upstream spec. defaultTransport picks the default
+// port of an upstream without a scheme.
+func ParseUpstream(spec, defaultTransport string) (Upstream, error) {
+	spec = strings.TrimSpace(spec)
+	var u Upstream
+	hostport := spec
+	if scheme, rest, ok := strings.Cut(spec, "://"); ok {
+		switch strings.ToLower(scheme) {
+		case "udp":
+			u.Transport = TransportUDP
+		case "tcp":
+			u.Transport = TransportTCP
+		case "tls", TransportTLS:
+			u.Transport = TransportTLS
+		case TransportHTTPS:
+			if rest == "" {
+				return u, fmt.Errorf("invalid upstream %q: missing host", spec)
+			}
+			u.Transport, u.Addr = TransportHTTPS, spec
+			return u, nil
+		default:
+			return u, fmt.Errorf("invalid upstream %q: unknown scheme %q", spec, scheme)
+		}
+		hostport = rest
+	}
+	if hostport == "" {
+		return u, fmt.Errorf("invalid upstream %q: missing host", spec)
+	}
+	transport := u.Transport
+	if transport == "" {
+		transport = defaultTransport
+	}
+	port := "53"
+	if transport == TransportTLS {
+		port = "853"
+	}
+	host, p, err := net.SplitHostPort(hostport)
+	switch {
+	case err == nil:
+		port = p
+	case net.ParseIP(hostport) != nil:
+		// A bare IPv6 address
+		host = hostport
+	default:
+		host = strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+	}
+	if host == "" || strings.ContainsAny(host, "/[]") {
+		return u, fmt.Errorf("invalid upstream %q: bad host", spec)
+	}
+	u.Addr = net.JoinHostPort(host, port)
+	return u, nil
+}
+
+// String returns the upstream in dns_servers syntax, with the port.
+func (u Upstream) String() string {
+	switch u.Transport {
+	case "", TransportHTTPS:
+		return u.Addr
+	case TransportTLS:
+		return "tls://" + u.Addr
+	}
+	return u.Transport + "://" + u.Addr
+}
+
+// serves reports whether lookups over transport may use u.
+func (u Upstream) serves(transport string) bool {
+	if transport == TransportHTTPS || u.Transport == TransportHTTPS {
+		return u.Transport == transport
+	}
+	return u.Transport == "" || u.Transport == transport
+}
+
+// upstream is a member of the resolver's upstream set and its health.
+type upstream struct {
+	Upstream
+
+	// Guarded by Resolver.mu
+	healthy     bool
+	failures    int // Consecutive failed probes
+	successes   int // Consecutive successful probes while unhealthy
+	lastErr     string
+	lastChecked time.Time
+}
+
+// UpstreamStatus is an upstream of the resolver and its health.
+type UpstreamStatus struct {
+	Address             string // In dns_servers syntax
+	Transport           string // Transport probes use
+	Healthy             bool   // In rotation
+	ConsecutiveFailures int
+	LastError           string
+	LastChecked         time.Time // Zero if never probed
+}
+
+// CheckUpstreams reports whether SetUpstreams would accept specs.
+func (r *Resolver) CheckUpstreams(specs []string) error {
+	_, err := r.parseUpstreams(specs)
+	return err
+}
+
+// SetUpstreams replaces the recursive upstreams, including DNS over HTTPS
+// ones. Upstreams kept from the current set keep their health; new ones
+// start healthy.
+func (r *Resolver) SetUpstreams(specs []string) error {
+	upstreams, err := r.parseUpstreams(specs)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := make(map[Upstream]*upstream, len(r.upstreams))
+	for _, u := range r.upstreams {
+		current[u.Upstream] = u
+	}
+	for i, u := range upstreams {
+		if kept, ok := current[u.Upstream]; ok {
+			upstreams[i] = kept
+		}
+	}
+	r.upstreams = upstreams
+	return nil
+}
+
+func (r *Resolver) parseUpstreams(specs []string) ([]*upstream, error) {
+	var upstreams []*upstream
+	seen := make(map[Upstream]bool)
+	usable := false
+	for _, spec := range specs {
+		u, err := ParseUpstream(spec, r.transport)
+		if err != nil {
+			return nil, err
+		}
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		usable = usable || u.serves(r.transport)
+		upstreams = append(upstreams, &upstream{Upstream: u, healthy: true})
+	}
+	if !usable {
+		return nil, fmt.Errorf("%w for transport %s", ErrNoUpstreams, r.transport)
+	}
+	return upstreams, nil
+}
+
+// Upstreams returns the recursive upstreams and their health.
+func (r *Resolver) Upstreams() []UpstreamStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]UpstreamStatus, 0, len(r.upstreams))
+	for _, u := range r.upstreams {
+		statuses = append(statuses, UpstreamStatus{
+			Address:             u.String(),
+			Transport:           r.probeTransport(u.Upstream),
+			Healthy:             u.healthy,
+			ConsecutiveFailures: u.failures,
+			LastError:           u.lastErr,
+			LastChecked:         u.lastChecked,
+		})
+	}
+	return statuses
+}
+
+// pickUpstream returns the address of the upstream a lookup over transport
+// uses: server if it is one of the upstreams serving transport, otherwise a
+// random healthy one. If every upstream is unhealthy, one is still picked
+// so lookups are not refused outright.
+func (r *Resolver) pickUpstream(transport, server string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var candidates, healthy []*upstream
+	for _, u := range r.upstreams {
+		if !u.serves(transport) {
+			continue
+		}
+		if server != "" && (u.Addr == server || u.String() == server) {
+			return r.upstreamAddr(u.Upstream, transport), nil
+		}
+		candidates = append(candidates, u)
+		if u.healthy {
+			healthy = append(healthy, u)
+		}
+	}
+	if len(candidates) == 0 {
+		if transport == TransportHTTPS {
+			return "", fmt.Errorf("%w %q: no resolver.doh_urls configured", ErrUnsupportedTransport, transport)
+		}
+		return "", fmt.Errorf("%w %q: no dns_query.dns_servers configured", ErrUnsupportedTransport, transport)
+	}
+	if server != "" {
+		return "", fmt.Errorf("%w: %s", ErrServerNotAllowed, server)
+	}
+	if len(healthy) > 0 {
+		candidates = healthy
+	}
+	return r.upstreamAddr(candidates[rand.Intn(len(candidates))].Upstream, transport), nil
+}
+
+// probeTransport is the transport health probes to u use.
+func (r *Resolver) probeTransport(u Upstream) string {
+	if u.Transport != "" {
+		return u.Transport
+	}
+	return r.transport
+}
+
+// StartHealthChecks probes every upstream on interval until ctx is
+// cancelled. An upstream failing unhealthyAfter probes in a row is taken
+// out of rotation until it passes healthyAfter in a row.
+func (r *Resolver) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// CheckHealth probes every upstream once, concurrently.
+func (r *Resolver) CheckHealth(ctx context.Context) {
+	r.mu.RLock()
+	upstreams := append([]*upstream(nil), r.upstreams...)
+	r.mu.RUnlock()
+	var wg sync.WaitGroup
+	for _, u := range upstreams {
+		wg.Add(1)
+		go func(u *upstream) {
+			defer wg.Done()
+			r.recordProbe(u, r.probe(ctx, u.Upstream))
+		}(u)
+	}
+	wg.Wait()
+}
+
+// probe asks u for the NS records of resolver.probe_name, bypassing the
+// cache and rate limits. SERVFAIL and REFUSED answers count as failures.
+func (r *Resolver) probe(ctx context.Context, u Upstream) error {
+	transport := r.probeTransport(u)
+	send, err := r.sender(transport)
+	if err != nil {
+		return err
+	}
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(r.probeName), dns.TypeNS)
+	resp, _, err := send(ctx, m, r.upstreamAddr(u, transport))
+	if err != nil {
+		return err
+	}
+	if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+		return fmt.Errorf("answered %s", dns.RcodeToString[resp.Rcode])
+	}
+	return nil
+}
+
+func (r *Resolver) recordProbe(u *upstream, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u.lastChecked = time.Now().UTC()
+	if err == nil {
+		u.failures, u.lastErr = 0, ""
+		if !u.healthy {
+			if u.successes++; u.successes >= r.healthyAfter {
+				u.healthy, u.successes = true, 0
+				log.Printf("Resolver: Upstream %s is healthy again; back in rotation", u)
+			}
+		}
+		return
+	}
+	u.successes = 0
+	u.failures++
+	u.lastErr = err.Error()
+	if u.healthy && u.failures >= r.unhealthyAfter {
+		u.healthy = false
+		log.Printf("Resolver: Upstream %s failed %d health checks (%v); out of rotation", u, u.failures, err)
+	}
+}
+
+// Querier is a *sql.DB or *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ActiveServers returns the upstreams in effect: the configured ones
+// (dns_query.dns_servers and resolver.doh_urls) that the dns_servers table
+// does not disable, followed by those it adds.
+func (r *Resolver) ActiveServers(ctx context.Context, q Querier) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SELECT address, enabled FROM dns_servers ORDER BY address")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	enabled := make(map[string]bool)
+	var added []string
+	for rows.Next() {
+		var address string
+		var on bool
+		if err := rows.Scan(&address, &on); err != nil {
+			return nil, err
+		}
+		enabled[address] = on
+		if on {
+			added = append(added, address)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var active []string
+	for _, spec := range r.configured {
+		u, err := ParseUpstream(spec, r.transport)
+		if err != nil {
+			return nil, err
+		}
+		if on, ok := enabled[u.String()]; !ok || on {
+			active = append(active, u.String())
+		}
+	}
+	return append(active, added...), nil
+}
+
+// NormalizeServer returns spec as stored in the dns_servers table.
+func (r *Resolver) NormalizeServer(spec string) (string, error) {
+	u, err := ParseUpstream(spec, r.transport)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// ReloadServers applies the upstreams in effect (see ActiveServers).
+func (r *Resolver) ReloadServers(ctx context.Context, db *sql.DB) error {
+	specs, err := r.ActiveServers(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to load DNS servers: %v", err)
+	}
+	return r.SetUpstreams(specs)
+}
+
+// WatchServers reloads the upstreams in effect on interval until ctx is
+// cancelled, so edits made through the API reach every process.
+func (r *Resolver) WatchServers(ctx context.Context, db *sql.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := r.ReloadServers(ctx, db); err != nil {
+				log.Printf("Resolver: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,228 @@
+// Package sandbox loads the synthetic dataset served to sandbox API keys
+// (api_keys.sandbox) into the sandbox database (sandbox.database).
+//
+// Loading replaces the DNS data of the sandbox database: domains, records,
+// DGA scores and PTR records are truncated and reloaded from the fixture,
+// so the dataset is the same after every load. The fixture is a YAML file,
+// or a dataset generated by the fixtures package at the scale and seed given
+// with -generate and -seed. The database must already have schema.sql
+// applied.
+package sandbox
+
+import (
+	"database/sql"
+	_ "embed"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+	"gopkg.in/yaml.v3"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/fixtures"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+// defaultFixture is the built-in synthetic dataset.
+//
+//go:embed fixture.yaml
+var defaultFixture []byte
+
+// fixture is the sandbox dataset format.
+type fixture struct {
+	Domains []fixtureDomain `yaml:"domains"`
+	PTR     []string        `yaml:"ptr"` // PTR RRs in zone file format
+}
+
+type fixtureDomain struct {
+	Name        string              `yaml:"name"`
+	Nameservers []string            `yaml:"nameservers"`
+	FirstSeen   time.Time           `yaml:"first_seen"` // The load time if unset
+	Records     map[string][]string `yaml:"records"`    // Source -> RRs in zone file format
+	DGA         *struct {
+		Entropy    float64 `yaml:"entropy"`
+		NgramScore float64 `yaml:"ngram_score"`
+		DGAScore   float64 `yaml:"dga_score"`
+		LikelyDGA  bool    `yaml:"likely_dga"`
+	} `yaml:"dga"`
+}
+
+// loadFixture replaces the sandbox tables with the fixture in one
+// transaction. Records are stamped with the load time.
+func loadFixture(db *sql.DB, f *fixture) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("TRUNCATE domain_scores, dns_records, domains, ptr_records RESTART IDENTITY CASCADE"); err != nil {
+		return fmt.Errorf("failed to clear sandbox tables: %v", err)
+	}
+
+	now := time.Now().UTC()
+	records := 0
+	for _, d := range f.Domains {
+		name := strings.ToLower(strings.TrimSuffix(d.Name, "."))
+		nameservers := d.Nameservers
+		if nameservers == nil {
+			nameservers = []string{}
+		}
+		firstSeen := d.FirstSeen
+		if firstSeen.IsZero() {
+			firstSeen = now
+		}
+		var domainID int
+		err := tx.QueryRow(`
+			INSERT INTO domains (domain_name, tld, public_suffix, nameservers, last_updated, first_seen)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, name, recordset.TLD(name), recordset.PublicSuffix(name), pq.StringArray(nameservers), now, firstSeen).Scan(&domainID)
+		if err != nil {
+			return fmt.Errorf("failed to insert domain %s: %v", name, err)
+		}
+		for source, rrs := range d.Records {
+			for _, data := range rrs {
+				rr, err := dns.NewRR(data)
+				if err != nil || rr == nil {
+					return fmt.Errorf("invalid record for %s: %q: %v", name, data, err)
+				}
+				priority, weight := recordset.SortKeys(rr)
+				normalized := recordset.NormalizeRR(rr)
+				_, err = tx.Exec(`
+					INSERT INTO dns_records (domain_id, record_type, record_data, canonical_hash, ttl, source, last_updated, first_seen, priority, weight)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8, $9)
+					ON CONFLICT DO NOTHING
+				`, domainID, dns.TypeToString[rr.Header().Rrtype], normalized, recordset.CanonicalHash(normalized), int(rr.Header().Ttl), strings.ToUpper(source), now, priority, weight)
+				if err != nil {
+					return fmt.Errorf("failed to insert record for %s: %v", name, err)
+				}
+				records++
+			}
+		}
+		if d.DGA != nil {
+			_, err := tx.Exec(`
+				INSERT INTO domain_scores (domain_id, entropy, ngram_score, dga_score, likely_dga, model, scored_at)
+				VALUES ($1, $2, $3, $4, $5, 'sandbox', $6)
+			`, domainID, d.DGA.Entropy, d.DGA.NgramScore, d.DGA.DGAScore, d.DGA.LikelyDGA, now)
+			if err != nil {
+				return fmt.Errorf("failed to insert DGA score for %s: %v", name, err)
+			}
+		}
+	}
+
+	for _, data := range f.PTR {
+		rr, err := dns.NewRR(data)
+		if err != nil || rr == nil {
+			return fmt.Errorf("invalid PTR record %q: %v", data, err)
+		}
+		ptr, ok := rr.(*dns.PTR)
+		if !ok {
+			return fmt.Errorf("not a PTR record: %q", data)
+		}
+		owner := strings.TrimSuffix(dns.CanonicalName(ptr.Hdr.Name), ".")
+		ip, ok := recordset.ReverseAddr(owner)
+		if !ok {
+			return fmt.Errorf("PTR owner %s is not a full reverse name", owner)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO ptr_records (ip, ptr_name, zone, ttl, source, last_updated)
+			VALUES ($1, $2, $3, $4, 'CZDS', $5)
+		`, ip.String(), strings.TrimSuffix(dns.CanonicalName(ptr.Ptr), "."), owner[strings.Index(owner, ".")+1:], int(ptr.Hdr.Ttl), now)
+		if err != nil {
+			return fmt.Errorf("failed to insert PTR record for %s: %v", ip, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d domains, %d records and %d PTR records into the sandbox\n", len(f.Domains), records, len(f.PTR))
+	return nil
+}
+
+// generatedFixture converts a generated dataset to the fixture format.
+func generatedFixture(ds *fixtures.Dataset) *fixture {
+	f := &fixture{}
+	for _, z := range ds.Zones {
+		for _, d := range z.Domains {
+			fd := fixtureDomain{Name: d.Name, Nameservers: d.Nameservers, FirstSeen: d.FirstSeen, Records: make(map[string][]string)}
+			for source, rrs := range d.Records {
+				for _, rr := range rrs {
+					fd.Records[source] = append(fd.Records[source], rr.String())
+				}
+			}
+			f.Domains = append(f.Domains, fd)
+		}
+	}
+	for _, ptr := range ds.PTR {
+		f.PTR = append(f.PTR, ptr.String())
+	}
+	return f
+}
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "Path to configuration file")
+	fixtureFile := flag.String("fixture", "", "Fixture to load; overrides sandbox.fixture")
+	generate := flag.Int("generate", 0, "Load a generated dataset with this many domains per TLD instead of a fixture")
+	seed := flag.Int64("seed", 1, "Seed for -generate; the same seed loads the same dataset")
+	tlds := flag.String("tlds", "example", "Comma-separated TLDs for -generate")
+	flag.Parse()
+
+	// Load configuration
+	config, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if config.Sandbox.Database == "" {
+		log.Fatal("sandbox.database is not configured")
+	}
+	if config.Sandbox.Database == config.AlloyDB.Database {
+		log.Fatal("sandbox.database must not be the production database")
+	}
+
+	var f *fixture
+	if *generate > 0 {
+		// Keep the generated first_seen times recent so new domain queries find them
+		f = generatedFixture(fixtures.Generate(fixtures.Options{
+			Seed:          *seed,
+			TLDs:          strings.Split(*tlds, ","),
+			DomainsPerTLD: *generate,
+			Now:           time.Now().UTC().Truncate(24 * time.Hour),
+		}))
+	} else {
+		data := defaultFixture
+		path := *fixtureFile
+		if path == "" {
+			path = config.Sandbox.Fixture
+		}
+		if path != "" {
+			if data, err = os.ReadFile(path); err != nil {
+				log.Fatalf("Failed to read fixture: %v", err)
+			}
+		}
+		f = &fixture{}
+		if err := yaml.Unmarshal(data, f); err != nil {
+			log.Fatalf("Failed to parse fixture: %v", err)
+		}
+	}
+
+	// Connect to the sandbox database on the AlloyDB host
+	db, err := sql.Open("postgres", storage.ConnString(config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.Sandbox.Database, config.AlloyDB.SSLMode))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatal("Failed to connect to sandbox database: ", err)
+	}
+	fmt.Printf("Connected to sandbox database %s successfully.\n", config.Sandbox.Database)
+
+	if err := loadFixture(db, f); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,39 +1,151 @@
 -- GRPC / REST API Tables
+-- Organizations group API keys sold to a team: member keys share a daily
+-- request quota and a watchlist, and org admin keys manage the members.
+CREATE TABLE organizations (
+                               id SERIAL PRIMARY KEY,
+                               name VARCHAR(255) NOT NULL UNIQUE,
+                               daily_request_quota BIGINT NOT NULL DEFAULT 0, -- Requests per UTC day across member keys, as in usage_counts; 0 for unlimited
+                               watchlist TEXT[] NOT NULL DEFAULT '{}', -- Added to member keys' NEW_DOMAINS report watchlists
+                               created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- API keys table for authentication
 CREATE TABLE api_keys (
                           api_key UUID PRIMARY KEY,
                           description VARCHAR(255),
                           created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-                          is_active BOOLEAN DEFAULT TRUE
+                          is_active BOOLEAN DEFAULT TRUE,
+                          role VARCHAR(50) NOT NULL DEFAULT '', -- Key role for response field redaction (redaction.roles)
+                          scopes TEXT[] NOT NULL DEFAULT '{}', -- Granted scopes, e.g. read:records or admin:keys; empty for those of the role (authorization.roles)
+                          sandbox BOOLEAN NOT NULL DEFAULT FALSE, -- Serve the key from the synthetic sandbox database (sandbox.database)
+                          organization_id INTEGER REFERENCES organizations (id), -- NULL for keys outside any organization
+                          org_admin BOOLEAN NOT NULL DEFAULT FALSE, -- May manage its organization's keys and watchlist
+                          owner VARCHAR(255) NOT NULL DEFAULT '', -- Person or service responsible for the key, set by CreateAPIKey
+                          expires_at TIMESTAMP, -- Rejected from then on; NULL for never. RotateAPIKey sets it on the old key
+                          revoked_at TIMESTAMP, -- Set by RevokeAPIKey along with is_active = FALSE
+                          rotated_to UUID, -- Key that replaced this one, set by RotateAPIKey
+                          monthly_request_quota BIGINT NOT NULL DEFAULT 0, -- Requests per UTC calendar month, as in usage_counts; 0 for unlimited
+                          monthly_record_quota BIGINT NOT NULL DEFAULT 0 -- Records returned per UTC calendar month, as in usage_counts; 0 for unlimited
 );
 
+CREATE INDEX idx_api_keys_organization_id ON api_keys (organization_id);
+
 -- Index for faster lookup
 CREATE INDEX idx_api_keys_api_key ON api_keys (api_key);
 
--- Example API key (generate UUID with `uuid_generate_v4()` or tool)
+-- Keys are managed with the AdminService RPCs. The first admin key, listed
+-- in logging.admin_api_keys, is inserted by hand:
 -- INSERT INTO api_keys (api_key, description) VALUES ('550e8400-e29b-41d4-a716-446655440000', 'Test API Key');
 
+-- Request defaults per API key, managed by the key itself through
+-- SetKeyPreferences. Empty values leave the request or server default alone.
+CREATE TABLE key_preferences (
+                                 api_key UUID PRIMARY KEY REFERENCES api_keys (api_key) ON DELETE CASCADE,
+                                 record_types TEXT[] NOT NULL DEFAULT '{}', -- GetRecords types when the request gives none
+                                 source_precedence TEXT[] NOT NULL DEFAULT '{}', -- Overrides merge.precedence for merged GetRecords
+                                 max_rows INTEGER NOT NULL DEFAULT 0, -- Caps GetRecords and defaults list limits; 0 for none
+                                 timezone VARCHAR(64) NOT NULL DEFAULT '', -- IANA zone for response timestamps; empty for UTC
+                                 updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recurring reports per API key, managed by the key itself through
+-- CreateReportSchedule and rendered and delivered by the report worker.
+CREATE TABLE report_schedules (
+                                  id SERIAL PRIMARY KEY,
+                                  api_key UUID NOT NULL REFERENCES api_keys (api_key) ON DELETE CASCADE,
+                                  report VARCHAR(20) NOT NULL, -- NEW_DOMAINS, EMAIL_POSTURE or COVERAGE
+                                  frequency VARCHAR(10) NOT NULL, -- DAILY or WEEKLY
+                                  format VARCHAR(10) NOT NULL, -- CSV, HTML or PDF
+                                  delivery VARCHAR(10) NOT NULL, -- EMAIL or STORAGE
+                                  destination TEXT NOT NULL DEFAULT '', -- Email address for EMAIL deliveries
+                                  watchlist TEXT[] NOT NULL DEFAULT '{}', -- Terms matched against domain names
+                                  tlds TEXT[] NOT NULL DEFAULT '{}', -- TLDs covered; all if empty
+                                  last_period_end TIMESTAMP, -- End of the last period claimed or delivered; NULL if none yet
+                                  last_status VARCHAR(20), -- SUCCESS, FAILED or RUNNING
+                                  last_error TEXT,
+                                  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_schedules_api_key ON report_schedules (api_key);
+
+-- Exports queued with StartExport and written to exports.output_dir by the
+-- export worker, which keeps heartbeat_at current while a job runs so jobs of
+-- a worker that died are picked up again. On existing databases, add
+-- signature.
+CREATE TABLE export_jobs (
+                             id SERIAL PRIMARY KEY,
+                             api_key UUID NOT NULL REFERENCES api_keys (api_key) ON DELETE CASCADE,
+                             kind VARCHAR(10) NOT NULL, -- DOMAINS or RECORDS
+                             format VARCHAR(10) NOT NULL, -- CSV or JSONL
+                             tld VARCHAR(63) NOT NULL DEFAULT '',
+                             pattern TEXT NOT NULL DEFAULT '', -- Domain name glob
+                             record_type VARCHAR(20) NOT NULL DEFAULT '', -- RECORDS only
+                             source VARCHAR(20) NOT NULL DEFAULT '', -- RECORDS only
+                             data_pattern TEXT NOT NULL DEFAULT '', -- Record data glob; RECORDS only
+                             first_seen_after TIMESTAMP,
+                             first_seen_before TIMESTAMP,
+                             scrub BOOLEAN NOT NULL DEFAULT FALSE, -- Pseudonymized and redacted per exports.scrub
+                             role VARCHAR(50), -- Role of the key when the export ran, whose redaction.roles fields it leaves out
+                             status VARCHAR(20) NOT NULL DEFAULT 'PENDING', -- PENDING, RUNNING, SUCCEEDED, FAILED or EXPIRED
+                             file_name TEXT, -- Relative to exports.output_dir; set when SUCCEEDED
+                             signature TEXT, -- Of the file (see the signing package); set when SUCCEEDED if signing.key_files is set
+                             row_count BIGINT NOT NULL DEFAULT 0,
+                             byte_count BIGINT NOT NULL DEFAULT 0,
+                             skipped_shards TEXT[] NOT NULL DEFAULT '{}', -- Unhealthy shards left out
+                             error TEXT,
+                             created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                             started_at TIMESTAMP,
+                             heartbeat_at TIMESTAMP,
+                             finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_export_jobs_api_key ON export_jobs (api_key);
+CREATE INDEX idx_export_jobs_status ON export_jobs (status) WHERE status IN ('PENDING', 'RUNNING');
+
 CREATE DATABASE dns_records_db;
 
   -- Domains table: Stores unique domains and their nameservers
+  -- On existing databases, add restricted_to (nullable, so every domain
+  -- stays public) and create idx_domains_restricted, and add inserted_at so
+  -- that rows already stored belong to every snapshot:
+  --   ALTER TABLE domains ADD COLUMN inserted_at TIMESTAMPTZ NOT NULL DEFAULT '-infinity';
+  --   ALTER TABLE domains ALTER COLUMN inserted_at SET DEFAULT now();
 CREATE TABLE domains (
                          id SERIAL PRIMARY KEY,
                          domain_name VARCHAR(255) NOT NULL,
-                         tld VARCHAR(50) NOT NULL,
+                         tld VARCHAR(50) NOT NULL, -- Last label: uk for example.co.uk
+                         public_suffix VARCHAR(255) NOT NULL, -- Registry suffix from the Public Suffix List (co.uk), or the zone it was read from if longer
                          nameservers TEXT[] NOT NULL DEFAULT '{}', -- Array of nameserver hostnames
                          last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                         first_seen TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- When the domain first appeared; only moved back by pdns imports
+                         restricted_to INTEGER[], -- Organizations (organizations.id) whose keys alone may read the domain and its records; NULL for public domains
+                         inserted_at TIMESTAMPTZ NOT NULL DEFAULT now(), -- When the row was inserted; never written by writers, so snapshot tokens can cut off on it
                          UNIQUE (domain_name, tld)
 );
 
 -- DNS records table: Stores all DNS records (NS, A, AAAA, MX, TXT, etc.)
+-- One row per record and source, keyed by canonical_hash; writers upsert
+-- later observations into it (see recordset.OnRecordConflict).
+-- On existing databases, add record_data_z to dns_records and
+-- record_history, and replace keep_record_history and its trigger, before
+-- setting record_data.compress_min_bytes; analytics -job compress-records
+-- compresses the records stored before. Add inserted_at the same way as on
+-- domains.
 CREATE TABLE dns_records (
                              id BIGSERIAL, -- No PRIMARY KEY on parent table for partitioning
                              domain_id INTEGER NOT NULL REFERENCES domains(id),
                              record_type VARCHAR(20) NOT NULL,
-                             record_data TEXT NOT NULL,
-                             ttl INTEGER,
-                             source VARCHAR(20) DEFAULT 'CZDS',
-                             last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+                             record_data TEXT NOT NULL, -- Normalized at write time (recordset.Normalize), as last observed; empty if compressed
+                             record_data_z BYTEA, -- record_data compressed (storage.EncodeRecordData), for long TXT records if record_data.compress_min_bytes is set
+                             canonical_hash BYTEA NOT NULL, -- SHA-256 of the canonical data without TTL (recordset.CanonicalHash)
+                             ttl INTEGER, -- As last observed
+                             source VARCHAR(20) NOT NULL DEFAULT 'CZDS',
+                             last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Latest observation
+                             first_seen TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Earliest observation
+                             observations INTEGER NOT NULL DEFAULT 1, -- Times the record was written from this source
+                             inserted_at TIMESTAMPTZ NOT NULL DEFAULT now(), -- When the row was inserted; never written by writers, so snapshot tokens can cut off on it
+                             priority INTEGER, -- MX preference, SRV priority or NAPTR order, parsed at write time; NULL for other types
+                             weight INTEGER -- SRV weight, parsed at write time; NULL for other types
 ) PARTITION BY LIST (record_type);
 
 -- Partitions
@@ -43,7 +155,7 @@ CREATE TABLE dns_records_aaaa PARTITION OF dns_records FOR VALUES IN ('AAAA');
 CREATE TABLE dns_records_mx PARTITION OF dns_records FOR VALUES IN ('MX');
 CREATE TABLE dns_records_txt PARTITION OF dns_records FOR VALUES IN ('TXT');
 CREATE TABLE dns_records_cname PARTITION OF dns_records FOR VALUES IN ('CNAME');
-CREATE TABLE dns_records_other PARTITION OF dns_records FOR VALUES IN ('SOA', 'PTR', 'SRV', 'CAA', 'DNSKEY', 'DS');
+CREATE TABLE dns_records_other PARTITION OF dns_records FOR VALUES IN ('SOA', 'PTR', 'SRV', 'NAPTR', 'TLSA', 'CAA', 'DNSKEY', 'DS');
 
 -- Add PRIMARY KEY constraints to partitions
 ALTER TABLE dns_records_ns ADD CONSTRAINT dns_records_ns_pk PRIMARY KEY (id);
@@ -53,16 +165,53 @@ ALTER TABLE dns_records_mx ADD CONSTRAINT dns_records_mx_pk PRIMARY KEY (id);
 ALTER TABLE dns_records_txt ADD CONSTRAINT dns_records_txt_pk PRIMARY KEY (id);
 ALTER TABLE dns_records_cname ADD CONSTRAINT dns_records_cname_pk PRIMARY KEY (id);
 ALTER TABLE dns_records_other ADD CONSTRAINT dns_records_other_pk PRIMARY KEY (id);
+-- Natural key of a record. On existing databases, stop the writers and run
+-- analytics -job normalize-records, which adds canonical_hash, first_seen
+-- and observations on every shard, fills canonical_hash, merges rows that
+-- share a key, makes canonical_hash and source NOT NULL and creates this
+-- index. It can be rerun, and does nothing on shards already migrated.
+CREATE UNIQUE INDEX dns_records_natural_key ON dns_records (domain_id, record_type, source, canonical_hash);
 -- Indexes
-CREATE INDEX idx_domains_domain_name ON domains (domain_name);
+-- Hot paths check that their indexes are used at server startup (see
+-- server/indexes.go). On existing databases, analytics -job create-indexes
+-- creates the ones missing with CREATE INDEX CONCURRENTLY, and replaces an
+-- idx_domains_domain_name without INCLUDE (id); it can be rerun.
+CREATE INDEX idx_domains_domain_name ON domains (domain_name) INCLUDE (id); -- GetRecords and other lookups by name, without visiting the heap
+CREATE INDEX idx_domains_domain_name_pattern ON domains (domain_name text_pattern_ops); -- Name searches (LIKE 'prefix%') in counts, exports and reports
+CREATE INDEX idx_domains_query_due ON domains (last_updated, id) WHERE nameservers <> '{}'; -- Query worker selection of delegated domains due a refresh
+CREATE INDEX idx_domains_nameservers ON domains USING GIN (nameservers); -- GetDomainsByNameserver (nameservers && ARRAY[...])
+CREATE INDEX idx_domains_reverse_name ON domains ((reverse(domain_name) COLLATE "C")); -- ListSubdomains (reversed names LIKE 'moc.elpmaxe.%', in reversed order)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_domains_domain_name_trgm ON domains USING GIN (domain_name gin_trgm_ops); -- SearchDomains (domain_name LIKE '%bank%', wildcards anywhere)
 CREATE INDEX idx_domains_tld ON domains (tld);
+CREATE INDEX idx_domains_tld_domain_name ON domains (tld, domain_name); -- ListDomainsByTLD pages (tld = $1 AND domain_name > $2 ORDER BY domain_name)
+CREATE INDEX idx_domains_restricted ON domains (id) WHERE restricted_to IS NOT NULL; -- Hiding restricted domains' records from queries that do not join domains
+-- Zone deltas, processed zone counts and searches by a suffix such as co.uk.
+-- czds used to store the zone name (co.uk) as tld. On existing databases,
+-- add public_suffix as nullable and, before ingesting again, run
+--   UPDATE domains SET public_suffix = tld, tld = regexp_replace(tld, '^.*\.', '') WHERE tld LIKE '%.%';
+--   UPDATE domains SET public_suffix = tld WHERE public_suffix IS NULL;
+-- then make it NOT NULL. The first fails for domains pdns also imported
+-- under their last label; delete those rows first. The second is wrong for
+-- pdns-only domains under multi-label suffixes until they are imported again.
+CREATE INDEX idx_domains_public_suffix ON domains (public_suffix);
+CREATE INDEX idx_domains_public_suffix_domain_name ON domains (public_suffix, domain_name); -- ListDomainsByTLD pages of public suffixes such as co.uk
+CREATE INDEX idx_domains_first_seen ON domains (first_seen);
 CREATE INDEX idx_dns_records_domain_id ON dns_records (domain_id);
 CREATE INDEX idx_dns_records_record_type ON dns_records (record_type);
 
--- Processed TLDs table (unchanged)
+-- Processed TLDs table: ingestion status per TLD. On existing databases, run
+--   ALTER TABLE processed_tlds ADD COLUMN IF NOT EXISTS first_processed TIMESTAMP;
+-- before deploying the czds worker; TLDs ingested before are left NULL.
 CREATE TABLE processed_tlds (
                                 tld VARCHAR(50) PRIMARY KEY,
-                                last_processed TIMESTAMP NOT NULL
+                                first_processed TIMESTAMP, -- First successful ingest; NULL if never successful, or if before the column was added
+                                last_processed TIMESTAMP, -- Last successful ingest; NULL if never successful
+                                last_attempted TIMESTAMP,
+                                last_status VARCHAR(20), -- SUCCESS, FAILED or INTERRUPTED (stopped by shutdown)
+                                last_error TEXT,
+                                domain_count INTEGER NOT NULL DEFAULT 0,
+                                record_count BIGINT NOT NULL DEFAULT 0
 );
 
 -- Query progress table to track last processed domain_id
@@ -78,3 +227,389 @@ CREATE INDEX idx_query_progress_id ON query_progress (id);
 -- Initialize with no progress
 INSERT INTO query_progress (last_domain_id) VALUES (NULL);
 
+-- Domains organizations track, added with ImportDomains; their domains rows
+-- are inserted on the shard of the TLD if missing. The query worker resolves
+-- them ahead of the zone-file sweep, nameservers or not, whenever
+-- last_queried is over 12 hours old.
+CREATE TABLE customer_domains (
+                                  organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+                                  domain_name VARCHAR(255) NOT NULL,
+                                  tld VARCHAR(50) NOT NULL,
+                                  source VARCHAR(20) NOT NULL DEFAULT 'CUSTOMER', -- As dns_records.source: where the domain came from
+                                  added_by UUID, -- Org admin key that imported it
+                                  added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  last_queried TIMESTAMP, -- Set by the query worker; NULL until first resolved
+                                  PRIMARY KEY (organization_id, domain_name)
+);
+
+CREATE INDEX idx_customer_domains_domain_name ON customer_domains (domain_name);
+CREATE INDEX idx_customer_domains_last_queried ON customer_domains (last_queried NULLS FIRST);
+
+-- Leases on work items held by the CZDS importer (one per TLD) and the query
+-- worker (one per domain batch). Workers heartbeat while they hold a lease;
+-- the server's reaper marks leases past expires_at EXPIRED and re-queues the
+-- item. Rows are kept after release to count repeated failures.
+CREATE TABLE work_leases (
+                             kind VARCHAR(20) NOT NULL, -- tld or domain_batch
+                             item VARCHAR(255) NOT NULL, -- TLD, or "<shard>:<first>-<last>" domain IDs of a batch
+                             owner VARCHAR(255) NOT NULL, -- host:pid of the worker that last held the lease
+                             state VARCHAR(20) NOT NULL, -- RUNNING, DONE, FAILED, INTERRUPTED or EXPIRED
+                             acquired_at TIMESTAMP NOT NULL,
+                             heartbeat_at TIMESTAMP NOT NULL,
+                             expires_at TIMESTAMP NOT NULL,
+                             failures INTEGER NOT NULL DEFAULT 0, -- Consecutive failed or expired runs; reset on success
+                             last_error TEXT,
+                             PRIMARY KEY (kind, item)
+);
+
+-- Expired lease scans by the reaper
+CREATE INDEX idx_work_leases_running ON work_leases (expires_at) WHERE state = 'RUNNING';
+
+
+-- DGA scores computed by the dga job for each domain's registrable label
+CREATE TABLE domain_scores (
+                               domain_id INTEGER PRIMARY KEY REFERENCES domains(id),
+                               entropy REAL NOT NULL,
+                               ngram_score REAL NOT NULL,
+                               dga_score REAL NOT NULL,
+                               likely_dga BOOLEAN NOT NULL DEFAULT FALSE,
+                               model VARCHAR(255) NOT NULL,
+                               scored_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for new-domain feeds filtering on the DGA flag
+CREATE INDEX idx_domain_scores_likely_dga ON domain_scores (likely_dga) WHERE likely_dga;
+
+-- Top-N aggregates maintained by the analytics job; tld = '' holds global rankings
+CREATE TABLE analytics_top (
+                               metric VARCHAR(20) NOT NULL, -- NAMESERVERS, MX_PROVIDERS, or ASNS
+                               tld VARCHAR(50) NOT NULL,
+                               key TEXT NOT NULL, -- Nameserver host, MX provider domain, or ASN
+                               label TEXT NOT NULL DEFAULT '', -- AS name for ASNS
+                               domain_count BIGINT NOT NULL,
+                               rank INTEGER NOT NULL,
+                               computed_at TIMESTAMP NOT NULL,
+                               PRIMARY KEY (metric, tld, key)
+);
+
+CREATE INDEX idx_analytics_top_rank ON analytics_top (metric, tld, rank);
+
+-- TTL changes between observations flagged by the query worker
+CREATE TABLE ttl_anomalies (
+                               id BIGSERIAL PRIMARY KEY,
+                               domain_id INTEGER NOT NULL REFERENCES domains(id),
+                               record_type VARCHAR(20) NOT NULL,
+                               old_ttl INTEGER NOT NULL,
+                               new_ttl INTEGER NOT NULL,
+                               observed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ttl_anomalies_domain_id ON ttl_anomalies (domain_id, observed_at);
+
+-- Authoritative answers stored by RefreshDomain, per client subnet sent as
+-- EDNS Client Subnet ('' for none), with the scope the server returned, so
+-- geo-targeted answers can be compared. Returned by GetRecords on request.
+CREATE TABLE geo_answers (
+                             domain_id INTEGER NOT NULL REFERENCES domains(id),
+                             record_type VARCHAR(20) NOT NULL,
+                             client_subnet VARCHAR(64) NOT NULL DEFAULT '',
+                             scope VARCHAR(64) NOT NULL DEFAULT '', -- Client block the answer applies to; '' if the server ignored ECS
+                             rcode VARCHAR(20) NOT NULL,
+                             answer TEXT[] NOT NULL DEFAULT '{}', -- Answer section in zone file format
+                             server VARCHAR(255) NOT NULL,
+                             queried_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                             PRIMARY KEY (domain_id, record_type, client_subnet)
+);
+
+-- IANA registrar ID registry, loaded by the analytics iana-registrars job
+CREATE TABLE iana_registrars (
+                                 iana_id INTEGER PRIMARY KEY,
+                                 name VARCHAR(255) NOT NULL,
+                                 status VARCHAR(50) NOT NULL,
+                                 rdap_url VARCHAR(255),
+                                 updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- IANA's list of TLDs, loaded by the analytics iana-tlds job and compared
+-- with processed_tlds by GetTLDCoverage
+CREATE TABLE iana_tlds (
+                           tld VARCHAR(63) PRIMARY KEY,
+                           kind VARCHAR(10) NOT NULL, -- CCTLD (two letters) or GTLD
+                           updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Registrar and abuse contacts per domain from RDAP, cached by GetAbuseContacts
+CREATE TABLE domain_abuse_contacts (
+                                       domain_name VARCHAR(255) PRIMARY KEY,
+                                       registrar_iana_id INTEGER, -- NULL if RDAP did not name an IANA registrar
+                                       registrar_name VARCHAR(255),
+                                       contacts JSONB NOT NULL DEFAULT '[]', -- [{role, email, phone, source_url}]
+                                       fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Domain lifecycle events returned by GetDomainLifecycle: nameserver changes
+-- recorded by zone ingests and registrar transfers detected over RDAP
+CREATE TABLE domain_lifecycle_events (
+                                         id BIGSERIAL PRIMARY KEY,
+                                         domain_name VARCHAR(255) NOT NULL,
+                                         event_type VARCHAR(20) NOT NULL, -- NAMESERVERS_CHANGED or TRANSFER
+                                         observed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                         old_nameservers TEXT[] NOT NULL DEFAULT '{}',
+                                         nameservers TEXT[] NOT NULL DEFAULT '{}',
+                                         provider_changed BOOLEAN NOT NULL DEFAULT FALSE, -- NAMESERVERS_CHANGED: no nameserver domain in common with the old set
+                                         significance VARCHAR(20), -- NAMESERVERS_CHANGED: NS_REPLACED (no nameserver in common with the old set) or MINOR; NULL on rows stored before it was added
+                                         old_registrar_iana_id INTEGER, -- TRANSFER only
+                                         old_registrar_name VARCHAR(255),
+                                         registrar_iana_id INTEGER,
+                                         registrar_name VARCHAR(255),
+                                         confidence VARCHAR(10), -- TRANSFER: CONFIRMED or LIKELY
+                                         evidence TEXT[] NOT NULL DEFAULT '{}', -- TRANSFER: signals behind the detection
+                                         checked_at TIMESTAMP, -- NAMESERVERS_CHANGED with provider_changed: when the transfer check ran
+                                         notified_at TIMESTAMP -- TRANSFER: when posted to lifecycle.webhook_url
+);
+CREATE INDEX idx_domain_lifecycle_events_domain ON domain_lifecycle_events (domain_name, observed_at);
+CREATE INDEX idx_domain_lifecycle_events_unchecked ON domain_lifecycle_events (id) WHERE provider_changed AND checked_at IS NULL;
+CREATE INDEX idx_domain_lifecycle_events_unnotified ON domain_lifecycle_events (id) WHERE event_type = 'TRANSFER' AND notified_at IS NULL;
+
+-- Runtime edits to the recursive upstreams of dns_query.dns_servers and
+-- resolver.doh_urls, made with UpdateDNSServers and picked up by the server
+-- and query workers. A disabled row removes a configured upstream.
+CREATE TABLE dns_servers (
+                             address VARCHAR(255) PRIMARY KEY, -- [udp|tcp|tls://]host:port or a DNS over HTTPS URL
+                             enabled BOOLEAN NOT NULL,
+                             updated_by VARCHAR(255), -- Admin API key that made the edit
+                             updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Rolling reliability per nameserver host, maintained by the query worker
+CREATE TABLE nameserver_reputation (
+                                       host VARCHAR(255) PRIMARY KEY,
+                                       score REAL NOT NULL DEFAULT 1, -- Exponentially weighted success rate (0-1)
+                                       queries BIGINT NOT NULL DEFAULT 0,
+                                       timeouts BIGINT NOT NULL DEFAULT 0,
+                                       failures BIGINT NOT NULL DEFAULT 0, -- Non-timeout errors plus SERVFAIL and REFUSED answers
+                                       consecutive_timeouts INTEGER NOT NULL DEFAULT 0,
+                                       avg_rtt_ms REAL NOT NULL DEFAULT 0,
+                                       last_success TIMESTAMP,
+                                       last_failure TIMESTAMP,
+                                       skip_until TIMESTAMP, -- Worker skips the host until then
+                                       updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_nameserver_reputation_score ON nameserver_reputation (score);
+
+-- Record-set checksum per domain, record type and source, written when CZDS
+-- ingests a zone and when the query worker resolves a domain. A set takes the
+-- next record_set_version_seq value whenever its checksum changes, so
+-- downstream caches can compare versions instead of records. CZDS sets
+-- dropped from their zone are tombstoned by the next ingest of it, which
+-- reports their domains as removed in its zone delta; the rows are kept so
+-- that the set's versions keep increasing if it returns. On existing
+-- databases, run
+--   ALTER TABLE record_set_checksums ADD COLUMN IF NOT EXISTS removed_at TIMESTAMP;
+-- before deploying the czds worker.
+CREATE SEQUENCE record_set_version_seq;
+
+CREATE TABLE record_set_checksums (
+                                      domain_id INTEGER NOT NULL REFERENCES domains(id),
+                                      record_type VARCHAR(20) NOT NULL,
+                                      source VARCHAR(20) NOT NULL, -- CZDS or QUERY
+                                      checksum CHAR(64) NOT NULL, -- Hex SHA-256 of the canonical record set (TTLs ignored)
+                                      record_count INTEGER NOT NULL,
+                                      version BIGINT NOT NULL DEFAULT nextval('record_set_version_seq'), -- Increases each time checksum changes
+                                      computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                      records TEXT[], -- QUERY only: the canonical set, to classify its next change
+                                      ttl INTEGER, -- QUERY only: lowest TTL of the set
+                                      removed_at TIMESTAMP, -- CZDS only: when the set was dropped from its zone; NULL while it is in it
+                                      PRIMARY KEY (domain_id, record_type, source)
+);
+
+-- Changes of the record sets the query worker resolves, classified by
+-- significance (see recordset.Classify), so GetDomainLifecycle can list them
+-- and consumers can skip cosmetic ones. On existing databases, add records
+-- and ttl to record_set_checksums and significance to
+-- domain_lifecycle_events; changes of sets stored before are UNCLASSIFIED.
+CREATE TABLE record_set_changes (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    domain_id INTEGER NOT NULL REFERENCES domains(id),
+                                    record_type VARCHAR(20) NOT NULL,
+                                    version BIGINT NOT NULL, -- Record-set version after the change
+                                    significance VARCHAR(20) NOT NULL, -- COSMETIC, MINOR, ASN_CHANGED, NS_REPLACED, MX_PROVIDER_CHANGED or UNCLASSIFIED; first observations are not stored
+                                    old_records TEXT[], -- Canonical records before the change; NULL if UNCLASSIFIED
+                                    records TEXT[] NOT NULL,
+                                    old_ttl INTEGER,
+                                    ttl INTEGER,
+                                    observed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_record_set_changes_domain_id ON record_set_changes (domain_id, observed_at);
+
+-- Versions of dns_records rows that a later observation replaced in place,
+-- with other data formatting or another TTL, so GetRecordHistory can return
+-- the timeline of a record type. Every writer upserts through
+-- recordset.OnRecordConflict, so they are kept by a trigger rather than by
+-- each writer. Only observations, which move last_updated forward, replace
+-- a version; analytics jobs that rewrite rows (normalize-records,
+-- compress-records) keep last_updated.
+CREATE TABLE record_history (
+                                id BIGSERIAL PRIMARY KEY,
+                                domain_id INTEGER NOT NULL REFERENCES domains(id),
+                                record_type VARCHAR(20) NOT NULL,
+                                source VARCHAR(20) NOT NULL,
+                                canonical_hash BYTEA NOT NULL,
+                                record_data TEXT NOT NULL,
+                                record_data_z BYTEA, -- As in dns_records
+                                ttl INTEGER,
+                                first_seen TIMESTAMP NOT NULL, -- First observation of this data and TTL: the record's first_seen, or when it last replaced a version
+                                last_seen TIMESTAMP, -- Latest observation of this data and TTL
+                                replaced_at TIMESTAMP NOT NULL -- Observation that replaced it (the record's new last_updated)
+);
+
+CREATE INDEX idx_record_history_record ON record_history (domain_id, record_type, source, canonical_hash, replaced_at);
+
+CREATE FUNCTION keep_record_history() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO record_history (domain_id, record_type, source, canonical_hash, record_data, record_data_z, ttl, first_seen, last_seen, replaced_at)
+    VALUES (OLD.domain_id, OLD.record_type, OLD.source, OLD.canonical_hash, OLD.record_data, OLD.record_data_z, OLD.ttl,
+            COALESCE((SELECT MAX(replaced_at) FROM record_history
+                      WHERE domain_id = OLD.domain_id AND record_type = OLD.record_type
+                        AND source = OLD.source AND canonical_hash = OLD.canonical_hash), OLD.first_seen),
+            OLD.last_updated, NEW.last_updated);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER dns_records_keep_history AFTER UPDATE ON dns_records
+    FOR EACH ROW
+    WHEN (NEW.last_updated > OLD.last_updated AND (OLD.record_data IS DISTINCT FROM NEW.record_data
+        OR OLD.record_data_z IS DISTINCT FROM NEW.record_data_z OR OLD.ttl IS DISTINCT FROM NEW.ttl))
+    EXECUTE FUNCTION keep_record_history();
+
+-- Requests and returned records per API key, day, RPC and TLD, counted by
+-- the server for billing exports (analytics -job billing-export), monthly key
+-- quotas and GetUsage
+CREATE TABLE usage_counts (
+                              api_key VARCHAR(255) NOT NULL,
+                              day DATE NOT NULL, -- UTC
+                              rpc VARCHAR(100) NOT NULL, -- e.g. GetRecords
+                              tld VARCHAR(63) NOT NULL DEFAULT '', -- Empty for RPCs not scoped to a domain or TLD
+                              requests BIGINT NOT NULL DEFAULT 0,
+                              errors BIGINT NOT NULL DEFAULT 0, -- Requests rejected as invalid, not found, etc.
+                              records BIGINT NOT NULL DEFAULT 0, -- DNS records returned; a call naming several TLDs counts them under the first
+                              PRIMARY KEY (api_key, day, rpc, tld)
+);
+
+CREATE INDEX idx_usage_counts_day ON usage_counts (day);
+
+-- PTR records from reverse zones (in-addr.arpa, ip6.arpa), keyed by address
+-- rather than stored as domains. Lives on the shard owning the arpa TLD.
+CREATE TABLE ptr_records (
+                             ip INET NOT NULL,
+                             ptr_name TEXT NOT NULL, -- Target host name without the trailing dot
+                             zone VARCHAR(255) NOT NULL, -- Reverse zone the record was ingested from, e.g. 2.0.192.in-addr.arpa
+                             ttl INTEGER,
+                             source VARCHAR(20) NOT NULL DEFAULT 'CZDS',
+                             last_updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                             PRIMARY KEY (ip, ptr_name, source)
+);
+
+-- Range scans for GetPTRRange (ip <<= cidr)
+CREATE INDEX idx_ptr_records_ip_range ON ptr_records USING GIST (ip inet_ops);
+-- Addresses pointing at a host name
+CREATE INDEX idx_ptr_records_ptr_name ON ptr_records (ptr_name);
+
+-- Keywords in the labels of newly seen domains per day and TLD, computed by
+-- analytics -job keyword-trends. Domains of TLDs whose zone was first
+-- ingested on or after the day are not new registrations and are left out.
+-- Keywords below analytics.keyword_min_count domains on a day are not stored.
+CREATE TABLE keyword_trends (
+                                day DATE NOT NULL, -- UTC day the domains were first seen
+                                tld VARCHAR(50) NOT NULL, -- Empty for all TLDs
+                                keyword VARCHAR(63) NOT NULL,
+                                domain_count INTEGER NOT NULL,
+                                computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                PRIMARY KEY (day, tld, keyword)
+);
+
+CREATE INDEX idx_keyword_trends_keyword ON keyword_trends (tld, keyword, day);
+
+-- Signed delegations per day and TLD, computed by analytics -job
+-- dnssec-adoption. delegations counts domains with nameservers; signed
+-- counts those of them with DS records.
+CREATE TABLE dnssec_adoption (
+                                 day DATE NOT NULL, -- UTC day of the run
+                                 tld VARCHAR(50) NOT NULL, -- Empty for all TLDs
+                                 delegations BIGINT NOT NULL,
+                                 signed BIGINT NOT NULL,
+                                 computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                 PRIMARY KEY (day, tld)
+);
+
+CREATE INDEX idx_dnssec_adoption_tld ON dnssec_adoption (tld, day);
+
+-- DNSSEC algorithms and key sizes in use per day and TLD, from DNSKEY
+-- records or, for domains without one, DS records (key_size 0)
+CREATE TABLE dnssec_algorithms (
+                                   day DATE NOT NULL,
+                                   tld VARCHAR(50) NOT NULL, -- Empty for all TLDs
+                                   algorithm SMALLINT NOT NULL, -- IANA DNSSEC algorithm number, e.g. 13 for ECDSAP256SHA256
+                                   key_size INTEGER NOT NULL, -- Bits; 0 if unknown
+                                   domain_count BIGINT NOT NULL,
+                                   PRIMARY KEY (day, tld, algorithm, key_size)
+);
+
+-- Domains sharing infrastructure, computed by analytics -job
+-- domain-clusters: public domains with the same nameserver set and A
+-- records, first seen within analytics.cluster_window_hours of the
+-- previous one. Each run replaces every cluster. A cluster keeps its id
+-- across runs while its first domain and infrastructure do not change.
+CREATE TABLE domain_clusters (
+                                 cluster_id VARCHAR(16) PRIMARY KEY, -- Hex
+                                 nameservers TEXT[] NOT NULL, -- Sorted
+                                 addresses TEXT[] NOT NULL, -- Sorted A record addresses
+                                 domain_count INTEGER NOT NULL,
+                                 first_seen_from TIMESTAMP NOT NULL, -- Earliest first_seen of its domains
+                                 first_seen_to TIMESTAMP NOT NULL, -- Latest
+                                 computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_domain_clusters_first_seen ON domain_clusters (first_seen_to DESC);
+
+CREATE TABLE domain_cluster_members (
+                                        cluster_id VARCHAR(16) NOT NULL REFERENCES domain_clusters (cluster_id) ON DELETE CASCADE,
+                                        domain_name VARCHAR(255) NOT NULL,
+                                        first_seen TIMESTAMP NOT NULL,
+                                        PRIMARY KEY (cluster_id, domain_name)
+);
+
+CREATE INDEX idx_domain_cluster_members_domain ON domain_cluster_members (domain_name);
+
+-- Record activity per domain and UTC day, rolled up by analytics -job
+-- activity-rollups for GetRecordActivity: records last observed on the day
+-- (a record re-observed before the job ran after the day counts on its
+-- later day only), first seen on it, and replaced on it by other data or
+-- TTL (see record_history). Days without activity have no row.
+CREATE TABLE record_activity_daily (
+                                       domain_name VARCHAR(255) NOT NULL,
+                                       day DATE NOT NULL,
+                                       observed INTEGER NOT NULL,
+                                       added INTEGER NOT NULL,
+                                       changed INTEGER NOT NULL,
+                                       computed_at TIMESTAMP NOT NULL,
+                                       PRIMARY KEY (domain_name, day)
+);
+
+CREATE INDEX idx_record_activity_daily_day ON record_activity_daily (day);
+
+-- Public domains first seen per apex (the label left of the public suffix)
+-- and ISO week, rolled up by analytics -job activity-rollups for
+-- GetSubdomainActivity. Weeks without new subdomains have no row.
+CREATE TABLE subdomain_activity_weekly (
+                                           apex VARCHAR(255) NOT NULL,
+                                           week DATE NOT NULL, -- Monday (UTC)
+                                           new_subdomains INTEGER NOT NULL,
+                                           computed_at TIMESTAMP NOT NULL,
+                                           PRIMARY KEY (apex, week)
+);
+
+CREATE INDEX idx_subdomain_activity_weekly_week ON subdomain_activity_weekly (week);
@@ -0,0 +1,388 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// rdapClient looks up registrar and abuse contacts for domains over RDAP,
+// using the IANA bootstrap file to find each TLD's registry server.
+type rdapClient struct {
+	httpClient   *http.Client
+	bootstrapURL string
+
+	mu       sync.Mutex
+	servers  map[string]string // TLD -> registry RDAP base URL
+	loadedAt time.Time
+}
+
+// rdapBootstrapTTL is how long the IANA bootstrap file is reused.
+const rdapBootstrapTTL = 24 * time.Hour
+
+func newRDAPClient(bootstrapURL string, timeout time.Duration) *rdapClient {
+	return &rdapClient{httpClient: &http.Client{Timeout: timeout}, bootstrapURL: bootstrapURL}
+}
+
+// rdapEntity is the subset of an RDAP entity object used for contacts.
+type rdapEntity struct {
+	Roles     []string          `json:"roles"`
+	VCard     []json.RawMessage `json:"vcardArray"`
+	PublicIDs []struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	} `json:"publicIds"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+// rdapDomain is the subset of an RDAP domain response used for contacts
+// and transfer detection.
+type rdapDomain struct {
+	Entities []rdapEntity `json:"entities"`
+	Links    []struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+		Type string `json:"type"`
+	} `json:"links"`
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+}
+
+// rdapRegistration is what the RDAP lookup of a domain reports.
+type rdapRegistration struct {
+	registrarID   int32 // 0 if no IANA registrar is named
+	registrarName string
+	contacts      []*pb.AbuseContact
+	transferred   time.Time // Latest transfer event; zero if the registry reports none
+}
+
+// registryServer returns the registry RDAP base URL for a domain's TLD,
+// reloading the bootstrap file when it is older than rdapBootstrapTTL.
+func (c *rdapClient) registryServer(ctx context.Context, domain string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.servers == nil || time.Since(c.loadedAt) > rdapBootstrapTTL {
+		var bootstrap struct {
+			Services [][][]string `json:"services"`
+		}
+		if err := c.getJSON(ctx, c.bootstrapURL, &bootstrap); err != nil {
+			return "", fmt.Errorf("failed to load RDAP bootstrap: %v", err)
+		}
+		servers := make(map[string]string)
+		for _, svc := range bootstrap.Services {
+			if len(svc) < 2 || len(svc[1]) == 0 {
+				continue
+			}
+			for _, tld := range svc[0] {
+				servers[strings.ToLower(tld)] = svc[1][0]
+			}
+		}
+		c.servers, c.loadedAt = servers, time.Now()
+	}
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	base, ok := c.servers[tld]
+	if !ok {
+		return "", fmt.Errorf("no RDAP server for TLD %s", tld)
+	}
+	return base, nil
+}
+
+// lookupDomain fetches the RDAP domain object for domain from base.
+func (c *rdapClient) lookupDomain(ctx context.Context, base, domain string) (*rdapDomain, string, error) {
+	url := strings.TrimSuffix(base, "/") + "/domain/" + domain
+	var resp rdapDomain
+	if err := c.getJSON(ctx, url, &resp); err != nil {
+		return nil, url, err
+	}
+	return &resp, url, nil
+}
+
+func (c *rdapClient) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// registrar returns the IANA registrar ID and name from the registrar
+// entity of an RDAP domain response, or 0 if none is named.
+func (d *rdapDomain) registrar() (int32, string) {
+	for _, e := range d.Entities {
+		if !hasRole(e.Roles, "registrar") {
+			continue
+		}
+		var id int32
+		for _, pid := range e.PublicIDs {
+			if pid.Type == "IANA Registrar ID" {
+				fmt.Sscanf(pid.Identifier, "%d", &id)
+			}
+		}
+		name, _, _ := vcardContact(e.VCard)
+		return id, name
+	}
+	return 0, ""
+}
+
+// abuseContacts returns the abuse contacts in an RDAP domain response:
+// abuse entities under the registrar entity (role "registrar") and abuse
+// entities at the top level (role "registry").
+func (d *rdapDomain) abuseContacts(sourceURL string) []*pb.AbuseContact {
+	var registrar, registry []*pb.AbuseContact
+	for _, e := range d.Entities {
+		if hasRole(e.Roles, "registrar") {
+			for _, sub := range e.Entities {
+				if hasRole(sub.Roles, "abuse") {
+					_, email, phone := vcardContact(sub.VCard)
+					registrar = append(registrar, &pb.AbuseContact{Role: "registrar", Email: email, Phone: phone, SourceUrl: sourceURL})
+				}
+			}
+		}
+		if hasRole(e.Roles, "abuse") {
+			_, email, phone := vcardContact(e.VCard)
+			registry = append(registry, &pb.AbuseContact{Role: "registry", Email: email, Phone: phone, SourceUrl: sourceURL})
+		}
+	}
+	return append(registrar, registry...)
+}
+
+// lastTransfer returns the date of the latest transfer event in an RDAP
+// domain response, or the zero time if there is none.
+func (d *rdapDomain) lastTransfer() time.Time {
+	var last time.Time
+	for _, e := range d.Events {
+		if e.Action != "transfer" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, e.Date); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// relatedURL returns the registrar's RDAP record for the domain, if the
+// registry links to one (thin registries such as .com do).
+func (d *rdapDomain) relatedURL() string {
+	for _, l := range d.Links {
+		if l.Rel == "related" && strings.Contains(l.Type, "rdap+json") {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// vcardContact extracts the formatted name, email, and voice phone from a
+// jCard (["vcard", [[name, params, type, value], ...]]).
+func vcardContact(vcard []json.RawMessage) (name, email, phone string) {
+	if len(vcard) < 2 {
+		return "", "", ""
+	}
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(vcard[1], &props); err != nil {
+		return "", "", ""
+	}
+	for _, p := range props {
+		if len(p) < 4 {
+			continue
+		}
+		var prop, value string
+		json.Unmarshal(p[0], &prop)
+		if json.Unmarshal(p[3], &value) != nil {
+			continue
+		}
+		switch prop {
+		case "fn":
+			name = value
+		case "email":
+			email = value
+		case "tel":
+			phone = strings.TrimPrefix(value, "tel:")
+		}
+	}
+	return name, email, phone
+}
+
+// GetAbuseContacts returns the registrar and abuse contacts for a domain.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Contacts
+// are looked up over RDAP and cached in domain_abuse_contacts for
+// rdap.cache_hours; set refresh to bypass the cache, which requires the
+// write:ingest scope. The registrar is enriched from the IANA registrar
+// registry loaded by the analytics job. Domains restricted to other
+// organizations are not found.
+func (s *server) GetAbuseContacts(ctx context.Context, req *pb.GetAbuseContactsRequest) (*pb.GetAbuseContactsResponse, error) {
+	domain := strings.TrimSuffix(strings.ToLower(req.Domain), ".")
+	if !strings.Contains(domain, ".") {
+		return nil, status.Errorf(codes.InvalidArgument, "a domain name is required")
+	}
+	prefs, err := s.keyPreferences(ctx, "GetAbuseContacts", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	visible, err := s.domainVisible(ctx, "GetAbuseContacts", prefs, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, statusError(codes.NotFound, reasonDomainNotFound, map[string]string{"domain": domain}, "domain %s not found", domain)
+	}
+
+	resp := &pb.GetAbuseContactsResponse{Domain: domain, Registrar: &pb.Registrar{}}
+	var registrarID sql.NullInt32
+	var registrarName sql.NullString
+	var contacts []byte
+	var fetchedAt time.Time
+	err = s.db.QueryRowContext(ctx, `
+		SELECT registrar_iana_id, registrar_name, contacts, fetched_at
+		FROM domain_abuse_contacts
+		WHERE domain_name = $1
+	`, domain).Scan(&registrarID, &registrarName, &contacts, &fetchedAt)
+	if err != nil && err != sql.ErrNoRows {
+		errorf("GetAbuseContacts: Failed to query cached contacts for %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query abuse contacts: %v", err)
+	}
+
+	if err == sql.ErrNoRows || req.Refresh || time.Since(fetchedAt) > s.rdapCacheTTL {
+		cached := err == nil
+		reg, err := s.fetchRegistration(ctx, domain)
+		if err != nil {
+			errorf("GetAbuseContacts: RDAP lookup failed for %s: %v", domain, err)
+			return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"retry_after": upstreamRetryAfter}, "RDAP lookup failed: %v", err)
+		}
+		resp.Registrar.IanaId, resp.Registrar.Name, resp.Contacts = reg.registrarID, reg.registrarName, reg.contacts
+		fetchedAt = time.Now().UTC()
+		if err := s.cacheRegistration(ctx, domain, reg, fetchedAt); err != nil {
+			errorf("GetAbuseContacts: Failed to cache contacts for %s: %v", domain, err)
+		}
+		// A refresh naming another registrar than the cached lookup is a transfer
+		if cached && registrarChanged(registrarID.Int32, registrarName.String, reg.registrarID, reg.registrarName) {
+			err := s.recordTransfer(ctx, &transfer{
+				domain:           domain,
+				oldRegistrarID:   registrarID.Int32,
+				oldRegistrarName: registrarName.String,
+				registrarID:      reg.registrarID,
+				registrarName:    reg.registrarName,
+				confidence:       "CONFIRMED",
+				evidence:         []string{evidenceRegistrarChanged},
+			})
+			if err != nil {
+				errorf("GetAbuseContacts: Failed to record transfer of %s: %v", domain, err)
+			}
+		}
+	} else {
+		resp.Registrar.IanaId, resp.Registrar.Name = registrarID.Int32, registrarName.String
+		if err := json.Unmarshal(contacts, &resp.Contacts); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to decode cached abuse contacts: %v", err)
+		}
+	}
+	resp.FetchedAt = fetchedAt.Format(time.RFC3339)
+
+	// Enrich the registrar from the IANA registry
+	if resp.Registrar.IanaId != 0 {
+		var name, regStatus string
+		var rdapURL sql.NullString
+		err := s.db.QueryRowContext(ctx,
+			"SELECT name, status, rdap_url FROM iana_registrars WHERE iana_id = $1",
+			resp.Registrar.IanaId).Scan(&name, &regStatus, &rdapURL)
+		if err != nil && err != sql.ErrNoRows {
+			errorf("GetAbuseContacts: Failed to query IANA registrar %d: %v", resp.Registrar.IanaId, err)
+			return nil, status.Errorf(codes.Internal, "failed to query registrar: %v", err)
+		}
+		if err == nil {
+			if resp.Registrar.Name == "" {
+				resp.Registrar.Name = name
+			}
+			resp.Registrar.Status, resp.Registrar.RdapUrl = regStatus, rdapURL.String
+		}
+	}
+	infof("GetAbuseContacts: Response for domain %s: registrar %d, %d contacts", domain, resp.Registrar.IanaId, len(resp.Contacts))
+	return resp, nil
+}
+
+// fetchRegistration queries the registry RDAP server for domain and, if it
+// names no registrar abuse contact, the registrar RDAP record it links to.
+func (s *server) fetchRegistration(ctx context.Context, domain string) (*rdapRegistration, error) {
+	base, err := s.rdap.registryServer(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	registry, url, err := s.rdap.lookupDomain(ctx, base, domain)
+	if err != nil {
+		return nil, err
+	}
+	reg := &rdapRegistration{contacts: registry.abuseContacts(url), transferred: registry.lastTransfer()}
+	reg.registrarID, reg.registrarName = registry.registrar()
+	if len(reg.contacts) > 0 && reg.contacts[0].Role == "registrar" {
+		return reg, nil
+	}
+
+	// Thin registries leave registrar contacts to the registrar's own RDAP server
+	related := registry.relatedURL()
+	if related == "" {
+		return reg, nil
+	}
+	var registrar rdapDomain
+	if err := s.rdap.getJSON(ctx, related, &registrar); err != nil {
+		debugf("GetAbuseContacts: Registrar RDAP lookup failed for %s: %v", domain, err)
+		return reg, nil
+	}
+	var fromRegistrar []*pb.AbuseContact
+	for _, c := range registrar.abuseContacts(related) {
+		if c.Role == "registrar" {
+			fromRegistrar = append(fromRegistrar, c)
+		}
+	}
+	if t := registrar.lastTransfer(); t.After(reg.transferred) {
+		reg.transferred = t
+	}
+	reg.contacts = append(fromRegistrar, reg.contacts...)
+	return reg, nil
+}
+
+// cacheRegistration stores a domain's RDAP registrar and contacts in
+// domain_abuse_contacts.
+func (s *server) cacheRegistration(ctx context.Context, domain string, reg *rdapRegistration, fetchedAt time.Time) error {
+	contacts, err := json.Marshal(reg.contacts)
+	if err != nil {
+		return fmt.Errorf("failed to encode abuse contacts: %v", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO domain_abuse_contacts (domain_name, registrar_iana_id, registrar_name, contacts, fetched_at)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5)
+		ON CONFLICT (domain_name) DO UPDATE
+		SET registrar_iana_id = EXCLUDED.registrar_iana_id, registrar_name = EXCLUDED.registrar_name,
+		    contacts = EXCLUDED.contacts, fetched_at = EXCLUDED.fetched_at
+	`, domain, reg.registrarID, reg.registrarName, contacts, fetchedAt)
+	return err
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// Activity request defaults and limits.
+const (
+	defaultActivityDays  = 30
+	maxActivityDays      = 365
+	defaultActivityWeeks = 12
+	maxActivityWeeks     = 104
+)
+
+// GetRecordActivity returns the records of a domain observed, first seen and
+// changed on each of the requested days, as rolled up by the
+// activity-rollups analytics job, so dashboards chart activity without
+// scanning records. The series ends at the latest rolled-up day and has a
+// point for every day; a hidden domain answers as an unknown one, with
+// zeros.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetRecordActivity(ctx context.Context, req *pb.GetRecordActivityRequest) (*pb.GetRecordActivityResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetRecordActivity", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Domain), "."))
+	if domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "domain is required")
+	}
+	days := int(req.Days)
+	if days <= 0 {
+		days = defaultActivityDays
+	}
+	if days > maxActivityDays {
+		return nil, status.Errorf(codes.InvalidArgument, "days must be at most %d", maxActivityDays)
+	}
+
+	resp := &pb.GetRecordActivityResponse{Domain: domain}
+	var latest, computedAt time.Time
+	err = s.db.QueryRowContext(ctx, "SELECT day, computed_at FROM record_activity_daily ORDER BY day DESC LIMIT 1").Scan(&latest, &computedAt)
+	if err == sql.ErrNoRows {
+		return resp, nil
+	}
+	if err != nil {
+		errorf("GetRecordActivity: Failed to query the latest rolled-up day: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query record activity: %v", err)
+	}
+	resp.ComputedAt = computedAt.Format(time.RFC3339)
+	first := latest.AddDate(0, 0, 1-days)
+	for day := first; !day.After(latest); day = day.AddDate(0, 0, 1) {
+		resp.Series = append(resp.Series, &pb.RecordActivityPoint{Day: day.Format("2006-01-02")})
+	}
+
+	visible, err := s.domainVisible(ctx, "GetRecordActivity", prefs, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return resp, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, observed, added, changed
+		FROM record_activity_daily
+		WHERE domain_name = $1 AND day BETWEEN $2 AND $3
+	`, domain, first, latest)
+	if err != nil {
+		errorf("GetRecordActivity: Failed to query activity of %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query record activity: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var day time.Time
+		var observed, added, changed int64
+		if err := rows.Scan(&day, &observed, &added, &changed); err != nil {
+			errorf("GetRecordActivity: Failed to scan activity of %s: %v", domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan record activity: %v", err)
+		}
+		p := resp.Series[int(day.Sub(first).Hours()/24)]
+		p.Observed, p.Added, p.Changed = observed, added, changed
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetRecordActivity: Failed to iterate activity of %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate record activity: %v", err)
+	}
+	infof("GetRecordActivity: Response for %s: %d days to %s", domain, len(resp.Series), latest.Format("2006-01-02"))
+	return resp, nil
+}
+
+// GetSubdomainActivity returns the public subdomains first seen under an
+// apex in each of the requested weeks, as rolled up by the activity-rollups
+// analytics job. The series ends at the latest rolled-up week and has a
+// point for every week.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetSubdomainActivity(ctx context.Context, req *pb.GetSubdomainActivityRequest) (*pb.GetSubdomainActivityResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if _, err := s.keyPreferences(ctx, "GetSubdomainActivity", apiKey); err != nil {
+		return nil, err
+	}
+	apex := strings.ToLower(strings.Trim(strings.TrimPrefix(strings.TrimSpace(req.Apex), "*."), "."))
+	if apex == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "apex is required")
+	}
+	if strings.ContainsAny(apex, "*?") {
+		return nil, status.Errorf(codes.InvalidArgument, "apex %q must be a domain name", req.Apex)
+	}
+	weeks := int(req.Weeks)
+	if weeks <= 0 {
+		weeks = defaultActivityWeeks
+	}
+	if weeks > maxActivityWeeks {
+		return nil, status.Errorf(codes.InvalidArgument, "weeks must be at most %d", maxActivityWeeks)
+	}
+
+	resp := &pb.GetSubdomainActivityResponse{Apex: apex}
+	var latest, computedAt time.Time
+	err := s.db.QueryRowContext(ctx, "SELECT week, computed_at FROM subdomain_activity_weekly ORDER BY week DESC LIMIT 1").Scan(&latest, &computedAt)
+	if err == sql.ErrNoRows {
+		return resp, nil
+	}
+	if err != nil {
+		errorf("GetSubdomainActivity: Failed to query the latest rolled-up week: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query subdomain activity: %v", err)
+	}
+	resp.ComputedAt = computedAt.Format(time.RFC3339)
+	first := latest.AddDate(0, 0, 7*(1-weeks))
+	for week := first; !week.After(latest); week = week.AddDate(0, 0, 7) {
+		resp.Series = append(resp.Series, &pb.SubdomainActivityPoint{Week: week.Format("2006-01-02")})
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT week, new_subdomains
+		FROM subdomain_activity_weekly
+		WHERE apex = $1 AND week BETWEEN $2 AND $3
+	`, apex, first, latest)
+	if err != nil {
+		errorf("GetSubdomainActivity: Failed to query activity under %s: %v", apex, err)
+		return nil, status.Errorf(codes.Internal, "failed to query subdomain activity: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var week time.Time
+		var count int64
+		if err := rows.Scan(&week, &count); err != nil {
+			errorf("GetSubdomainActivity: Failed to scan activity under %s: %v", apex, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan subdomain activity: %v", err)
+		}
+		resp.Series[int(week.Sub(first).Hours()/(24*7))].NewSubdomains = count
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetSubdomainActivity: Failed to iterate activity under %s: %v", apex, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate subdomain activity: %v", err)
+	}
+	infof("GetSubdomainActivity: Response for %s: %d weeks to %s", apex, len(resp.Series), latest.Format("2006-01-02"))
+	return resp, nil
+}
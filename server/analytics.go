@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// topNMetricNames maps TopNMetric values to analytics_top.metric.
+var topNMetricNames = map[pb.TopNMetric]string{
+	pb.TopNMetric_TOP_N_METRIC_NAMESERVERS:  "NAMESERVERS",
+	pb.TopNMetric_TOP_N_METRIC_MX_PROVIDERS: "MX_PROVIDERS",
+	pb.TopNMetric_TOP_N_METRIC_ASNS:         "ASNS",
+}
+
+// GetTopN returns a ranking precomputed by the analytics job.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Rankings
+// are global unless a TLD is given.
+func (s *server) GetTopN(ctx context.Context, req *pb.GetTopNRequest) (*pb.GetTopNResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetTopN", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	metric, ok := topNMetricNames[req.Metric]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported metric %s", req.Metric)
+	}
+	if req.Limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not be negative")
+	}
+	limit := prefs.limit(req.Limit)
+
+	query := storage.NewQuery(`
+		SELECT rank, key, label, domain_count, computed_at
+		FROM analytics_top
+	`).Where("metric = ?", metric).Where("tld = ?", req.Tld).Append("ORDER BY rank")
+	if limit > 0 {
+		query.Append("LIMIT ?", limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query.SQL(), query.Args()...)
+	if err != nil {
+		errorf("GetTopN: Failed to query %s for TLD %q: %v", metric, req.Tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query rankings: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.GetTopNResponse{}
+	var computedAt time.Time
+	for rows.Next() {
+		var e pb.TopNEntry
+		if err := rows.Scan(&e.Rank, &e.Key, &e.Label, &e.DomainCount, &computedAt); err != nil {
+			errorf("GetTopN: Failed to scan %s entry: %v", metric, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan ranking: %v", err)
+		}
+		resp.Entries = append(resp.Entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetTopN: Failed to iterate %s entries: %v", metric, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate rankings: %v", err)
+	}
+	if len(resp.Entries) > 0 {
+		resp.ComputedAt = computedAt.Format(time.RFC3339)
+	}
+	infof("GetTopN: Response for %s TLD %q: %d entries", metric, req.Tld, len(resp.Entries))
+	return resp, nil
+}
+
+// Keyword trend request defaults and limits.
+const (
+	defaultKeywordBaselineDays = 7
+	maxKeywordBaselineDays     = 90
+	defaultKeywordLimit        = 50
+)
+
+// GetKeywordTrends returns the keywords of newly seen domain labels on a day,
+// scored against their average over the preceding baseline days, as
+// computed by the keyword-trends analytics job.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Keywords
+// are global unless a TLD is given.
+func (s *server) GetKeywordTrends(ctx context.Context, req *pb.GetKeywordTrendsRequest) (*pb.GetKeywordTrendsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetKeywordTrends", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+	baselineDays := int(req.BaselineDays)
+	if baselineDays <= 0 {
+		baselineDays = defaultKeywordBaselineDays
+	}
+	if baselineDays > maxKeywordBaselineDays {
+		return nil, status.Errorf(codes.InvalidArgument, "baseline_days must be at most %d", maxKeywordBaselineDays)
+	}
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 {
+		limit = defaultKeywordLimit
+	}
+
+	var day time.Time
+	if req.Day != "" {
+		if day, err = time.Parse("2006-01-02", req.Day); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid day %q; expected YYYY-MM-DD", req.Day)
+		}
+	} else {
+		var latest sql.NullTime
+		if err := s.db.QueryRowContext(ctx, "SELECT MAX(day) FROM keyword_trends WHERE tld = $1", tld).Scan(&latest); err != nil {
+			errorf("GetKeywordTrends: Failed to find latest day for TLD %q: %v", tld, err)
+			return nil, status.Errorf(codes.Internal, "failed to find latest day: %v", err)
+		}
+		if !latest.Valid {
+			return &pb.GetKeywordTrendsResponse{Tld: tld}, nil
+		}
+		day = latest.Time
+	}
+
+	// Days below the job's minimum count have no row and count as zero
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT t.keyword, t.domain_count, t.computed_at,
+			COALESCE(SUM(h.domain_count), 0)::float8 / $3::int AS baseline
+		FROM keyword_trends t
+		LEFT JOIN keyword_trends h
+			ON h.tld = t.tld AND h.keyword = t.keyword
+			AND h.day >= t.day - $3::int AND h.day < t.day
+		WHERE t.day = $1 AND t.tld = $2
+		GROUP BY t.keyword, t.domain_count, t.computed_at
+		ORDER BY (t.domain_count + 1) / (COALESCE(SUM(h.domain_count), 0)::float8 / $3::int + 1) DESC, t.keyword
+		LIMIT $4
+	`, day, tld, baselineDays, limit)
+	if err != nil {
+		errorf("GetKeywordTrends: Failed to query keywords for TLD %q: %v", tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query keyword trends: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.GetKeywordTrendsResponse{Day: day.Format("2006-01-02"), Tld: tld}
+	var computedAt time.Time
+	for rows.Next() {
+		var t pb.KeywordTrend
+		if err := rows.Scan(&t.Keyword, &t.DomainCount, &computedAt, &t.Baseline); err != nil {
+			errorf("GetKeywordTrends: Failed to scan keyword: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan keyword trend: %v", err)
+		}
+		t.Score = float64(t.DomainCount+1) / (t.Baseline + 1)
+		resp.Trends = append(resp.Trends, &t)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetKeywordTrends: Failed to iterate keywords: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate keyword trends: %v", err)
+	}
+	if len(resp.Trends) > 0 {
+		resp.ComputedAt = computedAt.Format(time.RFC3339)
+	}
+	infof("GetKeywordTrends: Response for %s TLD %q: %d keywords", resp.Day, tld, len(resp.Trends))
+	return resp, nil
+}
+
+// DNSSEC adoption request defaults and limits.
+const (
+	defaultDNSSECDays = 30
+	maxDNSSECDays     = 365
+)
+
+// GetDNSSECAdoption returns the daily share of signed delegations over the
+// requested days, and the algorithms and key sizes in use on the latest day,
+// as computed by the dnssec-adoption analytics job.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Adoption
+// is global unless a TLD is given.
+func (s *server) GetDNSSECAdoption(ctx context.Context, req *pb.GetDNSSECAdoptionRequest) (*pb.GetDNSSECAdoptionResponse, error) {
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+	days := int(req.Days)
+	if days <= 0 {
+		days = defaultDNSSECDays
+	}
+	if days > maxDNSSECDays {
+		return nil, status.Errorf(codes.InvalidArgument, "days must be at most %d", maxDNSSECDays)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, delegations, signed, computed_at
+		FROM dnssec_adoption
+		WHERE tld = $1 AND day > (SELECT MAX(day) FROM dnssec_adoption WHERE tld = $1) - $2::int
+		ORDER BY day
+	`, tld, days)
+	if err != nil {
+		errorf("GetDNSSECAdoption: Failed to query adoption for TLD %q: %v", tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query DNSSEC adoption: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.GetDNSSECAdoptionResponse{Tld: tld}
+	var latest, computedAt time.Time
+	for rows.Next() {
+		var p pb.DNSSECAdoptionPoint
+		if err := rows.Scan(&latest, &p.Delegations, &p.Signed, &computedAt); err != nil {
+			errorf("GetDNSSECAdoption: Failed to scan adoption: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan DNSSEC adoption: %v", err)
+		}
+		p.Day = latest.Format("2006-01-02")
+		if p.Delegations > 0 {
+			p.SignedFraction = float64(p.Signed) / float64(p.Delegations)
+		}
+		resp.Series = append(resp.Series, &p)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetDNSSECAdoption: Failed to iterate adoption: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate DNSSEC adoption: %v", err)
+	}
+	if len(resp.Series) == 0 {
+		return resp, nil
+	}
+	resp.ComputedAt = computedAt.Format(time.RFC3339)
+
+	algorithms, err := s.db.QueryContext(ctx, `
+		SELECT algorithm, key_size, domain_count
+		FROM dnssec_algorithms
+		WHERE day = $1 AND tld = $2
+		ORDER BY domain_count DESC, algorithm, key_size
+	`, latest, tld)
+	if err != nil {
+		errorf("GetDNSSECAdoption: Failed to query algorithms for TLD %q: %v", tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query DNSSEC algorithms: %v", err)
+	}
+	defer algorithms.Close()
+	for algorithms.Next() {
+		var a pb.DNSSECAlgorithmUsage
+		if err := algorithms.Scan(&a.Algorithm, &a.KeySize, &a.DomainCount); err != nil {
+			errorf("GetDNSSECAdoption: Failed to scan algorithm: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan DNSSEC algorithm: %v", err)
+		}
+		a.AlgorithmName = dns.AlgorithmToString[uint8(a.Algorithm)]
+		resp.Algorithms = append(resp.Algorithms, &a)
+	}
+	if err := algorithms.Err(); err != nil {
+		errorf("GetDNSSECAdoption: Failed to iterate algorithms: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate DNSSEC algorithms: %v", err)
+	}
+	infof("GetDNSSECAdoption: Response for TLD %q: %d days, %d algorithms", tld, len(resp.Series), len(resp.Algorithms))
+	return resp, nil
+}
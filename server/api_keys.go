@@ -0,0 +1,309 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// maxRoleLength is the size of api_keys.role.
+const maxRoleLength = 50
+
+// checkScopes returns an InvalidArgument error if scopes has one that is
+// neither bare nor narrowed to a known area (see validScope).
+func checkScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !validScope(scope) {
+			return status.Errorf(codes.InvalidArgument, "invalid scope %q; must be read, write or admin, optionally narrowed to an area as in read:records", scope)
+		}
+	}
+	return nil
+}
+
+// adminService implements AdminService on the server's databases. It is a
+// type of its own since a server can embed only one Unimplemented server.
+type adminService struct {
+	pb.UnimplementedAdminServiceServer
+	s *server
+}
+
+// apiKeyColumns are the api_keys columns scanAPIKey scans.
+const apiKeyColumns = `api_key, COALESCE(description, ''), owner, role, COALESCE(is_active, FALSE), sandbox,
+	COALESCE(organization_id, 0), org_admin, created_at, expires_at, revoked_at, COALESCE(rotated_to::text, ''),
+	monthly_request_quota, monthly_record_quota, scopes`
+
+func scanAPIKey(row rowScanner) (*pb.APIKey, error) {
+	var k pb.APIKey
+	var createdAt, expiresAt, revokedAt sql.NullTime
+	if err := row.Scan(&k.ApiKey, &k.Description, &k.Owner, &k.Role, &k.Active, &k.Sandbox,
+		&k.OrganizationId, &k.OrgAdmin, &createdAt, &expiresAt, &revokedAt, &k.RotatedTo,
+		&k.MonthlyRequestQuota, &k.MonthlyRecordQuota, pq.Array(&k.Scopes)); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		k.CreatedAt = createdAt.Time.Format(time.RFC3339)
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = expiresAt.Time.Format(time.RFC3339)
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = revokedAt.Time.Format(time.RFC3339)
+	}
+	return &k, nil
+}
+
+// CreateAPIKey issues a random UUID key with the requested owner, role and
+// lifetime. An owner is required so keys can be traced to whoever uses them.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.APIKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	description := strings.TrimSpace(req.Description)
+	owner := strings.TrimSpace(req.Owner)
+	if len(description) > maxDescriptionLength || len(owner) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description and owner must be at most %d bytes", maxDescriptionLength)
+	}
+	if owner == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "owner is required")
+	}
+	if len(req.Role) > maxRoleLength {
+		return nil, status.Errorf(codes.InvalidArgument, "role must be at most %d bytes", maxRoleLength)
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl_seconds must not be negative")
+	}
+	if req.MonthlyRequestQuota < 0 || req.MonthlyRecordQuota < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "monthly quotas must not be negative")
+	}
+	if err := checkScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+
+	// Expiry is computed in SQL, on the clock authentication compares it with
+	key, err := scanAPIKey(a.s.keys.QueryRowContext(ctx, `
+		INSERT INTO api_keys (api_key, description, owner, role, sandbox, expires_at, monthly_request_quota, monthly_record_quota, scopes)
+		VALUES ($1, $2, $3, $4, $5, NOW() + make_interval(secs => NULLIF($6, 0)::float8), $7, $8, $9)
+		RETURNING `+apiKeyColumns,
+		uuid.NewString(), description, owner, req.Role, req.Sandbox, req.TtlSeconds, req.MonthlyRequestQuota, req.MonthlyRecordQuota, pq.Array(req.Scopes)))
+	if err != nil {
+		errorf("CreateAPIKey: Failed to create key for %s: %v", owner, err)
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %v", err)
+	}
+	// Servers may have cached the key as unknown
+	a.s.keysChanged(key.ApiKey)
+	infof("CreateAPIKey: API key %s created key %s for %s (role %q, scopes %v, expires %q)", apiKey, key.ApiKey, owner, key.Role, key.Scopes, key.ExpiresAt)
+	return key, nil
+}
+
+// RotateAPIKey issues a new key with the attributes of an active one and
+// moves its preferences and report schedules over. The old key keeps
+// working for grace_seconds, so clients can switch without an outage, and
+// then expires; it records the key that replaced it. Keys listed in
+// authorization.admin_api_keys are rotated in the config instead.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) RotateAPIKey(ctx context.Context, req *pb.RotateAPIKeyRequest) (*pb.APIKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	old, err := uuid.Parse(req.ApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api_key %q", req.ApiKey)
+	}
+	if req.GraceSeconds < 0 || req.TtlSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "grace_seconds and ttl_seconds must not be negative")
+	}
+	if a.s.adminKeys[old.String()] {
+		return nil, status.Errorf(codes.FailedPrecondition, "API key %s is listed in authorization.admin_api_keys; rotate it there", old)
+	}
+
+	tx, err := a.s.keys.BeginTx(ctx, nil)
+	if err != nil {
+		errorf("RotateAPIKey: Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	defer tx.Rollback()
+	var usable bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(is_active, FALSE) AND rotated_to IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		FROM api_keys WHERE api_key = $1
+		FOR UPDATE
+	`, old.String()).Scan(&usable)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found", old)
+	}
+	if err != nil {
+		errorf("RotateAPIKey: Failed to look up key %s: %v", old, err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	if !usable {
+		return nil, status.Errorf(codes.FailedPrecondition, "API key %s is revoked, expired or already rotated", old)
+	}
+
+	// The new key keeps the old one's expiry unless given its own lifetime
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, `
+		INSERT INTO api_keys (api_key, description, owner, role, sandbox, organization_id, org_admin, expires_at,
+			monthly_request_quota, monthly_record_quota, scopes)
+		SELECT $1, description, owner, role, sandbox, organization_id, org_admin,
+			COALESCE(NOW() + make_interval(secs => NULLIF($2, 0)::float8), expires_at),
+			monthly_request_quota, monthly_record_quota, scopes
+		FROM api_keys WHERE api_key = $3
+		RETURNING `+apiKeyColumns,
+		uuid.NewString(), req.TtlSeconds, old.String()))
+	if err != nil {
+		errorf("RotateAPIKey: Failed to create replacement of key %s: %v", old, err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO key_preferences (api_key, record_types, source_precedence, max_rows, timezone, updated_at)
+		SELECT $1, record_types, source_precedence, max_rows, timezone, updated_at FROM key_preferences WHERE api_key = $2`,
+		`UPDATE report_schedules SET api_key = $1 WHERE api_key = $2`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt, key.ApiKey, old.String()); err != nil {
+			errorf("RotateAPIKey: Failed to move settings of key %s: %v", old, err)
+			return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE api_keys SET rotated_to = $1, expires_at = LEAST(COALESCE(expires_at, 'infinity'), NOW() + make_interval(secs => $2::float8))
+		WHERE api_key = $3
+	`, key.ApiKey, req.GraceSeconds, old.String()); err != nil {
+		errorf("RotateAPIKey: Failed to retire key %s: %v", old, err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		errorf("RotateAPIKey: Failed to commit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to rotate API key: %v", err)
+	}
+	a.s.keysChanged(old.String(), key.ApiKey)
+	infof("RotateAPIKey: API key %s rotated key %s to %s (grace %ds)", apiKey, old, key.ApiKey, req.GraceSeconds)
+	return key, nil
+}
+
+// RevokeAPIKey deactivates a key; requests with it fail from then on. An
+// admin cannot revoke the key it calls with.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.APIKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	target, err := uuid.Parse(req.ApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api_key %q", req.ApiKey)
+	}
+	if target.String() == strings.ToLower(apiKey) {
+		return nil, status.Errorf(codes.FailedPrecondition, "an admin cannot revoke its own key")
+	}
+	key, err := scanAPIKey(a.s.keys.QueryRowContext(ctx, `
+		UPDATE api_keys SET is_active = FALSE, revoked_at = COALESCE(revoked_at, NOW())
+		WHERE api_key = $1
+		RETURNING `+apiKeyColumns, target.String()))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found", target)
+	}
+	if err != nil {
+		errorf("RevokeAPIKey: Failed to revoke key %s: %v", target, err)
+		return nil, status.Errorf(codes.Internal, "failed to revoke API key: %v", err)
+	}
+	a.s.keysChanged(target.String())
+	infof("RevokeAPIKey: API key %s revoked key %s", apiKey, target)
+	return key, nil
+}
+
+// SetAPIKeyQuota replaces the monthly request and record quotas of a key.
+// Usage already counted this month counts against the new quotas.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) SetAPIKeyQuota(ctx context.Context, req *pb.SetAPIKeyQuotaRequest) (*pb.APIKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	target, err := uuid.Parse(req.ApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api_key %q", req.ApiKey)
+	}
+	if req.MonthlyRequestQuota < 0 || req.MonthlyRecordQuota < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "monthly quotas must not be negative")
+	}
+	key, err := scanAPIKey(a.s.keys.QueryRowContext(ctx, `
+		UPDATE api_keys SET monthly_request_quota = $1, monthly_record_quota = $2
+		WHERE api_key = $3
+		RETURNING `+apiKeyColumns, req.MonthlyRequestQuota, req.MonthlyRecordQuota, target.String()))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found", target)
+	}
+	if err != nil {
+		errorf("SetAPIKeyQuota: Failed to update key %s: %v", target, err)
+		return nil, status.Errorf(codes.Internal, "failed to update API key quota: %v", err)
+	}
+	a.s.keysChanged(target.String())
+	infof("SetAPIKeyQuota: API key %s set the monthly quotas of key %s to %d requests, %d records", apiKey, target, req.MonthlyRequestQuota, req.MonthlyRecordQuota)
+	return key, nil
+}
+
+// SetAPIKeyScopes replaces the scopes of a key; with none it has those of
+// its role again. Keys listed in authorization.admin_api_keys have every scope
+// whatever is stored.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) SetAPIKeyScopes(ctx context.Context, req *pb.SetAPIKeyScopesRequest) (*pb.APIKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	target, err := uuid.Parse(req.ApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api_key %q", req.ApiKey)
+	}
+	if err := checkScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	key, err := scanAPIKey(a.s.keys.QueryRowContext(ctx, `
+		UPDATE api_keys SET scopes = $1
+		WHERE api_key = $2
+		RETURNING `+apiKeyColumns, pq.Array(req.Scopes), target.String()))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found", target)
+	}
+	if err != nil {
+		errorf("SetAPIKeyScopes: Failed to update key %s: %v", target, err)
+		return nil, status.Errorf(codes.Internal, "failed to update API key scopes: %v", err)
+	}
+	a.s.keysChanged(target.String())
+	infof("SetAPIKeyScopes: API key %s set the scopes of key %s to %v", apiKey, target, req.Scopes)
+	return key, nil
+}
+
+// ListAPIKeys returns the keys usable now, or every key with
+// include_inactive, newest first, optionally only those of an owner.
+//
+// It requires an API key with the admin scope.
+func (a *adminService) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	rows, err := a.s.keys.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE ($1 = '' OR owner = $1)
+		AND ($2 OR (COALESCE(is_active, FALSE) AND (expires_at IS NULL OR expires_at > NOW())))
+		ORDER BY created_at DESC
+	`, strings.TrimSpace(req.Owner), req.IncludeInactive)
+	if err != nil {
+		errorf("ListAPIKeys: Failed to query keys: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list API keys: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.ListAPIKeysResponse{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			errorf("ListAPIKeys: Failed to scan key: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to list API keys: %v", err)
+		}
+		resp.Keys = append(resp.Keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListAPIKeys: Failed to iterate keys: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to list API keys: %v", err)
+	}
+	infof("ListAPIKeys: Returning %d keys", len(resp.Keys))
+	return resp, nil
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// authCacheTTL bounds how long the authenticator caches a key's status.
+// Keys changed through the API are dropped sooner (see eventHub).
+const authCacheTTL = time.Minute
+
+// authExemptServices lists the services callable without an API key: probes
+// of the standard health service, and reflection, which only describes the
+// API already published at /openapi.json.
+var authExemptServices = map[string]bool{
+	healthpb.Health_ServiceDesc.ServiceName:                    true,
+	reflectionpb.ServerReflection_ServiceDesc.ServiceName:      true,
+	reflectionalphapb.ServerReflection_ServiceDesc.ServiceName: true,
+}
+
+// authExempt lists the RPCs callable without an API key in the metadata.
+var authExempt = map[string]bool{
+	"Authenticate":   true, // Checks the key in its request instead
+	"GetSigningKeys": true, // Public keys, for whoever verifies signed data
+}
+
+// exemptFromAuth reports whether fullMethod is callable without an API key.
+func exemptFromAuth(fullMethod string) bool {
+	return authExempt[path.Base(fullMethod)] || authExemptServices[strings.TrimPrefix(path.Dir(fullMethod), "/")]
+}
+
+// apiKeyContextKey is the context key of the API key the authenticator
+// accepted.
+type apiKeyContextKey struct{}
+
+type keyStatus struct {
+	found     bool
+	active    bool
+	expiresAt time.Time // Zero if the key does not expire
+	cached    time.Time
+}
+
+// authenticator rejects calls without a valid API key in the gRPC metadata
+// ("x-api-key") before they reach the other interceptors and the handlers,
+// which read the key with apiKeyFromContext. Key lookups are cached for
+// authCacheTTL, unknown keys included, so that a client retrying with a bad
+// key does not cost a query per call.
+type authenticator struct {
+	db *sql.DB
+
+	mu   sync.Mutex
+	keys map[string]keyStatus
+}
+
+func newAuthenticator(db *sql.DB) *authenticator {
+	return &authenticator{db: db, keys: make(map[string]keyStatus)}
+}
+
+// unaryInterceptor authenticates the call and passes its key on to handler
+// in the context.
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls.
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &keyedStream{ServerStream: ss, ctx: ctx})
+}
+
+// keyedStream is a ServerStream whose context carries the authenticated key.
+type keyedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *keyedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate returns ctx with the call's API key, or an Unauthenticated
+// error if the key is missing, unknown, inactive or expired.
+func (a *authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	method := path.Base(fullMethod)
+	if exemptFromAuth(fullMethod) {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	debugf("%s: Metadata received: %v", method, md)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		warnf("%s: Missing API key in metadata", method)
+		return nil, statusError(codes.Unauthenticated, reasonMissingKey, nil, "missing API key")
+	}
+	apiKey := apiKeys[0]
+	key, err := a.keyStatus(ctx, apiKey)
+	if err != nil {
+		errorf("%s: Failed to validate API key %s: %v", method, apiKey, err)
+		return nil, statusError(codes.Internal, codeReason(codes.Internal), nil, "failed to validate API key: %v", err)
+	}
+	switch {
+	case !key.found:
+		warnf("%s: API key %s not found", method, apiKey)
+		return nil, statusError(codes.Unauthenticated, reasonInvalidKey, nil, "invalid API key")
+	case !key.active:
+		warnf("%s: API key %s is inactive", method, apiKey)
+		return nil, statusError(codes.Unauthenticated, reasonKeyInactive, nil, "API key is inactive")
+	case !key.expiresAt.IsZero() && !time.Now().Before(key.expiresAt):
+		warnf("%s: API key %s has expired", method, apiKey)
+		return nil, statusError(codes.Unauthenticated, reasonKeyExpired, nil, "API key has expired")
+	}
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey), nil
+}
+
+// keyStatus returns the status of apiKey, from the cache if it is fresh.
+// Expiry is checked against the stored time, so a cached key still expires
+// on time.
+func (a *authenticator) keyStatus(ctx context.Context, apiKey string) (keyStatus, error) {
+	a.mu.Lock()
+	cached, ok := a.keys[apiKey]
+	a.mu.Unlock()
+	if ok && time.Since(cached.cached) < authCacheTTL {
+		return cached, nil
+	}
+	key := keyStatus{cached: time.Now()}
+	var expiresAt sql.NullTime
+	err := a.db.QueryRowContext(ctx, "SELECT COALESCE(is_active, FALSE), expires_at FROM api_keys WHERE api_key = $1", apiKey).
+		Scan(&key.active, &expiresAt)
+	if err != nil && err != sql.ErrNoRows {
+		return keyStatus{}, err
+	}
+	key.found = err == nil
+	if expiresAt.Valid {
+		key.expiresAt = expiresAt.Time
+	}
+	a.mu.Lock()
+	a.keys[apiKey] = key
+	a.mu.Unlock()
+	return key, nil
+}
+
+// forget drops the cached status of apiKey.
+func (a *authenticator) forget(apiKey string) {
+	a.mu.Lock()
+	delete(a.keys, apiKey)
+	a.mu.Unlock()
+}
+
+// run drops stale statuses every interval until ctx is done, so keys seen
+// once, such as mistyped ones, do not accumulate.
+func (a *authenticator) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			for apiKey, key := range a.keys {
+				if time.Since(key.cached) >= authCacheTTL {
+					delete(a.keys, apiKey)
+				}
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+// apiKeyFromContext returns the API key the authenticator accepted for the
+// call of ctx.
+func apiKeyFromContext(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return apiKey
+}
@@ -0,0 +1,349 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// Scopes a method can require (authorization.methods) and a role or key can
+// be granted (authorization.roles, api_keys.scopes). A scope may be narrowed
+// to the RPCs of one area, as in read:records; a method requires its scope
+// in its area, which the bare scope grants in every area.
+const (
+	scopeRead  = "read"
+	scopeWrite = "write"
+	scopeAdmin = "admin"
+)
+
+// Areas of RPCs a scope can be narrowed to.
+const (
+	areaRecords = "records" // Lookups, counts and analytics of the collected data
+	areaExports = "exports"
+	areaReports = "reports"
+	areaIngest  = "ingest" // Adding domains or fresh records to the collection
+	areaOrgs    = "orgs"
+	areaKeys    = "keys" // API keys, their preferences and usage
+	areaOps     = "ops"  // Operation of the service
+)
+
+// methodAreas are the areas of RPCs outside areaRecords.
+var methodAreas = map[string]string{
+	"StartExport":              areaExports,
+	"GetExport":                areaExports,
+	"ListExports":              areaExports,
+	"CreateReportSchedule":     areaReports,
+	"ListReportSchedules":      areaReports,
+	"DeleteReportSchedule":     areaReports,
+	"RefreshDomain":            areaIngest,
+	"ImportDomains":            areaIngest,
+	"GetOrganization":          areaOrgs,
+	"CreateOrganizationKey":    areaOrgs,
+	"UpdateOrganizationKey":    areaOrgs,
+	"SetOrganizationWatchlist": areaOrgs,
+	"ImportWatchlist":          areaOrgs,
+	"GetOrganizationUsage":     areaOrgs,
+	"CreateOrganization":       areaOrgs,
+	"SetOrganizationQuota":     areaOrgs,
+	"SetDomainRestriction":     areaOrgs,
+	"GetKeyPreferences":        areaKeys,
+	"SetKeyPreferences":        areaKeys,
+	"GetUsage":                 areaKeys,
+	"CreateAPIKey":             areaKeys,
+	"RotateAPIKey":             areaKeys,
+	"RevokeAPIKey":             areaKeys,
+	"SetAPIKeyQuota":           areaKeys,
+	"SetAPIKeyScopes":          areaKeys,
+	"ListAPIKeys":              areaKeys,
+	"ListNameserverReputation": areaOps,
+	"TailEvents":               areaOps,
+	"ListDNSServers":           areaOps,
+	"UpdateDNSServers":         areaOps,
+	"SetLogLevel":              areaOps,
+	"GetTLDCoverage":           areaOps,
+}
+
+// validScope reports whether scope is a bare scope or one narrowed to a
+// known area.
+func validScope(scope string) bool {
+	level, area, narrowed := strings.Cut(scope, ":")
+	if level != scopeRead && level != scopeWrite && level != scopeAdmin {
+		return false
+	}
+	if !narrowed {
+		return true
+	}
+	switch area {
+	case areaRecords, areaExports, areaReports, areaIngest, areaOrgs, areaKeys, areaOps:
+		return true
+	}
+	return false
+}
+
+// grantsScope reports whether the granted scopes include scope, which is
+// narrowed to an area, or the bare scope.
+func grantsScope(granted []string, scope string) bool {
+	level, _, _ := strings.Cut(scope, ":")
+	for _, g := range granted {
+		if g == scope || g == level {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultMethodScopes are the scopes of methods that need more than
+// authorization.default_scope unless authorization.methods says otherwise.
+var defaultMethodScopes = map[string]string{
+	// Changes to stored data or per-key settings
+	"RefreshDomain":            scopeWrite,
+	"StartExport":              scopeWrite,
+	"SetKeyPreferences":        scopeWrite,
+	"CreateReportSchedule":     scopeWrite,
+	"DeleteReportSchedule":     scopeWrite,
+	"CreateOrganizationKey":    scopeWrite,
+	"UpdateOrganizationKey":    scopeWrite,
+	"SetOrganizationWatchlist": scopeWrite,
+	"ImportWatchlist":          scopeWrite,
+	"ImportDomains":            scopeWrite,
+	// Operation of the service and key management
+	"ListNameserverReputation": scopeAdmin,
+	"TailEvents":               scopeAdmin,
+	"ListDNSServers":           scopeAdmin,
+	"UpdateDNSServers":         scopeAdmin,
+	"SetLogLevel":              scopeAdmin,
+	"GetTLDCoverage":           scopeAdmin,
+	"CreateOrganization":       scopeAdmin,
+	"SetOrganizationQuota":     scopeAdmin,
+	"SetDomainRestriction":     scopeAdmin,
+	"CreateAPIKey":             scopeAdmin,
+	"RotateAPIKey":             scopeAdmin,
+	"RevokeAPIKey":             scopeAdmin,
+	"SetAPIKeyQuota":           scopeAdmin,
+	"SetAPIKeyScopes":          scopeAdmin,
+	"ListAPIKeys":              scopeAdmin,
+}
+
+// defaultRoleScopes are the scopes of roles authorization.roles does not
+// list, which are those of every key before roles were checked.
+var defaultRoleScopes = []string{scopeRead, scopeWrite}
+
+// authzCacheTTL bounds how long a key's role is cached by the authorizer.
+const authzCacheTTL = time.Minute
+
+type keyRole struct {
+	role    string
+	scopes  []string // api_keys.scopes; empty for those of the role
+	usable  bool     // Key exists, is active and has not expired
+	expires time.Time
+}
+
+// authorizer checks that the key of each call has the scope its method
+// requires, so handlers need not check it. A method requires the scope in
+// authorization.methods, defaultMethodScopes or authorization.default_scope,
+// in that order, in its area (methodAreas, or areaRecords), and some
+// requests a further scope for what they ask (requestScopes). A key has its
+// api_keys.scopes, or if it has none the scopes authorization.roles grants
+// its api_keys.role; keys listed in authorization.admin_api_keys have every
+// scope, and those in logging.admin_api_keys may call SetLogLevel.
+type authorizer struct {
+	db           *sql.DB
+	adminKeys    map[string]bool     // authorization.admin_api_keys
+	logLevelKeys map[string]bool     // logging.admin_api_keys
+	methods      map[string]string   // Method name -> required scope
+	defaultScope string              // Of methods not in methods
+	roles        map[string][]string // api_keys.role -> granted scopes
+
+	mu   sync.Mutex
+	keys map[string]keyRole
+}
+
+// newAuthorizer returns an authorizer enforcing methods over the defaults,
+// or an error if methods names an RPC neither service has or a scope of an
+// unknown area.
+func newAuthorizer(db *sql.DB, adminKeys, logLevelKeys map[string]bool, methods map[string]string, defaultScope string, roles map[string][]string) (*authorizer, error) {
+	known := make(map[string]bool)
+	for _, desc := range []grpc.ServiceDesc{pb.DNSService_ServiceDesc, pb.AdminService_ServiceDesc} {
+		for _, m := range desc.Methods {
+			known[m.MethodName] = true
+		}
+		for _, st := range desc.Streams {
+			known[st.StreamName] = true
+		}
+	}
+	a := &authorizer{db: db, adminKeys: adminKeys, logLevelKeys: logLevelKeys, methods: make(map[string]string), defaultScope: defaultScope, roles: roles, keys: make(map[string]keyRole)}
+	for method, scope := range defaultMethodScopes {
+		a.methods[method] = scope
+	}
+	for method, scope := range methods {
+		if !known[method] {
+			return nil, fmt.Errorf("authorization.methods names unknown RPC %q", method)
+		}
+		if !validScope(scope) {
+			return nil, fmt.Errorf("authorization.methods scope %q of %s has an unknown area", scope, method)
+		}
+		a.methods[method] = scope
+	}
+	if !validScope(defaultScope) {
+		return nil, fmt.Errorf("authorization.default_scope %q has an unknown area", defaultScope)
+	}
+	for role, scopes := range roles {
+		for _, scope := range scopes {
+			if !validScope(scope) {
+				return nil, fmt.Errorf("authorization.roles scope %q of role %q has an unknown area", scope, role)
+			}
+		}
+	}
+	return a, nil
+}
+
+// requestScopes give the scope a request needs beyond its method's, for
+// requests whose fields ask for more than the method does, or "" if it
+// needs no more. The scope is narrowed to an area as the method's is.
+var requestScopes = map[string]func(req interface{}) string{
+	// A refresh bypasses the cache, costing RDAP queries that registries
+	// rate-limit the service by, and overwrites the cached contacts
+	"GetAbuseContacts": func(req interface{}) string {
+		if r, ok := req.(*pb.GetAbuseContactsRequest); ok && r.Refresh {
+			return scopeWrite + ":" + areaIngest
+		}
+		return ""
+	},
+}
+
+// unaryInterceptor rejects calls whose key lacks the scope of the method,
+// or of the request (requestScopes), with PermissionDenied. Only RPCs
+// exempt from authentication are let through without a usable key; it does
+// not rely on the authenticator having rejected the others, whose cached
+// key status may be stale.
+func (a *authorizer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	method := path.Base(info.FullMethod)
+	if scopeOf, ok := requestScopes[method]; ok {
+		if scope := scopeOf(req); scope != "" {
+			if err := a.requireScope(ctx, method, scope); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls.
+func (a *authorizer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize returns a PermissionDenied error if the call's key lacks the
+// scope fullMethod requires, and an Unauthenticated error if the call has
+// no key or its key is unknown, inactive or expired, unless fullMethod is
+// exempt from authentication.
+func (a *authorizer) authorize(ctx context.Context, fullMethod string) error {
+	if exemptFromAuth(fullMethod) {
+		return nil
+	}
+	method := path.Base(fullMethod)
+	scope, ok := a.methods[method]
+	if !ok {
+		scope = a.defaultScope
+	}
+	return a.requireScope(ctx, method, scope)
+}
+
+// requireScope is authorize for a call of method that requires scope, in
+// the method's area unless narrowed to one.
+func (a *authorizer) requireScope(ctx context.Context, method, scope string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		warnf("%s: Missing API key in metadata", method)
+		return statusError(codes.Unauthenticated, reasonMissingKey, nil, "missing API key")
+	}
+	if a.adminKeys[apiKeys[0]] || (method == "SetLogLevel" && a.logLevelKeys[apiKeys[0]]) {
+		return nil
+	}
+	if !strings.Contains(scope, ":") {
+		area, ok := methodAreas[method]
+		if !ok {
+			area = areaRecords
+		}
+		scope += ":" + area
+	}
+	key, err := a.keyRole(ctx, apiKeys[0])
+	if err != nil {
+		errorf("%s: Failed to look up role of API key %s: %v", method, apiKeys[0], err)
+		return statusError(codes.Internal, codeReason(codes.Internal), nil, "failed to look up API key role: %v", err)
+	}
+	if !key.usable {
+		// keyRole reads unusable keys afresh, so this catches keys revoked
+		// or expired while the authenticator still has them cached
+		warnf("%s: API key %s is unknown, inactive or expired", method, apiKeys[0])
+		return statusError(codes.Unauthenticated, reasonKeyInactive, nil, "API key is unknown, inactive or expired")
+	}
+	scopes := key.scopes
+	if len(scopes) == 0 {
+		var ok bool
+		if scopes, ok = a.roles[key.role]; !ok {
+			scopes = defaultRoleScopes
+		}
+	}
+	if grantsScope(scopes, scope) {
+		return nil
+	}
+	warnf("%s: API key %s (role %q, scopes %v) lacks the %s scope", method, apiKeys[0], key.role, key.scopes, scope)
+	if strings.HasPrefix(scope, scopeAdmin+":") {
+		return statusError(codes.PermissionDenied, reasonAdminKeyRequired, nil, "admin API key required")
+	}
+	return statusError(codes.PermissionDenied, reasonScopeRequired, map[string]string{"scope": scope, "rpc": method},
+		"API key lacks the %s scope %s requires", scope, method)
+}
+
+// forget drops the cached role of apiKey.
+func (a *authorizer) forget(apiKey string) {
+	a.mu.Lock()
+	delete(a.keys, apiKey)
+	a.mu.Unlock()
+}
+
+// keyRole returns the role of apiKey and whether it may be used. Only
+// usable keys are cached, so a key reactivated meanwhile is never let
+// through unchecked.
+func (a *authorizer) keyRole(ctx context.Context, apiKey string) (keyRole, error) {
+	a.mu.Lock()
+	cached, ok := a.keys[apiKey]
+	a.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+	var key keyRole
+	err := a.db.QueryRowContext(ctx, `
+		SELECT role, scopes, COALESCE(is_active, FALSE) AND COALESCE(expires_at > NOW(), TRUE)
+		FROM api_keys WHERE api_key = $1
+	`, apiKey).Scan(&key.role, pq.Array(&key.scopes), &key.usable)
+	if err != nil && err != sql.ErrNoRows {
+		return keyRole{}, err
+	}
+	if key.usable {
+		key.expires = time.Now().Add(authzCacheTTL)
+		a.mu.Lock()
+		a.keys[apiKey] = key
+		a.mu.Unlock()
+	}
+	return key, nil
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+func TestAuthorize(t *testing.T) {
+	a, err := newAuthorizer(nil, map[string]bool{"admin-key": true}, map[string]bool{"log-key": true},
+		map[string]string{"GetTLDCoverage": "read:ops"}, scopeRead, map[string][]string{"analyst": {"read:records"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cached, so no database is needed
+	expires := time.Now().Add(time.Hour)
+	a.keys["analyst-key"] = keyRole{role: "analyst", usable: true, expires: expires}
+	a.keys["default-key"] = keyRole{usable: true, expires: expires}
+	a.keys["ingest-key"] = keyRole{role: "analyst", scopes: []string{"write:ingest", "read"}, usable: true, expires: expires}
+	a.keys["log-key"] = keyRole{role: "analyst", usable: true, expires: expires}
+
+	for _, tc := range []struct {
+		key, method string
+		want        codes.Code
+	}{
+		{"analyst-key", pb.DNSService_GetRecords_FullMethodName, codes.OK},
+		{"analyst-key", pb.DNSService_RefreshDomain_FullMethodName, codes.PermissionDenied},
+		{"analyst-key", pb.DNSService_GetTLDCoverage_FullMethodName, codes.PermissionDenied},
+		{"default-key", pb.DNSService_RefreshDomain_FullMethodName, codes.OK},
+		{"default-key", pb.DNSService_GetTLDCoverage_FullMethodName, codes.OK},
+		{"default-key", pb.AdminService_ListAPIKeys_FullMethodName, codes.PermissionDenied},
+		{"ingest-key", pb.DNSService_RefreshDomain_FullMethodName, codes.OK},
+		{"ingest-key", pb.DNSService_StartExport_FullMethodName, codes.PermissionDenied},
+		{"log-key", pb.DNSService_SetLogLevel_FullMethodName, codes.OK},
+		{"log-key", pb.DNSService_UpdateDNSServers_FullMethodName, codes.PermissionDenied},
+		{"admin-key", pb.AdminService_ListAPIKeys_FullMethodName, codes.OK},
+		{"", pb.DNSService_GetRecords_FullMethodName, codes.Unauthenticated},
+	} {
+		ctx := context.Background()
+		if tc.key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", tc.key))
+		}
+		if got := status.Code(a.authorize(ctx, tc.method)); got != tc.want {
+			t.Errorf("%s calling %s: %v, want %v", tc.key, tc.method, got, tc.want)
+		}
+	}
+}
+
+func TestNewAuthorizerRejectsUnknownMethods(t *testing.T) {
+	if _, err := newAuthorizer(nil, nil, nil, map[string]string{"NoSuchRPC": scopeRead}, scopeRead, nil); err == nil {
+		t.Error("accepted an unknown RPC")
+	}
+	if _, err := newAuthorizer(nil, nil, nil, map[string]string{"GetRecords": "read:nowhere"}, scopeRead, nil); err == nil {
+		t.Error("accepted a scope of an unknown area")
+	}
+}
+
+func TestAuthorizeAbuseContactsRefresh(t *testing.T) {
+	a, err := newAuthorizer(nil, nil, nil, nil, scopeRead, map[string][]string{"analyst": {"read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Now().Add(time.Hour)
+	a.keys["analyst-key"] = keyRole{role: "analyst", usable: true, expires: expires}
+	a.keys["default-key"] = keyRole{usable: true, expires: expires}
+	info := &grpc.UnaryServerInfo{FullMethod: pb.DNSService_GetAbuseContacts_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+
+	for _, tc := range []struct {
+		key     string
+		refresh bool
+		want    codes.Code
+	}{
+		{"analyst-key", false, codes.OK},
+		{"analyst-key", true, codes.PermissionDenied},
+		{"default-key", true, codes.OK},
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tc.key))
+		_, err := a.unaryInterceptor(ctx, &pb.GetAbuseContactsRequest{Domain: "acme.example", Refresh: tc.refresh}, info, handler)
+		if got := status.Code(err); got != tc.want {
+			t.Errorf("%s with refresh=%t: %v, want %v", tc.key, tc.refresh, got, tc.want)
+		}
+	}
+}
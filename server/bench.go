@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/moos3/bell/config"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// NewGetRecordsBench returns what the bench tool's BenchmarkGetRecords
+// measures: a call of the GetRecords handler against db with cfg's merge
+// policy, through the authenticator as in the API so the key is looked up
+// and its preferences applied, returning the number of records. apiKey
+// must be an active key in db. Logging is limited to warnings until restore
+// is called.
+func NewGetRecordsBench(cfg *config.Config, db *sql.DB, apiKey string) (getRecords func(domain string, merged bool) (int, error), restore func()) {
+	s := &server{
+		db:        db,
+		keys:      db,
+		shards:    storage.NewSingleRouter(db),
+		merge:     newMergePolicy(cfg.Merge.Precedence, cfg.Merge.FreshnessWins),
+		adminKeys: make(map[string]bool),
+		prefs:     newPreferenceStore(db, cfg.Merge.FreshnessWins),
+	}
+	auth := newAuthenticator(db)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.DNSService_GetRecords_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.GetRecords(ctx, req.(*pb.GetRecordsRequest))
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", apiKey))
+	getRecords = func(domain string, merged bool) (int, error) {
+		resp, err := auth.unaryInterceptor(ctx, &pb.GetRecordsRequest{Domain: domain, Merged: merged}, info, handler)
+		if err != nil {
+			return 0, err
+		}
+		return len(resp.(*pb.GetRecordsResponse).Records), nil
+	}
+	return getRecords, logger.Override(slog.LevelWarn)
+}
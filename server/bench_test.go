@@ -0,0 +1,38 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/fixtures"
+)
+
+func BenchmarkGetRecords(b *testing.B) {
+	db := fixtures.OpenDatabase(b)
+	zone := fixtures.Generate(fixtures.Options{Seed: 1, DomainsPerTLD: 1000}).Zones[0]
+	if err := zone.Store(db); err != nil {
+		b.Fatal(err)
+	}
+	const apiKey = "00000000-0000-4000-8000-00000000be1c"
+	if _, err := db.Exec("INSERT INTO api_keys (api_key, description) VALUES ($1, 'bench') ON CONFLICT DO NOTHING", apiKey); err != nil {
+		b.Fatal(err)
+	}
+	getRecords, restore := NewGetRecordsBench(&config.Config{}, db, apiKey)
+	defer restore()
+
+	for _, merged := range []bool{false, true} {
+		b.Run(fmt.Sprintf("merged=%t", merged), func(b *testing.B) {
+			b.ReportAllocs()
+			records := 0
+			for i := 0; i < b.N; i++ {
+				n, err := getRecords(zone.Domains[i%len(zone.Domains)].Name, merged)
+				if err != nil {
+					b.Fatal(err)
+				}
+				records += n
+			}
+			b.ReportMetric(float64(records)/float64(b.N), "records/op")
+		})
+	}
+}
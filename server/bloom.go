@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// maxCheckDomains caps the number of domains in one CheckDomains request.
+const maxCheckDomains = 1000
+
+// bloomFilter is a fixed-size Bloom filter over lowercased domain names.
+// It never reports a stored domain as absent; a "maybe" must be confirmed
+// against the database.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64 // Number of bits
+	k    uint64 // Number of hash functions
+}
+
+// newBloomFilter sizes a filter for n names at false positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// hashes returns the two base hashes used for double hashing.
+func bloomHashes(name string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(name)))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31
+	return h1, h2 | 1
+}
+
+func (f *bloomFilter) add(name string) {
+	h1, h2 := bloomHashes(name)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *bloomFilter) mayContain(name string) bool {
+	h1, h2 := bloomHashes(name)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// domainFilter keeps a Bloom filter of every domain name across all shards.
+// It is built at startup, extended with recently updated domains on every
+// refresh, and rebuilt from scratch periodically to drop removed domains.
+type domainFilter struct {
+	shards            *storage.Router
+	expected          int
+	falsePositiveRate float64
+
+	mu          sync.RWMutex
+	filter      *bloomFilter // nil until the first build completes
+	refreshedAt time.Time    // Domains updated after this are added on refresh
+}
+
+// mayContain reports whether domain may exist. Before the first build
+// completes every domain may exist.
+func (d *domainFilter) mayContain(domain string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.filter == nil || d.filter.mayContain(strings.TrimSuffix(domain, "."))
+}
+
+// add adds domain to the filter in place, ahead of the next refresh. A
+// refresh already copying the filter may drop it, but then picks the domain
+// up from the database itself.
+func (d *domainFilter) add(domain string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.filter != nil {
+		d.filter.add(strings.TrimSuffix(domain, "."))
+	}
+}
+
+// load adds every domain updated after since to filter, across all shards.
+func (d *domainFilter) load(ctx context.Context, filter *bloomFilter, since time.Time) (int, error) {
+	var mu sync.Mutex
+	count := 0
+	_, err := d.shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		rows, err := shard.DB.QueryContext(ctx, "SELECT domain_name FROM domains WHERE last_updated > $1", since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			mu.Lock()
+			filter.add(name)
+			count++
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	return count, err
+}
+
+// rebuild builds a new filter sized for the current domain count and swaps it in.
+func (d *domainFilter) rebuild(ctx context.Context) error {
+	started := time.Now().UTC()
+	var mu sync.Mutex
+	total := 0
+	_, err := d.shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		var n int
+		if err := shard.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM domains").Scan(&n); err != nil {
+			return err
+		}
+		mu.Lock()
+		total += n
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	size := d.expected
+	if total*2 > size {
+		// Leave room for growth until the next rebuild
+		size = total * 2
+	}
+	filter := newBloomFilter(size, d.falsePositiveRate)
+	count, err := d.load(ctx, filter, time.Time{})
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.filter = filter
+	d.refreshedAt = started
+	d.mu.Unlock()
+	infof("Domain filter built: %d domains, %d MiB", count, len(filter.bits)*8>>20)
+	return nil
+}
+
+// refresh adds domains updated since the last build or refresh.
+func (d *domainFilter) refresh(ctx context.Context) error {
+	started := time.Now().UTC()
+	// Build into a copy so readers never see a half-written filter. The copy
+	// is taken under the lock since add writes to the filter in place.
+	d.mu.RLock()
+	filter, since := d.filter, d.refreshedAt
+	var updated *bloomFilter
+	if filter != nil {
+		updated = &bloomFilter{bits: append([]uint64(nil), filter.bits...), m: filter.m, k: filter.k}
+	}
+	d.mu.RUnlock()
+	if filter == nil {
+		return d.rebuild(ctx)
+	}
+	count, err := d.load(ctx, updated, since.Add(-time.Minute))
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.filter = updated
+	d.refreshedAt = started
+	d.mu.Unlock()
+	debugf("Domain filter refreshed: %d domains added", count)
+	return nil
+}
+
+// run builds the filter, then refreshes it every refreshInterval and rebuilds
+// it every rebuildInterval.
+func (d *domainFilter) run(ctx context.Context, refreshInterval, rebuildInterval time.Duration) {
+	if err := d.rebuild(ctx); err != nil {
+		errorf("Failed to build domain filter: %v", err)
+	}
+	refresh := time.NewTicker(refreshInterval)
+	defer refresh.Stop()
+	rebuild := time.NewTicker(rebuildInterval)
+	defer rebuild.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-refresh.C:
+			err = d.refresh(ctx)
+		case <-rebuild.C:
+			err = d.rebuild(ctx)
+		}
+		if err != nil {
+			errorf("Failed to update domain filter: %v", err)
+		}
+	}
+}
+
+// visibleDomainExists tells whether a domain ($1) exists and is visible to
+// the keys of an organization ($2).
+const visibleDomainExists = "SELECT EXISTS (SELECT 1 FROM domains WHERE domain_name = $1 AND (restricted_to IS NULL OR $2 = ANY(restricted_to)))"
+
+// CheckDomains reports which of the given domains exist in the database.
+// Domains restricted to other organizations do not.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Domains
+// the Bloom filter rules out are answered without a database lookup.
+func (s *server) CheckDomains(ctx context.Context, req *pb.CheckDomainsRequest) (*pb.CheckDomainsResponse, error) {
+	if len(req.Domains) > maxCheckDomains {
+		return nil, statusError(codes.InvalidArgument, reasonTooManyDomains, map[string]string{"quota_limit": strconv.Itoa(maxCheckDomains)}, "at most %d domains per request", maxCheckDomains)
+	}
+	prefs, err := s.keyPreferences(ctx, "CheckDomains", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.CheckDomainsResponse{}
+	skipped := 0
+	var checked, primary []string
+	start := time.Now()
+	for _, domain := range req.Domains {
+		result := &pb.DomainPresence{Domain: domain}
+		resp.Results = append(resp.Results, result)
+		if s.domains != nil && !s.domains.mayContain(domain) {
+			skipped++
+			continue
+		}
+		err := s.shards.ForDomain(domain).Reader(ctx).QueryRowContext(ctx, visibleDomainExists, domain, prefs.orgID).Scan(&result.Exists)
+		if err != nil {
+			errorf("CheckDomains: Failed to check domain %s: %v", domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to check domain: %v", err)
+		}
+		checked = append(checked, domain)
+		primary = append(primary, fmt.Sprintf("%s %t", domain, result.Exists))
+	}
+	if len(checked) > 0 && s.shadow.sample() {
+		s.shadow.compare("CheckDomains", fmt.Sprintf("%d domains", len(checked)), primary, time.Since(start), func(ctx context.Context, db *sql.DB) ([]string, error) {
+			var results []string
+			for _, domain := range checked {
+				var exists bool
+				if err := db.QueryRowContext(ctx, visibleDomainExists, domain, prefs.orgID).Scan(&exists); err != nil {
+					return nil, err
+				}
+				results = append(results, fmt.Sprintf("%s %t", domain, exists))
+			}
+			return results, nil
+		})
+	}
+	infof("CheckDomains: Checked %d domains, %d ruled out by filter", len(req.Domains), skipped)
+	return resp, nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// canaryCheck is one self-check: an API call that succeeds while the data
+// path works.
+type canaryCheck struct {
+	name string // e.g. "GetRecords example.com"
+	run  func(ctx context.Context, client pb.DNSServiceClient) error
+}
+
+// canaryStats are the outcomes of one check.
+type canaryStats struct {
+	Runs                int64   `json:"runs"`
+	Failures            int64   `json:"failures"`
+	ConsecutiveFailures int64   `json:"consecutive_failures"`
+	LastLatencyMs       float64 `json:"last_latency_ms"`
+	LastSuccess         string  `json:"last_success,omitempty"` // RFC 3339
+	LastError           string  `json:"last_error,omitempty"`   // Of the last failure, until a success
+}
+
+// canary runs known-good calls through the server's own gRPC listener, and
+// so through every interceptor, on an interval: GetRecords of control
+// domains that are in the corpus and, if live resolution is configured,
+// LookupLive of control domains. Its counters are published through expvar
+// as "canary". The server is not ready (see healthChecker) while any check
+// has failed threshold rounds in a row.
+type canary struct {
+	apiKey    string
+	timeout   time.Duration
+	threshold int64
+	checks    []canaryCheck
+
+	mu    sync.Mutex
+	stats map[string]*canaryStats // Check name -> outcomes
+	ready bool
+}
+
+// newCanary returns a canary checking domains with GetRecords and, if live,
+// liveDomains with LookupLive.
+func newCanary(apiKey string, domains, liveDomains []string, live bool, timeout time.Duration, threshold int) *canary {
+	c := &canary{apiKey: apiKey, timeout: timeout, threshold: int64(threshold), stats: make(map[string]*canaryStats), ready: true}
+	for _, domain := range domains {
+		c.checks = append(c.checks, canaryCheck{name: "GetRecords " + domain, run: func(ctx context.Context, client pb.DNSServiceClient) error {
+			resp, err := client.GetRecords(ctx, &pb.GetRecordsRequest{Domain: domain})
+			if err != nil {
+				return err
+			}
+			if len(resp.Records) == 0 {
+				return fmt.Errorf("no records returned")
+			}
+			return nil
+		}})
+	}
+	if live {
+		for _, domain := range liveDomains {
+			c.checks = append(c.checks, canaryCheck{name: "LookupLive " + domain, run: func(ctx context.Context, client pb.DNSServiceClient) error {
+				resp, err := client.LookupLive(ctx, &pb.LookupLiveRequest{Domain: domain})
+				if err != nil {
+					return err
+				}
+				for _, a := range resp.Answers {
+					if a.Error == "" && a.Rcode == "NOERROR" && len(a.Answer) > 0 {
+						return nil
+					}
+				}
+				return fmt.Errorf("no answers resolved")
+			}})
+		}
+	}
+	for _, check := range c.checks {
+		c.stats[check.name] = &canaryStats{}
+	}
+	expvar.Publish("canary", expvar.Func(func() interface{} { return c.snapshot() }))
+	return c
+}
+
+// run dials target, the server's gRPC listener, with opts and runs the
+// checks every interval until ctx is done.
+func (c *canary) run(ctx context.Context, target string, opts []grpc.DialOption, interval time.Duration) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		errorf("Canary: Failed to dial %s: %v", target, err)
+		return
+	}
+	defer conn.Close()
+	client := pb.NewDNSServiceClient(conn)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.round(ctx, client)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round runs every check once, concurrently, and updates readiness.
+func (c *canary) round(ctx context.Context, client pb.DNSServiceClient) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", c.apiKey)
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			start := time.Now()
+			err := check.run(checkCtx, client)
+			c.record(check.name, time.Since(start), err)
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	var failing []string
+	for name, st := range c.stats {
+		if st.ConsecutiveFailures >= c.threshold {
+			failing = append(failing, name)
+		}
+	}
+	wasReady := c.ready
+	c.ready = len(failing) == 0
+	c.mu.Unlock()
+	switch {
+	case wasReady && len(failing) > 0:
+		sort.Strings(failing)
+		warnf("Canary: Not ready; failing checks: %v", failing)
+	case !wasReady && len(failing) == 0:
+		infof("Canary: Ready again; every check passes")
+	}
+}
+
+// record adds the outcome of a check.
+func (c *canary) record(name string, latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := c.stats[name]
+	st.Runs++
+	st.LastLatencyMs = float64(latency.Microseconds()) / 1000
+	if err != nil {
+		st.Failures++
+		st.ConsecutiveFailures++
+		st.LastError = err.Error()
+		debugf("Canary: %s failed after %v: %v", name, latency.Round(time.Millisecond), err)
+		return
+	}
+	st.ConsecutiveFailures = 0
+	st.LastError = ""
+	st.LastSuccess = time.Now().UTC().Format(time.RFC3339)
+}
+
+func (c *canary) snapshot() map[string]canaryStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]canaryStats, len(c.stats))
+	for name, st := range c.stats {
+		out[name] = *st
+	}
+	return out
+}
+
+// isReady reports whether no check has failed threshold rounds in a row.
+func (c *canary) isReady() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ready
+}
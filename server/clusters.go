@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+const (
+	defaultClusterLimit       = 50
+	defaultClusterDomainLimit = 100
+	maxClusterLimit           = 1000
+)
+
+// ListDomainClusters returns the clusters found by the domain-clusters
+// analytics job, those with a domain first seen most recently first, so
+// analysts can spot campaigns as they register.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListDomainClusters(ctx context.Context, req *pb.ListDomainClustersRequest) (*pb.ListDomainClustersResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "ListDomainClusters", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	since, err := optionalTime("active_since", req.ActiveSince)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 {
+		limit = defaultClusterLimit
+	}
+	if limit > maxClusterLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be at most %d", maxClusterLimit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cluster_id, nameservers, addresses, domain_count, first_seen_from, first_seen_to, computed_at
+		FROM domain_clusters
+		WHERE domain_count >= $1 AND first_seen_to >= $2
+		ORDER BY first_seen_to DESC, domain_count DESC, cluster_id
+		LIMIT $3
+	`, req.MinSize, since, limit)
+	if err != nil {
+		errorf("ListDomainClusters: Failed to query clusters: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query clusters: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.ListDomainClustersResponse{}
+	for rows.Next() {
+		c, err := scanDomainCluster(rows)
+		if err != nil {
+			errorf("ListDomainClusters: Failed to scan cluster: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan cluster: %v", err)
+		}
+		resp.Clusters = append(resp.Clusters, c)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListDomainClusters: Failed to iterate clusters: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate clusters: %v", err)
+	}
+	infof("ListDomainClusters: Returning %d clusters for API key %s", len(resp.Clusters), apiKey)
+	return resp, nil
+}
+
+// GetDomainCluster returns a cluster found by the domain-clusters analytics
+// job and its domains, first seen first, looked up by ID or by one of its
+// domains. The job never clusters restricted domains; domains restricted
+// since it ran are left out for keys of other organizations, and looking a
+// cluster up by such a domain answers as for an unclustered one.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetDomainCluster(ctx context.Context, req *pb.GetDomainClusterRequest) (*pb.DomainCluster, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetDomainCluster", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	clusterID := strings.ToLower(strings.TrimSpace(req.ClusterId))
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Domain), "."))
+	if clusterID == "" && domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cluster_id or domain is required")
+	}
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 {
+		limit = defaultClusterDomainLimit
+	}
+	if limit > maxClusterLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be at most %d", maxClusterLimit)
+	}
+
+	if clusterID == "" {
+		visible, err := s.domainVisible(ctx, "GetDomainCluster", prefs, domain)
+		if err != nil {
+			return nil, err
+		}
+		err = sql.ErrNoRows
+		if visible {
+			err = s.db.QueryRowContext(ctx, "SELECT cluster_id FROM domain_cluster_members WHERE domain_name = $1 LIMIT 1", domain).Scan(&clusterID)
+		}
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "domain %s is not in a cluster", domain)
+		}
+		if err != nil {
+			errorf("GetDomainCluster: Failed to look up the cluster of domain %s: %v", domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to look up cluster: %v", err)
+		}
+	}
+
+	c, err := scanDomainCluster(s.db.QueryRowContext(ctx, `
+		SELECT cluster_id, nameservers, addresses, domain_count, first_seen_from, first_seen_to, computed_at
+		FROM domain_clusters
+		WHERE cluster_id = $1
+	`, clusterID))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "cluster %s not found", clusterID)
+	}
+	if err != nil {
+		errorf("GetDomainCluster: Failed to query cluster %s: %v", clusterID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query cluster: %v", err)
+	}
+
+	// One more than the limit tells whether the cluster was truncated
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT domain_name, first_seen
+		FROM domain_cluster_members
+		WHERE cluster_id = $1
+		ORDER BY first_seen, domain_name
+		LIMIT $2
+	`, clusterID, limit+1)
+	if err != nil {
+		errorf("GetDomainCluster: Failed to query domains of cluster %s: %v", clusterID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query cluster domains: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d pb.ClusterDomain
+		var firstSeen time.Time
+		if err := rows.Scan(&d.Domain, &firstSeen); err != nil {
+			errorf("GetDomainCluster: Failed to scan domain of cluster %s: %v", clusterID, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan cluster domain: %v", err)
+		}
+		d.FirstSeen = firstSeen.Format(time.RFC3339)
+		c.Domains = append(c.Domains, &d)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetDomainCluster: Failed to iterate domains of cluster %s: %v", clusterID, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate cluster domains: %v", err)
+	}
+	if len(c.Domains) > limit {
+		c.Domains = c.Domains[:limit]
+		c.Truncated = true
+	}
+	if c.Domains, err = s.visibleClusterDomains(ctx, prefs, c.Domains); err != nil {
+		errorf("GetDomainCluster: Failed to check visibility of the domains of cluster %s: %v", clusterID, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up cluster domains: %v", err)
+	}
+	infof("GetDomainCluster: Returning cluster %s with %d of %d domains for API key %s", clusterID, len(c.Domains), c.DomainCount, apiKey)
+	return c, nil
+}
+
+// visibleClusterDomains leaves out the domains restricted to other
+// organizations than the caller's, asking each shard about its domains.
+func (s *server) visibleClusterDomains(ctx context.Context, prefs *keyPreferences, domains []*pb.ClusterDomain) ([]*pb.ClusterDomain, error) {
+	byShard := make(map[*storage.Shard][]string)
+	for _, d := range domains {
+		shard := s.shards.ForDomain(d.Domain)
+		byShard[shard] = append(byShard[shard], d.Domain)
+	}
+	hidden := make(map[string]bool)
+	for shard, names := range byShard {
+		rows, err := shard.Reader(ctx).QueryContext(ctx, `
+			SELECT domain_name FROM domains
+			WHERE domain_name = ANY($1) AND restricted_to IS NOT NULL AND NOT ($2 = ANY(restricted_to))
+		`, pq.Array(names), prefs.orgID)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			hidden[name] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if len(hidden) == 0 {
+		return domains, nil
+	}
+	visible := domains[:0]
+	for _, d := range domains {
+		if !hidden[d.Domain] {
+			visible = append(visible, d)
+		}
+	}
+	return visible, nil
+}
+
+// scanDomainCluster scans a domain_clusters row selected as in
+// ListDomainClusters.
+func scanDomainCluster(row rowScanner) (*pb.DomainCluster, error) {
+	var c pb.DomainCluster
+	var from, to, computedAt time.Time
+	if err := row.Scan(&c.ClusterId, pq.Array(&c.Nameservers), pq.Array(&c.Addresses), &c.DomainCount, &from, &to, &computedAt); err != nil {
+		return nil, err
+	}
+	c.FirstSeenFrom = from.Format(time.RFC3339)
+	c.FirstSeenTo = to.Format(time.RFC3339)
+	c.ComputedAt = computedAt.Format(time.RFC3339)
+	return &c, nil
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// CountDomains counts the domains matching a TLD, name pattern and
+// first-seen range, so UIs can show totals without listing the domains.
+// Domains restricted to other organizations are not counted.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Counts
+// the planner expects to exceed counts.exact_threshold on a shard, or that
+// take longer than counts.timeout_seconds, are estimated and flagged.
+func (s *server) CountDomains(ctx context.Context, req *pb.CountDomainsRequest) (*pb.CountResponse, error) {
+	prefs, err := s.keyPreferences(ctx, "CountDomains", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, ". "))
+	pattern := strings.ToLower(strings.TrimSpace(req.Pattern))
+	after, err := optionalTime("first_seen_after", req.FirstSeenAfter)
+	if err != nil {
+		return nil, err
+	}
+	before, err := optionalTime("first_seen_before", req.FirstSeenBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := func(q *storage.Query) {
+		q.WhereVisible("d", prefs.orgID)
+		if tld != "" {
+			q.WhereTLD("d", tld)
+		}
+		if pattern != "" {
+			q.Where("d.domain_name LIKE ?", globPattern(pattern))
+		}
+		if !after.IsZero() {
+			q.Where("d.first_seen > ?", after)
+		}
+		if !before.IsZero() {
+			q.Where("d.first_seen < ?", before)
+		}
+	}
+	return s.count(ctx, "CountDomains", tld, "FROM domains d", filter, req.Exact)
+}
+
+// CountRecords counts the DNS records matching a TLD, domain name pattern,
+// record type, source and record data pattern. Records of domains
+// restricted to other organizations are not counted.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Large or
+// slow counts are estimated as in CountDomains.
+func (s *server) CountRecords(ctx context.Context, req *pb.CountRecordsRequest) (*pb.CountResponse, error) {
+	prefs, err := s.keyPreferences(ctx, "CountRecords", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, ". "))
+	domainPattern := strings.ToLower(strings.TrimSpace(req.DomainPattern))
+	recordType := strings.ToUpper(req.RecordType)
+	if _, ok := dns.StringToType[recordType]; recordType != "" && !ok {
+		return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": req.RecordType}, "unknown record type %q", req.RecordType)
+	}
+	source := strings.ToUpper(req.Source)
+
+	// Only join domains when filtering on them
+	from := storage.SQL("FROM dns_records r")
+	if tld != "" || domainPattern != "" {
+		from = "FROM dns_records r JOIN domains d ON d.id = r.domain_id"
+	}
+	filter := func(q *storage.Query) {
+		if tld != "" || domainPattern != "" {
+			q.WhereVisible("d", prefs.orgID)
+		} else {
+			q.WhereRecordVisible("r", prefs.orgID)
+		}
+		if tld != "" {
+			q.WhereTLD("d", tld)
+		}
+		if domainPattern != "" {
+			q.Where("d.domain_name LIKE ?", globPattern(domainPattern))
+		}
+		if recordType != "" {
+			q.Where("r.record_type = ?", recordType)
+		}
+		if source != "" {
+			q.Where("r.source = ?", source)
+		}
+		if req.DataPattern != "" {
+			q.Where("r.record_data LIKE ?", globPattern(req.DataPattern))
+		}
+	}
+	return s.count(ctx, "CountRecords", tld, from, filter, req.Exact)
+}
+
+// optionalTime parses an optional RFC 3339 request field, returning the
+// zero time if it is empty.
+func optionalTime(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid %s %q; must be RFC 3339", field, value)
+	}
+	return t.UTC(), nil
+}
+
+// globPattern converts a glob, where * matches any run of characters and ?
+// any one, to a LIKE pattern.
+func globPattern(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// count adds up the rows of from matching filter on the shard owning tld,
+// or on every healthy shard if tld is empty.
+func (s *server) count(ctx context.Context, method, tld string, from storage.SQL, filter func(*storage.Query), exact bool) (*pb.CountResponse, error) {
+	resp := &pb.CountResponse{}
+	var mu sync.Mutex
+	countShard := func(ctx context.Context, shard *storage.Shard) error {
+		n, estimated, err := s.countShard(ctx, shard.DB, from, filter, exact)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		resp.Count += n
+		resp.Estimated = resp.Estimated || estimated
+		mu.Unlock()
+		return nil
+	}
+	var err error
+	if tld != "" {
+		err = countShard(ctx, s.shards.ForTLD(tld))
+	} else {
+		resp.SkippedShards, err = s.shards.FanOut(ctx, countShard)
+	}
+	if err != nil {
+		errorf("%s: Failed to count: %v", method, err)
+		return nil, status.Errorf(codes.Internal, "failed to count: %v", err)
+	}
+	infof("%s: Counted %d (estimated %t)", method, resp.Count, resp.Estimated)
+	return resp, nil
+}
+
+// countShard counts the rows of from matching filter on one shard. It asks
+// the planner first and counts exactly only if the planner expects at most
+// counts.exact_threshold rows or exact is set; an exact count that runs
+// past counts.timeout_seconds is abandoned for the estimate.
+func (s *server) countShard(ctx context.Context, db *sql.DB, from storage.SQL, filter func(*storage.Query), exact bool) (int64, bool, error) {
+	explain := storage.NewQuery("EXPLAIN (FORMAT JSON) SELECT 1").Append(from)
+	filter(explain)
+	var plan []byte
+	if err := db.QueryRowContext(ctx, explain.SQL(), explain.Args()...).Scan(&plan); err != nil {
+		return 0, false, fmt.Errorf("failed to plan count: %v", err)
+	}
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &plans); err != nil || len(plans) == 0 {
+		return 0, false, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+	estimate := int64(plans[0].Plan.Rows)
+	if !exact && estimate > s.countThreshold {
+		return estimate, true, nil
+	}
+
+	countCtx, cancel := context.WithTimeout(ctx, s.countTimeout)
+	defer cancel()
+	q := storage.NewQuery("SELECT COUNT(*)").Append(from)
+	filter(q)
+	var n int64
+	err := db.QueryRowContext(countCtx, q.SQL(), q.Args()...).Scan(&n)
+	if err != nil && countCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		debugf("Count exceeded %v; returning the planner's estimate of %d", s.countTimeout, estimate)
+		return estimate, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return n, false, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/moos3/bell/coverage"
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// GetTLDCoverage reports the TLDs of IANA's list (iana_tlds, loaded by the
+// analytics iana-tlds job) whose zones have never been ingested. Each gap
+// is sized by coverage.estimated_sizes or, failing that, by the domains of
+// the TLD the corpus holds from other sources, and lists where its domains
+// could be obtained (see coverage.Sources). Shards that cannot be counted
+// leave their domains out of the observed counts.
+//
+// It requires an API key with the admin scope and returns FailedPrecondition
+// until the TLD list has been loaded.
+func (s *server) GetTLDCoverage(ctx context.Context, req *pb.GetTLDCoverageRequest) (*pb.GetTLDCoverageResponse, error) {
+	kind := strings.ToUpper(req.Kind)
+	if kind != "" && kind != coverage.KindCountryCode && kind != coverage.KindGeneric {
+		return nil, status.Errorf(codes.InvalidArgument, "kind must be %s or %s", coverage.KindCountryCode, coverage.KindGeneric)
+	}
+
+	resp := &pb.GetTLDCoverageResponse{}
+	var updatedAt sql.NullTime
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE EXISTS (
+			SELECT 1 FROM processed_tlds p WHERE p.tld = i.tld AND p.last_processed IS NOT NULL
+		)), MAX(updated_at)
+		FROM iana_tlds i
+	`).Scan(&resp.IanaTlds, &resp.CoveredTlds, &updatedAt); err != nil {
+		errorf("GetTLDCoverage: Failed to count TLDs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to count TLDs: %v", err)
+	}
+	if resp.IanaTlds == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "the IANA TLD list has not been loaded; run analytics -job iana-tlds")
+	}
+	resp.IanaUpdatedAt = updatedAt.Time.Format(time.RFC3339)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT tld, kind
+		FROM iana_tlds i
+		WHERE NOT EXISTS (SELECT 1 FROM processed_tlds p WHERE p.tld = i.tld AND p.last_processed IS NOT NULL)
+		ORDER BY tld
+	`)
+	if err != nil {
+		errorf("GetTLDCoverage: Failed to query missing TLDs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query missing TLDs: %v", err)
+	}
+	defer rows.Close()
+	gaps := make(map[string]*pb.TLDCoverageGap)
+	var tlds []string
+	for rows.Next() {
+		gap := &pb.TLDCoverageGap{}
+		if err := rows.Scan(&gap.Tld, &gap.Kind); err != nil {
+			errorf("GetTLDCoverage: Failed to scan TLD: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan TLD: %v", err)
+		}
+		if gap.Kind == coverage.KindCountryCode {
+			resp.CctldGaps++
+		} else {
+			resp.GtldGaps++
+		}
+		if kind != "" && gap.Kind != kind {
+			continue
+		}
+		gaps[gap.Tld] = gap
+		tlds = append(tlds, gap.Tld)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetTLDCoverage: Failed to iterate TLDs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate TLDs: %v", err)
+	}
+
+	for _, shard := range s.shards.Shards() {
+		counts, err := shard.Reader(ctx).QueryContext(ctx, "SELECT tld, COUNT(*) FROM domains WHERE tld = ANY($1) GROUP BY tld", pq.Array(tlds))
+		if err != nil {
+			errorf("GetTLDCoverage: Failed to count domains on shard %s: %v", shard.Name, err)
+			continue
+		}
+		for counts.Next() {
+			var tld string
+			var n int64
+			if err := counts.Scan(&tld, &n); err != nil {
+				errorf("GetTLDCoverage: Failed to scan domain count on shard %s: %v", shard.Name, err)
+				break
+			}
+			if gap, ok := gaps[tld]; ok {
+				gap.ObservedDomains += n
+			}
+		}
+		counts.Close()
+	}
+
+	for _, tld := range tlds {
+		gap := gaps[tld]
+		gap.EstimatedDomains = gap.ObservedDomains
+		if size, ok := s.coverageSizes[tld]; ok && size > 0 {
+			gap.EstimatedDomains = size
+			gap.EstimateConfigured = true
+		}
+		gap.Sources = coverage.Sources(tld, s.coverageSources)
+		resp.Gaps = append(resp.Gaps, gap)
+	}
+	sort.SliceStable(resp.Gaps, func(i, j int) bool {
+		return resp.Gaps[i].EstimatedDomains > resp.Gaps[j].EstimatedDomains
+	})
+	infof("GetTLDCoverage: %d of %d IANA TLDs covered; returning %d gaps", resp.CoveredTlds, resp.IanaTlds, len(resp.Gaps))
+	return resp, nil
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/storage"
+)
+
+const (
+	maxImportDomains         = 10000 // Per ImportDomains call
+	maxRejectedImportDomains = 100
+)
+
+// normalizeImportDomain lowercases a domain submitted to ImportDomains and
+// checks that it is a name under a public suffix.
+func normalizeImportDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return "", fmt.Errorf("empty")
+	}
+	if _, ok := dns.IsDomainName(domain); !ok || !strings.Contains(domain, ".") {
+		return "", fmt.Errorf("not a domain name")
+	}
+	if strings.Trim(domain, "abcdefghijklmnopqrstuvwxyz0123456789-._") != "" {
+		return "", fmt.Errorf("invalid characters; use punycode for internationalized names")
+	}
+	if recordset.IsPublicSuffix(domain) {
+		return "", fmt.Errorf("public suffix, not a domain")
+	}
+	return domain, nil
+}
+
+// ImportDomains adds domains to those the caller's organization tracks in
+// customer_domains, with source CUSTOMER. Domains bell does not know, such
+// as those of TLDs without zone-file access, are inserted on the shard of
+// their TLD without nameservers; the query worker resolves tracked domains
+// ahead of its sweep of the zones, looking their nameservers up first.
+// Invalid domains are reported rather than failing the import.
+//
+// A private import restricts the domains it inserts to the organization's
+// keys (domains.restricted_to), and adds the organization to those already
+// restricted to others. Domains bell already serves to every key stay
+// public and are counted in public_domains; an admin can restrict them with
+// SetDomainRestriction.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key").
+func (s *server) ImportDomains(ctx context.Context, req *pb.ImportDomainsRequest) (*pb.ImportDomainsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "ImportDomains", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Domains) > maxImportDomains {
+		return nil, statusError(codes.InvalidArgument, reasonTooManyDomains, map[string]string{"quota_limit": strconv.Itoa(maxImportDomains)}, "at most %d domains per request", maxImportDomains)
+	}
+
+	resp := &pb.ImportDomainsResponse{}
+	seen := make(map[string]bool)
+	var domains []string
+	for _, d := range req.Domains {
+		domain, err := normalizeImportDomain(d)
+		if err != nil {
+			resp.RejectedCount++
+			if len(resp.Rejected) < maxRejectedImportDomains {
+				resp.Rejected = append(resp.Rejected, &pb.RejectedDomain{Domain: d, Reason: err.Error()})
+			}
+			continue
+		}
+		if seen[domain] {
+			resp.AlreadyTracked++
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+
+	tx, err := s.keys.BeginTx(ctx, nil)
+	if err != nil {
+		errorf("ImportDomains: Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to import domains: %v", err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO customer_domains (organization_id, domain_name, tld, source, added_by)
+		VALUES ($1, $2, $3, 'CUSTOMER', $4)
+		ON CONFLICT (organization_id, domain_name) DO NOTHING
+	`)
+	if err != nil {
+		errorf("ImportDomains: Failed to prepare insert: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to import domains: %v", err)
+	}
+	defer stmt.Close()
+	added := make(map[*storage.Shard][]string)
+	for _, domain := range domains {
+		result, err := stmt.ExecContext(ctx, orgID, domain, recordset.TLD(domain), apiKey)
+		if err != nil {
+			errorf("ImportDomains: Failed to add %s to organization %d: %v", domain, orgID, err)
+			return nil, status.Errorf(codes.Internal, "failed to import domains: %v", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			resp.AlreadyTracked++
+			continue
+		}
+		resp.Added++
+		shard := s.shards.ForDomain(domain)
+		added[shard] = append(added[shard], domain)
+	}
+	restrictTo := 0
+	if req.Private {
+		restrictTo = orgID
+	}
+	for shard, domains := range added {
+		inserted, public, err := insertCustomerDomains(ctx, shard, domains, restrictTo)
+		if err != nil {
+			errorf("ImportDomains: Failed to insert domains on shard %s: %v", shard.Name, err)
+			return nil, status.Errorf(codes.Internal, "failed to import domains: %v", err)
+		}
+		resp.NewDomains += inserted
+		resp.PublicDomains += public
+	}
+	if err := tx.Commit(); err != nil {
+		errorf("ImportDomains: Failed to commit domains of organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to import domains: %v", err)
+	}
+	if s.domains != nil {
+		for _, domains := range added {
+			for _, domain := range domains {
+				s.domains.add(domain)
+			}
+		}
+	}
+	infof("ImportDomains: API key %s imported %d domains for organization %d (private %t): %d added (%d new, %d public), %d already tracked, %d rejected",
+		apiKey, len(req.Domains), orgID, req.Private, resp.Added, resp.NewDomains, resp.PublicDomains, resp.AlreadyTracked, resp.RejectedCount)
+	return resp, nil
+}
+
+// insertCustomerDomains inserts the domains bell does not know yet on shard,
+// due for querying and without nameservers, returning how many it inserted.
+// If restrictTo is set, the domains it inserts are restricted to that
+// organization, which is added to known domains restricted to others; the
+// known public domains are counted in public.
+func insertCustomerDomains(ctx context.Context, shard *storage.Shard, domains []string, restrictTo int) (inserted, public int32, err error) {
+	tx, err := shard.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO domains (domain_name, tld, public_suffix, last_updated, restricted_to)
+		VALUES ($1, $2, $3, NULL, CASE WHEN $4 > 0 THEN ARRAY[$4::integer] END)
+		ON CONFLICT (domain_name, tld) DO UPDATE
+			SET restricted_to = array_append(domains.restricted_to, $4::integer)
+			WHERE $4 > 0 AND domains.restricted_to IS NOT NULL AND NOT ($4 = ANY(domains.restricted_to))
+		RETURNING xmax = 0, restricted_to IS NULL
+	`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer stmt.Close()
+	for _, domain := range domains {
+		// No row comes back for known domains left as they were
+		var isNew, isPublic bool
+		err := stmt.QueryRowContext(ctx, domain, recordset.TLD(domain), recordset.PublicSuffix(domain), restrictTo).Scan(&isNew, &isPublic)
+		if err == sql.ErrNoRows {
+			if restrictTo > 0 {
+				var restricted bool
+				if err := tx.QueryRowContext(ctx, "SELECT restricted_to IS NOT NULL FROM domains WHERE domain_name = $1 AND tld = $2", domain, recordset.TLD(domain)).Scan(&restricted); err != nil {
+					return 0, 0, err
+				}
+				if !restricted {
+					public++
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		if isNew {
+			inserted++
+		}
+	}
+	return inserted, public, tx.Commit()
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+)
+
+// daneTimeout bounds the TLS handshake (and SMTP greeting) with the service.
+const daneTimeout = 15 * time.Second
+
+// TLSA certificate usages (RFC 6698 section 2.1.1).
+const (
+	usagePKIXTA = 0
+	usagePKIXEE = 1
+	usageDANETA = 2
+	usageDANEEE = 3
+)
+
+// ValidateDANE fetches the live certificate chain of a TLS service and
+// checks it against the TLSA records stored for _port._tcp.domain.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Ports 25
+// and 587 are reached through SMTP STARTTLS; every other port is expected to
+// speak TLS directly. DNSSEC is not checked: the stored records are trusted
+// as collected.
+func (s *server) ValidateDANE(ctx context.Context, req *pb.ValidateDANERequest) (*pb.ValidateDANEResponse, error) {
+	host := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	port := req.Port
+	if port == 0 {
+		port = 443
+	}
+	if port < 0 || port > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", port)
+	}
+	name := fmt.Sprintf("_%d._tcp.%s", port, host)
+
+	prefs, err := s.keyPreferences(ctx, "ValidateDANE", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.storedTLSA(ctx, name, prefs.orgID)
+	if err != nil {
+		errorf("ValidateDANE: Failed to query TLSA records for %s: %v", name, err)
+		return nil, status.Errorf(codes.Internal, "failed to query TLSA records: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, statusError(codes.NotFound, reasonTLSANotFound, map[string]string{"name": name}, "no TLSA records stored for %s", name)
+	}
+
+	chain, err := fetchCertificateChain(ctx, host, int(port))
+	if err != nil {
+		errorf("ValidateDANE: Failed to fetch certificate chain from %s:%d: %v", host, port, err)
+		return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"retry_after": upstreamRetryAfter}, "failed to fetch certificate chain: %v", err)
+	}
+
+	resp := &pb.ValidateDANEResponse{Name: name}
+	for _, cert := range chain {
+		resp.Chain = append(resp.Chain, cert.Subject.String())
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	verified, err := chain[0].Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	if err != nil {
+		resp.PkixError = err.Error()
+	} else {
+		resp.PkixValid = true
+	}
+
+	for _, tlsa := range records {
+		v := matchTLSA(tlsa, chain, verified)
+		resp.Records = append(resp.Records, v)
+		resp.Valid = resp.Valid || v.Match
+	}
+	infof("ValidateDANE: %s valid=%v pkix_valid=%v (%d records)", name, resp.Valid, resp.PkixValid, len(records))
+	return resp, nil
+}
+
+// storedTLSA returns the distinct TLSA records owned by name, whether stored
+// under the name itself (zone files) or under its domain (query worker), if
+// visible to the keys of organization orgID.
+func (s *server) storedTLSA(ctx context.Context, name string, orgID int) ([]*dns.TLSA, error) {
+	rows, err := s.shards.ForDomain(name).DB.QueryContext(ctx, `
+		SELECT r.record_data
+		FROM domains d
+		JOIN dns_records r ON d.id = r.domain_id
+		WHERE d.domain_name IN ($1, $2) AND r.record_type = 'TLSA' AND (d.restricted_to IS NULL OR $3 = ANY(d.restricted_to))
+	`, name, recordset.OwnerDomain(name), orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	seen := make(map[string]bool)
+	var records []*dns.TLSA
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		rr, err := dns.NewRR(data)
+		if err != nil || rr == nil {
+			continue
+		}
+		tlsa, ok := rr.(*dns.TLSA)
+		if !ok || strings.TrimSuffix(dns.CanonicalName(tlsa.Hdr.Name), ".") != name {
+			continue
+		}
+		key := fmt.Sprintf("%d %d %d %s", tlsa.Usage, tlsa.Selector, tlsa.MatchingType, strings.ToLower(tlsa.Certificate))
+		if !seen[key] {
+			seen[key] = true
+			records = append(records, tlsa)
+		}
+	}
+	return records, rows.Err()
+}
+
+// matchTLSA checks one TLSA record against the presented chain. End-entity
+// usages match the server certificate only; trust anchor usages match any
+// certificate above it, including the root of a PKIX-verified chain. PKIX
+// usages additionally require the chain to verify against system roots.
+func matchTLSA(tlsa *dns.TLSA, chain []*x509.Certificate, verified [][]*x509.Certificate) *pb.TLSAValidation {
+	v := &pb.TLSAValidation{
+		Record:       tlsa.String(),
+		Usage:        int32(tlsa.Usage),
+		Selector:     int32(tlsa.Selector),
+		MatchingType: int32(tlsa.MatchingType),
+		MatchedDepth: -1,
+	}
+	var candidates []*x509.Certificate
+	switch tlsa.Usage {
+	case usagePKIXEE, usageDANEEE:
+		candidates = chain[:1]
+	case usagePKIXTA, usageDANETA:
+		candidates = chain
+		if len(verified) > 0 && len(verified[0]) > len(chain) {
+			candidates = append(candidates, verified[0][len(verified[0])-1])
+		}
+	default:
+		v.Reason = "unknown certificate usage " + strconv.Itoa(int(tlsa.Usage))
+		return v
+	}
+	for depth, cert := range candidates {
+		if depth == 0 && (tlsa.Usage == usagePKIXTA || tlsa.Usage == usageDANETA) {
+			continue
+		}
+		data, err := dns.CertificateToDANE(tlsa.Selector, tlsa.MatchingType, cert)
+		if err != nil {
+			v.Reason = fmt.Sprintf("unsupported selector %d or matching type %d", tlsa.Selector, tlsa.MatchingType)
+			return v
+		}
+		if strings.EqualFold(data, tlsa.Certificate) {
+			v.MatchedDepth = int32(depth)
+			break
+		}
+	}
+	switch {
+	case v.MatchedDepth < 0:
+		v.Reason = "no certificate in the chain matches"
+	case (tlsa.Usage == usagePKIXTA || tlsa.Usage == usagePKIXEE) && len(verified) == 0:
+		v.Reason = "certificate matches but the chain fails PKIX validation"
+	default:
+		v.Match = true
+	}
+	return v
+}
+
+// fetchCertificateChain performs a TLS handshake with host:port and returns
+// the certificates the server presents, server certificate first.
+func fetchCertificateChain(ctx context.Context, host string, port int) ([]*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, daneTimeout)
+	defer cancel()
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	// The chain is validated against TLSA records and system roots by the
+	// caller; the handshake itself must not reject DANE-only certificates.
+	cfg := &tls.Config{ServerName: host, InsecureSkipVerify: true}
+
+	if port == 25 || port == 587 {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		c, err := smtp.NewClient(conn, host)
+		if err != nil {
+			return nil, err
+		}
+		defer c.Close()
+		if err := c.StartTLS(cfg); err != nil {
+			return nil, fmt.Errorf("STARTTLS: %v", err)
+		}
+		state, _ := c.TLSConnectionState()
+		c.Quit()
+		if len(state.PeerCertificates) == 0 {
+			return nil, fmt.Errorf("no certificates presented")
+		}
+		return state.PeerCertificates, nil
+	}
+
+	d := tls.Dialer{Config: cfg}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates presented")
+	}
+	return certs, nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// ListDNSServers returns the recursive upstreams of the server's resolver,
+// which the query workers share through the dns_servers table, with the
+// health the server's own probes found.
+//
+// It requires an API key with the admin scope.
+func (s *server) ListDNSServers(ctx context.Context, req *pb.ListDNSServersRequest) (*pb.ListDNSServersResponse, error) {
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	return s.dnsServers(), nil
+}
+
+// UpdateDNSServers adds and removes recursive upstreams. The edits are
+// stored in the dns_servers table, applied to the server's resolver at once
+// and picked up by query workers within resolver.servers_refresh_seconds.
+// Edits that would leave no upstream for resolver.transport are rejected.
+//
+// It requires an API key with the admin scope.
+func (s *server) UpdateDNSServers(ctx context.Context, req *pb.UpdateDNSServersRequest) (*pb.ListDNSServersResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	edits := make(map[string]bool) // Address -> enabled; a server both added and removed is removed
+	for _, specs := range []struct {
+		list    []string
+		enabled bool
+	}{{req.Add, true}, {req.Remove, false}} {
+		for _, spec := range specs.list {
+			address, err := s.resolver.NormalizeServer(spec)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+			}
+			edits[address] = specs.enabled
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		errorf("UpdateDNSServers: Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update DNS servers: %v", err)
+	}
+	defer tx.Rollback()
+	for address, enabled := range edits {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dns_servers (address, enabled, updated_by, updated_at) VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (address) DO UPDATE SET enabled = EXCLUDED.enabled, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		`, address, enabled, apiKey); err != nil {
+			errorf("UpdateDNSServers: Failed to store %s: %v", address, err)
+			return nil, status.Errorf(codes.Internal, "failed to update DNS servers: %v", err)
+		}
+	}
+	specs, err := s.resolver.ActiveServers(ctx, tx)
+	if err != nil {
+		errorf("UpdateDNSServers: Failed to load DNS servers: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to load DNS servers: %v", err)
+	}
+	if err := s.resolver.CheckUpstreams(specs); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		errorf("UpdateDNSServers: Failed to commit: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update DNS servers: %v", err)
+	}
+	if err := s.resolver.SetUpstreams(specs); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to apply DNS servers: %v", err)
+	}
+	infof("UpdateDNSServers: API key %s added %v and removed %v; upstreams now %v", apiKey, req.Add, req.Remove, specs)
+	return s.dnsServers(), nil
+}
+
+func (s *server) dnsServers() *pb.ListDNSServersResponse {
+	resp := &pb.ListDNSServersResponse{}
+	for _, u := range s.resolver.Upstreams() {
+		server := &pb.DNSServer{
+			Address:             u.Address,
+			Transport:           u.Transport,
+			Healthy:             u.Healthy,
+			ConsecutiveFailures: int32(u.ConsecutiveFailures),
+			LastError:           u.LastError,
+		}
+		if !u.LastChecked.IsZero() {
+			server.LastChecked = u.LastChecked.Format(time.RFC3339)
+		}
+		resp.Servers = append(resp.Servers, server)
+	}
+	return resp
+}
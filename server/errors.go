@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"path"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of every error the server returns.
+const errorDomain = "bell"
+
+// ErrorInfo reasons for errors that clients are expected to handle. Errors
+// without a specific reason carry the status code in upper snake case (e.g.
+// INVALID_ARGUMENT, INTERNAL).
+const (
+	reasonMissingKey         = "MISSING_KEY"        // No x-api-key metadata
+	reasonInvalidKey         = "INVALID_KEY"        // Key not in api_keys
+	reasonKeyInactive        = "KEY_INACTIVE"       // Key exists but has been deactivated
+	reasonKeyExpired         = "KEY_EXPIRED"        // Key is past its expires_at
+	reasonAdminKeyRequired   = "ADMIN_KEY_REQUIRED" // Admin RPC called without an admin key
+	reasonScopeRequired      = "SCOPE_REQUIRED"     // Key lacks the scope the RPC requires (authorization); metadata scope, rpc
+	reasonTooManyDomains     = "TOO_MANY_DOMAINS"   // Batch over its per-request limit; metadata quota_limit
+	reasonInvalidSnapshot    = "INVALID_SNAPSHOT_TOKEN"
+	reasonDomainNotFound     = "DOMAIN_NOT_FOUND"
+	reasonTLDNotIngested     = "TLD_NOT_INGESTED"
+	reasonUpstreamFailed     = "UPSTREAM_FAILED" // RDAP, DNS or TLS lookup failed; metadata retry_after
+	reasonResolverDisabled   = "RESOLVER_NOT_CONFIGURED"
+	reasonUnknownRecordType  = "UNKNOWN_RECORD_TYPE"
+	reasonTLSANotFound       = "TLSA_NOT_FOUND"      // No TLSA records stored for the service; metadata name
+	reasonSandboxUnavailable = "SANDBOX_UNAVAILABLE" // Sandbox key used where the sandbox is not configured or lacks the RPC
+	reasonNoOrganization     = "NO_ORGANIZATION"     // Organization RPC called with a key outside any organization
+	reasonOrgAdminRequired   = "ORG_ADMIN_REQUIRED"  // Organization management RPC called without an org admin key
+	reasonQuotaExceeded      = "QUOTA_EXCEEDED"      // Organization's daily request quota, or a monthly quota of the key, used up; metadata quota_limit, retry_after
+	reasonRateLimited        = "RATE_LIMITED"        // Key over rate_limit; metadata rate_limit, retry_after, also sent as the retry-after header
+	reasonExportsDisabled    = "EXPORTS_NOT_CONFIGURED"
+	reasonPatternTooBroad    = "PATTERN_TOO_BROAD" // Domain pattern over wildcards.max_cost; metadata pattern, cost, max_cost
+)
+
+// upstreamRetryAfter is the retry_after hint on UPSTREAM_FAILED errors.
+const upstreamRetryAfter = "30s"
+
+// statusError returns a status error carrying an ErrorInfo with reason and
+// metadata, so clients can branch on the reason instead of the message.
+func statusError(code codes.Code, reason string, metadata map[string]string, format string, args ...interface{}) error {
+	st := status.Newf(code, format, args...)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain, Metadata: metadata}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+// errorInfoInterceptor attaches an ErrorInfo to errors returned without
+// details, using the status code as the reason and the RPC as metadata.
+func errorInfoInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, withErrorInfo(err, info.FullMethod)
+}
+
+// errorInfoStreamInterceptor is errorInfoInterceptor for streaming calls.
+func errorInfoStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return withErrorInfo(handler(srv, ss), info.FullMethod)
+}
+
+// withErrorInfo adds an ErrorInfo to err unless it is nil or has details.
+func withErrorInfo(err error, fullMethod string) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if len(st.Details()) > 0 {
+		return err
+	}
+	return statusError(st.Code(), codeReason(st.Code()), map[string]string{"rpc": path.Base(fullMethod)}, "%s", st.Message())
+}
+
+// codeReason converts a status code to an upper snake case reason, e.g.
+// InvalidArgument to INVALID_ARGUMENT.
+func codeReason(code codes.Code) string {
+	var b strings.Builder
+	for i, r := range code.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
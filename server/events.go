@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/events"
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// tailBuffer is how many events a slow TailEvents or SubscribeRecordChanges
+// subscriber may fall behind before further events are dropped for it.
+const tailBuffer = 256
+
+// keyCache is a per-key cache whose entries the eventHub drops when another
+// server (or this one) reports a change to the key on events.KeyChannel.
+type keyCache interface {
+	forget(apiKey string)
+}
+
+// eventHub listens for events published by the workers on events.Channel and
+// fans them out to TailEvents and SubscribeRecordChanges subscribers. Events
+// are listened for on every shard, since record_set_changed is published on
+// the shard holding the set. It also listens on events.KeyChannel of the
+// default database and drops changed keys from the per-key caches, so role,
+// revocation and preference changes made through one server apply on every
+// server at once rather than when the caches expire.
+type eventHub struct {
+	caches    []keyCache
+	onChanged func(e *pb.IngestEvent) // Called with each record_set_changed event
+
+	mu   sync.Mutex
+	subs map[chan *pb.IngestEvent]bool
+}
+
+// newEventHub starts listening on connStrs, the default database's first and
+// then the other shards'. Lost connections are retried by the listeners;
+// events and key changes published meanwhile are missed, so the caches fall
+// back on their TTLs.
+func newEventHub(connStrs []string, caches []keyCache, onChanged func(e *pb.IngestEvent)) (*eventHub, error) {
+	h := &eventHub{caches: caches, onChanged: onChanged, subs: make(map[chan *pb.IngestEvent]bool)}
+	var listeners []*pq.Listener
+	for i, connStr := range connStrs {
+		listener := pq.NewListener(connStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				warnf("Event listener: %v", err)
+			}
+		})
+		listeners = append(listeners, listener)
+		channels := []string{events.Channel}
+		if i == 0 {
+			channels = append(channels, events.KeyChannel)
+		}
+		for _, channel := range channels {
+			if err := listener.Listen(channel); err != nil {
+				for _, l := range listeners {
+					l.Close()
+				}
+				return nil, err
+			}
+		}
+	}
+	for _, listener := range listeners {
+		go h.listen(listener)
+	}
+	return h, nil
+}
+
+// listen handles the notifications of listener until it is closed.
+func (h *eventHub) listen(listener *pq.Listener) {
+	for n := range listener.Notify {
+		// nil after a reconnect
+		if n == nil {
+			continue
+		}
+		if n.Channel == events.KeyChannel {
+			for _, c := range h.caches {
+				c.forget(n.Extra)
+			}
+			debugf("Event listener: Dropped cached state of API key %s", n.Extra)
+			continue
+		}
+		var e events.Event
+		if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+			debugf("Event listener: Ignoring malformed event: %v", err)
+			continue
+		}
+		msg := &pb.IngestEvent{
+			Time: e.Time, Source: e.Source, Kind: e.Kind, Tld: e.TLD,
+			Domain: e.Domain, Count: e.Count, Message: e.Message,
+			RecordType: e.RecordType, Version: e.Version, Significance: e.Significance,
+		}
+		if e.Kind == events.RecordSetChanged && h.onChanged != nil {
+			h.onChanged(msg)
+		}
+		h.broadcast(msg)
+	}
+}
+
+func (h *eventHub) subscribe() chan *pb.IngestEvent {
+	ch := make(chan *pb.IngestEvent, tailBuffer)
+	h.mu.Lock()
+	h.subs[ch] = true
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan *pb.IngestEvent) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
+
+// broadcast delivers e to every subscriber without blocking on slow ones.
+func (h *eventHub) broadcast(e *pb.IngestEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// recordSetChanged updates what this server caches about a domain whose
+// records the query worker saw change: the domain filter learns of it
+// before its next refresh, and the resolver forgets its cached answers of
+// that type so LookupLive does not serve the old records.
+func (s *server) recordSetChanged(e *pb.IngestEvent) {
+	if s.domains != nil {
+		s.domains.add(e.Domain)
+	}
+	if s.resolver != nil {
+		s.resolver.Forget(e.Domain, e.RecordType)
+	}
+}
+
+// keysChanged tells every API server, this one included, to drop what it
+// caches about apiKeys.
+func (s *server) keysChanged(apiKeys ...string) {
+	for _, apiKey := range apiKeys {
+		events.KeyChanged(s.keys, apiKey)
+	}
+}
@@ -0,0 +1,317 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/signing"
+)
+
+// Values accepted in StartExportRequest; the export worker writes each of
+// them.
+var (
+	exportKinds   = map[string]bool{"DOMAINS": true, "RECORDS": true}
+	exportFormats = map[string]bool{"CSV": true, "JSONL": true}
+)
+
+// Export limits per API key.
+const (
+	maxActiveExports = 3  // PENDING or RUNNING
+	maxListedExports = 50 // Returned by ListExports
+)
+
+// exportColumns are the export_jobs columns scanned by scanExport.
+const exportColumns = `id, kind, format, tld, pattern, record_type, source, data_pattern, first_seen_after, first_seen_before, scrub,
+	status, COALESCE(file_name, ''), row_count, byte_count, skipped_shards, COALESCE(error, ''), created_at, started_at, finished_at, COALESCE(signature, '')`
+
+// StartExport queues an export of the domains or DNS records matching a
+// filter for the export worker, which writes it to exports.output_dir. Poll
+// GetExport until it has succeeded for a download URL.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Sandbox
+// keys cannot export, and a key can have at most maxActiveExports exports
+// queued or running.
+func (s *server) StartExport(ctx context.Context, req *pb.StartExportRequest) (*pb.ExportJob, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if s.sandbox {
+		return nil, statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "exports are not available in the sandbox")
+	}
+	if s.exportKey == nil {
+		return nil, statusError(codes.FailedPrecondition, reasonExportsDisabled, nil, "exports are not configured")
+	}
+	job := &pb.ExportJob{
+		Kind:        strings.ToUpper(req.Kind),
+		Format:      strings.ToUpper(req.Format),
+		Tld:         strings.ToLower(strings.Trim(req.Tld, ". ")),
+		Pattern:     strings.ToLower(strings.TrimSpace(req.Pattern)),
+		RecordType:  strings.ToUpper(req.RecordType),
+		Source:      strings.ToUpper(req.Source),
+		DataPattern: req.DataPattern,
+		Scrub:       req.Scrub,
+		Status:      "PENDING",
+	}
+	if job.Format == "" {
+		job.Format = "CSV"
+	}
+	if !exportKinds[job.Kind] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported export kind %q; must be DOMAINS or RECORDS", req.Kind)
+	}
+	if !exportFormats[job.Format] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format %q; must be CSV or JSONL", req.Format)
+	}
+	if job.Scrub && !s.exportScrub {
+		return nil, statusError(codes.FailedPrecondition, reasonExportsDisabled, nil, "scrubbed exports are not configured")
+	}
+	if job.Kind == "DOMAINS" && (job.RecordType != "" || job.Source != "" || job.DataPattern != "") {
+		return nil, status.Errorf(codes.InvalidArgument, "record_type, source and data_pattern only apply to RECORDS exports")
+	}
+	if _, ok := dns.StringToType[job.RecordType]; job.RecordType != "" && !ok {
+		return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": req.RecordType}, "unknown record type %q", req.RecordType)
+	}
+	after, err := optionalTime("first_seen_after", req.FirstSeenAfter)
+	if err != nil {
+		return nil, err
+	}
+	before, err := optionalTime("first_seen_before", req.FirstSeenBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	var active int
+	err = s.keys.QueryRowContext(ctx, "SELECT COUNT(*) FROM export_jobs WHERE api_key = $1 AND status IN ('PENDING', 'RUNNING')", apiKey).Scan(&active)
+	if err != nil {
+		errorf("StartExport: Failed to count exports for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to count exports: %v", err)
+	}
+	if active >= maxActiveExports {
+		return nil, status.Errorf(codes.ResourceExhausted, "at most %d exports per key can be queued or running", maxActiveExports)
+	}
+	var createdAt time.Time
+	err = s.keys.QueryRowContext(ctx, `
+		INSERT INTO export_jobs (api_key, kind, format, tld, pattern, record_type, source, data_pattern, first_seen_after, first_seen_before, scrub)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`, apiKey, job.Kind, job.Format, job.Tld, job.Pattern, job.RecordType, job.Source, job.DataPattern,
+		nullTime(after), nullTime(before), job.Scrub).Scan(&job.Id, &createdAt)
+	if err != nil {
+		errorf("StartExport: Failed to store export for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to store export: %v", err)
+	}
+	if !after.IsZero() {
+		job.FirstSeenAfter = after.Format(time.RFC3339)
+	}
+	if !before.IsZero() {
+		job.FirstSeenBefore = before.Format(time.RFC3339)
+	}
+	job.CreatedAt = createdAt.Format(time.RFC3339)
+	infof("StartExport: Queued %s export %d for API key %s", job.Kind, job.Id, apiKey)
+	return job, nil
+}
+
+// GetExport returns an export of the caller's API key. Succeeded exports
+// carry a download URL signed with exports.signing_key, valid for
+// exports.url_ttl_minutes; each call issues a fresh one.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"); a key can
+// only see its own exports.
+func (s *server) GetExport(ctx context.Context, req *pb.GetExportRequest) (*pb.ExportJob, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if s.sandbox {
+		return nil, status.Errorf(codes.NotFound, "export %d not found", req.Id)
+	}
+	rows, err := s.keys.QueryContext(ctx, "SELECT "+exportColumns+" FROM export_jobs WHERE id = $1 AND api_key = $2", req.Id, apiKey)
+	if err != nil {
+		errorf("GetExport: Failed to query export %d: %v", req.Id, err)
+		return nil, status.Errorf(codes.Internal, "failed to query export: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			errorf("GetExport: Failed to query export %d: %v", req.Id, err)
+			return nil, status.Errorf(codes.Internal, "failed to query export: %v", err)
+		}
+		return nil, status.Errorf(codes.NotFound, "export %d not found", req.Id)
+	}
+	job, err := s.scanExport(rows)
+	if err != nil {
+		errorf("GetExport: Failed to scan export %d: %v", req.Id, err)
+		return nil, status.Errorf(codes.Internal, "failed to scan export: %v", err)
+	}
+	return job, nil
+}
+
+// ListExports returns the most recent exports of the caller's API key,
+// newest first, with download URLs as in GetExport.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListExports(ctx context.Context, req *pb.ListExportsRequest) (*pb.ListExportsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	resp := &pb.ListExportsResponse{}
+	if s.sandbox {
+		return resp, nil
+	}
+	rows, err := s.keys.QueryContext(ctx, "SELECT "+exportColumns+" FROM export_jobs WHERE api_key = $1 ORDER BY id DESC LIMIT $2", apiKey, maxListedExports)
+	if err != nil {
+		errorf("ListExports: Failed to query exports for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to query exports: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		job, err := s.scanExport(rows)
+		if err != nil {
+			errorf("ListExports: Failed to scan export: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan export: %v", err)
+		}
+		resp.Exports = append(resp.Exports, job)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListExports: Failed to iterate exports: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate exports: %v", err)
+	}
+	return resp, nil
+}
+
+// scanExport scans a row of exportColumns, signing a download URL if the
+// export has succeeded.
+func (s *server) scanExport(rows *sql.Rows) (*pb.ExportJob, error) {
+	var job pb.ExportJob
+	var fileName string
+	var after, before, startedAt, finishedAt sql.NullTime
+	var createdAt time.Time
+	if err := rows.Scan(&job.Id, &job.Kind, &job.Format, &job.Tld, &job.Pattern, &job.RecordType, &job.Source, &job.DataPattern,
+		&after, &before, &job.Scrub, &job.Status, &fileName, &job.Rows, &job.Bytes, pq.Array(&job.SkippedShards), &job.Error,
+		&createdAt, &startedAt, &finishedAt, &job.Signature); err != nil {
+		return nil, err
+	}
+	if after.Valid {
+		job.FirstSeenAfter = after.Time.Format(time.RFC3339)
+	}
+	if before.Valid {
+		job.FirstSeenBefore = before.Time.Format(time.RFC3339)
+	}
+	job.CreatedAt = createdAt.Format(time.RFC3339)
+	if startedAt.Valid {
+		job.StartedAt = startedAt.Time.Format(time.RFC3339)
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = finishedAt.Time.Format(time.RFC3339)
+	}
+	if job.Status == "SUCCEEDED" && finishedAt.Valid {
+		job.ExpiresAt = finishedAt.Time.Add(s.exportRetention).Format(time.RFC3339)
+		if s.exportKey != nil && fileName != "" {
+			expires := time.Now().Add(s.exportURLTTL).Truncate(time.Second)
+			job.DownloadUrl = s.exportURL(job.Id, fileName, expires)
+			job.DownloadUrlExpiresAt = expires.Format(time.RFC3339)
+		}
+	}
+	return &job, nil
+}
+
+// exportURL returns the signed download URL of an export file, valid until
+// expires.
+func (s *server) exportURL(id int32, fileName string, expires time.Time) string {
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", s.exportSignature(id, expires.Unix()))
+	return fmt.Sprintf("%s/downloads/exports/%d/%s?%s", strings.TrimSuffix(s.exportURLBase, "/"), id, path.Base(fileName), q.Encode())
+}
+
+// exportSignature signs an export id and URL expiry with exports.signing_key.
+func (s *server) exportSignature(id int32, expires int64) string {
+	mac := hmac.New(sha256.New, s.exportKey)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// serveExport serves GET /downloads/exports/{id}/{file}, the signed URLs from
+// GetExport, straight from exports.output_dir. The signature stands in for
+// the API key, so the URLs work in browsers and download tools, but the
+// export's key must still be active and have the role whose redaction the
+// export was written with. Range requests are supported so large downloads
+// can resume.
+func (s *server) serveExport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || s.exportKey == nil ||
+		!hmac.Equal([]byte(r.URL.Query().Get("signature")), []byte(s.exportSignature(int32(id), expires))) {
+		http.Error(w, "invalid download signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > expires {
+		http.Error(w, "download URL has expired; call GetExport for a new one", http.StatusGone)
+		return
+	}
+
+	var fileName, signature string
+	var sameRole bool
+	err = s.keys.QueryRowContext(r.Context(), `
+		SELECT j.file_name, COALESCE(j.signature, ''), COALESCE(j.role, '') = k.role
+		FROM export_jobs j
+		JOIN api_keys k ON k.api_key = j.api_key
+		WHERE j.id = $1 AND j.status = 'SUCCEEDED' AND k.is_active AND (k.expires_at IS NULL OR k.expires_at > NOW())
+	`, id).Scan(&fileName, &signature, &sameRole)
+	if err == sql.ErrNoRows || (err == nil && path.Base(fileName) != r.PathValue("file")) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		errorf("serveExport: Failed to look up export %d: %v", id, err)
+		http.Error(w, "failed to look up export", http.StatusInternalServerError)
+		return
+	}
+	if !sameRole {
+		http.Error(w, "the key's role changed since the export ran; start a new export", http.StatusGone)
+		return
+	}
+	f, err := os.Open(filepath.Join(s.exportDir, filepath.FromSlash(fileName)))
+	if err != nil {
+		errorf("serveExport: Failed to open export %d: %v", id, err)
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		errorf("serveExport: Failed to stat export %d: %v", id, err)
+		http.Error(w, "failed to read export", http.StatusInternalServerError)
+		return
+	}
+	contentType := "text/csv; charset=utf-8"
+	if strings.HasSuffix(fileName, ".jsonl") {
+		contentType = "application/jsonl"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if signature != "" {
+		w.Header().Set(signing.Header, signature)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("bell-export-%d-%s", id, path.Base(fileName))))
+	infof("serveExport: Serving export %d (%d bytes)", id, info.Size())
+	http.ServeContent(w, r, fileName, info.ModTime(), f)
+}
+
+// nullTime maps the zero time to NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/moos3/bell/config"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// dataFreshnessHeader carries the age of the records of a response, overall
+// and per source: "age=86400; CZDS=86400; QUERY=3600". The gateway forwards
+// it as X-Data-Freshness.
+const dataFreshnessHeader = "x-data-freshness"
+
+// sourceAges raises the age of each source in ages to the seconds since the
+// least recently observed of its records was last observed.
+func sourceAges(records []*pb.DNSRecord, now time.Time, ages map[string]int64) {
+	for _, r := range records {
+		updated, err := time.Parse(time.RFC3339, r.LastUpdated)
+		if err != nil {
+			continue
+		}
+		age := int64(max(now.Sub(updated), 0) / time.Second)
+		if current, ok := ages[r.Source]; !ok || age > current {
+			ages[r.Source] = age
+		}
+	}
+}
+
+// oldestAge returns the greatest of ages, 0 if there are none.
+func oldestAge(ages map[string]int64) int64 {
+	var oldest int64
+	for _, age := range ages {
+		oldest = max(oldest, age)
+	}
+	return oldest
+}
+
+// freshnessInterceptor sets data_age_seconds on record responses and sends
+// the ages of their records per source in the x-data-freshness header. It
+// runs outside any hedging, so the header is set once per call.
+func freshnessInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	now := time.Now()
+	ages := make(map[string]int64)
+	switch r := resp.(type) {
+	case *pb.GetRecordsResponse:
+		sourceAges(r.Records, now, ages)
+		r.DataAgeSeconds = oldestAge(ages)
+	case *pb.GetRecordsBatchResponse:
+		for _, result := range r.Results {
+			domainAges := make(map[string]int64)
+			sourceAges(result.Records, now, domainAges)
+			result.DataAgeSeconds = oldestAge(domainAges)
+			for source, age := range domainAges {
+				ages[source] = max(ages[source], age)
+			}
+		}
+	default:
+		return resp, nil
+	}
+	if len(ages) > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(dataFreshnessHeader, formatFreshness(ages)))
+	}
+	return resp, nil
+}
+
+// freshnessStreamInterceptor is freshnessInterceptor for StreamRecords. The
+// ages are only known once every record has been sent, so they go in the
+// x-data-freshness trailer instead of the header. Other streams carry no
+// stored records (StreamRecordBatches has their last_seen in its batches).
+func freshnessStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	fs := &freshnessStream{ServerStream: ss, ages: make(map[string]int64)}
+	if err := handler(srv, fs); err != nil {
+		return err
+	}
+	if len(fs.ages) > 0 {
+		ss.SetTrailer(metadata.Pairs(dataFreshnessHeader, formatFreshness(fs.ages)))
+	}
+	return nil
+}
+
+// freshnessStream is a ServerStream that collects the ages of the records
+// it sends per source.
+type freshnessStream struct {
+	grpc.ServerStream
+	ages map[string]int64
+}
+
+func (s *freshnessStream) SendMsg(m interface{}) error {
+	if r, ok := m.(*pb.StreamedRecord); ok && r.Record != nil {
+		sourceAges([]*pb.DNSRecord{r.Record}, time.Now(), s.ages)
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// formatFreshness renders ages as the x-data-freshness header value.
+func formatFreshness(ages map[string]int64) string {
+	sources := make([]string, 0, len(ages))
+	for source := range ages {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	parts := []string{fmt.Sprintf("age=%d", oldestAge(ages))}
+	for _, source := range sources {
+		parts = append(parts, fmt.Sprintf("%s=%d", source, ages[source]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// freshnessSLO promises that target percent of the domains in scope had
+// their records from source observed within maxAge.
+type freshnessSLO struct {
+	name    string
+	source  string
+	watched bool // Only domains organizations track (customer_domains); otherwise every domain with records from source
+	maxAge  time.Duration
+	target  float64
+}
+
+// sloStatus is the latest measurement of one SLO.
+type sloStatus struct {
+	Source            string  `json:"source"`
+	Scope             string  `json:"scope"`
+	MaxAgeSeconds     int64   `json:"max_age_seconds"`
+	TargetPercent     float64 `json:"target_percent"`
+	Domains           int64   `json:"domains"`                      // Domains in scope
+	Fresh             int64   `json:"fresh"`                        // Of those, observed within max_age_seconds
+	CompliancePercent float64 `json:"compliance_percent"`           // 100 without domains
+	Met               bool    `json:"met"`                          // compliance_percent is at least target_percent
+	OldestAgeSeconds  int64   `json:"oldest_age_seconds,omitempty"` // Of the least recently observed domain with records
+	CheckedAt         string  `json:"checked_at,omitempty"`         // RFC 3339; empty before the first measurement
+	Error             string  `json:"error,omitempty"`              // Why the last measurement failed; the counts are from the one before
+}
+
+// freshnessTracker measures compliance with the freshness SLOs on an
+// interval and publishes it through expvar as "freshness", keyed by SLO
+// name. Breaches and recoveries are logged.
+type freshnessTracker struct {
+	db      *sql.DB
+	shards  *storage.Router
+	slos    []freshnessSLO
+	timeout time.Duration
+
+	mu     sync.Mutex
+	status map[string]sloStatus
+}
+
+// newFreshnessTracker returns a tracker of the SLOs of cfg.
+func newFreshnessTracker(db *sql.DB, shards *storage.Router, cfg *config.Config) *freshnessTracker {
+	t := &freshnessTracker{db: db, shards: shards, timeout: time.Duration(cfg.Freshness.TimeoutSeconds) * time.Second, status: make(map[string]sloStatus)}
+	for _, c := range cfg.Freshness.SLOs {
+		slo := freshnessSLO{name: c.Name, source: c.Source, watched: c.Scope == "watched",
+			maxAge: time.Duration(c.MaxAgeHours * float64(time.Hour)), target: c.TargetPercent}
+		t.slos = append(t.slos, slo)
+		t.status[slo.name] = sloStatus{Source: c.Source, Scope: c.Scope, MaxAgeSeconds: int64(slo.maxAge / time.Second), TargetPercent: c.TargetPercent}
+	}
+	expvar.Publish("freshness", expvar.Func(func() interface{} { return t.snapshot() }))
+	return t
+}
+
+// run measures every SLO every interval until ctx is done.
+func (t *freshnessTracker) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, slo := range t.slos {
+			t.measure(ctx, slo)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// measure counts the fresh domains of slo on every shard and updates its
+// status.
+func (t *freshnessTracker) measure(ctx context.Context, slo freshnessSLO) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var watched map[*storage.Shard][]string
+	var domains int64
+	if slo.watched {
+		var err error
+		if watched, domains, err = t.watchedDomains(ctx); err != nil {
+			t.fail(slo, fmt.Errorf("failed to read watched domains: %v", err))
+			return
+		}
+	}
+	var mu sync.Mutex
+	var fresh int64
+	var oldest sql.NullInt64
+	skipped, err := t.shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		var total, shardFresh int64
+		var shardOldest sql.NullInt64
+		var err error
+		if slo.watched {
+			if len(watched[shard]) == 0 {
+				return nil
+			}
+			err = shard.DB.QueryRowContext(ctx, `
+				SELECT COUNT(*) FILTER (WHERE r.oldest >= NOW() - $2 * INTERVAL '1 second'),
+					EXTRACT(EPOCH FROM NOW() - MIN(r.oldest))::BIGINT
+				FROM domains d
+				JOIN LATERAL (SELECT MIN(last_updated) AS oldest FROM dns_records WHERE domain_id = d.id AND source = $1) r ON TRUE
+				WHERE d.domain_name = ANY($3)
+			`, slo.source, slo.maxAge.Seconds(), pq.Array(watched[shard])).Scan(&shardFresh, &shardOldest)
+		} else {
+			err = shard.DB.QueryRowContext(ctx, `
+				SELECT COUNT(*), COUNT(*) FILTER (WHERE oldest >= NOW() - $2 * INTERVAL '1 second'),
+					EXTRACT(EPOCH FROM NOW() - MIN(oldest))::BIGINT
+				FROM (SELECT MIN(last_updated) AS oldest FROM dns_records WHERE source = $1 GROUP BY domain_id) r
+			`, slo.source, slo.maxAge.Seconds()).Scan(&total, &shardFresh, &shardOldest)
+		}
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !slo.watched {
+			domains += total
+		}
+		fresh += shardFresh
+		if shardOldest.Valid && (!oldest.Valid || shardOldest.Int64 > oldest.Int64) {
+			oldest = shardOldest
+		}
+		return nil
+	})
+	if err == nil && len(skipped) > 0 {
+		err = fmt.Errorf("shards %s unavailable", strings.Join(skipped, ", "))
+	}
+	if err != nil {
+		t.fail(slo, err)
+		return
+	}
+
+	compliance := 100.0
+	if domains > 0 {
+		compliance = float64(fresh) * 100 / float64(domains)
+	}
+	t.mu.Lock()
+	st := t.status[slo.name]
+	wasMet, measured := st.Met, st.CheckedAt != ""
+	st.Domains, st.Fresh, st.CompliancePercent = domains, fresh, compliance
+	st.Met = compliance >= slo.target
+	st.OldestAgeSeconds = oldest.Int64
+	st.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+	st.Error = ""
+	t.status[slo.name] = st
+	t.mu.Unlock()
+	switch {
+	case !st.Met && (wasMet || !measured):
+		warnf("Freshness: SLO %s breached: %d of %d domains (%.2f%%) have %s records observed within %v, target %g%%",
+			slo.name, fresh, domains, compliance, slo.source, slo.maxAge, slo.target)
+	case st.Met && !wasMet && measured:
+		infof("Freshness: SLO %s met again: %.2f%% of %d domains fresh", slo.name, compliance, domains)
+	}
+}
+
+// watchedDomains returns the domains organizations track, grouped by the
+// shard they live on, and how many there are.
+func (t *freshnessTracker) watchedDomains(ctx context.Context) (map[*storage.Shard][]string, int64, error) {
+	rows, err := t.db.QueryContext(ctx, "SELECT DISTINCT domain_name FROM customer_domains")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	byShard := make(map[*storage.Shard][]string)
+	var n int64
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, 0, err
+		}
+		shard := t.shards.ForDomain(domain)
+		byShard[shard] = append(byShard[shard], domain)
+		n++
+	}
+	return byShard, n, rows.Err()
+}
+
+// fail records that measuring slo failed, keeping its last counts.
+func (t *freshnessTracker) fail(slo freshnessSLO, err error) {
+	errorf("Freshness: Failed to measure SLO %s: %v", slo.name, err)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st := t.status[slo.name]
+	st.Error = err.Error()
+	t.status[slo.name] = st
+}
+
+func (t *freshnessTracker) snapshot() map[string]sloStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snapshot := make(map[string]sloStatus, len(t.status))
+	for name, st := range t.status {
+		snapshot[name] = st
+	}
+	return snapshot
+}
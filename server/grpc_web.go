@@ -0,0 +1,206 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// grpcWebHandler translates gRPC-Web requests from browsers into native gRPC
+// calls served in-process by the gRPC server, so the web dashboard can call
+// DNSService without an Envoy proxy.
+//
+// Both binary (application/grpc-web, application/grpc-web+proto) and text
+// (application/grpc-web-text) encodings are supported. Trailers are sent as a
+// length-prefixed trailer frame at the end of the response body.
+type grpcWebHandler struct {
+	grpcServer *grpc.Server
+}
+
+// isGRPCWebRequest reports whether r is a gRPC-Web call.
+func isGRPCWebRequest(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web")
+}
+
+// ServeHTTP rewrites the request as HTTP/2 gRPC and hands it to the gRPC server.
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, "application/grpc-web-text")
+
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2"
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = io.NopCloser(&grpcWebTextReader{r: r.Body})
+	}
+
+	respContentType := "application/grpc-web+proto"
+	if text {
+		respContentType = "application/grpc-web-text+proto"
+	}
+	rw := &grpcWebResponseWriter{
+		w:           w,
+		header:      make(http.Header),
+		contentType: respContentType,
+	}
+	if text {
+		rw.body = base64.NewEncoder(base64.StdEncoding, w)
+	} else {
+		rw.body = nopWriteCloser{w}
+	}
+	h.grpcServer.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+// grpcWebTextReader decodes a grpc-web-text request body. Clients may encode
+// each message separately, so the body can be several base64 chunks each
+// ending in its own padding ("AAAA=BBB="): every 4-character group is
+// decoded on its own terms, a padded group ending a chunk rather than the
+// stream, as base64.NewDecoder would.
+type grpcWebTextReader struct {
+	r   io.Reader
+	in  []byte // Base64 read but not yet decoded, less than a group unless a read is pending
+	out []byte // Decoded but not yet returned
+	err error  // From r, returned once in and out are drained
+}
+
+func (t *grpcWebTextReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if n := len(t.in) / 4 * 4; n > 0 {
+			// Decode up to and including the first padded group
+			if i := bytes.IndexByte(t.in[:n], '='); i >= 0 {
+				n = (i/4 + 1) * 4
+			}
+			out := make([]byte, base64.StdEncoding.DecodedLen(n))
+			m, err := base64.StdEncoding.Decode(out, t.in[:n])
+			if err != nil {
+				return 0, err
+			}
+			t.out, t.in = out[:m], t.in[n:]
+			continue
+		}
+		if t.err != nil {
+			if t.err == io.EOF && len(t.in) > 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, t.err
+		}
+		buf := make([]byte, 4096)
+		n, err := t.r.Read(buf)
+		for _, c := range buf[:n] {
+			if c != '\r' && c != '\n' {
+				t.in = append(t.in, c)
+			}
+		}
+		t.err = err
+	}
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
+
+// grpcWebResponseWriter adapts the gRPC server's HTTP/2 response (headers,
+// body, trailers) to the gRPC-Web wire format.
+type grpcWebResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header     // Header map used by the gRPC server
+	sent        map[string]bool // Header keys sent before the body
+	body        io.WriteCloser  // Body writer, base64 encoding for text mode
+	contentType string          // gRPC-Web response content type
+	wroteHeader bool
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (rw *grpcWebResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *grpcWebResponseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.sent = make(map[string]bool)
+	declared := rw.declaredTrailers()
+	out := rw.w.Header()
+	for k, v := range rw.header {
+		if k == "Trailer" || declared[k] || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		out[k] = v
+		rw.sent[k] = true
+	}
+	out.Set("Content-Type", rw.contentType)
+	out.Del("Content-Length")
+	rw.w.WriteHeader(code)
+}
+
+func (rw *grpcWebResponseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.body.Write(b)
+}
+
+// Flush flushes buffered data to the client. In text mode the base64 encoder
+// may hold back up to two bytes until the response is finished.
+func (rw *grpcWebResponseWriter) Flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// declaredTrailers returns the header keys announced as trailers.
+func (rw *grpcWebResponseWriter) declaredTrailers() map[string]bool {
+	declared := make(map[string]bool)
+	for _, v := range rw.header.Values("Trailer") {
+		for _, k := range strings.Split(v, ",") {
+			declared[http.CanonicalHeaderKey(strings.TrimSpace(k))] = true
+		}
+	}
+	return declared
+}
+
+// finish writes the trailer frame: a 0x80 flag byte, a big-endian uint32
+// length, and the trailers in HTTP/1 header format with lowercase keys.
+func (rw *grpcWebResponseWriter) finish() {
+	if !rw.wroteHeader {
+		// Trailers-only response (e.g. an error before any message was sent)
+		rw.WriteHeader(http.StatusOK)
+	}
+	var trailer strings.Builder
+	for k, v := range rw.header {
+		if k == "Trailer" {
+			continue
+		}
+		name := strings.TrimPrefix(k, http.TrailerPrefix)
+		if name == k && rw.sent[k] {
+			continue
+		}
+		for _, value := range v {
+			trailer.WriteString(strings.ToLower(name) + ": " + value + "\r\n")
+		}
+	}
+	frame := make([]byte, 5, 5+trailer.Len())
+	frame[0] = 0x80
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailer.Len()))
+	frame = append(frame, trailer.String()...)
+	rw.body.Write(frame)
+	rw.body.Close()
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// healthServices are the names the gRPC health service answers for with
+// the server's readiness; "" is the server as a whole.
+var healthServices = []string{"", pb.DNSService_ServiceDesc.ServiceName, pb.AdminService_ServiceDesc.ServiceName}
+
+// Names the gRPC health service answers for with the outcome of one
+// component's checks, so probes can tell what is failing.
+const (
+	healthComponentDB     = "db"     // Every shard answers a ping
+	healthComponentIngest = "ingest" // Zones are ingested within health.max_ingestion_lag_hours
+	healthComponentCanary = "canary" // Canary checks pass; only with a canary
+)
+
+// ingestionLag is how long ago any zone was last ingested successfully.
+type ingestionLag struct {
+	LastProcessed string `json:"last_processed,omitempty"` // RFC 3339; empty if no zone ever was
+	LagSeconds    int64  `json:"lag_seconds"`
+	MaxLagSeconds int64  `json:"max_lag_seconds,omitempty"` // 0 if the lag is only reported
+	Error         string `json:"error,omitempty"`
+}
+
+// healthStatus is the outcome of one round of health checks.
+type healthStatus struct {
+	Ready      bool                   `json:"ready"`
+	CheckedAt  string                 `json:"checked_at,omitempty"` // RFC 3339; empty before the first round
+	Failing    []string               `json:"failing,omitempty"`    // Why the server is not ready
+	Components map[string]bool        `json:"components,omitempty"` // Health component -> whether its checks pass
+	Databases  map[string]string      `json:"databases,omitempty"`  // Shard name -> "ok" or the ping error
+	Ingestion  *ingestionLag          `json:"ingestion,omitempty"`
+	Canary     map[string]canaryStats `json:"canary,omitempty"`
+}
+
+// healthChecker decides on an interval whether the server can take traffic:
+// every shard, the default database included, must answer a ping, zones
+// must have been ingested within health.max_ingestion_lag_hours if set, and
+// the canary, if any, must be ready. It reports the outcome to the gRPC
+// health service for the server and each of its services, and each check's
+// under its component name (db, ingest, canary), and serves it at GET
+// /readyz.
+type healthChecker struct {
+	db      *sql.DB
+	shards  *storage.Router
+	canary  *canary // nil without canary checks
+	health  *health.Server
+	timeout time.Duration
+	maxLag  time.Duration // 0 reports the lag without judging it
+
+	mu       sync.Mutex
+	status   healthStatus
+	draining bool // Set by shutdown; rounds no longer update status
+}
+
+// newHealthChecker returns a checker reporting to healthServer, which
+// reports not serving until the first round.
+func newHealthChecker(db *sql.DB, shards *storage.Router, probe *canary, healthServer *health.Server, timeout, maxLag time.Duration) *healthChecker {
+	names := append([]string{healthComponentDB, healthComponentIngest}, healthServices...)
+	if probe != nil {
+		names = append(names, healthComponentCanary)
+	}
+	for _, name := range names {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return &healthChecker{db: db, shards: shards, canary: probe, health: healthServer, timeout: timeout, maxLag: maxLag,
+		status: healthStatus{Failing: []string{"not checked yet"}}}
+}
+
+// run checks every interval until ctx is done.
+func (h *healthChecker) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		h.round(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round runs every check once and updates readiness.
+func (h *healthChecker) round(ctx context.Context) {
+	st := healthStatus{
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+		Components: map[string]bool{healthComponentDB: true, healthComponentIngest: true},
+		Databases:  make(map[string]string),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, shard := range h.shards.Shards() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+			result := "ok"
+			if err := shard.DB.PingContext(pingCtx); err != nil {
+				result = err.Error()
+			}
+			mu.Lock()
+			st.Databases[shard.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	for _, shard := range h.shards.Shards() {
+		if st.Databases[shard.Name] != "ok" {
+			st.Failing = append(st.Failing, fmt.Sprintf("database %s: %s", shard.Name, st.Databases[shard.Name]))
+			st.Components[healthComponentDB] = false
+		}
+	}
+
+	st.Ingestion = h.ingestionLag(ctx)
+	switch {
+	case st.Ingestion.Error != "":
+		st.Failing = append(st.Failing, "ingestion lag: "+st.Ingestion.Error)
+		st.Components[healthComponentIngest] = false
+	case h.maxLag > 0 && st.Ingestion.LastProcessed != "" && st.Ingestion.LagSeconds > st.Ingestion.MaxLagSeconds:
+		st.Failing = append(st.Failing, fmt.Sprintf("no zone ingested for %v", (time.Duration(st.Ingestion.LagSeconds)*time.Second).Round(time.Minute)))
+		st.Components[healthComponentIngest] = false
+	}
+
+	if h.canary != nil {
+		st.Canary = h.canary.snapshot()
+		st.Components[healthComponentCanary] = h.canary.isReady()
+		if !st.Components[healthComponentCanary] {
+			st.Failing = append(st.Failing, "canary checks failing")
+		}
+	}
+	st.Ready = len(st.Failing) == 0
+
+	h.mu.Lock()
+	if h.draining {
+		h.mu.Unlock()
+		return
+	}
+	wasReady := h.status.Ready
+	h.status = st
+	h.mu.Unlock()
+	switch {
+	case wasReady && !st.Ready:
+		warnf("Health: Not ready: %v", st.Failing)
+	case !wasReady && st.Ready:
+		infof("Health: Ready")
+	}
+	for _, service := range healthServices {
+		h.health.SetServingStatus(service, servingStatus(st.Ready))
+	}
+	for component, ok := range st.Components {
+		h.health.SetServingStatus(component, servingStatus(ok))
+	}
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// shutdown reports not ready for good, so load balancers stop routing here
+// while requests drain.
+func (h *healthChecker) shutdown() {
+	h.mu.Lock()
+	h.draining = true
+	h.status.Ready = false
+	h.status.Failing = []string{"shutting down"}
+	h.mu.Unlock()
+	h.health.Shutdown()
+}
+
+// ingestionLag reads the latest successful zone ingest. A server whose
+// database never ingested a zone reports no lag rather than failing, so a
+// fresh deployment can become ready.
+func (h *healthChecker) ingestionLag(ctx context.Context) *ingestionLag {
+	lag := &ingestionLag{MaxLagSeconds: int64(h.maxLag / time.Second)}
+	queryCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	var last sql.NullTime
+	if err := h.db.QueryRowContext(queryCtx, "SELECT max(last_processed) FROM processed_tlds").Scan(&last); err != nil {
+		lag.Error = err.Error()
+		return lag
+	}
+	if last.Valid {
+		lag.LastProcessed = last.Time.UTC().Format(time.RFC3339)
+		lag.LagSeconds = int64(max(time.Since(last.Time), 0) / time.Second)
+	}
+	return lag
+}
+
+// liveness answers 200 while the process serves HTTP at all. It checks no
+// dependency, so an orchestrator restarts the server only when it hangs,
+// not when the database does; readiness covers dependencies.
+func liveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"alive":true}`))
+}
+
+// readiness answers 200 while the last round of checks passed and 503
+// otherwise, with the round's outcome as JSON.
+func (h *healthChecker) readiness(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	st := h.status
+	h.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if !st.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(st)
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"expvar"
+	"math/rand/v2"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/moos3/bell/storage"
+)
+
+// maxHedgeBurst bounds the hedges that can be saved up while traffic is
+// fast, so a sudden slowdown cannot double the load on every replica.
+const maxHedgeBurst = 10
+
+// hedger cuts the tail latency of read RPCs on shards with read replicas.
+// If an attempt has not answered after delay, or fails with an error another
+// replica might not have, the RPC is run again against another member of the
+// shard's read pool (see storage.Shard.Reader) and the first successful
+// response wins; the other attempt is cancelled. Replicas may lag the
+// primary slightly, so only RPCs that tolerate that belong in methods.
+//
+// Extra attempts are paid for from a token bucket that every hedgeable
+// request tops up by extraPercent/100, capping hedges at that share of
+// traffic.
+type hedger struct {
+	delay        time.Duration
+	extraPercent float64
+	methods      map[string]bool
+
+	mu     sync.Mutex
+	budget float64
+	stats  map[string]*hedgeStats // RPC -> counters
+}
+
+// hedgeStats counts hedged reads for one RPC.
+type hedgeStats struct {
+	Requests  int64 `json:"requests"`   // Hedgeable requests
+	Hedged    int64 `json:"hedged"`     // Requests that sent a second attempt
+	Throttled int64 `json:"throttled"`  // Requests that would have hedged but the budget was spent
+	HedgeWins int64 `json:"hedge_wins"` // Hedged requests answered by the second attempt
+}
+
+// newHedger hedges methods after delay, adding at most extraPercent attempts,
+// and publishes its counters through expvar as "hedging".
+func newHedger(delay time.Duration, extraPercent float64, methods []string) *hedger {
+	h := &hedger{
+		delay:        delay,
+		extraPercent: extraPercent,
+		methods:      make(map[string]bool),
+		stats:        make(map[string]*hedgeStats),
+	}
+	for _, m := range methods {
+		h.methods[m] = true
+	}
+	expvar.Publish("hedging", expvar.Func(func() interface{} { return h.snapshot() }))
+	return h
+}
+
+// hedgeable reports whether err might not recur on another replica.
+func hedgeable(err error) bool {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// attemptResult is the outcome of one attempt of a hedged RPC.
+type attemptResult struct {
+	resp  interface{}
+	err   error
+	hedge bool
+}
+
+func (h *hedger) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	rpc := path.Base(info.FullMethod)
+	if !h.methods[rpc] {
+		return handler(ctx, req)
+	}
+	h.earn(rpc)
+	start := rand.IntN(1 << 20)
+	results := make(chan attemptResult, 2)
+	attempt := func(n int, req interface{}) func() {
+		attemptCtx, cancel := context.WithCancel(storage.WithReadAttempt(ctx, start, n))
+		go func() {
+			resp, err := handler(attemptCtx, req)
+			results <- attemptResult{resp: resp, err: err, hedge: n > 0}
+		}()
+		return cancel
+	}
+	// Handlers may normalize their request in place, so the hedge gets a
+	// copy, taken before the first attempt can touch the request
+	hedgeReq := proto.Clone(req.(proto.Message))
+	defer attempt(0, req)()
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+	var first *attemptResult
+	select {
+	case r := <-results:
+		if r.err == nil || !hedgeable(r.err) {
+			return r.resp, r.err
+		}
+		first = &r
+	case <-timer.C:
+	}
+	if !h.spend(rpc) {
+		if first != nil {
+			return first.resp, first.err
+		}
+		r := <-results
+		return r.resp, r.err
+	}
+	defer attempt(1, hedgeReq)()
+
+	if first == nil {
+		r := <-results
+		if r.err == nil || !hedgeable(r.err) {
+			h.recordWin(rpc, r.hedge)
+			return r.resp, r.err
+		}
+		first = &r
+	}
+	r := <-results
+	if r.err != nil {
+		// Both failed; report the first attempt's error
+		return first.resp, first.err
+	}
+	h.recordWin(rpc, r.hedge)
+	return r.resp, r.err
+}
+
+// earn adds a request's share of hedges to the budget.
+func (h *hedger) earn(rpc string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.budget = min(h.budget+h.extraPercent/100, maxHedgeBurst)
+	h.statsFor(rpc).Requests++
+}
+
+// spend takes a hedge from the budget, reporting false if there is none.
+func (h *hedger) spend(rpc string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.statsFor(rpc)
+	if h.budget < 1 {
+		st.Throttled++
+		return false
+	}
+	h.budget--
+	st.Hedged++
+	return true
+}
+
+func (h *hedger) recordWin(rpc string, hedge bool) {
+	if !hedge {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.statsFor(rpc).HedgeWins++
+}
+
+// statsFor returns the counters of rpc; h.mu must be held.
+func (h *hedger) statsFor(rpc string) *hedgeStats {
+	st, ok := h.stats[rpc]
+	if !ok {
+		st = &hedgeStats{}
+		h.stats[rpc] = st
+	}
+	return st
+}
+
+func (h *hedger) snapshot() map[string]hedgeStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	snapshot := make(map[string]hedgeStats, len(h.stats))
+	for rpc, st := range h.stats {
+		snapshot[rpc] = *st
+	}
+	return snapshot
+}
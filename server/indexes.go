@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moos3/bell/storage"
+)
+
+// expectedIndex is an index a hot query relies on, with a query shaped like
+// it to plan.
+type expectedIndex struct {
+	name  string
+	usage string
+	query string
+	args  []interface{}
+}
+
+// expectedIndexes are the domains indexes of schema.sql that API and worker
+// queries need to avoid scanning the table.
+var expectedIndexes = []expectedIndex{
+	{"idx_domains_domain_name", "GetRecords", "SELECT id FROM domains WHERE domain_name = $1", []interface{}{"example.com"}},
+	{"idx_domains_domain_name_pattern", "domain name search", "SELECT id FROM domains WHERE domain_name LIKE $1", []interface{}{"example%"}},
+	{"idx_domains_query_due", "query worker selection",
+		"SELECT id FROM domains WHERE nameservers != '{}' AND (last_updated IS NULL OR last_updated < NOW() - INTERVAL '12 hours') AND id > $1 ORDER BY id LIMIT 100",
+		[]interface{}{0}},
+	{"idx_domains_nameservers", "GetDomainsByNameserver", "SELECT id FROM domains WHERE nameservers && $1", []interface{}{pq.Array([]string{"ns1.example.com"})}},
+	{"idx_domains_reverse_name", "ListSubdomains", `SELECT id FROM domains WHERE reverse(domain_name) COLLATE "C" LIKE $1`, []interface{}{"moc.elpmaxe.%"}},
+	{"idx_domains_domain_name_trgm", "SearchDomains", "SELECT id FROM domains WHERE domain_name LIKE $1", []interface{}{"%bank%"}},
+	{"idx_domains_tld_domain_name", "ListDomainsByTLD", "SELECT id FROM domains WHERE tld = $1 AND domain_name > $2 ORDER BY domain_name LIMIT 100", []interface{}{"com", ""}},
+}
+
+// checkIndexes warns about expected indexes missing from any shard. Each
+// query is planned with sequential scans disabled, so the planner uses an
+// index however small the table; a plan that does not use the expected one
+// is checked against the catalog to tell a missing index from one the
+// planner passed over for another.
+func checkIndexes(ctx context.Context, shards *storage.Router) {
+	for _, shard := range shards.Shards() {
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		for _, idx := range expectedIndexes {
+			used, err := planUsesIndex(checkCtx, shard, idx)
+			if err != nil {
+				errorf("Failed to check index %s on shard %s: %v", idx.name, shard.Name, err)
+				continue
+			}
+			if used {
+				continue
+			}
+			var exists bool
+			if err := shard.DB.QueryRowContext(checkCtx, "SELECT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = $1)", idx.name).Scan(&exists); err != nil {
+				errorf("Failed to check index %s on shard %s: %v", idx.name, shard.Name, err)
+				continue
+			}
+			if exists {
+				debugf("Index %s exists on shard %s but the planner chose another for %s", idx.name, shard.Name, idx.usage)
+				continue
+			}
+			warnf("Index %s is missing on shard %s; %s will scan the domains table; create it with analytics -job create-indexes", idx.name, shard.Name, idx.usage)
+		}
+		cancel()
+	}
+}
+
+// planUsesIndex reports whether the plan of idx's query on shard uses it.
+func planUsesIndex(ctx context.Context, shard *storage.Shard, idx expectedIndex) (bool, error) {
+	tx, err := shard.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+		return false, err
+	}
+	var plan []byte
+	if err := tx.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+idx.query, idx.args...).Scan(&plan); err != nil {
+		return false, fmt.Errorf("failed to plan: %v", err)
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &plans); err != nil || len(plans) == 0 {
+		return false, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+	return plans[0].Plan.usesIndex(idx.name), nil
+}
+
+// planNode is a node of an EXPLAIN (FORMAT JSON) plan.
+type planNode struct {
+	IndexName string     `json:"Index Name"`
+	Plans     []planNode `json:"Plans"`
+}
+
+func (n planNode) usesIndex(name string) bool {
+	if n.IndexName == name {
+		return true
+	}
+	for _, child := range n.Plans {
+		if child.usesIndex(name) {
+			return true
+		}
+	}
+	return false
+}
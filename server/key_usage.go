@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// keyMonth is an API key's monthly quotas and its usage so far this month.
+type keyMonth struct {
+	requestQuota int64  // 0 for unlimited
+	recordQuota  int64  // 0 for unlimited
+	month        string // UTC month, 2006-01
+	requests     int64  // In usage_counts when last read, plus admitted here since
+	records      int64
+	expires      time.Time
+}
+
+// keyQuota enforces the monthly request and record quotas of API keys
+// (api_keys.monthly_request_quota and monthly_record_quota). Requests are
+// counted as usageMeter counts them and records are the DNS records of the
+// responses. Usage is read from usage_counts, which every server adds to
+// about once a minute, plus what was admitted here since the last read, so
+// a key can go over its quotas by about a minute of traffic on other
+// servers. The record quota is checked before each call, so the call that
+// uses it up is still answered in full.
+type keyQuota struct {
+	db      *sql.DB
+	sandbox *sandboxRouter
+
+	mu   sync.Mutex
+	keys map[string]*keyMonth
+}
+
+func newKeyQuota(db *sql.DB, sandbox *sandboxRouter) *keyQuota {
+	return &keyQuota{db: db, sandbox: sandbox, keys: make(map[string]*keyMonth)}
+}
+
+// unaryInterceptor rejects calls made with a key that has used a monthly
+// quota, and counts the records of the calls it lets through. Like
+// orgQuota's, it must run before usageMeter's interceptor so that rejected
+// calls are not metered, and calls whose quotas cannot be looked up are let
+// through.
+func (q *keyQuota) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	apiKey, err := q.admitCall(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if msg, ok := resp.(proto.Message); ok && err == nil && apiKey != "" {
+		q.addRecords(apiKey, responseRecords(msg.ProtoReflect()))
+	}
+	return resp, err
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls; the quotas are
+// checked once the request has been received, and records are counted as
+// they are sent.
+func (q *keyQuota) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var apiKey string
+	return handler(srv, &interceptedStream{ServerStream: ss, recv: func(req interface{}) error {
+		var err error
+		apiKey, err = q.admitCall(ss.Context(), info.FullMethod, req)
+		return err
+	}, send: func(resp proto.Message) error {
+		if apiKey != "" {
+			q.addRecords(apiKey, responseRecords(resp.ProtoReflect()))
+		}
+		return nil
+	}})
+}
+
+// admitCall counts a call against its key's monthly request quota,
+// returning a ResourceExhausted error if either quota is used up. It returns
+// the key if the records of the call should be counted, which is when the
+// key has a record quota.
+func (q *keyQuota) admitCall(ctx context.Context, fullMethod string, req interface{}) (string, error) {
+	if quotaExempt[path.Base(fullMethod)] {
+		return "", nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return "", nil
+	}
+	if sandbox, err := q.sandbox.sandboxKey(ctx, apiKeys[0]); sandbox || err != nil {
+		return "", nil
+	}
+	now := time.Now().UTC()
+	u, err := q.usage(ctx, apiKeys[0], now)
+	if err != nil {
+		errorf("%s: Failed to look up monthly quotas of API key %s: %v", fullMethod, apiKeys[0], err)
+		return "", nil
+	}
+	if u.requestQuota == 0 && u.recordQuota == 0 {
+		return "", nil
+	}
+	requests := int64(1)
+	if msg, ok := req.(proto.Message); ok {
+		if n := len(requestTLDs(msg.ProtoReflect())); n > 1 {
+			requests = int64(n)
+		}
+	}
+
+	q.mu.Lock()
+	requestsOver := u.requestQuota > 0 && u.requests+requests > u.requestQuota
+	recordsOver := u.recordQuota > 0 && u.records >= u.recordQuota
+	if !requestsOver && !recordsOver {
+		u.requests += requests
+	}
+	q.mu.Unlock()
+	if !requestsOver && !recordsOver {
+		if u.recordQuota == 0 {
+			return "", nil
+		}
+		return apiKeys[0], nil
+	}
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	limit, unit := u.requestQuota, "requests"
+	if !requestsOver {
+		limit, unit = u.recordQuota, "records"
+	}
+	infof("%s: API key %s is over its monthly quota of %d %s", fullMethod, apiKeys[0], limit, unit)
+	return "", statusError(codes.ResourceExhausted, reasonQuotaExceeded, map[string]string{
+		"quota_limit": strconv.FormatInt(limit, 10),
+		"retry_after": nextMonth.Sub(now).Round(time.Second).String(),
+	}, "API key has used its monthly quota of %d %s", limit, unit)
+}
+
+// usage returns the quotas of apiKey and its usage in the month of now,
+// reading them from the database when the cached ones are stale.
+func (q *keyQuota) usage(ctx context.Context, apiKey string, now time.Time) (*keyMonth, error) {
+	month := now.Format("2006-01")
+	q.mu.Lock()
+	u, ok := q.keys[apiKey]
+	q.mu.Unlock()
+	if ok && u.month == month && now.Before(u.expires) {
+		return u, nil
+	}
+	u = &keyMonth{month: month, expires: now.Add(quotaCacheTTL)}
+	err := q.db.QueryRowContext(ctx, "SELECT monthly_request_quota, monthly_record_quota FROM api_keys WHERE api_key = $1", apiKey).
+		Scan(&u.requestQuota, &u.recordQuota)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if u.requestQuota != 0 || u.recordQuota != 0 {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		err := q.db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(records), 0)
+			FROM usage_counts
+			WHERE api_key = $1 AND day >= $2
+		`, apiKey, start).Scan(&u.requests, &u.records)
+		if err != nil {
+			return nil, err
+		}
+	}
+	q.mu.Lock()
+	q.keys[apiKey] = u
+	q.mu.Unlock()
+	return u, nil
+}
+
+// addRecords adds records returned to apiKey to its usage this month.
+func (q *keyQuota) addRecords(apiKey string, records int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if u, ok := q.keys[apiKey]; ok {
+		u.records += records
+	}
+}
+
+// forget drops the cached quotas and usage of apiKey.
+func (q *keyQuota) forget(apiKey string) {
+	q.mu.Lock()
+	delete(q.keys, apiKey)
+	q.mu.Unlock()
+}
+
+// GetUsage returns the requests, errors and returned records of the calling
+// key in a UTC month, in total and per day, from usage_counts, along with
+// its monthly quotas.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key") and stays
+// available when the key or its organization is over quota.
+func (s *server) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.GetUsageResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.Month != "" {
+		var err error
+		if start, err = time.Parse("2006-01", req.Month); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid month %q; must be YYYY-MM", req.Month)
+		}
+	}
+
+	resp := &pb.GetUsageResponse{Month: start.Format("2006-01")}
+	err := s.keys.QueryRowContext(ctx, "SELECT monthly_request_quota, monthly_record_quota FROM api_keys WHERE api_key = $1", apiKey).
+		Scan(&resp.MonthlyRequestQuota, &resp.MonthlyRecordQuota)
+	if err != nil && err != sql.ErrNoRows {
+		errorf("GetUsage: Failed to query quotas of API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to query quotas: %v", err)
+	}
+	rows, err := s.keys.QueryContext(ctx, `
+		SELECT day, SUM(requests), SUM(errors), SUM(records)
+		FROM usage_counts
+		WHERE api_key = $1 AND day >= $2 AND day < $3
+		GROUP BY day
+		ORDER BY day
+	`, apiKey, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		errorf("GetUsage: Failed to query usage of API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to query usage: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u pb.DailyUsage
+		var day time.Time
+		if err := rows.Scan(&day, &u.Requests, &u.Errors, &u.Records); err != nil {
+			errorf("GetUsage: Failed to scan usage: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan usage: %v", err)
+		}
+		u.Day = day.Format("2006-01-02")
+		resp.Requests += u.Requests
+		resp.Errors += u.Errors
+		resp.Records += u.Records
+		resp.Days = append(resp.Days, &u)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetUsage: Failed to iterate usage: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate usage: %v", err)
+	}
+	debugf("GetUsage: API key %s made %d requests for %d records in %s", apiKey, resp.Requests, resp.Records, resp.Month)
+	return resp, nil
+}
@@ -0,0 +1,468 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+)
+
+// Evidence behind a detected transfer.
+const (
+	evidenceProviderChanged  = "nameserver_provider_changed" // The zone ingest saw the delegation move to a new DNS provider
+	evidenceRegistrarChanged = "rdap_registrar_changed"      // RDAP names another registrar than the cached lookup
+	evidenceTransferEvent    = "rdap_transfer_event"         // The registry reports a transfer event near the nameserver change
+)
+
+const (
+	// transferEventWindow is how long before a nameserver change a registry
+	// transfer event still counts as evidence, and how long a recorded
+	// transfer suppresses another to the same registrar.
+	transferEventWindow = 30 * 24 * time.Hour
+	// maxNotifiedTransfers bounds the transfers posted in one webhook delivery.
+	maxNotifiedTransfers = 500
+)
+
+// transfer is a detected registrar transfer, stored as a TRANSFER event in
+// domain_lifecycle_events.
+type transfer struct {
+	domain           string
+	oldRegistrarID   int32
+	oldRegistrarName string
+	registrarID      int32
+	registrarName    string
+	oldNameservers   []string
+	nameservers      []string
+	confidence       string // CONFIRMED or LIKELY
+	evidence         []string
+}
+
+// transferChecker looks up domains whose nameservers moved to a new DNS
+// provider, as recorded by zone ingests, over RDAP to detect registrar
+// transfers, and posts new transfers to lifecycle.webhook_url.
+type transferChecker struct {
+	lookups       int           // RDAP lookups per check
+	likelyAfter   time.Duration // Provider changes RDAP cannot confirm within this long become LIKELY transfers
+	webhookURL    string
+	webhookSecret string
+	client        *http.Client
+}
+
+// registrarChanged reports whether two RDAP lookups name different
+// registrars, comparing IANA IDs when both have one and names otherwise.
+// An unnamed registrar is never a change.
+func registrarChanged(oldID int32, oldName string, id int32, name string) bool {
+	if oldID != 0 && id != 0 {
+		return oldID != id
+	}
+	if oldName == "" || name == "" {
+		return false
+	}
+	return !strings.EqualFold(oldName, name)
+}
+
+// recordTransfer stores a TRANSFER event, unless one to the same registrar
+// was recorded for the domain within transferEventWindow.
+func (s *server) recordTransfer(ctx context.Context, t *transfer) error {
+	old, ns := t.oldNameservers, t.nameservers
+	if old == nil {
+		old = []string{}
+	}
+	if ns == nil {
+		ns = []string{}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO domain_lifecycle_events (domain_name, event_type, old_nameservers, nameservers,
+			old_registrar_iana_id, old_registrar_name, registrar_iana_id, registrar_name, confidence, evidence)
+		SELECT $1, 'TRANSFER', $2, $3, NULLIF($4, 0), NULLIF($5, ''), NULLIF($6, 0), NULLIF($7, ''), $8, $9
+		WHERE NOT EXISTS (
+			SELECT 1 FROM domain_lifecycle_events
+			WHERE domain_name = $1 AND event_type = 'TRANSFER'
+			  AND registrar_iana_id IS NOT DISTINCT FROM NULLIF($6, 0)
+			  AND registrar_name IS NOT DISTINCT FROM NULLIF($7, '')
+			  AND observed_at > NOW() - $10 * INTERVAL '1 second'
+		)
+	`, t.domain, pq.StringArray(old), pq.StringArray(ns), t.oldRegistrarID, t.oldRegistrarName,
+		t.registrarID, t.registrarName, t.confidence, pq.StringArray(t.evidence), int(transferEventWindow.Seconds()))
+	if err != nil {
+		return err
+	}
+	infof("Lifecycle: Recorded %s transfer of %s to registrar %d (%s)", t.confidence, t.domain, t.registrarID, strings.Join(t.evidence, ", "))
+	return nil
+}
+
+// runTransferChecks checks provider changes for transfers and delivers new
+// transfers to the webhook every interval until ctx is done.
+func (s *server) runTransferChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := s.checkTransfers(ctx); err != nil {
+			errorf("Lifecycle: Failed to check transfers: %v", err)
+		}
+		if err := s.notifyTransfers(ctx); err != nil {
+			errorf("Lifecycle: Failed to deliver transfers: %v", err)
+		}
+	}
+}
+
+// checkTransfers looks up the oldest unchecked provider changes over RDAP.
+// A change is a CONFIRMED transfer if RDAP names another registrar than the
+// cached lookup or reports a transfer event shortly before it, and a LIKELY
+// one if RDAP has failed for it for lifecycle.likely_after_hours. Failed
+// lookups are retried on later checks until then.
+func (s *server) checkTransfers(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id, e.domain_name, e.old_nameservers, e.nameservers, e.observed_at,
+			c.domain_name IS NOT NULL, COALESCE(c.registrar_iana_id, 0), COALESCE(c.registrar_name, '')
+		FROM domain_lifecycle_events e
+		LEFT JOIN domain_abuse_contacts c ON c.domain_name = e.domain_name
+		WHERE e.provider_changed AND e.checked_at IS NULL
+		ORDER BY e.id
+		LIMIT $1
+	`, s.transfers.lookups)
+	if err != nil {
+		return fmt.Errorf("failed to query nameserver changes: %v", err)
+	}
+	type change struct {
+		id         int64
+		t          transfer
+		observedAt time.Time
+		cached     bool
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.id, &c.t.domain, pq.Array(&c.t.oldNameservers), pq.Array(&c.t.nameservers), &c.observedAt,
+			&c.cached, &c.t.oldRegistrarID, &c.t.oldRegistrarName); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan nameserver change: %v", err)
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate nameserver changes: %v", err)
+	}
+
+	for _, c := range changes {
+		t := c.t
+		t.evidence = []string{evidenceProviderChanged}
+		reg, err := s.fetchRegistration(ctx, t.domain)
+		switch {
+		case err != nil && time.Since(c.observedAt) < s.transfers.likelyAfter:
+			debugf("Lifecycle: RDAP lookup failed for %s, retrying on a later check: %v", t.domain, err)
+			continue
+		case err != nil:
+			t.confidence = "LIKELY"
+		default:
+			if err := s.cacheRegistration(ctx, t.domain, reg, time.Now().UTC()); err != nil {
+				errorf("Lifecycle: Failed to cache RDAP registrar for %s: %v", t.domain, err)
+			}
+			t.registrarID, t.registrarName = reg.registrarID, reg.registrarName
+			changed := c.cached && registrarChanged(t.oldRegistrarID, t.oldRegistrarName, reg.registrarID, reg.registrarName)
+			if changed {
+				t.evidence = append(t.evidence, evidenceRegistrarChanged)
+			} else {
+				// The cached registrar, if any, is not one transferred away from
+				t.oldRegistrarID, t.oldRegistrarName = 0, ""
+			}
+			if !reg.transferred.IsZero() && reg.transferred.After(c.observedAt.Add(-transferEventWindow)) {
+				t.evidence = append(t.evidence, evidenceTransferEvent)
+			}
+			if len(t.evidence) > 1 {
+				t.confidence = "CONFIRMED"
+			}
+		}
+		if t.confidence != "" {
+			if err := s.recordTransfer(ctx, &t); err != nil {
+				return fmt.Errorf("failed to record transfer of %s: %v", t.domain, err)
+			}
+		}
+		if _, err := s.db.ExecContext(ctx, "UPDATE domain_lifecycle_events SET checked_at = NOW() WHERE id = $1", c.id); err != nil {
+			return fmt.Errorf("failed to mark nameserver change %d checked: %v", c.id, err)
+		}
+	}
+	return nil
+}
+
+// transferNotification is the document posted to lifecycle.webhook_url with
+// newly detected transfers:
+//
+//	{
+//	  "schema_version": 1,
+//	  "transfers": [{
+//	    "domain": "moved.example",
+//	    "observed_at": "2025-01-01T00:00:00Z", // RFC 3339, UTC
+//	    "confidence": "CONFIRMED",             // or LIKELY
+//	    "evidence": ["nameserver_provider_changed", "rdap_registrar_changed"],
+//	    "old_registrar": {"iana_id": 146, "name": "Old Registrar"}, // Omitted if unknown
+//	    "registrar": {"iana_id": 1068, "name": "New Registrar"},
+//	    "old_nameservers": ["ns1.a.net"],
+//	    "nameservers": ["ns1.b.net"]
+//	  }]
+//	}
+//
+// Deliveries carry an X-Bell-Signature header ("sha256=<hex HMAC of the
+// body>") when lifecycle.webhook_secret is configured, like zone deltas.
+type transferNotification struct {
+	SchemaVersion int                `json:"schema_version"`
+	Transfers     []notifiedTransfer `json:"transfers"`
+}
+
+type notifiedTransfer struct {
+	Domain         string             `json:"domain"`
+	ObservedAt     string             `json:"observed_at"`
+	Confidence     string             `json:"confidence"`
+	Evidence       []string           `json:"evidence"`
+	OldRegistrar   *notifiedRegistrar `json:"old_registrar,omitempty"`
+	Registrar      *notifiedRegistrar `json:"registrar,omitempty"`
+	OldNameservers []string           `json:"old_nameservers"`
+	Nameservers    []string           `json:"nameservers"`
+}
+
+type notifiedRegistrar struct {
+	IanaID int32  `json:"iana_id,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// notifyTransfers posts the transfers not yet delivered to the webhook and
+// marks them delivered. Undelivered transfers are retried on the next check.
+func (s *server) notifyTransfers(ctx context.Context) error {
+	if s.transfers.webhookURL == "" {
+		return nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, domain_name, observed_at, confidence, evidence, old_nameservers, nameservers,
+			COALESCE(old_registrar_iana_id, 0), COALESCE(old_registrar_name, ''), COALESCE(registrar_iana_id, 0), COALESCE(registrar_name, '')
+		FROM domain_lifecycle_events
+		WHERE event_type = 'TRANSFER' AND notified_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`, maxNotifiedTransfers)
+	if err != nil {
+		return fmt.Errorf("failed to query transfers: %v", err)
+	}
+	doc := transferNotification{SchemaVersion: 1, Transfers: []notifiedTransfer{}}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var n notifiedTransfer
+		var observedAt time.Time
+		var old, current notifiedRegistrar
+		if err := rows.Scan(&id, &n.Domain, &observedAt, &n.Confidence, pq.Array(&n.Evidence), pq.Array(&n.OldNameservers), pq.Array(&n.Nameservers),
+			&old.IanaID, &old.Name, &current.IanaID, &current.Name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan transfer: %v", err)
+		}
+		n.ObservedAt = observedAt.UTC().Format(time.RFC3339)
+		if old != (notifiedRegistrar{}) {
+			n.OldRegistrar = &old
+		}
+		if current != (notifiedRegistrar{}) {
+			n.Registrar = &current
+		}
+		doc.Transfers = append(doc.Transfers, n)
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate transfers: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode transfers: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.transfers.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.transfers.webhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(s.transfers.webhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-Bell-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := s.transfers.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook rejected transfers: %s", resp.Status)
+	}
+	if _, err := s.db.ExecContext(ctx, "UPDATE domain_lifecycle_events SET notified_at = NOW() WHERE id = ANY($1)", pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to mark transfers delivered: %v", err)
+	}
+	infof("Lifecycle: Delivered %d transfers", len(ids))
+	return nil
+}
+
+// GetDomainLifecycle returns when a domain was first and last seen in its
+// zone, followed by its nameserver and record-set changes and detected
+// registrar transfers, oldest first. Changes carry their significance (see
+// recordset.Classify) and can be limited to some significances. Domains
+// restricted to other organizations have no lifecycle.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+// Nameserver changes are recorded by zone ingests and record-set changes by
+// the query worker; transfers are detected by the transfer check, which
+// looks domains that moved to a new DNS provider up over RDAP, and by
+// GetAbuseContacts refreshes.
+func (s *server) GetDomainLifecycle(ctx context.Context, req *pb.GetDomainLifecycleRequest) (*pb.GetDomainLifecycleResponse, error) {
+	domain := strings.TrimSuffix(strings.ToLower(req.Domain), ".")
+	if !strings.Contains(domain, ".") {
+		return nil, status.Errorf(codes.InvalidArgument, "a domain name is required")
+	}
+	significances := make(map[string]bool)
+	for _, significance := range req.Significance {
+		significances[strings.ToUpper(significance)] = true
+	}
+
+	prefs, err := s.keyPreferences(ctx, "GetDomainLifecycle", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetDomainLifecycleResponse{Domain: domain}
+	visible, err := s.domainVisible(ctx, "GetDomainLifecycle", prefs, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return resp, nil
+	}
+	var firstSeen time.Time
+	var lastSeen sql.NullTime
+	shard := s.shards.ForDomain(domain)
+	err = shard.DB.QueryRowContext(ctx,
+		"SELECT first_seen, last_updated FROM domains WHERE domain_name = $1 LIMIT 1", domain).Scan(&firstSeen, &lastSeen)
+	if err != nil && err != sql.ErrNoRows {
+		errorf("GetDomainLifecycle: Failed to query domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query domain: %v", err)
+	}
+	if err == nil {
+		resp.FirstSeen = firstSeen.UTC().Format(time.RFC3339)
+		if lastSeen.Valid {
+			resp.LastSeen = lastSeen.Time.UTC().Format(time.RFC3339)
+		}
+		resp.Events = append(resp.Events, &pb.LifecycleEvent{Type: "FIRST_SEEN", ObservedAt: resp.FirstSeen})
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT event_type, observed_at, old_nameservers, nameservers, provider_changed,
+			COALESCE(old_registrar_iana_id, 0), COALESCE(old_registrar_name, ''), COALESCE(registrar_iana_id, 0), COALESCE(registrar_name, ''),
+			COALESCE(confidence, ''), evidence, COALESCE(significance, '')
+		FROM domain_lifecycle_events
+		WHERE domain_name = $1
+		ORDER BY observed_at, id
+	`, domain)
+	if err != nil {
+		errorf("GetDomainLifecycle: Failed to query events for %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query lifecycle events: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		e := &pb.LifecycleEvent{}
+		var observedAt time.Time
+		old, current := &pb.Registrar{}, &pb.Registrar{}
+		if err := rows.Scan(&e.Type, &observedAt, pq.Array(&e.OldNameservers), pq.Array(&e.Nameservers), &e.ProviderChanged,
+			&old.IanaId, &old.Name, &current.IanaId, &current.Name, &e.Confidence, pq.Array(&e.Evidence), &e.Significance); err != nil {
+			errorf("GetDomainLifecycle: Failed to scan event for %s: %v", domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan lifecycle event: %v", err)
+		}
+		e.ObservedAt = observedAt.UTC().Format(time.RFC3339)
+		switch e.Type {
+		case "TRANSFER":
+			if old.IanaId != 0 || old.Name != "" {
+				e.OldRegistrar = old
+			}
+			e.Registrar = current
+		case "NAMESERVERS_CHANGED":
+			// Changes stored before classification are classified now
+			if e.Significance == "" {
+				e.Significance = recordset.ClassifyNameservers(e.OldNameservers, e.Nameservers)
+			}
+			if len(significances) > 0 && !significances[e.Significance] {
+				continue
+			}
+		}
+		resp.Events = append(resp.Events, e)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetDomainLifecycle: Failed to iterate events for %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate lifecycle events: %v", err)
+	}
+
+	changes, err := recordSetChanges(ctx, shard.Reader(ctx), domain, significances)
+	if err != nil {
+		errorf("GetDomainLifecycle: Failed to query record-set changes for %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query record-set changes: %v", err)
+	}
+	resp.Events = append(resp.Events, changes...)
+	// RFC 3339 UTC times sort as strings; FIRST_SEEN stays ahead of events at the same second
+	sort.SliceStable(resp.Events, func(i, j int) bool { return resp.Events[i].ObservedAt < resp.Events[j].ObservedAt })
+	if len(resp.Events) == 0 {
+		return nil, statusError(codes.NotFound, reasonDomainNotFound, map[string]string{"domain": domain}, "domain %s not found", domain)
+	}
+	infof("GetDomainLifecycle: Response for domain %s: %d events", domain, len(resp.Events))
+	return resp, nil
+}
+
+// recordSetChanges returns the record-set changes the query worker stored
+// for domain as RECORD_SET_CHANGED events, oldest first, optionally only
+// those of some significances.
+func recordSetChanges(ctx context.Context, db *sql.DB, domain string, significances map[string]bool) ([]*pb.LifecycleEvent, error) {
+	wanted := make([]string, 0, len(significances))
+	for significance := range significances {
+		wanted = append(wanted, significance)
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.record_type, c.version, c.significance, c.old_records, c.records,
+			COALESCE(c.old_ttl, 0), COALESCE(c.ttl, 0), c.observed_at
+		FROM record_set_changes c
+		JOIN domains d ON d.id = c.domain_id
+		WHERE d.domain_name = $1 AND (cardinality($2::text[]) = 0 OR c.significance = ANY($2))
+		ORDER BY c.observed_at, c.id
+	`, domain, pq.Array(wanted))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var changes []*pb.LifecycleEvent
+	for rows.Next() {
+		e := &pb.LifecycleEvent{Type: "RECORD_SET_CHANGED"}
+		var observedAt time.Time
+		if err := rows.Scan(&e.RecordType, &e.Version, &e.Significance, pq.Array(&e.OldRecords), pq.Array(&e.Records),
+			&e.OldTtl, &e.Ttl, &observedAt); err != nil {
+			return nil, err
+		}
+		e.ObservedAt = observedAt.UTC().Format(time.RFC3339)
+		changes = append(changes, e)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/resolver"
+)
+
+// liveRecordTypes are looked up when a LookupLive request names none.
+var liveRecordTypes = []string{"A", "AAAA"}
+
+// maxLiveRecordTypes bounds the queries a single LookupLive call sends.
+const maxLiveRecordTypes = 16
+
+// LookupLive resolves a domain live, one query per record type, through the
+// server's resolver: a recursive upstream over the requested transport, or
+// the domain's own nameservers if authoritative is set. The domain does not
+// have to be in the corpus and the answers are not stored. A client subnet
+// in the options is sent as EDNS Client Subnet and each answer reports the
+// scope the server gave it.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). A failed
+// query is reported in its answer rather than failing the call.
+func (s *server) LookupLive(ctx context.Context, req *pb.LookupLiveRequest) (*pb.LookupLiveResponse, error) {
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	if _, ok := dns.IsDomainName(domain); !ok || domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain %q", req.Domain)
+	}
+	requested := req.RecordType
+	if len(requested) == 0 {
+		requested = liveRecordTypes
+	}
+	if len(requested) > maxLiveRecordTypes {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d record types per lookup", maxLiveRecordTypes)
+	}
+	var qtypes []uint16
+	for _, rt := range requested {
+		qtype, ok := dns.StringToType[strings.ToUpper(rt)]
+		if !ok {
+			return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "unknown record type %q", rt)
+		}
+		qtypes = append(qtypes, qtype)
+	}
+
+	opts := req.Options
+	if opts == nil {
+		opts = &pb.LiveLookupOptions{}
+	}
+	lookup := resolver.LookupOptions{Transport: opts.Transport, Server: opts.Server, NoCache: opts.NoCache}
+	if opts.ClientSubnet != "" {
+		subnet, err := resolver.ParseClientSubnet(opts.ClientSubnet)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		lookup.ClientSubnet = subnet
+	}
+	if opts.Authoritative {
+		if opts.Server != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "server cannot be set for authoritative lookups")
+		}
+		// Any authoritative server passes the check
+		lookup.Nameserver = "."
+	}
+	if err := s.resolver.CheckLookup(lookup); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid options: %v", err)
+	}
+
+	start := time.Now()
+	resp := &pb.LookupLiveResponse{Domain: domain, Answers: make([]*pb.LiveAnswer, len(qtypes))}
+	if opts.Authoritative {
+		// Service names such as _sip._tls.example.com have no delegation
+		// of their own
+		nameservers, err := s.discoverNameservers(ctx, recordset.OwnerDomain(domain))
+		if err != nil {
+			errorf("LookupLive: Failed to discover nameservers for %s: %v", domain, err)
+			return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"domain": domain}, "failed to discover nameservers: %v", err)
+		}
+		resp.Nameservers = nameservers
+	}
+
+	var wg sync.WaitGroup
+	for i, qtype := range qtypes {
+		wg.Add(1)
+		go func(i int, qtype uint16) {
+			defer wg.Done()
+			resp.Answers[i] = s.lookupLive(ctx, domain, qtype, opts.Dnssec, lookup, resp.Nameservers)
+		}(i, qtype)
+	}
+	wg.Wait()
+	resp.ElapsedMs = float64(time.Since(start).Microseconds()) / 1000
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	infof("LookupLive: Response for domain %s: %d answers in %.1fms", domain, len(resp.Answers), resp.ElapsedMs)
+	return resp, nil
+}
+
+// lookupLive sends one query for domain. Authoritative lookups (those with
+// nameservers) try the nameservers in turn until one answers.
+func (s *server) lookupLive(ctx context.Context, domain string, qtype uint16, dnssec bool, lookup resolver.LookupOptions, nameservers []string) *pb.LiveAnswer {
+	answer := &pb.LiveAnswer{RecordType: dns.TypeToString[qtype]}
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), qtype)
+	if dnssec {
+		m.SetEdns0(4096, true)
+	}
+
+	var res *resolver.LookupResult
+	var err error
+	if len(nameservers) > 0 {
+		m.RecursionDesired = false
+		for _, ns := range nameservers {
+			lookup.Nameserver = ns + ":53"
+			res, err = s.resolver.Lookup(ctx, m, lookup)
+			if err == nil && res.Response.Rcode != dns.RcodeServerFailure && res.Response.Rcode != dns.RcodeRefused {
+				break
+			}
+		}
+	} else {
+		res, err = s.resolver.Lookup(ctx, m, lookup)
+	}
+	if res != nil {
+		answer.Server = res.Server
+		answer.Transport = res.Transport
+		answer.RttMs = float64(res.RTT.Microseconds()) / 1000
+		answer.Cached = res.Cached
+		answer.ClientSubnetScope = res.Scope
+	}
+	if err != nil {
+		answer.Error = err.Error()
+		return answer
+	}
+
+	r := res.Response
+	answer.Rcode = dns.RcodeToString[r.Rcode]
+	answer.AuthenticatedData = r.AuthenticatedData
+	answer.AuthoritativeAnswer = r.Authoritative
+	for _, rr := range r.Answer {
+		answer.Answer = append(answer.Answer, rr.String())
+	}
+	for _, rr := range r.Ns {
+		answer.Authority = append(answer.Authority, rr.String())
+	}
+	if r.Rcode == dns.RcodeServerFailure || r.Rcode == dns.RcodeRefused {
+		answer.Error = fmt.Sprintf("%s answered %s", res.Server, answer.Rcode)
+	}
+	return answer
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/moos3/bell/logging"
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// logger logs the server's messages. Its level is set from logging.level
+// or logging.modules at startup and can be changed at runtime with the
+// SetLogLevel RPC.
+var logger = logging.New("server")
+
+// debugf logs a message when the log level is debug.
+func debugf(format string, args ...interface{}) {
+	logger.Debugf(format, args...)
+}
+
+// infof logs a message when the log level is info or lower.
+func infof(format string, args ...interface{}) {
+	logger.Infof(format, args...)
+}
+
+// warnf logs a message when the log level is warn or lower: a request
+// refused for its credentials, or a degraded but working server.
+func warnf(format string, args ...interface{}) {
+	logger.Warnf(format, args...)
+}
+
+// errorf logs a failure.
+func errorf(format string, args ...interface{}) {
+	logger.Errorf(format, args...)
+}
+
+// Request log verbosities for logging.routes.
+const (
+	verbosityNone    = "none"    // Do not log the request
+	verbosityBasic   = "basic"   // Method, path, status, and duration
+	verbosityHeaders = "headers" // Basic plus request headers, with credentials redacted
+)
+
+// sensitiveHeaders are never logged verbatim; logging.redact_headers adds
+// to them. The x-api-key header is logged as logging.api_keys has keys
+// logged, so a key's requests can be told apart.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// requestLogger logs a sample of HTTP requests with per-route verbosity.
+type requestLogger struct {
+	sampleRate float64           // Fraction of requests logged (0-1)
+	routes     map[string]string // Path prefix -> verbosity; longest prefix wins
+	sensitive  map[string]bool   // Canonical names of the headers not logged verbatim
+}
+
+// newRequestLogger returns a requestLogger that also redacts the headers
+// named in redact.
+func newRequestLogger(sampleRate float64, routes map[string]string, redact []string) *requestLogger {
+	sensitive := make(map[string]bool)
+	for _, name := range append(sensitiveHeaders, redact...) {
+		sensitive[http.CanonicalHeaderKey(name)] = true
+	}
+	return &requestLogger{sampleRate: sampleRate, routes: routes, sensitive: sensitive}
+}
+
+// verbosity returns the verbosity configured for path, defaulting to basic.
+func (l *requestLogger) verbosity(path string) string {
+	verbosity, longest := verbosityBasic, -1
+	for prefix, v := range l.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			verbosity, longest = v, len(prefix)
+		}
+	}
+	return verbosity
+}
+
+// statusRecorder captures the response status code for request logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, which gRPC-Web and streaming responses require.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// middleware returns an http.Handler that logs sampled requests after they
+// are served by next.
+func (l *requestLogger) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verbosity := l.verbosity(r.URL.Path)
+		if verbosity == verbosityNone || !logger.Enabled(slog.LevelInfo) || rand.Float64() >= l.sampleRate {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		attrs := []any{"method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start).Round(time.Millisecond)}
+		if verbosity == verbosityHeaders {
+			headers := make([]any, 0, 2*len(r.Header))
+			for name, values := range r.Header {
+				value := strings.Join(values, ", ")
+				switch {
+				case name == "X-Api-Key":
+					value = logging.APIKey(value)
+				case l.sensitive[name]:
+					value = "[REDACTED]"
+				}
+				headers = append(headers, name, value)
+			}
+			attrs = append(attrs, slog.Group("headers", headers...))
+		}
+		logger.Log(slog.LevelInfo, "Request", attrs...)
+	})
+}
+
+// SetLogLevel changes the log level of the server, or of one of its modules
+// given by name, at runtime, or reports the current level if no level is
+// given. Modules without a level of their own follow the server's.
+//
+// It requires an API key with the admin scope.
+func (s *server) SetLogLevel(ctx context.Context, req *pb.SetLogLevelRequest) (*pb.SetLogLevelResponse, error) {
+	current, set := logging.Level, logging.SetLevel
+	if req.Module != "" {
+		module := logging.Module(req.Module)
+		if module == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown module %q", req.Module)
+		}
+		current, set = module.Level, module.SetLevel
+	}
+	previous := current()
+	if req.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid log level %q; must be debug, info, warn, or error", req.Level)
+		}
+		set(level)
+		infof("SetLogLevel: Log level of %s changed from %s to %s", cmp.Or(req.Module, "all modules"), previous, level)
+	}
+	return &pb.SetLogLevelResponse{
+		Level:         strings.ToLower(current().String()),
+		PreviousLevel: strings.ToLower(previous.String()),
+	}, nil
+}
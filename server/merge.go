@@ -0,0 +1,107 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// mergePolicy decides which source is authoritative when CZDS and QUERY
+// records disagree for the same domain and record type.
+type mergePolicy struct {
+	precedence    map[string]int // Source -> rank, lower is preferred
+	freshnessWins bool           // Prefer the most recently updated source over precedence
+}
+
+// newMergePolicy builds a mergePolicy from the configured source precedence.
+func newMergePolicy(precedence []string, freshnessWins bool) mergePolicy {
+	p := mergePolicy{precedence: make(map[string]int), freshnessWins: freshnessWins}
+	for i, source := range precedence {
+		p.precedence[source] = i
+	}
+	return p
+}
+
+// rank returns the precedence rank of a source; unknown sources rank last.
+func (p mergePolicy) rank(source string) int {
+	if r, ok := p.precedence[source]; ok {
+		return r
+	}
+	return len(p.precedence)
+}
+
+// sourceGroup holds the records of a single source for a record type.
+type sourceGroup struct {
+	source      string
+	records     []*pb.DNSRecord
+	lastUpdated time.Time
+}
+
+// merge reduces records to a single authoritative source per record type and
+// returns the kept records along with provenance for each record type.
+// Record order within the chosen source is preserved.
+func (p mergePolicy) merge(records []*pb.DNSRecord) ([]*pb.DNSRecord, []*pb.MergeProvenance) {
+	groups := make(map[string]map[string]*sourceGroup) // record type -> source -> group
+	var types []string
+	for _, r := range records {
+		bySource, ok := groups[r.RecordType]
+		if !ok {
+			bySource = make(map[string]*sourceGroup)
+			groups[r.RecordType] = bySource
+			types = append(types, r.RecordType)
+		}
+		g, ok := bySource[r.Source]
+		if !ok {
+			g = &sourceGroup{source: r.Source}
+			bySource[r.Source] = g
+		}
+		g.records = append(g.records, r)
+		if t, err := time.Parse(time.RFC3339, r.LastUpdated); err == nil && t.After(g.lastUpdated) {
+			g.lastUpdated = t
+		}
+	}
+
+	var merged []*pb.DNSRecord
+	var provenance []*pb.MergeProvenance
+	for _, rt := range types {
+		var candidates []*sourceGroup
+		for _, g := range groups[rt] {
+			candidates = append(candidates, g)
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if p.freshnessWins && !a.lastUpdated.Equal(b.lastUpdated) {
+				return a.lastUpdated.After(b.lastUpdated)
+			}
+			if ra, rb := p.rank(a.source), p.rank(b.source); ra != rb {
+				return ra < rb
+			}
+			return a.source < b.source
+		})
+
+		chosen := candidates[0]
+		// Freshness only decides when the chosen source is strictly newer than
+		// the runner-up; ties fall through to precedence.
+		reason := "single_source"
+		if len(candidates) > 1 {
+			reason = "precedence"
+			if p.freshnessWins && chosen.lastUpdated.After(candidates[1].lastUpdated) {
+				reason = "freshness"
+			}
+		}
+		prov := &pb.MergeProvenance{
+			RecordType:        rt,
+			ChosenSource:      chosen.source,
+			Reason:            reason,
+			ChosenLastUpdated: chosen.lastUpdated.Format(time.RFC3339),
+		}
+		for _, g := range candidates[1:] {
+			prov.DiscardedSources = append(prov.DiscardedSources, g.source)
+			prov.DiscardedRecords += int32(len(g.records))
+		}
+		merged = append(merged, chosen.records...)
+		provenance = append(provenance, prov)
+	}
+	return merged, provenance
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// pageTokenPrefix versions the page token format of GetDomainsByNameserver,
+// ListSubdomains and ListDomainsByTLD. v1 tokens, without a snapshot
+// cutoff, are still read.
+const pageTokenPrefix = "v2:"
+
+// GetDomainsByNameserver returns the domains whose zone delegation lists a
+// nameserver host, in name order. Domains of every TLD are searched, so each
+// healthy shard is asked for a page past the token's domain through the GIN
+// index on domains.nameservers and the pages are merged. Domains restricted
+// to other organizations are left out. The key's preferences supply the page
+// size the request leaves unset. Later pages leave out domains inserted
+// after the first was served (see snapshotCutoff).
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetDomainsByNameserver(ctx context.Context, req *pb.GetDomainsByNameserverRequest) (*pb.GetDomainsByNameserverResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetDomainsByNameserver", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	// Zone parsing stores nameservers without the trailing dot
+	nameserver := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Nameserver), "."))
+	if nameserver == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nameserver is required")
+	}
+	pageSize := int(prefs.limit(req.PageSize))
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	after, cutoff, err := parsePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	var mu sync.Mutex
+	var domains []*pb.DelegatedDomain
+	resp := &pb.GetDomainsByNameserverResponse{}
+	resp.SkippedShards, err = s.shards.FanOut(ctx, func(ctx context.Context, shard *storage.Shard) error {
+		// One extra row tells whether the shard has more
+		rows, err := shard.Reader(ctx).QueryContext(ctx, `
+			SELECT domain_name, tld, nameservers, first_seen
+			FROM domains
+			WHERE nameservers && $1 AND domain_name COLLATE "C" > $2
+				AND (restricted_to IS NULL OR $4 = ANY(restricted_to)) AND inserted_at <= $5
+			ORDER BY domain_name COLLATE "C"
+			LIMIT $3
+		`, pq.Array([]string{nameserver}), after, pageSize+1, prefs.orgID, cutoff)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var d pb.DelegatedDomain
+			var firstSeen time.Time
+			if err := rows.Scan(&d.Domain, &d.Tld, pq.Array(&d.Nameservers), &firstSeen); err != nil {
+				return err
+			}
+			d.FirstSeen = firstSeen.Format(time.RFC3339)
+			mu.Lock()
+			domains = append(domains, &d)
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		errorf("GetDomainsByNameserver: Failed to query domains of %s: %v", nameserver, err)
+		return nil, status.Errorf(codes.Internal, "failed to query domains: %v", err)
+	}
+
+	// Byte order, as the shards sort with COLLATE "C", so tokens are stable
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Domain < domains[j].Domain })
+	if len(domains) > pageSize {
+		domains = domains[:pageSize]
+		resp.NextPageToken = newPageToken(cutoff, domains[pageSize-1].Domain)
+	}
+	resp.Domains = domains
+	infof("GetDomainsByNameserver: Returning %d domains of %s (more: %t, skipped shards: %v)", len(domains), nameserver, resp.NextPageToken != "", resp.SkippedShards)
+	return resp, nil
+}
+
+// newPageToken encodes the snapshot cutoff of a listing and the last domain
+// of a page as an opaque token.
+func newPageToken(cutoff time.Time, last string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + cutoff.UTC().Format(time.RFC3339Nano) + "|" + last))
+}
+
+// parsePageToken decodes a token from newPageToken into the last domain and
+// the cutoff. An empty token starts before every domain, in a snapshot
+// starting now, as does the cutoff of a v1 token.
+func parsePageToken(token string) (string, time.Time, error) {
+	if token == "" {
+		return "", time.Now().UTC(), nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(raw), "v1:") {
+		return strings.TrimPrefix(string(raw), "v1:"), time.Now().UTC(), nil
+	}
+	if err != nil || !strings.HasPrefix(string(raw), pageTokenPrefix) {
+		return "", time.Time{}, fmt.Errorf("malformed page token")
+	}
+	at, last, ok := strings.Cut(strings.TrimPrefix(string(raw), pageTokenPrefix), "|")
+	cutoff, err := time.Parse(time.RFC3339Nano, at)
+	if !ok || err != nil {
+		return "", time.Time{}, fmt.Errorf("malformed page token")
+	}
+	return last, cutoff, nil
+}
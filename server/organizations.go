@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// Organization limits.
+const (
+	maxOrganizationKeys  = 1000
+	defaultUsageDays     = 30
+	maxUsageDays         = 90
+	maxDescriptionLength = 255
+)
+
+// orgMember returns the organization of apiKey and whether the key is one of
+// its org admins. Keys outside any organization get NO_ORGANIZATION.
+func (s *server) orgMember(ctx context.Context, method, apiKey string) (int, bool, error) {
+	if s.sandbox {
+		return 0, false, statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "organizations are not available in the sandbox")
+	}
+	var orgID sql.NullInt64
+	var orgAdmin bool
+	err := s.keys.QueryRowContext(ctx, "SELECT organization_id, org_admin FROM api_keys WHERE api_key = $1", apiKey).Scan(&orgID, &orgAdmin)
+	if err != nil {
+		errorf("%s: Failed to look up organization of API key %s: %v", method, apiKey, err)
+		return 0, false, status.Errorf(codes.Internal, "failed to look up organization: %v", err)
+	}
+	if !orgID.Valid {
+		warnf("%s: API key %s does not belong to an organization", method, apiKey)
+		return 0, false, statusError(codes.NotFound, reasonNoOrganization, nil, "API key does not belong to an organization")
+	}
+	return int(orgID.Int64), orgAdmin, nil
+}
+
+// orgAdmin returns the organization of apiKey, which must be one of its org
+// admins.
+func (s *server) orgAdmin(ctx context.Context, method, apiKey string) (int, error) {
+	orgID, orgAdmin, err := s.orgMember(ctx, method, apiKey)
+	if err != nil {
+		return 0, err
+	}
+	if !orgAdmin {
+		warnf("%s: API key %s is not an org admin key", method, apiKey)
+		return 0, statusError(codes.PermissionDenied, reasonOrgAdminRequired, nil, "org admin API key required")
+	}
+	return orgID, nil
+}
+
+// loadOrganization reads an organization and today's usage, and its keys
+// when withKeys is set.
+func (s *server) loadOrganization(ctx context.Context, method string, orgID int, withKeys bool) (*pb.Organization, error) {
+	org := &pb.Organization{Watchlist: []string{}}
+	var createdAt time.Time
+	err := s.keys.QueryRowContext(ctx, "SELECT id, name, daily_request_quota, watchlist, created_at FROM organizations WHERE id = $1", orgID).
+		Scan(&org.Id, &org.Name, &org.DailyRequestQuota, pq.Array(&org.Watchlist), &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "organization %d not found", orgID)
+	}
+	if err != nil {
+		errorf("%s: Failed to query organization %d: %v", method, orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query organization: %v", err)
+	}
+	org.CreatedAt = createdAt.Format(time.RFC3339)
+
+	err = s.keys.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(u.requests), 0)
+		FROM usage_counts u
+		JOIN api_keys k ON k.api_key::text = u.api_key
+		WHERE k.organization_id = $1 AND u.day = $2
+	`, orgID, time.Now().UTC().Format("2006-01-02")).Scan(&org.RequestsToday)
+	if err != nil {
+		errorf("%s: Failed to query usage of organization %d: %v", method, orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query organization usage: %v", err)
+	}
+
+	if !withKeys {
+		return org, nil
+	}
+	rows, err := s.keys.QueryContext(ctx, `
+		SELECT api_key, COALESCE(description, ''), COALESCE(is_active, FALSE), org_admin, created_at
+		FROM api_keys
+		WHERE organization_id = $1
+		ORDER BY created_at, api_key
+	`, orgID)
+	if err != nil {
+		errorf("%s: Failed to query keys of organization %d: %v", method, orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query organization keys: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key pb.OrganizationKey
+		var keyCreatedAt sql.NullTime
+		if err := rows.Scan(&key.ApiKey, &key.Description, &key.Active, &key.OrgAdmin, &keyCreatedAt); err != nil {
+			errorf("%s: Failed to scan organization key: %v", method, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan organization key: %v", err)
+		}
+		if keyCreatedAt.Valid {
+			key.CreatedAt = keyCreatedAt.Time.Format(time.RFC3339)
+		}
+		org.Keys = append(org.Keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("%s: Failed to iterate organization keys: %v", method, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate organization keys: %v", err)
+	}
+	return org, nil
+}
+
+// GetOrganization returns the organization of the caller's API key and its
+// usage today. Org admins also get the organization's keys.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key") and stays
+// available when the organization is over its quota.
+func (s *server) GetOrganization(ctx context.Context, req *pb.GetOrganizationRequest) (*pb.Organization, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, orgAdmin, err := s.orgMember(ctx, "GetOrganization", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadOrganization(ctx, "GetOrganization", orgID, orgAdmin)
+}
+
+// CreateOrganizationKey issues a new API key in the caller's organization,
+// with the caller's redaction role. The new key is returned once here and
+// afterwards only to org admins.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key").
+func (s *server) CreateOrganizationKey(ctx context.Context, req *pb.CreateOrganizationKeyRequest) (*pb.OrganizationKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "CreateOrganizationKey", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(req.Description)
+	if len(description) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d bytes", maxDescriptionLength)
+	}
+
+	var count int
+	if err := s.keys.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_keys WHERE organization_id = $1", orgID).Scan(&count); err != nil {
+		errorf("CreateOrganizationKey: Failed to count keys of organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to count organization keys: %v", err)
+	}
+	if count >= maxOrganizationKeys {
+		return nil, status.Errorf(codes.ResourceExhausted, "at most %d keys per organization", maxOrganizationKeys)
+	}
+	key := &pb.OrganizationKey{ApiKey: uuid.NewString(), Description: description, Active: true, OrgAdmin: req.OrgAdmin}
+	var createdAt time.Time
+	err = s.keys.QueryRowContext(ctx, `
+		INSERT INTO api_keys (api_key, description, organization_id, org_admin, role)
+		SELECT $1, $2, organization_id, $3, role FROM api_keys WHERE api_key = $4
+		RETURNING created_at
+	`, key.ApiKey, description, req.OrgAdmin, apiKey).Scan(&createdAt)
+	if err != nil {
+		errorf("CreateOrganizationKey: Failed to create key in organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %v", err)
+	}
+	s.keysChanged(key.ApiKey)
+	key.CreatedAt = createdAt.Format(time.RFC3339)
+	infof("CreateOrganizationKey: API key %s created key %s in organization %d (org admin %t)", apiKey, key.ApiKey, orgID, key.OrgAdmin)
+	return key, nil
+}
+
+// UpdateOrganizationKey replaces the description, active flag and org admin
+// flag of a key in the caller's organization. Org admins cannot deactivate
+// or demote their own key, so an organization always keeps an admin.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key").
+func (s *server) UpdateOrganizationKey(ctx context.Context, req *pb.UpdateOrganizationKeyRequest) (*pb.OrganizationKey, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "UpdateOrganizationKey", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uuid.Parse(req.ApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api_key %q", req.ApiKey)
+	}
+	if target.String() == strings.ToLower(apiKey) && (!req.Active || !req.OrgAdmin) {
+		return nil, status.Errorf(codes.FailedPrecondition, "an org admin cannot deactivate or demote its own key")
+	}
+	description := strings.TrimSpace(req.Description)
+	if len(description) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d bytes", maxDescriptionLength)
+	}
+
+	key := &pb.OrganizationKey{ApiKey: target.String(), Description: description, Active: req.Active, OrgAdmin: req.OrgAdmin}
+	var createdAt sql.NullTime
+	err = s.keys.QueryRowContext(ctx, `
+		UPDATE api_keys SET description = $1, is_active = $2, org_admin = $3
+		WHERE api_key = $4 AND organization_id = $5
+		RETURNING created_at
+	`, description, req.Active, req.OrgAdmin, key.ApiKey, orgID).Scan(&createdAt)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found in organization", key.ApiKey)
+	}
+	if err != nil {
+		errorf("UpdateOrganizationKey: Failed to update key %s: %v", key.ApiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to update API key: %v", err)
+	}
+	if createdAt.Valid {
+		key.CreatedAt = createdAt.Time.Format(time.RFC3339)
+	}
+	s.keysChanged(key.ApiKey)
+	infof("UpdateOrganizationKey: API key %s set key %s active %t, org admin %t", apiKey, key.ApiKey, key.Active, key.OrgAdmin)
+	return key, nil
+}
+
+// SetOrganizationWatchlist replaces the watchlist of the caller's
+// organization. The report worker adds it to the watchlist of every member
+// key's NEW_DOMAINS reports. Use ImportWatchlist for large lists.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key").
+func (s *server) SetOrganizationWatchlist(ctx context.Context, req *pb.SetOrganizationWatchlistRequest) (*pb.Organization, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "SetOrganizationWatchlist", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	watchlist := []string{}
+	seen := make(map[string]bool)
+	for _, term := range req.Watchlist {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" && !seen[term] {
+			seen[term] = true
+			watchlist = append(watchlist, term)
+		}
+	}
+	if len(watchlist) > maxOrganizationWatchlistTerms {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d watchlist terms", maxOrganizationWatchlistTerms)
+	}
+	if _, err := s.keys.ExecContext(ctx, "UPDATE organizations SET watchlist = $1 WHERE id = $2", pq.Array(watchlist), orgID); err != nil {
+		errorf("SetOrganizationWatchlist: Failed to update organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to update watchlist: %v", err)
+	}
+	infof("SetOrganizationWatchlist: API key %s set %d watchlist terms for organization %d", apiKey, len(watchlist), orgID)
+	return s.loadOrganization(ctx, "SetOrganizationWatchlist", orgID, true)
+}
+
+// GetOrganizationUsage returns the requests and errors of each key in the
+// caller's organization per UTC day, from usage_counts.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key") and
+// stays available when the organization is over its quota.
+func (s *server) GetOrganizationUsage(ctx context.Context, req *pb.GetOrganizationUsageRequest) (*pb.GetOrganizationUsageResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "GetOrganizationUsage", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	days := int(req.Days)
+	if days == 0 {
+		days = defaultUsageDays
+	}
+	if days < 0 || days > maxUsageDays {
+		return nil, status.Errorf(codes.InvalidArgument, "days must be between 1 and %d", maxUsageDays)
+	}
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), now.Day()-(days-1), 0, 0, 0, 0, time.UTC)
+
+	rows, err := s.keys.QueryContext(ctx, `
+		SELECT u.day, u.api_key, COALESCE(k.description, ''), SUM(u.requests), SUM(u.errors)
+		FROM usage_counts u
+		JOIN api_keys k ON k.api_key::text = u.api_key
+		WHERE k.organization_id = $1 AND u.day >= $2
+		GROUP BY u.day, u.api_key, k.description
+		ORDER BY u.day DESC, u.api_key
+	`, orgID, since)
+	if err != nil {
+		errorf("GetOrganizationUsage: Failed to query usage of organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query organization usage: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.GetOrganizationUsageResponse{}
+	for rows.Next() {
+		var u pb.OrganizationUsage
+		var day time.Time
+		if err := rows.Scan(&day, &u.ApiKey, &u.Description, &u.Requests, &u.Errors); err != nil {
+			errorf("GetOrganizationUsage: Failed to scan usage: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan organization usage: %v", err)
+		}
+		u.Day = day.Format("2006-01-02")
+		resp.TotalRequests += u.Requests
+		resp.Usage = append(resp.Usage, &u)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetOrganizationUsage: Failed to iterate usage: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate organization usage: %v", err)
+	}
+	return resp, nil
+}
+
+// CreateOrganization creates an organization and makes an existing key its
+// first org admin. The key must not be a sandbox key or already belong to an
+// organization.
+//
+// It requires an API key with the admin scope in the gRPC metadata ("x-api-key").
+func (s *server) CreateOrganization(ctx context.Context, req *pb.CreateOrganizationRequest) (*pb.Organization, error) {
+	apiKey := apiKeyFromContext(ctx)
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required and must be at most %d bytes", maxDescriptionLength)
+	}
+	if req.DailyRequestQuota < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "daily_request_quota must not be negative")
+	}
+	adminKey, err := uuid.Parse(req.AdminApiKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid admin_api_key %q", req.AdminApiKey)
+	}
+
+	tx, err := s.keys.BeginTx(ctx, nil)
+	if err != nil {
+		errorf("CreateOrganization: Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to create organization: %v", err)
+	}
+	defer tx.Rollback()
+	var orgID int
+	err = tx.QueryRowContext(ctx, "INSERT INTO organizations (name, daily_request_quota) VALUES ($1, $2) RETURNING id", name, req.DailyRequestQuota).Scan(&orgID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+		return nil, status.Errorf(codes.AlreadyExists, "organization %q already exists", name)
+	}
+	if err != nil {
+		errorf("CreateOrganization: Failed to insert organization %q: %v", name, err)
+		return nil, status.Errorf(codes.Internal, "failed to create organization: %v", err)
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE api_keys SET organization_id = $1, org_admin = TRUE
+		WHERE api_key = $2 AND organization_id IS NULL AND NOT sandbox
+	`, orgID, adminKey.String())
+	if err != nil {
+		errorf("CreateOrganization: Failed to add key %s to organization %d: %v", adminKey, orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to create organization: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "API key %s does not exist, is a sandbox key or already belongs to an organization", adminKey)
+	}
+	if err := tx.Commit(); err != nil {
+		errorf("CreateOrganization: Failed to commit organization %q: %v", name, err)
+		return nil, status.Errorf(codes.Internal, "failed to create organization: %v", err)
+	}
+	s.keysChanged(adminKey.String())
+	infof("CreateOrganization: API key %s created organization %d (%s) with org admin %s", apiKey, orgID, name, adminKey)
+	return s.loadOrganization(ctx, "CreateOrganization", orgID, true)
+}
+
+// SetOrganizationQuota changes the daily request quota an organization's
+// keys share. Servers pick up the change within a minute.
+//
+// It requires an API key with the admin scope in the gRPC metadata ("x-api-key").
+func (s *server) SetOrganizationQuota(ctx context.Context, req *pb.SetOrganizationQuotaRequest) (*pb.Organization, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if req.DailyRequestQuota < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "daily_request_quota must not be negative")
+	}
+	result, err := s.keys.ExecContext(ctx, "UPDATE organizations SET daily_request_quota = $1 WHERE id = $2", req.DailyRequestQuota, req.OrganizationId)
+	if err != nil {
+		errorf("SetOrganizationQuota: Failed to update organization %d: %v", req.OrganizationId, err)
+		return nil, status.Errorf(codes.Internal, "failed to update organization quota: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.NotFound, "organization %d not found", req.OrganizationId)
+	}
+	infof("SetOrganizationQuota: API key %s set the daily quota of organization %d to %d", apiKey, req.OrganizationId, req.DailyRequestQuota)
+	return s.loadOrganization(ctx, "SetOrganizationQuota", int(req.OrganizationId), true)
+}
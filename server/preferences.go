@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Key timezones must load on images without a zoneinfo database
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// preferenceCacheTTL bounds how long a key's preferences are cached. Changes
+// made through SetKeyPreferences apply immediately on the instance that
+// handled them and within this long elsewhere.
+const preferenceCacheTTL = time.Minute
+
+// maxPreferredRows caps the max_rows a key may set.
+const maxPreferredRows = 100000
+
+// keyPreferences are the defaults a key applies to requests that leave the
+// corresponding fields unset, and the organization it reads restricted
+// domains for.
+type keyPreferences struct {
+	recordTypes []string       // GetRecords record types
+	merge       *mergePolicy   // GetRecords merge policy; nil uses the server's
+	maxRows     int            // Cap on GetRecords records and default limit elsewhere; 0 is none
+	location    *time.Location // Zone for response timestamps; nil keeps UTC
+	orgID       int            // Organization of the key, whose restricted domains it may read; 0 if none
+
+	expires time.Time
+}
+
+// limit returns requested, or the key's max_rows if the request left it unset.
+func (p *keyPreferences) limit(requested int32) int32 {
+	if requested == 0 && p.maxRows > 0 {
+		return int32(p.maxRows)
+	}
+	return requested
+}
+
+// preferenceStore loads and caches key preferences from key_preferences.
+type preferenceStore struct {
+	db            *sql.DB
+	freshnessWins bool // From the server merge policy, kept for per-key precedence
+
+	mu    sync.Mutex
+	cache map[string]*keyPreferences // API key -> preferences
+}
+
+func newPreferenceStore(db *sql.DB, freshnessWins bool) *preferenceStore {
+	return &preferenceStore{db: db, freshnessWins: freshnessWins, cache: make(map[string]*keyPreferences)}
+}
+
+// get returns the preferences of apiKey. Keys without stored preferences get
+// empty ones.
+func (ps *preferenceStore) get(ctx context.Context, apiKey string) (*keyPreferences, error) {
+	ps.mu.Lock()
+	cached, ok := ps.cache[apiKey]
+	ps.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+	stored, err := ps.load(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	prefs := &keyPreferences{recordTypes: stored.RecordTypes, maxRows: int(stored.MaxRows), expires: time.Now().Add(preferenceCacheTTL)}
+	// Keys join an organization when they are created, so its ID is cached
+	// along with the preferences
+	if err := ps.db.QueryRowContext(ctx, "SELECT COALESCE(organization_id, 0) FROM api_keys WHERE api_key = $1", apiKey).Scan(&prefs.orgID); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if len(stored.SourcePrecedence) > 0 {
+		policy := newMergePolicy(stored.SourcePrecedence, ps.freshnessWins)
+		prefs.merge = &policy
+	}
+	if stored.Timezone != "" {
+		// Validated when stored; an unloadable zone falls back to UTC
+		if loc, err := time.LoadLocation(stored.Timezone); err == nil {
+			prefs.location = loc
+		}
+	}
+	ps.mu.Lock()
+	ps.cache[apiKey] = prefs
+	ps.mu.Unlock()
+	return prefs, nil
+}
+
+// forget drops the cached preferences of apiKey.
+func (ps *preferenceStore) forget(apiKey string) {
+	ps.mu.Lock()
+	delete(ps.cache, apiKey)
+	ps.mu.Unlock()
+}
+
+// load reads the stored preferences of apiKey.
+func (ps *preferenceStore) load(ctx context.Context, apiKey string) (*pb.KeyPreferences, error) {
+	var stored pb.KeyPreferences
+	err := ps.db.QueryRowContext(ctx, `
+		SELECT record_types, source_precedence, max_rows, timezone
+		FROM key_preferences
+		WHERE api_key = $1
+	`, apiKey).Scan(pq.Array(&stored.RecordTypes), pq.Array(&stored.SourcePrecedence), &stored.MaxRows, &stored.Timezone)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// unaryInterceptor converts RFC 3339 timestamps in successful responses to
+// the caller's preferred timezone. Requests without an API key are rejected
+// by the authenticator.
+func (ps *preferenceStore) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if msg, ok := resp.(proto.Message); ok {
+		if err := ps.localize(ctx, info.FullMethod, msg); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls; each response
+// is converted before it is sent.
+func (ps *preferenceStore) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &interceptedStream{ServerStream: ss, send: func(resp proto.Message) error {
+		return ps.localize(ss.Context(), info.FullMethod, resp)
+	}})
+}
+
+// localize converts the timestamps of resp to the caller's timezone.
+func (ps *preferenceStore) localize(ctx context.Context, fullMethod string, resp proto.Message) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return nil
+	}
+	prefs, err := ps.get(ctx, apiKeys[0])
+	if err != nil {
+		errorf("%s: Failed to look up key preferences: %v", fullMethod, err)
+		return status.Errorf(codes.Internal, "failed to look up key preferences: %v", err)
+	}
+	if prefs.location != nil {
+		localizeTimestamps(resp.ProtoReflect(), prefs.location)
+	}
+	return nil
+}
+
+// isTimestampField reports whether a string field holds an RFC 3339 time by
+// the naming the API uses (computed_at, last_updated, first_seen, time, ...).
+func isTimestampField(name string) bool {
+	return strings.HasSuffix(name, "_at") || strings.HasSuffix(name, "_updated") || strings.HasSuffix(name, "_seen") || name == "time"
+}
+
+// localizeTimestamps rewrites RFC 3339 timestamp fields of m in loc,
+// recursing into message and repeated message fields. Values that do not
+// parse are left alone.
+func localizeTimestamps(m protoreflect.Message, loc *time.Location) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() && isTimestampField(string(fd.Name())):
+			if t, err := time.Parse(time.RFC3339Nano, v.String()); err == nil {
+				layout := time.RFC3339
+				if t.Nanosecond() != 0 {
+					layout = time.RFC3339Nano
+				}
+				m.Set(fd, protoreflect.ValueOfString(t.In(loc).Format(layout)))
+			}
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				localizeTimestamps(list.Get(i).Message(), loc)
+			}
+		case fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			localizeTimestamps(v.Message(), loc)
+		}
+		return true
+	})
+}
+
+// keyPreferences returns the preferences of apiKey for method's handler.
+func (s *server) keyPreferences(ctx context.Context, method, apiKey string) (*keyPreferences, error) {
+	prefs, err := s.prefs.get(ctx, apiKey)
+	if err != nil {
+		errorf("%s: Failed to look up preferences for API key %s: %v", method, apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up key preferences: %v", err)
+	}
+	return prefs, nil
+}
+
+// GetKeyPreferences returns the defaults stored for the caller's API key.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetKeyPreferences(ctx context.Context, req *pb.GetKeyPreferencesRequest) (*pb.KeyPreferences, error) {
+	apiKey := apiKeyFromContext(ctx)
+	stored, err := s.prefs.load(ctx, apiKey)
+	if err != nil {
+		errorf("GetKeyPreferences: Failed to load preferences for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to load preferences: %v", err)
+	}
+	return stored, nil
+}
+
+// SetKeyPreferences replaces the defaults stored for the caller's API key.
+// Unset fields clear the corresponding default.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"); a key can
+// only change its own preferences.
+func (s *server) SetKeyPreferences(ctx context.Context, req *pb.SetKeyPreferencesRequest) (*pb.KeyPreferences, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs := req.Preferences
+	if prefs == nil {
+		prefs = &pb.KeyPreferences{}
+	}
+	stored := &pb.KeyPreferences{RecordTypes: []string{}, SourcePrecedence: []string{}, MaxRows: prefs.MaxRows, Timezone: prefs.Timezone}
+	for _, rt := range prefs.RecordTypes {
+		rt = strings.ToUpper(rt)
+		if _, ok := dns.StringToType[rt]; !ok {
+			return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "unknown record type %q", rt)
+		}
+		stored.RecordTypes = append(stored.RecordTypes, rt)
+	}
+	for _, source := range prefs.SourcePrecedence {
+		source = strings.ToUpper(strings.TrimSpace(source))
+		if source == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "source_precedence entries must not be empty")
+		}
+		stored.SourcePrecedence = append(stored.SourcePrecedence, source)
+	}
+	if stored.MaxRows < 0 || stored.MaxRows > maxPreferredRows {
+		return nil, status.Errorf(codes.InvalidArgument, "max_rows must be between 0 and %d", maxPreferredRows)
+	}
+	if stored.Timezone != "" {
+		if _, err := time.LoadLocation(stored.Timezone); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown timezone %q", stored.Timezone)
+		}
+	}
+
+	_, err := s.prefs.db.ExecContext(ctx, `
+		INSERT INTO key_preferences (api_key, record_types, source_precedence, max_rows, timezone, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (api_key) DO UPDATE
+		SET record_types = EXCLUDED.record_types, source_precedence = EXCLUDED.source_precedence,
+			max_rows = EXCLUDED.max_rows, timezone = EXCLUDED.timezone, updated_at = EXCLUDED.updated_at
+	`, apiKey, pq.Array(stored.RecordTypes), pq.Array(stored.SourcePrecedence), stored.MaxRows, stored.Timezone, time.Now().UTC())
+	if err != nil {
+		errorf("SetKeyPreferences: Failed to store preferences for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to store preferences: %v", err)
+	}
+	s.prefs.forget(apiKey)
+	s.keysChanged(apiKey)
+	infof("SetKeyPreferences: Updated preferences for API key %s", apiKey)
+	return stored, nil
+}
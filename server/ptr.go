@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// PTR range limits for GetPTRRange.
+const (
+	defaultPTRRangeLimit = 1000
+	maxPTRRangeLimit     = 10000
+)
+
+// GetPTR returns the PTR records of an address.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Records
+// come from reverse zones ingested by czds unless live is set, in which case
+// the address is resolved through the shared resolver's upstreams.
+func (s *server) GetPTR(ctx context.Context, req *pb.GetPTRRequest) (*pb.GetPTRResponse, error) {
+	ip, err := netip.ParseAddr(req.Ip)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid IP address %q", req.Ip)
+	}
+	ip = ip.Unmap().WithZone("")
+	resp := &pb.GetPTRResponse{Ip: ip.String()}
+
+	if req.Live {
+		if s.resolver == nil {
+			return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+		}
+		arpa, err := dns.ReverseAddr(ip.String())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid IP address %q", req.Ip)
+		}
+		m := new(dns.Msg)
+		m.SetQuestion(arpa, dns.TypePTR)
+		answer, upstream, err := s.resolver.Recursive(ctx, m)
+		if err != nil {
+			errorf("GetPTR: Failed to resolve %s using %s: %v", arpa, upstream, err)
+			return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"retry_after": upstreamRetryAfter}, "failed to resolve PTR: %v", err)
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		for _, rr := range answer.Answer {
+			if ptr, ok := rr.(*dns.PTR); ok {
+				resp.Records = append(resp.Records, &pb.PTRRecord{
+					Ip:          resp.Ip,
+					PtrName:     strings.TrimSuffix(dns.CanonicalName(ptr.Ptr), "."),
+					Ttl:         int32(ptr.Hdr.Ttl),
+					Source:      "LIVE",
+					LastUpdated: now,
+				})
+			}
+		}
+		infof("GetPTR: Resolved %s live: %d records", resp.Ip, len(resp.Records))
+		return resp, nil
+	}
+
+	rows, err := s.shards.ForTLD("arpa").DB.QueryContext(ctx, `
+		SELECT host(ip), ptr_name, ttl, source, zone, last_updated
+		FROM ptr_records
+		WHERE ip = $1::inet
+		ORDER BY ptr_name, source
+	`, resp.Ip)
+	if err != nil {
+		errorf("GetPTR: Failed to query PTR records for %s: %v", resp.Ip, err)
+		return nil, status.Errorf(codes.Internal, "failed to query PTR records: %v", err)
+	}
+	defer rows.Close()
+	if resp.Records, err = scanPTRRecords(rows); err != nil {
+		errorf("GetPTR: Failed to read PTR records for %s: %v", resp.Ip, err)
+		return nil, status.Errorf(codes.Internal, "failed to read PTR records: %v", err)
+	}
+	infof("GetPTR: Response for %s: %d records", resp.Ip, len(resp.Records))
+	return resp, nil
+}
+
+// GetPTRRange returns the ingested PTR records of every address in a CIDR
+// block, in address order, up to limit records.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetPTRRange(ctx context.Context, req *pb.GetPTRRangeRequest) (*pb.GetPTRRangeResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetPTRRange", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := netip.ParsePrefix(req.Cidr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid CIDR %q", req.Cidr)
+	}
+	prefix = prefix.Masked()
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 {
+		limit = defaultPTRRangeLimit
+	}
+	if limit > maxPTRRangeLimit {
+		limit = maxPTRRangeLimit
+	}
+
+	// One extra row tells whether the result was truncated
+	rows, err := s.shards.ForTLD("arpa").DB.QueryContext(ctx, `
+		SELECT host(ip), ptr_name, ttl, source, zone, last_updated
+		FROM ptr_records
+		WHERE ip <<= $1::cidr
+		ORDER BY ip, ptr_name, source
+		LIMIT $2
+	`, prefix.String(), limit+1)
+	if err != nil {
+		errorf("GetPTRRange: Failed to query PTR records for %s: %v", prefix, err)
+		return nil, status.Errorf(codes.Internal, "failed to query PTR records: %v", err)
+	}
+	defer rows.Close()
+	records, err := scanPTRRecords(rows)
+	if err != nil {
+		errorf("GetPTRRange: Failed to read PTR records for %s: %v", prefix, err)
+		return nil, status.Errorf(codes.Internal, "failed to read PTR records: %v", err)
+	}
+	resp := &pb.GetPTRRangeResponse{Cidr: prefix.String(), Records: records}
+	if len(records) > limit {
+		resp.Records = records[:limit]
+		resp.Truncated = true
+	}
+	infof("GetPTRRange: Response for %s: %d records (truncated=%v)", prefix, len(resp.Records), resp.Truncated)
+	return resp, nil
+}
+
+func scanPTRRecords(rows *sql.Rows) ([]*pb.PTRRecord, error) {
+	var records []*pb.PTRRecord
+	for rows.Next() {
+		var r pb.PTRRecord
+		var ttl sql.NullInt32
+		var lastUpdated time.Time
+		if err := rows.Scan(&r.Ip, &r.PtrName, &ttl, &r.Source, &r.Zone, &lastUpdated); err != nil {
+			return nil, err
+		}
+		r.Ttl = ttl.Int32
+		r.LastUpdated = lastUpdated.Format(time.RFC3339)
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
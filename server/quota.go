@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// quotaCacheTTL bounds how long a key's organization and an organization's
+// stored usage are cached by orgQuota.
+const quotaCacheTTL = 30 * time.Second
+
+// quotaExempt lists RPCs that stay available to an organization or key over
+// its quota, so its members can see where the quota went.
+var quotaExempt = map[string]bool{
+	"GetOrganization":      true,
+	"GetOrganizationUsage": true,
+	"GetUsage":             true,
+}
+
+type keyOrganization struct {
+	id      int // 0 for keys outside any organization
+	name    string
+	quota   int64 // 0 for unlimited
+	expires time.Time
+}
+
+type orgUsage struct {
+	day     string // UTC date, 2006-01-02
+	stored  int64  // Requests in usage_counts for day when last read
+	local   int64  // Requests admitted here since then
+	expires time.Time
+}
+
+// orgQuota enforces the daily request quotas organizations share across
+// their keys. Requests are counted as usageMeter counts them, once per TLD a
+// call names. Usage is read from usage_counts, which every server adds to
+// about once a minute, plus the calls admitted here since the last read, so
+// an organization can go over its quota by about a minute of traffic on
+// other servers.
+type orgQuota struct {
+	db      *sql.DB
+	sandbox *sandboxRouter
+
+	mu   sync.Mutex
+	keys map[string]keyOrganization
+	orgs map[int]*orgUsage
+}
+
+func newOrgQuota(db *sql.DB, sandbox *sandboxRouter) *orgQuota {
+	return &orgQuota{db: db, sandbox: sandbox, keys: make(map[string]keyOrganization), orgs: make(map[int]*orgUsage)}
+}
+
+// unaryInterceptor rejects calls made with a key whose organization has used
+// its quota for the UTC day. It must run before usageMeter's interceptor so
+// that rejected calls are not metered. Calls whose quota cannot be looked up
+// are let through.
+func (q *orgQuota) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := q.admitCall(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls; the quota is
+// checked once the request has been received.
+func (q *orgQuota) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &interceptedStream{ServerStream: ss, recv: func(req interface{}) error {
+		return q.admitCall(ss.Context(), info.FullMethod, req)
+	}})
+}
+
+// admitCall counts a call against its organization's quota, returning a
+// ResourceExhausted error if the quota is used up.
+func (q *orgQuota) admitCall(ctx context.Context, fullMethod string, req interface{}) error {
+	if quotaExempt[path.Base(fullMethod)] {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return nil
+	}
+	if sandbox, err := q.sandbox.sandboxKey(ctx, apiKeys[0]); sandbox || err != nil {
+		return nil
+	}
+	org, err := q.organization(ctx, apiKeys[0])
+	if err != nil {
+		errorf("%s: Failed to look up organization quota: %v", fullMethod, err)
+		return nil
+	}
+	if org.id == 0 || org.quota == 0 {
+		return nil
+	}
+	requests := int64(1)
+	if msg, ok := req.(proto.Message); ok {
+		if n := len(requestTLDs(msg.ProtoReflect())); n > 1 {
+			requests = int64(n)
+		}
+	}
+	ok, err := q.admit(ctx, org, requests)
+	if err != nil {
+		errorf("%s: Failed to read usage of organization %d: %v", fullMethod, org.id, err)
+		return nil
+	}
+	if !ok {
+		now := time.Now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		infof("%s: Organization %d is over its daily quota of %d requests", fullMethod, org.id, org.quota)
+		return statusError(codes.ResourceExhausted, reasonQuotaExceeded, map[string]string{
+			"quota_limit": strconv.FormatInt(org.quota, 10),
+			"retry_after": midnight.Sub(now).Round(time.Second).String(),
+		}, "organization %s has used its daily quota of %d requests", org.name, org.quota)
+	}
+	return nil
+}
+
+// organization returns the organization of apiKey, with id 0 for keys
+// outside any organization.
+func (q *orgQuota) organization(ctx context.Context, apiKey string) (keyOrganization, error) {
+	q.mu.Lock()
+	cached, ok := q.keys[apiKey]
+	q.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached, nil
+	}
+	var org keyOrganization
+	err := q.db.QueryRowContext(ctx, `
+		SELECT o.id, o.name, o.daily_request_quota
+		FROM api_keys k
+		JOIN organizations o ON o.id = k.organization_id
+		WHERE k.api_key = $1
+	`, apiKey).Scan(&org.id, &org.name, &org.quota)
+	if err != nil && err != sql.ErrNoRows {
+		return keyOrganization{}, err
+	}
+	org.expires = time.Now().Add(quotaCacheTTL)
+	q.mu.Lock()
+	q.keys[apiKey] = org
+	q.mu.Unlock()
+	return org, nil
+}
+
+// forget drops the cached organization of apiKey.
+func (q *orgQuota) forget(apiKey string) {
+	q.mu.Lock()
+	delete(q.keys, apiKey)
+	q.mu.Unlock()
+}
+
+// admit adds requests to the organization's usage for today unless that
+// would take it over its quota.
+func (q *orgQuota) admit(ctx context.Context, org keyOrganization, requests int64) (bool, error) {
+	day := time.Now().UTC().Format("2006-01-02")
+	q.mu.Lock()
+	u, ok := q.orgs[org.id]
+	q.mu.Unlock()
+	if !ok || u.day != day || time.Now().After(u.expires) {
+		var stored int64
+		err := q.db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(u.requests), 0)
+			FROM usage_counts u
+			JOIN api_keys k ON k.api_key::text = u.api_key
+			WHERE k.organization_id = $1 AND u.day = $2
+		`, org.id, day).Scan(&stored)
+		if err != nil {
+			return false, err
+		}
+		u = &orgUsage{day: day, stored: stored, expires: time.Now().Add(quotaCacheTTL)}
+		q.mu.Lock()
+		q.orgs[org.id] = u
+		q.mu.Unlock()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if u.stored+u.local+requests > org.quota {
+		return false, nil
+	}
+	u.local += requests
+	return true, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"math"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// tokenBucket holds the tokens left to one API key as of updated.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter enforces rate_limit with a token bucket per API key: a bucket
+// holds up to burst tokens, refills at rate tokens per second, and each call
+// takes one. Buckets live in this server's memory, so behind a load
+// balancer a key gets the rate on each server.
+type rateLimiter struct {
+	rate  float64 // Tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*tokenBucket)}
+}
+
+// unaryInterceptor rejects calls made with a key that has run out of tokens
+// with ResourceExhausted and a retry-after header giving the seconds until
+// the next token. It must run before orgQuota's interceptor so that
+// rejected calls are not counted against the quota. Calls without a key are
+// left to authentication.
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.admitCall(ctx, info.FullMethod, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls; a stream takes
+// one token when it starts.
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.admitCall(ss.Context(), info.FullMethod, ss.SetHeader); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// admitCall takes a token for the call's key, returning a ResourceExhausted
+// error after setting the retry-after header with setHeader if there is none.
+func (l *rateLimiter) admitCall(ctx context.Context, fullMethod string, setHeader func(metadata.MD) error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return nil
+	}
+	wait := l.take(apiKeys[0], time.Now())
+	if wait == 0 {
+		return nil
+	}
+	// Retry-After takes whole seconds
+	seconds := int(math.Ceil(wait.Seconds()))
+	setHeader(metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+	debugf("%s: API key %s is over its rate limit", path.Base(fullMethod), apiKeys[0])
+	return statusError(codes.ResourceExhausted, reasonRateLimited, map[string]string{
+		"rate_limit":  strconv.FormatFloat(l.rate, 'f', -1, 64),
+		"retry_after": (time.Duration(seconds) * time.Second).String(),
+	}, "rate limit of %g requests per second exceeded", l.rate)
+}
+
+// take takes a token from apiKey's bucket, returning 0, or how long until a
+// token is available if the bucket is empty.
+func (l *rateLimiter) take(apiKey string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[apiKey]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[apiKey] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// run drops the buckets that have refilled every interval until ctx is
+// done, so keys seen once, such as mistyped ones, do not accumulate.
+func (l *rateLimiter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.mu.Lock()
+			for apiKey, b := range l.buckets {
+				if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= l.burst {
+					delete(l.buckets, apiKey)
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/moos3/bell/arrowipc"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+const (
+	maxBatchRows  = 65536
+	maxBatchBytes = 2 << 20 // Below gRPC's default 4 MiB receive limit
+)
+
+// Columns of StreamRecordBatches, in schema order.
+const (
+	batchDomain = iota
+	batchRecordType
+	batchRecordData
+	batchTTL
+	batchSource
+	batchFirstSeen
+	batchLastSeen
+)
+
+var recordBatchFields = []arrowipc.Field{
+	batchDomain:     {Name: "domain", Type: arrowipc.Utf8},
+	batchRecordType: {Name: "record_type", Type: arrowipc.Utf8},
+	batchRecordData: {Name: "record_data", Type: arrowipc.Utf8},
+	batchTTL:        {Name: "ttl", Type: arrowipc.Int32, Nullable: true},
+	batchSource:     {Name: "source", Type: arrowipc.Utf8},
+	batchFirstSeen:  {Name: "first_seen", Type: arrowipc.Timestamp, Nullable: true},
+	batchLastSeen:   {Name: "last_seen", Type: arrowipc.Timestamp, Nullable: true},
+}
+
+// StreamRecordBatches sends the records of a domain, or of every domain of
+// a TLD, as an Arrow IPC stream read from the shard's cursor: the schema,
+// then a columnar batch per batch_rows records, or fewer to keep messages
+// within gRPC's default size limit, then the end-of-stream marker.
+// Compressed record data is decoded first. Filters and the key's
+// preferences are those of StreamRecords. Columns the key's role may not
+// see are blanked: empty strings, or nulls.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) StreamRecordBatches(req *pb.StreamRecordBatchesRequest, stream pb.DNSService_StreamRecordBatchesServer) error {
+	ctx := stream.Context()
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "StreamRecordBatches", apiKey)
+	if err != nil {
+		return err
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+	if (domain == "") == (tld == "") {
+		return status.Errorf(codes.InvalidArgument, "exactly one of domain and tld is required")
+	}
+	batchRows := int(req.BatchRows)
+	if batchRows <= 0 || batchRows > maxBatchRows {
+		batchRows = maxBatchRows
+	}
+	recordTypes := req.RecordType
+	if len(recordTypes) == 0 {
+		recordTypes = prefs.recordTypes
+	}
+
+	query := storage.NewQuery(`
+		SELECT d.domain_name, r.record_type, r.record_data, r.record_data_z, r.ttl, r.source, r.first_seen, r.last_updated
+		FROM domains d
+		JOIN dns_records r ON r.domain_id = d.id
+	`)
+	var shard *storage.Shard
+	if domain != "" {
+		if s.domains != nil && !s.domains.mayContain(domain) {
+			// Still a stream Arrow readers open, with no batches
+			debugf("StreamRecordBatches: Domain %s ruled out by filter", domain)
+			if err := stream.Send(&pb.ArrowMessage{Data: arrowipc.SchemaMessage(recordBatchFields)}); err != nil {
+				return err
+			}
+			return stream.Send(&pb.ArrowMessage{Data: arrowipc.EndOfStream()})
+		}
+		shard = s.shards.ForDomain(domain)
+		query.Where("d.domain_name = ?", domain)
+	} else {
+		shard = s.shards.ForTLD(tld)
+		query.WhereTLD("d", tld)
+	}
+	query.WhereVisible("d", prefs.orgID)
+	if len(recordTypes) > 0 {
+		query.WhereIn("r.record_type", recordTypes)
+	}
+	if req.Source != "" {
+		query.Where("r.source = ?", strings.ToUpper(req.Source))
+	}
+	if prefs.maxRows > 0 {
+		query.Append("LIMIT ?", prefs.maxRows)
+	}
+
+	if err := stream.Send(&pb.ArrowMessage{Data: arrowipc.SchemaMessage(recordBatchFields)}); err != nil {
+		return err
+	}
+	batch := arrowipc.NewBatch(recordBatchFields)
+	redacted := redactedColumns(redactedFields(ctx))
+	appendString := func(col int, s string) {
+		if redacted[col] {
+			s = ""
+		}
+		batch.AppendString(col, s)
+	}
+	appendTimestamp := func(col int, t *time.Time) {
+		if redacted[col] {
+			t = nil
+		}
+		batch.AppendTimestamp(col, timeOrZero(t))
+	}
+	send := func() error {
+		msg, err := batch.Message()
+		if err != nil {
+			return err
+		}
+		rows := batch.Len()
+		batch.Reset()
+		return stream.Send(&pb.ArrowMessage{Data: msg, Rows: int64(rows)})
+	}
+
+	rows, err := shard.DB.QueryContext(ctx, query.SQL(), query.Args()...)
+	if err != nil {
+		errorf("StreamRecordBatches: Failed to query records for %s%s: %v", domain, tld, err)
+		return status.Errorf(codes.Internal, "failed to query records: %v", err)
+	}
+	defer rows.Close()
+	sent := 0
+	for rows.Next() {
+		var name, recordType, data, source string
+		var z []byte
+		var ttl *int32
+		var firstSeen, lastUpdated *time.Time
+		if err := rows.Scan(&name, &recordType, &data, &z, &ttl, &source, &firstSeen, &lastUpdated); err != nil {
+			errorf("StreamRecordBatches: Failed to scan record for %s%s: %v", domain, tld, err)
+			return status.Errorf(codes.Internal, "failed to scan record: %v", err)
+		}
+		if data, err = storage.RecordData(data, z); err != nil {
+			errorf("StreamRecordBatches: Failed to decode record for %s%s: %v", domain, tld, err)
+			return status.Errorf(codes.Internal, "failed to decode record: %v", err)
+		}
+		appendString(batchDomain, name)
+		appendString(batchRecordType, recordType)
+		appendString(batchRecordData, data)
+		if ttl != nil && !redacted[batchTTL] {
+			batch.AppendInt32(batchTTL, *ttl, true)
+		} else {
+			batch.AppendInt32(batchTTL, 0, false)
+		}
+		appendString(batchSource, source)
+		appendTimestamp(batchFirstSeen, firstSeen)
+		appendTimestamp(batchLastSeen, lastUpdated)
+		sent++
+		if batch.Len() >= batchRows || batch.Size() >= maxBatchBytes {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errorf("StreamRecordBatches: Failed to iterate records for %s%s: %v", domain, tld, err)
+		return status.Errorf(codes.Internal, "failed to iterate records: %v", err)
+	}
+	if batch.Len() > 0 {
+		if err := send(); err != nil {
+			return err
+		}
+	}
+	if err := stream.Send(&pb.ArrowMessage{Data: arrowipc.EndOfStream()}); err != nil {
+		return err
+	}
+	infof("StreamRecordBatches: Streamed %d records of %s%s for API key %s", sent, domain, tld, apiKey)
+	return nil
+}
+
+// redactedColumns returns which columns of recordBatchFields are blanked
+// for a role redacting fields. Columns match the DNSRecord field they carry:
+// last_seen is DNSRecord's last_updated.
+func redactedColumns(fields map[string]bool) []bool {
+	redacted := make([]bool, len(recordBatchFields))
+	for col, field := range recordBatchFields {
+		redacted[col] = fields[field.Name]
+	}
+	redacted[batchLastSeen] = redacted[batchLastSeen] || fields["last_updated"]
+	return redacted
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
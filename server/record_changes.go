@@ -0,0 +1,110 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/moos3/bell/events"
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// maxSubscribedDomains caps the domains of one SubscribeRecordChanges call.
+const maxSubscribedDomains = 1000
+
+// maxVisibilityCache caps the domains whose visibility a subscription
+// remembers; the cache is emptied when full.
+const maxVisibilityCache = 10000
+
+// SubscribeRecordChanges streams the record_set_changed events of the query
+// worker for the requested domains, or for every domain of a TLD, until the
+// client disconnects. Events reach every API server over Postgres
+// LISTEN/NOTIFY, so subscribers on any server see changes written by any
+// worker. Changes to domains restricted to other organizations are left
+// out. The key's preferences supply record types the request leaves unset.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Changes
+// are live only; nothing observed before the call is replayed, changes are
+// dropped for a client that falls too far behind, and sandbox keys get
+// Unavailable.
+func (s *server) SubscribeRecordChanges(req *pb.SubscribeRecordChangesRequest, stream pb.DNSService_SubscribeRecordChangesServer) error {
+	ctx := stream.Context()
+	apiKey := apiKeyFromContext(ctx)
+	if s.events == nil {
+		return statusError(codes.Unavailable, codeReason(codes.Unavailable), nil, "record change streaming is not available")
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+	if (len(req.Domains) == 0) == (tld == "") {
+		return status.Errorf(codes.InvalidArgument, "exactly one of domains and tld is required")
+	}
+	if len(req.Domains) > maxSubscribedDomains {
+		return statusError(codes.InvalidArgument, reasonTooManyDomains, map[string]string{"quota_limit": strconv.Itoa(maxSubscribedDomains)}, "at most %d domains per subscription", maxSubscribedDomains)
+	}
+	prefs, err := s.keyPreferences(ctx, "SubscribeRecordChanges", apiKey)
+	if err != nil {
+		return err
+	}
+	domains := make(map[string]bool)
+	for _, domain := range req.Domains {
+		domains[strings.ToLower(strings.TrimSuffix(domain, "."))] = true
+	}
+	recordTypes := req.RecordTypes
+	if len(recordTypes) == 0 {
+		recordTypes = prefs.recordTypes
+	}
+	types := make(map[string]bool)
+	for _, rt := range recordTypes {
+		rt = strings.ToUpper(rt)
+		if _, ok := dns.StringToType[rt]; !ok {
+			return statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "unknown record type %q", rt)
+		}
+		types[rt] = true
+	}
+	significances := make(map[string]bool)
+	for _, significance := range req.Significances {
+		significances[strings.ToUpper(significance)] = true
+	}
+
+	visible := make(map[string]bool) // Domain -> visible to the key
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+	if tld != "" {
+		infof("SubscribeRecordChanges: Streaming changes in TLD %s for API key %s", tld, apiKey)
+	} else {
+		infof("SubscribeRecordChanges: Streaming changes to %d domains for API key %s", len(domains), apiKey)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-ch:
+			if e.Kind != events.RecordSetChanged || (tld != "" && e.Tld != tld) || (len(domains) > 0 && !domains[e.Domain]) ||
+				(len(types) > 0 && !types[e.RecordType]) || (len(significances) > 0 && !significances[e.Significance]) {
+				continue
+			}
+			shown, ok := visible[e.Domain]
+			if !ok {
+				if shown, err = s.domainVisible(ctx, "SubscribeRecordChanges", prefs, e.Domain); err != nil {
+					return err
+				}
+				if len(visible) >= maxVisibilityCache {
+					clear(visible)
+				}
+				visible[e.Domain] = shown
+			}
+			if !shown {
+				continue
+			}
+			if err := stream.Send(&pb.RecordChange{
+				Time: e.Time, Domain: e.Domain, Tld: e.Tld, RecordType: e.RecordType,
+				Version: e.Version, Significance: e.Significance,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
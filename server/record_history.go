@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// maxRecordHistoryEntries caps, and is the default of, the entries of one
+// GetRecordHistory response.
+const maxRecordHistoryEntries = 1000
+
+// GetRecordHistory returns the values a record type of a domain has had,
+// oldest first: each distinct record stored in dns_records, dated from its
+// first to its latest observation, and the versions of those records that
+// later observations replaced with another TTL or formatting (record_history).
+// A record is current if its source's latest observation of the type saw it;
+// the others were removed from the record set at some point after they were
+// last seen. Domains restricted to other organizations have no history.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). The key's
+// max_rows preference is the default limit.
+func (s *server) GetRecordHistory(ctx context.Context, req *pb.GetRecordHistoryRequest) (*pb.GetRecordHistoryResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetRecordHistory", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	if domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "domain is required")
+	}
+	recordType := strings.ToUpper(req.RecordType)
+	if recordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "record_type is required")
+	}
+	if _, ok := dns.StringToType[recordType]; !ok {
+		return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": req.RecordType}, "unknown record type %q", req.RecordType)
+	}
+	source := strings.ToUpper(req.Source)
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 || limit > maxRecordHistoryEntries {
+		limit = maxRecordHistoryEntries
+	}
+
+	resp := &pb.GetRecordHistoryResponse{Domain: domain, RecordType: recordType}
+	if s.domains != nil && !s.domains.mayContain(domain) {
+		debugf("GetRecordHistory: Domain %s ruled out by filter", domain)
+		return resp, nil
+	}
+	visible, err := s.domainVisible(ctx, "GetRecordHistory", prefs, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return resp, nil
+	}
+
+	// Stored records date from the last time one of their versions was
+	// replaced. The newest entries are read, with one extra row telling
+	// whether older ones were left out.
+	rows, err := s.shards.ForDomain(domain).Reader(ctx).QueryContext(ctx, `
+		SELECT record_data, record_data_z, ttl, source, first_seen, last_seen, superseded
+		FROM (
+			SELECT r.record_data, r.record_data_z, r.ttl, r.source,
+				COALESCE((
+					SELECT MAX(h.replaced_at) FROM record_history h
+					WHERE h.domain_id = r.domain_id AND h.record_type = r.record_type
+						AND h.source = r.source AND h.canonical_hash = r.canonical_hash
+				), r.first_seen) AS first_seen,
+				r.last_updated AS last_seen, FALSE AS superseded
+			FROM domains d JOIN dns_records r ON r.domain_id = d.id
+			WHERE d.domain_name = $1 AND r.record_type = $2 AND ($3 = '' OR r.source = $3)
+			UNION ALL
+			SELECT h.record_data, h.record_data_z, h.ttl, h.source, h.first_seen, h.last_seen, TRUE
+			FROM domains d JOIN record_history h ON h.domain_id = d.id
+			WHERE d.domain_name = $1 AND h.record_type = $2 AND ($3 = '' OR h.source = $3)
+		) history
+		ORDER BY last_seen DESC NULLS LAST, first_seen DESC
+		LIMIT $4
+	`, domain, recordType, source, limit+1)
+	if err != nil {
+		errorf("GetRecordHistory: Failed to query %s history of domain %s: %v", recordType, domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query record history: %v", err)
+	}
+	defer rows.Close()
+
+	type entry struct {
+		e        *pb.RecordHistoryEntry
+		lastSeen time.Time
+	}
+	var entries []entry
+	latest := make(map[string]time.Time) // Source -> latest observation of the type
+	for rows.Next() {
+		e := &pb.RecordHistoryEntry{}
+		var data string
+		var z []byte
+		var ttl sql.NullInt32
+		var firstSeen time.Time
+		var lastSeen sql.NullTime
+		if err := rows.Scan(&data, &z, &ttl, &e.Source, &firstSeen, &lastSeen, &e.Superseded); err != nil {
+			errorf("GetRecordHistory: Failed to scan %s history of domain %s: %v", recordType, domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan record history: %v", err)
+		}
+		if e.RecordData, err = storage.RecordData(data, z); err != nil {
+			errorf("GetRecordHistory: Failed to decode %s history of domain %s: %v", recordType, domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to decode record history: %v", err)
+		}
+		e.Ttl = ttl.Int32
+		e.FirstSeen = firstSeen.Format(time.RFC3339)
+		if lastSeen.Valid {
+			e.LastSeen = lastSeen.Time.Format(time.RFC3339)
+			if !e.Superseded && lastSeen.Time.After(latest[e.Source]) {
+				latest[e.Source] = lastSeen.Time
+			}
+		}
+		entries = append(entries, entry{e, lastSeen.Time})
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetRecordHistory: Failed to iterate %s history of domain %s: %v", recordType, domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate record history: %v", err)
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		resp.Truncated = true
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		e.e.Current = !e.e.Superseded && !e.lastSeen.IsZero() && e.lastSeen.Equal(latest[e.e.Source])
+		resp.Entries = append(resp.Entries, e.e)
+	}
+	infof("GetRecordHistory: Response for %s records of domain %s: %d entries", recordType, domain, len(resp.Entries))
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// maxBatchDomains caps the number of domains in one GetRecordsBatch request.
+const maxBatchDomains = 500
+
+// GetRecordsBatch returns the records of each requested domain, like
+// GetRecords without merging, version checks or DGA scores. Domains are
+// grouped by shard and each shard is queried once for all of its domains.
+// Domains restricted to other organizations have no records. The key's
+// preferences supply record types the request leaves unset and cap the
+// number of records returned per domain.
+//
+// Requested domains holding * or ? are patterns, expanded to the stored
+// domains they match before the records are read (see expandPatterns);
+// the response lists what each expanded to and whether it was truncated.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetRecordsBatch(ctx context.Context, req *pb.GetRecordsBatchRequest) (*pb.GetRecordsBatchResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if len(req.Domains) > maxBatchDomains {
+		return nil, statusError(codes.InvalidArgument, reasonTooManyDomains, map[string]string{"quota_limit": strconv.Itoa(maxBatchDomains)}, "at most %d domains per request", maxBatchDomains)
+	}
+	prefs, err := s.keyPreferences(ctx, "GetRecordsBatch", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	recordTypes := req.RecordType
+	if len(recordTypes) == 0 {
+		recordTypes = prefs.recordTypes
+	}
+	cutoff, snapshotToken, err := snapshotCutoff(req.SnapshotToken)
+	if err != nil {
+		return nil, statusError(codes.InvalidArgument, reasonInvalidSnapshot, nil, "%v", err)
+	}
+
+	maxExpansion := s.wildcardMaxExpansion
+	if req.MaxExpansion > 0 && int(req.MaxExpansion) < maxExpansion {
+		maxExpansion = int(req.MaxExpansion)
+	}
+	requested, expansions, err := s.expandPatterns(ctx, "GetRecordsBatch", prefs, req.Domains, maxExpansion)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetRecordsBatchResponse{Results: make(map[string]*pb.DomainRecords), SnapshotToken: snapshotToken, Expansions: expansions}
+	byShard := make(map[*storage.Shard][]string)
+	skipped := 0
+	for _, domain := range requested {
+		if _, ok := resp.Results[domain]; ok {
+			continue
+		}
+		resp.Results[domain] = &pb.DomainRecords{}
+		if s.domains != nil && !s.domains.mayContain(domain) {
+			skipped++
+			continue
+		}
+		shard := s.shards.ForDomain(domain)
+		byShard[shard] = append(byShard[shard], domain)
+	}
+
+	total := 0
+	for shard, domains := range byShard {
+		query := storage.NewQuery("SELECT d.domain_name, "+observedRecordColumns+observedRecordsFrom, cutoff).
+			WhereIn("d.domain_name", domains).WhereVisible("d", prefs.orgID).Where("r.inserted_at <= ?", cutoff)
+		if len(recordTypes) > 0 {
+			query.WhereIn("r.record_type", recordTypes)
+		}
+		if req.Order != pb.RecordOrder_RECORD_ORDER_STORAGE {
+			query.Append(semanticOrder)
+		}
+		rows, err := shard.Reader(ctx).QueryContext(ctx, query.SQL(), query.Args()...)
+		if err != nil {
+			errorf("GetRecordsBatch: Failed to query records of %d domains on shard %s: %v", len(domains), shard.Name, err)
+			return nil, status.Errorf(codes.Internal, "failed to query records: %v", err)
+		}
+		for rows.Next() {
+			var domain string
+			r, err := scanObservedRecord(rows, &domain)
+			if err != nil {
+				rows.Close()
+				errorf("GetRecordsBatch: Failed to scan record on shard %s: %v", shard.Name, err)
+				return nil, status.Errorf(codes.Internal, "failed to scan record: %v", err)
+			}
+			result, ok := resp.Results[domain]
+			if !ok {
+				continue
+			}
+			if prefs.maxRows > 0 && len(result.Records) >= prefs.maxRows {
+				result.Truncated = true
+				continue
+			}
+			result.Records = append(result.Records, r)
+			total++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			errorf("GetRecordsBatch: Failed to iterate records on shard %s: %v", shard.Name, err)
+			return nil, status.Errorf(codes.Internal, "failed to iterate records: %v", err)
+		}
+	}
+	infof("GetRecordsBatch: Response for %d domains (%d patterns): %d records, %d domains ruled out by filter", len(resp.Results), len(expansions), total, skipped)
+	return resp, nil
+}
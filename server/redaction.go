@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// roleCacheTTL bounds how long a key's role is cached by the redactor.
+const roleCacheTTL = time.Minute
+
+// redactor clears response fields that the caller's key role may not see.
+//
+// Rules map an api_keys.role to proto field names (e.g. "source",
+// "domain_id"); a named field is cleared wherever it appears in a response,
+// including nested and repeated messages. A rule also clears the fields
+// that carry the same data under another name (redactionAliases), so that
+// "source" hides DNSRecord.sources and the merge provenance's
+// chosen_source and discarded_sources too. Arrow record batches carry their
+// rows as encoded bytes, so StreamRecordBatches blanks the named columns as
+// it builds them, from the fields the stream interceptor puts in the
+// context (see redactedFields).
+type redactor struct {
+	db    *sql.DB
+	rules map[string]map[string]bool // Role -> field names to clear
+
+	mu    sync.Mutex
+	roles map[string]cachedRole // API key -> role
+}
+
+type cachedRole struct {
+	role    string
+	expires time.Time
+}
+
+// redactionAliases are the fields a rule naming a field clears with it.
+var redactionAliases = map[string][]string{
+	"source": {"sources", "chosen_source", "discarded_sources"},
+}
+
+func newRedactor(db *sql.DB, rules map[string][]string) *redactor {
+	r := &redactor{db: db, rules: make(map[string]map[string]bool), roles: make(map[string]cachedRole)}
+	for role, fields := range rules {
+		r.rules[role] = make(map[string]bool)
+		for _, field := range fields {
+			r.rules[role][field] = true
+			for _, alias := range redactionAliases[field] {
+				r.rules[role][alias] = true
+			}
+		}
+	}
+	return r
+}
+
+// role returns the role of apiKey, or "" if the key is unknown.
+func (r *redactor) role(ctx context.Context, apiKey string) (string, error) {
+	r.mu.Lock()
+	cached, ok := r.roles[apiKey]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.role, nil
+	}
+	var role string
+	err := r.db.QueryRowContext(ctx, "SELECT role FROM api_keys WHERE api_key = $1", apiKey).Scan(&role)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	r.mu.Lock()
+	r.roles[apiKey] = cachedRole{role: role, expires: time.Now().Add(roleCacheTTL)}
+	r.mu.Unlock()
+	return role, nil
+}
+
+// forget drops the cached role of apiKey.
+func (r *redactor) forget(apiKey string) {
+	r.mu.Lock()
+	delete(r.roles, apiKey)
+	r.mu.Unlock()
+}
+
+// unaryInterceptor redacts successful responses according to the caller's role.
+// Requests without an API key are rejected by the authenticator.
+func (r *redactor) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if msg, ok := resp.(proto.Message); ok {
+		if err := r.redact(ctx, info.FullMethod, msg); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls; each response
+// is redacted before it is sent. The handler's context carries the fields
+// to redact for responses the interceptor cannot redact itself.
+func (r *redactor) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	fields, err := r.fields(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	ctx := context.WithValue(ss.Context(), redactedFieldsKey{}, fields)
+	return handler(srv, &interceptedStream{ServerStream: &keyedStream{ServerStream: ss, ctx: ctx}, send: func(resp proto.Message) error {
+		redactMessage(resp, fields)
+		return nil
+	}})
+}
+
+// redactedFieldsKey is the context key of the fields a stream's responses
+// are redacted of.
+type redactedFieldsKey struct{}
+
+// redactedFields returns the field names the caller's role may not see, as
+// looked up by the stream interceptor, or nil.
+func redactedFields(ctx context.Context) map[string]bool {
+	fields, _ := ctx.Value(redactedFieldsKey{}).(map[string]bool)
+	return fields
+}
+
+// redact clears the fields of resp the caller's role may not see.
+func (r *redactor) redact(ctx context.Context, fullMethod string, resp proto.Message) error {
+	fields, err := r.fields(ctx, fullMethod)
+	if err != nil {
+		return err
+	}
+	redactMessage(resp, fields)
+	return nil
+}
+
+// fields returns the field names the caller's role may not see, or nil.
+func (r *redactor) fields(ctx context.Context, fullMethod string) (map[string]bool, error) {
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return nil, nil
+	}
+	role, err := r.role(ctx, apiKeys[0])
+	if err != nil {
+		errorf("%s: Failed to look up key role for redaction: %v", fullMethod, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up key role: %v", err)
+	}
+	return r.rules[role], nil
+}
+
+// redactMessage clears the fields of resp named in fields. Arrow messages
+// are left alone: their columns were blanked when the batch was built (see
+// redactedColumns), and their own fields hold the encoded stream.
+func redactMessage(resp proto.Message, fields map[string]bool) {
+	m := resp.ProtoReflect()
+	if len(fields) == 0 || m.Descriptor().FullName() == arrowMessageName {
+		return
+	}
+	redactFields(m, fields)
+}
+
+// redactFields clears every field of m named in fields, recursing into
+// message, repeated message, and map-of-message fields.
+func redactFields(m protoreflect.Message, fields map[string]bool) {
+	var cleared []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fields[string(fd.Name())] {
+			cleared = append(cleared, fd)
+			return true
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redactFields(list.Get(i).Message(), fields)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				redactFields(mv.Message(), fields)
+				return true
+			})
+		case fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			redactFields(v.Message(), fields)
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		m.Clear(fd)
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+func TestRedactClearsNestedFields(t *testing.T) {
+	r := newRedactor(nil, map[string][]string{"partner": {"domain_id", "ttl"}})
+	// Cached, so no database is needed
+	r.roles["partner-key"] = cachedRole{role: "partner", expires: time.Now().Add(time.Hour)}
+	r.roles["internal-key"] = cachedRole{role: "internal", expires: time.Now().Add(time.Hour)}
+
+	response := func() *pb.GetRecordsResponse {
+		return &pb.GetRecordsResponse{Records: []*pb.DNSRecord{
+			{DomainId: 7, RecordType: "A", RecordData: "192.0.2.1", Ttl: 300, Source: "QUERY"},
+			{DomainId: 7, RecordType: "NS", RecordData: "ns1.dns-host-a.example.", Ttl: 86400, Source: "CZDS"},
+		}}
+	}
+	ctx := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", key))
+	}
+
+	resp := response()
+	if err := r.redact(ctx("partner-key"), pb.DNSService_GetRecords_FullMethodName, resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range resp.Records {
+		if rec.DomainId != 0 || rec.Ttl != 0 {
+			t.Errorf("domain_id and ttl of %s record not cleared: %v", rec.RecordType, rec)
+		}
+		if rec.RecordData == "" || rec.Source == "" {
+			t.Errorf("fields outside the rule cleared: %v", rec)
+		}
+	}
+
+	resp = response()
+	if err := r.redact(ctx("internal-key"), pb.DNSService_GetRecords_FullMethodName, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Records[0].DomainId != 7 || resp.Records[0].Ttl != 300 {
+		t.Errorf("fields cleared for a role without rules: %v", resp.Records[0])
+	}
+}
+
+func TestRedactSourceClearsSourceFields(t *testing.T) {
+	resp := &pb.GetRecordsResponse{
+		Records: []*pb.DNSRecord{{RecordType: "A", RecordData: "192.0.2.1", Source: "QUERY", Sources: []string{"CZDS", "QUERY"}}},
+		Provenance: []*pb.MergeProvenance{{RecordType: "A", ChosenSource: "QUERY", Reason: "precedence",
+			DiscardedSources: []string{"CZDS"}, DiscardedRecords: 1}},
+	}
+	redactMessage(resp, newRedactor(nil, map[string][]string{"partner": {"source"}}).rules["partner"])
+	rec, prov := resp.Records[0], resp.Provenance[0]
+	if rec.Source != "" || len(rec.Sources) != 0 {
+		t.Errorf("record sources not cleared: %v", rec)
+	}
+	if prov.ChosenSource != "" || len(prov.DiscardedSources) != 0 {
+		t.Errorf("provenance sources not cleared: %v", prov)
+	}
+	if rec.RecordData == "" || prov.Reason == "" || prov.DiscardedRecords != 1 {
+		t.Errorf("fields outside the rule cleared: %v, %v", rec, prov)
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/resolver"
+)
+
+// RefreshDomain resolves a domain of the corpus at its authoritative
+// nameservers, one query per record type, and stores each answer in
+// geo_answers under the client subnet sent as EDNS Client Subnet, replacing
+// the previous answer for that subnet. The ECS scope of the answer is stored
+// with it, so GetRecords can show how a GeoDNS provider splits its clients.
+// The answers bypass the resolver cache and are kept apart from dns_records,
+// whose record sets the query worker maintains. Domains restricted to other
+// organizations are not found.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Failed
+// queries are reported in errors and leave the stored answer as it was.
+func (s *server) RefreshDomain(ctx context.Context, req *pb.RefreshDomainRequest) (*pb.RefreshDomainResponse, error) {
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	if _, ok := dns.IsDomainName(domain); !ok || domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain %q", req.Domain)
+	}
+	requested := req.RecordType
+	if len(requested) == 0 {
+		requested = liveRecordTypes
+	}
+	if len(requested) > maxLiveRecordTypes {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d record types per refresh", maxLiveRecordTypes)
+	}
+	var qtypes []uint16
+	for _, rt := range requested {
+		qtype, ok := dns.StringToType[strings.ToUpper(rt)]
+		if !ok {
+			return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "unknown record type %q", rt)
+		}
+		qtypes = append(qtypes, qtype)
+	}
+	lookup := resolver.LookupOptions{NoCache: true}
+	clientSubnet := ""
+	if req.ClientSubnet != "" {
+		subnet, err := resolver.ParseClientSubnet(req.ClientSubnet)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		lookup.ClientSubnet, clientSubnet = subnet, subnet.String()
+	}
+
+	prefs, err := s.keyPreferences(ctx, "RefreshDomain", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// The answers are written, so the domain is looked up on the primary.
+	// Domains restricted to other organizations are not found.
+	db := s.shards.ForDomain(domain).DB
+	var domainID int
+	err = db.QueryRowContext(ctx, "SELECT id FROM domains WHERE domain_name = $1 AND (restricted_to IS NULL OR $2 = ANY(restricted_to))", domain, prefs.orgID).Scan(&domainID)
+	if err == sql.ErrNoRows {
+		return nil, statusError(codes.NotFound, reasonDomainNotFound, map[string]string{"domain": domain}, "domain %s not found", domain)
+	}
+	if err != nil {
+		errorf("RefreshDomain: Failed to look up domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up domain: %v", err)
+	}
+	nameservers, err := s.discoverNameservers(ctx, domain)
+	if err != nil {
+		errorf("RefreshDomain: Failed to discover nameservers for %s: %v", domain, err)
+		return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"domain": domain}, "failed to discover nameservers: %v", err)
+	}
+
+	answers := make([]*pb.LiveAnswer, len(qtypes))
+	var wg sync.WaitGroup
+	for i, qtype := range qtypes {
+		wg.Add(1)
+		go func(i int, qtype uint16) {
+			defer wg.Done()
+			answers[i] = s.lookupLive(ctx, domain, qtype, false, lookup, nameservers)
+		}(i, qtype)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	resp := &pb.RefreshDomainResponse{Domain: domain, Nameservers: nameservers}
+	queriedAt := time.Now().UTC()
+	for _, a := range answers {
+		if a.Error != "" {
+			resp.Errors = append(resp.Errors, a.RecordType+": "+a.Error)
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO geo_answers (domain_id, record_type, client_subnet, scope, rcode, answer, server, queried_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (domain_id, record_type, client_subnet) DO UPDATE
+			SET scope = EXCLUDED.scope, rcode = EXCLUDED.rcode, answer = EXCLUDED.answer,
+				server = EXCLUDED.server, queried_at = EXCLUDED.queried_at
+		`, domainID, a.RecordType, clientSubnet, a.ClientSubnetScope, a.Rcode, pq.Array(a.Answer), a.Server, queriedAt); err != nil {
+			errorf("RefreshDomain: Failed to store %s answer for %s: %v", a.RecordType, domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to store answer: %v", err)
+		}
+		resp.Answers = append(resp.Answers, &pb.GeoAnswer{
+			RecordType:   a.RecordType,
+			ClientSubnet: clientSubnet,
+			Scope:        a.ClientSubnetScope,
+			Rcode:        a.Rcode,
+			Answer:       a.Answer,
+			Server:       a.Server,
+			QueriedAt:    queriedAt.Format(time.RFC3339),
+		})
+	}
+	infof("RefreshDomain: Stored %d answers for %s (client subnet %q, %d failed)", len(resp.Answers), domain, clientSubnet, len(resp.Errors))
+	return resp, nil
+}
+
+// geoAnswers returns the answers RefreshDomain stored for domain, optionally
+// only those of recordTypes, by record type and client subnet.
+func geoAnswers(ctx context.Context, db *sql.DB, domain string, recordTypes []string) ([]*pb.GeoAnswer, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT g.record_type, g.client_subnet, g.scope, g.rcode, g.answer, g.server, g.queried_at
+		FROM geo_answers g
+		JOIN domains d ON d.id = g.domain_id
+		WHERE d.domain_name = $1 AND (cardinality($2::text[]) = 0 OR g.record_type = ANY($2))
+		ORDER BY g.record_type, g.client_subnet
+	`, domain, pq.Array(recordTypes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var answers []*pb.GeoAnswer
+	for rows.Next() {
+		var a pb.GeoAnswer
+		var queriedAt time.Time
+		if err := rows.Scan(&a.RecordType, &a.ClientSubnet, &a.Scope, &a.Rcode, pq.Array(&a.Answer), &a.Server, &queriedAt); err != nil {
+			return nil, err
+		}
+		a.QueriedAt = queriedAt.Format(time.RFC3339)
+		answers = append(answers, &a)
+	}
+	return answers, rows.Err()
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// Values accepted in ReportSchedule fields; the report worker renders and
+// delivers each of them.
+var (
+	reportKinds       = map[string]bool{"NEW_DOMAINS": true, "EMAIL_POSTURE": true, "COVERAGE": true}
+	reportFrequencies = map[string]bool{"DAILY": true, "WEEKLY": true}
+	reportFormats     = map[string]bool{"CSV": true, "HTML": true, "PDF": true}
+	reportDeliveries  = map[string]bool{"EMAIL": true, "STORAGE": true}
+)
+
+// Report schedule limits per API key.
+const (
+	maxReportSchedules = 20
+	maxWatchlistTerms  = 100
+)
+
+// CreateReportSchedule stores a recurring report for the caller's API key.
+// The first report covers the period that ended most recently.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Sandbox
+// keys cannot schedule reports.
+func (s *server) CreateReportSchedule(ctx context.Context, req *pb.CreateReportScheduleRequest) (*pb.ReportSchedule, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if s.sandbox {
+		return nil, statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "report schedules are not available in the sandbox")
+	}
+	in := req.Schedule
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "schedule is required")
+	}
+	sched := &pb.ReportSchedule{
+		Report:      strings.ToUpper(in.Report),
+		Frequency:   strings.ToUpper(in.Frequency),
+		Format:      strings.ToUpper(in.Format),
+		Delivery:    strings.ToUpper(in.Delivery),
+		Destination: strings.TrimSpace(in.Destination),
+		Watchlist:   []string{},
+		Tlds:        []string{},
+	}
+	if !reportKinds[sched.Report] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported report %q; must be NEW_DOMAINS, EMAIL_POSTURE or COVERAGE", in.Report)
+	}
+	if !reportFrequencies[sched.Frequency] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported frequency %q; must be DAILY or WEEKLY", in.Frequency)
+	}
+	if !reportFormats[sched.Format] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format %q; must be CSV, HTML or PDF", in.Format)
+	}
+	if !reportDeliveries[sched.Delivery] {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported delivery %q; must be EMAIL or STORAGE", in.Delivery)
+	}
+	if sched.Delivery == "EMAIL" {
+		if _, err := mail.ParseAddress(sched.Destination); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid destination email address %q", in.Destination)
+		}
+	} else {
+		sched.Destination = ""
+	}
+	for _, term := range in.Watchlist {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			sched.Watchlist = append(sched.Watchlist, term)
+		}
+	}
+	if len(sched.Watchlist) > maxWatchlistTerms {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d watchlist terms", maxWatchlistTerms)
+	}
+	for _, tld := range in.Tlds {
+		if tld = strings.ToLower(strings.Trim(tld, ". ")); tld != "" {
+			sched.Tlds = append(sched.Tlds, tld)
+		}
+	}
+	if sched.Report == "NEW_DOMAINS" && len(sched.Watchlist) == 0 {
+		// The report worker adds the organization's watchlist to the key's own
+		var orgTerms int
+		err := s.keys.QueryRowContext(ctx, `
+			SELECT COALESCE(cardinality(o.watchlist), 0)
+			FROM api_keys k
+			LEFT JOIN organizations o ON o.id = k.organization_id
+			WHERE k.api_key = $1
+		`, apiKey).Scan(&orgTerms)
+		if err != nil {
+			errorf("CreateReportSchedule: Failed to look up organization watchlist for API key %s: %v", apiKey, err)
+			return nil, status.Errorf(codes.Internal, "failed to look up organization watchlist: %v", err)
+		}
+		if orgTerms == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "NEW_DOMAINS reports need a watchlist, or an organization watchlist")
+		}
+	}
+	if sched.Report == "EMAIL_POSTURE" && len(sched.Watchlist) == 0 && len(sched.Tlds) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "EMAIL_POSTURE reports need a watchlist or TLDs")
+	}
+
+	var count int
+	if err := s.keys.QueryRowContext(ctx, "SELECT COUNT(*) FROM report_schedules WHERE api_key = $1", apiKey).Scan(&count); err != nil {
+		errorf("CreateReportSchedule: Failed to count schedules for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to count report schedules: %v", err)
+	}
+	if count >= maxReportSchedules {
+		return nil, status.Errorf(codes.ResourceExhausted, "at most %d report schedules per key", maxReportSchedules)
+	}
+	var createdAt time.Time
+	err := s.keys.QueryRowContext(ctx, `
+		INSERT INTO report_schedules (api_key, report, frequency, format, delivery, destination, watchlist, tlds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, apiKey, sched.Report, sched.Frequency, sched.Format, sched.Delivery, sched.Destination,
+		pq.Array(sched.Watchlist), pq.Array(sched.Tlds)).Scan(&sched.Id, &createdAt)
+	if err != nil {
+		errorf("CreateReportSchedule: Failed to store schedule for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to store report schedule: %v", err)
+	}
+	sched.CreatedAt = createdAt.Format(time.RFC3339)
+	infof("CreateReportSchedule: Created %s %s report %d for API key %s", sched.Frequency, sched.Report, sched.Id, apiKey)
+	return sched, nil
+}
+
+// ListReportSchedules returns the report schedules of the caller's API key,
+// oldest first.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListReportSchedules(ctx context.Context, req *pb.ListReportSchedulesRequest) (*pb.ListReportSchedulesResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	resp := &pb.ListReportSchedulesResponse{}
+	if s.sandbox {
+		return resp, nil
+	}
+	rows, err := s.keys.QueryContext(ctx, `
+		SELECT id, report, frequency, format, delivery, destination, watchlist, tlds,
+			last_period_end, COALESCE(last_status, ''), COALESCE(last_error, ''), created_at
+		FROM report_schedules
+		WHERE api_key = $1
+		ORDER BY id
+	`, apiKey)
+	if err != nil {
+		errorf("ListReportSchedules: Failed to query schedules for API key %s: %v", apiKey, err)
+		return nil, status.Errorf(codes.Internal, "failed to query report schedules: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sched pb.ReportSchedule
+		var lastPeriodEnd sql.NullTime
+		var createdAt time.Time
+		if err := rows.Scan(&sched.Id, &sched.Report, &sched.Frequency, &sched.Format, &sched.Delivery, &sched.Destination,
+			pq.Array(&sched.Watchlist), pq.Array(&sched.Tlds), &lastPeriodEnd, &sched.LastStatus, &sched.LastError, &createdAt); err != nil {
+			errorf("ListReportSchedules: Failed to scan schedule: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan report schedule: %v", err)
+		}
+		if lastPeriodEnd.Valid {
+			sched.LastPeriodEnd = lastPeriodEnd.Time.Format(time.RFC3339)
+		}
+		sched.CreatedAt = createdAt.Format(time.RFC3339)
+		resp.Schedules = append(resp.Schedules, &sched)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListReportSchedules: Failed to iterate schedules: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate report schedules: %v", err)
+	}
+	return resp, nil
+}
+
+// DeleteReportSchedule removes a report schedule of the caller's API key.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"); a key can
+// only delete its own schedules.
+func (s *server) DeleteReportSchedule(ctx context.Context, req *pb.DeleteReportScheduleRequest) (*pb.DeleteReportScheduleResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	if s.sandbox {
+		return nil, status.Errorf(codes.NotFound, "report schedule %d not found", req.Id)
+	}
+	result, err := s.keys.ExecContext(ctx, "DELETE FROM report_schedules WHERE id = $1 AND api_key = $2", req.Id, apiKey)
+	if err != nil {
+		errorf("DeleteReportSchedule: Failed to delete schedule %d: %v", req.Id, err)
+		return nil, status.Errorf(codes.Internal, "failed to delete report schedule: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.NotFound, "report schedule %d not found", req.Id)
+	}
+	infof("DeleteReportSchedule: Deleted report %d for API key %s", req.Id, apiKey)
+	return &pb.DeleteReportScheduleResponse{}, nil
+}
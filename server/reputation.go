@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// ListNameserverReputation returns the query worker's reliability stats per
+// nameserver host, least reliable first.
+//
+// It requires an API key with the admin scope. The RPC is not
+// exposed through the gateway.
+func (s *server) ListNameserverReputation(ctx context.Context, req *pb.ListNameserverReputationRequest) (*pb.ListNameserverReputationResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT host, score, queries, timeouts, failures, consecutive_timeouts, avg_rtt_ms, last_success, last_failure, skip_until
+		FROM nameserver_reputation
+		WHERE ($1 = '' OR host = $1 OR host LIKE '%.' || $1)
+		AND (NOT $2 OR skip_until > NOW())
+		ORDER BY score, timeouts DESC
+		LIMIT $3
+	`, req.Host, req.SkippedOnly, limit)
+	if err != nil {
+		errorf("ListNameserverReputation: Failed to query reputation: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query nameserver reputation: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.ListNameserverReputationResponse{}
+	for rows.Next() {
+		var ns pb.NameserverReputation
+		var lastSuccess, lastFailure, skipUntil sql.NullTime
+		if err := rows.Scan(&ns.Host, &ns.Score, &ns.Queries, &ns.Timeouts, &ns.Failures, &ns.ConsecutiveTimeouts,
+			&ns.AvgRttMs, &lastSuccess, &lastFailure, &skipUntil); err != nil {
+			errorf("ListNameserverReputation: Failed to scan reputation: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan nameserver reputation: %v", err)
+		}
+		if lastSuccess.Valid {
+			ns.LastSuccess = lastSuccess.Time.Format(time.RFC3339)
+		}
+		if lastFailure.Valid {
+			ns.LastFailure = lastFailure.Time.Format(time.RFC3339)
+		}
+		if skipUntil.Valid && skipUntil.Time.After(time.Now()) {
+			ns.SkipUntil = skipUntil.Time.Format(time.RFC3339)
+		}
+		resp.Nameservers = append(resp.Nameservers, &ns)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListNameserverReputation: Failed to iterate reputation: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate nameserver reputation: %v", err)
+	}
+	infof("ListNameserverReputation: Response: %d nameservers", len(resp.Nameservers))
+	return resp, nil
+}
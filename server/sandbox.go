@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// sandboxCacheTTL bounds how long a key's sandbox flag is cached.
+const sandboxCacheTTL = time.Minute
+
+// sandboxHeader is set on responses served from the sandbox dataset.
+const sandboxHeader = "x-bell-sandbox"
+
+// sandboxRouter sends the DNSService calls of sandbox keys (api_keys.sandbox)
+// to a server backed by the synthetic sandbox database, so integrators can
+// develop against the API without seeing production data. Sandbox calls are
+// not metered for billing.
+type sandboxRouter struct {
+	db  *sql.DB // Default database holding api_keys
+	srv *server // Serves sandbox keys; nil if sandbox.database is not configured
+
+	mu    sync.Mutex
+	flags map[string]cachedSandboxFlag // API key -> sandbox flag
+}
+
+type cachedSandboxFlag struct {
+	sandbox bool
+	expires time.Time
+}
+
+func newSandboxRouter(db *sql.DB) *sandboxRouter {
+	return &sandboxRouter{db: db, flags: make(map[string]cachedSandboxFlag)}
+}
+
+// sandboxKey reports whether apiKey is a sandbox key. Unknown keys are not.
+func (r *sandboxRouter) sandboxKey(ctx context.Context, apiKey string) (bool, error) {
+	r.mu.Lock()
+	cached, ok := r.flags[apiKey]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.sandbox, nil
+	}
+	var sandbox bool
+	err := r.db.QueryRowContext(ctx, "SELECT sandbox FROM api_keys WHERE api_key = $1", apiKey).Scan(&sandbox)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	r.mu.Lock()
+	r.flags[apiKey] = cachedSandboxFlag{sandbox: sandbox, expires: time.Now().Add(sandboxCacheTTL)}
+	r.mu.Unlock()
+	return sandbox, nil
+}
+
+// forget drops the cached sandbox flag of apiKey.
+func (r *sandboxRouter) forget(apiKey string) {
+	r.mu.Lock()
+	delete(r.flags, apiKey)
+	r.mu.Unlock()
+}
+
+// unaryInterceptor serves DNSService calls made with a sandbox key from the
+// sandbox server instead of handler. It must be the innermost interceptor so
+// that redaction and key preferences still apply to sandbox responses.
+func (r *sandboxRouter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method, sandbox, err := r.route(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if !sandbox {
+		return handler(ctx, req)
+	}
+	for _, desc := range pb.DNSService_ServiceDesc.Methods {
+		if desc.MethodName != method {
+			continue
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(sandboxHeader, "true"))
+		return desc.Handler(r.srv, ctx, func(in interface{}) error {
+			proto.Merge(in.(proto.Message), req.(proto.Message))
+			return nil
+		}, nil)
+	}
+	return nil, statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "%s is not available in the sandbox", method)
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls.
+func (r *sandboxRouter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	method, sandbox, err := r.route(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if !sandbox {
+		return handler(srv, ss)
+	}
+	for _, desc := range pb.DNSService_ServiceDesc.Streams {
+		if desc.StreamName != method {
+			continue
+		}
+		ss.SetHeader(metadata.Pairs(sandboxHeader, "true"))
+		return desc.Handler(r.srv, ss)
+	}
+	return statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "%s is not available in the sandbox", method)
+}
+
+// route reports whether a call to fullMethod is a DNSService call made with
+// a sandbox key, and returns the method name. It fails sandbox calls if no
+// sandbox is configured.
+func (r *sandboxRouter) route(ctx context.Context, fullMethod string) (method string, sandbox bool, err error) {
+	service, method := path.Split(fullMethod)
+	if strings.Trim(service, "/") != pb.DNSService_ServiceDesc.ServiceName {
+		return method, false, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return method, false, nil
+	}
+	sandbox, err = r.sandboxKey(ctx, apiKeys[0])
+	if err != nil {
+		errorf("%s: Failed to look up sandbox flag: %v", fullMethod, err)
+		return method, false, status.Errorf(codes.Internal, "failed to look up sandbox flag: %v", err)
+	}
+	// Never fall back to production data for a sandbox key
+	if sandbox && r.srv == nil {
+		return method, false, statusError(codes.FailedPrecondition, reasonSandboxUnavailable, nil, "sandbox is not configured on this server")
+	}
+	return method, sandbox, nil
+}
+
+// sandboxServer returns a copy of s that reads DNS data and metadata tables
+// from the sandbox database. Keys, preferences, redaction and admin keys are
+// shared with s; live lookups, shadow reads, the domain filter and report
+// schedules are off.
+func (s *server) sandboxServer(db *sql.DB) *server {
+	sandbox := *s
+	sandbox.db = db
+	sandbox.shards = storage.NewSingleRouter(db)
+	sandbox.domains = nil
+	sandbox.resolver = nil
+	sandbox.events = nil
+	sandbox.shadow = nil
+	sandbox.sandbox = true
+	return &sandbox
+}
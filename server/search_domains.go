@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+const (
+	// maxSearchResults caps the domains one search returns across all of
+	// its pages.
+	maxSearchResults = 10000
+
+	// minSearchLiteral is the shortest run of letters and digits a pattern
+	// must hold for idx_domains_domain_name_trgm to narrow the search;
+	// pg_trgm extracts no trigrams from shorter runs and would read the
+	// whole index.
+	minSearchLiteral = 3
+
+	// searchTokenPrefix versions the page token format of SearchDomains,
+	// which also counts the domains returned so far. s1 tokens, without a
+	// snapshot cutoff, are still read.
+	searchTokenPrefix = "s2:"
+)
+
+// SearchDomains returns the domains whose names match a glob, in name order.
+// The glob runs in SQL as a LIKE pattern, which idx_domains_domain_name_trgm
+// serves whether the wildcards lead, trail or surround the literal part.
+// Patterns ending in a literal TLD ask only the shard owning it; others are
+// fanned out to every healthy shard and the pages merged, as in
+// GetDomainsByNameserver. A search stops after maxSearchResults domains,
+// however it is paged. Domains restricted to other organizations are left
+// out. The key's preferences supply the page size the request leaves unset.
+// Later pages leave out domains inserted after the first was served (see
+// snapshotCutoff).
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) SearchDomains(ctx context.Context, req *pb.SearchDomainsRequest) (*pb.SearchDomainsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "SearchDomains", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	pattern := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Pattern), "."))
+	if pattern == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern is required")
+	}
+	if len(pattern) > 253 {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern is longer than a domain name")
+	}
+	if longestLiteral(pattern) < minSearchLiteral {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern %q must hold at least %d consecutive letters or digits", req.Pattern, minSearchLiteral)
+	}
+	pageSize := int(prefs.limit(req.PageSize))
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	served, after, cutoff, err := parseSearchToken(req.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	resp := &pb.SearchDomainsResponse{}
+	if pageSize >= maxSearchResults-served {
+		pageSize = maxSearchResults - served
+		resp.Capped = true
+	}
+	if pageSize <= 0 {
+		return resp, nil
+	}
+
+	var mu sync.Mutex
+	var domains []*pb.DomainMatch
+	search := func(ctx context.Context, shard *storage.Shard) error {
+		// One extra row tells whether the shard has more
+		rows, err := shard.Reader(ctx).QueryContext(ctx, `
+			SELECT domain_name, tld, first_seen, last_updated
+			FROM domains
+			WHERE domain_name LIKE $1 AND ($2 = '' OR tld = $2) AND domain_name COLLATE "C" > $3
+				AND (restricted_to IS NULL OR $5 = ANY(restricted_to)) AND inserted_at <= $6
+			ORDER BY domain_name COLLATE "C"
+			LIMIT $4
+		`, globPattern(pattern), patternTLD(pattern), after, pageSize+1, prefs.orgID, cutoff)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var d pb.DomainMatch
+			var firstSeen time.Time
+			var lastUpdated sql.NullTime
+			if err := rows.Scan(&d.Domain, &d.Tld, &firstSeen, &lastUpdated); err != nil {
+				return err
+			}
+			d.FirstSeen = firstSeen.Format(time.RFC3339)
+			if lastUpdated.Valid {
+				d.LastUpdated = lastUpdated.Time.Format(time.RFC3339)
+			}
+			mu.Lock()
+			domains = append(domains, &d)
+			mu.Unlock()
+		}
+		return rows.Err()
+	}
+	if tld := patternTLD(pattern); tld != "" {
+		err = search(ctx, s.shards.ForTLD(tld))
+	} else {
+		resp.SkippedShards, err = s.shards.FanOut(ctx, search)
+	}
+	if err != nil {
+		errorf("SearchDomains: Failed to search domains matching %s: %v", pattern, err)
+		return nil, status.Errorf(codes.Internal, "failed to search domains: %v", err)
+	}
+
+	// Byte order, as the shards sort with COLLATE "C", so tokens are stable
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Domain < domains[j].Domain })
+	if len(domains) > pageSize {
+		domains = domains[:pageSize]
+		if !resp.Capped {
+			resp.NextPageToken = newSearchToken(served+pageSize, cutoff, domains[pageSize-1].Domain)
+		}
+	} else {
+		// The last page, whether or not it reached the cap
+		resp.Capped = false
+	}
+	resp.Domains = domains
+	infof("SearchDomains: Returning %d domains matching %s (more: %t, capped: %t, skipped shards: %v)",
+		len(domains), pattern, resp.NextPageToken != "", resp.Capped, resp.SkippedShards)
+	return resp, nil
+}
+
+// longestLiteral returns the length of the longest run of letters and
+// digits in pattern, the characters pg_trgm builds trigrams from.
+func longestLiteral(pattern string) int {
+	longest, run := 0, 0
+	for _, r := range pattern {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// patternTLD returns the TLD of every name pattern matches, if its last
+// label is literal, or "".
+func patternTLD(pattern string) string {
+	i := strings.LastIndexByte(pattern, '.')
+	if i < 0 {
+		return ""
+	}
+	tld := pattern[i+1:]
+	if tld == "" || strings.ContainsAny(tld, "*?") {
+		return ""
+	}
+	return tld
+}
+
+// newSearchToken encodes the number of domains a search has returned, its
+// snapshot cutoff and the last domain returned as an opaque token.
+func newSearchToken(served int, cutoff time.Time, last string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(searchTokenPrefix + strconv.Itoa(served) + ":" + cutoff.UTC().Format(time.RFC3339Nano) + "|" + last))
+}
+
+// parseSearchToken decodes a token from newSearchToken. An empty token
+// starts before every domain, in a snapshot starting now, as does the
+// cutoff of an s1 token.
+func parseSearchToken(token string) (int, string, time.Time, error) {
+	if token == "" {
+		return 0, "", time.Now().UTC(), nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(raw), "s1:") {
+		count, last, ok := strings.Cut(strings.TrimPrefix(string(raw), "s1:"), ":")
+		served, err := strconv.Atoi(count)
+		if !ok || err != nil || served < 0 {
+			return 0, "", time.Time{}, fmt.Errorf("malformed page token")
+		}
+		return served, last, time.Now().UTC(), nil
+	}
+	if err != nil || !strings.HasPrefix(string(raw), searchTokenPrefix) {
+		return 0, "", time.Time{}, fmt.Errorf("malformed page token")
+	}
+	count, rest, ok := strings.Cut(strings.TrimPrefix(string(raw), searchTokenPrefix), ":")
+	served, err := strconv.Atoi(count)
+	if !ok || err != nil || served < 0 {
+		return 0, "", time.Time{}, fmt.Errorf("malformed page token")
+	}
+	at, last, ok := strings.Cut(rest, "|")
+	cutoff, err := time.Parse(time.RFC3339Nano, at)
+	if !ok || err != nil {
+		return 0, "", time.Time{}, fmt.Errorf("malformed page token")
+	}
+	return served, last, cutoff, nil
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// maxRecordPattern bounds the length of a SearchRecords pattern.
+const maxRecordPattern = 512
+
+// SearchRecords returns the records whose data matches a POSIX extended
+// regular expression, which PostgreSQL evaluates with ~ (or ~* when case
+// insensitive). Compressed record data is not in record_data, so those rows
+// are decoded and matched here with the same expression. The scan runs on
+// the shard owning the requested TLD, or on every healthy shard, until it
+// has found one record more than the limit or record_search.timeout_seconds
+// have passed; either way the matches found so far are returned, flagged as
+// truncated or timed out, and which records a cut search returns is
+// arbitrary. Records of domains restricted to other organizations are left
+// out. The key's preferences supply record types the request leaves unset
+// and the default limit.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) SearchRecords(ctx context.Context, req *pb.SearchRecordsRequest) (*pb.SearchRecordsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "SearchRecords", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if req.Pattern == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern is required")
+	}
+	if len(req.Pattern) > maxRecordPattern {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern is longer than %d bytes", maxRecordPattern)
+	}
+	// Go's POSIX syntax is the ERE subset both engines read alike
+	if _, err := regexp.CompilePOSIX(req.Pattern); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pattern %q: %v", req.Pattern, err)
+	}
+	operator, goPattern := storage.SQL("~"), req.Pattern
+	if req.CaseInsensitive {
+		operator, goPattern = "~*", "(?i)"+req.Pattern
+	}
+	re := regexp.MustCompile(goPattern)
+	requested := req.RecordType
+	if len(requested) == 0 {
+		requested = prefs.recordTypes
+	}
+	var recordTypes []string
+	for _, t := range requested {
+		if _, ok := dns.StringToType[strings.ToUpper(t)]; !ok {
+			return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": t}, "unknown record type %q", t)
+		}
+		recordTypes = append(recordTypes, strings.ToUpper(t))
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, ". "))
+	limit := int(prefs.limit(req.Limit))
+	if limit <= 0 || limit > s.searchMaxResults {
+		limit = s.searchMaxResults
+	}
+
+	query := storage.NewQuery(`
+		SELECT d.domain_name, r.domain_id, r.record_type, r.record_data, r.record_data_z, r.ttl, r.source, r.last_updated,
+			COALESCE(c.version, 0), r.observations, r.first_seen
+		FROM domains d
+		JOIN dns_records r ON r.domain_id = d.id
+		LEFT JOIN record_set_checksums c ON c.domain_id = r.domain_id AND c.record_type = r.record_type AND c.source = r.source
+	`)
+	query.Where("(r.record_data "+operator+" ? OR r.record_data_z IS NOT NULL)", req.Pattern)
+	query.WhereVisible("d", prefs.orgID)
+	if tld != "" {
+		query.WhereTLD("d", tld)
+	}
+	if len(recordTypes) > 0 {
+		query.WhereIn("r.record_type", recordTypes)
+	}
+
+	resp := &pb.SearchRecordsResponse{}
+	searchCtx, cancel := context.WithTimeout(ctx, s.searchTimeout)
+	defer cancel()
+	var mu sync.Mutex
+	search := func(ctx context.Context, shard *storage.Shard) error {
+		// Cancelled once the shard has enough matches, which stops the
+		// scan rather than draining its rows
+		ctx, stop := context.WithCancel(ctx)
+		defer stop()
+		rows, err := shard.Reader(ctx).QueryContext(ctx, query.SQL(), query.Args()...)
+		if err != nil {
+			if searchCtx.Err() == context.DeadlineExceeded {
+				mu.Lock()
+				resp.TimedOut = true
+				mu.Unlock()
+				return nil
+			}
+			return err
+		}
+		defer rows.Close()
+		var found []*pb.StreamedRecord
+		for len(found) <= limit && rows.Next() {
+			var msg pb.StreamedRecord
+			var r pb.DNSRecord
+			var data string
+			var z []byte
+			var lastUpdated, firstSeen time.Time
+			if err := rows.Scan(&msg.Domain, &r.DomainId, &r.RecordType, &data, &z, &r.Ttl, &r.Source, &lastUpdated,
+				&r.Version, &r.ObservationCount, &firstSeen); err != nil {
+				return err
+			}
+			if r.RecordData, err = storage.RecordData(data, z); err != nil {
+				return err
+			}
+			if len(z) > 0 && !re.MatchString(r.RecordData) {
+				continue
+			}
+			r.LastUpdated = lastUpdated.Format(time.RFC3339)
+			r.FirstSeen = firstSeen.Format(time.RFC3339)
+			r.LastSeen = r.LastUpdated
+			r.Sources = []string{r.Source}
+			msg.Record = &r
+			found = append(found, &msg)
+		}
+		stop()
+		err = rows.Err()
+		mu.Lock()
+		defer mu.Unlock()
+		resp.Records = append(resp.Records, found...)
+		switch {
+		case len(found) > limit:
+		case searchCtx.Err() == context.DeadlineExceeded:
+			resp.TimedOut = true
+		case err != nil:
+			return err
+		}
+		return nil
+	}
+	if tld != "" {
+		err = search(searchCtx, s.shards.ForTLD(tld))
+	} else {
+		resp.SkippedShards, err = s.shards.FanOut(searchCtx, search)
+	}
+	if err != nil {
+		errorf("SearchRecords: Failed to search records matching %s: %v", req.Pattern, err)
+		return nil, status.Errorf(codes.Internal, "failed to search records: %v", err)
+	}
+
+	sort.Slice(resp.Records, func(i, j int) bool {
+		a, b := resp.Records[i], resp.Records[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Record.RecordType != b.Record.RecordType {
+			return a.Record.RecordType < b.Record.RecordType
+		}
+		return a.Record.RecordData < b.Record.RecordData
+	})
+	if len(resp.Records) > limit {
+		resp.Records = resp.Records[:limit]
+		resp.Truncated = true
+	}
+	infof("SearchRecords: Returning %d records matching %s for API key %s (truncated: %t, timed out: %t, skipped shards: %v)",
+		len(resp.Records), req.Pattern, apiKey, resp.Truncated, resp.TimedOut, resp.SkippedShards)
+	return resp, nil
+}
@@ -6,34 +6,89 @@ package server
 import (
 	"context"
 	"database/sql"
+	"expvar"
 	"flag"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/google/uuid"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
 	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/lease"
+	"github.com/moos3/bell/logging"
+	"github.com/moos3/bell/openapi"
 	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/resolver"
+	"github.com/moos3/bell/signing"
+	"github.com/moos3/bell/storage"
+	"github.com/moos3/bell/tracing"
 )
 
 // server implements the DNSService gRPC interface, handling authentication
 // and DNS record queries against an AlloyDB database.
 type server struct {
 	pb.UnimplementedDNSServiceServer
-	db *sql.DB // Database connection
+	db        *sql.DB         // Default database: metadata tables and unsharded TLDs
+	keys      *sql.DB         // api_keys; the default database even when db is the sandbox
+	shards    *storage.Router // Routes domain and record queries to the shard owning the TLD
+	merge     mergePolicy     // Source conflict resolution for merged GetRecords
+	domains   *domainFilter   // Bloom filter of known domains; nil if disabled
+	adminKeys map[string]bool // API keys listed in authorization.admin_api_keys, which have every scope
+
+	rdap         *rdapClient      // Registrar and abuse contact lookups
+	rdapCacheTTL time.Duration    // How long stored abuse contacts are reused
+	transfers    *transferChecker // Registrar transfer detection for GetDomainLifecycle
+
+	coverageSizes   map[string]int64  // TLD -> estimated registered domains (coverage.estimated_sizes)
+	coverageSources map[string]string // TLD -> configured data source (coverage.sources)
+
+	countThreshold int64         // Largest per-shard planner estimate CountDomains and CountRecords count exactly
+	countTimeout   time.Duration // Exact counts taking longer fall back to the estimate
+
+	searchTimeout    time.Duration // SearchRecords scans stop after this long
+	searchMaxResults int           // Most records a SearchRecords call returns
+
+	wildcardMaxExpansion int     // Most domains a GetRecordsBatch pattern expands to
+	wildcardMaxCost      float64 // Planner cost above which a pattern is rejected
+
+	signer *signing.Signer // Signs responses and export files; nil if signing.key_files is empty
+
+	exportKey       []byte        // Signs export download URLs; nil if exports are not configured
+	exportDir       string        // Where the export worker writes export files
+	exportScrub     bool          // Scrubbed exports are configured (exports.scrub.key)
+	exportURLBase   string        // Public base URL of the HTTP listener
+	exportURLTTL    time.Duration // How long download URLs stay valid
+	exportRetention time.Duration // How long export files are kept
+
+	resolver *resolver.Resolver // Live DNS for VerifyDomain, LookupLive and TraceResolution; nil if no upstreams are configured
+	events   *eventHub          // Worker events for TailEvents and SubscribeRecordChanges; nil if listening failed
+	shadow   *shadowReader      // Mirrors a sample of reads to a secondary database; nil if disabled
+	prefs    *preferenceStore   // Per-key request defaults and response timezone
+	sandbox  bool               // Serves sandbox keys from the synthetic dataset
 }
 
 // Authenticate validates an API key against the api_keys table in AlloyDB.
@@ -41,21 +96,25 @@ type server struct {
 // It returns an AuthenticateResponse indicating whether the key is valid
 // and an optional message describing the result.
 func (s *server) Authenticate(ctx context.Context, req *pb.AuthenticateRequest) (*pb.AuthenticateResponse, error) {
-	var isActive bool
-	err := s.db.QueryRow("SELECT is_active FROM api_keys WHERE api_key = $1", req.ApiKey).Scan(&isActive)
+	var isActive, expired bool
+	err := s.keys.QueryRow("SELECT is_active, COALESCE(expires_at <= NOW(), FALSE) FROM api_keys WHERE api_key = $1", req.ApiKey).Scan(&isActive, &expired)
 	if err == sql.ErrNoRows {
-		log.Printf("Authenticate: API key %s not found", req.ApiKey)
+		warnf("Authenticate: API key %s not found", req.ApiKey)
 		return &pb.AuthenticateResponse{Valid: false, Message: "Invalid API key"}, nil
 	}
 	if err != nil {
-		log.Printf("Authenticate: Failed to validate API key %s: %v", req.ApiKey, err)
+		errorf("Authenticate: Failed to validate API key %s: %v", req.ApiKey, err)
 		return nil, status.Errorf(codes.Internal, "failed to validate API key: %v", err)
 	}
 	if !isActive {
-		log.Printf("Authenticate: API key %s is inactive", req.ApiKey)
+		warnf("Authenticate: API key %s is inactive", req.ApiKey)
 		return &pb.AuthenticateResponse{Valid: false, Message: "API key is inactive"}, nil
 	}
-	log.Printf("Authenticate: API key %s is valid", req.ApiKey)
+	if expired {
+		warnf("Authenticate: API key %s has expired", req.ApiKey)
+		return &pb.AuthenticateResponse{Valid: false, Message: "API key has expired"}, nil
+	}
+	infof("Authenticate: API key %s is valid", req.ApiKey)
 	return &pb.AuthenticateResponse{Valid: true, Message: "API key is valid"}, nil
 }
 
@@ -64,104 +123,245 @@ func (s *server) Authenticate(ctx context.Context, req *pb.AuthenticateRequest)
 // It requires a valid API key in the gRPC metadata ("x-api-key") and
 // returns a GetRecordsResponse containing the matching DNS records.
 // Optional record types (e.g., A, AAAA) can be specified to filter results.
+// With merged set, only the authoritative source per record type is returned
+// according to the configured merge policy, along with provenance details.
+// Passing the snapshot_token of an earlier response restricts results to
+// records that existed when that response was served. Records are ordered
+// semantically unless the request asks for storage order. Domains
+// restricted to other organizations have no records. The key's
+// preferences supply record types and source precedence the request leaves
+// unset, and cap the number of records returned. Each record carries the
+// version of its record set and the response the highest of them; passing
+// that back as known_version gets not_modified instead of the records while
+// it is unchanged. With include_geo_answers set, the answers RefreshDomain
+// stored per client subnet are returned too.
 func (s *server) GetRecords(ctx context.Context, req *pb.GetRecordsRequest) (*pb.GetRecordsResponse, error) {
-	// Log metadata for debugging
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		log.Println("GetRecords: Missing metadata")
-		return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
-	}
-	log.Printf("GetRecords: Metadata received: %v", md)
-
-	// Validate API key from metadata
-	apiKeys := md.Get("x-api-key")
-	if len(apiKeys) == 0 {
-		log.Println("GetRecords: Missing API key in metadata")
-		return nil, status.Errorf(codes.Unauthenticated, "missing API key")
-	}
-	var isActive bool
-	apiKey := apiKeys[0]
-	err := s.db.QueryRow("SELECT is_active FROM api_keys WHERE api_key = $1", apiKey).Scan(&isActive)
-	if err == sql.ErrNoRows {
-		log.Printf("GetRecords: API key %s not found", apiKey)
-		return nil, status.Errorf(codes.Unauthenticated, "invalid API key")
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "GetRecords", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if isPattern(req.Domain) {
+		return nil, status.Errorf(codes.InvalidArgument, "domain %q is a pattern; expand it with GetRecordsBatch", req.Domain)
 	}
+	recordTypes := req.RecordType
+	if len(recordTypes) == 0 {
+		recordTypes = prefs.recordTypes
+	}
+
+	cutoff, snapshotToken, err := snapshotCutoff(req.SnapshotToken)
 	if err != nil {
-		log.Printf("GetRecords: Failed to validate API key %s: %v", apiKey, err)
-		return nil, status.Errorf(codes.Internal, "failed to validate API key: %v", err)
+		return nil, statusError(codes.InvalidArgument, reasonInvalidSnapshot, nil, "%v", err)
 	}
-	if !isActive {
-		log.Printf("GetRecords: API key %s is inactive", apiKey)
-		return nil, status.Errorf(codes.Unauthenticated, "API key is inactive")
+
+	// Skip the database for domains the filter rules out
+	if s.domains != nil && !s.domains.mayContain(req.Domain) {
+		debugf("GetRecords: Domain %s ruled out by filter", req.Domain)
+		return &pb.GetRecordsResponse{SnapshotToken: snapshotToken}, nil
+	}
+	visible, err := s.domainVisible(ctx, "GetRecords", prefs, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return &pb.GetRecordsResponse{SnapshotToken: snapshotToken}, nil
 	}
 
 	// Query records
-	query := `
-		SELECT r.domain_id, r.record_type, r.record_data, r.ttl, r.source, r.last_updated
-		FROM domains d
-		JOIN dns_records r ON d.id = r.domain_id
-		WHERE d.domain_name = $1
-	`
-	args := []interface{}{req.Domain}
-	if len(req.RecordType) > 0 {
-		query += fmt.Sprintf(" AND r.record_type IN (%s)", generatePlaceholders(2, len(req.RecordType)))
-		for _, rt := range req.RecordType {
-			args = append(args, rt)
-		}
+	query := storage.NewQuery("SELECT "+observedRecordColumns+observedRecordsFrom, cutoff).
+		Where("d.domain_name = ?", req.Domain).Where("r.inserted_at <= ?", cutoff)
+	if len(recordTypes) > 0 {
+		query.WhereIn("r.record_type", recordTypes)
 	}
-	rows, err := s.db.Query(query, args...)
+	if req.Order != pb.RecordOrder_RECORD_ORDER_STORAGE {
+		query.Append(semanticOrder)
+	}
+	shard := s.shards.ForDomain(req.Domain)
+	start := time.Now()
+	rows, err := shard.Reader(ctx).QueryContext(ctx, query.SQL(), query.Args()...)
 	if err != nil {
-		log.Printf("GetRecords: Failed to query records for domain %s: %v", req.Domain, err)
+		errorf("GetRecords: Failed to query records for domain %s: %v", req.Domain, err)
 		return nil, status.Errorf(codes.Internal, "failed to query records: %v", err)
 	}
 	defer rows.Close()
 
 	var records []*pb.DNSRecord
 	for rows.Next() {
-		var r pb.DNSRecord
-		var lastUpdated time.Time
-		if err := rows.Scan(&r.DomainId, &r.RecordType, &r.RecordData, &r.Ttl, &r.Source, &lastUpdated); err != nil {
-			log.Printf("GetRecords: Failed to scan record for domain %s: %v", req.Domain, err)
+		r, err := scanObservedRecord(rows)
+		if err != nil {
+			errorf("GetRecords: Failed to scan record for domain %s: %v", req.Domain, err)
 			return nil, status.Errorf(codes.Internal, "failed to scan record: %v", err)
 		}
-		r.LastUpdated = lastUpdated.Format(time.RFC3339)
-		records = append(records, &r)
+		records = append(records, r)
 	}
 	if err := rows.Err(); err != nil {
-		log.Printf("GetRecords: Failed to iterate records for domain %s: %v", req.Domain, err)
+		errorf("GetRecords: Failed to iterate records for domain %s: %v", req.Domain, err)
 		return nil, status.Errorf(codes.Internal, "failed to iterate records: %v", err)
 	}
-	log.Printf("GetRecords: Response for domain %s: %v records", req.Domain, len(records))
+	if s.shadow.sample() {
+		// Storage order is arbitrary, so only semantic order is compared
+		ordered := req.Order != pb.RecordOrder_RECORD_ORDER_STORAGE
+		primary := recordFingerprints(records)
+		if !ordered {
+			sort.Strings(primary)
+		}
+		s.shadow.compare("GetRecords", req.Domain, primary, time.Since(start), func(ctx context.Context, db *sql.DB) ([]string, error) {
+			return shadowRecords(ctx, db, query, ordered)
+		})
+	}
+	infof("GetRecords: Response for domain %s: %v records", req.Domain, len(records))
 	for _, r := range records {
-		log.Printf("GetRecords: Record for %s: type=%s, data=%s, ttl=%d, source=%s, last_updated=%s",
+		debugf("GetRecords: Record for %s: type=%s, data=%s, ttl=%d, source=%s, last_updated=%s",
 			req.Domain, r.RecordType, r.RecordData, r.Ttl, r.Source, r.LastUpdated)
 	}
-	return &pb.GetRecordsResponse{Records: records}, nil
+
+	var provenance []*pb.MergeProvenance
+	if req.Merged {
+		policy := s.merge
+		if prefs.merge != nil {
+			policy = *prefs.merge
+		}
+		records, provenance = policy.merge(records)
+		infof("GetRecords: Merged response for domain %s: %v records", req.Domain, len(records))
+	}
+	truncated := prefs.maxRows > 0 && len(records) > prefs.maxRows
+	if truncated {
+		records = records[:prefs.maxRows]
+	}
+	var version int64
+	for _, r := range records {
+		version = max(version, r.Version)
+	}
+	if req.KnownVersion != 0 && version == req.KnownVersion {
+		infof("GetRecords: Records for domain %s not modified since version %d", req.Domain, version)
+		return &pb.GetRecordsResponse{SnapshotToken: snapshotToken, Version: version, NotModified: true}, nil
+	}
+
+	dga, err := s.getDGAScore(shard.Reader(ctx), req.Domain)
+	if err != nil {
+		errorf("GetRecords: Failed to get DGA score for domain %s: %v", req.Domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to get DGA score: %v", err)
+	}
+	var geo []*pb.GeoAnswer
+	if req.IncludeGeoAnswers {
+		if geo, err = geoAnswers(ctx, shard.Reader(ctx), req.Domain, recordTypes); err != nil {
+			errorf("GetRecords: Failed to get geo answers for domain %s: %v", req.Domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to get geo answers: %v", err)
+		}
+	}
+	return &pb.GetRecordsResponse{Records: records, Dga: dga, Provenance: provenance, SnapshotToken: snapshotToken, Truncated: truncated, Version: version, GeoAnswers: geo}, nil
 }
 
-// generatePlaceholders creates a comma-separated string of PostgreSQL placeholders
-// (e.g., "$2,$3") starting from the given index and count.
-func generatePlaceholders(start, count int) string {
-	placeholders := make([]string, count)
-	for i := 0; i < count; i++ {
-		placeholders[i] = fmt.Sprintf("$%d", start+i)
+// recordFingerprints identifies records for shadow comparison. domain_id and
+// last_updated are left out since they legitimately differ between backends.
+func recordFingerprints(records []*pb.DNSRecord) []string {
+	fingerprints := make([]string, len(records))
+	for i, r := range records {
+		fingerprints[i] = fmt.Sprintf("%s %s %d %s", r.RecordType, r.RecordData, r.Ttl, r.Source)
 	}
-	return strings.Join(placeholders, ",")
+	return fingerprints
 }
 
-// logHeadersMiddleware logs all HTTP request headers before passing the request
-// to the next handler in the chain.
-func logHeadersMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("Request: %s %s", r.Method, r.URL.String())
-		log.Println("Headers:")
-		for name, values := range r.Header {
-			for _, value := range values {
-				log.Printf("  %s: %s", name, value)
-			}
+// shadowRecords runs a GetRecords query against the shadow database and
+// returns the fingerprints of the records it finds, sorted unless ordered.
+func shadowRecords(ctx context.Context, db *sql.DB, query *storage.Query, ordered bool) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query.SQL(), query.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []*pb.DNSRecord
+	for rows.Next() {
+		r, err := scanObservedRecord(rows)
+		if err != nil {
+			return nil, err
 		}
-		next.ServeHTTP(w, r)
-	})
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	fingerprints := recordFingerprints(records)
+	if !ordered {
+		sort.Strings(fingerprints)
+	}
+	return fingerprints, nil
+}
+
+// observedRecordColumns are the columns scanObservedRecord scans, selected
+// from observedRecordsFrom.
+const observedRecordColumns = `r.domain_id, r.record_type, r.record_data, r.record_data_z, r.ttl, r.source, r.last_updated, COALESCE(c.version, 0),
+			obs.observations, obs.first_seen, obs.last_seen, obs.sources`
+
+// observedRecordsFrom joins domains (d) to their records (r), the versions
+// of the records' sets (c) and the records' observations by every source
+// inserted by a snapshot cutoff, its one placeholder (obs). Each source's row of a
+// record counts its own observations.
+const observedRecordsFrom = `
+		FROM domains d
+		JOIN dns_records r ON d.id = r.domain_id
+		LEFT JOIN record_set_checksums c ON c.domain_id = r.domain_id AND c.record_type = r.record_type AND c.source = r.source
+		CROSS JOIN LATERAL (
+			SELECT SUM(o.observations) AS observations, MIN(o.first_seen) AS first_seen, MAX(o.last_updated) AS last_seen,
+				array_agg(DISTINCT o.source) AS sources
+			FROM dns_records o
+			WHERE o.domain_id = r.domain_id AND o.record_type = r.record_type AND o.canonical_hash = r.canonical_hash
+				AND o.inserted_at <= ?
+		) obs
+	`
+
+// scanObservedRecord scans a GetRecords row: the record, decompressed, its
+// set's version and its observations, after any leading columns into
+// leading.
+func scanObservedRecord(rows *sql.Rows, leading ...interface{}) (*pb.DNSRecord, error) {
+	var r pb.DNSRecord
+	var data string
+	var z []byte
+	var lastUpdated, firstSeen, lastSeen time.Time
+	dest := append(leading, &r.DomainId, &r.RecordType, &data, &z, &r.Ttl, &r.Source, &lastUpdated, &r.Version,
+		&r.ObservationCount, &firstSeen, &lastSeen, pq.Array(&r.Sources))
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	var err error
+	if r.RecordData, err = storage.RecordData(data, z); err != nil {
+		return nil, err
+	}
+	r.LastUpdated = lastUpdated.Format(time.RFC3339)
+	r.FirstSeen = firstSeen.Format(time.RFC3339)
+	r.LastSeen = lastSeen.Format(time.RFC3339)
+	return &r, nil
+}
+
+// semanticOrder orders records by meaning rather than storage: SOA, NS, MX
+// by preference, SRV by priority and then descending weight, NAPTR by order,
+// then the other types alphabetically. priority and weight are parsed at
+// write time; rows written without them sort after those that have them.
+const semanticOrder = `
+		ORDER BY CASE r.record_type WHEN 'SOA' THEN 0 WHEN 'NS' THEN 1 WHEN 'MX' THEN 2 WHEN 'SRV' THEN 3 WHEN 'NAPTR' THEN 4 ELSE 5 END,
+			r.record_type, r.priority NULLS LAST, r.weight DESC NULLS LAST, r.record_data, r.source
+	`
+
+// getDGAScore returns the score computed by the dga job for a domain, or nil
+// if the domain has not been scored yet. db is the shard holding the domain.
+func (s *server) getDGAScore(db *sql.DB, domain string) (*pb.DGAScore, error) {
+	var score pb.DGAScore
+	var scoredAt time.Time
+	err := db.QueryRow(`
+		SELECT s.entropy, s.ngram_score, s.dga_score, s.likely_dga, s.scored_at
+		FROM domains d
+		JOIN domain_scores s ON s.domain_id = d.id
+		WHERE d.domain_name = $1
+		LIMIT 1
+	`, domain).Scan(&score.Entropy, &score.NgramScore, &score.Score, &score.LikelyDga, &scoredAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	score.ScoredAt = scoredAt.Format(time.RFC3339)
+	return &score, nil
 }
 
 // main starts the gRPC server and gRPC-Gateway with CORS support.
@@ -174,7 +374,34 @@ func main() {
 	// Load configuration
 	config, err := config.LoadConfig(*configFile)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
+	}
+	if err := logging.Setup(config); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	// Runs last, after the deferred closes, if a listener failed
+	failed := false
+	defer func() {
+		if failed {
+			os.Exit(1)
+		}
+	}()
+
+	// Before opening databases, so their statements are traced
+	shutdownTracing, err := tracing.Setup(context.Background(), config, "bell-server")
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	defer shutdownTracing(context.Background())
+	if tracing.Enabled() && config.Tracing.MetricsAddress != "" {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", tracing.MetricsHandler())
+			infof("Serving latency metrics at http://%s/metrics", config.Tracing.MetricsAddress)
+			if err := http.ListenAndServe(config.Tracing.MetricsAddress, mux); err != nil {
+				errorf("Latency metrics server failed: %v", err)
+			}
+		}()
 	}
 
 	// Connect to AlloyDB
@@ -182,65 +409,378 @@ func main() {
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.AlloyDB.Database, config.AlloyDB.SSLMode,
 	)
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open(tracing.DriverName, connStr)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("%v", err)
 	}
 	defer db.Close()
 	if err := db.Ping(); err != nil {
-		log.Fatal("Failed to connect to AlloyDB: ", err)
+		logger.Fatalf("Failed to connect to AlloyDB: %v", err)
+	}
+	infof("Connected to AlloyDB successfully")
+
+	shards, err := storage.NewRouter(config, db)
+	if err != nil {
+		logger.Fatalf("%v", err)
 	}
-	fmt.Println("Connected to AlloyDB successfully.")
+	defer shards.Close()
+
+	// Background work stops once requests have drained, as it may still
+	// need the databases: the usage meter flushes its counts on the way out
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var workers sync.WaitGroup
+	shards.StartHealthChecks(background, time.Duration(config.Sharding.HealthCheckSeconds)*time.Second)
+
+	// Re-queue TLD ingests and query batches whose worker stopped heartbeating
+	go lease.NewManager(shards, config).RunReaper(background, time.Duration(config.Leases.ReapIntervalSeconds)*time.Second)
 
 	// Start gRPC server
-	grpcServer := grpc.NewServer()
-	s := &server{db: db}
+	redact := newRedactor(db, config.Redaction.Roles)
+	sandbox := newSandboxRouter(db)
+	usage := newUsageMeter(db, sandbox)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		usage.run(background, time.Minute)
+	}()
+	quota := newOrgQuota(db, sandbox)
+	keyQuota := newKeyQuota(db, sandbox)
+	prefs := newPreferenceStore(db, config.Merge.FreshnessWins)
+	adminKeys := make(map[string]bool)
+	for _, key := range config.Authorization.AdminAPIKeys {
+		adminKeys[key] = true
+	}
+	logLevelKeys := make(map[string]bool)
+	for _, key := range config.Logging.AdminAPIKeys {
+		logLevelKeys[key] = true
+	}
+	auth := newAuthenticator(db)
+	go auth.run(background, time.Minute)
+	authz, err := newAuthorizer(db, adminKeys, logLevelKeys, config.Authorization.Methods, config.Authorization.DefaultScope, config.Authorization.Roles)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	// Signing sees the response as the client will, after every other interceptor
+	signer := &responseSigner{}
+	if len(config.Signing.KeyFiles) > 0 {
+		if signer.signer, err = signing.Load(config.Signing.KeyFiles); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		infof("Signing responses on request with key %s", signer.signer.Keys()[0].ID)
+	}
+	interceptors := []grpc.UnaryServerInterceptor{errorInfoInterceptor, signer.unaryInterceptor}
+	streamInterceptors := []grpc.StreamServerInterceptor{errorInfoStreamInterceptor}
+	if config.RateLimit.RequestsPerSecond > 0 {
+		limiter := newRateLimiter(config.RateLimit.RequestsPerSecond, config.RateLimit.Burst)
+		go limiter.run(background, time.Minute)
+		interceptors = append(interceptors, limiter.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, limiter.streamInterceptor)
+		infof("Rate limiting API keys to %g requests per second, bursts of %d", config.RateLimit.RequestsPerSecond, config.RateLimit.Burst)
+	}
+	interceptors = append(interceptors, auth.unaryInterceptor, authz.unaryInterceptor, quota.unaryInterceptor, keyQuota.unaryInterceptor, usage.unaryInterceptor, redact.unaryInterceptor, prefs.unaryInterceptor, sandbox.unaryInterceptor, freshnessInterceptor)
+	streamInterceptors = append(streamInterceptors, auth.streamInterceptor, authz.streamInterceptor, quota.streamInterceptor, keyQuota.streamInterceptor, usage.streamInterceptor, redact.streamInterceptor, prefs.streamInterceptor, sandbox.streamInterceptor, freshnessStreamInterceptor)
+	if config.Hedging.MaxExtraPercent > 0 && shards.HasReplicas() {
+		hedge := newHedger(time.Duration(config.Hedging.DelayMs)*time.Millisecond, config.Hedging.MaxExtraPercent, config.Hedging.Methods)
+		interceptors = append(interceptors, hedge.unaryInterceptor)
+		infof("Hedging %v after %dms, at most %v%% extra reads", config.Hedging.Methods, config.Hedging.DelayMs, config.Hedging.MaxExtraPercent)
+	}
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...), grpc.ChainStreamInterceptor(streamInterceptors...)}
+	var certs *certReloader
+	if config.TLS.CertFile != "" {
+		if certs, err = newCertReloader(config.TLS.CertFile, config.TLS.KeyFile, config.TLS.ClientCAFile); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		go certs.watch(background, time.Duration(config.TLS.ReloadSeconds)*time.Second)
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certs.serverConfig("h2"))))
+		infof("Serving TLS with certificate %s (client certificates required: %t)", config.TLS.CertFile, config.TLS.ClientCAFile != "")
+	}
+	if tracing.Enabled() {
+		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	checkIndexes(context.Background(), shards)
+	s := &server{
+		db:        db,
+		keys:      db,
+		shards:    shards,
+		merge:     newMergePolicy(config.Merge.Precedence, config.Merge.FreshnessWins),
+		adminKeys: adminKeys,
+		prefs:     prefs,
+
+		rdap:         newRDAPClient(config.RDAP.BootstrapURL, time.Duration(config.RDAP.TimeoutSeconds)*time.Second),
+		rdapCacheTTL: time.Duration(config.RDAP.CacheHours) * time.Hour,
+		transfers: &transferChecker{
+			lookups:       config.Lifecycle.TransferLookups,
+			likelyAfter:   time.Duration(config.Lifecycle.LikelyAfterHours) * time.Hour,
+			webhookURL:    config.Lifecycle.WebhookURL,
+			webhookSecret: config.Lifecycle.WebhookSecret,
+			client:        &http.Client{Timeout: time.Duration(config.Lifecycle.TimeoutSeconds) * time.Second},
+		},
+
+		coverageSizes:   config.Coverage.EstimatedSizes,
+		coverageSources: config.Coverage.Sources,
+
+		countThreshold: config.Counts.ExactThreshold,
+		countTimeout:   time.Duration(config.Counts.TimeoutSeconds) * time.Second,
+
+		searchTimeout:    time.Duration(config.RecordSearch.TimeoutSeconds) * time.Second,
+		searchMaxResults: config.RecordSearch.MaxResults,
+
+		wildcardMaxExpansion: config.Wildcards.MaxExpansion,
+		wildcardMaxCost:      config.Wildcards.MaxCost,
+
+		signer: signer.signer,
+
+		exportDir:       config.Exports.OutputDir,
+		exportScrub:     config.Exports.Scrub.Key != "",
+		exportURLBase:   config.Exports.URLBase,
+		exportURLTTL:    time.Duration(config.Exports.URLTTLMinutes) * time.Minute,
+		exportRetention: time.Duration(config.Exports.RetentionHours) * time.Hour,
+	}
+	if config.Exports.OutputDir != "" && config.Exports.SigningKey != "" {
+		s.exportKey = []byte(config.Exports.SigningKey)
+	}
+	if s.resolver, err = resolver.New(config); err != nil {
+		warnf("Live resolution RPCs disabled: %v", err)
+	} else {
+		s.resolver.WatchServers(background, db, time.Duration(config.Resolver.ServersRefreshSeconds)*time.Second)
+		s.resolver.StartHealthChecks(background, time.Duration(config.Resolver.HealthCheckSeconds)*time.Second)
+	}
+	if config.Shadow.Percent > 0 {
+		shadowDB, err := sql.Open(tracing.DriverName, storage.ConnString(config.Shadow.Host, config.Shadow.Port, config.Shadow.User, config.Shadow.Password, config.Shadow.Database, config.Shadow.SSLMode))
+		if err != nil {
+			logger.Fatalf("Failed to open shadow database: %v", err)
+		}
+		defer shadowDB.Close()
+		s.shadow = newShadowReader(shadowDB, config.Shadow.Percent,
+			time.Duration(config.Shadow.TimeoutSeconds)*time.Second, config.Shadow.MaxInFlight)
+		infof("Mirroring %v%% of reads to shadow database %s/%s", config.Shadow.Percent, config.Shadow.Host, config.Shadow.Database)
+		if config.Shadow.MetricsAddress != "" {
+			go func() {
+				infof("Serving shadow metrics at http://%s/debug/vars", config.Shadow.MetricsAddress)
+				if err := http.ListenAndServe(config.Shadow.MetricsAddress, expvar.Handler()); err != nil {
+					errorf("Shadow metrics server failed: %v", err)
+				}
+			}()
+		}
+	}
+	if config.DomainFilter.Enabled {
+		s.domains = &domainFilter{
+			shards:            shards,
+			expected:          config.DomainFilter.ExpectedDomains,
+			falsePositiveRate: config.DomainFilter.FalsePositiveRate,
+		}
+		go s.domains.run(background,
+			time.Duration(config.DomainFilter.RefreshSeconds)*time.Second,
+			time.Duration(config.DomainFilter.RebuildHours)*time.Hour)
+	}
+	// After the domain filter, which record-set changes are added to
+	eventConnStrs := []string{connStr}
+	for _, sc := range config.Sharding.Shards {
+		eventConnStrs = append(eventConnStrs, storage.ConnString(sc.Host, sc.Port, sc.User, sc.Password, sc.Database, sc.SSLMode))
+	}
+	if s.events, err = newEventHub(eventConnStrs, []keyCache{auth, authz, redact, sandbox, quota, keyQuota, prefs}, s.recordSetChanged); err != nil {
+		warnf("TailEvents and SubscribeRecordChanges disabled: failed to listen for events: %v", err)
+	}
+	go s.runTransferChecks(background, time.Duration(config.Lifecycle.TransferCheckSeconds)*time.Second)
+	if config.Sandbox.Database != "" {
+		sandboxDB, err := sql.Open(tracing.DriverName, storage.ConnString(config.AlloyDB.Host, config.AlloyDB.Port, config.AlloyDB.User, config.AlloyDB.Password, config.Sandbox.Database, config.AlloyDB.SSLMode))
+		if err != nil {
+			logger.Fatalf("Failed to open sandbox database: %v", err)
+		}
+		defer sandboxDB.Close()
+		sandbox.srv = s.sandboxServer(sandboxDB)
+		infof("Serving sandbox keys from database %s", config.Sandbox.Database)
+	}
 	pb.RegisterDNSServiceServer(grpcServer, s)
+	pb.RegisterAdminServiceServer(grpcServer, &adminService{s: s})
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	if config.GRPC.Reflection {
+		reflection.Register(grpcServer)
+		infof("Serving gRPC reflection")
+	}
 	lis, err := net.Listen("tcp", *grpcPort)
 	if err != nil {
-		log.Fatalf("Failed to listen on %s: %v", *grpcPort, err)
+		logger.Fatalf("Failed to listen on %s: %v", *grpcPort, err)
 	}
 
 	// Start gRPC-Gateway with CORS and case-insensitive header matcher
-	ctx := context.Background()
 	gwmux := runtime.NewServeMux(
 		runtime.WithIncomingHeaderMatcher(func(header string) (string, bool) {
 			if strings.EqualFold(header, "X-API-Key") {
-				log.Printf("Mapping header %s to x-api-key", header)
+				debugf("Mapping header %s to x-api-key", header)
 				return "x-api-key", true
 			}
 			return header, false
 		}),
+		// Rate-limited calls answer 429 with a standard Retry-After header
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			if key == "retry-after" {
+				return "Retry-After", true
+			}
+			if key == dataFreshnessHeader {
+				return "X-Data-Freshness", true
+			}
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
 	)
 	opts := []grpc.DialOption{grpc.WithInsecure()}
-	err = pb.RegisterDNSServiceHandlerFromEndpoint(ctx, gwmux, *grpcPort, opts)
+	if certs != nil {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(certs.gatewayConfig()))}
+	}
+	// The gateway's calls continue the trace of the HTTP request
+	gwOpts := opts
+	if tracing.Enabled() {
+		gwOpts = append(gwOpts[:len(gwOpts):len(gwOpts)], grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+	err = pb.RegisterDNSServiceHandlerFromEndpoint(background, gwmux, *grpcPort, gwOpts)
 	if err != nil {
-		log.Fatalf("Failed to register gateway: %v", err)
+		logger.Fatalf("Failed to register gateway: %v", err)
+	}
+	if err := pb.RegisterAdminServiceHandlerFromEndpoint(background, gwmux, *grpcPort, gwOpts); err != nil {
+		logger.Fatalf("Failed to register admin gateway: %v", err)
 	}
 
 	// Configure CORS
 	corsMiddleware := cors.New(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000"},
+		AllowedOrigins:   config.Gateway.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"X-API-Key", "x-api-key", "Content-Type"},
+		AllowedHeaders:   []string{"X-API-Key", "x-api-key", "Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", signing.RequestHeader},
+		ExposedHeaders:   []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin", "Retry-After", "X-Data-Freshness", signing.Header},
 		AllowCredentials: true,
 	})
 
-	// Chain middlewares: log headers, then CORS, then gRPC-Gateway
+	// Route gRPC-Web calls to the in-process gRPC server and everything else
+	// to the gRPC-Gateway
+	grpcWeb := &grpcWebHandler{grpcServer: grpcServer}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.Gateway.GRPCWeb && isGRPCWebRequest(r) {
+			grpcWeb.ServeHTTP(w, r)
+			return
+		}
+		gwmux.ServeHTTP(w, r)
+	})
+
+	// Chain middlewares: sampled request logging, then CORS, then response
+	// signing, then gRPC-Web or gRPC-Gateway
+	requests := newRequestLogger(config.Logging.SampleRate, config.Logging.Routes, config.Logging.RedactHeaders)
 	mux := http.NewServeMux()
-	mux.Handle("/", logHeadersMiddleware(corsMiddleware.Handler(gwmux)))
+	mux.Handle("/", requests.middleware(corsMiddleware.Handler(signer.middleware(handler))))
+	mux.Handle("/openapi.json", corsMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi.Document)
+	})))
+	if config.Gateway.SwaggerUI {
+		page, err := openapi.SwaggerUI(config.Gateway.SwaggerAssets, "/openapi.json")
+		if err != nil {
+			logger.Fatalf("Failed to render the Swagger UI: %v", err)
+		}
+		mux.HandleFunc("GET /docs", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(page)
+		})
+		infof("Serving the Swagger UI at /docs")
+	}
+	mux.HandleFunc("GET /downloads/exports/{id}/{file}", s.serveExport)
+
+	// Self-checks through the gRPC listener, as the gateway calls it
+	var probe *canary
+	if config.Canary.IntervalSeconds > 0 {
+		probe = newCanary(config.Canary.APIKey, config.Canary.Domains, config.Canary.LiveDomains, s.resolver != nil,
+			time.Duration(config.Canary.TimeoutSeconds)*time.Second, config.Canary.FailureThreshold)
+		go probe.run(background, *grpcPort, opts, time.Duration(config.Canary.IntervalSeconds)*time.Second)
+		infof("Running %d canary checks every %ds", len(probe.checks), config.Canary.IntervalSeconds)
+		// The shadow metrics server, if any, serves the same expvar handler
+		if addr := config.Canary.MetricsAddress; addr != "" && (config.Shadow.Percent <= 0 || addr != config.Shadow.MetricsAddress) {
+			go func() {
+				infof("Serving canary metrics at http://%s/debug/vars", addr)
+				if err := http.ListenAndServe(addr, expvar.Handler()); err != nil {
+					errorf("Canary metrics server failed: %v", err)
+				}
+			}()
+		}
+	}
+	if len(config.Freshness.SLOs) > 0 {
+		freshness := newFreshnessTracker(db, shards, config)
+		go freshness.run(background, time.Duration(config.Freshness.IntervalSeconds)*time.Second)
+		infof("Tracking %d freshness SLOs every %ds", len(config.Freshness.SLOs), config.Freshness.IntervalSeconds)
+	}
+	checker := newHealthChecker(db, shards, probe, healthServer,
+		time.Duration(config.Health.TimeoutSeconds)*time.Second, time.Duration(config.Health.MaxIngestionLagHours)*time.Hour)
+	go checker.run(background, time.Duration(config.Health.IntervalSeconds)*time.Second)
+	mux.HandleFunc("GET /healthz", liveness)
+	mux.HandleFunc("GET /readyz", checker.readiness)
+	var root http.Handler = mux
+	if tracing.Enabled() {
+		// Continues traceparent headers of incoming requests
+		root = otelhttp.NewHandler(mux, "http", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method // Paths name domains; they are in url.path
+		}))
+	}
+	var h3Server *http3.Server
+	if config.Gateway.HTTP3 {
+		h3Server = &http3.Server{
+			Addr:       *httpPort,
+			Handler:    root,
+			TLSConfig:  certs.serverConfig(),
+			QUICConfig: &quic.Config{}, // No 0-RTT, whose early requests can be replayed
+		}
+		// Clients learn of HTTP/3 from Alt-Svc on TCP responses
+		h3Root := root
+		root = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h3Server.SetQUICHeaders(w.Header())
+			h3Root.ServeHTTP(w, r)
+		})
+	}
 	server := &http.Server{
 		Addr:    *httpPort,
-		Handler: h2c.NewHandler(mux, &http2.Server{}),
+		Handler: h2c.NewHandler(root, &http2.Server{}),
+	}
+	serveErrs := make(chan error, 3)
+	go func() {
+		var err error
+		if certs != nil {
+			server.TLSConfig = certs.serverConfig("h2", "http/1.1")
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != http.ErrServerClosed {
+			serveErrs <- fmt.Errorf("failed to serve HTTP: %v", err)
+		}
+	}()
+	if h3Server != nil {
+		go func() {
+			if err := h3Server.ListenAndServe(); err != http.ErrServerClosed {
+				serveErrs <- fmt.Errorf("failed to serve HTTP/3: %v", err)
+			}
+		}()
+		infof("HTTP/3 server listening on %s (UDP)", *httpPort)
 	}
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
-			log.Fatalf("Failed to serve HTTP: %v", err)
+		if err := grpcServer.Serve(lis); err != nil {
+			serveErrs <- fmt.Errorf("failed to serve gRPC: %v", err)
 		}
 	}()
+	infof("gRPC server listening on %s", *grpcPort)
+	infof("HTTP server listening on %s", *httpPort)
 
-	fmt.Printf("gRPC server listening on %s\nHTTP server listening on %s\n", *grpcPort, *httpPort)
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve gRPC: %v", err)
+	// Drain on SIGTERM or SIGINT, or when either listener fails
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+	select {
+	case <-signals.Done():
+		infof("Shutting down; draining requests")
+	case err := <-serveErrs:
+		errorf("Shutting down: %v", err)
+		failed = true
 	}
+	stopSignals() // A second signal kills the process
+	checker.shutdown()
+	drain(grpcServer, server, h3Server, time.Duration(config.Shutdown.DelaySeconds)*time.Second, time.Duration(config.Shutdown.TimeoutSeconds)*time.Second)
+	stopBackground()
+	workers.Wait()
+	infof("Closing database connections")
 }
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+)
+
+// serviceRecordTypes are the types GetServiceRecords returns.
+var serviceRecordTypes = map[string]bool{"SRV": true, "NAPTR": true}
+
+// GetServiceRecords returns the SRV and NAPTR records of a name with their
+// fields parsed, so telecom users can look up _sip._tls style names directly.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Records are
+// looked up both under the name itself (as zone files store them) and under
+// its domain (as the query worker stores them), keeping those owned by name.
+// Names of domains restricted to other organizations have no records.
+func (s *server) GetServiceRecords(ctx context.Context, req *pb.GetServiceRecordsRequest) (*pb.GetServiceRecordsResponse, error) {
+	prefs, err := s.keyPreferences(ctx, "GetServiceRecords", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	name := strings.ToLower(strings.TrimSuffix(req.Name, "."))
+	recordTypes := []string{"SRV", "NAPTR"}
+	if len(req.RecordType) > 0 {
+		recordTypes = nil
+		for _, rt := range req.RecordType {
+			rt = strings.ToUpper(rt)
+			if !serviceRecordTypes[rt] {
+				return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "record type %q is not SRV or NAPTR", rt)
+			}
+			recordTypes = append(recordTypes, rt)
+		}
+	}
+
+	rows, err := s.shards.ForDomain(name).Reader(ctx).QueryContext(ctx, `
+		SELECT r.record_data, r.ttl, r.source, r.last_updated
+		FROM domains d
+		JOIN dns_records r ON d.id = r.domain_id
+		WHERE d.domain_name IN ($1, $2) AND r.record_type = ANY($3) AND (d.restricted_to IS NULL OR $4 = ANY(d.restricted_to))
+	`, name, recordset.OwnerDomain(name), pq.Array(recordTypes), prefs.orgID)
+	if err != nil {
+		errorf("GetServiceRecords: Failed to query records for %s: %v", name, err)
+		return nil, status.Errorf(codes.Internal, "failed to query records: %v", err)
+	}
+	defer rows.Close()
+
+	resp := &pb.GetServiceRecordsResponse{Name: name}
+	for rows.Next() {
+		var data, source string
+		var ttl int32
+		var lastUpdated time.Time
+		if err := rows.Scan(&data, &ttl, &source, &lastUpdated); err != nil {
+			errorf("GetServiceRecords: Failed to scan record for %s: %v", name, err)
+			return nil, status.Errorf(codes.Internal, "failed to scan record: %v", err)
+		}
+		svc, ok := recordset.ParseService(data)
+		if !ok || svc.Name != name {
+			continue
+		}
+		resp.Records = append(resp.Records, &pb.ServiceRecord{
+			Name:        svc.Name,
+			RecordType:  svc.RecordType,
+			Ttl:         ttl,
+			Source:      source,
+			LastUpdated: lastUpdated.Format(time.RFC3339),
+			Service:     svc.Service,
+			Proto:       svc.Proto,
+			Priority:    int32(svc.Priority),
+			Weight:      int32(svc.Weight),
+			Port:        int32(svc.Port),
+			Target:      svc.Target,
+			Order:       int32(svc.Order),
+			Preference:  int32(svc.Preference),
+			Flags:       svc.Flags,
+			Services:    svc.Services,
+			Regexp:      svc.Regexp,
+			Replacement: svc.Replacement,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		errorf("GetServiceRecords: Failed to iterate records for %s: %v", name, err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate records: %v", err)
+	}
+
+	// SRV before NAPTR, each in the order clients should try them
+	sort.SliceStable(resp.Records, func(i, j int) bool {
+		a, b := resp.Records[i], resp.Records[j]
+		if a.RecordType != b.RecordType {
+			return a.RecordType == "SRV"
+		}
+		if a.RecordType == "SRV" {
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+			return a.Weight > b.Weight
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.Preference < b.Preference
+	})
+	infof("GetServiceRecords: Response for %s: %d records", name, len(resp.Records))
+	return resp, nil
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// shadowReader mirrors a sample of reads to a secondary database and
+// compares its answers with the primary's in the background, so a storage
+// migration can be checked against live traffic before it serves any.
+// Clients always get the primary's answer; the secondary only feeds metrics.
+type shadowReader struct {
+	db      *sql.DB
+	percent float64       // Share of reads mirrored, 0-100
+	timeout time.Duration // Per mirrored read
+	slots   chan struct{} // Bounds mirrored reads in flight
+
+	mu    sync.Mutex
+	stats map[string]*shadowStats // RPC -> counters
+}
+
+// shadowStats counts mirrored reads for one RPC.
+type shadowStats struct {
+	Sampled       int64 `json:"sampled"`        // Reads selected for mirroring
+	Dropped       int64 `json:"dropped"`        // Sampled while max_in_flight reads were running
+	Errors        int64 `json:"errors"`         // Secondary reads that failed or timed out
+	Matched       int64 `json:"matched"`        // Same results in the same order
+	OrderDiverged int64 `json:"order_diverged"` // Same results in a different order
+	Diverged      int64 `json:"diverged"`       // Results missing from or extra on the secondary
+	Missing       int64 `json:"missing"`        // Primary results absent on the secondary
+	Extra         int64 `json:"extra"`          // Secondary results absent on the primary
+	PrimaryMicros int64 `json:"primary_micros"` // Summed primary latency of compared reads
+	ShadowMicros  int64 `json:"shadow_micros"`  // Summed secondary latency of compared reads
+}
+
+// newShadowReader mirrors percent of reads to db and publishes its counters
+// through expvar as "shadow".
+func newShadowReader(db *sql.DB, percent float64, timeout time.Duration, maxInFlight int) *shadowReader {
+	sh := &shadowReader{
+		db:      db,
+		percent: percent,
+		timeout: timeout,
+		slots:   make(chan struct{}, maxInFlight),
+		stats:   make(map[string]*shadowStats),
+	}
+	expvar.Publish("shadow", expvar.Func(func() interface{} { return sh.snapshot() }))
+	return sh
+}
+
+// sample reports whether the current read should be mirrored. It is safe to
+// call on a nil shadowReader, which never samples.
+func (sh *shadowReader) sample() bool {
+	return sh != nil && rand.Float64()*100 < sh.percent
+}
+
+// compare runs read against the secondary in the background and records how
+// its results differ from primary. key identifies the read in divergence
+// logs. Results are compared as multisets first, then by order.
+func (sh *shadowReader) compare(rpc, key string, primary []string, primaryLatency time.Duration, read func(ctx context.Context, db *sql.DB) ([]string, error)) {
+	select {
+	case sh.slots <- struct{}{}:
+	default:
+		sh.record(rpc, func(st *shadowStats) { st.Sampled++; st.Dropped++ })
+		return
+	}
+	go func() {
+		defer func() { <-sh.slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), sh.timeout)
+		defer cancel()
+		start := time.Now()
+		secondary, err := read(ctx, sh.db)
+		elapsed := time.Since(start)
+		if err != nil {
+			errorf("Shadow: %s %s: Secondary read failed: %v", rpc, key, err)
+			sh.record(rpc, func(st *shadowStats) { st.Sampled++; st.Errors++ })
+			return
+		}
+		missing, extra := multisetDiff(primary, secondary)
+		sh.record(rpc, func(st *shadowStats) {
+			st.Sampled++
+			st.PrimaryMicros += primaryLatency.Microseconds()
+			st.ShadowMicros += elapsed.Microseconds()
+			switch {
+			case len(missing) > 0 || len(extra) > 0:
+				st.Diverged++
+				st.Missing += int64(len(missing))
+				st.Extra += int64(len(extra))
+			case !sameOrder(primary, secondary):
+				st.OrderDiverged++
+			default:
+				st.Matched++
+			}
+		})
+		if len(missing) > 0 || len(extra) > 0 {
+			warnf("Shadow: %s %s diverged: %d missing on secondary %q, %d extra %q",
+				rpc, key, len(missing), firstN(missing, 3), len(extra), firstN(extra, 3))
+		} else if !sameOrder(primary, secondary) {
+			debugf("Shadow: %s %s: Secondary returned %d results in a different order", rpc, key, len(secondary))
+		}
+	}()
+}
+
+func (sh *shadowReader) record(rpc string, update func(st *shadowStats)) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	st, ok := sh.stats[rpc]
+	if !ok {
+		st = &shadowStats{}
+		sh.stats[rpc] = st
+	}
+	update(st)
+}
+
+func (sh *shadowReader) snapshot() map[string]shadowStats {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	out := make(map[string]shadowStats, len(sh.stats))
+	for rpc, st := range sh.stats {
+		out[rpc] = *st
+	}
+	return out
+}
+
+// multisetDiff returns the entries of a missing from b and of b missing
+// from a, counting duplicates.
+func multisetDiff(a, b []string) (missing, extra []string) {
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] > 0 {
+			counts[v]--
+		} else {
+			extra = append(extra, v)
+		}
+	}
+	for v, n := range counts {
+		for ; n > 0; n-- {
+			missing = append(missing, v)
+		}
+	}
+	sort.Strings(missing)
+	return missing, extra
+}
+
+func sameOrder(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"google.golang.org/grpc"
+)
+
+// drain shuts the servers down gracefully: after delay, during which they
+// keep serving while reporting not ready so load balancers stop routing
+// here, they stop accepting connections and wait up to timeout for the
+// requests in flight, then cut off whatever is left, such as subscriptions.
+func drain(grpcServer *grpc.Server, httpServer *http.Server, h3Server *http3.Server, delay, timeout time.Duration) {
+	if delay > 0 {
+		infof("Shutdown: Serving for %v more while load balancers stop routing here", delay)
+		time.Sleep(delay)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		warnf("Shutdown: HTTP requests still in flight after %v; closing their connections", timeout)
+		httpServer.Close()
+	}
+	if h3Server != nil {
+		if err := h3Server.Shutdown(ctx); err != nil {
+			warnf("Shutdown: HTTP/3 requests still in flight after %v; closing their connections", timeout)
+			h3Server.Close()
+		}
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		warnf("Shutdown: gRPC calls still in flight after %v; cancelling them", timeout)
+		grpcServer.Stop()
+		<-stopped
+	}
+	infof("Shutdown: Requests drained")
+}
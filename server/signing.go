@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/signing"
+)
+
+// responseSigner signs the responses of callers that ask for it with an
+// X-Bell-Sign: true header (see the signing package): HTTP gateway
+// responses over their body as sent, in an X-Bell-Signature header, and
+// unary gRPC and gRPC-Web responses over their deterministic protobuf
+// encoding, in the x-bell-signature response header. Streams are not
+// signed.
+type responseSigner struct {
+	signer *signing.Signer // nil if signing.key_files is empty
+}
+
+// signatureRequested reports whether the values of the X-Bell-Sign header
+// ask for a signature.
+func signatureRequested(values []string) bool {
+	for _, v := range values {
+		if v == "1" || strings.EqualFold(v, "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// unaryInterceptor signs the response of a gRPC call asking for it, and
+// fails such calls up front if signing is not configured.
+func (rs *responseSigner) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !signatureRequested(md.Get(signing.RequestHeader)) {
+		return handler(ctx, req)
+	}
+	if rs.signer == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "response signing is not configured")
+	}
+	resp, err := handler(ctx, req)
+	msg, ok := resp.(proto.Message)
+	if err != nil || !ok {
+		return resp, err
+	}
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		errorf("%s: Failed to encode response for signing: %v", info.FullMethod, err)
+		return nil, status.Errorf(codes.Internal, "failed to sign response: %v", err)
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(signing.Header), rs.signer.SignPayload(payload, time.Now()))); err != nil {
+		errorf("%s: Failed to set response signature: %v", info.FullMethod, err)
+		return nil, status.Errorf(codes.Internal, "failed to sign response: %v", err)
+	}
+	return resp, nil
+}
+
+// bufferedResponse holds a response until it is signed.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+// middleware signs the gateway responses of HTTP requests asking for it,
+// holding each response until it is complete. gRPC-Web requests are left
+// to unaryInterceptor.
+func (rs *responseSigner) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !signatureRequested(r.Header.Values(signing.RequestHeader)) || isGRPCWebRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if rs.signer == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": codes.FailedPrecondition, "message": "response signing is not configured"})
+			return
+		}
+		buf := &bufferedResponse{header: make(http.Header)}
+		next.ServeHTTP(buf, r)
+		for name, values := range buf.header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(signing.Header, rs.signer.SignPayload(buf.body.Bytes(), time.Now()))
+		w.WriteHeader(max(buf.status, http.StatusOK))
+		w.Write(buf.body.Bytes())
+	})
+}
+
+// GetSigningKeys returns the public keys that signed responses and export
+// files verify against, the active key first.
+//
+// It needs no API key, so anyone holding signed data can check it.
+func (s *server) GetSigningKeys(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+	if s.signer == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "response signing is not configured")
+	}
+	resp := &pb.GetSigningKeysResponse{}
+	for _, key := range s.signer.Keys() {
+		der, err := x509.MarshalPKIXPublicKey(key.PublicKey)
+		if err != nil {
+			errorf("GetSigningKeys: Failed to encode key %s: %v", key.ID, err)
+			return nil, status.Errorf(codes.Internal, "failed to encode signing key: %v", err)
+		}
+		resp.Keys = append(resp.Keys, &pb.SigningKey{
+			KeyId:        key.ID,
+			Algorithm:    "Ed25519",
+			PublicKey:    base64.StdEncoding.EncodeToString(key.PublicKey),
+			PublicKeyPem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			Active:       key.Active,
+		})
+	}
+	return resp, nil
+}
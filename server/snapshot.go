@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// snapshotTokenPrefix versions the snapshot token format.
+const snapshotTokenPrefix = "v1:"
+
+// Snapshot tokens give paging clients a consistent view while ingestion runs.
+//
+// domains and dns_records rows are stamped with inserted_at by the database
+// when inserted, and writers never set or update it, unlike first_seen,
+// which imports backdate. A cutoff on inserted_at therefore selects the rows
+// that existed when the snapshot started, however their first_seen was
+// written. Rows are still updated in place by later observations, so their
+// TTL, last_updated and nameservers may be newer than the cutoff; and a row
+// whose write transaction started before the cutoff but committed after it
+// only appears once committed. The token is an opaque encoding of the
+// cutoff; the page tokens of the paged domain RPCs carry one the same way.
+
+// newSnapshotToken encodes a snapshot cutoff as an opaque token.
+func newSnapshotToken(cutoff time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(snapshotTokenPrefix + cutoff.UTC().Format(time.RFC3339Nano)))
+}
+
+// parseSnapshotToken decodes a token from newSnapshotToken.
+func parseSnapshotToken(token string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(raw), snapshotTokenPrefix) {
+		return time.Time{}, fmt.Errorf("malformed snapshot token")
+	}
+	cutoff, err := time.Parse(time.RFC3339Nano, strings.TrimPrefix(string(raw), snapshotTokenPrefix))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed snapshot token")
+	}
+	return cutoff, nil
+}
+
+// snapshotCutoff returns the cutoff for a request and the token to hand back.
+// Without a token, a new snapshot starts now.
+func snapshotCutoff(token string) (time.Time, string, error) {
+	if token == "" {
+		cutoff := time.Now().UTC()
+		return cutoff, newSnapshotToken(cutoff), nil
+	}
+	cutoff, err := parseSnapshotToken(token)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return cutoff, token, nil
+}
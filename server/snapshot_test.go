@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/moos3/bell/fixtures"
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+func TestPageTokenKeepsCutoff(t *testing.T) {
+	cutoff := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	last, got, err := parsePageToken(newPageToken(cutoff, "acme.example"))
+	if err != nil || last != "acme.example" || !got.Equal(cutoff) {
+		t.Errorf("parsePageToken = %q, %v, %v; want acme.example, %v", last, got, err, cutoff)
+	}
+
+	// v1 tokens carry no cutoff and start a snapshot now
+	before := time.Now()
+	last, got, err = parsePageToken(base64.RawURLEncoding.EncodeToString([]byte("v1:acme.example")))
+	if err != nil || last != "acme.example" || got.Before(before) {
+		t.Errorf("parsePageToken(v1) = %q, %v, %v", last, got, err)
+	}
+
+	for _, token := range []string{"not base64!", base64.RawURLEncoding.EncodeToString([]byte("v2:yesterday|acme.example")),
+		base64.RawURLEncoding.EncodeToString([]byte("v9:acme.example"))} {
+		if _, _, err := parsePageToken(token); err == nil {
+			t.Errorf("parsePageToken(%q) accepted a malformed token", token)
+		}
+	}
+}
+
+func TestSearchTokenKeepsCutoff(t *testing.T) {
+	cutoff := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	served, last, got, err := parseSearchToken(newSearchToken(250, cutoff, "acme.example"))
+	if err != nil || served != 250 || last != "acme.example" || !got.Equal(cutoff) {
+		t.Errorf("parseSearchToken = %d, %q, %v, %v", served, last, got, err)
+	}
+	served, last, _, err = parseSearchToken(base64.RawURLEncoding.EncodeToString([]byte("s1:250:acme.example")))
+	if err != nil || served != 250 || last != "acme.example" {
+		t.Errorf("parseSearchToken(s1) = %d, %q, %v", served, last, err)
+	}
+	if _, _, _, err := parseSearchToken(base64.RawURLEncoding.EncodeToString([]byte("s2:-1:" + cutoff.Format(time.RFC3339Nano) + "|x"))); err == nil {
+		t.Error("parseSearchToken accepted a negative count")
+	}
+}
+
+func TestSnapshotToken(t *testing.T) {
+	cutoff, token, err := snapshotCutoff("")
+	if err != nil || token == "" {
+		t.Fatalf("snapshotCutoff(\"\") = %v, %q, %v", cutoff, token, err)
+	}
+	again, sameToken, err := snapshotCutoff(token)
+	if err != nil || !again.Equal(cutoff) || sameToken != token {
+		t.Errorf("snapshotCutoff(%q) = %v, %q, %v; want %v", token, again, sameToken, err, cutoff)
+	}
+	if _, _, err := snapshotCutoff("bogus"); err == nil {
+		t.Error("snapshotCutoff accepted a malformed token")
+	}
+}
+
+// newTestServer returns a server reading db as its only shard.
+func newTestServer(db *sql.DB) *server {
+	return &server{
+		db:        db,
+		keys:      db,
+		shards:    storage.NewSingleRouter(db),
+		merge:     newMergePolicy(nil, false),
+		adminKeys: make(map[string]bool),
+		prefs:     newPreferenceStore(db, false),
+	}
+}
+
+func TestListDomainsByTLDSnapshot(t *testing.T) {
+	db := fixtures.OpenDatabase(t)
+	zone := fixtures.Generate(fixtures.Options{Seed: 5, DomainsPerTLD: 30}).Zones[0]
+	if err := zone.Store(db); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(db)
+	ctx := context.Background()
+
+	resp, err := s.ListDomainsByTLD(ctx, &pb.ListDomainsByTLDRequest{Tld: "example", PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Inserted after the snapshot started, backdated as imports do
+	if _, err := db.Exec(`
+		INSERT INTO domains (domain_name, tld, public_suffix, first_seen)
+		VALUES ('zzz-late.example', 'example', 'example', '2000-01-01')
+	`); err != nil {
+		t.Fatal(err)
+	}
+	listed := len(resp.Domains)
+	for resp.NextPageToken != "" {
+		if resp, err = s.ListDomainsByTLD(ctx, &pb.ListDomainsByTLDRequest{Tld: "example", PageSize: 10, PageToken: resp.NextPageToken}); err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range resp.Domains {
+			if d.Domain == "zzz-late.example" {
+				t.Error("a domain inserted after the snapshot started was listed")
+			}
+		}
+		listed += len(resp.Domains)
+	}
+	if listed != len(zone.Domains) {
+		t.Errorf("listed %d domains, want %d", listed, len(zone.Domains))
+	}
+
+	resp, err = s.ListDomainsByTLD(ctx, &pb.ListDomainsByTLDRequest{Tld: "example", PageSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Domains) != len(zone.Domains)+1 {
+		t.Errorf("a new listing returned %d domains, want %d", len(resp.Domains), len(zone.Domains)+1)
+	}
+}
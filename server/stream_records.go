@@ -0,0 +1,112 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// StreamRecords sends the records of a domain, or of every domain of a TLD,
+// one message per row as they are read from the shard's cursor, so neither
+// side holds the whole result set. Records come in storage order, and their
+// observation counts and first and last sightings are those of the row's own
+// source: combining sources would need a scan per record. Records of domains
+// restricted to other organizations are left out. The key's preferences
+// supply record types the request leaves unset and cap the number of records
+// sent.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) StreamRecords(req *pb.StreamRecordsRequest, stream pb.DNSService_StreamRecordsServer) error {
+	ctx := stream.Context()
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "StreamRecords", apiKey)
+	if err != nil {
+		return err
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+	if (domain == "") == (tld == "") {
+		return status.Errorf(codes.InvalidArgument, "exactly one of domain and tld is required")
+	}
+	recordTypes := req.RecordType
+	if len(recordTypes) == 0 {
+		recordTypes = prefs.recordTypes
+	}
+
+	query := storage.NewQuery(`
+		SELECT d.domain_name, r.domain_id, r.record_type, r.record_data, r.record_data_z, r.ttl, r.source, r.last_updated, COALESCE(c.version, 0),
+			r.observations, r.first_seen
+		FROM domains d
+		JOIN dns_records r ON r.domain_id = d.id
+		LEFT JOIN record_set_checksums c ON c.domain_id = r.domain_id AND c.record_type = r.record_type AND c.source = r.source
+	`)
+	var shard *storage.Shard
+	if domain != "" {
+		if s.domains != nil && !s.domains.mayContain(domain) {
+			debugf("StreamRecords: Domain %s ruled out by filter", domain)
+			return nil
+		}
+		shard = s.shards.ForDomain(domain)
+		query.Where("d.domain_name = ?", domain)
+	} else {
+		shard = s.shards.ForTLD(tld)
+		query.WhereTLD("d", tld)
+	}
+	query.WhereVisible("d", prefs.orgID)
+	if len(recordTypes) > 0 {
+		query.WhereIn("r.record_type", recordTypes)
+	}
+	if req.Source != "" {
+		query.Where("r.source = ?", strings.ToUpper(req.Source))
+	}
+	if prefs.maxRows > 0 {
+		query.Append("LIMIT ?", prefs.maxRows)
+	}
+
+	rows, err := shard.DB.QueryContext(ctx, query.SQL(), query.Args()...)
+	if err != nil {
+		errorf("StreamRecords: Failed to query records for %s%s: %v", domain, tld, err)
+		return status.Errorf(codes.Internal, "failed to query records: %v", err)
+	}
+	defer rows.Close()
+	sent := 0
+	for rows.Next() {
+		var msg pb.StreamedRecord
+		var r pb.DNSRecord
+		var data string
+		var z []byte
+		var lastUpdated, firstSeen time.Time
+		if err := rows.Scan(&msg.Domain, &r.DomainId, &r.RecordType, &data, &z, &r.Ttl, &r.Source, &lastUpdated, &r.Version,
+			&r.ObservationCount, &firstSeen); err != nil {
+			errorf("StreamRecords: Failed to scan record for %s%s: %v", domain, tld, err)
+			return status.Errorf(codes.Internal, "failed to scan record: %v", err)
+		}
+		if r.RecordData, err = storage.RecordData(data, z); err != nil {
+			errorf("StreamRecords: Failed to decode record for %s%s: %v", domain, tld, err)
+			return status.Errorf(codes.Internal, "failed to decode record: %v", err)
+		}
+		r.LastUpdated = lastUpdated.Format(time.RFC3339)
+		r.FirstSeen = firstSeen.Format(time.RFC3339)
+		r.LastSeen = r.LastUpdated
+		r.Sources = []string{r.Source}
+		msg.Record = &r
+		if err := stream.Send(&msg); err != nil {
+			return err
+		}
+		sent++
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errorf("StreamRecords: Failed to iterate records for %s%s: %v", domain, tld, err)
+		return status.Errorf(codes.Internal, "failed to iterate records: %v", err)
+	}
+	infof("StreamRecords: Streamed %d records of %s%s for API key %s", sent, domain, tld, apiKey)
+	return nil
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// interceptedStream lets a stream interceptor act on the request and the
+// responses of a server-streaming call the way a unary interceptor acts on
+// req and resp. recv runs once the request is decoded and can fail the call
+// before the handler sees it; send runs on each response before it is sent.
+type interceptedStream struct {
+	grpc.ServerStream
+	recv func(req interface{}) error
+	send func(resp proto.Message) error
+}
+
+func (s *interceptedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.recv != nil {
+		return s.recv(m)
+	}
+	return nil
+}
+
+func (s *interceptedStream) SendMsg(m interface{}) error {
+	if msg, ok := m.(proto.Message); ok && s.send != nil {
+		if err := s.send(msg); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
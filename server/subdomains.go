@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// ListSubdomains returns the domains, found in zone files or by active
+// queries, whose names end in ".<apex>". The *.<apex> match runs in SQL as a
+// prefix match on the reversed name, which idx_domains_reverse_name serves,
+// and results come in reversed-name order so a name's subdomains follow it.
+// Subdomains share the apex's TLD, so only the shard owning it is asked.
+// Subdomains restricted to other organizations are left out. The key's
+// preferences supply the page size the request leaves unset.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListSubdomains(ctx context.Context, req *pb.ListSubdomainsRequest) (*pb.ListSubdomainsResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "ListSubdomains", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	apex := strings.ToLower(strings.Trim(strings.TrimPrefix(strings.TrimSpace(req.Apex), "*."), "."))
+	if apex == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "apex is required")
+	}
+	if strings.ContainsAny(apex, "*?") {
+		return nil, status.Errorf(codes.InvalidArgument, "apex %q must be a domain name", req.Apex)
+	}
+	pageSize := int(prefs.limit(req.PageSize))
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	after, cutoff, err := parsePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// The expressions match idx_domains_reverse_name; one extra row tells
+	// whether there are more
+	rows, err := s.shards.ForDomain(apex).Reader(ctx).QueryContext(ctx, `
+		SELECT domain_name, tld, first_seen, last_updated
+		FROM domains
+		WHERE reverse(domain_name) COLLATE "C" LIKE $1 AND reverse(domain_name) COLLATE "C" > reverse($2)
+			AND (restricted_to IS NULL OR $4 = ANY(restricted_to)) AND inserted_at <= $5
+		ORDER BY reverse(domain_name) COLLATE "C"
+		LIMIT $3
+	`, globPattern(reverseName(apex)+".*"), after, pageSize+1, prefs.orgID, cutoff)
+	if err != nil {
+		errorf("ListSubdomains: Failed to query subdomains of %s: %v", apex, err)
+		return nil, status.Errorf(codes.Internal, "failed to query subdomains: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.ListSubdomainsResponse{}
+	for rows.Next() {
+		var d pb.Subdomain
+		var firstSeen time.Time
+		var lastUpdated sql.NullTime
+		if err := rows.Scan(&d.Domain, &d.Tld, &firstSeen, &lastUpdated); err != nil {
+			errorf("ListSubdomains: Failed to scan subdomain of %s: %v", apex, err)
+			return nil, status.Errorf(codes.Internal, "failed to query subdomains: %v", err)
+		}
+		d.FirstSeen = firstSeen.Format(time.RFC3339)
+		if lastUpdated.Valid {
+			d.LastUpdated = lastUpdated.Time.Format(time.RFC3339)
+		}
+		resp.Subdomains = append(resp.Subdomains, &d)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListSubdomains: Failed to query subdomains of %s: %v", apex, err)
+		return nil, status.Errorf(codes.Internal, "failed to query subdomains: %v", err)
+	}
+
+	if len(resp.Subdomains) > pageSize {
+		resp.Subdomains = resp.Subdomains[:pageSize]
+		resp.NextPageToken = newPageToken(cutoff, resp.Subdomains[pageSize-1].Domain)
+	}
+	infof("ListSubdomains: Returning %d subdomains of %s (more: %t)", len(resp.Subdomains), apex, resp.NextPageToken != "")
+	return resp, nil
+}
+
+// reverseName reverses name character by character, as PostgreSQL's
+// reverse does.
+func reverseName(name string) string {
+	runes := []rune(name)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// TailEvents streams ingestion and worker events (zones started and
+// completed, batches committed, errors, record-set changes) until the client
+// disconnects. Filtering by significance keeps only record-set changes, so
+// alerting can skip cosmetic ones.
+//
+// It requires an API key with the admin scope. Events are live
+// only; nothing published before the call is replayed, and events are
+// dropped for a client that falls too far behind.
+func (s *server) TailEvents(req *pb.TailEventsRequest, stream pb.DNSService_TailEventsServer) error {
+	ctx := stream.Context()
+	apiKey := apiKeyFromContext(ctx)
+	if s.events == nil {
+		return statusError(codes.Unavailable, codeReason(codes.Unavailable), nil, "event streaming is not available")
+	}
+	sources := make(map[string]bool)
+	for _, source := range req.Sources {
+		sources[source] = true
+	}
+	kinds := make(map[string]bool)
+	for _, kind := range req.Kinds {
+		kinds[kind] = true
+	}
+	significances := make(map[string]bool)
+	for _, significance := range req.Significances {
+		significances[strings.ToUpper(significance)] = true
+	}
+	tld := strings.ToLower(strings.Trim(req.Tld, "."))
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+	infof("TailEvents: Streaming events for API key %s", apiKey)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-ch:
+			if (len(sources) > 0 && !sources[e.Source]) || (len(kinds) > 0 && !kinds[e.Kind]) || (tld != "" && e.Tld != tld) ||
+				(len(significances) > 0 && !significances[e.Significance]) {
+				continue
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
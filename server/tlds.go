@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// tldStatusQuery selects the columns scanned by scanTLDStatus.
+const tldStatusQuery = `
+	SELECT tld, last_processed, last_attempted, COALESCE(last_status, ''), COALESCE(last_error, ''), domain_count, record_count
+	FROM processed_tlds
+`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTLDStatus scans a row selected by tldStatusQuery into a TLDStatus.
+func scanTLDStatus(row rowScanner) (*pb.TLDStatus, error) {
+	var st pb.TLDStatus
+	var lastProcessed, lastAttempted sql.NullTime
+	if err := row.Scan(&st.Tld, &lastProcessed, &lastAttempted, &st.LastStatus, &st.LastError, &st.DomainCount, &st.RecordCount); err != nil {
+		return nil, err
+	}
+	if lastProcessed.Valid {
+		st.LastProcessed = lastProcessed.Time.Format(time.RFC3339)
+	}
+	if lastAttempted.Valid {
+		st.LastAttempted = lastAttempted.Time.Format(time.RFC3339)
+	}
+	return &st, nil
+}
+
+// ListTLDs returns the ingestion status of every TLD known to the CZDS importer.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListTLDs(ctx context.Context, req *pb.ListTLDsRequest) (*pb.ListTLDsResponse, error) {
+	query := storage.NewQuery(tldStatusQuery).Append("ORDER BY tld")
+	rows, err := s.db.QueryContext(ctx, query.SQL())
+	if err != nil {
+		errorf("ListTLDs: Failed to query TLDs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to query TLDs: %v", err)
+	}
+	defer rows.Close()
+
+	var tlds []*pb.TLDStatus
+	for rows.Next() {
+		st, err := scanTLDStatus(rows)
+		if err != nil {
+			errorf("ListTLDs: Failed to scan TLD: %v", err)
+			return nil, status.Errorf(codes.Internal, "failed to scan TLD: %v", err)
+		}
+		tlds = append(tlds, st)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListTLDs: Failed to iterate TLDs: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to iterate TLDs: %v", err)
+	}
+	infof("ListTLDs: Response: %d TLDs", len(tlds))
+	return &pb.ListTLDsResponse{Tlds: tlds}, nil
+}
+
+// GetTLDStatus returns the ingestion status of a single TLD.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key") and returns
+// NotFound if the TLD has never been ingested.
+func (s *server) GetTLDStatus(ctx context.Context, req *pb.GetTLDStatusRequest) (*pb.GetTLDStatusResponse, error) {
+	if req.Tld == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "tld is required")
+	}
+
+	query := storage.NewQuery(tldStatusQuery).Where("tld = ?", req.Tld)
+	st, err := scanTLDStatus(s.db.QueryRowContext(ctx, query.SQL(), query.Args()...))
+	if err == sql.ErrNoRows {
+		return nil, statusError(codes.NotFound, reasonTLDNotIngested, map[string]string{"tld": req.Tld}, "TLD %s has not been ingested", req.Tld)
+	}
+	if err != nil {
+		errorf("GetTLDStatus: Failed to query TLD %s: %v", req.Tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query TLD: %v", err)
+	}
+	return &pb.GetTLDStatusResponse{Status: st}, nil
+}
+
+// ListDomainsByTLD returns the domains of a TLD, or of a public suffix such
+// as co.uk, in name order, optionally only those first seen within a range.
+// A TLD lives on one shard, which pages through it on
+// idx_domains_tld_domain_name (idx_domains_public_suffix_domain_name for a
+// suffix) past the token's domain. Domains restricted to other
+// organizations are left out. The key's preferences supply the page size
+// the request leaves unset.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) ListDomainsByTLD(ctx context.Context, req *pb.ListDomainsByTLDRequest) (*pb.ListDomainsByTLDResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	prefs, err := s.keyPreferences(ctx, "ListDomainsByTLD", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	tld := strings.ToLower(strings.Trim(strings.TrimSpace(req.Tld), "."))
+	if tld == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "tld is required")
+	}
+	if strings.ContainsAny(tld, "*? ") {
+		return nil, status.Errorf(codes.InvalidArgument, "tld %q must be a TLD or public suffix", req.Tld)
+	}
+	after, err := optionalTime("first_seen_after", req.FirstSeenAfter)
+	if err != nil {
+		return nil, err
+	}
+	before, err := optionalTime("first_seen_before", req.FirstSeenBefore)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(prefs.limit(req.PageSize))
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	last, cutoff, err := parsePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// One extra row tells whether there are more
+	query := storage.NewQuery("SELECT d.domain_name, d.public_suffix, d.nameservers, d.first_seen, d.last_updated FROM domains d").
+		WhereTLD("d", tld).WhereVisible("d", prefs.orgID).Where("d.domain_name > ?", last).Where("d.inserted_at <= ?", cutoff)
+	if !after.IsZero() {
+		query.Where("d.first_seen > ?", after)
+	}
+	if !before.IsZero() {
+		query.Where("d.first_seen < ?", before)
+	}
+	query.Append("ORDER BY d.domain_name LIMIT ?", pageSize+1)
+	rows, err := s.shards.ForTLD(tld).Reader(ctx).QueryContext(ctx, query.SQL(), query.Args()...)
+	if err != nil {
+		errorf("ListDomainsByTLD: Failed to query domains of %s: %v", tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query domains: %v", err)
+	}
+	defer rows.Close()
+	resp := &pb.ListDomainsByTLDResponse{}
+	for rows.Next() {
+		var d pb.TLDDomain
+		var firstSeen time.Time
+		var lastUpdated sql.NullTime
+		if err := rows.Scan(&d.Domain, &d.PublicSuffix, pq.Array(&d.Nameservers), &firstSeen, &lastUpdated); err != nil {
+			errorf("ListDomainsByTLD: Failed to scan domain of %s: %v", tld, err)
+			return nil, status.Errorf(codes.Internal, "failed to query domains: %v", err)
+		}
+		d.FirstSeen = firstSeen.Format(time.RFC3339)
+		if lastUpdated.Valid {
+			d.LastUpdated = lastUpdated.Time.Format(time.RFC3339)
+		}
+		resp.Domains = append(resp.Domains, &d)
+	}
+	if err := rows.Err(); err != nil {
+		errorf("ListDomainsByTLD: Failed to query domains of %s: %v", tld, err)
+		return nil, status.Errorf(codes.Internal, "failed to query domains: %v", err)
+	}
+
+	if len(resp.Domains) > pageSize {
+		resp.Domains = resp.Domains[:pageSize]
+		resp.NextPageToken = newPageToken(cutoff, resp.Domains[pageSize-1].Domain)
+	}
+	infof("ListDomainsByTLD: Returning %d domains of %s (more: %t)", len(resp.Domains), tld, resp.NextPageToken != "")
+	return resp, nil
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader holds the certificate of the gRPC and HTTP listeners and the
+// CA bundle client certificates are verified against, reloading them when
+// their files change so rotated certificates are served without a restart.
+// Each handshake takes the current pair through GetConfigForClient.
+type certReloader struct {
+	certFile, keyFile, caFile string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool // nil unless client certificates are required
+	modTimes [3]time.Time   // Of certFile, keyFile and caFile when last loaded
+}
+
+// newCertReloader loads the certificate and key, and the client CA bundle
+// if caFile is set.
+func newCertReloader(certFile, keyFile, caFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload loads the files if any changed since the last load and reports
+// whether they did. On error the loaded certificate is kept.
+func (r *certReloader) reload() (bool, error) {
+	var modTimes [3]time.Time
+	for i, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	r.mu.RLock()
+	unchanged := r.cert != nil && modTimes == r.modTimes
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load TLS certificate %s: %v", r.certFile, err)
+	}
+	var clientCA *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read client CA bundle %s: %v", r.caFile, err)
+		}
+		clientCA = x509.NewCertPool()
+		if !clientCA.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificates in client CA bundle %s", r.caFile)
+		}
+	}
+	r.mu.Lock()
+	r.cert, r.clientCA, r.modTimes = &cert, clientCA, modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+// watch reloads the files on interval until ctx is cancelled. A certificate
+// caught mid-rotation, with the key not yet matching, fails to load and is
+// retried on the next tick.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.reload()
+			if err != nil {
+				warnf("TLS: Keeping the current certificate: %v", err)
+			} else if changed {
+				infof("TLS: Reloaded certificate %s", r.certFile)
+			}
+		}
+	}
+}
+
+// current returns the loaded certificate and client CA pool.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.clientCA
+}
+
+// serverConfig returns the TLS configuration of a listener negotiating
+// nextProtos. With a client CA bundle, every client must present a
+// certificate: one it signed, or the server's own, which the gateway
+// presents when dialing the gRPC listener (see gatewayConfig).
+func (r *certReloader) serverConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCA := r.current()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if clientCA != nil {
+				cfg.ClientAuth = tls.RequireAnyClientCert
+				cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return verifyClientCert(rawCerts, cert, clientCA)
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// verifyClientCert accepts the server's own certificate or one chaining to
+// clientCA for client authentication.
+func verifyClientCert(rawCerts [][]byte, own *tls.Certificate, clientCA *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("client certificate required")
+	}
+	if bytes.Equal(rawCerts[0], own.Certificate[0]) {
+		return nil
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %v", err)
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         clientCA,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// gatewayConfig returns the TLS configuration the gateway dials the gRPC
+// listener with. The listener's address is rarely a name in its
+// certificate, so instead of the usual verification the server must present
+// the certificate currently loaded, which is also presented as the client
+// certificate when mutual TLS is on.
+func (r *certReloader) gatewayConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true, // Replaced by VerifyPeerCertificate's pinning
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			cert, _ := r.current()
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], cert.Certificate[0]) {
+				return errors.New("gRPC listener presented an unexpected certificate")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+	}
+}
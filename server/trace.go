@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/resolver"
+)
+
+// Trace limits: a delegation chain longer than maxTraceSteps is treated as a
+// loop, and at most maxTraceAttempts nameservers of a zone are tried.
+const (
+	maxTraceSteps    = 24
+	maxTraceAttempts = 3
+)
+
+// traceServer is a nameserver and the address it is queried at.
+type traceServer struct {
+	name string
+	addr string // ip:port
+}
+
+// rootServers are the root hints where traces start. Only IPv4 addresses are
+// listed, as the server may not have IPv6 connectivity.
+var rootServers = []traceServer{
+	{"a.root-servers.net", "198.41.0.4:53"},
+	{"b.root-servers.net", "170.247.170.2:53"},
+	{"c.root-servers.net", "192.33.4.12:53"},
+	{"d.root-servers.net", "199.7.91.13:53"},
+	{"e.root-servers.net", "192.203.230.10:53"},
+	{"f.root-servers.net", "192.5.5.241:53"},
+	{"g.root-servers.net", "192.112.36.4:53"},
+	{"h.root-servers.net", "198.97.190.53:53"},
+	{"i.root-servers.net", "192.36.148.17:53"},
+	{"j.root-servers.net", "192.58.128.30:53"},
+	{"k.root-servers.net", "193.0.14.129:53"},
+	{"l.root-servers.net", "199.7.83.42:53"},
+	{"m.root-servers.net", "202.12.27.33:53"},
+}
+
+// TraceResolution resolves a domain iteratively from the root servers,
+// without recursion or the cache, and returns each step of the delegation
+// chain. If the domain is in the corpus, the final answer is compared with
+// the stored records, to explain why they differ from live DNS.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). A trace
+// that cannot complete (lame delegation, no reachable nameserver) is
+// returned with the failing step rather than as an error.
+func (s *server) TraceResolution(ctx context.Context, req *pb.TraceResolutionRequest) (*pb.TraceResolutionResponse, error) {
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	if _, ok := dns.IsDomainName(domain); !ok || domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain %q", req.Domain)
+	}
+	recordType := strings.ToUpper(req.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dns.StringToType[recordType]
+	if !ok {
+		return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": recordType}, "unknown record type %q", recordType)
+	}
+
+	start := time.Now()
+	resp := &pb.TraceResolutionResponse{Domain: domain, RecordType: recordType}
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), qtype)
+	m.RecursionDesired = false
+	m.SetEdns0(4096, true)
+
+	zone, servers := ".", rootServers
+	for len(resp.Steps) < maxTraceSteps {
+		step, next, nextServers := s.traceStep(ctx, m, zone, servers)
+		resp.Steps = append(resp.Steps, step)
+		if step.Kind != "REFERRAL" {
+			resp.Complete = step.Kind != "ERROR"
+			resp.Answer = step.Answer
+			break
+		}
+		zone, servers = next, nextServers
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if last := resp.Steps[len(resp.Steps)-1]; last.Kind == "REFERRAL" {
+		last.Kind = "ERROR"
+		last.Error = fmt.Sprintf("more than %d referrals", maxTraceSteps)
+	}
+
+	// Domains restricted to other organizations are traced as if not in the
+	// corpus
+	prefs, err := s.keyPreferences(ctx, "TraceResolution", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	db := s.shards.ForDomain(domain).DB
+	var domainID int32
+	err = db.QueryRowContext(ctx, "SELECT id FROM domains WHERE domain_name = $1 AND (restricted_to IS NULL OR $2 = ANY(restricted_to)) LIMIT 1", domain, prefs.orgID).Scan(&domainID)
+	if err != nil && err != sql.ErrNoRows {
+		errorf("TraceResolution: Failed to look up domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up domain: %v", err)
+	}
+	if err == nil {
+		dbSets, err := databaseRecordSets(ctx, db, domainID, []string{recordType})
+		if err != nil {
+			errorf("TraceResolution: Failed to query records for domain %s: %v", domain, err)
+			return nil, status.Errorf(codes.Internal, "failed to query records: %v", err)
+		}
+		resp.Stored = recordset.Set(dbSets[recordType])
+		if resp.Complete {
+			liveSet := recordset.Set(resp.Answer)
+			resp.MissingFromDatabase = difference(liveSet, resp.Stored)
+			resp.StaleInDatabase = difference(resp.Stored, liveSet)
+			resp.Drift = len(resp.MissingFromDatabase) > 0 || len(resp.StaleInDatabase) > 0
+		}
+	}
+	resp.ElapsedMs = float64(time.Since(start).Microseconds()) / 1000
+	infof("TraceResolution: Response for domain %s %s: %d steps, complete=%v, drift=%v", domain, recordType, len(resp.Steps), resp.Complete, resp.Drift)
+	return resp, nil
+}
+
+// traceStep asks the nameservers of zone for m's question, trying up to
+// maxTraceAttempts of them, and classifies the answer. For a referral it also
+// returns the delegated zone and its nameservers' addresses.
+func (s *server) traceStep(ctx context.Context, m *dns.Msg, zone string, servers []traceServer) (*pb.TraceStep, string, []traceServer) {
+	step := &pb.TraceStep{Zone: zone, Kind: "ERROR"}
+	var r *dns.Msg
+	for i, ns := range servers {
+		if i == maxTraceAttempts {
+			break
+		}
+		res, err := s.resolver.Lookup(ctx, m, resolver.LookupOptions{Nameserver: ns.addr, NoCache: true})
+		if err == nil && (res.Response.Rcode == dns.RcodeServerFailure || res.Response.Rcode == dns.RcodeRefused) {
+			err = fmt.Errorf("answered %s", dns.RcodeToString[res.Response.Rcode])
+		}
+		if err != nil {
+			step.FailedServers = append(step.FailedServers, fmt.Sprintf("%s (%s): %v", ns.name, ns.addr, err))
+			continue
+		}
+		step.Server, step.Address = ns.name, ns.addr
+		step.RttMs = float64(res.RTT.Microseconds()) / 1000
+		r = res.Response
+		break
+	}
+	if r == nil {
+		step.Error = fmt.Sprintf("no nameserver of %s answered", zone)
+		return step, "", nil
+	}
+	step.Rcode = dns.RcodeToString[r.Rcode]
+	for _, rr := range r.Answer {
+		step.Answer = append(step.Answer, rr.String())
+	}
+
+	qname, qtype := m.Question[0].Name, m.Question[0].Qtype
+	switch {
+	case r.Rcode == dns.RcodeNameError:
+		step.Kind = "NXDOMAIN"
+		step.Authority, step.Dnssec = negativeProof(r)
+		return step, "", nil
+	case r.Rcode != dns.RcodeSuccess:
+		step.Error = fmt.Sprintf("%s answered %s", step.Server, step.Rcode)
+		return step, "", nil
+	case len(r.Answer) > 0:
+		step.Kind = "CNAME"
+		for _, rr := range r.Answer {
+			if rr.Header().Rrtype == qtype {
+				step.Kind = "ANSWER"
+			}
+		}
+		step.Dnssec = "UNSIGNED"
+		for _, rr := range r.Answer {
+			if _, ok := rr.(*dns.RRSIG); ok {
+				step.Dnssec = "SIGNED"
+			}
+		}
+		return step, "", nil
+	}
+
+	var delegation string
+	for _, rr := range r.Ns {
+		if ns, ok := rr.(*dns.NS); ok {
+			delegation = dns.CanonicalName(ns.Hdr.Name)
+			step.Nameservers = append(step.Nameservers, strings.TrimSuffix(ns.Ns, "."))
+		}
+	}
+	if delegation == "" || r.Authoritative {
+		step.Kind = "NODATA"
+		step.Nameservers = nil
+		step.Authority, step.Dnssec = negativeProof(r)
+		return step, "", nil
+	}
+	if delegation == dns.CanonicalName(zone) || !dns.IsSubDomain(zone, delegation) || !dns.IsSubDomain(delegation, qname) {
+		step.Error = fmt.Sprintf("lame referral from %s to %s", zone, delegation)
+		return step, "", nil
+	}
+	step.Kind = "REFERRAL"
+	step.Delegation = delegation
+	step.Dnssec = "UNSIGNED"
+	for _, rr := range r.Ns {
+		if _, ok := rr.(*dns.DS); ok {
+			step.Ds = append(step.Ds, rr.String())
+			step.Dnssec = "SIGNED"
+		}
+	}
+
+	// Query the delegated nameservers at their IPv4 glue, resolving those
+	// without glue through a recursive upstream as dig +trace does
+	glue := make(map[string][]string)
+	for _, rr := range r.Extra {
+		switch g := rr.(type) {
+		case *dns.A:
+			step.Glue = append(step.Glue, rr.String())
+			name := strings.TrimSuffix(dns.CanonicalName(g.Hdr.Name), ".")
+			glue[name] = append(glue[name], g.A.String())
+		case *dns.AAAA:
+			step.Glue = append(step.Glue, rr.String())
+		}
+	}
+	var next []traceServer
+	for _, name := range step.Nameservers {
+		addrs, ok := glue[strings.ToLower(name)]
+		if !ok {
+			addrs = s.resolveAddresses(ctx, name)
+		}
+		for _, a := range addrs {
+			next = append(next, traceServer{name: name, addr: net.JoinHostPort(a, "53")})
+		}
+	}
+	if len(next) == 0 {
+		step.Kind = "ERROR"
+		step.Error = fmt.Sprintf("no addresses for the nameservers of %s", delegation)
+		return step, "", nil
+	}
+	return step, delegation, next
+}
+
+// negativeProof returns the SOA and NSEC/NSEC3 records of a negative answer
+// and whether the denial is signed.
+func negativeProof(r *dns.Msg) ([]string, string) {
+	var authority []string
+	signed := "UNSIGNED"
+	for _, rr := range r.Ns {
+		switch rr.(type) {
+		case *dns.SOA, *dns.NSEC, *dns.NSEC3:
+			authority = append(authority, rr.String())
+		case *dns.RRSIG:
+			signed = "SIGNED"
+		}
+	}
+	return authority, signed
+}
+
+// resolveAddresses returns the IPv4 addresses of a nameserver without glue,
+// or none if they cannot be resolved.
+func (s *server) resolveAddresses(ctx context.Context, name string) []string {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), dns.TypeA)
+	r, _, err := s.resolver.Recursive(ctx, m)
+	if err != nil {
+		debugf("TraceResolution: Failed to resolve nameserver %s: %v", name, err)
+		return nil
+	}
+	var addrs []string
+	for _, rr := range r.Answer {
+		if a, ok := rr.(*dns.A); ok {
+			addrs = append(addrs, a.A.String())
+		}
+	}
+	return addrs
+}
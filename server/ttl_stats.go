@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// ttlBucketBounds are the lower bounds of the TTL histogram buckets
+// (1 minute, 5 minutes, 1 hour, 1 day).
+var ttlBucketBounds = []int32{0, 60, 300, 3600, 86400}
+
+// GetTTLStats returns the TTL distribution of stored records for a domain or
+// TLD, along with TTL anomalies flagged by the query worker. Records of
+// domains restricted to other organizations are left out.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key").
+func (s *server) GetTTLStats(ctx context.Context, req *pb.GetTTLStatsRequest) (*pb.GetTTLStatsResponse, error) {
+	if (req.Domain == "") == (req.Tld == "") {
+		return nil, status.Errorf(codes.InvalidArgument, "exactly one of domain or tld is required")
+	}
+	prefs, err := s.keyPreferences(ctx, "GetTTLStats", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	anomalyLimit := req.AnomalyLimit
+	if anomalyLimit <= 0 {
+		anomalyLimit = 100
+	}
+
+	scope := req.Domain
+	shard := s.shards.ForDomain(req.Domain)
+	if req.Tld != "" {
+		scope = req.Tld
+		shard = s.shards.ForTLD(req.Tld)
+	}
+	// filtered applies the scope, visibility and optional record type shared
+	// by all three queries
+	filtered := func(base storage.SQL, args ...interface{}) *storage.Query {
+		q := storage.NewQuery(base, args...).WhereVisible("d", prefs.orgID)
+		if req.Tld != "" {
+			q.WhereTLD("d", req.Tld)
+		} else {
+			q.Where("d.domain_name = ?", req.Domain)
+		}
+		if req.RecordType != "" {
+			q.Where("r.record_type = ?", req.RecordType)
+		}
+		return q
+	}
+
+	resp := &pb.GetTTLStatsResponse{}
+	var mean, p50, p90, p99 *float64
+	var min, max *int32
+	stats := filtered(`
+		SELECT COUNT(*), MIN(r.ttl), MAX(r.ttl), AVG(r.ttl),
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY r.ttl),
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY r.ttl),
+		       percentile_cont(0.99) WITHIN GROUP (ORDER BY r.ttl)
+		FROM dns_records r
+		JOIN domains d ON d.id = r.domain_id
+	`).Where("r.ttl IS NOT NULL")
+	err = shard.Reader(ctx).QueryRowContext(ctx, stats.SQL(), stats.Args()...).Scan(&resp.Count, &min, &max, &mean, &p50, &p90, &p99)
+	if err != nil {
+		errorf("GetTTLStats: Failed to compute TTL stats for %s: %v", scope, err)
+		return nil, status.Errorf(codes.Internal, "failed to compute TTL stats: %v", err)
+	}
+	if resp.Count > 0 {
+		resp.Min, resp.Max, resp.Mean = *min, *max, *mean
+		resp.P50, resp.P90, resp.P99 = *p50, *p90, *p99
+	}
+
+	// Histogram: width_bucket assigns bucket i+1 to TTLs in [bounds[i], bounds[i+1])
+	histogram := filtered(`
+		SELECT width_bucket(r.ttl, ?::int[]), COUNT(*)
+		FROM dns_records r
+		JOIN domains d ON d.id = r.domain_id
+	`, pq.Array(ttlBucketBounds)).Where("r.ttl IS NOT NULL").Append("GROUP BY 1")
+	rows, err := shard.Reader(ctx).QueryContext(ctx, histogram.SQL(), histogram.Args()...)
+	if err != nil {
+		errorf("GetTTLStats: Failed to compute TTL histogram for %s: %v", scope, err)
+		return nil, status.Errorf(codes.Internal, "failed to compute TTL histogram: %v", err)
+	}
+	defer rows.Close()
+	counts := make([]int64, len(ttlBucketBounds))
+	for rows.Next() {
+		var bucket int
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan TTL histogram: %v", err)
+		}
+		// Negative TTLs fall in bucket 0; fold them into the first bucket
+		if bucket < 1 {
+			bucket = 1
+		}
+		counts[bucket-1] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to iterate TTL histogram: %v", err)
+	}
+	for i, lower := range ttlBucketBounds {
+		b := &pb.TTLBucket{MinTtl: lower, Count: counts[i]}
+		if i+1 < len(ttlBucketBounds) {
+			b.MaxTtl = ttlBucketBounds[i+1]
+		}
+		resp.Buckets = append(resp.Buckets, b)
+	}
+
+	recent := filtered(`
+		SELECT d.domain_name, r.record_type, r.old_ttl, r.new_ttl, r.observed_at
+		FROM ttl_anomalies r
+		JOIN domains d ON d.id = r.domain_id
+	`).Append("ORDER BY r.observed_at DESC LIMIT ?", anomalyLimit)
+	anomalies, err := shard.Reader(ctx).QueryContext(ctx, recent.SQL(), recent.Args()...)
+	if err != nil {
+		errorf("GetTTLStats: Failed to query TTL anomalies for %s: %v", scope, err)
+		return nil, status.Errorf(codes.Internal, "failed to query TTL anomalies: %v", err)
+	}
+	defer anomalies.Close()
+	for anomalies.Next() {
+		var a pb.TTLAnomaly
+		var observedAt time.Time
+		if err := anomalies.Scan(&a.Domain, &a.RecordType, &a.OldTtl, &a.NewTtl, &observedAt); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to scan TTL anomaly: %v", err)
+		}
+		a.ObservedAt = observedAt.Format(time.RFC3339)
+		resp.Anomalies = append(resp.Anomalies, &a)
+	}
+	if err := anomalies.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to iterate TTL anomalies: %v", err)
+	}
+
+	infof("GetTTLStats: Response for %s: %d records, %d anomalies", scope, resp.Count, len(resp.Anomalies))
+	return resp, nil
+}
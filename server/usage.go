@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// usageKey identifies one row of usage_counts.
+type usageKey struct {
+	apiKey string
+	day    string // UTC date, 2006-01-02
+	rpc    string // Method name without the service (e.g. GetRecords)
+	tld    string // "" for RPCs that do not name a domain or TLD
+}
+
+type usageCount struct {
+	requests int64
+	errors   int64
+	records  int64
+}
+
+// usageMeter counts requests and returned records per API key, day, RPC and
+// TLD for billing exports, monthly key quotas and GetUsage. Counts are kept
+// in memory and added to usage_counts by flush, so they reach the database
+// in batches about once a minute. Sandbox keys are not counted.
+type usageMeter struct {
+	db      *sql.DB
+	sandbox *sandboxRouter
+
+	mu     sync.Mutex
+	counts map[usageKey]*usageCount
+}
+
+func newUsageMeter(db *sql.DB, sandbox *sandboxRouter) *usageMeter {
+	return &usageMeter{db: db, sandbox: sandbox, counts: make(map[usageKey]*usageCount)}
+}
+
+// unaryInterceptor counts each authenticated call once per TLD it touches,
+// along with the DNS records of its response. Calls rejected for their
+// credentials, and calls that failed on the server's side, are not counted;
+// other failures count as errors.
+func (u *usageMeter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	var records int64
+	if msg, ok := resp.(proto.Message); ok && err == nil {
+		records = responseRecords(msg.ProtoReflect())
+	}
+	u.count(ctx, info.FullMethod, req, err, records)
+	return resp, err
+}
+
+// streamInterceptor is unaryInterceptor for streaming calls, counting the
+// records of every message sent. Calls that end before their request is
+// received, such as those over quota, are not counted.
+func (u *usageMeter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var req interface{}
+	var records int64
+	err := handler(srv, &interceptedStream{ServerStream: ss, recv: func(m interface{}) error {
+		req = m
+		return nil
+	}, send: func(resp proto.Message) error {
+		records += responseRecords(resp.ProtoReflect())
+		return nil
+	}})
+	if req != nil {
+		u.count(ss.Context(), info.FullMethod, req, err, records)
+	}
+	return err
+}
+
+// count records a call that ended with err after returning records DNS
+// records, which are counted under the call's first TLD.
+func (u *usageMeter) count(ctx context.Context, fullMethod string, req interface{}, err error, records int64) {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied, codes.Internal:
+		return
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return
+	}
+	// A failed lookup also failed the call in the sandbox router
+	if sandbox, lookupErr := u.sandbox.sandboxKey(ctx, apiKeys[0]); sandbox || lookupErr != nil {
+		return
+	}
+	tlds := []string{""}
+	if msg, ok := req.(proto.Message); ok {
+		if found := requestTLDs(msg.ProtoReflect()); len(found) > 0 {
+			tlds = found
+		}
+	}
+
+	day := time.Now().UTC().Format("2006-01-02")
+	rpc := path.Base(fullMethod)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, tld := range tlds {
+		k := usageKey{apiKey: apiKeys[0], day: day, rpc: rpc, tld: tld}
+		c, ok := u.counts[k]
+		if !ok {
+			c = &usageCount{}
+			u.counts[k] = c
+		}
+		c.requests++
+		if err != nil {
+			c.errors++
+		}
+		c.records += records
+		records = 0
+	}
+}
+
+// dnsRecordName is the full name of the messages responseRecords counts.
+var dnsRecordName = (&pb.DNSRecord{}).ProtoReflect().Descriptor().FullName()
+
+// arrowMessageName is the full name of the messages of StreamRecordBatches,
+// which count the rows of their batch.
+var arrowMessageName = (&pb.ArrowMessage{}).ProtoReflect().Descriptor().FullName()
+
+// responseRecords returns the number of DNSRecord messages in m, however
+// deeply nested, or the rows of an Arrow record batch.
+func responseRecords(m protoreflect.Message) int64 {
+	switch m.Descriptor().FullName() {
+	case dnsRecordName:
+		return 1
+	case arrowMessageName:
+		return m.Interface().(*pb.ArrowMessage).Rows
+	}
+	var n int64
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					n += responseRecords(v.Message())
+					return true
+				})
+			}
+		case fd.Kind() != protoreflect.MessageKind:
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				n += responseRecords(list.Get(i).Message())
+			}
+		default:
+			n += responseRecords(v.Message())
+		}
+		return true
+	})
+	return n
+}
+
+// requestTLDs returns the distinct TLDs named by a request's top-level
+// domain, domains and tld fields.
+func requestTLDs(m protoreflect.Message) []string {
+	seen := make(map[string]bool)
+	var tlds []string
+	add := func(name string, isDomain bool) {
+		name = strings.ToLower(strings.Trim(name, "."))
+		if isDomain {
+			name = name[strings.LastIndex(name, ".")+1:]
+		}
+		// Labels longer than DNS allows are not TLDs; count them as unscoped
+		if name != "" && len(name) <= 63 && !seen[name] {
+			seen[name] = true
+			tlds = append(tlds, name)
+		}
+	}
+	fields := m.Descriptor().Fields()
+	if fd := fields.ByName("domain"); fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+		add(m.Get(fd).String(), true)
+	}
+	if fd := fields.ByName("domains"); fd != nil && fd.Kind() == protoreflect.StringKind && fd.IsList() {
+		list := m.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			add(list.Get(i).String(), true)
+		}
+	}
+	if fd := fields.ByName("tld"); fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+		add(m.Get(fd).String(), false)
+	}
+	return tlds
+}
+
+// run flushes counts every interval until ctx is done, then flushes once more.
+func (u *usageMeter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if err := u.flush(); err != nil {
+				errorf("Usage: Failed to flush usage counts: %v", err)
+			}
+			return
+		}
+		if err := u.flush(); err != nil {
+			errorf("Usage: Failed to flush usage counts: %v", err)
+		}
+	}
+}
+
+// flush adds the counts gathered since the last flush to usage_counts. On
+// failure the counts are kept for the next flush.
+func (u *usageMeter) flush() (err error) {
+	u.mu.Lock()
+	counts := u.counts
+	u.counts = make(map[usageKey]*usageCount)
+	u.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			u.mu.Lock()
+			for k, c := range counts {
+				if cur, ok := u.counts[k]; ok {
+					cur.requests += c.requests
+					cur.errors += c.errors
+					cur.records += c.records
+				} else {
+					u.counts[k] = c
+				}
+			}
+			u.mu.Unlock()
+		}
+	}()
+
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO usage_counts (api_key, day, rpc, tld, requests, errors, records)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (api_key, day, rpc, tld) DO UPDATE
+		SET requests = usage_counts.requests + EXCLUDED.requests, errors = usage_counts.errors + EXCLUDED.errors,
+			records = usage_counts.records + EXCLUDED.records
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for k, c := range counts {
+		if _, err := stmt.Exec(k.apiKey, k.day, k.rpc, k.tld, c.requests, c.errors, c.records); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/recordset"
+	"github.com/moos3/bell/resolver"
+	"github.com/moos3/bell/storage"
+)
+
+// verifyRecordTypes are checked when a VerifyDomain request names none; they
+// match what the query worker resolves, plus NS.
+var verifyRecordTypes = []string{"NS", "A", "AAAA", "MX", "TXT", "CNAME"}
+
+// VerifyDomain re-resolves a domain's record sets against its nameservers and
+// compares them with the current sets in the database and with the checksums
+// stored at zone ingest and worker resolution time. Domains restricted to
+// other organizations are not found.
+//
+// It requires a valid API key in the gRPC metadata ("x-api-key"). Live
+// answers bypass the shared resolver's cache.
+func (s *server) VerifyDomain(ctx context.Context, req *pb.VerifyDomainRequest) (*pb.VerifyDomainResponse, error) {
+	if s.resolver == nil {
+		return nil, statusError(codes.Unavailable, reasonResolverDisabled, nil, "live resolution is not configured")
+	}
+	domain := strings.ToLower(strings.TrimSuffix(req.Domain, "."))
+	requested := req.RecordType
+	if len(requested) == 0 {
+		requested = verifyRecordTypes
+	}
+	var recordTypes []string
+	for _, rt := range requested {
+		rt = strings.ToUpper(rt)
+		if _, ok := dns.StringToType[rt]; !ok {
+			return nil, statusError(codes.InvalidArgument, reasonUnknownRecordType, map[string]string{"record_type": rt}, "unknown record type %q", rt)
+		}
+		recordTypes = append(recordTypes, rt)
+	}
+
+	prefs, err := s.keyPreferences(ctx, "VerifyDomain", apiKeyFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Domains restricted to other organizations are not found
+	db := s.shards.ForDomain(domain).DB
+	var domainID int32
+	var nameservers pq.StringArray
+	err = db.QueryRowContext(ctx, `
+		SELECT id, nameservers FROM domains
+		WHERE domain_name = $1 AND (restricted_to IS NULL OR $2 = ANY(restricted_to))
+		LIMIT 1
+	`, domain, prefs.orgID).Scan(&domainID, &nameservers)
+	if err == sql.ErrNoRows {
+		return nil, statusError(codes.NotFound, reasonDomainNotFound, map[string]string{"domain": domain}, "domain %s not found", domain)
+	}
+	if err != nil {
+		errorf("VerifyDomain: Failed to look up domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to look up domain: %v", err)
+	}
+
+	stored, err := storedChecksums(ctx, db, domainID)
+	if err != nil {
+		errorf("VerifyDomain: Failed to query checksums for domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query record set checksums: %v", err)
+	}
+	dbSets, err := databaseRecordSets(ctx, db, domainID, recordTypes)
+	if err != nil {
+		errorf("VerifyDomain: Failed to query records for domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to query records: %v", err)
+	}
+
+	if len(nameservers) == 0 {
+		if nameservers, err = s.discoverNameservers(ctx, domain); err != nil {
+			errorf("VerifyDomain: Failed to discover nameservers for %s: %v", domain, err)
+			return nil, statusError(codes.Unavailable, reasonUpstreamFailed, map[string]string{"retry_after": upstreamRetryAfter}, "failed to discover nameservers: %v", err)
+		}
+	}
+
+	resp := &pb.VerifyDomainResponse{Domain: domain, Nameservers: nameservers}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, rt := range recordTypes {
+		v := &pb.RecordSetVerification{
+			RecordType: rt,
+			Zone:       stored[rt+"/CZDS"],
+			Query:      stored[rt+"/QUERY"],
+		}
+		dbSet := recordset.Set(dbSets[rt])
+		if len(dbSet) > 0 {
+			v.Database = &pb.RecordSetChecksum{Checksum: recordset.Checksum(dbSet), RecordCount: int32(len(dbSet)), ComputedAt: now}
+		}
+		live, err := s.resolveLive(ctx, domain, dns.StringToType[rt], nameservers)
+		if err != nil {
+			v.LiveError = err.Error()
+		} else {
+			liveSet := recordset.Set(live)
+			if len(liveSet) > 0 {
+				v.Live = &pb.RecordSetChecksum{Checksum: recordset.Checksum(liveSet), RecordCount: int32(len(liveSet)), ComputedAt: now}
+			}
+			v.MissingFromDatabase = difference(liveSet, dbSet)
+			v.StaleInDatabase = difference(dbSet, liveSet)
+			v.Drift = checksumOf(v.Database) != checksumOf(v.Live) ||
+				(v.Zone != nil && v.Zone.Checksum != checksumOf(v.Live))
+		}
+		resp.RecordSets = append(resp.RecordSets, v)
+		resp.Drift = resp.Drift || v.Drift
+	}
+	infof("VerifyDomain: Response for domain %s: %d record sets, drift=%v", domain, len(resp.RecordSets), resp.Drift)
+	return resp, nil
+}
+
+// storedChecksums returns the checksums stored for a domain keyed by
+// "<record type>/<source>".
+func storedChecksums(ctx context.Context, db *sql.DB, domainID int32) (map[string]*pb.RecordSetChecksum, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT record_type, source, checksum, record_count, version, computed_at
+		FROM record_set_checksums
+		WHERE domain_id = $1 AND removed_at IS NULL
+	`, domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stored := make(map[string]*pb.RecordSetChecksum)
+	for rows.Next() {
+		var recordType, source string
+		var c pb.RecordSetChecksum
+		var computedAt time.Time
+		if err := rows.Scan(&recordType, &source, &c.Checksum, &c.RecordCount, &c.Version, &computedAt); err != nil {
+			return nil, err
+		}
+		c.ComputedAt = computedAt.Format(time.RFC3339)
+		stored[recordType+"/"+source] = &c
+	}
+	return stored, rows.Err()
+}
+
+// databaseRecordSets returns a domain's current record data grouped by
+// record type: the union over sources of the records each source's latest
+// observation of the type saw, as GetRecordHistory marks them current. Sets
+// dropped from their zone are left out, and so are records a source stopped
+// seeing, which stay in dns_records with an older last_updated.
+func databaseRecordSets(ctx context.Context, db *sql.DB, domainID int32, recordTypes []string) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT r.record_type, r.record_data, r.record_data_z
+		FROM dns_records r
+		WHERE r.domain_id = $1 AND r.record_type = ANY($2)
+			AND r.last_updated = (
+				SELECT MAX(l.last_updated) FROM dns_records l
+				WHERE l.domain_id = r.domain_id AND l.record_type = r.record_type AND l.source = r.source
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM record_set_checksums c
+				WHERE c.domain_id = r.domain_id AND c.record_type = r.record_type AND c.source = r.source
+					AND c.removed_at IS NOT NULL
+			)
+	`, domainID, pq.Array(recordTypes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sets := make(map[string][]string)
+	for rows.Next() {
+		var recordType, data string
+		var z []byte
+		if err := rows.Scan(&recordType, &data, &z); err != nil {
+			return nil, err
+		}
+		if data, err = storage.RecordData(data, z); err != nil {
+			return nil, err
+		}
+		sets[recordType] = append(sets[recordType], data)
+	}
+	return sets, rows.Err()
+}
+
+// discoverNameservers asks a recursive upstream for a domain's NS records.
+func (s *server) discoverNameservers(ctx context.Context, domain string) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), dns.TypeNS)
+	r, upstream, err := s.resolver.Recursive(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("NS query using %s failed: %v", upstream, err)
+	}
+	var nameservers []string
+	for _, ans := range r.Answer {
+		if ns, ok := ans.(*dns.NS); ok {
+			nameservers = append(nameservers, strings.TrimSuffix(ns.Ns, "."))
+		}
+	}
+	if len(nameservers) == 0 {
+		return nil, fmt.Errorf("no NS records for %s", domain)
+	}
+	return nameservers, nil
+}
+
+// resolveLive queries nameservers in turn for one record type, bypassing the
+// cache, and returns the answer of the first that responds, as the query
+// worker does.
+func (s *server) resolveLive(ctx context.Context, domain string, recordType uint16, nameservers []string) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), recordType)
+	var lastErr error
+	for _, ns := range nameservers {
+		res, err := s.resolver.Lookup(ctx, m, resolver.LookupOptions{Nameserver: ns + ":53", NoCache: true})
+		if err == nil && (res.Response.Rcode == dns.RcodeServerFailure || res.Response.Rcode == dns.RcodeRefused) {
+			err = fmt.Errorf("%s answered %s", ns, dns.RcodeToString[res.Response.Rcode])
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var data []string
+		for _, ans := range res.Response.Answer {
+			data = append(data, ans.String())
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("no nameserver answered: %v", lastErr)
+}
+
+// difference returns the members of sorted set a that are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, x := range b {
+		in[x] = true
+	}
+	var diff []string
+	for _, x := range a {
+		if !in[x] {
+			diff = append(diff, x)
+		}
+	}
+	return diff
+}
+
+func checksumOf(c *pb.RecordSetChecksum) string {
+	if c == nil {
+		return ""
+	}
+	return c.Checksum
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+// Domains whose restricted_to lists organizations, such as the private
+// zones customers import, are visible only to those organizations' keys.
+// Queries over several domains filter on restricted_to in SQL
+// (storage.Query.WhereVisible); handlers of a single domain check it with
+// domainVisible first and answer for a hidden domain as for an unknown one.
+// Precomputed analytics (GetTopN, GetKeywordTrends, GetDNSSECAdoption) and
+// TLD-wide totals count restricted domains without naming them; domain
+// clusters and weekly subdomain activity leave them out.
+
+// domainVisible reports whether domain is visible to the keys of the
+// caller's organization. Unknown domains are, so callers answer for them as
+// before.
+func (s *server) domainVisible(ctx context.Context, method string, prefs *keyPreferences, domain string) (bool, error) {
+	var visible bool
+	err := s.shards.ForDomain(domain).Reader(ctx).QueryRowContext(ctx, `
+		SELECT restricted_to IS NULL OR $2 = ANY(restricted_to) FROM domains WHERE domain_name = $1 LIMIT 1
+	`, domain, prefs.orgID).Scan(&visible)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		errorf("%s: Failed to check visibility of domain %s: %v", method, domain, err)
+		return false, status.Errorf(codes.Internal, "failed to look up domain: %v", err)
+	}
+	if !visible {
+		debugf("%s: Domain %s is restricted to other organizations", method, domain)
+	}
+	return visible, nil
+}
+
+// SetDomainRestriction restricts a domain and its records to the keys of the
+// given organizations, or makes it public again when none are given. Keys
+// see the change within a minute, as their organization is cached with
+// their preferences.
+//
+// It requires an API key with the admin scope in the gRPC metadata ("x-api-key").
+func (s *server) SetDomainRestriction(ctx context.Context, req *pb.SetDomainRestrictionRequest) (*pb.DomainRestriction, error) {
+	apiKey := apiKeyFromContext(ctx)
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Domain), "."))
+	if domain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "domain is required")
+	}
+	var orgIDs []int32
+	seen := make(map[int32]bool)
+	for _, id := range req.OrganizationIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		var exists bool
+		if err := s.keys.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM organizations WHERE id = $1)", id).Scan(&exists); err != nil {
+			errorf("SetDomainRestriction: Failed to look up organization %d: %v", id, err)
+			return nil, status.Errorf(codes.Internal, "failed to look up organization: %v", err)
+		}
+		if !exists {
+			return nil, status.Errorf(codes.NotFound, "organization %d not found", id)
+		}
+		orgIDs = append(orgIDs, id)
+	}
+
+	var restrictedTo interface{} // NULL makes the domain public
+	if len(orgIDs) > 0 {
+		restrictedTo = pq.Array(orgIDs)
+	}
+	result, err := s.shards.ForDomain(domain).DB.ExecContext(ctx, "UPDATE domains SET restricted_to = $2 WHERE domain_name = $1", domain, restrictedTo)
+	if err != nil {
+		errorf("SetDomainRestriction: Failed to update domain %s: %v", domain, err)
+		return nil, status.Errorf(codes.Internal, "failed to update domain: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.NotFound, "domain %s not found", domain)
+	}
+	infof("SetDomainRestriction: API key %s restricted domain %s to organizations %v", apiKey, domain, orgIDs)
+	return &pb.DomainRestriction{Domain: domain, OrganizationIds: orgIDs}, nil
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+)
+
+const (
+	maxOrganizationWatchlistTerms = 10000
+	maxWatchlistImportBytes       = 16 << 20
+	maxRejectedWatchlistEntries   = 100
+	minWatchTermLength            = 3 // Characters other than wildcards; shorter terms match too much
+	maxWatchTermLength            = 253
+)
+
+// stixDomainComparison matches the domain-name:value comparisons of a STIX
+// pattern, e.g. [domain-name:value = 'evil.example'].
+var stixDomainComparison = regexp.MustCompile(`domain-name:value\s*(=|LIKE)\s*'((?:[^'\\]|\\.)*)'`)
+
+// csvWatchlistHeaders are the first-column names of a CSV header row.
+var csvWatchlistHeaders = map[string]bool{"domain": true, "domains": true, "domain_name": true, "indicator": true, "value": true, "pattern": true, "term": true}
+
+// watchEntry is a domain or pattern read from an imported watchlist file.
+type watchEntry struct {
+	location string // CSV "line N" or STIX object ID
+	value    string
+	reject   string // Why the entry cannot be imported, if known when reading
+}
+
+// normalizeWatchTerm lowercases a watchlist term and checks that it is a
+// domain name, a name fragment or a glob of one.
+func normalizeWatchTerm(term string) (string, error) {
+	term = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(term)), ".")
+	if term == "" {
+		return "", fmt.Errorf("empty")
+	}
+	if len(term) > maxWatchTermLength {
+		return "", fmt.Errorf("longer than %d characters", maxWatchTermLength)
+	}
+	literal := 0
+	for _, r := range term {
+		switch {
+		case r == '*' || r == '?':
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			literal++
+		default:
+			return "", fmt.Errorf("invalid character %q; use punycode for internationalized names", r)
+		}
+	}
+	if literal < minWatchTermLength {
+		return "", fmt.Errorf("needs at least %d characters besides wildcards", minWatchTermLength)
+	}
+	return term, nil
+}
+
+// readWatchlistCSV returns the first column of each row of a CSV file,
+// skipping a header row naming the column (see csvWatchlistHeaders).
+func readWatchlistCSV(content string) ([]watchEntry, error) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var entries []watchEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+		if line == 1 && csvWatchlistHeaders[strings.ToLower(strings.TrimSpace(record[0]))] {
+			continue
+		}
+		if strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		entries = append(entries, watchEntry{location: fmt.Sprintf("line %d", line), value: record[0]})
+	}
+	return entries, nil
+}
+
+// readWatchlistSTIX returns the domains of a STIX 2.1 bundle: the values of
+// domain-name objects and the domain-name:value comparisons of indicator
+// patterns, with LIKE wildcards converted to globs. Revoked and expired
+// indicators are skipped; indicators without a usable comparison are
+// returned as rejected.
+func readWatchlistSTIX(content string) ([]watchEntry, error) {
+	var bundle struct {
+		Type    string `json:"type"`
+		Objects []struct {
+			Type        string `json:"type"`
+			ID          string `json:"id"`
+			Value       string `json:"value"`        // domain-name
+			Pattern     string `json:"pattern"`      // indicator
+			PatternType string `json:"pattern_type"` // indicator; stix if empty
+			Revoked     bool   `json:"revoked"`
+			ValidUntil  string `json:"valid_until"`
+		} `json:"objects"`
+	}
+	d := json.NewDecoder(strings.NewReader(content))
+	if err := d.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("invalid STIX bundle: %v", err)
+	}
+	if bundle.Type != "bundle" {
+		return nil, fmt.Errorf("invalid STIX bundle: type is %q, not bundle", bundle.Type)
+	}
+	var entries []watchEntry
+	for _, o := range bundle.Objects {
+		switch o.Type {
+		case "domain-name":
+			entries = append(entries, watchEntry{location: o.ID, value: o.Value})
+		case "indicator":
+			if o.Revoked {
+				continue
+			}
+			if until, err := time.Parse(time.RFC3339, o.ValidUntil); err == nil && until.Before(time.Now()) {
+				continue
+			}
+			if o.PatternType != "" && o.PatternType != "stix" {
+				entries = append(entries, watchEntry{location: o.ID, value: o.Pattern, reject: fmt.Sprintf("pattern type %s is not supported", o.PatternType)})
+				continue
+			}
+			matches := stixDomainComparison.FindAllStringSubmatch(o.Pattern, -1)
+			if len(matches) == 0 {
+				entries = append(entries, watchEntry{location: o.ID, value: o.Pattern, reject: "no domain-name:value comparison with = or LIKE"})
+				continue
+			}
+			for _, m := range matches {
+				value := strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(m[2])
+				if m[1] == "LIKE" {
+					value = strings.NewReplacer("%", "*", "_", "?").Replace(value)
+				}
+				entries = append(entries, watchEntry{location: o.ID, value: value})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ImportWatchlist adds the domains and patterns of a CSV file or STIX 2.1
+// bundle to the watchlist of the caller's organization, or replaces it.
+// Entries are validated and deduplicated; invalid ones are reported rather
+// than failing the import, unless the result would exceed
+// maxOrganizationWatchlistTerms.
+//
+// It requires an org admin API key in the gRPC metadata ("x-api-key").
+func (s *server) ImportWatchlist(ctx context.Context, req *pb.ImportWatchlistRequest) (*pb.ImportWatchlistResponse, error) {
+	apiKey := apiKeyFromContext(ctx)
+	orgID, err := s.orgAdmin(ctx, "ImportWatchlist", apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Content) > maxWatchlistImportBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "content is larger than %d bytes", maxWatchlistImportBytes)
+	}
+	var entries []watchEntry
+	switch strings.ToUpper(req.Format) {
+	case "CSV":
+		entries, err = readWatchlistCSV(req.Content)
+	case "STIX":
+		entries, err = readWatchlistSTIX(req.Content)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "format must be CSV or STIX")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	tx, err := s.keys.BeginTx(ctx, nil)
+	if err != nil {
+		errorf("ImportWatchlist: Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to update watchlist: %v", err)
+	}
+	defer tx.Rollback()
+	watchlist := []string{}
+	if err := tx.QueryRowContext(ctx, "SELECT watchlist FROM organizations WHERE id = $1 FOR UPDATE", orgID).Scan(pq.Array(&watchlist)); err != nil {
+		errorf("ImportWatchlist: Failed to query organization %d: %v", orgID, err)
+		return nil, status.Errorf(codes.Internal, "failed to query watchlist: %v", err)
+	}
+	if req.Replace {
+		watchlist = []string{}
+	}
+	seen := make(map[string]bool)
+	for _, term := range watchlist {
+		seen[term] = true
+	}
+
+	resp := &pb.ImportWatchlistResponse{}
+	for _, e := range entries {
+		term, err := normalizeWatchTerm(e.value)
+		if e.reject == "" && err != nil {
+			e.reject = err.Error()
+		}
+		if e.reject != "" {
+			resp.RejectedCount++
+			if len(resp.Rejected) < maxRejectedWatchlistEntries {
+				resp.Rejected = append(resp.Rejected, &pb.RejectedWatchlistEntry{Location: e.location, Value: e.value, Reason: e.reject})
+			}
+			continue
+		}
+		if seen[term] {
+			resp.Duplicates++
+			continue
+		}
+		seen[term] = true
+		watchlist = append(watchlist, term)
+		resp.Added++
+	}
+	if len(watchlist) > maxOrganizationWatchlistTerms {
+		return nil, statusError(codes.InvalidArgument, reasonTooManyDomains, map[string]string{"quota_limit": fmt.Sprint(maxOrganizationWatchlistTerms)},
+			"the watchlist would have %d terms; at most %d are allowed", len(watchlist), maxOrganizationWatchlistTerms)
+	}
+	if !req.DryRun {
+		if _, err := tx.ExecContext(ctx, "UPDATE organizations SET watchlist = $1 WHERE id = $2", pq.Array(watchlist), orgID); err != nil {
+			errorf("ImportWatchlist: Failed to update organization %d: %v", orgID, err)
+			return nil, status.Errorf(codes.Internal, "failed to update watchlist: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			errorf("ImportWatchlist: Failed to commit watchlist of organization %d: %v", orgID, err)
+			return nil, status.Errorf(codes.Internal, "failed to update watchlist: %v", err)
+		}
+	}
+	if resp.Organization, err = s.loadOrganization(ctx, "ImportWatchlist", orgID, true); err != nil {
+		return nil, err
+	}
+	infof("ImportWatchlist: API key %s imported %d %s entries for organization %d: %d added, %d duplicates, %d rejected (dry run %t)",
+		apiKey, len(entries), strings.ToUpper(req.Format), orgID, resp.Added, resp.Duplicates, resp.RejectedCount, req.DryRun)
+	return resp, nil
+}
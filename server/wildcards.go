@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/moos3/bell/pb/bell/v1"
+	"github.com/moos3/bell/storage"
+)
+
+// isPattern reports whether a requested domain is a pattern to expand.
+func isPattern(domain string) bool {
+	return strings.ContainsAny(domain, "*?")
+}
+
+// expandPatterns replaces the patterns among domains with the visible
+// domains they match, in name order, and reports what each pattern expanded
+// to. A pattern expands to at most maxExpansion domains, and all of them
+// with the literal domains to at most maxBatchDomains, so later patterns
+// get what earlier ones leave. Every pattern's cost is checked before any
+// is expanded: one the planner expects to cost more than wildcards.max_cost
+// to list in full rejects the request.
+func (s *server) expandPatterns(ctx context.Context, method string, prefs *keyPreferences, domains []string, maxExpansion int) ([]string, []*pb.PatternExpansion, error) {
+	var patterns []string
+	room := maxBatchDomains
+	for _, domain := range domains {
+		if !isPattern(domain) {
+			room--
+			continue
+		}
+		pattern := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		if len(pattern) > 253 {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "pattern %q is longer than a domain name", domain)
+		}
+		if longestLiteral(pattern) < minSearchLiteral {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "pattern %q must hold at least %d consecutive letters or digits", domain, minSearchLiteral)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(patterns) == 0 {
+		return domains, nil, nil
+	}
+	for _, pattern := range patterns {
+		cost, err := s.patternCost(ctx, pattern)
+		if err != nil {
+			errorf("%s: Failed to plan expansion of %s: %v", method, pattern, err)
+			return nil, nil, status.Errorf(codes.Internal, "failed to plan pattern expansion: %v", err)
+		}
+		if cost > s.wildcardMaxCost {
+			debugf("%s: Rejecting pattern %s costing %.0f", method, pattern, cost)
+			return nil, nil, statusError(codes.InvalidArgument, reasonPatternTooBroad, map[string]string{
+				"pattern":  pattern,
+				"cost":     strconv.FormatFloat(cost, 'f', 0, 64),
+				"max_cost": strconv.FormatFloat(s.wildcardMaxCost, 'f', 0, 64),
+			}, "pattern %q matches too many domains to expand; add literal characters", pattern)
+		}
+	}
+
+	var expanded []string
+	var expansions []*pb.PatternExpansion
+	next := 0
+	for _, domain := range domains {
+		if !isPattern(domain) {
+			expanded = append(expanded, domain)
+			continue
+		}
+		pattern := patterns[next]
+		next++
+		names, truncated, err := s.matchDomains(ctx, pattern, prefs.orgID, max(min(maxExpansion, room), 0))
+		if err != nil {
+			errorf("%s: Failed to expand %s: %v", method, pattern, err)
+			return nil, nil, status.Errorf(codes.Internal, "failed to expand pattern: %v", err)
+		}
+		room -= len(names)
+		expanded = append(expanded, names...)
+		expansions = append(expansions, &pb.PatternExpansion{Pattern: domain, Domains: names, Truncated: truncated})
+	}
+	return expanded, expansions, nil
+}
+
+// patternShards runs fn on the shard owning the TLD of every name pattern
+// matches, or on every healthy shard if its TLD is a wildcard.
+func (s *server) patternShards(ctx context.Context, pattern string, fn func(ctx context.Context, shard *storage.Shard) error) error {
+	if tld := patternTLD(pattern); tld != "" {
+		return fn(ctx, s.shards.ForTLD(tld))
+	}
+	_, err := s.shards.FanOut(ctx, fn)
+	return err
+}
+
+// patternCost returns the planner's cost of listing every domain pattern
+// matches, summed over the shards asked.
+func (s *server) patternCost(ctx context.Context, pattern string) (float64, error) {
+	var mu sync.Mutex
+	var total float64
+	err := s.patternShards(ctx, pattern, func(ctx context.Context, shard *storage.Shard) error {
+		var plan []byte
+		if err := shard.Reader(ctx).QueryRowContext(ctx, `
+			EXPLAIN (FORMAT JSON) SELECT domain_name FROM domains WHERE domain_name LIKE $1 AND ($2 = '' OR tld = $2)
+		`, globPattern(pattern), patternTLD(pattern)).Scan(&plan); err != nil {
+			return err
+		}
+		var plans []struct {
+			Plan struct {
+				Cost float64 `json:"Total Cost"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal(plan, &plans); err != nil || len(plans) == 0 {
+			return fmt.Errorf("failed to parse query plan: %v", err)
+		}
+		mu.Lock()
+		total += plans[0].Plan.Cost
+		mu.Unlock()
+		return nil
+	})
+	return total, err
+}
+
+// matchDomains returns the first limit domains visible to orgID that
+// pattern matches, in name order, and whether there were more.
+func (s *server) matchDomains(ctx context.Context, pattern string, orgID, limit int) ([]string, bool, error) {
+	var mu sync.Mutex
+	var names []string
+	err := s.patternShards(ctx, pattern, func(ctx context.Context, shard *storage.Shard) error {
+		// One extra row tells whether the shard has more
+		rows, err := shard.Reader(ctx).QueryContext(ctx, `
+			SELECT domain_name
+			FROM domains
+			WHERE domain_name LIKE $1 AND ($2 = '' OR tld = $2)
+				AND (restricted_to IS NULL OR $4 = ANY(restricted_to))
+			ORDER BY domain_name COLLATE "C"
+			LIMIT $3
+		`, globPattern(pattern), patternTLD(pattern), limit+1, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			mu.Lock()
+			names = append(names, name)
+			mu.Unlock()
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	sort.Strings(names)
+	if len(names) > limit {
+		return names[:limit], true, nil
+	}
+	return names, false, nil
+}
@@ -0,0 +1,162 @@
+// Package signing signs the payloads bell serves, so consumers can prove
+// data came from it unmodified: responses of callers that ask for it and
+// export files. Keys are Ed25519, named by the first 16 hex digits of the
+// SHA-256 of their public key, and published through GetSigningKeys.
+//
+// A signature value, as carried by the X-Bell-Signature header, the
+// x-bell-signature gRPC header and ExportJob.signature, reads
+//
+//	keyid=<key id>,t=<unix seconds>,sig=<base64url signature>
+//
+// and signs Message(t, SHA-256 of the payload): "bell-v1.<t>.<hex digest>".
+// The payload is the HTTP response body as sent, the export file, or, for
+// gRPC, the response message in deterministic protobuf encoding. Verify
+// checks a value.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header is the HTTP header carrying a signature value, and RequestHeader
+// the header or gRPC metadata key that asks for one.
+const (
+	Header        = "X-Bell-Signature"
+	RequestHeader = "X-Bell-Sign"
+)
+
+// Key is a published signing key.
+type Key struct {
+	ID        string
+	PublicKey ed25519.PublicKey
+	Active    bool // Signs new payloads; the others are kept so earlier signatures still verify
+}
+
+// Signer signs with the first of its keys.
+type Signer struct {
+	private ed25519.PrivateKey
+	keys    []Key
+}
+
+// Load reads PEM files of Ed25519 keys (e.g. from openssl genpkey
+// -algorithm ed25519): the first a PKCS #8 private key, which signs, and
+// the others retired keys, private or PKIX public, published until their
+// signatures no longer matter.
+func Load(files []string) (*Signer, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+	s := &Signer{}
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %v", err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM block in signing key %s", file)
+		}
+		var public ed25519.PublicKey
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse signing key %s: %v", file, err)
+			}
+			private, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("signing key %s is not an Ed25519 key", file)
+			}
+			if i == 0 {
+				s.private = private
+			}
+			public = private.Public().(ed25519.PublicKey)
+		case "PUBLIC KEY":
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse signing key %s: %v", file, err)
+			}
+			var ok bool
+			if public, ok = parsed.(ed25519.PublicKey); !ok {
+				return nil, fmt.Errorf("signing key %s is not an Ed25519 key", file)
+			}
+		default:
+			return nil, fmt.Errorf("signing key %s holds a %s, not a PRIVATE KEY or PUBLIC KEY", file, block.Type)
+		}
+		if s.private == nil {
+			return nil, fmt.Errorf("the first signing key, %s, must be a private key", file)
+		}
+		s.keys = append(s.keys, Key{ID: KeyID(public), PublicKey: public, Active: i == 0})
+	}
+	return s, nil
+}
+
+// KeyID names a public key.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Keys returns the published keys, the active one first.
+func (s *Signer) Keys() []Key {
+	return s.keys
+}
+
+// Sign returns the signature value of a payload with SHA-256 digest,
+// signed at t.
+func (s *Signer) Sign(digest []byte, t time.Time) string {
+	sig := ed25519.Sign(s.private, Message(t.Unix(), digest))
+	return fmt.Sprintf("keyid=%s,t=%d,sig=%s", s.keys[0].ID, t.Unix(), base64.RawURLEncoding.EncodeToString(sig))
+}
+
+// SignPayload signs payload at t.
+func (s *Signer) SignPayload(payload []byte, t time.Time) string {
+	sum := sha256.Sum256(payload)
+	return s.Sign(sum[:], t)
+}
+
+// Message returns what a signature made at unix time t over a payload with
+// SHA-256 digest signs.
+func Message(t int64, digest []byte) []byte {
+	return []byte(fmt.Sprintf("bell-v1.%d.%s", t, hex.EncodeToString(digest)))
+}
+
+// Verify checks a signature value against the SHA-256 digest of a payload,
+// with the public key key returns for its key ID, and returns when it was
+// signed.
+func Verify(value string, digest []byte, key func(id string) ed25519.PublicKey) (time.Time, error) {
+	fields := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		name, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return time.Time{}, fmt.Errorf("malformed signature %q", value)
+		}
+		fields[name] = v
+	}
+	t, err := strconv.ParseInt(fields["t"], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed signature time %q", fields["t"])
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(fields["sig"])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed signature: %v", err)
+	}
+	public := key(fields["keyid"])
+	if public == nil {
+		return time.Time{}, fmt.Errorf("unknown signing key %q", fields["keyid"])
+	}
+	if !ed25519.Verify(public, Message(t, digest), sig) {
+		return time.Time{}, fmt.Errorf("signature does not match the payload")
+	}
+	return time.Unix(t, 0).UTC(), nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"os"
+	"testing"
+)
+
+// compressDeflateV1 writes data with codecDeflateDictV1, as rows stored
+// before zstd were.
+func compressDeflateV1(t *testing.T, data string) []byte {
+	var b bytes.Buffer
+	b.WriteByte(codecDeflateDictV1)
+	w, err := flate.NewWriterDict(&b, flate.BestCompression, recordDictV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(data))
+	w.Close()
+	return b.Bytes()
+}
+
+func readSamples(t *testing.T) []string {
+	f, err := os.Open("testdata/txt_samples.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var samples []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		samples = append(samples, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestRecordDataRoundTrip(t *testing.T) {
+	var plain, deflated, zstd int
+	for _, data := range readSamples(t) {
+		z := CompressRecordData(data)
+		if z[0] != RecordDataCodec {
+			t.Fatalf("codec byte %d, want %d", z[0], RecordDataCodec)
+		}
+		got, err := RecordData("", z)
+		if err != nil || got != data {
+			t.Fatalf("RecordData(CompressRecordData(%q)) = %q, %v", data, got, err)
+		}
+		old := compressDeflateV1(t, data)
+		if got, err := RecordData("", old); err != nil || got != data {
+			t.Fatalf("RecordData of DEFLATE row %q = %q, %v", data, got, err)
+		}
+		plain += len(data)
+		deflated += len(old)
+		zstd += len(z)
+	}
+	t.Logf("%d bytes: DEFLATE %d, zstd %d", plain, deflated, zstd)
+	if zstd >= deflated {
+		t.Errorf("zstd with the trained dictionary stored %d bytes, DEFLATE %d", zstd, deflated)
+	}
+}
+
+func TestRecordDataUnknownCodec(t *testing.T) {
+	if _, err := RecordData("", []byte{0xff, 1, 2}); err == nil {
+		t.Error("RecordData accepted an unknown codec")
+	}
+}
+
+func TestEncodeRecordData(t *testing.T) {
+	long := `example.com.	3600	IN	TXT	"v=spf1 include:_spf.google.com include:sendgrid.net include:mailgun.org ~all"`
+	if data, z := EncodeRecordData("TXT", long, 0); data != long || z != nil {
+		t.Error("compressed with compression disabled")
+	}
+	if data, z := EncodeRecordData("A", long, 10); data != long || z != nil {
+		t.Error("compressed a record other than TXT")
+	}
+	data, z := EncodeRecordData("TXT", long, 10)
+	if data != "" || z == nil {
+		t.Fatal("did not compress a long TXT record")
+	}
+	if got, err := RecordData(data, z); err != nil || got != long {
+		t.Errorf("RecordData = %q, %v", got, err)
+	}
+}
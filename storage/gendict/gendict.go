@@ -0,0 +1,133 @@
+// Package gendict trains the zstd dictionary the storage package compresses
+// TXT record data with, on sample record data: a file of record_data
+// values, one per line, or TXT records sampled from every shard of a
+// deployment. A dictionary trained again gets a new ID and needs a new
+// codec in storage, so that rows written with the old one still decode.
+package gendict
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	_ "github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/storage"
+)
+
+func main() {
+	samplesFile := flag.String("samples", "storage/testdata/txt_samples.txt", "Record data to train on, one dns_records.record_data value per line")
+	configFile := flag.String("config", "", "Sample the TXT records of this deployment's shards instead of reading -samples")
+	perShard := flag.Int("per-shard", 20000, "TXT records sampled per shard with -config")
+	id := flag.Uint("id", 0, "Dictionary ID written to every frame; at least 32768")
+	size := flag.Int("size", 16<<10, "Maximum dictionary size in bytes")
+	out := flag.String("out", "", "Dictionary file to write")
+	flag.Parse()
+	if *id < 32768 || *id >= 1<<31 || *out == "" {
+		log.Fatal("-id (32768 to 2^31-1) and -out are required")
+	}
+
+	var samples [][]byte
+	var err error
+	if *configFile != "" {
+		samples, err = sampleShards(*configFile, *perShard)
+	} else {
+		samples, err = readSamples(*samplesFile)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	trained, err := dict.BuildZstdDict(samples, dict.Options{MaxDictSize: *size, HashBytes: 8, ZstdDictID: uint32(*id)})
+	if err != nil {
+		log.Fatalf("Failed to train dictionary: %v", err)
+	}
+	if err := os.WriteFile(*out, trained, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	// How well it does on what it was trained on
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderDict(trained), zstd.WithEncoderLevel(zstd.SpeedBetterCompression), zstd.WithEncoderCRC(false))
+	if err != nil {
+		log.Fatalf("Trained dictionary is unusable: %v", err)
+	}
+	var plain, compressed int
+	for _, s := range samples {
+		plain += len(s)
+		compressed += len(enc.EncodeAll(s, nil))
+	}
+	fmt.Printf("Wrote %s (%d bytes, ID %d) from %d samples: %d bytes compress to %d (%.1f%%)\n",
+		*out, len(trained), *id, len(samples), plain, compressed, 100*float64(compressed)/float64(plain))
+}
+
+// readSamples reads one sample per line of file.
+func readSamples(file string) ([][]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var samples [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			samples = append(samples, append([]byte(nil), scanner.Bytes()...))
+		}
+	}
+	return samples, scanner.Err()
+}
+
+// sampleShards reads up to perShard random TXT records from every shard of
+// the deployment configured in configFile, decompressed.
+func sampleShards(configFile string, perShard int) ([][]byte, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("postgres", storage.ConnString(cfg.AlloyDB.Host, cfg.AlloyDB.Port, cfg.AlloyDB.User, cfg.AlloyDB.Password, cfg.AlloyDB.Database, cfg.AlloyDB.SSLMode))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	shards, err := storage.NewRouter(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	defer shards.Close()
+	var samples [][]byte
+	for _, shard := range shards.Shards() {
+		rows, err := shard.DB.Query(`
+			SELECT record_data, record_data_z FROM dns_records
+			WHERE record_type = 'TXT'
+			ORDER BY random()
+			LIMIT $1
+		`, perShard)
+		if err != nil {
+			return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+		for rows.Next() {
+			var data string
+			var z []byte
+			if err := rows.Scan(&data, &z); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+			}
+			if data, err = storage.RecordData(data, z); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+			}
+			samples = append(samples, []byte(data))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("shard %s: %v", shard.Name, err)
+		}
+	}
+	return samples, nil
+}
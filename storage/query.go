@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SQL is a fragment of statement text. Untyped string constants convert to
+// SQL implicitly but string variables do not, so request values cannot reach
+// statement text without an explicit conversion that stands out in review;
+// they are passed as bind arguments instead.
+type SQL string
+
+// Query builds a parameterized statement. Fragments mark bind arguments with
+// ?, which are numbered $1, $2, ... in the order they are added, so filters
+// can be added conditionally without tracking placeholder positions.
+//
+//	q := storage.NewQuery("SELECT id FROM domains").Where("tld = ?", tld)
+//	if len(names) > 0 {
+//		q.WhereIn("domain_name", names)
+//	}
+//	rows, err := db.QueryContext(ctx, q.SQL(), q.Args()...)
+type Query struct {
+	text  strings.Builder
+	args  []interface{}
+	where bool
+}
+
+// NewQuery starts a query with fragment, whose ? placeholders bind args.
+func NewQuery(fragment SQL, args ...interface{}) *Query {
+	return new(Query).Append(fragment, args...)
+}
+
+// Append adds fragment, preceded by a space, binding its ? placeholders to
+// args. A ? inside a quoted literal or identifier is left as is. Append
+// panics if the number of placeholders and args differ, since that is a
+// programming error.
+func (q *Query) Append(fragment SQL, args ...interface{}) *Query {
+	if q.text.Len() > 0 {
+		q.text.WriteByte(' ')
+	}
+	bound := 0
+	var quote byte
+	for i := 0; i < len(fragment); i++ {
+		c := fragment[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			if bound == len(args) {
+				panic("storage: more placeholders than arguments in " + strconv.Quote(string(fragment)))
+			}
+			q.args = append(q.args, args[bound])
+			bound++
+			q.text.WriteString("$" + strconv.Itoa(len(q.args)))
+			continue
+		}
+		q.text.WriteByte(c)
+	}
+	if bound != len(args) {
+		panic("storage: more arguments than placeholders in " + strconv.Quote(string(fragment)))
+	}
+	return q
+}
+
+// Where adds a condition, joined to earlier ones with AND. The first
+// condition starts the WHERE clause, so the base query must not have one.
+func (q *Query) Where(cond SQL, args ...interface{}) *Query {
+	if q.where {
+		return q.Append("AND ("+cond+")", args...)
+	}
+	q.where = true
+	return q.Append("WHERE ("+cond+")", args...)
+}
+
+// WhereIn adds the condition that column is one of values, a slice of a type
+// supported by pq.Array. The values are bound as a single array argument, so
+// the statement text does not depend on their number.
+func (q *Query) WhereIn(column SQL, values interface{}) *Query {
+	return q.Where(column+" = ANY(?)", pq.Array(values))
+}
+
+// WhereTLD adds the condition that the domains table aliased as table is
+// under tld. A TLD with a dot, such as co.uk, is matched against the
+// public_suffix column, since domains.tld only holds the last label.
+func (q *Query) WhereTLD(table SQL, tld string) *Query {
+	if strings.Contains(tld, ".") {
+		return q.Where(table+".public_suffix = ?", tld)
+	}
+	return q.Where(table+".tld = ?", tld)
+}
+
+// WhereVisible adds the condition that the domains table aliased as table
+// is visible to the keys of organization orgID (0 for keys outside any):
+// public, or restricted to organizations including it.
+func (q *Query) WhereVisible(table SQL, orgID int) *Query {
+	return q.Where(table+".restricted_to IS NULL OR ? = ANY("+table+".restricted_to)", orgID)
+}
+
+// WhereRecordVisible is WhereVisible for queries over dns_records aliased
+// as table that do not join domains: the records of domains restricted to
+// other organizations are left out through idx_domains_restricted.
+func (q *Query) WhereRecordVisible(table SQL, orgID int) *Query {
+	return q.Where(`NOT EXISTS (
+		SELECT 1 FROM domains restricted
+		WHERE restricted.id = `+table+`.domain_id AND restricted.restricted_to IS NOT NULL AND NOT (? = ANY(restricted.restricted_to))
+	)`, orgID)
+}
+
+// SQL returns the statement text.
+func (q *Query) SQL() string {
+	return q.text.String()
+}
+
+// Args returns the bind arguments in placeholder order.
+func (q *Query) Args() []interface{} {
+	return q.args
+}
@@ -0,0 +1,279 @@
+// Package storage routes DNS data (domains, dns_records) to AlloyDB shards
+// by TLD.
+//
+// The alloydb config block is the default shard: it holds all metadata tables
+// (api_keys, processed_tlds, analytics) and the DNS data of every TLD not
+// mapped to another shard. Additional shards are listed under shards, each
+// owning a set of TLDs.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/moos3/bell/config"
+	"github.com/moos3/bell/tracing"
+)
+
+// DefaultShard is the name of the shard configured by the alloydb block.
+const DefaultShard = "default"
+
+// Shard is a single database instance holding the DNS data of some TLDs.
+type Shard struct {
+	Name     string
+	DB       *sql.DB
+	Replicas []*sql.DB // Read replicas of DB, used only by hedged reads (see Reader)
+
+	mu        sync.RWMutex
+	healthy   bool
+	lastError error
+	checkedAt time.Time
+}
+
+// Healthy reports whether the shard passed its last health check.
+func (s *Shard) Healthy() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.healthy
+}
+
+// LastError returns the error from the shard's last health check, if any.
+func (s *Shard) LastError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastError
+}
+
+func (s *Shard) setHealth(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.healthy && err != nil {
+		log.Printf("Shard %s is unhealthy: %v", s.Name, err)
+	} else if !s.healthy && err == nil && !s.checkedAt.IsZero() {
+		log.Printf("Shard %s recovered", s.Name)
+	}
+	s.healthy = err == nil
+	s.lastError = err
+	s.checkedAt = time.Now()
+}
+
+// readAttemptKey is the context key of a hedged read's readAttempt.
+type readAttemptKey struct{}
+
+// readAttempt identifies one attempt of a hedged read. Attempts of the same
+// read share start, a per-read offset into the read pool that spreads reads
+// across replicas.
+type readAttempt struct {
+	start, n int
+}
+
+// WithReadAttempt marks ctx as attempt n (0 for the first) of a hedged read.
+// Reads that call Reader with the returned context go to a different member
+// of the shard's read pool for each attempt.
+func WithReadAttempt(ctx context.Context, start, n int) context.Context {
+	return context.WithValue(ctx, readAttemptKey{}, readAttempt{start: start, n: n})
+}
+
+// Reader returns the database a read-only query in ctx should use. Outside a
+// hedged read, or on a shard without replicas, that is the primary;
+// otherwise each attempt gets its own member of the read pool, the primary
+// followed by the replicas.
+func (s *Shard) Reader(ctx context.Context) *sql.DB {
+	attempt, ok := ctx.Value(readAttemptKey{}).(readAttempt)
+	if !ok || len(s.Replicas) == 0 {
+		return s.DB
+	}
+	i := (attempt.start + attempt.n) % (len(s.Replicas) + 1)
+	if i < 0 {
+		i = -i
+	}
+	if i == 0 {
+		return s.DB
+	}
+	return s.Replicas[i-1]
+}
+
+// openReplicas opens the read replicas at addrs ("host" or "host:port") with
+// the primary's credentials. Connections are made lazily so that a replica
+// outage only fails the hedged attempts sent to it.
+func openReplicas(shard *Shard, addrs []string, port, user, password, database, sslMode string) error {
+	for _, addr := range addrs {
+		host, replicaPort := addr, port
+		if h, p, err := net.SplitHostPort(addr); err == nil {
+			host, replicaPort = h, p
+		}
+		replica, err := sql.Open(tracing.DriverName, ConnString(host, replicaPort, user, password, database, sslMode))
+		if err != nil {
+			return fmt.Errorf("failed to open replica %s of shard %s: %v", addr, shard.Name, err)
+		}
+		shard.Replicas = append(shard.Replicas, replica)
+	}
+	return nil
+}
+
+// Router maps TLDs to shards.
+type Router struct {
+	shards []*Shard          // Default shard first, then in config order
+	byTLD  map[string]*Shard // TLD -> owning shard; unmapped TLDs use the default
+}
+
+// ConnString builds a lib/pq connection string.
+func ConnString(host, port, user, password, database, sslMode string) string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		host, port, user, password, database, sslMode,
+	)
+}
+
+// NewRouter builds a router around the already-open default database and
+// opens every shard listed in cfg.Sharding.Shards. All shards must be reachable.
+// Read replicas of the default and other shards are opened but not checked.
+func NewRouter(cfg *config.Config, db *sql.DB) (*Router, error) {
+	r := &Router{byTLD: make(map[string]*Shard)}
+	r.shards = append(r.shards, &Shard{Name: DefaultShard, DB: db, healthy: true})
+	a := cfg.AlloyDB
+	if err := openReplicas(r.shards[0], a.Replicas, a.Port, a.User, a.Password, a.Database, a.SSLMode); err != nil {
+		r.Close()
+		return nil, err
+	}
+	for _, sc := range cfg.Sharding.Shards {
+		shardDB, err := sql.Open(tracing.DriverName, ConnString(sc.Host, sc.Port, sc.User, sc.Password, sc.Database, sc.SSLMode))
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to open shard %s: %v", sc.Name, err)
+		}
+		if err := shardDB.Ping(); err != nil {
+			shardDB.Close()
+			r.Close()
+			return nil, fmt.Errorf("failed to connect to shard %s: %v", sc.Name, err)
+		}
+		shard := &Shard{Name: sc.Name, DB: shardDB, healthy: true}
+		r.shards = append(r.shards, shard)
+		if err := openReplicas(shard, sc.Replicas, sc.Port, sc.User, sc.Password, sc.Database, sc.SSLMode); err != nil {
+			r.Close()
+			return nil, err
+		}
+		for _, tld := range sc.TLDs {
+			r.byTLD[strings.ToLower(strings.Trim(tld, "."))] = shard
+		}
+	}
+	return r, nil
+}
+
+// NewSingleRouter builds a router that sends every TLD to db, such as a
+// small unsharded dataset.
+func NewSingleRouter(db *sql.DB) *Router {
+	return &Router{shards: []*Shard{{Name: DefaultShard, DB: db, healthy: true}}, byTLD: make(map[string]*Shard)}
+}
+
+// Default returns the default shard's database, which also holds metadata tables.
+func (r *Router) Default() *sql.DB {
+	return r.shards[0].DB
+}
+
+// ForTLD returns the shard owning tld.
+func (r *Router) ForTLD(tld string) *Shard {
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	if shard, ok := r.byTLD[tld]; ok {
+		return shard
+	}
+	// A public suffix such as co.uk lives with its TLD
+	if shard, ok := r.byTLD[tld[strings.LastIndex(tld, ".")+1:]]; ok {
+		return shard
+	}
+	return r.shards[0]
+}
+
+// ForDomain returns the shard owning the TLD of domain.
+func (r *Router) ForDomain(domain string) *Shard {
+	domain = strings.TrimSuffix(domain, ".")
+	return r.ForTLD(domain[strings.LastIndex(domain, ".")+1:])
+}
+
+// HasReplicas reports whether any shard has read replicas.
+func (r *Router) HasReplicas() bool {
+	for _, shard := range r.shards {
+		if len(shard.Replicas) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Shards returns all shards, default first.
+func (r *Router) Shards() []*Shard {
+	return r.shards
+}
+
+// FanOut runs fn concurrently against every healthy shard, for queries that
+// span TLDs. It returns the first error; unhealthy shards are skipped and
+// reported in skipped so callers can flag partial results.
+func (r *Router) FanOut(ctx context.Context, fn func(ctx context.Context, shard *Shard) error) (skipped []string, err error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, shard := range r.shards {
+		if !shard.Healthy() {
+			skipped = append(skipped, shard.Name)
+			continue
+		}
+		wg.Add(1)
+		go func(shard *Shard) {
+			defer wg.Done()
+			if shardErr := fn(ctx, shard); shardErr != nil {
+				mu.Lock()
+				if err == nil {
+					err = fmt.Errorf("shard %s: %v", shard.Name, shardErr)
+				}
+				mu.Unlock()
+			}
+		}(shard)
+	}
+	wg.Wait()
+	return skipped, err
+}
+
+// CheckHealth pings every shard once.
+func (r *Router) CheckHealth(ctx context.Context) {
+	for _, shard := range r.shards {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		shard.setHealth(shard.DB.PingContext(pingCtx))
+		cancel()
+	}
+}
+
+// StartHealthChecks pings every shard on interval until ctx is cancelled.
+func (r *Router) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// Close closes every shard and replica opened by the router. The default
+// database is owned by the caller and left open.
+func (r *Router) Close() {
+	for i, shard := range r.shards {
+		for _, replica := range shard.Replicas {
+			replica.Close()
+		}
+		if i > 0 {
+			shard.DB.Close()
+		}
+	}
+}
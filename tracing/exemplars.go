@@ -0,0 +1,218 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the latency
+// histograms.
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// latencyInstrument is a duration instrument of the gRPC and SQL
+// instrumentation, served as a histogram.
+type latencyInstrument struct {
+	family string          // Name of the histogram
+	help   string          // Its # HELP text
+	scale  float64         // Converts recorded values to seconds
+	labels []attribute.Key // Attributes of the measurements kept as labels
+}
+
+// latencyInstruments are the instruments served, by the name otelgrpc and
+// otelsql create them with. Both record milliseconds.
+var latencyInstruments = map[string]latencyInstrument{
+	"rpc.server.duration": {
+		family: "bell_rpc_server_duration_seconds",
+		help:   "Duration of the gRPC calls the server handled.",
+		scale:  1e-3,
+		labels: []attribute.Key{"rpc.service", "rpc.method", "rpc.grpc.status_code"},
+	},
+	"db.sql.latency": {
+		family: "bell_db_call_duration_seconds",
+		help:   "Duration of database calls, by database/sql method.",
+		scale:  1e-3,
+		labels: []attribute.Key{"method", "status"},
+	},
+}
+
+// exemplar is the last observation of a bucket made in a sampled trace.
+type exemplar struct {
+	traceID string
+	value   float64 // Seconds
+	at      time.Time
+}
+
+// latencySeries is one histogram series: an instrument's observations with
+// the same labels.
+type latencySeries struct {
+	labels    []string    // Values of the instrument's labels, in order
+	buckets   []int64     // Observations no longer than each of latencyBuckets
+	exemplars []*exemplar // Per bucket, then +Inf; nil until a sampled trace lands in it
+	count     int64
+	sum       float64
+}
+
+// latencyHistograms holds the observations of every latencyInstrument.
+type latencyHistograms struct {
+	mu     sync.Mutex
+	series map[string]map[string]*latencySeries // Family -> label values joined by NUL -> series
+}
+
+// latency receives the durations the instrumentation records once Setup has
+// installed exemplarMeterProvider.
+var latency = &latencyHistograms{series: make(map[string]map[string]*latencySeries)}
+
+// observe adds an observation of seconds to the series of inst with attrs,
+// with the trace of ctx as the exemplar of its bucket if it is sampled.
+func (h *latencyHistograms) observe(ctx context.Context, inst latencyInstrument, seconds float64, attrs attribute.Set) {
+	labels := make([]string, len(inst.labels))
+	for i, key := range inst.labels {
+		if v, ok := attrs.Value(key); ok {
+			labels[i] = v.Emit()
+		}
+	}
+	key := strings.Join(labels, "\x00")
+	bucket := sort.SearchFloat64s(latencyBuckets, seconds) // len(latencyBuckets) for +Inf
+	sc := trace.SpanContextFromContext(ctx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	family, ok := h.series[inst.family]
+	if !ok {
+		family = make(map[string]*latencySeries)
+		h.series[inst.family] = family
+	}
+	s, ok := family[key]
+	if !ok {
+		s = &latencySeries{
+			labels:    labels,
+			buckets:   make([]int64, len(latencyBuckets)),
+			exemplars: make([]*exemplar, len(latencyBuckets)+1),
+		}
+		family[key] = s
+	}
+	for i := bucket; i < len(latencyBuckets); i++ {
+		s.buckets[i]++
+	}
+	s.count++
+	s.sum += seconds
+	// Only sampled traces reach the collector, so only they are worth linking
+	if sc.IsSampled() {
+		s.exemplars[bucket] = &exemplar{traceID: sc.TraceID().String(), value: seconds, at: time.Now()}
+	}
+}
+
+// exemplarMeterProvider is the global meter provider once Setup has run. It
+// feeds the latencyInstruments to latency and discards every other
+// instrument.
+type exemplarMeterProvider struct {
+	noop.MeterProvider
+}
+
+func (exemplarMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return exemplarMeter{}
+}
+
+type exemplarMeter struct {
+	noop.Meter
+}
+
+func (exemplarMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	inst, ok := latencyInstruments[name]
+	if !ok {
+		return noop.Float64Histogram{}, nil
+	}
+	return latencyRecorder{inst: inst}, nil
+}
+
+// latencyRecorder records the measurements of a latencyInstrument.
+type latencyRecorder struct {
+	noop.Float64Histogram
+	inst latencyInstrument
+}
+
+func (r latencyRecorder) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	latency.observe(ctx, r.inst, value*r.inst.scale, metric.NewRecordConfig(opts).Attributes())
+}
+
+// MetricsHandler serves the gRPC and database latency histograms in the
+// OpenMetrics text format. Each bucket carries as its exemplar the trace ID
+// of the last sampled request that landed in it, so a latency spike leads
+// to a trace of it:
+//
+//	bell_rpc_server_duration_seconds{rpc_service,rpc_method,rpc_grpc_status_code}
+//	bell_db_call_duration_seconds{method,status}
+//
+// The histograms are empty unless Setup has enabled tracing.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		latency.write(w)
+	})
+}
+
+// labelEscaper escapes OpenMetrics label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// write writes the histograms to w in the OpenMetrics text format.
+func (h *latencyHistograms) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b := bufio.NewWriter(w)
+	names := make([]string, 0, len(latencyInstruments))
+	for name := range latencyInstruments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		inst := latencyInstruments[name]
+		fmt.Fprintf(b, "# HELP %s %s\n", inst.family, inst.help)
+		fmt.Fprintf(b, "# TYPE %s histogram\n", inst.family)
+		fmt.Fprintf(b, "# UNIT %s seconds\n", inst.family)
+		family := h.series[inst.family]
+		keys := make([]string, 0, len(family))
+		for key := range family {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := family[key]
+			pairs := make([]string, len(inst.labels))
+			for i, label := range inst.labels {
+				pairs[i] = fmt.Sprintf(`%s="%s"`, strings.ReplaceAll(string(label), ".", "_"), labelEscaper.Replace(s.labels[i]))
+			}
+			labels := strings.Join(pairs, ",")
+			for i, bound := range latencyBuckets {
+				fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d%s\n", inst.family, labels, strconv.FormatFloat(bound, 'g', -1, 64), s.buckets[i], s.exemplars[i])
+			}
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d%s\n", inst.family, labels, s.count, s.exemplars[len(latencyBuckets)])
+			fmt.Fprintf(b, "%s_count{%s} %d\n", inst.family, labels, s.count)
+			fmt.Fprintf(b, "%s_sum{%s} %s\n", inst.family, labels, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		}
+	}
+	fmt.Fprintln(b, "# EOF")
+	return b.Flush()
+}
+
+// String renders the exemplar as it follows a bucket's value, or nothing
+// for a nil exemplar.
+func (e *exemplar) String() string {
+	if e == nil {
+		return ""
+	}
+	return fmt.Sprintf(` # {trace_id="%s"} %s %s`, e.traceID, strconv.FormatFloat(e.value, 'g', -1, 64),
+		strconv.FormatFloat(float64(e.at.UnixMilli())/1e3, 'f', 3, 64))
+}
@@ -0,0 +1,90 @@
+// Package tracing exports OpenTelemetry traces of bell's server over
+// OTLP/HTTP.
+//
+// Setup installs the global tracer provider and W3C trace context
+// propagation. Instrumented code takes its tracer from the global provider
+// and so records nothing until Setup has run with tracing.endpoint set:
+// the server's gRPC and HTTP handlers, SQL statements on connections opened
+// with DriverName, and the resolver's outbound DNS exchanges. Spans for SQL
+// are only recorded inside a traced request, so background loops such as
+// usage flushes do not start traces of their own.
+//
+// Setup also installs a meter provider keeping the durations of gRPC calls
+// and SQL statements as latency histograms, whose buckets carry trace IDs
+// as exemplars; MetricsHandler serves them.
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/moos3/bell/config"
+)
+
+// DriverName is the database/sql driver to open PostgreSQL with: "postgres",
+// or once Setup has enabled tracing, a wrapper around it recording a span
+// per statement.
+var DriverName = "postgres"
+
+// enabled is set once Setup has installed the exporter.
+var enabled bool
+
+// Enabled reports whether Setup has enabled tracing.
+func Enabled() bool {
+	return enabled
+}
+
+// Setup exports the traces of service to tracing.endpoint, sampling
+// tracing.sample_rate of the traces it starts; requests arriving with a
+// trace context follow their caller's sampling decision. It must run before
+// the process opens its databases. The returned function flushes and stops
+// the exporter. If tracing.endpoint is empty, Setup does nothing.
+func Setup(ctx context.Context, cfg *config.Config, service string) (func(context.Context) error, error) {
+	if cfg.Tracing.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Tracing.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRate))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetMeterProvider(exemplarMeterProvider{})
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Printf("Tracing: %v", err)
+	}))
+
+	DriverName, err = otelsql.Register("postgres",
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnectorConnect: true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register traced database driver: %v", err)
+	}
+	enabled = true
+	log.Printf("Exporting traces of %s to %s, sampling %v of new traces", service, cfg.Tracing.Endpoint, cfg.Tracing.SampleRate)
+	return provider.Shutdown, nil
+}